@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+
+	"nof0-api/pkg/backtest"
+	"nof0-api/pkg/confkit"
+	llmpkg "nof0-api/pkg/llm"
+	managerpkg "nof0-api/pkg/manager"
+)
+
+// backtest replays historical price ticks through a trader's executor (and
+// its LLM) and fills the decisions on the in-memory simulator. With --cycles
+// it instead replays recorded cycles through the executor as a challenger and
+// reports how its decisions and simulated PnL differ from the recorded ones.
+func main() {
+	var (
+		ticksCSV    = flag.String("ticks-csv", "", "CSV of price ticks with symbol, price and ts_ms columns")
+		dsn         = flag.String("dsn", "", "Postgres DSN to load price_ticks from when --ticks-csv is empty")
+		provider    = flag.String("provider", "", "price_ticks provider to select (empty selects all)")
+		symbolsRaw  = flag.String("symbols", "BTC,ETH", "comma-separated symbols to replay")
+		fromRaw     = flag.String("from", "", "replay start, RFC3339 (DB source only; empty means earliest)")
+		toRaw       = flag.String("to", "", "replay end, RFC3339 (DB source only; empty means latest)")
+		managerPath = flag.String("manager-config", "etc/manager.yaml", "path to manager configuration")
+		llmPath     = flag.String("llm-config", "etc/llm.yaml", "path to llm client configuration")
+		traderID    = flag.String("trader", "", "trader whose executor settings to replay (default: first trader)")
+		equity      = flag.Float64("equity", 10000, "initial simulated equity in USD")
+		feeBps      = flag.Float64("fee-bps", 4.5, "fee per fill in basis points of notional")
+		every       = flag.Int("decision-every", 1, "consult the executor every N ticks")
+		outPath     = flag.String("out", "", "write the JSON report to this path")
+		cyclesPath  = flag.String("cycles", "", "JSON file of recorded cycles to diff against the trader's executor instead of replaying ticks")
+	)
+	flag.Parse()
+	logx.MustSetup(logx.LogConf{})
+	logx.DisableStat()
+	confkit.LoadDotenvOnce()
+
+	var (
+		ticks  []backtest.PriceTick
+		cycles []backtest.HistoricalCycle
+		err    error
+	)
+	if *cyclesPath != "" {
+		cycles, err = loadCycles(*cyclesPath)
+		if err != nil {
+			fatalf("load cycles: %v", err)
+		}
+		if len(cycles) == 0 {
+			fatalf("no cycles to replay")
+		}
+	} else {
+		ticks, err = loadTicks(*ticksCSV, *dsn, *provider, parseSymbols(*symbolsRaw), *fromRaw, *toRaw)
+		if err != nil {
+			fatalf("load ticks: %v", err)
+		}
+		if len(ticks) == 0 {
+			fatalf("no price ticks to replay")
+		}
+	}
+
+	managerCfg, err := managerpkg.LoadConfig(*managerPath)
+	if err != nil {
+		fatalf("load manager config: %v", err)
+	}
+	traderCfg, err := pickTrader(managerCfg, *traderID)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	llmCfg, err := llmpkg.LoadConfig(*llmPath)
+	if err != nil {
+		fatalf("load llm config: %v", err)
+	}
+	llmClient, err := llmpkg.NewClient(llmCfg)
+	if err != nil {
+		fatalf("initialise llm client: %v", err)
+	}
+	defer func() {
+		_ = llmClient.Close()
+	}()
+	exec, err := managerpkg.NewBasicExecutorFactory(llmClient, nil).NewExecutor(traderCfg)
+	if err != nil {
+		fatalf("build executor for trader %s: %v", traderCfg.ID, err)
+	}
+
+	if cycles != nil {
+		diff := &backtest.ReplayDiff{
+			Challenger:         exec,
+			Cycles:             cycles,
+			InitialEquity:      *equity,
+			FeeBps:             *feeBps,
+			MaxPositionSizeUSD: traderCfg.RiskParams.MaxPositionSizeUSD,
+			OutputPath:         *outPath,
+		}
+		report, err := diff.Run(context.Background())
+		if err != nil {
+			fatalf("replay diff: %v", err)
+		}
+		for _, c := range report.Cycles {
+			if c.Error != "" {
+				fmt.Printf("cycle=%d time=%s error=%s\n", c.Index, c.Time.Format(time.RFC3339), c.Error)
+				continue
+			}
+			for _, d := range c.Diffs {
+				fmt.Printf("cycle=%d time=%s symbol=%s baseline=%s challenger=%s same=%t size_delta=%.2f\n",
+					c.Index, c.Time.Format(time.RFC3339), d.Symbol, d.BaselineAction, d.ChallengerAction, d.SameAction, d.SizeDeltaUSD)
+			}
+		}
+		fmt.Printf("trader=%s cycles=%d matching=%d diverging=%d errors=%d\n",
+			traderCfg.ID, len(report.Cycles), report.Matching, report.Diverging, report.Errors)
+		fmt.Printf("baseline_pnl=%.2f challenger_pnl=%.2f delta=%.2f usd\n", report.BaselinePNL, report.ChallengerPNL, report.PNLDelta)
+		return
+	}
+
+	engine := &backtest.ExecutorEngine{
+		Executor:           exec,
+		Feeder:             backtest.NewTickFeeder(ticks),
+		InitialEquity:      *equity,
+		FeeBps:             *feeBps,
+		MajorCoinLeverage:  traderCfg.RiskParams.MajorCoinLeverage,
+		AltcoinLeverage:    traderCfg.RiskParams.AltcoinLeverage,
+		MaxPositionSizeUSD: traderCfg.RiskParams.MaxPositionSizeUSD,
+		DecisionEvery:      *every,
+		OutputPath:         *outPath,
+	}
+	res, err := engine.Run(context.Background())
+	if err != nil {
+		fatalf("backtest: %v", err)
+	}
+	snap := res.AnalyticsSnapshot(traderCfg.ID)
+	fmt.Printf("trader=%s steps=%d decisions=%d errors=%d opens=%d closes=%d\n",
+		traderCfg.ID, res.Steps, res.Decisions, res.DecisionErrors, res.Opens, res.Closes)
+	fmt.Printf("equity %.2f -> %.2f usd pnl=%.2f usd (%.2f%%) fees=%.2f usd\n",
+		res.InitialEquity, res.FinalEquity, snap.TotalPnLUSD, snap.TotalPnLPct, res.Fees)
+	fmt.Printf("win_rate=%.2f sharpe=%.3f max_drawdown_pct=%.2f\n", snap.WinRate, snap.SharpeRatio, snap.MaxDrawdownPct)
+}
+
+func loadTicks(csvPath, dsn, provider string, symbols []string, fromRaw, toRaw string) ([]backtest.PriceTick, error) {
+	if csvPath != "" {
+		ticks, err := backtest.LoadPriceTicksCSVFile(csvPath)
+		if err != nil {
+			return nil, err
+		}
+		return filterSymbols(ticks, symbols), nil
+	}
+	if dsn == "" {
+		return nil, fmt.Errorf("either --ticks-csv or --dsn is required")
+	}
+	from, err := parseBound(fromRaw, time.Unix(0, 0))
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseBound(toRaw, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		Symbol string  `db:"symbol"`
+		Price  float64 `db:"price"`
+		TsMs   int64   `db:"ts_ms"`
+	}
+	conn := sqlx.NewSqlConn("pgx", dsn)
+	query := `SELECT symbol, price, ts_ms FROM price_ticks WHERE ts_ms BETWEEN $1 AND $2 AND ($3 = '' OR provider = $3) ORDER BY ts_ms`
+	if err := conn.QueryRowsCtx(context.Background(), &rows, query, from.UnixMilli(), to.UnixMilli(), provider); err != nil {
+		return nil, fmt.Errorf("query price_ticks: %w", err)
+	}
+	ticks := make([]backtest.PriceTick, 0, len(rows))
+	for _, r := range rows {
+		ticks = append(ticks, backtest.PriceTick{Symbol: strings.ToUpper(r.Symbol), Price: r.Price, TsMs: r.TsMs})
+	}
+	return filterSymbols(ticks, symbols), nil
+}
+
+func parseBound(raw string, fallback time.Time) (time.Time, error) {
+	if strings.TrimSpace(raw) == "" {
+		return fallback, nil
+	}
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(raw))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: %w", raw, err)
+	}
+	return t, nil
+}
+
+func filterSymbols(ticks []backtest.PriceTick, symbols []string) []backtest.PriceTick {
+	if len(symbols) == 0 {
+		return ticks
+	}
+	want := make(map[string]bool, len(symbols))
+	for _, s := range symbols {
+		want[s] = true
+	}
+	out := ticks[:0]
+	for _, t := range ticks {
+		if want[t.Symbol] {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func loadCycles(path string) ([]backtest.HistoricalCycle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return backtest.LoadHistoricalCycles(f)
+}
+
+func pickTrader(cfg *managerpkg.Config, id string) (managerpkg.TraderConfig, error) {
+	if len(cfg.Traders) == 0 {
+		return managerpkg.TraderConfig{}, fmt.Errorf("manager config defines no traders")
+	}
+	if strings.TrimSpace(id) == "" {
+		return cfg.Traders[0], nil
+	}
+	for _, t := range cfg.Traders {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return managerpkg.TraderConfig{}, fmt.Errorf("trader %s not found in manager config", id)
+}
+
+func parseSymbols(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if s := strings.ToUpper(strings.TrimSpace(part)); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func fatalf(format string, args ...interface{}) {
+	logx.Errorf(format, args...)
+	os.Exit(1)
+}
@@ -229,8 +229,8 @@ func monitorMarket(parentCtx context.Context, provider market.Provider) {
 			}
 
 			// Validate data
-			if snapshot.Price.Last <= 0 {
-				log.Printf("[market.snapshot.%s] [WARN] invalid price=%f, took %dms", sym, snapshot.Price.Last, elapsed.Milliseconds())
+			if err := market.CheckSnapshot(snapshot, market.DefaultSanityLimits()); err != nil {
+				log.Printf("[market.snapshot.%s] [WARN] %v, took %dms", sym, err, elapsed.Milliseconds())
 				return
 			}
 
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+
+	"nof0-api/internal/config"
+	"nof0-api/internal/model"
+)
+
+const queryTimeout = 30 * time.Second
+
+// exportRow is the flattened view of a trade written by the exporter.
+type exportRow struct {
+	TraderID    string   `json:"trader_id"`
+	Symbol      string   `json:"symbol"`
+	Side        string   `json:"side"`
+	Quantity    *float64 `json:"quantity"`
+	Leverage    *float64 `json:"leverage"`
+	Confidence  *float64 `json:"confidence"`
+	EntryPrice  *float64 `json:"entry_price"`
+	ExitPrice   *float64 `json:"exit_price"`
+	EntryTime   string   `json:"entry_time"`
+	ExitTime    string   `json:"exit_time,omitempty"`
+	GrossPnL    *float64 `json:"realized_gross_pnl"`
+	NetPnL      *float64 `json:"realized_net_pnl"`
+	FeesDollars *float64 `json:"fees_usd"`
+}
+
+var csvHeader = []string{
+	"trader_id", "symbol", "side", "quantity", "leverage", "confidence",
+	"entry_price", "exit_price", "entry_time", "exit_time",
+	"realized_gross_pnl", "realized_net_pnl", "fees_usd",
+}
+
+func main() {
+	var (
+		configPath string
+		dsn        string
+		traderID   string
+		fromRaw    string
+		toRaw      string
+		format     string
+		outPath    string
+	)
+	flag.StringVar(&configPath, "config", "etc/nof0.yaml", "App config providing postgres and cache settings")
+	flag.StringVar(&dsn, "dsn", "", "Postgres DSN (overrides the config)")
+	flag.StringVar(&traderID, "trader", "", "Trader (model) id to export")
+	flag.StringVar(&fromRaw, "from", "", "Earliest entry time, inclusive (YYYY-MM-DD or RFC3339)")
+	flag.StringVar(&toRaw, "to", "", "Latest entry time, exclusive (YYYY-MM-DD or RFC3339; default now)")
+	flag.StringVar(&format, "format", "csv", "Output format: csv or jsonl")
+	flag.StringVar(&outPath, "out", "", "Output file (default stdout)")
+	flag.Parse()
+
+	if strings.TrimSpace(traderID) == "" {
+		log.Fatal("--trader is required")
+	}
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "csv" && format != "jsonl" {
+		log.Fatalf("--format %q must be csv or jsonl", format)
+	}
+	from, err := parseTime(fromRaw)
+	if err != nil {
+		log.Fatalf("--from: %v", err)
+	}
+	to, err := parseTime(toRaw)
+	if err != nil {
+		log.Fatalf("--to: %v", err)
+	}
+	if !from.IsZero() && !to.IsZero() && !to.After(from) {
+		log.Fatalf("--to must be after --from")
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+	if dsn == "" {
+		dsn = cfg.Postgres.DataSource
+	}
+	if strings.TrimSpace(dsn) == "" {
+		log.Fatal("no postgres DSN: set --dsn or postgres.DataSource in the config")
+	}
+	if len(cfg.Cache) == 0 {
+		log.Fatal("cache configuration required by the trades model")
+	}
+	trades := model.NewTradesModel(sqlx.NewSqlConn("pgx", dsn), cfg.Cache)
+
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+	var fromMs, toMs int64
+	if !from.IsZero() {
+		fromMs = from.UnixMilli()
+	}
+	if !to.IsZero() {
+		toMs = to.UnixMilli()
+	}
+	records, err := trades.RangeByModel(ctx, traderID, fromMs, toMs)
+	if err != nil {
+		log.Fatalf("query trades: %v", err)
+	}
+
+	var out io.Writer = os.Stdout
+	if outPath != "" {
+		f, err := os.Create(outPath)
+		if err != nil {
+			log.Fatalf("create %s: %v", outPath, err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := writeTrades(out, format, records); err != nil {
+		log.Fatalf("write trades: %v", err)
+	}
+	log.Printf("exported %d trades for %s", len(records), traderID)
+}
+
+// parseTime accepts a date (UTC midnight) or an RFC3339 timestamp; empty
+// input yields the zero time.
+func parseTime(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, raw); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither YYYY-MM-DD nor RFC3339", raw)
+	}
+	return t, nil
+}
+
+// writeTrades writes records to w as CSV (with a header) or JSON lines.
+func writeTrades(w io.Writer, format string, records []model.TradeRecord) error {
+	switch format {
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return err
+		}
+		for _, rec := range records {
+			row := toExportRow(rec)
+			if err := cw.Write([]string{
+				row.TraderID, row.Symbol, row.Side,
+				formatFloat(row.Quantity), formatFloat(row.Leverage), formatFloat(row.Confidence),
+				formatFloat(row.EntryPrice), formatFloat(row.ExitPrice),
+				row.EntryTime, row.ExitTime,
+				formatFloat(row.GrossPnL), formatFloat(row.NetPnL), formatFloat(row.FeesDollars),
+			}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case "jsonl":
+		enc := json.NewEncoder(w)
+		for _, rec := range records {
+			if err := enc.Encode(toExportRow(rec)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+func toExportRow(rec model.TradeRecord) exportRow {
+	row := exportRow{
+		TraderID:    rec.ModelID,
+		Symbol:      rec.Symbol,
+		Side:        rec.Side,
+		Quantity:    rec.Quantity,
+		Leverage:    rec.Leverage,
+		Confidence:  rec.Confidence,
+		EntryPrice:  rec.EntryPrice,
+		ExitPrice:   rec.ExitPrice,
+		EntryTime:   formatMs(rec.EntryTsMs),
+		GrossPnL:    rec.RealizedGrossPnl,
+		NetPnL:      rec.RealizedNetPnl,
+		FeesDollars: rec.TotalCommissionDollars,
+	}
+	if rec.ExitTsMs != nil {
+		row.ExitTime = formatMs(*rec.ExitTsMs)
+	}
+	if row.FeesDollars == nil && (rec.EntryCommissionDollars != nil || rec.ExitCommissionDollars != nil) {
+		fees := 0.0
+		if rec.EntryCommissionDollars != nil {
+			fees += *rec.EntryCommissionDollars
+		}
+		if rec.ExitCommissionDollars != nil {
+			fees += *rec.ExitCommissionDollars
+		}
+		row.FeesDollars = &fees
+	}
+	return row
+}
+
+func formatMs(ms int64) string {
+	if ms <= 0 {
+		return ""
+	}
+	return time.UnixMilli(ms).UTC().Format(time.RFC3339)
+}
+
+func formatFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/internal/model"
+)
+
+func f64(v float64) *float64 { return &v }
+
+func sampleTrades() []model.TradeRecord {
+	exit := time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC).UnixMilli()
+	return []model.TradeRecord{
+		{
+			ModelID:                "trader_a",
+			Symbol:                 "BTC",
+			Side:                   "long",
+			Quantity:               f64(0.5),
+			Leverage:               f64(5),
+			Confidence:             f64(82),
+			EntryPrice:             f64(60000),
+			EntryTsMs:              time.Date(2025, 1, 2, 1, 0, 0, 0, time.UTC).UnixMilli(),
+			ExitPrice:              f64(61000),
+			ExitTsMs:               &exit,
+			RealizedGrossPnl:       f64(500),
+			RealizedNetPnl:         f64(470),
+			EntryCommissionDollars: f64(12),
+			ExitCommissionDollars:  f64(18),
+		},
+		{
+			ModelID:    "trader_a",
+			Symbol:     "ETH",
+			Side:       "short",
+			EntryPrice: f64(3000),
+			EntryTsMs:  time.Date(2025, 1, 2, 4, 0, 0, 0, time.UTC).UnixMilli(),
+		},
+	}
+}
+
+func TestWriteTradesCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeTrades(&buf, "csv", sampleTrades()))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, csvHeader, rows[0])
+	assert.Equal(t, []string{
+		"trader_a", "BTC", "long", "0.5", "5", "82", "60000", "61000",
+		"2025-01-02T01:00:00Z", "2025-01-02T03:00:00Z", "500", "470", "30",
+	}, rows[1])
+	// Open trades leave the exit columns empty.
+	assert.Equal(t, "", rows[2][7])
+	assert.Equal(t, "", rows[2][9])
+}
+
+func TestWriteTradesJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeTrades(&buf, "jsonl", sampleTrades()))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var row exportRow
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &row))
+	assert.Equal(t, "BTC", row.Symbol)
+	require.NotNil(t, row.FeesDollars)
+	assert.InDelta(t, 30, *row.FeesDollars, 1e-9)
+	assert.Equal(t, "2025-01-02T03:00:00Z", row.ExitTime)
+}
+
+func TestWriteTradesRejectsUnknownFormat(t *testing.T) {
+	assert.Error(t, writeTrades(&bytes.Buffer{}, "xml", nil))
+}
+
+func TestParseTime(t *testing.T) {
+	got, err := parseTime("2025-01-02")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), got)
+
+	got, err = parseTime("2025-01-02T03:04:05Z")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), got)
+
+	got, err = parseTime("")
+	require.NoError(t, err)
+	assert.True(t, got.IsZero())
+
+	_, err = parseTime("yesterday")
+	assert.Error(t, err)
+}
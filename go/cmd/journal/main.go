@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nof0-api/pkg/journal"
+)
+
+// journal reads a trader's cycle records back from the file journal and
+// prints them (prompt digest, decisions, actions, executions and CoT trace)
+// or exports them as JSON lines, to debug why a trader acted as it did.
+func main() {
+	var (
+		dir      = flag.String("dir", "", "Journal directory (default journal/<trader>)")
+		traderID = flag.String("trader", "", "Trader id whose journal to read")
+		fromRaw  = flag.String("from", "", "Earliest cycle time, inclusive (YYYY-MM-DD or RFC3339)")
+		toRaw    = flag.String("to", "", "Latest cycle time, exclusive (YYYY-MM-DD or RFC3339)")
+		status   = flag.String("status", "all", "Cycles to include: all, success or failure")
+		format   = flag.String("format", "text", "Output format: text or jsonl")
+		noCoT    = flag.Bool("no-cot", false, "Omit CoT traces from text output")
+		outPath  = flag.String("out", "", "Output file (default stdout)")
+	)
+	flag.Parse()
+
+	if *dir == "" {
+		if strings.TrimSpace(*traderID) == "" {
+			log.Fatal("--trader or --dir is required")
+		}
+		*dir = filepath.Join("journal", *traderID)
+	}
+	*format = strings.ToLower(strings.TrimSpace(*format))
+	if *format != "text" && *format != "jsonl" {
+		log.Fatalf("--format %q must be text or jsonl", *format)
+	}
+	filter := journal.Filter{TraderID: strings.TrimSpace(*traderID)}
+	var err error
+	if filter.Success, err = parseStatus(*status); err != nil {
+		log.Fatalf("--status: %v", err)
+	}
+	if filter.From, err = parseTime(*fromRaw); err != nil {
+		log.Fatalf("--from: %v", err)
+	}
+	if filter.To, err = parseTime(*toRaw); err != nil {
+		log.Fatalf("--to: %v", err)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.To.After(filter.From) {
+		log.Fatal("--to must be after --from")
+	}
+
+	records, err := journal.NewReader(*dir).Cycles(filter)
+	if err != nil {
+		log.Fatalf("read journal: %v", err)
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			log.Fatalf("create %s: %v", *outPath, err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := writeCycles(out, *format, records, !*noCoT); err != nil {
+		log.Fatalf("write cycles: %v", err)
+	}
+	log.Printf("read %d cycles from %s", len(records), *dir)
+}
+
+// parseStatus maps --status to a success filter; nil matches every cycle.
+func parseStatus(raw string) (*bool, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "all":
+		return nil, nil
+	case "success":
+		v := true
+		return &v, nil
+	case "failure":
+		v := false
+		return &v, nil
+	default:
+		return nil, fmt.Errorf("%q must be all, success or failure", raw)
+	}
+}
+
+// parseTime accepts a date (UTC midnight) or an RFC3339 timestamp; empty
+// input yields the zero time.
+func parseTime(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, raw); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither YYYY-MM-DD nor RFC3339", raw)
+	}
+	return t, nil
+}
+
+// writeCycles writes records to w as a readable report or as JSON lines.
+func writeCycles(w io.Writer, format string, records []*journal.CycleRecord, withCoT bool) error {
+	switch format {
+	case "text":
+		for _, rec := range records {
+			if err := writeCycleText(w, rec, withCoT); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "jsonl":
+		enc := json.NewEncoder(w)
+		for _, rec := range records {
+			if err := enc.Encode(rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+func writeCycleText(w io.Writer, rec *journal.CycleRecord, withCoT bool) error {
+	var b strings.Builder
+	outcome := "success"
+	if !rec.Success {
+		outcome = "failure"
+	}
+	fmt.Fprintf(&b, "=== cycle %d trader=%s at=%s %s\n", rec.CycleNumber, rec.TraderID, rec.Timestamp.UTC().Format(time.RFC3339), outcome)
+	if rec.ErrorMessage != "" {
+		fmt.Fprintf(&b, "error: %s\n", rec.ErrorMessage)
+	}
+	if rec.PromptDigest != "" {
+		fmt.Fprintf(&b, "prompt_digest: %s\n", rec.PromptDigest)
+	}
+	if rec.InputHash != "" {
+		fmt.Fprintf(&b, "input_hash: %s\n", rec.InputHash)
+	}
+	if rec.DecisionsJSON != "" {
+		fmt.Fprintf(&b, "decisions: %s\n", rec.DecisionsJSON)
+	}
+	for _, a := range rec.Actions {
+		data, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "action: %s\n", data)
+	}
+	for _, e := range rec.Executions {
+		fmt.Fprintf(&b, "execution: #%d %s %s %s", e.IntentIndex, e.Symbol, e.Action, e.Status)
+		if e.Reason != "" {
+			fmt.Fprintf(&b, " (%s)", e.Reason)
+		}
+		if e.NotionalUSD > 0 {
+			fmt.Fprintf(&b, " notional=%.2f price=%g size=%g", e.NotionalUSD, e.Price, e.Size)
+		}
+		b.WriteString("\n")
+	}
+	if withCoT && rec.CoTTrace != "" {
+		fmt.Fprintf(&b, "cot:\n%s\n", rec.CoTTrace)
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/journal"
+)
+
+func sampleCycles() []*journal.CycleRecord {
+	return []*journal.CycleRecord{
+		{
+			Timestamp:     time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+			TraderID:      "t1",
+			CycleNumber:   7,
+			PromptDigest:  "abc123",
+			CoTTrace:      "BTC above EMA20",
+			DecisionsJSON: `[{"symbol":"BTC","action":"open_long"}]`,
+			Actions:       []map[string]any{{"symbol": "BTC", "result": "ok"}},
+			Executions: []journal.DecisionExecution{
+				{IntentIndex: 0, Symbol: "BTC", Action: "open_long", Status: journal.ExecutionSubmitted, NotionalUSD: 250, Price: 100, Size: 2.5},
+				{IntentIndex: 1, Symbol: "ETH", Action: "open_short", Status: journal.ExecutionDropped, Reason: "cooldown"},
+			},
+			Success: true,
+		},
+		{
+			Timestamp:    time.Date(2025, 3, 1, 13, 0, 0, 0, time.UTC),
+			TraderID:     "t1",
+			CycleNumber:  8,
+			ErrorMessage: "llm timeout",
+		},
+	}
+}
+
+func TestWriteCyclesText(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeCycles(&buf, "text", sampleCycles(), true))
+	out := buf.String()
+	assert.Contains(t, out, "=== cycle 7 trader=t1 at=2025-03-01T12:00:00Z success")
+	assert.Contains(t, out, "prompt_digest: abc123")
+	assert.Contains(t, out, `decisions: [{"symbol":"BTC","action":"open_long"}]`)
+	assert.Contains(t, out, `action: {"result":"ok","symbol":"BTC"}`)
+	assert.Contains(t, out, "execution: #0 BTC open_long submitted notional=250.00 price=100 size=2.5")
+	assert.Contains(t, out, "execution: #1 ETH open_short dropped (cooldown)")
+	assert.Contains(t, out, "cot:\nBTC above EMA20")
+	assert.Contains(t, out, "=== cycle 8 trader=t1 at=2025-03-01T13:00:00Z failure\nerror: llm timeout")
+
+	buf.Reset()
+	require.NoError(t, writeCycles(&buf, "text", sampleCycles(), false))
+	assert.NotContains(t, buf.String(), "cot:")
+}
+
+func TestWriteCyclesJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeCycles(&buf, "jsonl", sampleCycles(), true))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var rec journal.CycleRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &rec))
+	assert.Equal(t, 7, rec.CycleNumber)
+	assert.Equal(t, "BTC above EMA20", rec.CoTTrace)
+}
+
+func TestParseStatus(t *testing.T) {
+	got, err := parseStatus("all")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	got, err = parseStatus("Failure")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.False(t, *got)
+
+	_, err = parseStatus("maybe")
+	assert.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
@@ -132,27 +133,37 @@ func adaptManagerConfig(cfg *managerpkg.Config, totalEquity float64, allowed []s
 	}
 
 	cfg.Manager.TotalEquityUSD = totalEquity
-	cfg.Manager.ReserveEquityPct = 0
 
-	perTraderEquity := totalEquity / float64(len(cfg.Traders))
-	allocationRemaining := 100.0
+	// Keep configured allocation_pct and reserve_equity_pct; only traders
+	// without an allocation split what the reserve and the others leave,
+	// the last of them absorbing rounding.
+	var missing []int
+	allocationRemaining := 100.0 - cfg.Manager.ReserveEquityPct
 	for i := range cfg.Traders {
-		tr := &cfg.Traders[i]
-		// Force allocation to split evenly across all configured traders; last trader absorbs rounding.
-		if i == len(cfg.Traders)-1 {
-			tr.AllocationPct = allocationRemaining
+		if cfg.Traders[i].AllocationPct > 0 {
+			allocationRemaining -= cfg.Traders[i].AllocationPct
 		} else {
-			share := 100.0 / float64(len(cfg.Traders))
-			tr.AllocationPct = share
+			missing = append(missing, i)
+		}
+	}
+	if len(missing) > 0 {
+		if allocationRemaining <= 0 {
+			return fmt.Errorf("no allocation left for %d trader(s) without allocation_pct after reserve %.2f%%", len(missing), cfg.Manager.ReserveEquityPct)
+		}
+		share := allocationRemaining / float64(len(missing))
+		for n, i := range missing {
+			if n == len(missing)-1 {
+				share = allocationRemaining
+			}
+			cfg.Traders[i].AllocationPct = share
 			allocationRemaining -= share
 		}
+	}
 
-		// Cap max position size to per-trader equity.
-		maxSize := perTraderEquity
-		if maxSize <= 0 {
-			maxSize = totalEquity
-		}
-		if tr.RiskParams.MaxPositionSizeUSD > maxSize {
+	for i := range cfg.Traders {
+		tr := &cfg.Traders[i]
+		// Cap max position size to the trader's share of equity.
+		if maxSize := totalEquity * tr.AllocationPct / 100; tr.RiskParams.MaxPositionSizeUSD > maxSize {
 			tr.RiskParams.MaxPositionSizeUSD = maxSize
 		}
 		if tr.RiskParams.MaxPositions > len(allowed) {
@@ -254,6 +265,11 @@ func main() {
 		promptProfile = flag.String("executor-prompt-profile", "default", "executor prompt profile (default|fast)")
 		paperTrading  = flag.Bool("paper-trading", false, "route trades to the in-memory simulator instead of live exchanges")
 		paperExchange = flag.String("paper-exchange-provider", "paper_trading", "exchange provider id to use when --paper-trading is enabled")
+		maxCycles     = flag.Int("max-cycles", 0, "stop after this many decision cycles across all traders (0 = unlimited)")
+		dryRun        = flag.Bool("dry-run", false, "log fully formed orders instead of submitting them to the exchange")
+		metricsAddr   = flag.String("metrics-addr", "", "serve /healthz and /metrics on this address (empty disables)")
+		probeExchange = flag.Bool("probe-exchanges", false, "verify exchange credentials and connectivity with a read-only call before trading")
+		flattenOnExit = flag.Bool("flatten-on-exit", false, "on shutdown, cancel resting orders and close every open position once in-flight cycles finish")
 	)
 	flag.Parse()
 	logx.MustSetup(logx.LogConf{})
@@ -284,6 +300,15 @@ func main() {
 	if err != nil {
 		fatalf("build exchange providers: %v", err)
 	}
+	if *probeExchange && !*paperTrading {
+		probeCtx, cancelProbe := context.WithTimeout(context.Background(), 30*time.Second)
+		err := exchangepkg.ProbeProviders(probeCtx, exchangeProviders)
+		cancelProbe()
+		if err != nil {
+			fatalf("%v", err)
+		}
+		logx.Infof("exchange probe ok providers=%d", len(exchangeProviders))
+	}
 
 	marketCfg, err := marketpkg.LoadConfig(*marketPath)
 	if err != nil {
@@ -344,6 +369,9 @@ func main() {
 	if err := adaptManagerConfig(managerCfg, *totalEquity, allowedSymbols); err != nil {
 		fatalf("adapt manager config: %v", err)
 	}
+	if *maxCycles > 0 {
+		managerCfg.Manager.MaxCycles = *maxCycles
+	}
 
 	// Validate trader-level model assignments against LLM config.
 	for _, trader := range managerCfg.Traders {
@@ -382,6 +410,8 @@ func main() {
 			TTL:                       ttlSet,
 			ConversationsModel:        svcCtx.ConversationsModel,
 			ConversationMessagesModel: svcCtx.ConversationMessagesModel,
+			TakerFeeBps:               managerCfg.Manager.TakerFeeBps,
+			MakerFeeBps:               managerCfg.Manager.MakerFeeBps,
 		})
 		marketPersist = marketpersist.NewService(marketpersist.Config{
 			SQLConn:          svcCtx.DBConn,
@@ -416,6 +446,14 @@ func main() {
 	execFactory := managerpkg.NewBasicExecutorFactory(llmClient, conversationRecorder)
 
 	mgr := managerpkg.NewManager(managerCfg, execFactory, exchangeProviders, filteredMarkets, persistService)
+	mgr.SetVaultProviderBuilder(exchangeCfg.BuildVaultProvider)
+	if ingestor != nil {
+		mgr.SetIngestStatusSource(func() any { return ingestor.Status() })
+	}
+	if *dryRun {
+		mgr.SetDryRun(true)
+		logx.Infof("dry-run enabled: orders will be logged, not submitted")
+	}
 
 	traderIDs := make([]string, 0, len(managerCfg.Traders))
 	for _, traderCfg := range managerCfg.Traders {
@@ -435,6 +473,17 @@ func main() {
 		hydrateCancel()
 	}
 
+	reconcileCtx, reconcileCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	report, err := mgr.ReconcileOnStartup(reconcileCtx)
+	if err != nil {
+		logx.Errorf("manager: startup reconcile err=%v", err)
+	}
+	if report != nil {
+		logx.Infof("manager: startup reconcile accounts=%d adopted=%d flattened=%d missing=%d mismatched=%d",
+			report.Accounts, len(report.Adopted), len(report.Flattened), len(report.Missing), len(report.Mismatched))
+	}
+	reconcileCancel()
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -442,6 +491,21 @@ func main() {
 		go ingestor.Run(ctx)
 	}
 
+	if addr := strings.TrimSpace(*metricsAddr); addr != "" {
+		statusServer := &http.Server{Addr: addr, Handler: mgr.StatusHandler(), ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			logx.Infof("serving health and metrics on %s", addr)
+			if err := statusServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logx.Errorf("metrics server exited: %v", err)
+			}
+		}()
+		defer func() {
+			shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer shutdownCancel()
+			_ = statusServer.Shutdown(shutdownCtx)
+		}()
+	}
+
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
@@ -452,8 +516,18 @@ func main() {
 	}()
 
 	logx.Infof("starting manager loop with equity=%.2f USD, symbols=%s", *totalEquity, strings.Join(allowedSymbols, ","))
-	if err := mgr.RunTradingLoop(ctx); err != nil && err != context.Canceled {
-		fatalf("manager loop exited with error: %v", err)
+	loopErr := mgr.RunTradingLoop(ctx)
+	if *flattenOnExit {
+		flattenCtx, flattenCancel := context.WithTimeout(context.Background(), 60*time.Second)
+		if err := mgr.StopAndFlatten(flattenCtx); err != nil {
+			logx.Errorf("manager: flatten on exit err=%v", err)
+		} else {
+			logx.Info("manager: flattened all active traders")
+		}
+		flattenCancel()
+	}
+	if loopErr != nil && loopErr != context.Canceled {
+		fatalf("manager loop exited with error: %v", loopErr)
 	}
 	logx.Info("manager loop stopped")
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/zeromicro/go-zero/core/logx"
@@ -22,6 +23,37 @@ type MarketIngestor struct {
 	assetRefresh   time.Duration
 	delayPerSymbol time.Duration
 	assetsAt       map[string]time.Time
+
+	mu     sync.Mutex
+	status map[statusKey]*SymbolStatus
+}
+
+type statusKey struct{ provider, symbol string }
+
+// IngestStatus is a point-in-time health report for the ingestor.
+type IngestStatus struct {
+	Symbols []SymbolStatus `json:"symbols"`
+}
+
+// SymbolStatus tracks snapshot ingestion for one symbol on one provider.
+type SymbolStatus struct {
+	Provider    string    `json:"provider"`
+	Symbol      string    `json:"symbol"`
+	LastSuccess time.Time `json:"last_success,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at,omitempty"`
+	Successes   int64     `json:"successes"`
+	Failures    int64     `json:"failures"`
+}
+
+// Symbol returns the status for provider/symbol and whether it was found.
+func (s IngestStatus) Symbol(provider, symbol string) (SymbolStatus, bool) {
+	for _, st := range s.Symbols {
+		if st.Provider == provider && st.Symbol == symbol {
+			return st, true
+		}
+	}
+	return SymbolStatus{}, false
 }
 
 const (
@@ -69,9 +101,50 @@ func NewMarketIngestor(providers map[string]marketpkg.Provider, symbols []string
 		assetRefresh:   assetRefresh,
 		delayPerSymbol: delay,
 		assetsAt:       make(map[string]time.Time, len(providers)),
+		status:         make(map[statusKey]*SymbolStatus),
 	}
 }
 
+// Status reports per-symbol snapshot ingestion health, ordered by provider
+// then symbol. Symbols not yet attempted are listed with zero counts.
+func (m *MarketIngestor) Status() IngestStatus {
+	if m == nil {
+		return IngestStatus{}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := IngestStatus{Symbols: make([]SymbolStatus, 0, len(m.orderedNames)*len(m.symbols))}
+	for _, name := range m.orderedNames {
+		for _, symbol := range m.symbols {
+			if st, ok := m.status[statusKey{name, symbol}]; ok {
+				out.Symbols = append(out.Symbols, *st)
+				continue
+			}
+			out.Symbols = append(out.Symbols, SymbolStatus{Provider: name, Symbol: symbol})
+		}
+	}
+	return out
+}
+
+func (m *MarketIngestor) recordSnapshot(provider, symbol string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := statusKey{provider, symbol}
+	st, ok := m.status[key]
+	if !ok {
+		st = &SymbolStatus{Provider: provider, Symbol: symbol}
+		m.status[key] = st
+	}
+	if err != nil {
+		st.Failures++
+		st.LastError = err.Error()
+		st.LastErrorAt = time.Now()
+		return
+	}
+	st.Successes++
+	st.LastSuccess = time.Now()
+}
+
 // Run starts the ingestion loop and blocks until the context is cancelled.
 func (m *MarketIngestor) Run(ctx context.Context) {
 	if m == nil || len(m.orderedNames) == 0 || len(m.symbols) == 0 {
@@ -132,8 +205,16 @@ func (m *MarketIngestor) refreshSnapshots(ctx context.Context) {
 				return
 			}
 			reqCtx, cancel := context.WithTimeout(ctx, defaultSnapshotTimeout)
-			if _, err := prov.Snapshot(reqCtx, symbol); err != nil && reqCtx.Err() == nil {
-				logx.WithContext(ctx).Errorf("market ingest: snapshot provider=%s symbol=%s err=%v", name, symbol, err)
+			_, err := prov.Snapshot(reqCtx, symbol)
+			switch {
+			case err == nil:
+				m.recordSnapshot(name, symbol, nil)
+			case ctx.Err() == nil:
+				// Per-request timeouts count as failures; shutdown does not.
+				if reqCtx.Err() == nil {
+					logx.WithContext(ctx).Errorf("market ingest: snapshot provider=%s symbol=%s err=%v", name, symbol, err)
+				}
+				m.recordSnapshot(name, symbol, err)
 			}
 			cancel()
 			if m.delayPerSymbol > 0 {
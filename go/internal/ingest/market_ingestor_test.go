@@ -0,0 +1,74 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	marketpkg "nof0-api/pkg/market"
+)
+
+type stubMarket struct {
+	fail map[string]error
+}
+
+func (s *stubMarket) Snapshot(_ context.Context, symbol string) (*marketpkg.Snapshot, error) {
+	if err := s.fail[symbol]; err != nil {
+		return nil, err
+	}
+	return &marketpkg.Snapshot{Symbol: symbol, Price: marketpkg.PriceInfo{Last: 1}}, nil
+}
+
+func (s *stubMarket) ListAssets(context.Context) ([]marketpkg.Asset, error) { return nil, nil }
+
+func TestMarketIngestorStatus(t *testing.T) {
+	stub := &stubMarket{fail: map[string]error{"ETH": errors.New("upstream 502")}}
+	ing := NewMarketIngestor(map[string]marketpkg.Provider{"hl": stub}, []string{"btc", "ETH", "SOL"}, time.Minute, 0, 0)
+
+	before := ing.Status()
+	require.Len(t, before.Symbols, 3, "configured symbols are listed before the first ingest")
+	assert.Zero(t, before.Symbols[0].Successes)
+
+	ing.refreshSnapshots(context.Background())
+	delete(stub.fail, "ETH")
+	stub.fail["SOL"] = errors.New("timeout")
+	ing.refreshSnapshots(context.Background())
+
+	status := ing.Status()
+	btc, ok := status.Symbol("hl", "BTC")
+	require.True(t, ok)
+	assert.Equal(t, int64(2), btc.Successes)
+	assert.Zero(t, btc.Failures)
+	assert.False(t, btc.LastSuccess.IsZero())
+	assert.Empty(t, btc.LastError)
+
+	eth, ok := status.Symbol("hl", "ETH")
+	require.True(t, ok)
+	assert.Equal(t, int64(1), eth.Successes, "ETH recovered on the second pass")
+	assert.Equal(t, int64(1), eth.Failures)
+	assert.Equal(t, "upstream 502", eth.LastError, "last error is kept after recovery")
+	assert.False(t, eth.LastErrorAt.After(eth.LastSuccess))
+
+	sol, ok := status.Symbol("hl", "SOL")
+	require.True(t, ok)
+	assert.Equal(t, int64(1), sol.Successes)
+	assert.Equal(t, int64(1), sol.Failures)
+	assert.Equal(t, "timeout", sol.LastError)
+}
+
+func TestMarketIngestorStatusIgnoresShutdown(t *testing.T) {
+	stub := &stubMarket{fail: map[string]error{"BTC": context.Canceled}}
+	ing := NewMarketIngestor(map[string]marketpkg.Provider{"hl": stub}, []string{"BTC"}, time.Minute, 0, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ing.refreshSnapshots(ctx)
+
+	btc, ok := ing.Status().Symbol("hl", "BTC")
+	require.True(t, ok)
+	assert.Zero(t, btc.Failures, "cancelled runs should not count as failures")
+}
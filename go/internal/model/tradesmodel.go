@@ -3,6 +3,7 @@ package model
 import (
 	"context"
 	"fmt"
+	"math"
 
 	"github.com/zeromicro/go-zero/core/stores/cache"
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
@@ -54,6 +55,7 @@ type (
 	TradesModel interface {
 		tradesModel
 		RecentByModel(ctx context.Context, modelID string, limit int) ([]TradeRecord, error)
+		RangeByModel(ctx context.Context, modelID string, fromMs, toMs int64) ([]TradeRecord, error)
 	}
 
 	customTradesModel struct {
@@ -61,23 +63,8 @@ type (
 	}
 )
 
-// NewTradesModel returns a model for the database table.
-func NewTradesModel(conn sqlx.SqlConn, c cache.CacheConf, opts ...cache.Option) TradesModel {
-	return &customTradesModel{
-		defaultTradesModel: newTradesModel(conn, c, opts...),
-	}
-}
-
-// RecentByModel returns trades for the given model ordered by entry timestamp
-// descending. Limit defaults to 200 when non-positive.
-func (m *customTradesModel) RecentByModel(ctx context.Context, modelID string, limit int) ([]TradeRecord, error) {
-	if limit <= 0 {
-		limit = 200
-	}
-
-	const query = `
-SELECT
-    id,
+// tradeRecordColumns lists the columns scanned into a TradeRecord.
+const tradeRecordColumns = `    id,
     model_id,
     exchange_provider,
     symbol,
@@ -110,7 +97,25 @@ SELECT
     exit_plan,
     realized_gross_pnl,
     realized_net_pnl,
-    total_commission_dollars
+    total_commission_dollars`
+
+// NewTradesModel returns a model for the database table.
+func NewTradesModel(conn sqlx.SqlConn, c cache.CacheConf, opts ...cache.Option) TradesModel {
+	return &customTradesModel{
+		defaultTradesModel: newTradesModel(conn, c, opts...),
+	}
+}
+
+// RecentByModel returns trades for the given model ordered by entry timestamp
+// descending. Limit defaults to 200 when non-positive.
+func (m *customTradesModel) RecentByModel(ctx context.Context, modelID string, limit int) ([]TradeRecord, error) {
+	if limit <= 0 {
+		limit = 200
+	}
+
+	const query = `
+SELECT
+` + tradeRecordColumns + `
 FROM public.trades
 WHERE model_id = $1
 ORDER BY entry_ts_ms DESC
@@ -128,6 +133,33 @@ LIMIT $2`
 	return result, nil
 }
 
+// RangeByModel returns trades for the given model whose entry timestamp falls
+// in [fromMs, toMs), ordered by entry timestamp ascending. A non-positive toMs
+// leaves the range open-ended.
+func (m *customTradesModel) RangeByModel(ctx context.Context, modelID string, fromMs, toMs int64) ([]TradeRecord, error) {
+	if toMs <= 0 {
+		toMs = math.MaxInt64
+	}
+
+	const query = `
+SELECT
+` + tradeRecordColumns + `
+FROM public.trades
+WHERE model_id = $1 AND entry_ts_ms >= $2 AND entry_ts_ms < $3
+ORDER BY entry_ts_ms ASC`
+
+	var rows []Trades
+	if err := m.QueryRowsNoCacheCtx(ctx, &rows, query, modelID, fromMs, toMs); err != nil {
+		return nil, fmt.Errorf("trades.RangeByModel query: %w", err)
+	}
+
+	result := make([]TradeRecord, 0, len(rows))
+	for i := range rows {
+		result = append(result, buildTradeRecord(&rows[i]))
+	}
+	return result, nil
+}
+
 func buildTradeRecord(row *Trades) TradeRecord {
 	rec := TradeRecord{
 		ID:               row.Id,
@@ -28,6 +28,9 @@ import (
 
 var (
 	_ managerpkg.PersistenceService    = (*Service)(nil)
+	_ managerpkg.RealizedPnLReader     = (*Service)(nil)
+	_ managerpkg.OpenPositionReader    = (*Service)(nil)
+	_ managerpkg.LeverageRecorder      = (*Service)(nil)
 	_ executorpkg.ConversationRecorder = (*Service)(nil)
 )
 
@@ -43,6 +46,8 @@ type Service struct {
 	ttl                       cachekeys.TTLSet
 	conversationsModel        model.ConversationsModel
 	conversationMessagesModel model.ConversationMessagesModel
+	takerFeeBps               float64
+	makerFeeBps               float64
 }
 
 // Config enumerates dependencies needed to persist manager events.
@@ -57,13 +62,32 @@ type Config struct {
 	TTL                       cachekeys.TTLSet
 	ConversationsModel        model.ConversationsModel
 	ConversationMessagesModel model.ConversationMessagesModel
+	// TakerFeeBps and MakerFeeBps estimate commissions on fills the venue
+	// reports no fee for; nil keeps defaultTakerFeeBps and defaultMakerFeeBps.
+	TakerFeeBps *float64
+	MakerFeeBps *float64
 }
 
+// defaultTakerFeeBps and defaultMakerFeeBps are the Hyperliquid base-tier
+// fees. Order responses carry no fee, so commissions of IOC fills are
+// estimated from fill notional; post_only fills are charged the maker rate.
+const (
+	defaultTakerFeeBps = 4.5
+	defaultMakerFeeBps = 1.5
+)
+
 // NewService returns a concrete persistence service when mandatory dependencies are present.
 func NewService(cfg Config) managerpkg.PersistenceService {
 	if cfg.SQLConn == nil {
 		return nil
 	}
+	takerFeeBps, makerFeeBps := defaultTakerFeeBps, defaultMakerFeeBps
+	if cfg.TakerFeeBps != nil {
+		takerFeeBps = *cfg.TakerFeeBps
+	}
+	if cfg.MakerFeeBps != nil {
+		makerFeeBps = *cfg.MakerFeeBps
+	}
 	return &Service{
 		sqlConn:                   cfg.SQLConn,
 		positionsModel:            cfg.PositionsModel,
@@ -75,6 +99,8 @@ func NewService(cfg Config) managerpkg.PersistenceService {
 		ttl:                       cfg.TTL,
 		conversationsModel:        cfg.ConversationsModel,
 		conversationMessagesModel: cfg.ConversationMessagesModel,
+		takerFeeBps:               takerFeeBps,
+		makerFeeBps:               makerFeeBps,
 	}
 }
 
@@ -93,6 +119,8 @@ func (s *Service) RecordPositionEvent(ctx context.Context, event managerpkg.Posi
 		return s.handleOpenPosition(ctx, modelID, symbol, event)
 	case managerpkg.PositionEventClose:
 		return s.handleClosePosition(ctx, modelID, symbol, event)
+	case managerpkg.PositionEventReduce:
+		return s.handleReducePosition(ctx, modelID, symbol, event)
 	default:
 		return nil
 	}
@@ -142,10 +170,303 @@ func (s *Service) RecordDecisionCycle(ctx context.Context, record managerpkg.Dec
 	if err != nil {
 		return err
 	}
+	if err := s.insertCycleCandidates(ctx, mID, record.Cycle, row.ExecutedAt); err != nil {
+		logx.WithContext(ctx).Errorf("enginepersist: insert cycle candidates model=%s err=%v", mID, err)
+	}
+	if err := s.recordCycleGuards(ctx, mID, record.Cycle, row.ExecutedAt); err != nil {
+		logx.WithContext(ctx).Errorf("enginepersist: record cycle guards model=%s err=%v", mID, err)
+	}
+	if err := s.recordCycleInputHash(ctx, mID, record.Cycle, row.ExecutedAt); err != nil {
+		logx.WithContext(ctx).Errorf("enginepersist: record cycle input hash model=%s err=%v", mID, err)
+	}
+	if err := s.recordCycleID(ctx, mID, record.Cycle, row.ExecutedAt); err != nil {
+		logx.WithContext(ctx).Errorf("enginepersist: record cycle id model=%s err=%v", mID, err)
+	}
+	if conv, ok := cycleConversation(mID, record, row.ExecutedAt); ok {
+		if err := s.RecordConversation(ctx, conv); err != nil {
+			logx.WithContext(ctx).Errorf("enginepersist: record cycle conversation model=%s cycle=%s err=%v", mID, conv.CycleID, err)
+		}
+	}
+	if err := s.insertCycleIntents(ctx, mID, record.Cycle, row.ExecutedAt); err != nil {
+		logx.WithContext(ctx).Errorf("enginepersist: insert cycle intents model=%s err=%v", mID, err)
+	}
 	s.cacheDecisionSummary(ctx, mID, record)
 	return nil
 }
 
+// recordCycleGuards attaches the effective guard set to a stored decision cycle.
+func (s *Service) recordCycleGuards(ctx context.Context, modelID string, cycle *journal.CycleRecord, executedAt time.Time) error {
+	if s.sqlConn == nil || cycle == nil || len(cycle.Guards) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(cycle.Guards)
+	if err != nil {
+		return err
+	}
+	const statement = `UPDATE public.decision_cycles SET guards = $3 WHERE model_id = $1 AND executed_at = $2`
+	_, err = s.sqlConn.ExecCtx(ctx, statement, modelID, executedAt, string(payload))
+	return err
+}
+
+// recordCycleInputHash attaches the material input hash to a stored decision
+// cycle so analytics can group cycles that saw the same state.
+func (s *Service) recordCycleInputHash(ctx context.Context, modelID string, cycle *journal.CycleRecord, executedAt time.Time) error {
+	if s.sqlConn == nil || cycle == nil || strings.TrimSpace(cycle.InputHash) == "" {
+		return nil
+	}
+	const statement = `UPDATE public.decision_cycles SET input_hash = $3 WHERE model_id = $1 AND executed_at = $2`
+	_, err := s.sqlConn.ExecCtx(ctx, statement, modelID, executedAt, cycle.InputHash)
+	return err
+}
+
+// recordCycleID attaches the cycle id shared with the cycle's conversation
+// to a stored decision cycle.
+func (s *Service) recordCycleID(ctx context.Context, modelID string, cycle *journal.CycleRecord, executedAt time.Time) error {
+	if s.sqlConn == nil || cycle == nil || strings.TrimSpace(cycle.CycleID) == "" {
+		return nil
+	}
+	const statement = `UPDATE public.decision_cycles SET cycle_id = $3 WHERE model_id = $1 AND executed_at = $2`
+	_, err := s.sqlConn.ExecCtx(ctx, statement, modelID, executedAt, cycle.CycleID)
+	return err
+}
+
+// cycleConversation builds the conversation holding a cycle's full prompt
+// and the model's reply (CoT then decisions), linked by the cycle id. It
+// reports false when the trader does not persist prompts.
+func cycleConversation(modelID string, record managerpkg.DecisionCycleRecord, executedAt time.Time) (executorpkg.ConversationRecord, bool) {
+	if record.Cycle == nil || strings.TrimSpace(record.Prompt) == "" || strings.TrimSpace(record.Cycle.CycleID) == "" {
+		return executorpkg.ConversationRecord{}, false
+	}
+	var parts []string
+	for _, part := range []string{record.Cycle.CoTTrace, record.Cycle.DecisionsJSON} {
+		if s := strings.TrimSpace(part); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return executorpkg.ConversationRecord{
+		ModelID:          modelID,
+		Prompt:           record.Prompt,
+		PromptTokens:     record.Usage.PromptTokens,
+		Response:         strings.Join(parts, "\n\n"),
+		CompletionTokens: record.Usage.CompletionTokens,
+		TotalTokens:      record.Usage.TotalTokens,
+		Timestamp:        executedAt,
+		Topic:            "decision_cycle",
+		CycleID:          record.Cycle.CycleID,
+	}, true
+}
+
+// insertCycleIntents stores the model's decision intents and the manager's
+// execution outcomes in separate tables, linked by cycle id and intent index.
+func (s *Service) insertCycleIntents(ctx context.Context, modelID string, cycle *journal.CycleRecord, executedAt time.Time) error {
+	if s.sqlConn == nil || cycle == nil || (len(cycle.Intents) == 0 && len(cycle.Executions) == 0) {
+		return nil
+	}
+	const intentStatement = `INSERT INTO public.decision_intents (model_id, cycle_id, cycle_number, executed_at, intent_index, symbol, action, leverage, position_size_usd, entry_price, stop_loss, take_profit, confidence) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+	const executionStatement = `INSERT INTO public.decision_executions (model_id, cycle_id, cycle_number, executed_at, intent_index, symbol, action, status, reason, price, size, notional_usd, leverage, order_style, reduce_only) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+	cycleID := sql.NullString{String: cycle.CycleID, Valid: strings.TrimSpace(cycle.CycleID) != ""}
+	var cycleNumber sql.NullInt64
+	if cycle.CycleNumber > 0 {
+		cycleNumber = sql.NullInt64{Int64: int64(cycle.CycleNumber), Valid: true}
+	}
+	return s.sqlConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		for _, in := range cycle.Intents {
+			if _, err := session.ExecCtx(ctx, intentStatement, modelID, cycleID, cycleNumber, executedAt, in.Index, in.Symbol, in.Action,
+				in.Leverage, in.PositionSizeUSD, in.EntryPrice, in.StopLoss, in.TakeProfit, in.Confidence); err != nil {
+				return err
+			}
+		}
+		for _, ex := range cycle.Executions {
+			reason := sql.NullString{String: ex.Reason, Valid: ex.Reason != ""}
+			orderStyle := sql.NullString{String: ex.OrderStyle, Valid: ex.OrderStyle != ""}
+			if _, err := session.ExecCtx(ctx, executionStatement, modelID, cycleID, cycleNumber, executedAt, ex.IntentIndex, ex.Symbol, ex.Action,
+				ex.Status, reason, ex.Price, ex.Size, ex.NotionalUSD, ex.Leverage, orderStyle, ex.ReduceOnly); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// IntentOutcome pairs a persisted decision intent with the execution the
+// manager recorded for it; Execution is nil when none was stored.
+type IntentOutcome struct {
+	Intent    journal.DecisionIntent
+	Execution *journal.DecisionExecution
+}
+
+// CycleIntents returns the intents persisted for cycleID, in the order the
+// model produced them, each joined with its execution outcome.
+func (s *Service) CycleIntents(ctx context.Context, cycleID string) ([]IntentOutcome, error) {
+	if s == nil || s.sqlConn == nil || strings.TrimSpace(cycleID) == "" {
+		return nil, nil
+	}
+	const query = `SELECT i.intent_index, i.symbol, i.action, i.leverage, i.position_size_usd, i.entry_price, i.stop_loss, i.take_profit, i.confidence,
+		e.symbol AS exec_symbol, e.action AS exec_action, e.status, e.reason, e.price, e.size, e.notional_usd, e.leverage AS exec_leverage, e.order_style, e.reduce_only
+		FROM public.decision_intents i
+		LEFT JOIN public.decision_executions e ON e.cycle_id = i.cycle_id AND e.intent_index = i.intent_index
+		WHERE i.cycle_id = $1
+		ORDER BY i.intent_index ASC`
+	var rows []struct {
+		IntentIndex     int             `db:"intent_index"`
+		Symbol          string          `db:"symbol"`
+		Action          string          `db:"action"`
+		Leverage        sql.NullInt64   `db:"leverage"`
+		PositionSizeUSD sql.NullFloat64 `db:"position_size_usd"`
+		EntryPrice      sql.NullFloat64 `db:"entry_price"`
+		StopLoss        sql.NullFloat64 `db:"stop_loss"`
+		TakeProfit      sql.NullFloat64 `db:"take_profit"`
+		Confidence      sql.NullInt64   `db:"confidence"`
+		ExecSymbol      sql.NullString  `db:"exec_symbol"`
+		ExecAction      sql.NullString  `db:"exec_action"`
+		Status          sql.NullString  `db:"status"`
+		Reason          sql.NullString  `db:"reason"`
+		Price           sql.NullFloat64 `db:"price"`
+		Size            sql.NullFloat64 `db:"size"`
+		NotionalUSD     sql.NullFloat64 `db:"notional_usd"`
+		ExecLeverage    sql.NullInt64   `db:"exec_leverage"`
+		OrderStyle      sql.NullString  `db:"order_style"`
+		ReduceOnly      sql.NullBool    `db:"reduce_only"`
+	}
+	if err := s.sqlConn.QueryRowsCtx(ctx, &rows, query, cycleID); err != nil {
+		return nil, err
+	}
+	out := make([]IntentOutcome, 0, len(rows))
+	for _, r := range rows {
+		outcome := IntentOutcome{Intent: journal.DecisionIntent{
+			Index:           r.IntentIndex,
+			Symbol:          r.Symbol,
+			Action:          r.Action,
+			Leverage:        int(r.Leverage.Int64),
+			PositionSizeUSD: r.PositionSizeUSD.Float64,
+			EntryPrice:      r.EntryPrice.Float64,
+			StopLoss:        r.StopLoss.Float64,
+			TakeProfit:      r.TakeProfit.Float64,
+			Confidence:      int(r.Confidence.Int64),
+		}}
+		if r.Status.Valid {
+			outcome.Execution = &journal.DecisionExecution{
+				IntentIndex: r.IntentIndex,
+				Symbol:      r.ExecSymbol.String,
+				Action:      r.ExecAction.String,
+				Status:      r.Status.String,
+				Reason:      r.Reason.String,
+				Price:       r.Price.Float64,
+				Size:        r.Size.Float64,
+				NotionalUSD: r.NotionalUSD.Float64,
+				Leverage:    int(r.ExecLeverage.Int64),
+				OrderStyle:  r.OrderStyle.String,
+				ReduceOnly:  r.ReduceOnly.Bool,
+			}
+		}
+		out = append(out, outcome)
+	}
+	return out, nil
+}
+
+// insertCycleCandidates stores the ranked candidate universe offered in a cycle.
+func (s *Service) insertCycleCandidates(ctx context.Context, modelID string, cycle *journal.CycleRecord, executedAt time.Time) error {
+	if s.sqlConn == nil || cycle == nil || len(cycle.CandidateSet) == 0 {
+		return nil
+	}
+	const statement = `INSERT INTO public.cycle_candidates (model_id, cycle_id, cycle_number, executed_at, rank, symbol, score, sources) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	cycleID := sql.NullString{String: cycle.CycleID, Valid: strings.TrimSpace(cycle.CycleID) != ""}
+	var cycleNumber sql.NullInt64
+	if cycle.CycleNumber > 0 {
+		cycleNumber = sql.NullInt64{Int64: int64(cycle.CycleNumber), Valid: true}
+	}
+	return s.sqlConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		for i, c := range cycle.CandidateSet {
+			if _, err := session.ExecCtx(ctx, statement, modelID, cycleID, cycleNumber, executedAt, i+1, c.Symbol, c.Score, pq.Array(c.Sources)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CycleCandidates returns the candidates persisted for cycleID, in the order
+// they were ranked.
+func (s *Service) CycleCandidates(ctx context.Context, cycleID string) ([]journal.CandidateScore, error) {
+	if s == nil || s.sqlConn == nil || strings.TrimSpace(cycleID) == "" {
+		return nil, nil
+	}
+	const query = `SELECT symbol, score, sources FROM public.cycle_candidates WHERE cycle_id = $1 ORDER BY rank ASC`
+	var rows []struct {
+		Symbol  string          `db:"symbol"`
+		Score   sql.NullFloat64 `db:"score"`
+		Sources pq.StringArray  `db:"sources"`
+	}
+	if err := s.sqlConn.QueryRowsCtx(ctx, &rows, query, cycleID); err != nil {
+		return nil, err
+	}
+	out := make([]journal.CandidateScore, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, journal.CandidateScore{Symbol: r.Symbol, Score: r.Score.Float64, Sources: []string(r.Sources)})
+	}
+	return out, nil
+}
+
+// RealizedGrossPnL sums realized gross PnL of trades closed within
+// [start, end], before commissions and funding.
+func (s *Service) RealizedGrossPnL(ctx context.Context, traderID string, start, end time.Time) (float64, error) {
+	if s == nil || s.sqlConn == nil {
+		return 0, nil
+	}
+	const query = `SELECT COALESCE(SUM(realized_gross_pnl), 0) FROM public.trades WHERE model_id = $1 AND exit_ts_ms >= $2 AND exit_ts_ms <= $3`
+	var total float64
+	if err := s.sqlConn.QueryRowCtx(ctx, &total, query, traderID, start.UTC().UnixMilli(), end.UTC().UnixMilli()); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// RecordLeverageChange appends a leverage/margin-mode update to the audit table.
+func (s *Service) RecordLeverageChange(ctx context.Context, change managerpkg.LeverageChange) error {
+	if s == nil || s.sqlConn == nil || strings.TrimSpace(change.TraderID) == "" {
+		return nil
+	}
+	changedAt := change.ChangedAt
+	if changedAt.IsZero() {
+		changedAt = time.Now()
+	}
+	const statement = `INSERT INTO public.leverage_changes (model_id, symbol, asset, margin_mode, leverage, changed_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := s.sqlConn.ExecCtx(ctx, statement, change.TraderID, strings.ToUpper(strings.TrimSpace(change.Symbol)), change.Asset, change.Mode, change.Leverage, changedAt.UTC())
+	return err
+}
+
+// RecordFundingAccrual stores the cumulative funding accrued on an open position.
+func (s *Service) RecordFundingAccrual(ctx context.Context, accrual managerpkg.FundingAccrual) error {
+	if s == nil || s.sqlConn == nil || strings.TrimSpace(accrual.TraderID) == "" {
+		return nil
+	}
+	const statement = `UPDATE public.positions SET funding_accrued = $2, funding_accrued_through = $3, updated_at = NOW() WHERE id = $1 AND status = 'open'`
+	_, err := s.sqlConn.ExecCtx(ctx, statement, positionID(accrual.TraderID, strings.ToUpper(strings.TrimSpace(accrual.Symbol))), accrual.TotalUSD, accrual.Through.UTC())
+	return err
+}
+
+// OpenPositions returns the positions still marked open for a trader.
+func (s *Service) OpenPositions(ctx context.Context, traderID string) ([]managerpkg.CachedPosition, error) {
+	if s == nil || s.positionsModel == nil {
+		return nil, nil
+	}
+	data, err := s.positionsModel.ActiveByModels(ctx, []string{traderID})
+	if err != nil {
+		return nil, err
+	}
+	records := data[traderID]
+	out := make([]managerpkg.CachedPosition, 0, len(records))
+	for _, rec := range records {
+		out = append(out, managerpkg.CachedPosition{
+			Symbol:     strings.ToUpper(strings.TrimSpace(rec.Symbol)),
+			Side:       strings.ToLower(strings.TrimSpace(rec.Side)),
+			Quantity:   rec.Quantity,
+			EntryPrice: rec.EntryPrice,
+		})
+	}
+	return out, nil
+}
+
 // RecordAccountSnapshot captures periodic equity metrics.
 func (s *Service) RecordAccountSnapshot(ctx context.Context, snapshot managerpkg.AccountSyncSnapshot) error {
 	if s == nil || s.snapshotsModel == nil || snapshot.TraderID == "" {
@@ -158,13 +479,14 @@ func (s *Service) RecordAccountSnapshot(ctx context.Context, snapshot managerpkg
 	metaPayload := map[string]any{
 		"available_balance_usd": snapshot.AvailableBalanceUSD,
 		"unrealized_pnl_usd":    snapshot.UnrealizedPnLUSD,
+		"realized_pnl_usd":      snapshot.RealizedPnLUSD,
 	}
 	metaBytes, _ := json.Marshal(metaPayload)
 	row := &model.AccountEquitySnapshots{
 		ModelId:            snapshot.TraderID,
 		TsMs:               ts.UTC().UnixMilli(),
 		DollarEquity:       snapshot.EquityUSD,
-		RealizedPnl:        0,
+		RealizedPnl:        snapshot.RealizedPnLUSD,
 		TotalUnrealizedPnl: snapshot.UnrealizedPnLUSD,
 		Metadata:           string(metaBytes),
 	}
@@ -575,7 +897,10 @@ func (s *Service) RecordConversation(ctx context.Context, rec executorpkg.Conver
 		return nil
 	}
 	modelID := strings.TrimSpace(rec.ModelID)
-	if modelID == "" || strings.TrimSpace(rec.Prompt) == "" || strings.TrimSpace(rec.Response) == "" {
+	// A cycle's prompt is kept even when the call produced no reply, since
+	// failed cycles are the ones worth debugging.
+	linked := strings.TrimSpace(rec.CycleID) != ""
+	if modelID == "" || strings.TrimSpace(rec.Prompt) == "" || (strings.TrimSpace(rec.Response) == "" && !linked) {
 		return nil
 	}
 	ts := rec.Timestamp
@@ -587,12 +912,16 @@ func (s *Service) RecordConversation(ctx context.Context, rec executorpkg.Conver
 	if trimmed := strings.TrimSpace(rec.Topic); trimmed != "" {
 		topic = sql.NullString{String: trimmed, Valid: true}
 	}
+	cycleID := sql.NullString{}
+	if linked {
+		cycleID = sql.NullString{String: strings.TrimSpace(rec.CycleID), Valid: true}
+	}
 	err := s.sqlConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
 		insertConv := `
-INSERT INTO public.conversations (model_id, topic, created_at)
-VALUES ($1, $2, NOW())
+INSERT INTO public.conversations (model_id, topic, cycle_id, created_at)
+VALUES ($1, $2, $3, NOW())
 RETURNING id`
-		if err := session.QueryRowCtx(ctx, &conversationID, insertConv, modelID, topic); err != nil {
+		if err := session.QueryRowCtx(ctx, &conversationID, insertConv, modelID, topic, cycleID); err != nil {
 			return err
 		}
 		if err := s.insertConversationMessage(ctx, session, conversationID, "system", rec.Prompt, rec.PromptTokens, ts, map[string]any{
@@ -603,6 +932,9 @@ RETURNING id`
 		}); err != nil {
 			return err
 		}
+		if strings.TrimSpace(rec.Response) == "" {
+			return nil
+		}
 		return s.insertConversationMessage(ctx, session, conversationID, "assistant", rec.Response, rec.CompletionTokens, ts, map[string]any{
 			"model":             rec.ModelName,
 			"completion_tokens": rec.CompletionTokens,
@@ -633,11 +965,11 @@ func (s *Service) handleOpenPosition(ctx context.Context, modelID, symbol string
 INSERT INTO public.positions (
     id, model_id, exchange_provider, symbol, side, status,
     entry_time_ms, entry_price, leverage, quantity, confidence, risk_usd,
-    wait_for_fill, created_at, updated_at
+    commission, wait_for_fill, created_at, updated_at
 ) VALUES (
     $1, $2, $3, $4, $5, 'open',
     $6, $7, $8, $9, $10, $11,
-    FALSE, NOW(), NOW()
+    $12, FALSE, NOW(), NOW()
 )
 ON CONFLICT (id) DO UPDATE SET
     side = EXCLUDED.side,
@@ -648,6 +980,7 @@ ON CONFLICT (id) DO UPDATE SET
     quantity = EXCLUDED.quantity,
     confidence = EXCLUDED.confidence,
     risk_usd = EXCLUDED.risk_usd,
+    commission = EXCLUDED.commission,
     updated_at = NOW();
 `
 	_, err := s.sqlConn.ExecCtx(
@@ -660,10 +993,11 @@ ON CONFLICT (id) DO UPDATE SET
 		side,
 		entryTime.UTC().UnixMilli(),
 		price,
-		float64(event.Decision.Leverage),
+		float64(activeLeverage(event)),
 		qty,
 		float64(event.Decision.Confidence),
 		event.Decision.RiskUSD,
+		nullFloatValue(toNullFloat(s.fillFee(event, price, qty), price > 0 && qty > 0)),
 	)
 	if err != nil {
 		return err
@@ -673,7 +1007,7 @@ ON CONFLICT (id) DO UPDATE SET
 		Side:        side,
 		Quantity:    qty,
 		EntryPrice:  price,
-		Leverage:    float64(event.Decision.Leverage),
+		Leverage:    float64(activeLeverage(event)),
 		Confidence:  float64(event.Decision.Confidence),
 		RiskUSD:     event.Decision.RiskUSD,
 		UpdatedAtMs: time.Now().UTC().UnixMilli(),
@@ -692,7 +1026,12 @@ func (s *Service) handleClosePosition(ctx context.Context, modelID, symbol strin
 			return err
 		}
 	}
+	// Prefer the venue's reported average fill over decision-derived values.
+	fillPx, fillQty, filled := extractFill(event.ExchangeResponse)
 	closePrice := effectivePrice(event)
+	if filled && fillPx > 0 {
+		closePrice = fillPx
+	}
 	if closePrice <= 0 && existing != nil && existing.EntryPrice > 0 {
 		closePrice = existing.EntryPrice
 	}
@@ -701,21 +1040,24 @@ func (s *Service) handleClosePosition(ctx context.Context, modelID, symbol strin
 		closeTime = time.Now()
 	}
 	qty := effectiveQuantity(event, closePrice)
+	if filled && fillQty > 0 {
+		qty = fillQty
+	}
 	if qty <= 0 && existing != nil && existing.Quantity > 0 {
 		qty = existing.Quantity
 	}
-	qtyForPnl := qty
-	if qtyForPnl <= 0 && existing != nil {
-		qtyForPnl = existing.Quantity
-	}
-	var pnl sql.NullFloat64
-	if existing != nil && closePrice > 0 && existing.EntryPrice > 0 && qtyForPnl > 0 {
-		sign := 1.0
-		if strings.EqualFold(existing.Side, "short") {
-			sign = -1.0
+	var result *tradePnL
+	if existing != nil && closePrice > 0 && existing.EntryPrice > 0 && qty > 0 {
+		entryFee := estimateFee(existing.EntryPrice, existing.Quantity, s.takerFeeBps)
+		if existing.Commission.Valid {
+			entryFee = existing.Commission.Float64
 		}
-		value := sign * (closePrice - existing.EntryPrice) * qtyForPnl
-		pnl = sql.NullFloat64{Float64: value, Valid: true}
+		pnl := computeTradePnL(existing.Side, existing.EntryPrice, closePrice, qty, existing.Quantity, entryFee, s.fillFee(event, closePrice, qty), event.FundingUSD)
+		result = &pnl
+	}
+	netPnl := sql.NullFloat64{}
+	if result != nil {
+		netPnl = sql.NullFloat64{Float64: result.Net, Valid: true}
 	}
 	statement := `
 UPDATE public.positions
@@ -725,10 +1067,10 @@ SET status = 'closed',
     updated_at = NOW()
 WHERE id = $1;
 `
-	if _, err := s.sqlConn.ExecCtx(ctx, statement, positionID(modelID, symbol), closePrice, nullFloatValue(pnl)); err != nil {
+	if _, err := s.sqlConn.ExecCtx(ctx, statement, positionID(modelID, symbol), closePrice, nullFloatValue(netPnl)); err != nil {
 		return err
 	}
-	summary, err := s.insertTrade(ctx, existing, modelID, symbol, closePrice, qty, pnl, closeTime, event)
+	summary, err := s.insertTrade(ctx, existing, modelID, symbol, closePrice, qty, result, closeTime, event)
 	if err != nil {
 		return err
 	}
@@ -739,7 +1081,84 @@ WHERE id = $1;
 	return nil
 }
 
-func (s *Service) insertTrade(ctx context.Context, pos *model.Positions, modelID, symbol string, closePrice, qty float64, pnl sql.NullFloat64, closeTime time.Time, event managerpkg.PositionEvent) (*tradeCacheEntry, error) {
+// handleReducePosition shrinks an open position by a partial close, booking
+// the reduced slice as a trade with its share of the entry commission. A
+// reduce that leaves nothing open is handled as a full close.
+func (s *Service) handleReducePosition(ctx context.Context, modelID, symbol string, event managerpkg.PositionEvent) error {
+	if s.positionsModel == nil {
+		return nil
+	}
+	existing, err := s.positionsModel.FindOne(ctx, positionID(modelID, symbol))
+	if err == model.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fillPx, fillQty, filled := extractFill(event.ExchangeResponse)
+	closePrice := effectivePrice(event)
+	if filled && fillPx > 0 {
+		closePrice = fillPx
+	}
+	qty := event.FillSize
+	if filled && fillQty > 0 {
+		qty = fillQty
+	}
+	if qty <= 0 {
+		return nil
+	}
+	remaining := existing.Quantity - qty
+	if remaining <= existing.Quantity*1e-9 {
+		return s.handleClosePosition(ctx, modelID, symbol, event)
+	}
+	closeTime := event.OccurredAt
+	if closeTime.IsZero() {
+		closeTime = time.Now()
+	}
+	entryFee := estimateFee(existing.EntryPrice, existing.Quantity, s.takerFeeBps)
+	if existing.Commission.Valid {
+		entryFee = existing.Commission.Float64
+	}
+	var result *tradePnL
+	if closePrice > 0 && existing.EntryPrice > 0 {
+		pnl := computeTradePnL(existing.Side, existing.EntryPrice, closePrice, qty, existing.Quantity, entryFee, s.fillFee(event, closePrice, qty), 0)
+		result = &pnl
+	}
+	// The remaining quantity keeps the unconsumed part of the entry commission.
+	remainingFee := entryFee * remaining / existing.Quantity
+	const statement = `
+UPDATE public.positions
+SET quantity = $2,
+    commission = $3,
+    current_price = CASE WHEN $4 > 0 THEN $4 ELSE current_price END,
+    updated_at = NOW()
+WHERE id = $1 AND status = 'open';
+`
+	if _, err := s.sqlConn.ExecCtx(ctx, statement, positionID(modelID, symbol), remaining, remainingFee, closePrice); err != nil {
+		return err
+	}
+	summary, err := s.insertTrade(ctx, existing, modelID, symbol, closePrice, qty, result, closeTime, event)
+	if err != nil {
+		return err
+	}
+	s.cacheOpenPosition(ctx, modelID, symbol, &positionCacheEntry{
+		Symbol:      symbol,
+		Side:        existing.Side,
+		Quantity:    remaining,
+		EntryPrice:  existing.EntryPrice,
+		Leverage:    existing.Leverage.Float64,
+		Confidence:  existing.Confidence.Float64,
+		RiskUSD:     existing.RiskUsd.Float64,
+		UpdatedAtMs: time.Now().UTC().UnixMilli(),
+		Exchange:    existing.ExchangeProvider,
+	})
+	if summary != nil {
+		s.appendRecentTrade(ctx, modelID, *summary)
+	}
+	return nil
+}
+
+func (s *Service) insertTrade(ctx context.Context, pos *model.Positions, modelID, symbol string, closePrice, qty float64, result *tradePnL, closeTime time.Time, event managerpkg.PositionEvent) (*tradeCacheEntry, error) {
 	if s == nil || s.tradesModel == nil || pos == nil {
 		return nil, nil
 	}
@@ -753,13 +1172,29 @@ func (s *Service) insertTrade(ctx context.Context, pos *model.Positions, modelID
 	}
 	exitHuman := sql.NullString{String: closeTime.UTC().Format(time.RFC3339), Valid: true}
 	confidence := toNullFloat(float64(event.Decision.Confidence), event.Decision.Confidence > 0)
+	var gross, net, entryFee, exitFee, totalFee sql.NullFloat64
+	if result != nil {
+		gross = sql.NullFloat64{Float64: result.Gross, Valid: true}
+		net = sql.NullFloat64{Float64: result.Net, Valid: true}
+		entryFee = sql.NullFloat64{Float64: result.EntryFee, Valid: true}
+		exitFee = sql.NullFloat64{Float64: result.ExitFee, Valid: true}
+		totalFee = sql.NullFloat64{Float64: result.EntryFee + result.ExitFee, Valid: true}
+	}
+	tradeType := "close"
+	if event.Event == managerpkg.PositionEventReduce {
+		tradeType = "partial_close"
+	}
+	if event.Reconciled {
+		// Synthetic close for a position the exchange no longer reports.
+		tradeType = "reconcile"
+	}
 	trade := &model.Trades{
 		Id:                     buildTradeID(modelID, symbol, closeTime),
 		ModelId:                modelID,
 		ExchangeProvider:       pos.ExchangeProvider,
 		Symbol:                 symbol,
 		Side:                   pos.Side,
-		TradeType:              sql.NullString{String: "close", Valid: true},
+		TradeType:              sql.NullString{String: tradeType, Valid: true},
 		Quantity:               toNullFloat(tradeQty, tradeQty > 0),
 		Leverage:               pos.Leverage,
 		Confidence:             confidence,
@@ -770,17 +1205,17 @@ func (s *Service) insertTrade(ctx context.Context, pos *model.Positions, modelID
 		EntryTid:               sql.NullInt64{},
 		EntryOid:               pos.EntryOid,
 		EntryCrossed:           false,
-		EntryCommissionDollars: pos.Commission,
+		EntryCommissionDollars: entryFee,
 		ExitPrice:              toNullFloat(closePrice, closePrice > 0),
 		ExitTsMs:               sql.NullInt64{Int64: closeTime.UTC().UnixMilli(), Valid: true},
 		ExitHumanTime:          exitHuman,
 		ExitSz:                 toNullFloat(tradeQty, tradeQty > 0),
-		ExitCommissionDollars:  sql.NullFloat64{},
-		ExitClosedPnl:          pnl,
+		ExitCommissionDollars:  exitFee,
+		ExitClosedPnl:          gross,
 		ExitPlan:               pos.ExitPlan,
-		RealizedGrossPnl:       pnl,
-		RealizedNetPnl:         pnl,
-		TotalCommissionDollars: pos.Commission,
+		RealizedGrossPnl:       gross,
+		RealizedNetPnl:         net,
+		TotalCommissionDollars: totalFee,
 	}
 	_, err := s.tradesModel.Insert(ctx, trade)
 	if isUniqueViolation(err) {
@@ -796,7 +1231,7 @@ func (s *Service) insertTrade(ctx context.Context, pos *model.Positions, modelID
 		Quantity:     tradeQty,
 		EntryPrice:   pos.EntryPrice,
 		ExitPrice:    closePrice,
-		RealizedPnL:  pnl.Float64,
+		RealizedPnL:  net.Float64,
 		Confidence:   float64(event.Decision.Confidence),
 		ClosedAtMs:   closeTime.UTC().UnixMilli(),
 		Exchange:     traderExchange(event),
@@ -807,6 +1242,15 @@ func (s *Service) insertTrade(ctx context.Context, pos *model.Positions, modelID
 	return summary, nil
 }
 
+// activeLeverage prefers the leverage confirmed on the exchange over the
+// decision's requested value.
+func activeLeverage(event managerpkg.PositionEvent) int {
+	if event.Leverage > 0 {
+		return event.Leverage
+	}
+	return event.Decision.Leverage
+}
+
 func normalizedModelID(event managerpkg.PositionEvent) string {
 	if strings.TrimSpace(event.TraderID) != "" {
 		return event.TraderID
@@ -860,6 +1304,56 @@ func effectiveQuantity(event managerpkg.PositionEvent, price float64) float64 {
 	return 0
 }
 
+// tradePnL splits a closed trade's result into gross price PnL and net PnL
+// after commissions and funding.
+type tradePnL struct {
+	Gross    float64
+	Net      float64
+	EntryFee float64
+	ExitFee  float64
+}
+
+// computeTradePnL prices a (possibly partial) close of qty against a position
+// opened with openQty. The entry commission is pro-rated to the closed share;
+// exitFee is the commission of the close itself.
+func computeTradePnL(side string, entryPx, exitPx, qty, openQty, entryFee, exitFee, fundingUSD float64) tradePnL {
+	sign := 1.0
+	if strings.EqualFold(side, "short") {
+		sign = -1.0
+	}
+	if openQty > 0 && qty < openQty {
+		entryFee *= qty / openQty
+	}
+	gross := sign * (exitPx - entryPx) * qty
+	return tradePnL{
+		Gross:    gross,
+		Net:      gross - entryFee - exitFee - fundingUSD,
+		EntryFee: entryFee,
+		ExitFee:  exitFee,
+	}
+}
+
+// fillFee returns the commission on an event's fill of qty at price: the fee
+// the venue reported when the event carries one, else an estimate at the
+// maker rate for post_only fills and the taker rate otherwise.
+func (s *Service) fillFee(event managerpkg.PositionEvent, price, qty float64) float64 {
+	if event.FeeUSD != 0 {
+		return event.FeeUSD
+	}
+	feeBps := s.takerFeeBps
+	if event.Maker {
+		feeBps = s.makerFeeBps
+	}
+	return estimateFee(price, qty, feeBps)
+}
+
+func estimateFee(price, qty, feeBps float64) float64 {
+	if price <= 0 || qty <= 0 || feeBps <= 0 {
+		return 0
+	}
+	return math.Abs(price*qty) * feeBps / 10_000
+}
+
 func extractFill(resp *exchange.OrderResponse) (price float64, qty float64, ok bool) {
 	if resp == nil {
 		return 0, 0, false
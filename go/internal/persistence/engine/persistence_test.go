@@ -0,0 +1,231 @@
+package engine
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/journal"
+	"nof0-api/pkg/llm"
+	managerpkg "nof0-api/pkg/manager"
+)
+
+func filledResponse(avgPx, totalSz string) *exchange.OrderResponse {
+	return &exchange.OrderResponse{
+		Status: "ok",
+		Response: exchange.OrderResponseData{
+			Type: "order",
+			Data: exchange.OrderResponseDataDetail{
+				Statuses: []exchange.OrderStatusResponse{
+					{Filled: &exchange.FilledOrder{AvgPx: avgPx, TotalSz: totalSz, Oid: 42}},
+				},
+			},
+		},
+	}
+}
+
+func TestComputeTradePnLFromFilledResponse(t *testing.T) {
+	px, qty, ok := extractFill(filledResponse("101.5", "0.5"))
+	require.True(t, ok)
+	require.InDelta(t, 101.5, px, 1e-9)
+	require.InDelta(t, 0.5, qty, 1e-9)
+
+	// Long 1.0 @ 100 with a 0.045 entry fee; half closed @ 101.5.
+	entryFee := estimateFee(100, 1, defaultTakerFeeBps)
+	pnl := computeTradePnL("long", 100, px, qty, 1, entryFee, estimateFee(px, qty, defaultTakerFeeBps), 0)
+
+	assert.InDelta(t, 0.75, pnl.Gross, 1e-9)
+	assert.InDelta(t, 0.0225, pnl.EntryFee, 1e-9)
+	assert.InDelta(t, 0.0228375, pnl.ExitFee, 1e-9)
+	assert.InDelta(t, 0.75-0.0225-0.0228375, pnl.Net, 1e-9)
+}
+
+func TestComputeTradePnLShortDeductsFunding(t *testing.T) {
+	px, qty, ok := extractFill(filledResponse("95", "2"))
+	require.True(t, ok)
+
+	pnl := computeTradePnL("short", 100, px, qty, 2, 0.09, estimateFee(px, qty, defaultTakerFeeBps), 1.5)
+
+	assert.InDelta(t, 10, pnl.Gross, 1e-9)
+	assert.InDelta(t, 0.09, pnl.EntryFee, 1e-9)
+	assert.InDelta(t, 0.0855, pnl.ExitFee, 1e-9)
+	assert.InDelta(t, 10-0.09-0.0855-1.5, pnl.Net, 1e-9)
+}
+
+func TestFillFeePrefersVenueFeeThenMakerRate(t *testing.T) {
+	zero := 0.0
+	s := NewService(Config{SQLConn: newMemConn(), TakerFeeBps: &zero}).(*Service)
+	assert.Zero(t, s.fillFee(managerpkg.PositionEvent{}, 100, 1), "a configured zero taker fee is kept")
+	assert.InDelta(t, 0.015, s.fillFee(managerpkg.PositionEvent{Maker: true}, 100, 1), 1e-12, "post_only fills use the maker rate")
+	assert.InDelta(t, -0.002, s.fillFee(managerpkg.PositionEvent{Maker: true, FeeUSD: -0.002}, 100, 1), 1e-12, "a reported fee, rebates included, wins")
+
+	s = NewService(Config{SQLConn: newMemConn()}).(*Service)
+	assert.InDelta(t, 0.045, s.fillFee(managerpkg.PositionEvent{}, 100, 1), 1e-12, "taker default")
+}
+
+func TestExtractFillIgnoresRestingOrders(t *testing.T) {
+	resp := &exchange.OrderResponse{
+		Status: "ok",
+		Response: exchange.OrderResponseData{
+			Data: exchange.OrderResponseDataDetail{
+				Statuses: []exchange.OrderStatusResponse{{Resting: &exchange.RestingOrder{Oid: 7}}},
+			},
+		},
+	}
+	_, _, ok := extractFill(resp)
+	assert.False(t, ok)
+}
+
+func TestCycleConversationLinksPromptToCycle(t *testing.T) {
+	executedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	record := managerpkg.DecisionCycleRecord{
+		TraderID: "t1",
+		Cycle: &journal.CycleRecord{
+			TraderID:      "t1",
+			CycleID:       "t1-1740830400000000000",
+			CoTTrace:      "flat market",
+			DecisionsJSON: `[{"action":"hold"}]`,
+		},
+		Prompt: "## Account\nequity 1000",
+		Usage:  llm.Usage{PromptTokens: 120, CompletionTokens: 30, TotalTokens: 150},
+	}
+
+	conv, ok := cycleConversation("t1", record, executedAt)
+	require.True(t, ok)
+	assert.Equal(t, "t1-1740830400000000000", conv.CycleID)
+	assert.Equal(t, "## Account\nequity 1000", conv.Prompt)
+	assert.Equal(t, "flat market\n\n[{\"action\":\"hold\"}]", conv.Response)
+	assert.Equal(t, "decision_cycle", conv.Topic)
+	assert.Equal(t, 150, conv.TotalTokens)
+	assert.Equal(t, executedAt, conv.Timestamp)
+
+	// Without a persisted prompt the cycle keeps only its digest.
+	record.Prompt = ""
+	_, ok = cycleConversation("t1", record, executedAt)
+	assert.False(t, ok)
+}
+
+var (
+	memInsert = regexp.MustCompile(`^INSERT INTO public\.(\w+) \(([^)]*)\) VALUES`)
+	memSelect = regexp.MustCompile(`^SELECT (.+) FROM public\.(\w+) WHERE (.+) ORDER BY (\w+) ASC$`)
+	memCond   = regexp.MustCompile(`^(\w+) = \$(\d+)$`)
+)
+
+// memConn is an in-memory sqlx.SqlConn for the single-table INSERT and
+// "SELECT ... WHERE col = $n [AND ...] ORDER BY col ASC" statements the
+// service issues. Other methods are not implemented.
+type memConn struct {
+	sqlx.SqlConn
+	tables map[string][]map[string]driver.Value
+}
+
+func newMemConn() *memConn {
+	return &memConn{tables: make(map[string][]map[string]driver.Value)}
+}
+
+func (c *memConn) TransactCtx(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	return fn(ctx, c)
+}
+
+func (c *memConn) ExecCtx(_ context.Context, query string, args ...any) (sql.Result, error) {
+	m := memInsert.FindStringSubmatch(query)
+	if m == nil {
+		return nil, fmt.Errorf("memConn: unsupported statement %q", query)
+	}
+	row := make(map[string]driver.Value)
+	for i, col := range strings.Split(m[2], ",") {
+		v, err := driver.DefaultParameterConverter.ConvertValue(args[i])
+		if err != nil {
+			return nil, err
+		}
+		row[strings.TrimSpace(col)] = v
+	}
+	c.tables[m[1]] = append(c.tables[m[1]], row)
+	return nil, nil
+}
+
+func (c *memConn) QueryRowsCtx(_ context.Context, v any, query string, args ...any) error {
+	m := memSelect.FindStringSubmatch(query)
+	if m == nil {
+		return fmt.Errorf("memConn: unsupported query %q", query)
+	}
+	var rows []map[string]driver.Value
+next:
+	for _, row := range c.tables[m[2]] {
+		for _, cond := range strings.Split(m[3], " AND ") {
+			cm := memCond.FindStringSubmatch(strings.TrimSpace(cond))
+			if cm == nil {
+				return fmt.Errorf("memConn: unsupported condition %q", cond)
+			}
+			n, _ := strconv.Atoi(cm[2])
+			want, err := driver.DefaultParameterConverter.ConvertValue(args[n-1])
+			if err != nil {
+				return err
+			}
+			if !reflect.DeepEqual(row[cm[1]], want) {
+				continue next
+			}
+		}
+		rows = append(rows, row)
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i][m[4]].(int64) < rows[j][m[4]].(int64) })
+
+	dest := reflect.ValueOf(v).Elem()
+	for _, row := range rows {
+		elem := reflect.New(dest.Type().Elem()).Elem()
+		for i := 0; i < elem.NumField(); i++ {
+			field := elem.Field(i)
+			val := row[elem.Type().Field(i).Tag.Get("db")]
+			if scanner, ok := field.Addr().Interface().(sql.Scanner); ok {
+				if err := scanner.Scan(val); err != nil {
+					return err
+				}
+			} else if val != nil {
+				field.Set(reflect.ValueOf(val).Convert(field.Type()))
+			}
+		}
+		dest.Set(reflect.Append(dest, elem))
+	}
+	return nil
+}
+
+func TestCycleCandidatesReadBackByCycleID(t *testing.T) {
+	ctx := context.Background()
+	s := &Service{sqlConn: newMemConn()}
+	executedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	cycle := &journal.CycleRecord{
+		TraderID:    "t1",
+		CycleID:     "t1-1",
+		CycleNumber: 7,
+		CandidateSet: []journal.CandidateScore{
+			{Symbol: "BTC", Score: 0.92, Sources: []string{"momentum", "volume"}},
+			{Symbol: "ETH", Score: 0.41},
+			{Symbol: "SOL", Score: 0.4, Sources: []string{"open_position"}},
+		},
+	}
+	require.NoError(t, s.insertCycleCandidates(ctx, "t1", cycle, executedAt))
+	// Another cycle stored with the same timestamp must not leak in.
+	other := &journal.CycleRecord{TraderID: "t2", CycleID: "t2-1", CandidateSet: []journal.CandidateScore{{Symbol: "DOGE", Score: 0.1}}}
+	require.NoError(t, s.insertCycleCandidates(ctx, "t2", other, executedAt))
+
+	got, err := s.CycleCandidates(ctx, "t1-1")
+	require.NoError(t, err)
+	assert.Equal(t, cycle.CandidateSet, got, "candidates should read back in rank order with scores intact")
+
+	none, err := s.CycleCandidates(ctx, "t1-2")
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
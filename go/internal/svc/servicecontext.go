@@ -232,6 +232,14 @@ func NewServiceContext(c config.Config, mainConfigPath string) *ServiceContext {
 			if !ok {
 				log.Fatalf("manager trader %s references unknown exchange provider %s", trader.ID, trader.ExchangeProvider)
 			}
+			if trader.VaultAddress != "" {
+				// Each vault trader signs through its own provider instance.
+				vaultProvider, err := svc.ExchangeConfig.BuildVaultProvider(trader.ExchangeProvider, trader.VaultAddress)
+				if err != nil {
+					log.Fatalf("manager trader %s vault provider: %v", trader.ID, err)
+				}
+				exProvider = vaultProvider
+			}
 			svc.ManagerTraderExchange[trader.ID] = exProvider
 
 			mktProvider, ok := svc.MarketProviders[trader.MarketProvider]
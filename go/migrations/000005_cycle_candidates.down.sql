@@ -0,0 +1,4 @@
+-- Rollback cycle candidate persistence
+
+DROP INDEX IF EXISTS idx_cycle_candidates_model_executed_at;
+DROP TABLE IF EXISTS cycle_candidates;
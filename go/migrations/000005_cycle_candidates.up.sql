@@ -0,0 +1,15 @@
+-- Candidate universe offered to the model per decision cycle
+
+CREATE TABLE IF NOT EXISTS cycle_candidates (
+    id BIGSERIAL PRIMARY KEY,
+    model_id TEXT NOT NULL,
+    cycle_number INT,
+    executed_at TIMESTAMPTZ NOT NULL,
+    rank INT NOT NULL,
+    symbol TEXT NOT NULL,
+    score DOUBLE PRECISION,
+    sources TEXT[]
+);
+
+CREATE INDEX IF NOT EXISTS idx_cycle_candidates_model_executed_at
+    ON cycle_candidates(model_id, executed_at DESC);
@@ -0,0 +1,4 @@
+-- Rollback leverage change audit
+
+DROP INDEX IF EXISTS idx_leverage_changes_model_symbol_changed_at;
+DROP TABLE IF EXISTS leverage_changes;
@@ -0,0 +1,14 @@
+-- Audit trail of leverage/margin-mode updates sent to the exchange
+
+CREATE TABLE IF NOT EXISTS leverage_changes (
+    id BIGSERIAL PRIMARY KEY,
+    model_id TEXT NOT NULL,
+    symbol TEXT NOT NULL,
+    asset INT NOT NULL,
+    margin_mode TEXT NOT NULL,
+    leverage INT NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_leverage_changes_model_symbol_changed_at
+    ON leverage_changes(model_id, symbol, changed_at DESC);
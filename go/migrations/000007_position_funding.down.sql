@@ -0,0 +1,4 @@
+-- Rollback position funding accrual
+
+ALTER TABLE positions DROP COLUMN IF EXISTS funding_accrued_through;
+ALTER TABLE positions DROP COLUMN IF EXISTS funding_accrued;
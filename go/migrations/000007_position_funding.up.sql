@@ -0,0 +1,4 @@
+-- Funding accrued on open positions (positive = paid)
+
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS funding_accrued DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS funding_accrued_through TIMESTAMPTZ;
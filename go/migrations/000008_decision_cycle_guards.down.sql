@@ -0,0 +1,3 @@
+-- Rollback decision cycle guard configuration
+
+ALTER TABLE decision_cycles DROP COLUMN IF EXISTS guards;
@@ -0,0 +1,3 @@
+-- Effective guard configuration recorded with each decision cycle
+
+ALTER TABLE decision_cycles ADD COLUMN IF NOT EXISTS guards JSONB;
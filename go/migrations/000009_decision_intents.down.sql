@@ -0,0 +1,6 @@
+-- Rollback decision intent and execution persistence
+
+DROP INDEX IF EXISTS idx_decision_executions_model_executed_at;
+DROP TABLE IF EXISTS decision_executions;
+DROP INDEX IF EXISTS idx_decision_intents_model_executed_at;
+DROP TABLE IF EXISTS decision_intents;
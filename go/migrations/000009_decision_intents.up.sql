@@ -0,0 +1,41 @@
+-- Model decision intent and manager execution outcome per decision cycle
+
+CREATE TABLE IF NOT EXISTS decision_intents (
+    id BIGSERIAL PRIMARY KEY,
+    model_id TEXT NOT NULL,
+    cycle_number INT,
+    executed_at TIMESTAMPTZ NOT NULL,
+    intent_index INT NOT NULL,
+    symbol TEXT NOT NULL,
+    action TEXT NOT NULL,
+    leverage INT,
+    position_size_usd DOUBLE PRECISION,
+    entry_price DOUBLE PRECISION,
+    stop_loss DOUBLE PRECISION,
+    take_profit DOUBLE PRECISION,
+    confidence INT
+);
+
+CREATE INDEX IF NOT EXISTS idx_decision_intents_model_executed_at
+    ON decision_intents(model_id, executed_at DESC);
+
+CREATE TABLE IF NOT EXISTS decision_executions (
+    id BIGSERIAL PRIMARY KEY,
+    model_id TEXT NOT NULL,
+    cycle_number INT,
+    executed_at TIMESTAMPTZ NOT NULL,
+    intent_index INT NOT NULL,
+    symbol TEXT NOT NULL,
+    action TEXT NOT NULL,
+    status TEXT NOT NULL,
+    reason TEXT,
+    price DOUBLE PRECISION,
+    size DOUBLE PRECISION,
+    notional_usd DOUBLE PRECISION,
+    leverage INT,
+    order_style TEXT,
+    reduce_only BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_decision_executions_model_executed_at
+    ON decision_executions(model_id, executed_at DESC);
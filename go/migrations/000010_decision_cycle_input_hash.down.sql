@@ -0,0 +1,4 @@
+-- Rollback decision cycle input hash
+
+DROP INDEX IF EXISTS idx_decision_cycles_model_input_hash;
+ALTER TABLE decision_cycles DROP COLUMN IF EXISTS input_hash;
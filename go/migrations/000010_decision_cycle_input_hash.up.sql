@@ -0,0 +1,6 @@
+-- Hash of the material decision inputs recorded with each decision cycle
+
+ALTER TABLE decision_cycles ADD COLUMN IF NOT EXISTS input_hash TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_decision_cycles_model_input_hash
+    ON decision_cycles(model_id, input_hash);
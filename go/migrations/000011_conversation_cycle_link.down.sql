@@ -0,0 +1,6 @@
+-- Rollback conversation cycle link
+
+DROP INDEX IF EXISTS idx_conversations_cycle_id;
+DROP INDEX IF EXISTS idx_decision_cycles_cycle_id;
+ALTER TABLE conversations DROP COLUMN IF EXISTS cycle_id;
+ALTER TABLE decision_cycles DROP COLUMN IF EXISTS cycle_id;
@@ -0,0 +1,9 @@
+-- Link persisted prompts (conversations) to the decision cycle that produced them
+
+ALTER TABLE decision_cycles ADD COLUMN IF NOT EXISTS cycle_id TEXT;
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS cycle_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_decision_cycles_cycle_id
+    ON decision_cycles(cycle_id);
+CREATE INDEX IF NOT EXISTS idx_conversations_cycle_id
+    ON conversations(cycle_id);
@@ -0,0 +1,6 @@
+-- Rollback decision intent cycle id
+
+DROP INDEX IF EXISTS idx_decision_executions_cycle_id;
+DROP INDEX IF EXISTS idx_decision_intents_cycle_id;
+ALTER TABLE decision_executions DROP COLUMN IF EXISTS cycle_id;
+ALTER TABLE decision_intents DROP COLUMN IF EXISTS cycle_id;
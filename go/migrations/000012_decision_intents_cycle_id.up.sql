@@ -0,0 +1,9 @@
+-- Key decision intents and executions by the decision cycle id
+
+ALTER TABLE decision_intents ADD COLUMN IF NOT EXISTS cycle_id TEXT;
+ALTER TABLE decision_executions ADD COLUMN IF NOT EXISTS cycle_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_decision_intents_cycle_id
+    ON decision_intents(cycle_id, intent_index);
+CREATE INDEX IF NOT EXISTS idx_decision_executions_cycle_id
+    ON decision_executions(cycle_id, intent_index);
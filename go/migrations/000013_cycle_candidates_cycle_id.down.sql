@@ -0,0 +1,4 @@
+-- Rollback cycle candidate cycle id
+
+DROP INDEX IF EXISTS idx_cycle_candidates_cycle_id;
+ALTER TABLE cycle_candidates DROP COLUMN IF EXISTS cycle_id;
@@ -0,0 +1,6 @@
+-- Key cycle candidates by the decision cycle id
+
+ALTER TABLE cycle_candidates ADD COLUMN IF NOT EXISTS cycle_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_cycle_candidates_cycle_id
+    ON cycle_candidates(cycle_id, rank);
@@ -185,7 +185,7 @@ func sharpe(equity []float64) float64 {
 	return m / sd * math.Sqrt(float64(len(rets)))
 }
 
-func writeReport(path string, r *Result) error {
+func writeReport(path string, r any) error {
 	b, err := json.MarshalIndent(r, "", "  ")
 	if err != nil {
 		return err
@@ -0,0 +1,141 @@
+package backtest
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"nof0-api/pkg/market"
+)
+
+// PriceTick is one row of the price_ticks table.
+type PriceTick struct {
+	Symbol string
+	Price  float64
+	TsMs   int64
+}
+
+// StepFeeder yields time-ordered market snapshots for every symbol trading
+// at each step.
+type StepFeeder interface {
+	NextStep(ctx context.Context) (time.Time, map[string]*market.Snapshot, bool, error)
+}
+
+// LoadPriceTicksCSVFile reads ticks from a CSV file; see LoadPriceTicksCSV.
+func LoadPriceTicksCSVFile(path string) ([]PriceTick, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadPriceTicksCSV(f)
+}
+
+// LoadPriceTicksCSV reads ticks from CSV with a header naming the symbol,
+// price and ts_ms columns, as produced by exporting price_ticks. Other
+// columns, such as provider, are ignored.
+func LoadPriceTicksCSV(r io.Reader) ([]PriceTick, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("backtest: read ticks csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	cols := map[string]int{}
+	for i, name := range records[0] {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"symbol", "price", "ts_ms"} {
+		if _, ok := cols[name]; !ok {
+			return nil, fmt.Errorf("backtest: ticks csv missing %q column", name)
+		}
+	}
+	ticks := make([]PriceTick, 0, len(records)-1)
+	for line, rec := range records[1:] {
+		price, err := strconv.ParseFloat(strings.TrimSpace(rec[cols["price"]]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("backtest: ticks csv line %d: invalid price: %w", line+2, err)
+		}
+		ts, err := strconv.ParseInt(strings.TrimSpace(rec[cols["ts_ms"]]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("backtest: ticks csv line %d: invalid ts_ms: %w", line+2, err)
+		}
+		ticks = append(ticks, PriceTick{
+			Symbol: strings.ToUpper(strings.TrimSpace(rec[cols["symbol"]])),
+			Price:  price,
+			TsMs:   ts,
+		})
+	}
+	return ticks, nil
+}
+
+// TickFeeder groups ticks sharing a timestamp into one step. Each snapshot
+// carries the 1h and 4h changes against the symbol's latest earlier tick at
+// or before that offset.
+type TickFeeder struct {
+	steps   []int64
+	bySteps map[int64][]PriceTick
+	history map[string][]PriceTick
+	idx     int
+}
+
+// NewTickFeeder orders ticks by time; ticks with a non-positive price are dropped.
+func NewTickFeeder(ticks []PriceTick) *TickFeeder {
+	f := &TickFeeder{bySteps: make(map[int64][]PriceTick), history: make(map[string][]PriceTick)}
+	for _, t := range ticks {
+		if t.Price <= 0 || t.Symbol == "" {
+			continue
+		}
+		if _, ok := f.bySteps[t.TsMs]; !ok {
+			f.steps = append(f.steps, t.TsMs)
+		}
+		f.bySteps[t.TsMs] = append(f.bySteps[t.TsMs], t)
+	}
+	sort.Slice(f.steps, func(i, j int) bool { return f.steps[i] < f.steps[j] })
+	return f
+}
+
+// NextStep returns the snapshots of the next timestamp.
+func (f *TickFeeder) NextStep(ctx context.Context) (time.Time, map[string]*market.Snapshot, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return time.Time{}, nil, false, err
+	}
+	if f.idx >= len(f.steps) {
+		return time.Time{}, nil, false, nil
+	}
+	ts := f.steps[f.idx]
+	f.idx++
+	snaps := make(map[string]*market.Snapshot, len(f.bySteps[ts]))
+	for _, t := range f.bySteps[ts] {
+		hist := f.history[t.Symbol]
+		snaps[t.Symbol] = &market.Snapshot{
+			Symbol: t.Symbol,
+			Price:  market.PriceInfo{Last: t.Price},
+			Change: market.ChangeInfo{
+				OneHour:  changeSince(hist, t, time.Hour),
+				FourHour: changeSince(hist, t, 4*time.Hour),
+			},
+		}
+		f.history[t.Symbol] = append(hist, t)
+	}
+	return time.UnixMilli(ts).UTC(), snaps, true, nil
+}
+
+// changeSince is the fractional change of t against the latest tick in hist
+// at least window older, or 0 without one.
+func changeSince(hist []PriceTick, t PriceTick, window time.Duration) float64 {
+	cutoff := t.TsMs - window.Milliseconds()
+	for i := len(hist) - 1; i >= 0; i-- {
+		if hist[i].TsMs <= cutoff {
+			return (t.Price - hist[i].Price) / hist[i].Price
+		}
+	}
+	return 0
+}
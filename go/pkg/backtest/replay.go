@@ -0,0 +1,375 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+	"nof0-api/pkg/manager"
+	"nof0-api/pkg/market"
+)
+
+// ExecutorEngine replays a StepFeeder through an executor.Executor, asking it
+// for decisions at each step and filling them on the sim exchange at the
+// step's prices. No live exchange is touched; the executor's LLM still is.
+type ExecutorEngine struct {
+	Executor executorpkg.Executor
+	Feeder   StepFeeder
+	// Exch fills decisions; nil uses a simulator funded with InitialEquity.
+	Exch *sim.Provider
+
+	InitialEquity      float64 // defaults to 100000 if zero
+	FeeBps             float64 // per-fill fee in basis points of notional
+	MajorCoinLeverage  int     // BTC/ETH leverage cap passed to the executor
+	AltcoinLeverage    int     // leverage cap for other symbols
+	MaxPositionSizeUSD float64 // caps each open's notional (0 disables)
+	// DecisionEvery consults the executor every N steps (default 1); prices
+	// still advance on the others.
+	DecisionEvery int
+
+	// Optional: write JSON report to this path
+	OutputPath string
+}
+
+// EquityPoint is the account equity after a replay step.
+type EquityPoint struct {
+	Time   time.Time `json:"time"`
+	Equity float64   `json:"equity"`
+}
+
+// ReplayFill records one simulated fill of a decision.
+type ReplayFill struct {
+	Time     time.Time `json:"time"`
+	Symbol   string    `json:"symbol"`
+	Action   string    `json:"action"`
+	Price    float64   `json:"price"`
+	Qty      float64   `json:"qty"`
+	Fee      float64   `json:"fee"`
+	Realized float64   `json:"realized"` // realized PnL of a close, before fees
+}
+
+// ReplayResult summarizes an ExecutorEngine run.
+type ReplayResult struct {
+	Steps          int
+	Decisions      int
+	DecisionErrors int
+	Opens          int
+	Closes         int
+	Wins           int
+	WinRate        float64
+	RealizedPNL    float64
+	Fees           float64
+	InitialEquity  float64
+	FinalEquity    float64
+	TotalPNL       float64
+	MaxDDPct       float64
+	Sharpe         float64
+	EquityCurve    []EquityPoint
+	Fills          []ReplayFill
+}
+
+// AnalyticsSnapshot expresses the result in the metrics the manager persists
+// to model_analytics, so replays compare directly with live traders.
+func (r *ReplayResult) AnalyticsSnapshot(traderID string) manager.AnalyticsSnapshot {
+	snap := manager.AnalyticsSnapshot{
+		TraderID:       traderID,
+		TotalPnLUSD:    r.TotalPNL,
+		SharpeRatio:    r.Sharpe,
+		WinRate:        r.WinRate,
+		TotalTrades:    r.Closes,
+		MaxDrawdownPct: r.MaxDDPct,
+	}
+	if r.InitialEquity > 0 {
+		snap.TotalPnLPct = 100 * r.TotalPNL / r.InitialEquity
+	}
+	if n := len(r.EquityCurve); n > 0 {
+		snap.UpdatedAt = r.EquityCurve[n-1].Time
+	}
+	return snap
+}
+
+// Run replays every step of the feeder.
+func (e *ExecutorEngine) Run(ctx context.Context) (*ReplayResult, error) {
+	if e.Executor == nil || e.Feeder == nil {
+		return nil, fmt.Errorf("backtest: executor engine not fully configured")
+	}
+	eq0 := e.InitialEquity
+	if eq0 <= 0 {
+		eq0 = 100000
+	}
+	exch := e.Exch
+	if exch == nil {
+		exch = sim.NewWithEquity(eq0)
+	}
+	every := e.DecisionEvery
+	if every <= 0 {
+		every = 1
+	}
+	res := &ReplayResult{InitialEquity: eq0}
+	for {
+		now, snaps, ok, err := e.Feeder.NextStep(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		res.Steps++
+		for sym, snap := range snaps {
+			if err := exch.SetMarkPrice(ctx, sym, snap.Price.Last); err != nil {
+				return nil, err
+			}
+		}
+		if (res.Steps-1)%every == 0 {
+			if err := e.decide(ctx, exch, now, snaps, res); err != nil {
+				return nil, err
+			}
+		}
+		equity, err := exch.GetAccountValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res.EquityCurve = append(res.EquityCurve, EquityPoint{Time: now, Equity: equity - res.Fees})
+	}
+	res.FinalEquity = eq0
+	if n := len(res.EquityCurve); n > 0 {
+		res.FinalEquity = res.EquityCurve[n-1].Equity
+	}
+	res.TotalPNL = res.FinalEquity - eq0
+	if res.Closes > 0 {
+		res.WinRate = float64(res.Wins) / float64(res.Closes)
+	}
+	curve := make([]float64, 0, len(res.EquityCurve)+1)
+	curve = append(curve, eq0)
+	for _, p := range res.EquityCurve {
+		curve = append(curve, p.Equity)
+	}
+	res.MaxDDPct = maxDrawdownPct(curve)
+	res.Sharpe = sharpe(curve[1:])
+
+	if e.OutputPath != "" {
+		if err := writeReport(e.OutputPath, res); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// decide asks the executor for one step's decisions and fills them, closes first.
+func (e *ExecutorEngine) decide(ctx context.Context, exch *sim.Provider, now time.Time, snaps map[string]*market.Snapshot, res *ReplayResult) error {
+	input, err := e.buildContext(ctx, exch, now, snaps, res)
+	if err != nil {
+		return err
+	}
+	res.Decisions++
+	out, err := e.Executor.GetFullDecision(input)
+	if err != nil || out == nil {
+		// Like the manager, never execute a bundle that failed validation.
+		res.DecisionErrors++
+		return nil
+	}
+	decisions := append([]executorpkg.Decision(nil), out.Decisions...)
+	sort.SliceStable(decisions, func(i, j int) bool {
+		return isClose(decisions[i].Action) && !isClose(decisions[j].Action)
+	})
+	for _, d := range decisions {
+		snap := snaps[strings.ToUpper(strings.TrimSpace(d.Symbol))]
+		if snap == nil || snap.Price.Last <= 0 {
+			continue
+		}
+		switch d.Action {
+		case "close_long", "close_short":
+			if err := e.fillClose(ctx, exch, now, d, res); err != nil {
+				return err
+			}
+		case "open_long", "open_short":
+			if err := e.fillOpen(ctx, exch, now, d, snap.Price.Last, res); err != nil {
+				return err
+			}
+		}
+	}
+	e.Executor.UpdatePerformance(&executorpkg.PerformanceView{
+		SharpeRatio: sharpeOfCurve(res.EquityCurve),
+		WinRate:     winRate(res),
+		TotalTrades: res.Closes,
+		UpdatedAt:   now,
+	})
+	return nil
+}
+
+func (e *ExecutorEngine) buildContext(ctx context.Context, exch *sim.Provider, now time.Time, snaps map[string]*market.Snapshot, res *ReplayResult) (*executorpkg.Context, error) {
+	acct, err := exch.GetAccountState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	equity := parseFloat(acct.MarginSummary.AccountValue) - res.Fees
+	marginUsed := parseFloat(acct.MarginSummary.TotalMarginUsed)
+	account := executorpkg.AccountInfo{
+		TotalEquity:      equity,
+		AvailableBalance: equity - marginUsed,
+		TotalPnL:         equity - res.InitialEquity,
+		MarginUsed:       marginUsed,
+		PositionCount:    len(acct.AssetPositions),
+	}
+	if equity > 0 {
+		account.MarginUsedPct = 100 * marginUsed / equity
+	}
+	if res.InitialEquity > 0 {
+		account.TotalPnLPct = 100 * account.TotalPnL / res.InitialEquity
+	}
+	positions := make([]executorpkg.PositionInfo, 0, len(acct.AssetPositions))
+	for _, p := range acct.AssetPositions {
+		qty := parseFloat(p.Szi)
+		side := "long"
+		if qty < 0 {
+			side, qty = "short", -qty
+		}
+		pi := executorpkg.PositionInfo{
+			Symbol:        p.Coin,
+			Side:          side,
+			EntryPrice:    parsePtr(p.EntryPx),
+			Quantity:      qty,
+			Leverage:      p.Leverage.Value,
+			UnrealizedPnL: parseFloat(p.UnrealizedPnl),
+			UpdateTime:    now.UnixMilli(),
+		}
+		if s := snaps[p.Coin]; s != nil && pi.EntryPrice > 0 {
+			pi.MarkPrice = s.Price.Last
+			pi.UnrealizedPnLPct = 100 * (pi.MarkPrice - pi.EntryPrice) / pi.EntryPrice
+		}
+		positions = append(positions, pi)
+	}
+	symbols := make([]string, 0, len(snaps))
+	for sym := range snaps {
+		symbols = append(symbols, sym)
+	}
+	sort.Strings(symbols)
+	candidates := make([]executorpkg.CandidateCoin, 0, len(symbols))
+	for _, sym := range symbols {
+		candidates = append(candidates, executorpkg.CandidateCoin{Symbol: sym, Sources: []string{"backtest"}, Score: math.Abs(snaps[sym].Change.OneHour)})
+	}
+	return &executorpkg.Context{
+		CurrentTime:        now.UTC().Format(time.RFC3339),
+		CallCount:          res.Decisions + 1,
+		Account:            account,
+		Positions:          positions,
+		CandidateCoins:     candidates,
+		MarketDataMap:      snaps,
+		MajorCoinLeverage:  e.MajorCoinLeverage,
+		AltcoinLeverage:    e.AltcoinLeverage,
+		MaxPositionSizeUSD: e.MaxPositionSizeUSD,
+	}, nil
+}
+
+func (e *ExecutorEngine) fillOpen(ctx context.Context, exch *sim.Provider, now time.Time, d executorpkg.Decision, price float64, res *ReplayResult) error {
+	notional := d.PositionSizeUSD
+	if e.MaxPositionSizeUSD > 0 && notional > e.MaxPositionSizeUSD {
+		notional = e.MaxPositionSizeUSD
+	}
+	if notional <= 0 {
+		return nil
+	}
+	asset, err := exch.GetAssetIndex(ctx, d.Symbol)
+	if err != nil {
+		return err
+	}
+	if d.Leverage > 0 {
+		if err := exch.UpdateLeverage(ctx, asset, true, d.Leverage); err != nil {
+			return err
+		}
+	}
+	qty := notional / price
+	order := exchange.Order{
+		Asset:     asset,
+		IsBuy:     d.Action == "open_long",
+		LimitPx:   strconv.FormatFloat(price, 'f', -1, 64),
+		Sz:        strconv.FormatFloat(qty, 'f', -1, 64),
+		OrderType: exchange.OrderType{Limit: &exchange.LimitOrderType{TIF: "Ioc"}},
+	}
+	if _, err := exch.PlaceOrder(ctx, order); err != nil {
+		return fmt.Errorf("backtest: open %s: %w", d.Symbol, err)
+	}
+	fee := notional * e.FeeBps / 10000
+	res.Fees += fee
+	res.Opens++
+	res.Fills = append(res.Fills, ReplayFill{Time: now, Symbol: d.Symbol, Action: d.Action, Price: price, Qty: qty, Fee: fee})
+	return nil
+}
+
+func (e *ExecutorEngine) fillClose(ctx context.Context, exch *sim.Provider, now time.Time, d executorpkg.Decision, res *ReplayResult) error {
+	positions, err := exch.GetPositions(ctx)
+	if err != nil {
+		return err
+	}
+	var held *exchange.Position
+	for i := range positions {
+		if strings.EqualFold(positions[i].Coin, d.Symbol) {
+			held = &positions[i]
+		}
+	}
+	if held == nil {
+		return nil
+	}
+	szi := parseFloat(held.Szi)
+	if (szi > 0) != (d.Action == "close_long") {
+		return nil // side mismatch; the manager rejects these too
+	}
+	entry := parsePtr(held.EntryPx)
+	resp, err := exch.ClosePosition(ctx, d.Symbol)
+	if err != nil {
+		return fmt.Errorf("backtest: close %s: %w", d.Symbol, err)
+	}
+	price := entry
+	if resp != nil && len(resp.Response.Data.Statuses) > 0 && resp.Response.Data.Statuses[0].Filled != nil {
+		price = parseFloat(resp.Response.Data.Statuses[0].Filled.AvgPx)
+	}
+	qty := math.Abs(szi)
+	realized := (price - entry) * szi
+	fee := price * qty * e.FeeBps / 10000
+	res.Fees += fee
+	res.RealizedPNL += realized
+	res.Closes++
+	if realized-fee > 0 {
+		res.Wins++
+	}
+	res.Fills = append(res.Fills, ReplayFill{Time: now, Symbol: d.Symbol, Action: d.Action, Price: price, Qty: qty, Fee: fee, Realized: realized})
+	return nil
+}
+
+func isClose(action string) bool {
+	return action == "close_long" || action == "close_short"
+}
+
+func winRate(res *ReplayResult) float64 {
+	if res.Closes == 0 {
+		return 0
+	}
+	return float64(res.Wins) / float64(res.Closes)
+}
+
+func sharpeOfCurve(points []EquityPoint) float64 {
+	curve := make([]float64, 0, len(points))
+	for _, p := range points {
+		curve = append(curve, p.Equity)
+	}
+	return sharpe(curve)
+}
+
+func parseFloat(s string) float64 {
+	v, _ := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return v
+}
+
+func parsePtr(s *string) float64 {
+	if s == nil {
+		return 0
+	}
+	return parseFloat(*s)
+}
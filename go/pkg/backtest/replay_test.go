@@ -0,0 +1,113 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// scriptedExecutor returns a fixed decision list per call.
+type scriptedExecutor struct {
+	steps  [][]executorpkg.Decision
+	errAt  map[int]bool
+	inputs []*executorpkg.Context
+	perf   *executorpkg.PerformanceView
+}
+
+func (s *scriptedExecutor) GetFullDecision(input *executorpkg.Context) (*executorpkg.FullDecision, error) {
+	call := len(s.inputs)
+	s.inputs = append(s.inputs, input)
+	if s.errAt[call] {
+		return &executorpkg.FullDecision{}, errors.New("invalid decision")
+	}
+	out := &executorpkg.FullDecision{}
+	if call < len(s.steps) {
+		out.Decisions = s.steps[call]
+	}
+	return out, nil
+}
+
+func (s *scriptedExecutor) UpdatePerformance(view *executorpkg.PerformanceView) { s.perf = view }
+
+func (s *scriptedExecutor) GetConfig() *executorpkg.Config { return &executorpkg.Config{} }
+
+const replayTicks = `provider,symbol,price,ts_ms
+hyperliquid,BTC,100,0
+hyperliquid,ETH,10,0
+hyperliquid,BTC,110,3600000
+hyperliquid,ETH,10,3600000
+hyperliquid,BTC,120,7200000
+hyperliquid,ETH,9,7200000
+hyperliquid,BTC,115,10800000
+hyperliquid,ETH,9,10800000
+`
+
+func TestExecutorEngineReplaysDecisions(t *testing.T) {
+	ticks, err := LoadPriceTicksCSV(strings.NewReader(replayTicks))
+	require.NoError(t, err)
+	exec := &scriptedExecutor{
+		steps: [][]executorpkg.Decision{
+			{{Symbol: "BTC", Action: "open_long", Leverage: 2, PositionSizeUSD: 1000}},
+			{{Symbol: "ETH", Action: "open_short", PositionSizeUSD: 5000}},
+			{{Symbol: "BTC", Action: "close_long"}, {Symbol: "ETH", Action: "close_short"}},
+		},
+		errAt: map[int]bool{3: true},
+	}
+	out := filepath.Join(t.TempDir(), "report.json")
+	e := &ExecutorEngine{
+		Executor:           exec,
+		Feeder:             NewTickFeeder(ticks),
+		InitialEquity:      10000,
+		MaxPositionSizeUSD: 2000,
+		MajorCoinLeverage:  5,
+		OutputPath:         out,
+	}
+
+	res, err := e.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 4, res.Steps)
+	assert.Equal(t, 4, res.Decisions)
+	assert.Equal(t, 1, res.DecisionErrors)
+	assert.Equal(t, 2, res.Opens)
+	assert.Equal(t, 2, res.Closes)
+	assert.Equal(t, 2, res.Wins)
+	assert.Equal(t, 1.0, res.WinRate)
+	require.Len(t, res.EquityCurve, 4)
+	assert.Equal(t, time.UnixMilli(3600000).UTC(), res.EquityCurve[1].Time)
+
+	// BTC: 10 @ 100 closed @ 120; ETH: capped to 2000 notional, 200 @ 10 closed @ 9.
+	assert.InDelta(t, 200+200, res.RealizedPNL, 1e-6)
+	assert.InDelta(t, 10400, res.FinalEquity, 1e-6)
+	assert.InDelta(t, 400, res.TotalPNL, 1e-6)
+	assert.Zero(t, res.MaxDDPct)
+	assert.Greater(t, res.Sharpe, 0.0)
+	assert.FileExists(t, out)
+
+	second := exec.inputs[1]
+	require.Len(t, second.Positions, 1)
+	assert.Equal(t, "long", second.Positions[0].Side)
+	assert.InDelta(t, 0.1, second.MarketDataMap["BTC"].Change.OneHour, 1e-9, "1h change against the previous hourly tick")
+	assert.Equal(t, 5, second.MajorCoinLeverage)
+	require.NotNil(t, exec.perf)
+	assert.Equal(t, 2, exec.perf.TotalTrades)
+
+	snap := res.AnalyticsSnapshot("replay")
+	assert.Equal(t, "replay", snap.TraderID)
+	assert.InDelta(t, 4.0, snap.TotalPnLPct, 1e-9)
+	assert.Equal(t, 2, snap.TotalTrades)
+	assert.Equal(t, res.Sharpe, snap.SharpeRatio)
+}
+
+func TestLoadPriceTicksCSVRequiresColumns(t *testing.T) {
+	_, err := LoadPriceTicksCSV(strings.NewReader("symbol,close\nBTC,100\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"price"`)
+}
@@ -0,0 +1,250 @@
+package backtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// HistoricalCycle is one recorded decision cycle: the exact Context the
+// incumbent model was prompted with and the decisions it returned.
+type HistoricalCycle struct {
+	Time      time.Time              `json:"time"`
+	Context   *executorpkg.Context   `json:"context"`
+	Decisions []executorpkg.Decision `json:"decisions"`
+}
+
+// LoadHistoricalCycles decodes a JSON array of cycles, oldest first.
+func LoadHistoricalCycles(r io.Reader) ([]HistoricalCycle, error) {
+	var cycles []HistoricalCycle
+	if err := json.NewDecoder(r).Decode(&cycles); err != nil {
+		return nil, fmt.Errorf("backtest: decode cycles: %w", err)
+	}
+	for i, c := range cycles {
+		if c.Context == nil {
+			return nil, fmt.Errorf("backtest: cycles[%d] has no context", i)
+		}
+	}
+	return cycles, nil
+}
+
+// ReplayDiff re-runs recorded cycles through a challenger executor and
+// compares its decisions with the recorded ones. Both decision streams are
+// filled on separate simulators at each cycle's prices to estimate the PnL
+// the challenger would have made instead.
+type ReplayDiff struct {
+	Challenger executorpkg.Executor
+	Cycles     []HistoricalCycle
+
+	InitialEquity      float64 // defaults to 100000 if zero
+	FeeBps             float64 // per-fill fee in basis points of notional
+	MaxPositionSizeUSD float64 // caps each open's notional (0 disables)
+
+	// Optional: write JSON report to this path
+	OutputPath string
+}
+
+// DecisionDiff compares the baseline and challenger actions for one symbol.
+// A symbol missing from one side counts as hold.
+type DecisionDiff struct {
+	Symbol            string  `json:"symbol"`
+	BaselineAction    string  `json:"baseline_action"`
+	ChallengerAction  string  `json:"challenger_action"`
+	SameAction        bool    `json:"same_action"`
+	BaselineSizeUSD   float64 `json:"baseline_size_usd,omitempty"`
+	ChallengerSizeUSD float64 `json:"challenger_size_usd,omitempty"`
+	SizeDeltaUSD      float64 `json:"size_delta_usd"` // challenger minus baseline
+}
+
+// CycleDiff is the per-cycle comparison. Matching means every symbol has the
+// same action and size.
+type CycleDiff struct {
+	Index    int            `json:"index"`
+	Time     time.Time      `json:"time"`
+	Matching bool           `json:"matching"`
+	Error    string         `json:"error,omitempty"`
+	Diffs    []DecisionDiff `json:"diffs,omitempty"`
+}
+
+// DiffReport summarizes a ReplayDiff run.
+type DiffReport struct {
+	Cycles        []CycleDiff `json:"cycles"`
+	Matching      int         `json:"matching"`
+	Diverging     int         `json:"diverging"`
+	Errors        int         `json:"errors"`
+	BaselinePNL   float64     `json:"baseline_pnl"`
+	ChallengerPNL float64     `json:"challenger_pnl"`
+	PNLDelta      float64     `json:"pnl_delta"` // challenger minus baseline
+}
+
+// sizeTolerance absorbs float noise when comparing decision sizes in USD.
+const sizeTolerance = 0.01
+
+// Run replays every cycle in order.
+func (r *ReplayDiff) Run(ctx context.Context) (*DiffReport, error) {
+	if r.Challenger == nil {
+		return nil, fmt.Errorf("backtest: replay diff has no challenger executor")
+	}
+	eq0 := r.InitialEquity
+	if eq0 <= 0 {
+		eq0 = 100000
+	}
+	fills := &ExecutorEngine{FeeBps: r.FeeBps, MaxPositionSizeUSD: r.MaxPositionSizeUSD}
+	baseExch, challExch := sim.NewWithEquity(eq0), sim.NewWithEquity(eq0)
+	baseRes := &ReplayResult{InitialEquity: eq0}
+	challRes := &ReplayResult{InitialEquity: eq0}
+
+	report := &DiffReport{}
+	for i, cycle := range r.Cycles {
+		if cycle.Context == nil {
+			return nil, fmt.Errorf("backtest: cycles[%d] has no context", i)
+		}
+		for sym, snap := range cycle.Context.MarketDataMap {
+			if snap == nil || snap.Price.Last <= 0 {
+				continue
+			}
+			for _, exch := range []*sim.Provider{baseExch, challExch} {
+				if err := exch.SetMarkPrice(ctx, sym, snap.Price.Last); err != nil {
+					return nil, err
+				}
+			}
+		}
+		cd := CycleDiff{Index: i, Time: cycle.Time}
+		var challenger []executorpkg.Decision
+		out, err := r.Challenger.GetFullDecision(cycle.Context)
+		switch {
+		case err != nil:
+			cd.Error = err.Error()
+		case out == nil:
+			cd.Error = "no decision"
+		default:
+			challenger = out.Decisions
+		}
+		if cd.Error != "" {
+			// Like the manager, never execute a bundle that failed validation.
+			report.Errors++
+		} else {
+			cd.Diffs, cd.Matching = diffDecisions(cycle.Decisions, challenger)
+			if cd.Matching {
+				report.Matching++
+			} else {
+				report.Diverging++
+			}
+		}
+		report.Cycles = append(report.Cycles, cd)
+
+		if err := fillCycle(ctx, fills, baseExch, cycle, cycle.Decisions, baseRes); err != nil {
+			return nil, err
+		}
+		if err := fillCycle(ctx, fills, challExch, cycle, challenger, challRes); err != nil {
+			return nil, err
+		}
+	}
+	baseEq, err := baseExch.GetAccountValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	challEq, err := challExch.GetAccountValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.BaselinePNL = baseEq - baseRes.Fees - eq0
+	report.ChallengerPNL = challEq - challRes.Fees - eq0
+	report.PNLDelta = report.ChallengerPNL - report.BaselinePNL
+
+	if r.OutputPath != "" {
+		if err := writeReport(r.OutputPath, report); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// fillCycle fills decisions at the cycle's prices, closes first.
+func fillCycle(ctx context.Context, e *ExecutorEngine, exch *sim.Provider, cycle HistoricalCycle, decisions []executorpkg.Decision, res *ReplayResult) error {
+	ordered := append([]executorpkg.Decision(nil), decisions...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return isClose(ordered[i].Action) && !isClose(ordered[j].Action)
+	})
+	for _, d := range ordered {
+		snap := cycle.Context.MarketDataMap[strings.ToUpper(strings.TrimSpace(d.Symbol))]
+		if snap == nil || snap.Price.Last <= 0 {
+			continue
+		}
+		switch d.Action {
+		case "close_long", "close_short":
+			if err := e.fillClose(ctx, exch, cycle.Time, d, res); err != nil {
+				return err
+			}
+		case "open_long", "open_short":
+			if err := e.fillOpen(ctx, exch, cycle.Time, d, snap.Price.Last, res); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// diffDecisions pairs decisions by symbol and reports whether both sides
+// agree on every action and size.
+func diffDecisions(baseline, challenger []executorpkg.Decision) ([]DecisionDiff, bool) {
+	type pair struct{ base, chall *executorpkg.Decision }
+	bySymbol := make(map[string]*pair)
+	var symbols []string
+	lookup := func(sym string) *pair {
+		sym = strings.ToUpper(strings.TrimSpace(sym))
+		p, ok := bySymbol[sym]
+		if !ok {
+			p = &pair{}
+			bySymbol[sym] = p
+			symbols = append(symbols, sym)
+		}
+		return p
+	}
+	for i := range baseline {
+		lookup(baseline[i].Symbol).base = &baseline[i]
+	}
+	for i := range challenger {
+		lookup(challenger[i].Symbol).chall = &challenger[i]
+	}
+	sort.Strings(symbols)
+
+	matching := true
+	diffs := make([]DecisionDiff, 0, len(symbols))
+	for _, sym := range symbols {
+		p := bySymbol[sym]
+		d := DecisionDiff{Symbol: sym, BaselineAction: "hold", ChallengerAction: "hold"}
+		if p.base != nil {
+			d.BaselineAction = normalizeAction(p.base.Action)
+			d.BaselineSizeUSD = p.base.PositionSizeUSD
+		}
+		if p.chall != nil {
+			d.ChallengerAction = normalizeAction(p.chall.Action)
+			d.ChallengerSizeUSD = p.chall.PositionSizeUSD
+		}
+		d.SameAction = d.BaselineAction == d.ChallengerAction
+		d.SizeDeltaUSD = d.ChallengerSizeUSD - d.BaselineSizeUSD
+		if !d.SameAction || d.SizeDeltaUSD > sizeTolerance || d.SizeDeltaUSD < -sizeTolerance {
+			matching = false
+		}
+		diffs = append(diffs, d)
+	}
+	return diffs, matching
+}
+
+// normalizeAction folds the no-trade actions into hold.
+func normalizeAction(action string) string {
+	action = strings.ToLower(strings.TrimSpace(action))
+	switch action {
+	case "", "wait":
+		return "hold"
+	}
+	return action
+}
@@ -0,0 +1,97 @@
+package backtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// diffCycles records an incumbent that opens BTC at 100, holds through 110
+// and closes at 90.
+const diffCycles = `[
+  {"time": "2025-01-01T00:00:00Z",
+   "context": {"CallCount": 1, "MarketDataMap": {"BTC": {"Price": {"Last": 100}}, "ETH": {"Price": {"Last": 10}}}},
+   "decisions": [{"Symbol": "BTC", "Action": "open_long", "Leverage": 2, "PositionSizeUSD": 1000}]},
+  {"time": "2025-01-01T01:00:00Z",
+   "context": {"CallCount": 2, "MarketDataMap": {"BTC": {"Price": {"Last": 110}}, "ETH": {"Price": {"Last": 10}}}},
+   "decisions": [{"Symbol": "BTC", "Action": "hold"}]},
+  {"time": "2025-01-01T02:00:00Z",
+   "context": {"CallCount": 3, "MarketDataMap": {"BTC": {"Price": {"Last": 90}}, "ETH": {"Price": {"Last": 10}}}},
+   "decisions": [{"Symbol": "BTC", "Action": "close_long"}]},
+  {"time": "2025-01-01T03:00:00Z",
+   "context": {"CallCount": 4, "MarketDataMap": {"BTC": {"Price": {"Last": 90}}, "ETH": {"Price": {"Last": 10}}}},
+   "decisions": [{"Symbol": "ETH", "Action": "open_short", "PositionSizeUSD": 500}]}
+]`
+
+func TestReplayDiffReportsMatchingAndDivergingCycles(t *testing.T) {
+	cycles, err := LoadHistoricalCycles(strings.NewReader(diffCycles))
+	require.NoError(t, err)
+	require.Len(t, cycles, 4)
+
+	challenger := &scriptedExecutor{
+		steps: [][]executorpkg.Decision{
+			{{Symbol: "BTC", Action: "open_long", Leverage: 2, PositionSizeUSD: 1000}},
+			{{Symbol: "BTC", Action: "close_long"}},
+			nil,
+			{{Symbol: "ETH", Action: "open_short", PositionSizeUSD: 800}},
+		},
+	}
+	report, err := (&ReplayDiff{Challenger: challenger, Cycles: cycles, InitialEquity: 10000}).Run(context.Background())
+	require.NoError(t, err)
+
+	// The challenger was prompted with the stored contexts, not rebuilt ones.
+	require.Len(t, challenger.inputs, 4)
+	for i, in := range challenger.inputs {
+		assert.Same(t, cycles[i].Context, in)
+	}
+
+	require.Len(t, report.Cycles, 4)
+	assert.Equal(t, 1, report.Matching)
+	assert.Equal(t, 3, report.Diverging)
+	assert.Zero(t, report.Errors)
+
+	assert.True(t, report.Cycles[0].Matching)
+	require.Len(t, report.Cycles[0].Diffs, 1)
+	assert.True(t, report.Cycles[0].Diffs[0].SameAction)
+	assert.Zero(t, report.Cycles[0].Diffs[0].SizeDeltaUSD)
+
+	assert.False(t, report.Cycles[1].Matching)
+	assert.Equal(t, DecisionDiff{Symbol: "BTC", BaselineAction: "hold", ChallengerAction: "close_long"}, report.Cycles[1].Diffs[0])
+
+	assert.False(t, report.Cycles[2].Matching)
+	assert.Equal(t, "close_long", report.Cycles[2].Diffs[0].BaselineAction)
+	assert.Equal(t, "hold", report.Cycles[2].Diffs[0].ChallengerAction)
+
+	// Same action, different size still diverges.
+	assert.False(t, report.Cycles[3].Matching)
+	assert.True(t, report.Cycles[3].Diffs[0].SameAction)
+	assert.InDelta(t, 300, report.Cycles[3].Diffs[0].SizeDeltaUSD, 1e-9)
+
+	// The challenger sold BTC at 110, the incumbent at 90.
+	assert.Less(t, report.BaselinePNL, 0.0)
+	assert.Greater(t, report.ChallengerPNL, 0.0)
+	assert.InDelta(t, report.ChallengerPNL-report.BaselinePNL, report.PNLDelta, 1e-9)
+	assert.Greater(t, report.PNLDelta, 150.0)
+}
+
+func TestReplayDiffCountsChallengerErrors(t *testing.T) {
+	cycles, err := LoadHistoricalCycles(strings.NewReader(diffCycles))
+	require.NoError(t, err)
+	challenger := &scriptedExecutor{errAt: map[int]bool{0: true}}
+
+	report, err := (&ReplayDiff{Challenger: challenger, Cycles: cycles[:1]}).Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Errors)
+	assert.Zero(t, report.Matching+report.Diverging)
+	assert.Equal(t, "invalid decision", report.Cycles[0].Error)
+}
+
+func TestLoadHistoricalCyclesRequiresContext(t *testing.T) {
+	_, err := LoadHistoricalCycles(strings.NewReader(`[{"decisions": []}]`))
+	assert.ErrorContains(t, err, "cycles[0] has no context")
+}
@@ -218,3 +218,24 @@ func (c *Config) BuildProviders() (map[string]Provider, error) {
 	}
 	return result, nil
 }
+
+// BuildVaultProvider instantiates the named provider with its vault address
+// replaced by vaultAddress, so orders are signed for that vault or
+// subaccount. The shared provider configuration is left unchanged.
+func (c *Config) BuildVaultProvider(name, vaultAddress string) (Provider, error) {
+	providerCfg, ok := c.Providers[name]
+	if !ok || providerCfg == nil {
+		return nil, fmt.Errorf("exchange provider %s: not defined", name)
+	}
+	builder, ok := lookupProviderBuilder(providerCfg.Type)
+	if !ok {
+		return nil, fmt.Errorf("exchange provider %s: unsupported type %q", name, providerCfg.Type)
+	}
+	cfgCopy := *providerCfg
+	cfgCopy.VaultAddress = strings.TrimSpace(vaultAddress)
+	provider, err := builder(name, &cfgCopy)
+	if err != nil {
+		return nil, fmt.Errorf("exchange provider %s (vault %s): %w", name, cfgCopy.VaultAddress, err)
+	}
+	return provider, nil
+}
@@ -60,3 +60,26 @@ providers:
 	assert.Error(t, err, "LoadConfig should error for missing private_key")
 	assert.Contains(t, err.Error(), "private_key", "error should mention private_key")
 }
+
+func TestBuildVaultProviderOverridesVaultAddress(t *testing.T) {
+	var built []exchange.ProviderConfig
+	exchange.RegisterProvider("vault_capture", func(name string, cfg *exchange.ProviderConfig) (exchange.Provider, error) {
+		built = append(built, *cfg)
+		return nil, nil
+	})
+	base := &exchange.ProviderConfig{Type: "vault_capture", PrivateKey: testPrivateKey, Testnet: true}
+	cfg := &exchange.Config{Providers: map[string]*exchange.ProviderConfig{"shared": base}}
+
+	const vault = "0x1111111111111111111111111111111111111111"
+	_, err := cfg.BuildVaultProvider("shared", vault)
+	assert.NoError(t, err)
+	if assert.Len(t, built, 1) {
+		assert.Equal(t, vault, built[0].VaultAddress)
+		assert.Equal(t, testPrivateKey, built[0].PrivateKey, "other settings carry over")
+		assert.True(t, built[0].Testnet)
+	}
+	assert.Empty(t, base.VaultAddress, "the shared config must not be modified")
+
+	_, err = cfg.BuildVaultProvider("missing", vault)
+	assert.ErrorContains(t, err, "not defined")
+}
@@ -0,0 +1,42 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// HTTPError reports a non-2xx response from an exchange endpoint.
+type HTTPError struct {
+	Op         string // request description, e.g. "hyperliquid: exchange"
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s http status %d: %s", e.Op, e.StatusCode, e.Body)
+}
+
+// IsRetriable reports whether err is a transient transport failure worth
+// retrying: a 5xx or 429 response, a timeout, or a dropped connection.
+// Exchange rejections such as insufficient margin are terminal.
+func IsRetriable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= http.StatusInternalServerError || httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
@@ -0,0 +1,31 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetriable(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"server error", &HTTPError{Op: "hyperliquid: exchange", StatusCode: 502}, true},
+		{"rate limited", fmt.Errorf("place: %w", &HTTPError{StatusCode: 429}), true},
+		{"bad request", &HTTPError{StatusCode: 400, Body: "Insufficient margin"}, false},
+		{"timeout", context.DeadlineExceeded, true},
+		{"cancelled", context.Canceled, false},
+		{"connection refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"rejection", errors.New("insufficient margin to place order"), false},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, IsRetriable(tc.err), tc.name)
+	}
+}
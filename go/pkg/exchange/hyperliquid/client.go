@@ -137,9 +137,13 @@ func WithAssetCacheTTL(ttl time.Duration) ClientOption {
 }
 
 // getInfoAddress returns the address to use for info requests.
-// If mainAddress is configured (API wallet scenario), it returns mainAddress.
-// Otherwise, it returns the signer's address.
+// A configured vault is the account orders are placed for, so its state is
+// queried. Otherwise, if mainAddress is configured (API wallet scenario), it
+// returns mainAddress, else the signer's address.
 func (c *Client) getInfoAddress() string {
+	if c.vault != "" {
+		return c.vault
+	}
 	if c.mainAddress != "" {
 		return c.mainAddress
 	}
@@ -377,6 +381,55 @@ func (c *Client) GetSubAccounts(ctx context.Context, user string) ([]SubAccount,
 	return out, nil
 }
 
+// GetUserFills retrieves the trading account's most recent fills (the venue
+// returns up to 2000, newest first) and keeps those executed at or after since.
+// A zero since keeps every returned fill.
+func (c *Client) GetUserFills(ctx context.Context, since time.Time) ([]exchange.UserFill, error) {
+	infoAddr := c.getInfoAddress()
+	if infoAddr == "" {
+		return nil, fmt.Errorf("hyperliquid: client address unavailable")
+	}
+	var out []exchange.UserFill
+	if err := c.doInfoRequest(ctx, InfoRequest{Type: "userFills", User: infoAddr}, &out); err != nil {
+		return nil, err
+	}
+	if since.IsZero() {
+		return out, nil
+	}
+	cutoff := since.UnixMilli()
+	kept := out[:0]
+	for _, f := range out {
+		if f.Time >= cutoff {
+			kept = append(kept, f)
+		}
+	}
+	return kept, nil
+}
+
+// GetUserFillsByTime retrieves fills for the trading account within [start, end].
+// A zero end time leaves the range open-ended (up to now).
+func (c *Client) GetUserFillsByTime(ctx context.Context, start, end time.Time) ([]exchange.UserFill, error) {
+	infoAddr := c.getInfoAddress()
+	if infoAddr == "" {
+		return nil, fmt.Errorf("hyperliquid: client address unavailable")
+	}
+	if start.IsZero() {
+		return nil, fmt.Errorf("hyperliquid: userFillsByTime requires a start time")
+	}
+	if !end.IsZero() && end.Before(start) {
+		return nil, fmt.Errorf("hyperliquid: userFillsByTime end %s before start %s", end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+	req := InfoRequest{Type: "userFillsByTime", User: infoAddr, StartTime: start.UnixMilli()}
+	if !end.IsZero() {
+		req.EndTime = end.UnixMilli()
+	}
+	var out []exchange.UserFill
+	if err := c.doInfoRequest(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GetVaultDetails retrieves vault details by address (optionally scoped by user).
 func (c *Client) GetVaultDetails(ctx context.Context, vaultAddress string, user string) (*VaultDetails, error) {
 	if !common.IsHexAddress(vaultAddress) {
@@ -428,7 +481,7 @@ func (c *Client) doExchangeRequest(ctx context.Context, action interface{}, resu
 	}
 	if resp.StatusCode < http.StatusOK || resp.StatusCode >= 300 {
 		c.logf("hyperliquid: exchange error status=%d body=%s", resp.StatusCode, string(body))
-		return fmt.Errorf("hyperliquid: exchange http status %d: %s", resp.StatusCode, string(body))
+		return &exchange.HTTPError{Op: "hyperliquid: exchange", StatusCode: resp.StatusCode, Body: string(body)}
 	}
 	if result != nil {
 		if err := json.Unmarshal(body, result); err != nil {
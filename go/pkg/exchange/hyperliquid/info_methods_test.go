@@ -2,9 +2,11 @@ package hyperliquid
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -81,3 +83,74 @@ func TestGetVaultDetails_InvalidVault(t *testing.T) {
 	_, err = client.GetVaultDetails(context.Background(), "not-an-address", "")
 	require.Error(t, err)
 }
+
+func TestGetUserFillsByTime_Success(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[
+          {"coin":"BTC","px":"60000","sz":"0.01","side":"A","time":1700000500000,"startPosition":"0.01","dir":"Close Long","closedPnl":"12.5","hash":"0xabc","oid":1,"crossed":true,"fee":"0.3","tid":11,"feeToken":"USDC"},
+          {"coin":"ETH","px":"3000","sz":"0.5","side":"B","time":1700000600000,"startPosition":"0","dir":"Open Long","closedPnl":"0.0","hash":"0xdef","oid":2,"crossed":true,"fee":"0.2","tid":12,"feeToken":"USDC"}
+        ]`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a741b52d7c5d5095e2f", false)
+	require.NoError(t, err)
+	client.infoURL = server.URL
+
+	start := time.UnixMilli(1700000000000)
+	end := time.UnixMilli(1700001000000)
+	fills, err := client.GetUserFillsByTime(context.Background(), start, end)
+	require.NoError(t, err)
+	require.Len(t, fills, 2)
+	require.Equal(t, "12.5", fills[0].ClosedPnl)
+	require.Equal(t, "Open Long", fills[1].Dir)
+	require.Equal(t, "userFillsByTime", got["type"])
+	require.EqualValues(t, 1700000000000, got["startTime"])
+	require.EqualValues(t, 1700001000000, got["endTime"])
+}
+
+func TestGetUserFillsByTime_InvalidRange(t *testing.T) {
+	client, err := NewClient("0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a741b52d7c5d5095e2f", false)
+	require.NoError(t, err)
+	now := time.Now()
+	_, err = client.GetUserFillsByTime(context.Background(), now, now.Add(-time.Hour))
+	require.Error(t, err)
+	_, err = client.GetUserFillsByTime(context.Background(), time.Time{}, now)
+	require.Error(t, err)
+}
+
+func TestGetUserFills_FiltersBySince(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[
+          {"coin":"ETH","px":"3010.5","sz":"0.5","side":"A","time":1700000600000,"startPosition":"0.5","dir":"Close Long","closedPnl":"5.25","hash":"0xdef","oid":2,"crossed":true,"fee":"0.68","tid":12,"feeToken":"USDC"},
+          {"coin":"ETH","px":"3000","sz":"0.5","side":"B","time":1700000000000,"startPosition":"0","dir":"Open Long","closedPnl":"0.0","hash":"0xabc","oid":1,"crossed":true,"fee":"0.67","tid":11,"feeToken":"USDC"}
+        ]`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a741b52d7c5d5095e2f", false)
+	require.NoError(t, err)
+	client.infoURL = server.URL
+
+	fills, err := client.GetUserFills(context.Background(), time.Time{})
+	require.NoError(t, err)
+	require.Len(t, fills, 2)
+	require.Equal(t, "userFills", got["type"])
+	require.Equal(t, client.getInfoAddress(), got["user"])
+	require.NotContains(t, got, "startTime")
+
+	fills, err = client.GetUserFills(context.Background(), time.UnixMilli(1700000300000))
+	require.NoError(t, err)
+	require.Len(t, fills, 1)
+	fill := fills[0]
+	require.InDelta(t, 3010.5, fill.Price(), 1e-9)
+	require.InDelta(t, 0.5, fill.Size(), 1e-9)
+	require.InDelta(t, 0.68, fill.FeeUSD(), 1e-9)
+	require.Equal(t, int64(1700000600000), fill.ExecutedAt().UnixMilli())
+}
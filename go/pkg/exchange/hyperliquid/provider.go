@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"nof0-api/pkg/exchange"
 )
@@ -31,6 +32,8 @@ type clientAPI interface {
 	CancelOrdersByCloid(ctx context.Context, cancels []CancelByCloid) error
 	ModifyOrder(ctx context.Context, req ModifyOrderRequest) (*exchange.OrderResponse, error)
 	ModifyOrders(ctx context.Context, requests []ModifyOrderRequest) (*exchange.OrderResponse, error)
+	GetUserFills(ctx context.Context, since time.Time) ([]exchange.UserFill, error)
+	GetUserFillsByTime(ctx context.Context, start, end time.Time) ([]exchange.UserFill, error)
 }
 
 type Provider struct {
@@ -152,11 +155,28 @@ func (p *Provider) ModifyOrder(ctx context.Context, req ModifyOrderRequest) (*ex
 	return p.client.ModifyOrder(ctx, req)
 }
 
+// ModifyOrderByCloid replaces the resting order identified by cloid with
+// order, which keeps the same client order identifier.
+func (p *Provider) ModifyOrderByCloid(ctx context.Context, cloid string, order exchange.Order) (*exchange.OrderResponse, error) {
+	order.Cloid = cloid
+	return p.client.ModifyOrder(ctx, ModifyOrderRequest{Cloid: cloid, Order: order})
+}
+
 // ModifyOrders updates multiple resting orders atomically.
 func (p *Provider) ModifyOrders(ctx context.Context, requests []ModifyOrderRequest) (*exchange.OrderResponse, error) {
 	return p.client.ModifyOrders(ctx, requests)
 }
 
+// GetUserFills returns the account's recent fills executed at or after since.
+func (p *Provider) GetUserFills(ctx context.Context, since time.Time) ([]exchange.UserFill, error) {
+	return p.client.GetUserFills(ctx, since)
+}
+
+// GetUserFillsByTime returns account fills executed within [start, end].
+func (p *Provider) GetUserFillsByTime(ctx context.Context, start, end time.Time) ([]exchange.UserFill, error) {
+	return p.client.GetUserFillsByTime(ctx, start, end)
+}
+
 // FormatSize rounds a float quantity to szDecimals and returns a string.
 func (p *Provider) FormatSize(ctx context.Context, coin string, qty float64) (string, error) {
 	return p.client.FormatSize(ctx, coin, qty)
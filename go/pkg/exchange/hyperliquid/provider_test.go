@@ -3,6 +3,7 @@ package hyperliquid
 import (
 	"context"
 	"testing"
+	"time"
 
 	"nof0-api/pkg/exchange"
 
@@ -99,6 +100,24 @@ func (m *MockClient) ModifyOrders(ctx context.Context, requests []ModifyOrderReq
 	return args.Get(0).(*exchange.OrderResponse), args.Error(1)
 }
 
+func (m *MockClient) GetUserFills(ctx context.Context, since time.Time) ([]exchange.UserFill, error) {
+	args := m.Called(ctx, since)
+	var fills []exchange.UserFill
+	if v := args.Get(0); v != nil {
+		fills = v.([]exchange.UserFill)
+	}
+	return fills, args.Error(1)
+}
+
+func (m *MockClient) GetUserFillsByTime(ctx context.Context, start, end time.Time) ([]exchange.UserFill, error) {
+	args := m.Called(ctx, start, end)
+	var fills []exchange.UserFill
+	if v := args.Get(0); v != nil {
+		fills = v.([]exchange.UserFill)
+	}
+	return fills, args.Error(1)
+}
+
 func (m *MockClient) FormatSize(ctx context.Context, coin string, qty float64) (string, error) {
 	args := m.Called(ctx, coin, qty)
 	return args.Get(0).(string), args.Error(1)
@@ -236,6 +255,30 @@ func TestProviderGetOpenOrders(t *testing.T) {
 	})
 }
 
+func TestProviderModifyOrderByCloid(t *testing.T) {
+	mockClient := &MockClient{}
+	provider := &Provider{client: mockClient}
+
+	ctx := context.Background()
+	cloid := "0x00000000000000000000000000000001"
+	order := exchange.Order{
+		Asset:     1,
+		IsBuy:     true,
+		LimitPx:   "2995",
+		Sz:        "0.1",
+		OrderType: exchange.OrderType{Limit: &exchange.LimitOrderType{TIF: "Alo"}},
+	}
+	want := order
+	want.Cloid = cloid
+	expected := &exchange.OrderResponse{Status: "ok"}
+	mockClient.On("ModifyOrder", ctx, ModifyOrderRequest{Cloid: cloid, Order: want}).Return(expected, nil)
+
+	resp, err := provider.ModifyOrderByCloid(ctx, cloid, order)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, resp)
+	mockClient.AssertExpectations(t)
+}
+
 func TestProviderGetPositions(t *testing.T) {
 	// Create mock client
 	mockClient := &MockClient{}
@@ -610,3 +653,19 @@ func TestProviderInit(t *testing.T) {
 		assert.NotNil(t, provider)
 	})
 }
+
+func TestProviderImplementsFillHistory(t *testing.T) {
+	var _ exchange.FillHistory = (*Provider)(nil)
+
+	mockClient := &MockClient{}
+	provider := &Provider{client: mockClient}
+	ctx := context.Background()
+	since := time.UnixMilli(1700000000000)
+	want := []exchange.UserFill{{Coin: "BTC", Px: "60000", Sz: "0.01", Fee: "0.3", Time: 1700000500000}}
+	mockClient.On("GetUserFills", ctx, since).Return(want, nil)
+
+	got, err := provider.GetUserFills(ctx, since)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+	mockClient.AssertExpectations(t)
+}
@@ -145,6 +145,9 @@ type InfoRequest struct {
 	User string `json:"user,omitempty"`
 	// For vaultDetails endpoint
 	VaultAddress string `json:"vaultAddress,omitempty"`
+	// For time-ranged endpoints such as userFillsByTime (milliseconds)
+	StartTime int64 `json:"startTime,omitempty"`
+	EndTime   int64 `json:"endTime,omitempty"`
 }
 
 // AccountStateResponse wraps account state returned by Hyperliquid.
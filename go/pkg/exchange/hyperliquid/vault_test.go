@@ -0,0 +1,54 @@
+package hyperliquid
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange"
+)
+
+func TestVaultProviderQueriesVaultAccount(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		users []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req InfoRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		mu.Lock()
+		users = append(users, req.User)
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"assetPositions":[],"marginSummary":{"accountValue":"500","totalMarginUsed":"0"},"withdrawable":"500"}`))
+	}))
+	defer server.Close()
+
+	const (
+		vault = "0x1111111111111111111111111111111111111111"
+		main  = "0x2222222222222222222222222222222222222222"
+	)
+	cfg := &exchange.Config{Providers: map[string]*exchange.ProviderConfig{
+		"hl": {Type: "hyperliquid", PrivateKey: "0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a741b52d7c5d5095e2f", MainAddress: main},
+	}}
+	built, err := cfg.BuildVaultProvider("hl", vault)
+	require.NoError(t, err)
+	provider, ok := built.(*Provider)
+	require.True(t, ok)
+	provider.client.(*Client).infoURL = server.URL
+
+	_, err = provider.GetAccountState(context.Background())
+	require.NoError(t, err)
+	_, err = provider.GetPositions(context.Background())
+	require.NoError(t, err)
+
+	require.NotEmpty(t, users)
+	for _, user := range users {
+		assert.Equal(t, vault, user, "a vault provider must read the vault's account, not the master's")
+	}
+}
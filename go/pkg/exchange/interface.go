@@ -1,6 +1,9 @@
 package exchange
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Provider exposes trading capabilities in an exchange-agnostic fashion.
 type Provider interface {
@@ -21,3 +24,12 @@ type Provider interface {
 	// Utilities.
 	GetAssetIndex(ctx context.Context, coin string) (int, error)
 }
+
+// FillHistory is implemented by providers that can report the account's
+// executed fills. Callers type-assert for it; venues without fill history
+// simply don't implement it.
+type FillHistory interface {
+	// GetUserFills returns the account's recent fills executed at or after
+	// since (a zero since returns everything the venue reports).
+	GetUserFills(ctx context.Context, since time.Time) ([]UserFill, error)
+}
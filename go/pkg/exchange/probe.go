@@ -0,0 +1,102 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultProbeTimeout bounds each provider's probe call when the provider
+// config sets no timeout.
+const defaultProbeTimeout = 10 * time.Second
+
+// ProbeResult is the outcome of probing one provider.
+type ProbeResult struct {
+	Provider string
+	Err      error
+}
+
+// ProbeError lists the providers whose probe failed.
+type ProbeError struct {
+	Failures []ProbeResult
+}
+
+func (e *ProbeError) Error() string {
+	parts := make([]string, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		parts = append(parts, fmt.Sprintf("provider %s: %v", f.Provider, f.Err))
+	}
+	return "exchange probe: " + strings.Join(parts, "; ")
+}
+
+// Probe builds every configured provider and checks it with a read-only
+// account call, so bad credentials or an unreachable venue fail at startup
+// rather than mid-loop. It returns a *ProbeError naming each failing provider.
+func (c *Config) Probe(ctx context.Context) error {
+	providers, err := c.BuildProviders()
+	if err != nil {
+		return err
+	}
+	timeouts := make(map[string]time.Duration, len(c.Providers))
+	for name, cfg := range c.Providers {
+		timeouts[name] = cfg.Timeout
+	}
+	return probeProviders(ctx, providers, timeouts)
+}
+
+// ProbeProviders runs the Probe check against already built providers.
+func ProbeProviders(ctx context.Context, providers map[string]Provider) error {
+	return probeProviders(ctx, providers, nil)
+}
+
+func probeProviders(ctx context.Context, providers map[string]Provider, timeouts map[string]time.Duration) error {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var failures []ProbeResult
+	for _, name := range names {
+		timeout := timeouts[name]
+		if timeout <= 0 {
+			timeout = defaultProbeTimeout
+		}
+		if err := probeProvider(ctx, providers[name], timeout); err != nil {
+			failures = append(failures, ProbeResult{Provider: name, Err: err})
+		}
+	}
+	if len(failures) > 0 {
+		return &ProbeError{Failures: failures}
+	}
+	return nil
+}
+
+func probeProvider(ctx context.Context, provider Provider, timeout time.Duration) error {
+	if provider == nil {
+		return errors.New("provider is nil")
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if _, err := provider.GetAccountState(ctx); err != nil {
+		return fmt.Errorf("get account state: %w%s", err, probeHint(err))
+	}
+	return nil
+}
+
+// probeHint suggests the likely cause of common probe failures.
+func probeHint(err error) string {
+	var httpErr *HTTPError
+	switch {
+	case errors.As(err, &httpErr) && (httpErr.StatusCode == http.StatusUnauthorized || httpErr.StatusCode == http.StatusForbidden):
+		return " (credentials rejected; check private_key, api_key and main_address)"
+	case errors.Is(err, context.DeadlineExceeded):
+		return " (timed out; check network access and the testnet flag)"
+	case IsRetriable(err):
+		return " (venue unreachable or unavailable)"
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubAccountProvider stubs GetAccountState; other Provider methods are not used.
+type stubAccountProvider struct {
+	Provider
+	err   error
+	delay time.Duration
+}
+
+func (s *stubAccountProvider) GetAccountState(ctx context.Context) (*AccountState, error) {
+	if s.delay > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(s.delay):
+		}
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &AccountState{}, nil
+}
+
+func TestConfigProbeReportsAuthFailure(t *testing.T) {
+	RegisterProvider("probe_stub", func(name string, _ *ProviderConfig) (Provider, error) {
+		if name == "bad_keys" {
+			return &stubAccountProvider{err: &HTTPError{Op: "probe_stub: info", StatusCode: 401, Body: "invalid signature"}}, nil
+		}
+		return &stubAccountProvider{}, nil
+	})
+	cfg := &Config{Providers: map[string]*ProviderConfig{
+		"good":     {Type: "probe_stub"},
+		"bad_keys": {Type: "probe_stub"},
+	}}
+
+	err := cfg.Probe(context.Background())
+	require.Error(t, err)
+	var probeErr *ProbeError
+	require.ErrorAs(t, err, &probeErr)
+	require.Len(t, probeErr.Failures, 1, "only the failing provider should be reported")
+	assert.Equal(t, "bad_keys", probeErr.Failures[0].Provider)
+	assert.Contains(t, err.Error(), "provider bad_keys")
+	assert.Contains(t, err.Error(), "http status 401")
+	assert.Contains(t, err.Error(), "credentials rejected")
+	assert.NotContains(t, err.Error(), "provider good")
+}
+
+func TestProbeProvidersTimesOut(t *testing.T) {
+	providers := map[string]Provider{
+		"slow": &stubAccountProvider{delay: time.Second},
+		"ok":   &stubAccountProvider{},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := ProbeProviders(ctx, providers)
+	var probeErr *ProbeError
+	require.ErrorAs(t, err, &probeErr)
+	require.Len(t, probeErr.Failures, 1)
+	assert.True(t, errors.Is(probeErr.Failures[0].Err, context.DeadlineExceeded))
+	assert.Contains(t, err.Error(), "timed out")
+
+	assert.NoError(t, ProbeProviders(context.Background(), map[string]Provider{"ok": &stubAccountProvider{}}))
+}
@@ -55,6 +55,17 @@ func New() *Provider {
 	}
 }
 
+// NewWithEquity constructs a simulator starting with equityUSD of cash; a
+// non-positive value keeps the default.
+func NewWithEquity(equityUSD float64) *Provider {
+	p := New()
+	if equityUSD > 0 {
+		p.initialEquity = equityUSD
+		p.cash = equityUSD
+	}
+	return p
+}
+
 func canonical(coin string) string { return strings.ToUpper(strings.TrimSpace(coin)) }
 
 // GetAssetIndex resolves a stable asset identifier for the provided coin.
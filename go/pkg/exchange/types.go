@@ -3,6 +3,9 @@ package exchange
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Core trading domain types shared across exchange implementations.
@@ -135,6 +138,45 @@ type Fill struct {
 	Timestamp int64  `json:"timestamp,omitempty"`
 }
 
+// UserFill is a historical trade execution reported by the venue for an account.
+type UserFill struct {
+	Coin          string `json:"coin"`
+	Px            string `json:"px"`
+	Sz            string `json:"sz"`
+	Side          string `json:"side"`
+	Time          int64  `json:"time"`
+	StartPosition string `json:"startPosition"`
+	Dir           string `json:"dir"`
+	ClosedPnl     string `json:"closedPnl"`
+	Hash          string `json:"hash"`
+	Oid           int64  `json:"oid"`
+	Crossed       bool   `json:"crossed"`
+	Fee           string `json:"fee"`
+	Tid           int64  `json:"tid"`
+	FeeToken      string `json:"feeToken"`
+	Cloid         string `json:"cloid,omitempty"`
+}
+
+// Price returns the fill price, or 0 when it does not parse.
+func (f UserFill) Price() float64 { return parseDecimal(f.Px) }
+
+// Size returns the filled quantity, or 0 when it does not parse.
+func (f UserFill) Size() float64 { return parseDecimal(f.Sz) }
+
+// FeeUSD returns the fee charged for the fill, or 0 when it does not parse.
+func (f UserFill) FeeUSD() float64 { return parseDecimal(f.Fee) }
+
+// ExecutedAt returns the fill time.
+func (f UserFill) ExecutedAt() time.Time { return time.UnixMilli(f.Time) }
+
+func parseDecimal(raw string) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
 // OrderResponse captures the standard exchange response after an order submission.
 type OrderResponse struct {
 	Status       string            `json:"status"` // "ok" or "err".
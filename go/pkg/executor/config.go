@@ -26,8 +26,32 @@ type Config struct {
 	AllowedTraderIDs       []string            `yaml:"allowed_trader_ids"`
 	SigningKey             string              `yaml:"signing_key"`
 	Overrides              map[string]Override `yaml:"overrides"`
+	PromptSections         PromptSections      `yaml:"prompt_sections"`
+	SymbolUniverse         SymbolUniverseMode  `yaml:"symbol_universe"`
+	DecisionSchema         DecisionSchemaMode  `yaml:"decision_schema"`
 	TraderID               string              `yaml:"-"` // runtime-only metadata for persistence hooks
 
+	// ToolCalling lets the model call get_snapshot / get_positions before
+	// submitting its decision; it needs a ToolProvider on the executor.
+	// MaxToolIterations caps the model turns per decision (default 4).
+	ToolCalling       bool `yaml:"tool_calling"`
+	MaxToolIterations int  `yaml:"max_tool_iterations"`
+
+	// StreamDecisions streams the completion so the model's reasoning is
+	// captured into FullDecision.CoTTrace as it arrives; the decision is read
+	// from the trailing JSON object. Ignored when ToolCalling is active.
+	StreamDecisions bool `yaml:"stream_decisions"`
+
+	// RequireStopLoss and RequireTakeProfit reject opens that omit that
+	// level; unset means required. An open without both levels has no
+	// risk/reward ratio to enforce.
+	RequireStopLoss   *bool `yaml:"require_stop_loss"`
+	RequireTakeProfit *bool `yaml:"require_take_profit"`
+
+	// PromptPipeline orders, toggles and swaps the context sections rendered
+	// by {{ .ContextSections }}; empty keeps the default layout.
+	PromptPipeline []PromptSectionConfig `yaml:"prompt_pipeline"`
+
 	DecisionIntervalRaw string `yaml:"decision_interval"`
 	DecisionTimeoutRaw  string `yaml:"decision_timeout"`
 	minRiskRewardSet    bool
@@ -42,6 +66,43 @@ type Override struct {
 	MaxPositions      *int     `yaml:"max_positions,omitempty"`
 }
 
+// SymbolUniverseMode controls how decisions for symbols outside the provided
+// candidates and open positions are handled.
+type SymbolUniverseMode string
+
+const (
+	// SymbolUniverseOff accepts any symbol (default).
+	SymbolUniverseOff SymbolUniverseMode = ""
+	// SymbolUniverseLenient drops off-universe decisions.
+	SymbolUniverseLenient SymbolUniverseMode = "lenient"
+	// SymbolUniverseStrict rejects the decision bundle with an error.
+	SymbolUniverseStrict SymbolUniverseMode = "strict"
+)
+
+// DecisionSchemaMode selects the structured output schema requested from the
+// model.
+type DecisionSchemaMode string
+
+const (
+	// DecisionSchemaFull requires every decision field (default).
+	DecisionSchemaFull DecisionSchemaMode = ""
+	// DecisionSchemaMinimal requires only signal and symbol; leverage, entry
+	// price, confidence and risk are derived when the model omits them. It
+	// suits weaker models that fail the full schema.
+	DecisionSchemaMinimal DecisionSchemaMode = "minimal"
+)
+
+// PromptSections toggles optional blocks of the rendered prompt to save tokens.
+// Nil fields default to included.
+type PromptSections struct {
+	IncludeRiskBudget       *bool `yaml:"include_risk_budget,omitempty"`
+	IncludePerformance      *bool `yaml:"include_performance,omitempty"`
+	IncludeCandidatesDetail *bool `yaml:"include_candidates_detail,omitempty"`
+	IncludeMarketSnapshots  *bool `yaml:"include_market_snapshots,omitempty"`
+}
+
+func sectionEnabled(v *bool) bool { return v == nil || *v }
+
 // LoadConfig reads configuration from disk.
 func LoadConfig(path string) (*Config, error) {
 	confkit.LoadDotenvOnce()
@@ -129,6 +190,10 @@ func (c *Config) parseDurations() error {
 
 func (c *Config) expandFields() {
 	c.SigningKey = strings.TrimSpace(os.ExpandEnv(c.SigningKey))
+	for i := range c.PromptPipeline {
+		c.PromptPipeline[i].Name = strings.TrimSpace(c.PromptPipeline[i].Name)
+		c.PromptPipeline[i].Source = strings.TrimSpace(c.PromptPipeline[i].Source)
+	}
 	for i, id := range c.AllowedTraderIDs {
 		c.AllowedTraderIDs[i] = strings.TrimSpace(id)
 	}
@@ -151,6 +216,12 @@ func (c *Config) Validate() error {
 	if c.MaxPositions <= 0 {
 		return errors.New("executor config: max_positions must be positive")
 	}
+	if c.DecisionInterval > 0 && c.DecisionTimeout >= c.DecisionInterval {
+		return fmt.Errorf("executor config: decision_timeout %s must be less than decision_interval %s", c.DecisionTimeout, c.DecisionInterval)
+	}
+	if c.MaxToolIterations < 0 {
+		return errors.New("executor config: max_tool_iterations cannot be negative")
+	}
 	if len(c.AllowedTraderIDs) > 0 {
 		seen := make(map[string]struct{}, len(c.AllowedTraderIDs))
 		for _, id := range c.AllowedTraderIDs {
@@ -163,6 +234,19 @@ func (c *Config) Validate() error {
 			seen[id] = struct{}{}
 		}
 	}
+	switch c.SymbolUniverse {
+	case SymbolUniverseOff, SymbolUniverseLenient, SymbolUniverseStrict:
+	default:
+		return fmt.Errorf("executor config: symbol_universe must be lenient or strict, got %q", c.SymbolUniverse)
+	}
+	switch c.DecisionSchema {
+	case DecisionSchemaFull, DecisionSchemaMinimal:
+	default:
+		return fmt.Errorf("executor config: decision_schema must be minimal or empty, got %q", c.DecisionSchema)
+	}
+	if err := c.validatePromptPipeline(); err != nil {
+		return err
+	}
 	for key, override := range c.Overrides {
 		if strings.TrimSpace(key) == "" {
 			return errors.New("executor config: overrides cannot contain empty keys")
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -106,3 +107,22 @@ decision_timeout: 1s
 	assert.Error(t, err, "LoadConfig should error for invalid config")
 	assert.Contains(t, err.Error(), "major_coin_leverage", "error should mention major_coin_leverage")
 }
+
+func TestConfigValidateSymbolUniverse(t *testing.T) {
+	cfg := &Config{MajorCoinLeverage: 10, AltcoinLeverage: 5, MinConfidence: 50, MinRiskReward: 2, MaxPositions: 1, SymbolUniverse: "loose"}
+	assert.Error(t, cfg.Validate(), "unknown symbol_universe should be rejected")
+	cfg.SymbolUniverse = SymbolUniverseStrict
+	assert.NoError(t, cfg.Validate(), "strict symbol_universe should be accepted")
+}
+
+func TestConfigValidateTimeoutBelowInterval(t *testing.T) {
+	cfg := &Config{MajorCoinLeverage: 10, AltcoinLeverage: 5, MinConfidence: 50, MinRiskReward: 2, MaxPositions: 1,
+		DecisionInterval: 30 * time.Second, DecisionTimeout: 60 * time.Second}
+	err := cfg.Validate()
+	assert.Error(t, err, "a timeout longer than the interval lets cycles overlap")
+	assert.Contains(t, err.Error(), "decision_timeout")
+	cfg.DecisionTimeout = 30 * time.Second
+	assert.Error(t, cfg.Validate(), "the timeout must be strictly less than the interval")
+	cfg.DecisionTimeout = 20 * time.Second
+	assert.NoError(t, cfg.Validate())
+}
@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
 	"strings"
 	"time"
@@ -32,6 +33,8 @@ type BasicExecutor struct {
 	modelAlias    string
 	failures      map[string]int
 	conversations ConversationRecorder
+	// tools answers data tool calls when cfg.ToolCalling is set.
+	tools ToolProvider
 }
 
 // NewExecutor constructs a BasicExecutor. The templatePath is the executor prompt template provided by caller.
@@ -119,35 +122,102 @@ func (e *BasicExecutor) GetFullDecision(input *Context) (*FullDecision, error) {
 	}
 
 	// Use package-level contract type for structured response.
-	var out decisionContract
+	var (
+		out     decisionContract
+		minimal minimalDecisionContract
+		target  any = &out
+	)
+	if e.cfg.DecisionSchema == DecisionSchemaMinimal {
+		target = &minimal
+	}
 	callCtx, cancel := context.WithTimeout(context.Background(), e.cfg.DecisionTimeout)
 	defer cancel()
 	callStart := time.Now()
-	resp, err := e.llm.ChatStructured(callCtx, req, &out)
+	var (
+		resp *llm.ChatResponse
+		cot  string
+	)
+	switch {
+	case e.cfg.ToolCalling && e.tools != nil:
+		resp, err = e.chatWithTools(callCtx, req, target)
+	case e.cfg.StreamDecisions:
+		resp, cot, err = e.chatStream(callCtx, req, target)
+	default:
+		resp, err = e.llm.ChatStructured(callCtx, req, target)
+	}
+	// Tokens are billed even when the response is unusable, so report usage
+	// on every path that has one.
+	var usage llm.Usage
+	if resp != nil {
+		usage = resp.Usage
+	}
 	if err != nil {
 		logx.WithContext(callCtx).Errorf("executor: chat failed digest=%s duration=%s error=%v", promptDigest, time.Since(callStart), err)
-		return &FullDecision{UserPrompt: promptStr, CoTTrace: "", Decisions: nil, Timestamp: time.Now()}, err
+		return &FullDecision{UserPrompt: promptStr, CoTTrace: cot, Decisions: nil, Timestamp: time.Now(), Usage: usage}, err
 	}
 	logx.WithContext(callCtx).Infof("executor: chat completed digest=%s duration=%s", promptDigest, time.Since(callStart))
 	e.recordConversation(callCtx, promptStr, resp)
 
 	// Phase 3: Map & validate.
+	if e.cfg.DecisionSchema == DecisionSchemaMinimal {
+		out = decisionContract(minimal)
+	}
+	if err := checkSchemaVersion(out.SchemaVersion); err != nil {
+		logx.WithContext(callCtx).Errorf("executor: schema version mismatch digest=%s version=%q error=%v", promptDigest, out.SchemaVersion, err)
+		return &FullDecision{UserPrompt: promptStr, CoTTrace: cot, Decisions: nil, Timestamp: time.Now(), Usage: usage}, err
+	}
 	mapped := mapDecisionContract(out, input.Positions)
+	if e.cfg.DecisionSchema == DecisionSchemaMinimal {
+		applyMinimalDefaults(e.cfg, input, &mapped)
+	}
+	if !inSymbolUniverse(input, mapped) {
+		switch e.cfg.SymbolUniverse {
+		case SymbolUniverseStrict:
+			err := fmt.Errorf("executor: decision symbol %q is outside the candidate set and open positions", mapped.Symbol)
+			return &FullDecision{UserPrompt: promptStr, CoTTrace: cot, Decisions: nil, Timestamp: time.Now(), Usage: usage}, err
+		case SymbolUniverseLenient:
+			logx.Infof("executor: dropping off-universe decision digest=%s symbol=%s action=%s", promptDigest, mapped.Symbol, mapped.Action)
+			return &FullDecision{UserPrompt: promptStr, CoTTrace: cot, Decisions: nil, Timestamp: time.Now(), Usage: usage}, nil
+		}
+	}
 	if err := ValidateDecisions(e.cfg, input, []Decision{mapped}); err != nil {
 		e.trackFailure(mapped.Symbol, err)
-		return &FullDecision{UserPrompt: promptStr, CoTTrace: "", Decisions: []Decision{mapped}, Timestamp: time.Now()}, err
+		return &FullDecision{UserPrompt: promptStr, CoTTrace: cot, Decisions: nil, Timestamp: time.Now(), Usage: usage}, err
 	}
 	e.resetFailure(mapped.Symbol)
 	logx.Infof("executor: decision validated digest=%s symbol=%s action=%s notional=%.2f confidence=%d", promptDigest, mapped.Symbol, mapped.Action, mapped.PositionSizeUSD, mapped.Confidence)
 
 	return &FullDecision{
 		UserPrompt: promptStr,
-		CoTTrace:   "",
+		CoTTrace:   cot,
 		Decisions:  []Decision{mapped},
 		Timestamp:  time.Now(),
+		Usage:      usage,
 	}, nil
 }
 
+// inSymbolUniverse reports whether a trade decision targets a provided
+// candidate or an open position. Non-trade actions always pass.
+func inSymbolUniverse(input *Context, d Decision) bool {
+	switch d.Action {
+	case "open_long", "open_short", "close_long", "close_short":
+	default:
+		return true
+	}
+	symbol := strings.ToUpper(strings.TrimSpace(d.Symbol))
+	for _, c := range input.CandidateCoins {
+		if strings.EqualFold(strings.TrimSpace(c.Symbol), symbol) {
+			return true
+		}
+	}
+	for _, p := range input.Positions {
+		if strings.EqualFold(strings.TrimSpace(p.Symbol), symbol) {
+			return true
+		}
+	}
+	return false
+}
+
 func condPerf(p *PerformanceView) *PerformanceView {
 	if p != nil {
 		return p
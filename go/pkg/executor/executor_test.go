@@ -3,10 +3,12 @@ package executor
 import (
 	"context"
 	"path/filepath"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"nof0-api/pkg/llm"
+	"nof0-api/pkg/market"
 )
 
 // fakeLLM returns a fixed structured decision matching the contract.
@@ -80,4 +82,187 @@ func TestExecutor_GetFullDecision(t *testing.T) {
 	assert.Equal(t, "BTC", d.Symbol, "symbol should be BTC")
 	assert.GreaterOrEqual(t, d.Confidence, 75, "confidence should be >= 75")
 	assert.NotEmpty(t, out.UserPrompt, "UserPrompt should be populated")
+	assert.Equal(t, 150, out.Usage.TotalTokens, "LLM usage should be passed through")
+}
+
+func TestExecutor_SymbolUniverse(t *testing.T) {
+	templatePath := filepath.Join("..", "..", "etc", "prompts", "executor", "default_prompt.tmpl")
+	newCfg := func(mode SymbolUniverseMode) *Config {
+		return &Config{
+			MajorCoinLeverage:      20,
+			AltcoinLeverage:        10,
+			MinConfidence:          75,
+			MinRiskReward:          3.0,
+			MaxPositions:           4,
+			DecisionIntervalRaw:    "3m",
+			DecisionTimeoutRaw:     "60s",
+			MaxConcurrentDecisions: 1,
+			SymbolUniverse:         mode,
+		}
+	}
+	// fakeLLM always proposes BTC, which is not among these candidates.
+	ctx := &Context{
+		CurrentTime:    "2025-01-01T00:00:00Z",
+		CandidateCoins: []CandidateCoin{{Symbol: "ETH"}, {Symbol: "SOL"}},
+	}
+
+	lenient, err := NewExecutor(newCfg(SymbolUniverseLenient), &fakeLLM{}, templatePath, "")
+	assert.NoError(t, err, "NewExecutor should not error")
+	out, err := lenient.GetFullDecision(ctx)
+	assert.NoError(t, err, "lenient mode should not error")
+	assert.Empty(t, out.Decisions, "off-universe decision should be dropped")
+	assert.Equal(t, 150, out.Usage.TotalTokens, "dropped decisions still report usage")
+
+	strict, err := NewExecutor(newCfg(SymbolUniverseStrict), &fakeLLM{}, templatePath, "")
+	assert.NoError(t, err, "NewExecutor should not error")
+	out, err = strict.GetFullDecision(ctx)
+	assert.Error(t, err, "strict mode should reject off-universe decision")
+	assert.Empty(t, out.Decisions, "a rejected decision must not be handed back for execution")
+
+	ctx.CandidateCoins = append(ctx.CandidateCoins, CandidateCoin{Symbol: "BTC"})
+	out, err = strict.GetFullDecision(ctx)
+	assert.NoError(t, err, "in-universe decision should pass strict mode")
+	assert.Len(t, out.Decisions, 1, "in-universe decision should be kept")
+}
+
+// sparseLLM answers with only the essential fields and records the schema it
+// was asked to satisfy.
+type sparseLLM struct {
+	fakeLLM
+	required []string
+}
+
+func (s *sparseLLM) ChatStructured(_ context.Context, _ *llm.ChatRequest, target interface{}) (*llm.ChatResponse, error) {
+	schema, err := llm.GenerateSchema(target)
+	if err != nil {
+		return nil, err
+	}
+	s.required, _ = schema["required"].([]string)
+	jsonStr := `{"signal":"buy_to_enter","symbol":"BTC","position_size_usd":200,"stop_loss":95,"take_profit":115}`
+	if err := llm.ParseStructured(jsonStr, target); err != nil {
+		return nil, err
+	}
+	return &llm.ChatResponse{
+		Model:   "test-model",
+		Choices: []llm.Choice{{Message: llm.Message{Role: "assistant", Content: jsonStr}}},
+	}, nil
+}
+
+func TestExecutor_MinimalDecisionSchema(t *testing.T) {
+	templatePath := filepath.Join("..", "..", "etc", "prompts", "executor", "default_prompt.tmpl")
+	newCfg := func(mode DecisionSchemaMode) *Config {
+		return &Config{
+			MajorCoinLeverage:      20,
+			AltcoinLeverage:        10,
+			MinConfidence:          75,
+			MinRiskReward:          3.0,
+			MaxPositions:           4,
+			DecisionIntervalRaw:    "3m",
+			DecisionTimeoutRaw:     "60s",
+			MaxConcurrentDecisions: 1,
+			DecisionSchema:         mode,
+		}
+	}
+	ctx := &Context{
+		CurrentTime:   "2025-01-01T00:00:00Z",
+		MarketDataMap: map[string]*market.Snapshot{"BTC": {Symbol: "BTC", Price: market.PriceInfo{Last: 100}}},
+	}
+
+	full := &sparseLLM{}
+	exec, err := NewExecutor(newCfg(DecisionSchemaFull), full, templatePath, "")
+	assert.NoError(t, err, "NewExecutor should not error")
+	_, err = exec.GetFullDecision(ctx)
+	assert.Error(t, err, "the full schema path should reject a response missing fields")
+	assert.Contains(t, full.required, "confidence", "the full schema requires every field")
+
+	minimal := &sparseLLM{}
+	exec, err = NewExecutor(newCfg(DecisionSchemaMinimal), minimal, templatePath, "")
+	assert.NoError(t, err, "NewExecutor should not error")
+	out, err := exec.GetFullDecision(ctx)
+	assert.NoError(t, err, "the minimal schema path should produce a valid decision")
+	assert.ElementsMatch(t, []string{"signal", "symbol"}, minimal.required, "only essential fields should be required")
+	if assert.Len(t, out.Decisions, 1) {
+		d := out.Decisions[0]
+		assert.Equal(t, "open_long", d.Action)
+		assert.Equal(t, 20, d.Leverage, "leverage defaults to the major coin cap")
+		assert.InDelta(t, 100, d.EntryPrice, 1e-9, "entry defaults to the latest price")
+		assert.Equal(t, 75, d.Confidence, "confidence defaults to the configured minimum")
+		assert.InDelta(t, 10, d.RiskUSD, 1e-9, "risk is derived from the stop distance")
+	}
+}
+
+// versionedLLM answers with the fakeLLM decision declaring version.
+type versionedLLM struct {
+	fakeLLM
+	version    string
+	takeProfit float64 // defaults to 115
+}
+
+func (v *versionedLLM) ChatStructured(_ context.Context, _ *llm.ChatRequest, target interface{}) (*llm.ChatResponse, error) {
+	tp := v.takeProfit
+	if tp == 0 {
+		tp = 115
+	}
+	jsonStr := `{"schema_version":"` + v.version + `","signal":"buy_to_enter","symbol":"BTC","leverage":5,"position_size_usd":200,` +
+		`"entry_price":100,"stop_loss":95,"take_profit":` + strconv.FormatFloat(tp, 'f', -1, 64) + `,"risk_usd":10,"confidence":90,"invalidation_condition":"below EMA20","reasoning":"clear uptrend"}`
+	if err := llm.ParseStructured(jsonStr, target); err != nil {
+		return nil, err
+	}
+	return &llm.ChatResponse{
+		Model:   "test-model",
+		Choices: []llm.Choice{{Message: llm.Message{Role: "assistant", Content: jsonStr}}},
+	}, nil
+}
+
+func TestExecutor_SchemaVersion(t *testing.T) {
+	templatePath := filepath.Join("..", "..", "etc", "prompts", "executor", "default_prompt.tmpl")
+	cfg := &Config{
+		MajorCoinLeverage:      20,
+		AltcoinLeverage:        10,
+		MinConfidence:          75,
+		MinRiskReward:          3.0,
+		MaxPositions:           4,
+		DecisionIntervalRaw:    "3m",
+		DecisionTimeoutRaw:     "60s",
+		MaxConcurrentDecisions: 1,
+	}
+	for _, version := range []string{DecisionSchemaVersion, ""} {
+		exec, err := NewExecutor(cfg, &versionedLLM{version: version}, templatePath, "")
+		assert.NoError(t, err, "NewExecutor should not error")
+		out, err := exec.GetFullDecision(&Context{CurrentTime: "2025-01-01T00:00:00Z"})
+		assert.NoError(t, err, "schema_version %q should be accepted", version)
+		assert.Len(t, out.Decisions, 1)
+	}
+
+	exec, err := NewExecutor(cfg, &versionedLLM{version: "2"}, templatePath, "")
+	assert.NoError(t, err, "NewExecutor should not error")
+	out, err := exec.GetFullDecision(&Context{CurrentTime: "2025-01-01T00:00:00Z"})
+	assert.ErrorContains(t, err, `schema_version "2" unsupported`)
+	assert.Empty(t, out.Decisions, "a mismatched contract yields no decisions")
+}
+
+func TestConfigValidateDecisionSchema(t *testing.T) {
+	cfg := &Config{MajorCoinLeverage: 20, AltcoinLeverage: 10, MinRiskReward: 3, MaxPositions: 4, DecisionSchema: "tiny"}
+	assert.ErrorContains(t, cfg.Validate(), "decision_schema")
+}
+
+func TestExecutor_InvalidDecisionIsNotReturned(t *testing.T) {
+	templatePath := filepath.Join("..", "..", "etc", "prompts", "executor", "default_prompt.tmpl")
+	cfg := &Config{
+		MajorCoinLeverage:      20,
+		AltcoinLeverage:        10,
+		MinConfidence:          75,
+		MinRiskReward:          3.0,
+		MaxPositions:           4,
+		DecisionIntervalRaw:    "3m",
+		DecisionTimeoutRaw:     "60s",
+		MaxConcurrentDecisions: 1,
+	}
+	// A 95 stop and 101 target on a 100 long misses the 3:1 floor.
+	exec, err := NewExecutor(cfg, &versionedLLM{version: DecisionSchemaVersion, takeProfit: 101}, templatePath, "")
+	assert.NoError(t, err, "NewExecutor should not error")
+	out, err := exec.GetFullDecision(&Context{CurrentTime: "2025-01-01T00:00:00Z"})
+	assert.ErrorContains(t, err, "risk")
+	assert.Empty(t, out.Decisions, "a decision failing validation is not handed back for execution")
+	assert.NotEmpty(t, out.UserPrompt, "the prompt is still reported for journaling")
 }
@@ -0,0 +1,156 @@
+package executor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+
+	"nof0-api/pkg/market"
+)
+
+// MaterialInputs is the part of a decision Context that can change what the
+// model decides: account state, positions, candidates, market data and the
+// risk caps. Clock fields (CurrentTime, RuntimeMinutes, CallCount, position
+// update times) are left out, so two cycles over the same state hash alike
+// and differing decisions between them point at model nondeterminism.
+type MaterialInputs struct {
+	Account     MaterialAccount    `json:"account"`
+	Positions   []MaterialPosition `json:"positions,omitempty"`
+	Candidates  []string           `json:"candidates,omitempty"`
+	Market      []MaterialMarket   `json:"market,omitempty"`
+	Performance *MaterialPerf      `json:"performance,omitempty"`
+
+	MajorCoinLeverage  int     `json:"major_coin_leverage,omitempty"`
+	AltcoinLeverage    int     `json:"altcoin_leverage,omitempty"`
+	MaxRiskPct         float64 `json:"max_risk_pct,omitempty"`
+	MaxPositionSizeUSD float64 `json:"max_position_size_usd,omitempty"`
+}
+
+// MaterialAccount holds account balances rounded to cents.
+type MaterialAccount struct {
+	TotalEquity      float64 `json:"total_equity"`
+	AvailableBalance float64 `json:"available_balance"`
+	MarginUsed       float64 `json:"margin_used"`
+}
+
+// MaterialPosition identifies an open position by size and entry.
+type MaterialPosition struct {
+	Symbol     string  `json:"symbol"`
+	Side       string  `json:"side"`
+	Quantity   float64 `json:"quantity"`
+	EntryPrice float64 `json:"entry_price"`
+	Leverage   int     `json:"leverage"`
+}
+
+// MaterialMarket is the per-symbol market data rendered into the prompt.
+type MaterialMarket struct {
+	Symbol      string             `json:"symbol"`
+	Price       float64            `json:"price"`
+	Change1h    float64            `json:"change_1h"`
+	Change4h    float64            `json:"change_4h"`
+	EMA         map[string]float64 `json:"ema,omitempty"`
+	MACD        float64            `json:"macd,omitempty"`
+	RSI         map[string]float64 `json:"rsi,omitempty"`
+	FundingRate float64            `json:"funding_rate,omitempty"`
+	OpenInt     float64            `json:"open_interest,omitempty"`
+}
+
+// MaterialPerf is the performance feedback shown to the model.
+type MaterialPerf struct {
+	SharpeRatio float64 `json:"sharpe_ratio"`
+	WinRate     float64 `json:"win_rate"`
+	TotalTrades int     `json:"total_trades"`
+}
+
+// MaterialInputsOf extracts the material inputs of ctx in a canonical order.
+func MaterialInputsOf(ctx *Context) MaterialInputs {
+	var in MaterialInputs
+	if ctx == nil {
+		return in
+	}
+	in.Account = MaterialAccount{
+		TotalEquity:      roundCents(ctx.Account.TotalEquity),
+		AvailableBalance: roundCents(ctx.Account.AvailableBalance),
+		MarginUsed:       roundCents(ctx.Account.MarginUsed),
+	}
+	for _, p := range ctx.Positions {
+		in.Positions = append(in.Positions, MaterialPosition{
+			Symbol:     strings.ToUpper(strings.TrimSpace(p.Symbol)),
+			Side:       strings.ToLower(strings.TrimSpace(p.Side)),
+			Quantity:   p.Quantity,
+			EntryPrice: p.EntryPrice,
+			Leverage:   p.Leverage,
+		})
+	}
+	sort.Slice(in.Positions, func(i, j int) bool {
+		if in.Positions[i].Symbol != in.Positions[j].Symbol {
+			return in.Positions[i].Symbol < in.Positions[j].Symbol
+		}
+		return in.Positions[i].Side < in.Positions[j].Side
+	})
+	// Candidate order is the ranking the model sees, so it is kept.
+	for _, c := range ctx.CandidateCoins {
+		in.Candidates = append(in.Candidates, strings.ToUpper(strings.TrimSpace(c.Symbol)))
+	}
+	for sym, snap := range ctx.MarketDataMap {
+		if snap == nil {
+			continue
+		}
+		in.Market = append(in.Market, materialMarket(strings.ToUpper(strings.TrimSpace(sym)), snap))
+	}
+	sort.Slice(in.Market, func(i, j int) bool { return in.Market[i].Symbol < in.Market[j].Symbol })
+	if ctx.Performance != nil {
+		in.Performance = &MaterialPerf{
+			SharpeRatio: ctx.Performance.SharpeRatio,
+			WinRate:     ctx.Performance.WinRate,
+			TotalTrades: ctx.Performance.TotalTrades,
+		}
+	}
+	in.MajorCoinLeverage = ctx.MajorCoinLeverage
+	in.AltcoinLeverage = ctx.AltcoinLeverage
+	in.MaxRiskPct = ctx.MaxRiskPct
+	in.MaxPositionSizeUSD = ctx.MaxPositionSizeUSD
+	return in
+}
+
+// materialMarket extracts the prompt-visible market data of snap.
+func materialMarket(symbol string, snap *market.Snapshot) MaterialMarket {
+	mm := MaterialMarket{
+		Symbol:   symbol,
+		Price:    snap.Price.Last,
+		Change1h: snap.Change.OneHour,
+		Change4h: snap.Change.FourHour,
+		EMA:      snap.Indicators.EMA,
+		MACD:     snap.Indicators.MACD,
+		RSI:      snap.Indicators.RSI,
+	}
+	if snap.Funding != nil {
+		mm.FundingRate = snap.Funding.Rate
+	}
+	if snap.OpenInterest != nil {
+		mm.OpenInt = snap.OpenInterest.Latest
+	}
+	return mm
+}
+
+// Hash returns the hex SHA-256 of the canonical JSON encoding of in.
+func (in MaterialInputs) Hash() string {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// InputHash is shorthand for MaterialInputsOf(ctx).Hash().
+func InputHash(ctx *Context) string {
+	return MaterialInputsOf(ctx).Hash()
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
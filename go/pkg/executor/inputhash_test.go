@@ -0,0 +1,59 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"nof0-api/pkg/market"
+)
+
+func hashTestContext() *Context {
+	return &Context{
+		CurrentTime:    "2025-01-01 00:00:00",
+		RuntimeMinutes: 5,
+		CallCount:      1,
+		Account:        AccountInfo{TotalEquity: 1000.001, AvailableBalance: 800, MarginUsed: 200},
+		Positions: []PositionInfo{
+			{Symbol: "ETH", Side: "short", Quantity: 0.5, EntryPrice: 3000, Leverage: 3, MarkPrice: 2990, UpdateTime: 1},
+			{Symbol: "BTC", Side: "long", Quantity: 0.01, EntryPrice: 60000, Leverage: 5, MarkPrice: 60100, UpdateTime: 1},
+		},
+		CandidateCoins: []CandidateCoin{{Symbol: "SOL"}, {Symbol: "BTC"}},
+		MarketDataMap: map[string]*market.Snapshot{
+			"BTC": {Symbol: "BTC", Price: market.PriceInfo{Last: 60100}, Indicators: market.IndicatorInfo{RSI: map[string]float64{"RSI7": 55}}},
+			"SOL": {Symbol: "SOL", Price: market.PriceInfo{Last: 150}, Funding: &market.FundingInfo{Rate: 0.0001}},
+		},
+		MajorCoinLeverage: 5,
+		AltcoinLeverage:   3,
+	}
+}
+
+func TestInputHashIgnoresClockFields(t *testing.T) {
+	a := hashTestContext()
+	b := hashTestContext()
+	b.CurrentTime = "2025-01-01 00:03:00"
+	b.RuntimeMinutes = 8
+	b.CallCount = 2
+	b.Account.TotalEquity = 1000.0004 // same to the cent
+	b.Positions[0], b.Positions[1] = b.Positions[1], b.Positions[0]
+	b.Positions[0].UpdateTime = 99
+	b.Positions[0].MarkPrice = 60200
+	assert.Equal(t, InputHash(a), InputHash(b), "identical material inputs should hash alike")
+	assert.Len(t, InputHash(a), 64)
+}
+
+func TestInputHashChangesWithMaterialInputs(t *testing.T) {
+	base := InputHash(hashTestContext())
+
+	changed := hashTestContext()
+	changed.Positions[0].Quantity = 0.6
+	assert.NotEqual(t, base, InputHash(changed), "a changed position should change the hash")
+
+	changed = hashTestContext()
+	changed.MarketDataMap["BTC"].Price.Last = 60200
+	assert.NotEqual(t, base, InputHash(changed), "a changed price should change the hash")
+
+	changed = hashTestContext()
+	changed.CandidateCoins[0], changed.CandidateCoins[1] = changed.CandidateCoins[1], changed.CandidateCoins[0]
+	assert.NotEqual(t, base, InputHash(changed), "candidate ranking is material")
+}
@@ -21,6 +21,9 @@ type ConversationRecord struct {
 	ModelName        string
 	Timestamp        time.Time
 	Topic            string
+	// CycleID links the conversation to a decision cycle; empty for
+	// standalone executor calls.
+	CycleID string
 }
 
 type noopConversationRecorder struct{}
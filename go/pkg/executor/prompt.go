@@ -17,6 +17,8 @@ type PromptInputs struct {
 	PerformanceView string
 	CandidateCoins  string
 	MarketSnapshots string
+	// Sections holds the pipeline output rendered by {{ .ContextSections }}.
+	Sections []RenderedSection
 }
 
 // PromptRenderer renders the executor system prompt from a template file.
@@ -18,17 +18,30 @@ func buildPromptInputs(cfg *Config, ctx *Context) PromptInputs {
 		current = now
 	}
 
-	return PromptInputs{
+	inputs := PromptInputs{
 		CurrentTime:     current,
 		RuntimeMinutes:  ctx.RuntimeMinutes,
 		SharpeRatio:     safePerf(ctx.Performance).SharpeRatio,
 		AccountOverview: formatAccount(ctx.Account),
 		OpenPositions:   formatPositions(ctx.Positions),
-		RiskBudget:      formatRiskBudget(cfg, ctx),
-		PerformanceView: formatPerformance(ctx.Performance),
-		CandidateCoins:  formatCandidates(ctx.CandidateCoins),
-		MarketSnapshots: formatMarketJSON(ctx.MarketDataMap),
+		CandidateCoins:  formatCandidateSymbols(ctx.CandidateCoins),
 	}
+	// Disabled sections stay empty so the template omits them entirely.
+	sections := cfg.PromptSections
+	if sectionEnabled(sections.IncludeRiskBudget) {
+		inputs.RiskBudget = formatRiskBudget(cfg, ctx)
+	}
+	if sectionEnabled(sections.IncludePerformance) {
+		inputs.PerformanceView = formatPerformance(ctx.Performance)
+	}
+	if sectionEnabled(sections.IncludeCandidatesDetail) {
+		inputs.CandidateCoins = formatCandidates(ctx.CandidateCoins)
+	}
+	if sectionEnabled(sections.IncludeMarketSnapshots) {
+		inputs.MarketSnapshots = formatMarketJSON(ctx.MarketDataMap)
+	}
+	inputs.Sections = buildPromptSections(cfg, ctx)
+	return inputs
 }
 
 func formatAccount(a AccountInfo) string {
@@ -65,6 +78,19 @@ func formatCandidates(cands []CandidateCoin) string {
 	return strings.Join(items, ", ")
 }
 
+// formatCandidateSymbols lists candidate symbols without their source tags.
+func formatCandidateSymbols(cands []CandidateCoin) string {
+	if len(cands) == 0 {
+		return "(none)"
+	}
+	items := make([]string, 0, len(cands))
+	for _, c := range cands {
+		items = append(items, c.Symbol)
+	}
+	sort.Strings(items)
+	return strings.Join(items, ", ")
+}
+
 func formatPerformance(p *PerformanceView) string {
 	if p == nil {
 		return "(n/a)"
@@ -0,0 +1,195 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// PromptSection produces one titled block of the prompt's context area.
+// Build returning an empty string omits the block.
+type PromptSection struct {
+	Name  string
+	Title string
+	Build func(cfg *Config, ctx *Context) string
+}
+
+// PromptSectionConfig places a registered section in the prompt pipeline.
+type PromptSectionConfig struct {
+	Name    string `yaml:"name"`
+	Source  string `yaml:"source,omitempty"` // registered section to render; defaults to Name
+	Title   string `yaml:"title,omitempty"`  // overrides the section's title
+	Enabled *bool  `yaml:"enabled,omitempty"`
+}
+
+// RenderedSection is a built section ready for template assembly.
+type RenderedSection struct {
+	Name  string
+	Title string
+	Body  string
+}
+
+// defaultPromptPipeline mirrors the historical layout of the context block.
+var defaultPromptPipeline = []string{"account", "positions", "risk_budget", "performance", "candidates", "market"}
+
+var (
+	promptSectionsMu      sync.RWMutex
+	promptSectionRegistry = map[string]PromptSection{
+		"account": {Name: "account", Title: "ACCOUNT:", Build: func(_ *Config, ctx *Context) string {
+			return formatAccount(ctx.Account)
+		}},
+		"positions": {Name: "positions", Title: "OPEN_POSITIONS:", Build: func(_ *Config, ctx *Context) string {
+			return formatPositions(ctx.Positions)
+		}},
+		"risk_budget": {Name: "risk_budget", Title: "RISK_BUDGET:", Build: func(cfg *Config, ctx *Context) string {
+			if !sectionEnabled(cfg.PromptSections.IncludeRiskBudget) {
+				return ""
+			}
+			return formatRiskBudget(cfg, ctx)
+		}},
+		"performance": {Name: "performance", Title: "PERFORMANCE_VIEW:", Build: func(cfg *Config, ctx *Context) string {
+			if !sectionEnabled(cfg.PromptSections.IncludePerformance) {
+				return ""
+			}
+			return formatPerformance(ctx.Performance)
+		}},
+		"candidates": {Name: "candidates", Title: "CANDIDATE_COINS:", Build: func(cfg *Config, ctx *Context) string {
+			if sectionEnabled(cfg.PromptSections.IncludeCandidatesDetail) {
+				return formatCandidates(ctx.CandidateCoins)
+			}
+			return formatCandidateSymbols(ctx.CandidateCoins)
+		}},
+		"market": {Name: "market", Title: marketSectionTitle, Build: func(cfg *Config, ctx *Context) string {
+			if !sectionEnabled(cfg.PromptSections.IncludeMarketSnapshots) {
+				return ""
+			}
+			return formatMarketJSON(ctx.MarketDataMap)
+		}},
+	}
+)
+
+const marketSectionTitle = "MARKET_SNAPSHOTS (JSON; change_* values are fractional ratios, e.g. 0.01 = 1%, funding is also fractional):"
+
+// RegisterPromptSection adds or replaces a named section producer. Register
+// custom sections before loading configs that reference them.
+func RegisterPromptSection(s PromptSection) error {
+	name := strings.TrimSpace(s.Name)
+	if name == "" {
+		return errors.New("executor: prompt section requires a name")
+	}
+	if s.Build == nil {
+		return errors.New("executor: prompt section requires a Build func")
+	}
+	s.Name = name
+	promptSectionsMu.Lock()
+	promptSectionRegistry[name] = s
+	promptSectionsMu.Unlock()
+	return nil
+}
+
+func lookupPromptSection(name string) (PromptSection, bool) {
+	promptSectionsMu.RLock()
+	defer promptSectionsMu.RUnlock()
+	s, ok := promptSectionRegistry[name]
+	return s, ok
+}
+
+// promptPipeline returns the configured section order, or the default layout.
+func (c *Config) promptPipeline() []PromptSectionConfig {
+	if len(c.PromptPipeline) > 0 {
+		return c.PromptPipeline
+	}
+	out := make([]PromptSectionConfig, 0, len(defaultPromptPipeline))
+	for _, name := range defaultPromptPipeline {
+		out = append(out, PromptSectionConfig{Name: name})
+	}
+	return out
+}
+
+// buildPromptSections runs the pipeline in order, skipping disabled and empty sections.
+func buildPromptSections(cfg *Config, ctx *Context) []RenderedSection {
+	pipeline := cfg.promptPipeline()
+	out := make([]RenderedSection, 0, len(pipeline))
+	for _, entry := range pipeline {
+		if !sectionEnabled(entry.Enabled) {
+			continue
+		}
+		source := entry.Source
+		if source == "" {
+			source = entry.Name
+		}
+		section, ok := lookupPromptSection(source)
+		if !ok {
+			continue
+		}
+		body := section.Build(cfg, ctx)
+		if strings.TrimSpace(body) == "" {
+			continue
+		}
+		title := section.Title
+		if entry.Title != "" {
+			title = entry.Title
+		}
+		out = append(out, RenderedSection{Name: entry.Name, Title: title, Body: body})
+	}
+	return out
+}
+
+// ContextSections assembles the context block for templates. Inputs built
+// without the pipeline fall back to the individual fields in default order.
+func (in PromptInputs) ContextSections() string {
+	sections := in.Sections
+	if len(sections) == 0 {
+		sections = in.fieldSections()
+	}
+	blocks := make([]string, 0, len(sections))
+	for _, s := range sections {
+		if s.Title == "" {
+			blocks = append(blocks, s.Body)
+			continue
+		}
+		blocks = append(blocks, s.Title+"\n"+s.Body)
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+func (in PromptInputs) fieldSections() []RenderedSection {
+	fields := []RenderedSection{
+		{Name: "account", Title: "ACCOUNT:", Body: in.AccountOverview},
+		{Name: "positions", Title: "OPEN_POSITIONS:", Body: in.OpenPositions},
+		{Name: "risk_budget", Title: "RISK_BUDGET:", Body: in.RiskBudget},
+		{Name: "performance", Title: "PERFORMANCE_VIEW:", Body: in.PerformanceView},
+		{Name: "candidates", Title: "CANDIDATE_COINS:", Body: in.CandidateCoins},
+		{Name: "market", Title: marketSectionTitle, Body: in.MarketSnapshots},
+	}
+	out := fields[:0]
+	for _, f := range fields {
+		if strings.TrimSpace(f.Body) != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// validatePromptPipeline rejects unknown sources and duplicate section names.
+func (c *Config) validatePromptPipeline() error {
+	seen := make(map[string]struct{}, len(c.PromptPipeline))
+	for i, entry := range c.PromptPipeline {
+		if entry.Name == "" {
+			return fmt.Errorf("executor config: prompt_pipeline[%d] requires a name", i)
+		}
+		if _, ok := seen[entry.Name]; ok {
+			return fmt.Errorf("executor config: prompt_pipeline contains duplicate section %q", entry.Name)
+		}
+		seen[entry.Name] = struct{}{}
+		source := entry.Source
+		if source == "" {
+			source = entry.Name
+		}
+		if _, ok := lookupPromptSection(source); !ok {
+			return fmt.Errorf("executor config: prompt_pipeline[%d] references unknown section %q", i, source)
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package executor
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -73,3 +74,99 @@ func TestPromptRendererEmptyPath(t *testing.T) {
 	_, err := NewPromptRenderer(cfg, " ")
 	assert.Error(t, err, "NewPromptRenderer should error for empty template path")
 }
+
+func TestPromptSectionsToggle(t *testing.T) {
+	templatePath := filepath.Join("..", "..", "etc", "prompts", "executor", "default_prompt.tmpl")
+	disabled := false
+	cfg := &Config{
+		MajorCoinLeverage:      20,
+		AltcoinLeverage:        8,
+		MinConfidence:          75,
+		MinRiskReward:          3.0,
+		MaxPositions:           3,
+		DecisionIntervalRaw:    "3m",
+		DecisionTimeoutRaw:     "60s",
+		MaxConcurrentDecisions: 1,
+		PromptSections: PromptSections{
+			IncludePerformance:      &disabled,
+			IncludeCandidatesDetail: &disabled,
+		},
+	}
+	renderer, err := NewPromptRenderer(cfg, templatePath)
+	assert.NoError(t, err, "NewPromptRenderer should not error")
+
+	ctx := &Context{
+		CandidateCoins: []CandidateCoin{{Symbol: "BTC", Sources: []string{"volume"}}},
+		Performance:    &PerformanceView{SharpeRatio: 1.2},
+	}
+	out, err := renderer.Render(buildPromptInputs(cfg, ctx))
+	assert.NoError(t, err, "Render should not error")
+
+	assert.NotContains(t, out, "PERFORMANCE_VIEW:", "disabled performance section should be omitted")
+	assert.NotContains(t, out, "ROLLING_SHARPE:", "sharpe belongs to the performance section")
+	assert.NotContains(t, out, "BTC [volume]", "candidate sources should be omitted without detail")
+	assert.Contains(t, out, "CANDIDATE_COINS:\nBTC", "candidate symbols should remain")
+	assert.Contains(t, out, "RISK_BUDGET:", "enabled sections should remain")
+	assert.Contains(t, out, "MARKET_SNAPSHOTS", "enabled sections should remain")
+	assert.Contains(t, out, "OPEN_POSITIONS:", "core sections should remain")
+}
+
+func TestPromptPipelineReorder(t *testing.T) {
+	templatePath := filepath.Join("..", "..", "etc", "prompts", "executor", "default_prompt.tmpl")
+	cfg := &Config{
+		MajorCoinLeverage:      20,
+		AltcoinLeverage:        8,
+		MinConfidence:          75,
+		MinRiskReward:          3.0,
+		MaxPositions:           3,
+		DecisionIntervalRaw:    "3m",
+		DecisionTimeoutRaw:     "60s",
+		MaxConcurrentDecisions: 1,
+	}
+	renderer, err := NewPromptRenderer(cfg, templatePath)
+	assert.NoError(t, err, "NewPromptRenderer should not error")
+	ctx := &Context{CandidateCoins: []CandidateCoin{{Symbol: "BTC"}}}
+
+	out, err := renderer.Render(buildPromptInputs(cfg, ctx))
+	assert.NoError(t, err, "Render should not error")
+	assert.Less(t, strings.Index(out, "ACCOUNT:"), strings.Index(out, "CANDIDATE_COINS:"), "default layout puts account first")
+
+	disabled := false
+	cfg.PromptPipeline = []PromptSectionConfig{
+		{Name: "candidates"},
+		{Name: "positions", Title: "POSITIONS:"},
+		{Name: "account"},
+		{Name: "market", Enabled: &disabled},
+	}
+	assert.NoError(t, cfg.validatePromptPipeline(), "pipeline should validate")
+	out, err = renderer.Render(buildPromptInputs(cfg, ctx))
+	assert.NoError(t, err, "Render should not error")
+
+	candidates := strings.Index(out, "CANDIDATE_COINS:\nBTC")
+	positions := strings.Index(out, "POSITIONS:\n(none)")
+	account := strings.Index(out, "ACCOUNT:")
+	assert.True(t, candidates >= 0 && positions > candidates && account > positions, "sections should render in configured order")
+	assert.NotContains(t, out, "OPEN_POSITIONS:", "title override should replace the default title")
+	assert.NotContains(t, out, "MARKET_SNAPSHOTS", "disabled sections should be omitted")
+	assert.NotContains(t, out, "RISK_BUDGET:", "sections missing from the pipeline should be omitted")
+}
+
+func TestPromptPipelineCustomSection(t *testing.T) {
+	err := RegisterPromptSection(PromptSection{
+		Name:  "test_watchlist",
+		Title: "WATCHLIST:",
+		Build: func(_ *Config, ctx *Context) string { return strings.ToLower(ctx.CandidateCoins[0].Symbol) },
+	})
+	assert.NoError(t, err, "RegisterPromptSection should not error")
+	assert.Error(t, RegisterPromptSection(PromptSection{Name: "empty"}), "sections require a Build func")
+
+	cfg := &Config{PromptPipeline: []PromptSectionConfig{{Name: "candidates", Source: "test_watchlist"}}}
+	assert.NoError(t, cfg.validatePromptPipeline(), "registered source should validate")
+	inputs := buildPromptInputs(cfg, &Context{CandidateCoins: []CandidateCoin{{Symbol: "ETH"}}})
+	assert.Equal(t, "WATCHLIST:\neth", inputs.ContextSections(), "source should replace the section producer")
+
+	cfg.PromptPipeline = []PromptSectionConfig{{Name: "missing"}}
+	assert.ErrorContains(t, cfg.validatePromptPipeline(), `unknown section "missing"`)
+	cfg.PromptPipeline = []PromptSectionConfig{{Name: "account"}, {Name: "account"}}
+	assert.ErrorContains(t, cfg.validatePromptPipeline(), `duplicate section "account"`)
+}
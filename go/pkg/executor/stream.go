@@ -0,0 +1,124 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"nof0-api/pkg/llm"
+)
+
+// streamProgressInterval spaces the progress log lines of a streamed decision.
+const streamProgressInterval = 5 * time.Second
+
+// streamInstruction asks the model to reason in the open before committing to
+// the decision, so the reasoning can be captured as it streams.
+const streamInstruction = "Think through the decision step by step in plain text. " +
+	"Then end your answer with exactly one JSON object, and nothing after it, matching this schema:\n"
+
+// chatStream streams the decision completion, accumulating the model's
+// reasoning as it arrives, then decodes the trailing JSON object into target.
+// It returns the response (content set to that JSON object) and the reasoning
+// that preceded it. When ctx expires mid-stream the reasoning received so far
+// is still returned with the error.
+func (e *BasicExecutor) chatStream(ctx context.Context, req *llm.ChatRequest, target any) (*llm.ChatResponse, string, error) {
+	schema, err := llm.GenerateSchema(target)
+	if err != nil {
+		return nil, "", err
+	}
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return nil, "", err
+	}
+	streamReq := *req
+	streamReq.Messages = append(append([]llm.Message(nil), req.Messages...),
+		llm.Message{Role: "user", Content: streamInstruction + string(schemaJSON)})
+
+	stream, err := e.llm.ChatStream(ctx, &streamReq)
+	if err != nil {
+		return nil, "", err
+	}
+	if stream == nil {
+		return nil, "", errors.New("executor: llm client returned no stream")
+	}
+
+	var (
+		text     strings.Builder
+		resp     = &llm.ChatResponse{}
+		chunks   int
+		start    = time.Now()
+		lastLog  = start
+		finished bool
+	)
+	for !finished {
+		select {
+		case <-ctx.Done():
+			return resp, strings.TrimSpace(text.String()), fmt.Errorf("executor: decision stream interrupted after %d chunks: %w", chunks, ctx.Err())
+		case chunk, ok := <-stream:
+			if !ok {
+				finished = true
+				break
+			}
+			chunks++
+			if chunk.ID != "" {
+				resp.ID = chunk.ID
+			}
+			if chunk.Model != "" {
+				resp.Model = chunk.Model
+			}
+			if chunk.Usage != nil {
+				resp.Usage = *chunk.Usage
+			}
+			for _, c := range chunk.Choices {
+				text.WriteString(c.Delta.Content)
+			}
+			if now := time.Now(); now.Sub(lastLog) >= streamProgressInterval {
+				lastLog = now
+				logx.WithContext(ctx).Infof("executor: decision streaming chunks=%d chars=%d elapsed=%s", chunks, text.Len(), now.Sub(start).Round(time.Millisecond))
+			}
+		}
+	}
+	// The client closes the stream when ctx is cancelled as well as on completion.
+	if err := ctx.Err(); err != nil {
+		return resp, strings.TrimSpace(text.String()), fmt.Errorf("executor: decision stream interrupted after %d chunks: %w", chunks, err)
+	}
+
+	cot, payload, ok := splitStreamedDecision(text.String())
+	if !ok {
+		return resp, cot, fmt.Errorf("executor: streamed response after %d chunks has no decision JSON", chunks)
+	}
+	if err := llm.ParseStructured(payload, target); err != nil {
+		return resp, cot, err
+	}
+	resp.Choices = []llm.Choice{{Message: llm.Message{Role: "assistant", Content: payload}, FinishReason: "stop"}}
+	return resp, cot, nil
+}
+
+// splitStreamedDecision separates a streamed answer into the reasoning and the
+// trailing JSON object. The object is the outermost valid JSON that ends at
+// the last closing brace; a surrounding code fence is dropped.
+func splitStreamedDecision(raw string) (cot, payload string, ok bool) {
+	raw = sanitizeResponse(raw)
+	end := strings.LastIndex(raw, "}")
+	if end < 0 {
+		return raw, "", false
+	}
+	for start := strings.Index(raw, "{"); start >= 0 && start < end; {
+		if candidate := raw[start : end+1]; json.Valid([]byte(candidate)) {
+			cot = strings.TrimSpace(raw[:start])
+			cot = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(cot, "```json"), "```"))
+			return cot, candidate, true
+		}
+		next := strings.Index(raw[start+1:], "{")
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+	return raw, "", false
+}
@@ -0,0 +1,149 @@
+package executor
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/llm"
+)
+
+// streamingLLM streams its chunks one delta at a time; with hang set it
+// stops sending after the chunks and waits for ctx to expire.
+type streamingLLM struct {
+	chunks []string
+	usage  *llm.Usage
+	hang   bool
+	reqs   []*llm.ChatRequest
+}
+
+func (s *streamingLLM) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	return nil, nil
+}
+
+func (s *streamingLLM) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamResponse, error) {
+	s.reqs = append(s.reqs, req)
+	ch := make(chan llm.StreamResponse)
+	go func() {
+		defer close(ch)
+		for _, c := range s.chunks {
+			select {
+			case ch <- llm.StreamResponse{ID: "stream-1", Model: "test-model", Choices: []llm.StreamChoice{{Delta: llm.Delta{Content: c}}}}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if s.hang {
+			<-ctx.Done()
+			return
+		}
+		if s.usage != nil {
+			select {
+			case ch <- llm.StreamResponse{Usage: s.usage}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func (s *streamingLLM) ChatStructured(ctx context.Context, req *llm.ChatRequest, target interface{}) (*llm.ChatResponse, error) {
+	panic("ChatStructured must not be called in streaming mode")
+}
+
+func (s *streamingLLM) GetConfig() *llm.Config { return &llm.Config{} }
+func (s *streamingLLM) Close() error           { return nil }
+
+func newStreamingExecutor(t *testing.T, client llm.LLMClient, timeout string) *BasicExecutor {
+	t.Helper()
+	cfg := &Config{
+		MajorCoinLeverage:      20,
+		AltcoinLeverage:        10,
+		MinConfidence:          75,
+		MinRiskReward:          3.0,
+		MaxPositions:           4,
+		DecisionIntervalRaw:    "3m",
+		DecisionTimeoutRaw:     timeout,
+		MaxConcurrentDecisions: 1,
+		StreamDecisions:        true,
+	}
+	require.NoError(t, cfg.parseDurations())
+	exec, err := NewExecutor(cfg, client, filepath.Join("..", "..", "etc", "prompts", "executor", "default_prompt.tmpl"), "")
+	require.NoError(t, err)
+	return exec
+}
+
+func TestExecutor_StreamDecisionsCapturesCoT(t *testing.T) {
+	client := &streamingLLM{
+		chunks: []string{
+			"BTC is holding above EMA20 ",
+			"with rising open interest.\nA long has 3:1 reward.\n```json\n",
+			`{"signal":"buy_to_enter","symbol":"BTC","leverage":5,"position_size_usd":200,`,
+			`"entry_price":100,"stop_loss":95,"take_profit":115,"risk_usd":10,"confidence":90,`,
+			`"invalidation_condition":"below EMA20","reasoning":"clear uptrend"}` + "\n```",
+		},
+		usage: &llm.Usage{PromptTokens: 100, CompletionTokens: 80, TotalTokens: 180},
+	}
+	exec := newStreamingExecutor(t, client, "5s")
+
+	out, err := exec.GetFullDecision(&Context{CurrentTime: "2025-01-01T00:00:00Z"})
+	require.NoError(t, err)
+	assert.Equal(t, "BTC is holding above EMA20 with rising open interest.\nA long has 3:1 reward.", out.CoTTrace)
+	require.Len(t, out.Decisions, 1)
+	assert.Equal(t, "open_long", out.Decisions[0].Action)
+	assert.Equal(t, "BTC", out.Decisions[0].Symbol)
+	assert.Equal(t, 180, out.Usage.TotalTokens)
+
+	// The schema instruction follows the rendered prompt.
+	require.Len(t, client.reqs, 1)
+	msgs := client.reqs[0].Messages
+	require.Len(t, msgs, 2)
+	assert.Equal(t, "system", msgs[0].Role)
+	assert.True(t, strings.HasPrefix(msgs[1].Content, streamInstruction))
+}
+
+func TestExecutor_StreamDecisionsTimeoutKeepsPartialCoT(t *testing.T) {
+	client := &streamingLLM{chunks: []string{"Weighing BTC momentum ", "against funding..."}, hang: true}
+	exec := newStreamingExecutor(t, client, "50ms")
+
+	out, err := exec.GetFullDecision(&Context{CurrentTime: "2025-01-01T00:00:00Z"})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	require.NotNil(t, out)
+	assert.Equal(t, "Weighing BTC momentum against funding...", out.CoTTrace)
+	assert.Empty(t, out.Decisions)
+}
+
+func TestExecutor_StreamDecisionsWithoutJSON(t *testing.T) {
+	client := &streamingLLM{chunks: []string{"I would rather not trade."}}
+	exec := newStreamingExecutor(t, client, "5s")
+
+	out, err := exec.GetFullDecision(&Context{CurrentTime: "2025-01-01T00:00:00Z"})
+	assert.ErrorContains(t, err, "no decision JSON")
+	assert.Equal(t, "I would rather not trade.", out.CoTTrace)
+}
+
+func TestSplitStreamedDecision(t *testing.T) {
+	cases := []struct {
+		name, raw, cot, payload string
+		ok                      bool
+	}{
+		{name: "bare", raw: `{"a":1}`, payload: `{"a":1}`, ok: true},
+		{name: "reasoning with braces", raw: "set {x} aside then\n{\"a\":{\"b\":2}}", cot: "set {x} aside then", payload: `{"a":{"b":2}}`, ok: true},
+		{name: "fenced", raw: "why\n```json\n{\"a\":1}\n```", cot: "why", payload: `{"a":1}`, ok: true},
+		{name: "no object", raw: "nothing here", cot: "nothing here"},
+		{name: "truncated", raw: `thinking {"a":`, cot: `thinking {"a":`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cot, payload, ok := splitStreamedDecision(tc.raw)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.cot, cot)
+			assert.Equal(t, tc.payload, payload)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"nof0-api/pkg/llm"
+	"nof0-api/pkg/market"
+)
+
+// Data tools offered to the model in tool-calling mode.
+const (
+	ToolGetSnapshot  = "get_snapshot"
+	ToolGetPositions = "get_positions"
+)
+
+// defaultMaxToolIterations bounds the model turns of one tool-calling
+// decision when Config.MaxToolIterations is unset.
+const defaultMaxToolIterations = 4
+
+// ToolProvider resolves the data tools a model may call mid-reasoning. The
+// manager backs it with the trader's market and exchange providers.
+type ToolProvider interface {
+	Snapshot(ctx context.Context, symbol string) (*market.Snapshot, error)
+	Positions(ctx context.Context) ([]PositionInfo, error)
+}
+
+// WithToolProvider supplies the accessors used when Config.ToolCalling is set.
+func WithToolProvider(p ToolProvider) ExecutorOption {
+	return func(exec *BasicExecutor) {
+		exec.tools = p
+	}
+}
+
+// SetToolProvider replaces the tool accessors after construction, for
+// callers that build executors before their providers are resolved.
+func (e *BasicExecutor) SetToolProvider(p ToolProvider) { e.tools = p }
+
+// toolPosition is the get_positions view of an open position.
+type toolPosition struct {
+	Symbol        string  `json:"symbol"`
+	Side          string  `json:"side"`
+	Quantity      float64 `json:"quantity"`
+	EntryPrice    float64 `json:"entry_price"`
+	MarkPrice     float64 `json:"mark_price,omitempty"`
+	Leverage      int     `json:"leverage"`
+	UnrealizedPnL float64 `json:"unrealized_pnl"`
+}
+
+// decisionTools declares the data tools plus the final submit tool whose
+// parameters are the decision schema.
+func decisionTools(schema map[string]interface{}) []llm.Tool {
+	return []llm.Tool{
+		{Type: "function", Function: llm.FunctionDefinition{
+			Name:        ToolGetSnapshot,
+			Description: "Fetch the latest price, changes, indicators, funding and open interest for a symbol",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"symbol": map[string]interface{}{"type": "string", "description": "Exchange symbol, e.g. BTC"},
+				},
+				"required":             []string{"symbol"},
+				"additionalProperties": false,
+			},
+		}},
+		{Type: "function", Function: llm.FunctionDefinition{
+			Name:        ToolGetPositions,
+			Description: "List the account's open positions",
+			Parameters: map[string]interface{}{
+				"type":                 "object",
+				"properties":           map[string]interface{}{},
+				"additionalProperties": false,
+			},
+		}},
+		{Type: "function", Function: llm.FunctionDefinition{
+			Name:        llm.StructuredToolName,
+			Description: "Submit the final trading decision",
+			Parameters:  schema,
+		}},
+	}
+}
+
+// chatWithTools runs the tool-calling loop: data tool calls are answered from
+// e.tools and fed back until the model submits a decision. The last allowed
+// turn forces the submit tool. The returned response carries the usage of
+// every turn.
+func (e *BasicExecutor) chatWithTools(ctx context.Context, req *llm.ChatRequest, target any) (*llm.ChatResponse, error) {
+	schema, err := llm.GenerateSchema(target)
+	if err != nil {
+		return nil, err
+	}
+	maxTurns := e.cfg.MaxToolIterations
+	if maxTurns <= 0 {
+		maxTurns = defaultMaxToolIterations
+	}
+	turn := *req
+	turn.Messages = append([]llm.Message(nil), req.Messages...)
+	turn.Tools = decisionTools(schema)
+
+	var usage llm.Usage
+	var last *llm.ChatResponse
+	for i := 0; i < maxTurns; i++ {
+		turn.ToolChoice = "auto"
+		if i == maxTurns-1 {
+			turn.ToolChoice = llm.StructuredToolName
+		}
+		resp, err := e.llm.Chat(ctx, &turn)
+		if resp != nil {
+			usage = usage.Add(resp.Usage)
+			last = resp
+		}
+		if err != nil {
+			return withUsage(last, usage), err
+		}
+		if len(resp.Choices) == 0 {
+			return withUsage(resp, usage), errors.New("executor: empty tool-calling response")
+		}
+		choice := resp.Choices[0]
+		calls := choice.ToolCalls
+		if len(calls) == 0 {
+			// A plain answer is accepted when it parses as the decision.
+			if err := llm.ParseStructured(strings.TrimSpace(choice.Message.Content), target); err != nil {
+				return withUsage(resp, usage), fmt.Errorf("executor: tool-calling turn %d returned neither a tool call nor a decision: %w", i+1, err)
+			}
+			return withUsage(resp, usage), nil
+		}
+		for _, call := range calls {
+			if call.Function.Name == llm.StructuredToolName {
+				if err := llm.ParseStructured(strings.TrimSpace(call.Function.Arguments), target); err != nil {
+					return withUsage(resp, usage), fmt.Errorf("executor: parse %s arguments: %w", llm.StructuredToolName, err)
+				}
+				out := withUsage(resp, usage)
+				// Record the submitted decision as the response content.
+				out.Choices[0].Message.Content = call.Function.Arguments
+				return out, nil
+			}
+		}
+		turn.Messages = append(turn.Messages, llm.Message{Role: "assistant", Content: choice.Message.Content, ToolCalls: calls})
+		for _, call := range calls {
+			result := e.resolveTool(ctx, call)
+			logx.WithContext(ctx).Infof("executor: tool call turn=%d name=%s args=%s", i+1, call.Function.Name, call.Function.Arguments)
+			turn.Messages = append(turn.Messages, llm.Message{Role: "tool", ToolCallID: call.ID, Content: result})
+		}
+	}
+	return withUsage(last, usage), fmt.Errorf("executor: no decision submitted after %d tool-calling turns", maxTurns)
+}
+
+// resolveTool answers one data tool call as JSON. Failures are reported to
+// the model as {"error": ...} so it can recover instead of aborting the cycle.
+func (e *BasicExecutor) resolveTool(ctx context.Context, call llm.ToolCall) string {
+	var (
+		result any
+		err    error
+	)
+	switch call.Function.Name {
+	case ToolGetSnapshot:
+		var args struct {
+			Symbol string `json:"symbol"`
+		}
+		if err = json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+			break
+		}
+		symbol := strings.ToUpper(strings.TrimSpace(args.Symbol))
+		if symbol == "" {
+			err = errors.New("symbol is required")
+			break
+		}
+		var snap *market.Snapshot
+		if snap, err = e.tools.Snapshot(ctx, symbol); err == nil {
+			if snap == nil {
+				err = fmt.Errorf("no snapshot for %s", symbol)
+				break
+			}
+			result = materialMarket(symbol, snap)
+		}
+	case ToolGetPositions:
+		var positions []PositionInfo
+		if positions, err = e.tools.Positions(ctx); err == nil {
+			view := make([]toolPosition, 0, len(positions))
+			for _, p := range positions {
+				view = append(view, toolPosition{
+					Symbol:        p.Symbol,
+					Side:          p.Side,
+					Quantity:      p.Quantity,
+					EntryPrice:    p.EntryPrice,
+					MarkPrice:     p.MarkPrice,
+					Leverage:      p.Leverage,
+					UnrealizedPnL: p.UnrealizedPnL,
+				})
+			}
+			result = view
+		}
+	default:
+		err = fmt.Errorf("unknown tool %q", call.Function.Name)
+	}
+	if err != nil {
+		result = map[string]string{"error": err.Error()}
+	}
+	data, mErr := json.Marshal(result)
+	if mErr != nil {
+		return fmt.Sprintf(`{"error":%q}`, mErr.Error())
+	}
+	return string(data)
+}
+
+// withUsage returns a copy of resp carrying the accumulated usage, or a bare
+// response holding only the usage when resp is nil.
+func withUsage(resp *llm.ChatResponse, usage llm.Usage) *llm.ChatResponse {
+	if resp == nil {
+		return &llm.ChatResponse{Usage: usage}
+	}
+	out := *resp
+	out.Choices = append([]llm.Choice(nil), resp.Choices...)
+	out.Usage = usage
+	return &out
+}
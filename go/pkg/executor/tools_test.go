@@ -0,0 +1,135 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/llm"
+	"nof0-api/pkg/market"
+)
+
+// scriptedLLM replays one Chat response per call and records the requests.
+type scriptedLLM struct {
+	fakeLLM
+	turns    []llm.Choice
+	requests []llm.ChatRequest
+}
+
+func (s *scriptedLLM) Chat(_ context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	cp := *req
+	cp.Messages = append([]llm.Message(nil), req.Messages...)
+	s.requests = append(s.requests, cp)
+	if len(s.turns) == 0 {
+		return nil, errors.New("script exhausted")
+	}
+	choice := s.turns[0]
+	if len(s.turns) > 1 {
+		s.turns = s.turns[1:]
+	}
+	return &llm.ChatResponse{
+		Model:   "test-model",
+		Choices: []llm.Choice{choice},
+		Usage:   llm.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	}, nil
+}
+
+type stubTools struct {
+	snapshots map[string]*market.Snapshot
+	positions []PositionInfo
+}
+
+func (s *stubTools) Snapshot(_ context.Context, symbol string) (*market.Snapshot, error) {
+	if snap, ok := s.snapshots[symbol]; ok {
+		return snap, nil
+	}
+	return nil, errors.New("unknown symbol")
+}
+
+func (s *stubTools) Positions(context.Context) ([]PositionInfo, error) { return s.positions, nil }
+
+func toolCall(id, name, args string) llm.ToolCall {
+	return llm.ToolCall{ID: id, Type: "function", Function: llm.FunctionCall{Name: name, Arguments: args}}
+}
+
+func newToolExecutor(t *testing.T, client llm.LLMClient, maxIter int) *BasicExecutor {
+	t.Helper()
+	cfg := &Config{
+		MajorCoinLeverage:      20,
+		AltcoinLeverage:        10,
+		MinConfidence:          75,
+		MinRiskReward:          3.0,
+		MaxPositions:           4,
+		DecisionIntervalRaw:    "3m",
+		DecisionTimeoutRaw:     "60s",
+		MaxConcurrentDecisions: 1,
+		ToolCalling:            true,
+		MaxToolIterations:      maxIter,
+	}
+	templatePath := filepath.Join("..", "..", "etc", "prompts", "executor", "default_prompt.tmpl")
+	tools := &stubTools{
+		snapshots: map[string]*market.Snapshot{"BTC": {Symbol: "BTC", Price: market.PriceInfo{Last: 101.5}}},
+		positions: []PositionInfo{{Symbol: "ETH", Side: "long", Quantity: 2, EntryPrice: 3000}},
+	}
+	exec, err := NewExecutor(cfg, client, templatePath, "", WithToolProvider(tools))
+	require.NoError(t, err)
+	return exec
+}
+
+const toolDecisionJSON = `{"signal":"buy_to_enter","symbol":"BTC","leverage":5,"position_size_usd":200,"entry_price":100,"stop_loss":95,"take_profit":115,"risk_usd":10,"confidence":90,"invalidation_condition":"below EMA20","reasoning":"snapshot confirms uptrend"}`
+
+func TestExecutor_ToolCallingResolvesToolsUntilDecision(t *testing.T) {
+	client := &scriptedLLM{turns: []llm.Choice{
+		{ToolCalls: []llm.ToolCall{
+			toolCall("c1", ToolGetSnapshot, `{"symbol":"btc"}`),
+			toolCall("c2", ToolGetPositions, `{}`),
+			toolCall("c3", ToolGetSnapshot, `{"symbol":"DOGE"}`),
+		}},
+		{ToolCalls: []llm.ToolCall{toolCall("c4", llm.StructuredToolName, toolDecisionJSON)}},
+	}}
+	exec := newToolExecutor(t, client, 0)
+
+	out, err := exec.GetFullDecision(&Context{CurrentTime: "2025-01-01T00:00:00Z"})
+	require.NoError(t, err)
+	require.Len(t, out.Decisions, 1)
+	assert.Equal(t, "open_long", out.Decisions[0].Action)
+	assert.Equal(t, "BTC", out.Decisions[0].Symbol)
+	assert.Equal(t, 30, out.Usage.TotalTokens, "usage should cover every turn")
+
+	require.Len(t, client.requests, 2)
+	first := client.requests[0]
+	require.Len(t, first.Tools, 3)
+	assert.Equal(t, "auto", first.ToolChoice)
+
+	msgs := client.requests[1].Messages
+	require.Len(t, msgs, len(first.Messages)+4, "assistant turn plus one result per tool call")
+	assistant := msgs[len(first.Messages)]
+	assert.Equal(t, "assistant", assistant.Role)
+	assert.Len(t, assistant.ToolCalls, 3)
+	results := msgs[len(first.Messages)+1:]
+	assert.Equal(t, "c1", results[0].ToolCallID)
+	assert.Contains(t, results[0].Content, `"price":101.5`)
+	assert.Equal(t, "c2", results[1].ToolCallID)
+	assert.Contains(t, results[1].Content, `"symbol":"ETH"`)
+	assert.Equal(t, "c3", results[2].ToolCallID)
+	assert.True(t, strings.HasPrefix(results[2].Content, `{"error":`), "tool failures are reported to the model")
+}
+
+func TestExecutor_ToolCallingCapsIterations(t *testing.T) {
+	client := &scriptedLLM{turns: []llm.Choice{
+		{ToolCalls: []llm.ToolCall{toolCall("c1", ToolGetPositions, `{}`)}},
+	}}
+	exec := newToolExecutor(t, client, 3)
+
+	out, err := exec.GetFullDecision(&Context{CurrentTime: "2025-01-01T00:00:00Z"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "after 3 tool-calling turns")
+	require.Len(t, client.requests, 3, "the loop must stop at max_tool_iterations")
+	assert.Equal(t, llm.StructuredToolName, client.requests[2].ToolChoice, "the last turn forces a decision")
+	assert.Equal(t, 45, out.Usage.TotalTokens)
+}
@@ -3,6 +3,7 @@ package executor
 import (
 	"time"
 
+	"nof0-api/pkg/llm"
 	market "nof0-api/pkg/market"
 )
 
@@ -19,6 +20,7 @@ type PositionInfo struct {
 	LiquidationPrice float64
 	MarginUsed       float64
 	UpdateTime       int64
+	EntryTimeMs      int64 // when the position was opened, if known
 }
 
 // AccountInfo summarizes account-level state.
@@ -36,6 +38,7 @@ type AccountInfo struct {
 type CandidateCoin struct {
 	Symbol  string
 	Sources []string
+	Score   float64 // Ranking score assigned during candidate selection
 }
 
 // OpenInterest is a placeholder for optional OI enrichment not covered by market.Snapshot.
@@ -79,6 +82,7 @@ type Context struct {
 	MaxPositionSizeUSD float64 // hard cap per trade
 	// Optional P0 guards (disabled when zero values):
 	LiquidityThresholdUSD          float64              // require OI*Price ≥ threshold for new opens
+	MaxAbsFundingRate              float64              // reject opens paying funding above this rate
 	MaxMarginUsagePct              float64              // after new position margin
 	BTCETHPositionValueMinMultiple float64              // min equity multiple for BTC/ETH position value
 	BTCETHPositionValueMaxMultiple float64              // max equity multiple for BTC/ETH position value
@@ -94,6 +98,8 @@ type Decision struct {
 	Action                string
 	Leverage              int
 	PositionSizeUSD       float64
+	PositionSizePct       float64 // percent (0-100) of available balance; takes precedence over PositionSizeUSD
+	CloseFraction         float64 // share (0-1) of the position a close scales out; 0 or 1 closes it all
 	EntryPrice            float64
 	StopLoss              float64
 	TakeProfit            float64
@@ -101,6 +107,9 @@ type Decision struct {
 	RiskUSD               float64
 	Reasoning             string
 	InvalidationCondition string
+	// IdempotencyKey identifies the logical intent for manager-side retry
+	// dedup; it is assigned by the manager and never parsed from model output.
+	IdempotencyKey string `json:"-"`
 }
 
 // FullDecision is the full response produced by the executor.
@@ -109,4 +118,5 @@ type FullDecision struct {
 	CoTTrace   string
 	Decisions  []Decision
 	Timestamp  time.Time
+	Usage      llm.Usage // tokens and estimated cost of the LLM call
 }
@@ -1,9 +1,29 @@
 package executor
 
 import (
+	"fmt"
+	"math"
 	"strings"
 )
 
+// DecisionSchemaVersion is the decision contract version the prompt
+// templates declare. Bump it, and update the templates, whenever the
+// contract's fields change meaning.
+const DecisionSchemaVersion = "1"
+
+// supportedSchemaVersions lists the contract versions this executor parses.
+var supportedSchemaVersions = map[string]bool{DecisionSchemaVersion: true}
+
+// checkSchemaVersion rejects a contract declaring a version the executor does
+// not know. An empty version predates versioning and is read as current.
+func checkSchemaVersion(version string) error {
+	version = strings.TrimSpace(version)
+	if version == "" || supportedSchemaVersions[version] {
+		return nil
+	}
+	return fmt.Errorf("executor: decision schema_version %q unsupported (want %s)", version, DecisionSchemaVersion)
+}
+
 // sanitizeResponse performs minimal cleanup prior to parsing.
 func sanitizeResponse(s string) string {
 	s = strings.TrimSpace(s)
@@ -14,10 +34,13 @@ func sanitizeResponse(s string) string {
 
 // decisionContract mirrors the structured JSON contract expected from the LLM.
 type decisionContract struct {
+	SchemaVersion         string  `json:"schema_version,omitempty"`
 	Signal                string  `json:"signal"`
 	Symbol                string  `json:"symbol"`
 	Leverage              int     `json:"leverage"`
 	PositionSizeUSD       float64 `json:"position_size_usd"`
+	PositionSizePct       float64 `json:"position_size_pct,omitempty"`
+	CloseFraction         float64 `json:"close_fraction,omitempty"`
 	EntryPrice            float64 `json:"entry_price"`
 	StopLoss              float64 `json:"stop_loss"`
 	TakeProfit            float64 `json:"take_profit"`
@@ -27,6 +50,53 @@ type decisionContract struct {
 	Reasoning             string  `json:"reasoning"`
 }
 
+// minimalDecisionContract is decisionContract with only signal and symbol
+// required, requested under DecisionSchemaMinimal. Field names and types must
+// stay identical to decisionContract so the two convert directly.
+type minimalDecisionContract struct {
+	SchemaVersion         string  `json:"schema_version,omitempty"`
+	Signal                string  `json:"signal"`
+	Symbol                string  `json:"symbol"`
+	Leverage              int     `json:"leverage,omitempty"`
+	PositionSizeUSD       float64 `json:"position_size_usd,omitempty"`
+	PositionSizePct       float64 `json:"position_size_pct,omitempty"`
+	CloseFraction         float64 `json:"close_fraction,omitempty"`
+	EntryPrice            float64 `json:"entry_price,omitempty"`
+	StopLoss              float64 `json:"stop_loss,omitempty"`
+	TakeProfit            float64 `json:"take_profit,omitempty"`
+	RiskUSD               float64 `json:"risk_usd,omitempty"`
+	Confidence            int     `json:"confidence,omitempty"`
+	InvalidationCondition string  `json:"invalidation_condition,omitempty"`
+	Reasoning             string  `json:"reasoning,omitempty"`
+}
+
+// applyMinimalDefaults fills entry fields a minimal-schema response may omit:
+// leverage from the configured cap, entry price from the latest market price,
+// confidence at the configured minimum and risk from the stop distance.
+// Sizing, stop loss and take profit are never invented.
+func applyMinimalDefaults(cfg *Config, input *Context, d *Decision) {
+	if d.Action != "open_long" && d.Action != "open_short" {
+		return
+	}
+	if d.Leverage <= 0 {
+		d.Leverage = cfg.AltcoinLeverage
+		if isBTCETH(d.Symbol) {
+			d.Leverage = cfg.MajorCoinLeverage
+		}
+	}
+	if d.EntryPrice <= 0 && input != nil {
+		if snap, ok := input.MarketDataMap[d.Symbol]; ok && snap != nil {
+			d.EntryPrice = snap.Price.Last
+		}
+	}
+	if d.Confidence <= 0 {
+		d.Confidence = cfg.MinConfidence
+	}
+	if d.RiskUSD <= 0 && d.PositionSizeUSD > 0 && d.EntryPrice > 0 && d.StopLoss > 0 {
+		d.RiskUSD = d.PositionSizeUSD * math.Abs(d.EntryPrice-d.StopLoss) / d.EntryPrice
+	}
+}
+
 // mapDecisionContract converts the LLM contract into internal Decision format.
 func mapDecisionContract(d decisionContract, positions []PositionInfo) Decision {
 	action := strings.ToLower(strings.TrimSpace(d.Signal))
@@ -54,6 +124,8 @@ func mapDecisionContract(d decisionContract, positions []PositionInfo) Decision
 		Action:                mapped,
 		Leverage:              d.Leverage,
 		PositionSizeUSD:       d.PositionSizeUSD,
+		PositionSizePct:       d.PositionSizePct,
+		CloseFraction:         d.CloseFraction,
 		EntryPrice:            d.EntryPrice,
 		StopLoss:              d.StopLoss,
 		TakeProfit:            d.TakeProfit,
@@ -2,10 +2,42 @@ package executor
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
 )
 
+// Guard names carried by GuardError. Names shared with the manager's own
+// guards use the same label so rejection counts aggregate per guard.
+const (
+	GuardMaxLeverage     = "max_leverage"
+	GuardLiquidity       = "liquidity"
+	GuardFundingRate     = "max_funding_rate"
+	GuardValueBand       = "position_value_band"
+	GuardMarginUsage     = "max_margin_usage"
+	GuardCooldown        = "cooldown"
+	GuardMaxPositions    = "max_positions"
+	GuardDuplicatePos    = "duplicate_position"
+	GuardMaxRisk         = "max_risk_pct"
+	GuardMaxPositionSize = "max_position_size_usd"
+)
+
+// GuardError reports a decision rejected by one of the validator's risk
+// guards. Use errors.As to recover the guard name; malformed decisions are
+// reported as plain errors.
+type GuardError struct {
+	Guard string
+	Err   error
+}
+
+func (e *GuardError) Error() string { return e.Err.Error() }
+
+func (e *GuardError) Unwrap() error { return e.Err }
+
+func guardErrorf(guard, format string, args ...any) error {
+	return &GuardError{Guard: guard, Err: fmt.Errorf(format, args...)}
+}
+
 // ValidateDecisions applies sanity checks against configuration and current context.
 func ValidateDecisions(cfg *Config, ctx *Context, decisions []Decision) error {
 	if cfg == nil {
@@ -14,43 +46,32 @@ func ValidateDecisions(cfg *Config, ctx *Context, decisions []Decision) error {
 	for i, d := range decisions {
 		action := strings.TrimSpace(d.Action)
 		symbol := strings.TrimSpace(d.Symbol)
+		if err := validateDecisionRanges(i, action, d); err != nil {
+			return err
+		}
 		switch action {
 		case "open_long", "open_short":
 			if symbol == "" {
 				return fmt.Errorf("decision[%d]: symbol is required", i)
 			}
-			if d.Leverage <= 0 {
-				return fmt.Errorf("decision[%d]: leverage must be positive", i)
+			if d.PositionSizeUSD <= 0 && d.PositionSizePct <= 0 {
+				return fmt.Errorf("decision[%d]: position_size_usd or position_size_pct must be positive", i)
 			}
-			if d.PositionSizeUSD <= 0 {
-				return fmt.Errorf("decision[%d]: position_size_usd must be positive", i)
+			if d.PositionSizePct > 100 {
+				return fmt.Errorf("decision[%d]: position_size_pct %.2f exceeds 100", i, d.PositionSizePct)
 			}
-			if d.StopLoss <= 0 || d.TakeProfit <= 0 || d.EntryPrice <= 0 {
-				return fmt.Errorf("decision[%d]: entry/stop_loss/take_profit must be positive", i)
-			}
-			if d.Confidence < 0 || d.Confidence > 100 {
-				return fmt.Errorf("decision[%d]: confidence must be 0-100", i)
+			if d.PositionSizePct > 0 && ctx != nil && ctx.Account.AvailableBalance > 0 {
+				// Validate the notional the manager will actually size from.
+				d.PositionSizeUSD = ctx.Account.AvailableBalance * d.PositionSizePct / 100
+				if ctx.MaxPositionSizeUSD > 0 && d.PositionSizeUSD > ctx.MaxPositionSizeUSD {
+					d.PositionSizeUSD = ctx.MaxPositionSizeUSD
+				}
 			}
 			if d.Confidence < cfg.MinConfidence {
 				return fmt.Errorf("decision[%d]: confidence below threshold", i)
 			}
-			// Price relationship & RR check
-			if action == "open_long" {
-				if !(d.TakeProfit > d.EntryPrice && d.EntryPrice > d.StopLoss) {
-					return fmt.Errorf("decision[%d]: long requires TP>entry>SL", i)
-				}
-				rr := (d.TakeProfit - d.EntryPrice) / (d.EntryPrice - d.StopLoss)
-				if rr < cfg.MinRiskReward {
-					return fmt.Errorf("decision[%d]: reward/risk %.2f below min %.2f", i, rr, cfg.MinRiskReward)
-				}
-			} else { // open_short
-				if !(d.StopLoss > d.EntryPrice && d.EntryPrice > d.TakeProfit) {
-					return fmt.Errorf("decision[%d]: short requires SL>entry>TP", i)
-				}
-				rr := (d.EntryPrice - d.TakeProfit) / (d.StopLoss - d.EntryPrice)
-				if rr < cfg.MinRiskReward {
-					return fmt.Errorf("decision[%d]: reward/risk %.2f below min %.2f", i, rr, cfg.MinRiskReward)
-				}
+			if err := validateRiskReward(cfg, i, action, d); err != nil {
+				return err
 			}
 			// Leverage caps (config) and asset-level cap if available; take the minimum
 			capLev := cfg.AltcoinLeverage
@@ -66,7 +87,7 @@ func ValidateDecisions(cfg *Config, ctx *Context, decisions []Decision) error {
 				}
 			}
 			if d.Leverage > capLev {
-				return fmt.Errorf("decision[%d]: leverage %dx exceeds cap %dx", i, d.Leverage, capLev)
+				return guardErrorf(GuardMaxLeverage, "decision[%d]: leverage %dx exceeds cap %dx", i, d.Leverage, capLev)
 			}
 
 			// Extended guards (enabled only when context provides non-zero values)
@@ -76,7 +97,16 @@ func ValidateDecisions(cfg *Config, ctx *Context, decisions []Decision) error {
 					if snap, ok := ctx.MarketDataMap[d.Symbol]; ok && snap != nil && snap.OpenInterest != nil && snap.Price.Last > 0 {
 						oiValueUSD := snap.OpenInterest.Latest * snap.Price.Last
 						if oiValueUSD+1e-9 < ctx.LiquidityThresholdUSD {
-							return fmt.Errorf("decision[%d]: %s illiquid: oi*price %.2f < threshold %.2f", i, d.Symbol, oiValueUSD, ctx.LiquidityThresholdUSD)
+							return guardErrorf(GuardLiquidity, "decision[%d]: %s illiquid: oi*price %.2f < threshold %.2f", i, d.Symbol, oiValueUSD, ctx.LiquidityThresholdUSD)
+						}
+					}
+				}
+
+				// Funding: reject opens on the paying side beyond the threshold.
+				if ctx.MaxAbsFundingRate > 0 && ctx.MarketDataMap != nil {
+					if snap, ok := ctx.MarketDataMap[d.Symbol]; ok && snap != nil && snap.Funding != nil {
+						if paid := fundingPaid(action, snap.Funding.Rate); paid > ctx.MaxAbsFundingRate+1e-12 {
+							return guardErrorf(GuardFundingRate, "decision[%d]: %s %s would pay funding %.6f above max %.6f", i, action, d.Symbol, paid, ctx.MaxAbsFundingRate)
 						}
 					}
 				}
@@ -88,26 +118,26 @@ func ValidateDecisions(cfg *Config, ctx *Context, decisions []Decision) error {
 						if ctx.BTCETHPositionValueMinMultiple > 0 {
 							minV := equity * ctx.BTCETHPositionValueMinMultiple
 							if d.PositionSizeUSD+1e-9 < minV {
-								return fmt.Errorf("decision[%d]: position_size_usd %.2f below BTC/ETH min %.2f (%.2fx equity)", i, d.PositionSizeUSD, minV, ctx.BTCETHPositionValueMinMultiple)
+								return guardErrorf(GuardValueBand, "decision[%d]: position_size_usd %.2f below BTC/ETH min %.2f (%.2fx equity)", i, d.PositionSizeUSD, minV, ctx.BTCETHPositionValueMinMultiple)
 							}
 						}
 						if ctx.BTCETHPositionValueMaxMultiple > 0 {
 							maxV := equity * ctx.BTCETHPositionValueMaxMultiple
 							if d.PositionSizeUSD-1e-9 > maxV {
-								return fmt.Errorf("decision[%d]: position_size_usd %.2f exceeds BTC/ETH max %.2f (%.2fx equity)", i, d.PositionSizeUSD, maxV, ctx.BTCETHPositionValueMaxMultiple)
+								return guardErrorf(GuardValueBand, "decision[%d]: position_size_usd %.2f exceeds BTC/ETH max %.2f (%.2fx equity)", i, d.PositionSizeUSD, maxV, ctx.BTCETHPositionValueMaxMultiple)
 							}
 						}
 					} else {
 						if ctx.AltPositionValueMinMultiple > 0 {
 							minV := equity * ctx.AltPositionValueMinMultiple
 							if d.PositionSizeUSD+1e-9 < minV {
-								return fmt.Errorf("decision[%d]: position_size_usd %.2f below alt min %.2f (%.2fx equity)", i, d.PositionSizeUSD, minV, ctx.AltPositionValueMinMultiple)
+								return guardErrorf(GuardValueBand, "decision[%d]: position_size_usd %.2f below alt min %.2f (%.2fx equity)", i, d.PositionSizeUSD, minV, ctx.AltPositionValueMinMultiple)
 							}
 						}
 						if ctx.AltPositionValueMaxMultiple > 0 {
 							maxV := equity * ctx.AltPositionValueMaxMultiple
 							if d.PositionSizeUSD-1e-9 > maxV {
-								return fmt.Errorf("decision[%d]: position_size_usd %.2f exceeds alt max %.2f (%.2fx equity)", i, d.PositionSizeUSD, maxV, ctx.AltPositionValueMaxMultiple)
+								return guardErrorf(GuardValueBand, "decision[%d]: position_size_usd %.2f exceeds alt max %.2f (%.2fx equity)", i, d.PositionSizeUSD, maxV, ctx.AltPositionValueMaxMultiple)
 							}
 						}
 					}
@@ -119,7 +149,7 @@ func ValidateDecisions(cfg *Config, ctx *Context, decisions []Decision) error {
 					used := ctx.Account.MarginUsed + newMargin
 					usagePct := 100 * (used / ctx.Account.TotalEquity)
 					if usagePct > ctx.MaxMarginUsagePct+1e-9 {
-						return fmt.Errorf("decision[%d]: margin usage %.2f%% exceeds cap %.2f%% after new position", i, usagePct, ctx.MaxMarginUsagePct)
+						return guardErrorf(GuardMarginUsage, "decision[%d]: margin usage %.2f%% exceeds cap %.2f%% after new position", i, usagePct, ctx.MaxMarginUsagePct)
 					}
 				}
 
@@ -127,20 +157,20 @@ func ValidateDecisions(cfg *Config, ctx *Context, decisions []Decision) error {
 				if ctx.CooldownAfterClose > 0 && ctx.RecentlyClosed != nil {
 					if ts, ok := ctx.RecentlyClosed[d.Symbol]; ok && !ts.IsZero() {
 						if time.Since(ts) < ctx.CooldownAfterClose {
-							return fmt.Errorf("decision[%d]: %s in cooldown window (%s remaining)", i, d.Symbol, (ctx.CooldownAfterClose - time.Since(ts)).Truncate(time.Second))
+							return guardErrorf(GuardCooldown, "decision[%d]: %s in cooldown window (%s remaining)", i, d.Symbol, (ctx.CooldownAfterClose - time.Since(ts)).Truncate(time.Second))
 						}
 					}
 				}
 			}
 			// Position count
 			if ctx != nil && len(ctx.Positions) >= cfg.MaxPositions {
-				return fmt.Errorf("decision[%d]: max_positions reached (%d)", i, cfg.MaxPositions)
+				return guardErrorf(GuardMaxPositions, "decision[%d]: max_positions reached (%d)", i, cfg.MaxPositions)
 			}
 			// No pyramiding / hedging: disallow opening if any position already exists on the symbol
 			if ctx != nil {
 				for _, p := range ctx.Positions {
 					if strings.EqualFold(p.Symbol, d.Symbol) {
-						return fmt.Errorf("decision[%d]: position already exists on %s; no add/hedge allowed", i, d.Symbol)
+						return guardErrorf(GuardDuplicatePos, "decision[%d]: position already exists on %s; no add/hedge allowed", i, d.Symbol)
 					}
 				}
 			}
@@ -148,18 +178,21 @@ func ValidateDecisions(cfg *Config, ctx *Context, decisions []Decision) error {
 			if ctx != nil && ctx.Account.TotalEquity > 0 && ctx.MaxRiskPct > 0 {
 				maxRiskUSD := ctx.Account.TotalEquity * (ctx.MaxRiskPct / 100.0)
 				if d.RiskUSD > maxRiskUSD+1e-9 { // small epsilon
-					return fmt.Errorf("decision[%d]: risk_usd %.2f exceeds max %.2f (%.2f%% of equity)", i, d.RiskUSD, maxRiskUSD, ctx.MaxRiskPct)
+					return guardErrorf(GuardMaxRisk, "decision[%d]: risk_usd %.2f exceeds max %.2f (%.2f%% of equity)", i, d.RiskUSD, maxRiskUSD, ctx.MaxRiskPct)
 				}
 			}
 			if ctx != nil && ctx.MaxPositionSizeUSD > 0 {
 				if d.PositionSizeUSD > ctx.MaxPositionSizeUSD+1e-9 {
-					return fmt.Errorf("decision[%d]: position_size_usd %.2f exceeds cap %.2f", i, d.PositionSizeUSD, ctx.MaxPositionSizeUSD)
+					return guardErrorf(GuardMaxPositionSize, "decision[%d]: position_size_usd %.2f exceeds cap %.2f", i, d.PositionSizeUSD, ctx.MaxPositionSizeUSD)
 				}
 			}
 		case "close_long", "close_short":
 			if symbol == "" {
 				return fmt.Errorf("decision[%d]: symbol is required", i)
 			}
+			if d.CloseFraction < 0 || d.CloseFraction > 1 {
+				return fmt.Errorf("decision[%d]: close_fraction %.4f must be within 0..1", i, d.CloseFraction)
+			}
 			if ctx == nil {
 				return fmt.Errorf("decision[%d]: context required to validate close action", i)
 			}
@@ -186,4 +219,79 @@ func ValidateDecisions(cfg *Config, ctx *Context, decisions []Decision) error {
 	return nil
 }
 
+// validateDecisionRanges checks the fields every decision must keep in range
+// regardless of account context: confidence within 0-100, non-negative
+// leverage, sizing and protective levels, and for opens a positive leverage
+// and entry.
+func validateDecisionRanges(i int, action string, d Decision) error {
+	if d.Confidence < 0 || d.Confidence > 100 {
+		return fmt.Errorf("decision[%d]: confidence %d must be 0-100", i, d.Confidence)
+	}
+	if d.Leverage < 0 {
+		return fmt.Errorf("decision[%d]: leverage %d cannot be negative", i, d.Leverage)
+	}
+	if d.PositionSizeUSD < 0 || d.PositionSizePct < 0 {
+		return fmt.Errorf("decision[%d]: position size cannot be negative", i)
+	}
+	if d.StopLoss < 0 || d.TakeProfit < 0 {
+		return fmt.Errorf("decision[%d]: stop_loss/take_profit cannot be negative", i)
+	}
+	if action != "open_long" && action != "open_short" {
+		return nil
+	}
+	if d.Leverage == 0 {
+		return fmt.Errorf("decision[%d]: leverage must be positive", i)
+	}
+	if d.EntryPrice <= 0 {
+		return fmt.Errorf("decision[%d]: entry_price must be positive", i)
+	}
+	return nil
+}
+
+// validateRiskReward checks an open's stop loss and take profit against its
+// entry. Each level must be present when cfg requires it and sit on the loss
+// or profit side of entry for the direction; with both present, the
+// reward/risk ratio |TP-entry| / |entry-SL| must reach cfg.MinRiskReward.
+func validateRiskReward(cfg *Config, i int, action string, d Decision) error {
+	hasSL, hasTP := d.StopLoss > 0, d.TakeProfit > 0
+	if !hasSL && levelRequired(cfg.RequireStopLoss) {
+		return fmt.Errorf("decision[%d]: stop_loss is required", i)
+	}
+	if !hasTP && levelRequired(cfg.RequireTakeProfit) {
+		return fmt.Errorf("decision[%d]: take_profit is required", i)
+	}
+	long := action == "open_long"
+	slOK := !hasSL || (long && d.StopLoss < d.EntryPrice) || (!long && d.StopLoss > d.EntryPrice)
+	tpOK := !hasTP || (long && d.TakeProfit > d.EntryPrice) || (!long && d.TakeProfit < d.EntryPrice)
+	if !slOK || !tpOK {
+		if long {
+			return fmt.Errorf("decision[%d]: long requires TP>entry>SL", i)
+		}
+		return fmt.Errorf("decision[%d]: short requires SL>entry>TP", i)
+	}
+	if !hasSL || !hasTP {
+		return nil
+	}
+	rr := math.Abs(d.TakeProfit-d.EntryPrice) / math.Abs(d.EntryPrice-d.StopLoss)
+	if rr < cfg.MinRiskReward {
+		return fmt.Errorf("decision[%d]: reward/risk %.2f below min %.2f", i, rr, cfg.MinRiskReward)
+	}
+	return nil
+}
+
+// levelRequired resolves a RequireStopLoss/RequireTakeProfit setting, which
+// defaults to required when unset.
+func levelRequired(require *bool) bool {
+	return require == nil || *require
+}
+
 // isBTCETH moved to utils.go for single definition
+
+// fundingPaid returns the funding rate an open on the given side would pay
+// per interval: longs pay positive funding, shorts pay negative funding.
+func fundingPaid(action string, rate float64) float64 {
+	if action == "open_short" {
+		return -rate
+	}
+	return rate
+}
@@ -1,6 +1,7 @@
 package executor
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -53,6 +54,8 @@ func TestValidateDecisions_RR_Fails(t *testing.T) {
 	}
 	err := ValidateDecisions(cfg, ctx, []Decision{d})
 	assert.Error(t, err, "ValidateDecisions should fail due to insufficient risk/reward ratio")
+	var guardErr *GuardError
+	assert.False(t, errors.As(err, &guardErr), "a malformed decision is not a guard rejection")
 }
 
 func TestValidateDecisions_LeverageCap_Fails(t *testing.T) {
@@ -125,6 +128,26 @@ func TestValidateDecisions_RiskAndSizeCaps(t *testing.T) {
 	assert.Error(t, err, "ValidateDecisions should fail due to position size cap exceeded")
 }
 
+func TestValidateDecisions_PositionSizePct(t *testing.T) {
+	cfg := baseCfg()
+	ctx := &Context{Account: AccountInfo{TotalEquity: 1000, AvailableBalance: 1000}, MaxMarginUsagePct: 50}
+	d := Decision{Symbol: "ETH", Action: "open_short", Leverage: 3, PositionSizePct: 10, EntryPrice: 100, StopLoss: 110, TakeProfit: 70, Confidence: 90}
+	assert.NoError(t, ValidateDecisions(cfg, ctx, []Decision{d}), "pct sizing without a USD size should validate")
+
+	// 90% of 1000 at 3x needs 300 margin: 30% usage, still under 50%.
+	d.PositionSizePct = 90
+	assert.NoError(t, ValidateDecisions(cfg, ctx, []Decision{d}))
+
+	// pct wins over a small USD size: 100% at 1x needs 1000 margin = 100% usage.
+	d.Leverage = 1
+	d.PositionSizePct = 100
+	d.PositionSizeUSD = 10
+	assert.Error(t, ValidateDecisions(cfg, ctx, []Decision{d}), "margin check should use the pct-derived notional")
+
+	d.PositionSizePct = 150
+	assert.Error(t, ValidateDecisions(cfg, ctx, []Decision{d}), "pct above 100 should be rejected")
+}
+
 func TestValidateDecisions_Close_NoPosition_Fails(t *testing.T) {
 	cfg := baseCfg()
 	ctx := &Context{Positions: nil}
@@ -141,6 +164,15 @@ func TestValidateDecisions_Close_WithMatching_Passes(t *testing.T) {
 	assert.NoError(t, err, "ValidateDecisions should not error for valid close position")
 }
 
+func TestValidateDecisions_CloseFraction(t *testing.T) {
+	cfg := baseCfg()
+	ctx := &Context{Positions: []PositionInfo{{Symbol: "BTC", Side: "long"}}}
+	err := ValidateDecisions(cfg, ctx, []Decision{{Symbol: "BTC", Action: "close_long", CloseFraction: 0.5}})
+	assert.NoError(t, err, "ValidateDecisions should accept a partial close")
+	err = ValidateDecisions(cfg, ctx, []Decision{{Symbol: "BTC", Action: "close_long", CloseFraction: 1.5}})
+	assert.Error(t, err, "ValidateDecisions should reject a close fraction above 1")
+}
+
 func TestValidateDecisions_LiquidityThreshold_Fails(t *testing.T) {
 	cfg := baseCfg()
 	ctx := &Context{
@@ -153,6 +185,32 @@ func TestValidateDecisions_LiquidityThreshold_Fails(t *testing.T) {
 	d := Decision{Symbol: "ABC", Action: "open_long", Leverage: 2, PositionSizeUSD: 100, EntryPrice: 10, StopLoss: 9, TakeProfit: 13, Confidence: 90}
 	err := ValidateDecisions(cfg, ctx, []Decision{d})
 	assert.Error(t, err, "should fail below liquidity threshold")
+	assertGuard(t, err, GuardLiquidity)
+}
+
+func TestValidateDecisions_FundingGuard(t *testing.T) {
+	cfg := baseCfg()
+	ctx := &Context{
+		Account:           AccountInfo{TotalEquity: 10000},
+		MaxAbsFundingRate: 0.0005,
+		MarketDataMap: map[string]*market.Snapshot{
+			"ABC": {Price: market.PriceInfo{Last: 10}, Funding: &market.FundingInfo{Rate: 0.001}},
+			"XYZ": {Price: market.PriceInfo{Last: 10}, Funding: &market.FundingInfo{Rate: -0.001}},
+		},
+	}
+	long := Decision{Symbol: "ABC", Action: "open_long", Leverage: 2, PositionSizeUSD: 100, EntryPrice: 10, StopLoss: 9, TakeProfit: 13, Confidence: 90}
+	short := Decision{Symbol: "ABC", Action: "open_short", Leverage: 2, PositionSizeUSD: 100, EntryPrice: 10, StopLoss: 11, TakeProfit: 7, Confidence: 90}
+
+	err := ValidateDecisions(cfg, ctx, []Decision{long})
+	assert.ErrorContains(t, err, "would pay funding", "longs pay positive funding")
+	assert.NoError(t, ValidateDecisions(cfg, ctx, []Decision{short}), "shorts receive positive funding")
+
+	long.Symbol, short.Symbol = "XYZ", "XYZ"
+	assert.NoError(t, ValidateDecisions(cfg, ctx, []Decision{long}), "longs receive negative funding")
+	assert.ErrorContains(t, ValidateDecisions(cfg, ctx, []Decision{short}), "would pay funding", "shorts pay negative funding")
+
+	ctx.MaxAbsFundingRate = 0
+	assert.NoError(t, ValidateDecisions(cfg, ctx, []Decision{short}), "guard is off at zero")
 }
 
 func TestValidateDecisions_MarginUsage_Fails(t *testing.T) {
@@ -162,6 +220,7 @@ func TestValidateDecisions_MarginUsage_Fails(t *testing.T) {
 	d := Decision{Symbol: "XYZ", Action: "open_long", Leverage: 3, PositionSizeUSD: 300, EntryPrice: 100, StopLoss: 90, TakeProfit: 130, Confidence: 90}
 	err := ValidateDecisions(cfg, ctx, []Decision{d})
 	assert.Error(t, err, "should fail due to margin usage cap")
+	assertGuard(t, err, GuardMarginUsage)
 }
 
 func TestValidateDecisions_ValueBand_And_Cooldown(t *testing.T) {
@@ -178,4 +237,85 @@ func TestValidateDecisions_ValueBand_And_Cooldown(t *testing.T) {
 	d := Decision{Symbol: "ALT", Action: "open_long", Leverage: 5, PositionSizeUSD: 500, EntryPrice: 10, StopLoss: 9, TakeProfit: 13, Confidence: 90}
 	err := ValidateDecisions(cfg, ctx, []Decision{d})
 	assert.Error(t, err, "should fail due to value band and cooldown")
+	assertGuard(t, err, GuardValueBand)
+}
+
+func assertGuard(t *testing.T, err error, guard string) {
+	t.Helper()
+	var guardErr *GuardError
+	if assert.True(t, errors.As(err, &guardErr), "expected a guard rejection, got %v", err) {
+		assert.Equal(t, guard, guardErr.Guard)
+	}
+}
+
+func TestValidateDecisions_Ranges(t *testing.T) {
+	cfg := baseCfg()
+	ctx := &Context{Positions: []PositionInfo{{Symbol: "SOL", Side: "long"}}}
+	long := Decision{Symbol: "BTC", Action: "open_long", Leverage: 10, PositionSizeUSD: 100, EntryPrice: 100, StopLoss: 95, TakeProfit: 115, Confidence: 80}
+	short := Decision{Symbol: "BTC", Action: "open_short", Leverage: 10, PositionSizeUSD: 100, EntryPrice: 100, StopLoss: 105, TakeProfit: 85, Confidence: 80}
+	cases := []struct {
+		name   string
+		mutate func(d Decision) Decision
+		base   Decision
+		errMsg string
+	}{
+		{name: "confidence above 100", base: long, mutate: func(d Decision) Decision { d.Confidence = 101; return d }, errMsg: "must be 0-100"},
+		{name: "negative confidence on hold", base: Decision{Action: "hold"}, mutate: func(d Decision) Decision { d.Confidence = -1; return d }, errMsg: "must be 0-100"},
+		{name: "zero leverage on open", base: long, mutate: func(d Decision) Decision { d.Leverage = 0; return d }, errMsg: "leverage must be positive"},
+		{name: "negative leverage on close", base: Decision{Symbol: "SOL", Action: "close_long"}, mutate: func(d Decision) Decision { d.Leverage = -2; return d }, errMsg: "cannot be negative"},
+		{name: "negative size", base: Decision{Symbol: "SOL", Action: "close_long"}, mutate: func(d Decision) Decision { d.PositionSizeUSD = -5; return d }, errMsg: "position size cannot be negative"},
+		{name: "missing entry", base: long, mutate: func(d Decision) Decision { d.EntryPrice = 0; return d }, errMsg: "entry_price must be positive"},
+		{name: "negative stop", base: Decision{Symbol: "SOL", Action: "close_long"}, mutate: func(d Decision) Decision { d.StopLoss = -1; return d }, errMsg: "cannot be negative"},
+		{name: "long stop above entry", base: long, mutate: func(d Decision) Decision { d.StopLoss = 101; return d }, errMsg: "long requires TP>entry>SL"},
+		{name: "short take profit above entry", base: short, mutate: func(d Decision) Decision { d.TakeProfit = 110; return d }, errMsg: "short requires SL>entry>TP"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.NoError(t, ValidateDecisions(cfg, ctx, []Decision{tc.base}), "the unmodified decision should pass")
+			assert.ErrorContains(t, ValidateDecisions(cfg, ctx, []Decision{tc.mutate(tc.base)}), tc.errMsg)
+		})
+	}
+}
+
+func TestValidateDecisions_RiskRewardBySide(t *testing.T) {
+	cfg := baseCfg() // min RR 3.0
+	cases := []struct {
+		name   string
+		d      Decision
+		errMsg string
+	}{
+		{name: "long passes", d: Decision{Action: "open_long", EntryPrice: 100, StopLoss: 95, TakeProfit: 115}},
+		{name: "long fails", d: Decision{Action: "open_long", EntryPrice: 100, StopLoss: 95, TakeProfit: 110}, errMsg: "reward/risk 2.00 below min 3.00"},
+		{name: "short passes", d: Decision{Action: "open_short", EntryPrice: 100, StopLoss: 102, TakeProfit: 94}},
+		{name: "short fails", d: Decision{Action: "open_short", EntryPrice: 100, StopLoss: 104, TakeProfit: 94}, errMsg: "reward/risk 1.50 below min 3.00"},
+		{name: "missing stop loss", d: Decision{Action: "open_long", EntryPrice: 100, TakeProfit: 115}, errMsg: "stop_loss is required"},
+		{name: "missing take profit", d: Decision{Action: "open_short", EntryPrice: 100, StopLoss: 102}, errMsg: "take_profit is required"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := tc.d
+			d.Symbol, d.Leverage, d.PositionSizeUSD, d.Confidence = "BTC", 5, 100, 80
+			err := ValidateDecisions(cfg, &Context{}, []Decision{d})
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+}
+
+func TestValidateDecisions_OptionalProtectiveLevels(t *testing.T) {
+	cfg := baseCfg()
+	optional := false
+	cfg.RequireStopLoss, cfg.RequireTakeProfit = &optional, &optional
+	d := Decision{Symbol: "BTC", Action: "open_long", Leverage: 5, PositionSizeUSD: 100, Confidence: 80, EntryPrice: 100}
+
+	assert.NoError(t, ValidateDecisions(cfg, &Context{}, []Decision{d}), "no levels, no ratio to enforce")
+	d.TakeProfit = 102
+	assert.NoError(t, ValidateDecisions(cfg, &Context{}, []Decision{d}), "a lone take profit cannot form a ratio")
+	d.StopLoss = 99
+	assert.ErrorContains(t, ValidateDecisions(cfg, &Context{}, []Decision{d}), "reward/risk", "once both are set the ratio applies")
+	d.StopLoss, d.TakeProfit = 0, 99
+	assert.ErrorContains(t, ValidateDecisions(cfg, &Context{}, []Decision{d}), "long requires TP>entry>SL", "a level on the wrong side is rejected")
 }
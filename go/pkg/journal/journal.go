@@ -1,10 +1,15 @@
 package journal
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -13,53 +18,277 @@ type CycleRecord struct {
 	Timestamp     time.Time              `json:"timestamp"`
 	TraderID      string                 `json:"trader_id"`
 	CycleNumber   int                    `json:"cycle_number"`
+	CycleID       string                 `json:"cycle_id,omitempty"` // links the cycle to its stored conversation
 	PromptDigest  string                 `json:"prompt_digest,omitempty"`
+	InputHash     string                 `json:"input_hash,omitempty"` // hash of the material prompt inputs, see executor.MaterialInputs
 	CoTTrace      string                 `json:"cot_trace,omitempty"`
 	DecisionsJSON string                 `json:"decisions_json,omitempty"`
 	Account       map[string]any         `json:"account_snapshot,omitempty"`
 	Positions     []map[string]any       `json:"positions_snapshot,omitempty"`
 	Candidates    []string               `json:"candidates,omitempty"`
+	CandidateSet  []CandidateScore       `json:"candidate_scores,omitempty"`
 	MarketDigest  map[string]any         `json:"market_snap_digest,omitempty"`
 	Actions       []map[string]any       `json:"actions,omitempty"`
+	Intents       []DecisionIntent       `json:"intents,omitempty"`
+	Executions    []DecisionExecution    `json:"executions,omitempty"`
+	Guards        []GuardSetting         `json:"guards,omitempty"`
 	Success       bool                   `json:"success"`
 	ErrorMessage  string                 `json:"error_message,omitempty"`
 	Extra         map[string]interface{} `json:"extra,omitempty"`
 }
 
+// CandidateScore records one candidate offered to the model and how it ranked.
+type CandidateScore struct {
+	Symbol  string   `json:"symbol"`
+	Score   float64  `json:"score"`
+	Sources []string `json:"sources,omitempty"`
+}
+
+// GuardSetting records one guard as resolved for a cycle: whether it was
+// active and the thresholds it enforced.
+type GuardSetting struct {
+	Name    string         `json:"name"`
+	Enabled bool           `json:"enabled"`
+	Params  map[string]any `json:"params,omitempty"`
+}
+
+// DecisionIntent is one decision exactly as the model produced it, before
+// guards, caps or order-style adjustments.
+type DecisionIntent struct {
+	Index           int     `json:"index"`
+	Symbol          string  `json:"symbol"`
+	Action          string  `json:"action"`
+	Leverage        int     `json:"leverage,omitempty"`
+	PositionSizeUSD float64 `json:"position_size_usd,omitempty"`
+	PositionSizePct float64 `json:"position_size_pct,omitempty"`
+	EntryPrice      float64 `json:"entry_price,omitempty"`
+	StopLoss        float64 `json:"stop_loss,omitempty"`
+	TakeProfit      float64 `json:"take_profit,omitempty"`
+	Confidence      int     `json:"confidence,omitempty"`
+}
+
+// Execution statuses for DecisionExecution.
+const (
+	ExecutionSubmitted = "submitted" // an order reached the exchange
+	ExecutionFailed    = "failed"    // execution was attempted and errored
+	ExecutionDropped   = "dropped"   // removed by manager guards before execution
+	ExecutionSkipped   = "skipped"   // not executed, e.g. hold/wait or a skipped cycle
+	ExecutionDryRun    = "dry_run"   // order was formed and logged but not sent
+)
+
+// DecisionExecution records what the manager did with the intent at
+// IntentIndex. Order fields describe the order actually submitted (or, for
+// ExecutionDryRun, the order that would have been) and are zero otherwise.
+type DecisionExecution struct {
+	IntentIndex int     `json:"intent_index"`
+	Symbol      string  `json:"symbol"`
+	Action      string  `json:"action"`
+	Status      string  `json:"status"`
+	Reason      string  `json:"reason,omitempty"`
+	Price       float64 `json:"price,omitempty"`
+	Size        float64 `json:"size,omitempty"`
+	NotionalUSD float64 `json:"notional_usd,omitempty"`
+	Leverage    int     `json:"leverage,omitempty"`
+	OrderStyle  string  `json:"order_style,omitempty"`
+	ReduceOnly  bool    `json:"reduce_only,omitempty"`
+}
+
 // Writer persists cycle records to a directory as JSON files (journal style).
+// By default each cycle gets its own file; with rotation enabled, cycles are
+// appended as JSON lines to segment files that roll over by size or age.
+// Either layout may be gzip-compressed. It is safe for concurrent use.
 type Writer struct {
+	mu    sync.Mutex
 	dir   string
 	seq   int
 	nowFn func() time.Time
+
+	compress    bool
+	maxBytes    int64
+	rotateEvery time.Duration
+
+	segPath   string
+	segBucket time.Time
+	segBytes  int64
+}
+
+// WriterOption configures optional Writer behaviour.
+type WriterOption func(*Writer)
+
+// WithCompression gzips written files, adding a .gz suffix.
+func WithCompression() WriterOption {
+	return func(w *Writer) {
+		w.compress = true
+	}
+}
+
+// WithMaxFileBytes appends cycles to segment files and rolls to a new one
+// once the current segment has reached n bytes on disk.
+func WithMaxFileBytes(n int64) WriterOption {
+	return func(w *Writer) {
+		if n > 0 {
+			w.maxBytes = n
+		}
+	}
+}
+
+// WithRotateEvery appends cycles to segment files and rolls to a new one
+// when a cycle's timestamp falls in a later UTC period of length d (24h
+// rolls daily at midnight UTC).
+func WithRotateEvery(d time.Duration) WriterOption {
+	return func(w *Writer) {
+		if d > 0 {
+			w.rotateEvery = d
+		}
+	}
 }
 
 // NewWriter constructs a journal writer.
-func NewWriter(dir string) *Writer {
+func NewWriter(dir string, opts ...WriterOption) *Writer {
 	if dir == "" {
 		dir = "journal"
 	}
 	_ = os.MkdirAll(dir, 0o755)
-	return &Writer{dir: dir, nowFn: time.Now}
+	w := &Writer{dir: dir, nowFn: time.Now}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// rotating reports whether cycles go to rolling segment files.
+func (w *Writer) rotating() bool {
+	return w.maxBytes > 0 || w.rotateEvery > 0
 }
 
-// WriteCycle writes a cycle record to a timestamped JSON file.
+// WriteCycle writes a cycle record and returns the file it landed in: its
+// own timestamped JSON file, or the current segment when rotating.
 func (w *Writer) WriteCycle(rec *CycleRecord) (string, error) {
 	if rec == nil {
 		return "", fmt.Errorf("journal: nil record")
 	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
 	if rec.Timestamp.IsZero() {
 		rec.Timestamp = w.nowFn()
 	}
 	w.seq++
 	rec.CycleNumber = w.seq
+	if w.rotating() {
+		return w.appendSegment(rec)
+	}
 	name := fmt.Sprintf("cycle_%s_%05d.json", rec.Timestamp.UTC().Format("20060102_150405"), w.seq)
 	path := filepath.Join(w.dir, name)
 	data, err := json.MarshalIndent(rec, "", "  ")
 	if err != nil {
 		return "", err
 	}
+	if w.compress {
+		path += ".gz"
+		if data, err = gzipBytes(data); err != nil {
+			return "", err
+		}
+	}
 	if err := os.WriteFile(path, data, 0o644); err != nil {
 		return "", err
 	}
 	return path, nil
 }
+
+// appendSegment appends rec as one JSON line to the current segment, first
+// rolling to a new segment when the size or age limit has been reached.
+// Compressed segments hold one gzip member per cycle, which gzip readers
+// decode as a single stream.
+func (w *Writer) appendSegment(rec *CycleRecord) (string, error) {
+	var bucket time.Time
+	if w.rotateEvery > 0 {
+		bucket = rec.Timestamp.UTC().Truncate(w.rotateEvery)
+	}
+	if w.segPath == "" || (w.maxBytes > 0 && w.segBytes >= w.maxBytes) || !bucket.Equal(w.segBucket) {
+		name := fmt.Sprintf("cycles_%s_%05d.jsonl", rec.Timestamp.UTC().Format("20060102_150405"), w.seq)
+		if w.compress {
+			name += ".gz"
+		}
+		w.segPath = filepath.Join(w.dir, name)
+		w.segBucket = bucket
+		w.segBytes = 0
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return "", err
+	}
+	data = append(data, '\n')
+	if w.compress {
+		if data, err = gzipBytes(data); err != nil {
+			return "", err
+		}
+	}
+	f, err := os.OpenFile(w.segPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", err
+	}
+	n, err := f.Write(data)
+	w.segBytes += int64(n)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", err
+	}
+	return w.segPath, nil
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ReadCycle loads a cycle record previously written by WriteCycle. For a
+// segment file it returns the first record; use ReadCycles for all of them.
+func ReadCycle(path string) (*CycleRecord, error) {
+	recs, err := ReadCycles(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(recs) == 0 {
+		return nil, fmt.Errorf("journal: %s holds no cycle", path)
+	}
+	return recs[0], nil
+}
+
+// ReadCycles loads every cycle record in a journal file, transparently
+// decompressing .gz files.
+func ReadCycles(path string) ([]*CycleRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("journal: decompress %s: %w", path, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+	var recs []*CycleRecord
+	dec := json.NewDecoder(r)
+	for {
+		var rec CycleRecord
+		if err := dec.Decode(&rec); err == io.EOF {
+			return recs, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("journal: decode %s: %w", path, err)
+		}
+		recs = append(recs, &rec)
+	}
+}
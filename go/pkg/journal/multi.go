@@ -0,0 +1,102 @@
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// CycleWriter is a destination for cycle records. WriteCycle returns a
+// sink-specific location (such as a file path) or "" when there is none.
+type CycleWriter interface {
+	WriteCycle(rec *CycleRecord) (string, error)
+}
+
+// CycleWriterFunc adapts a function, such as a database insert, to a CycleWriter.
+type CycleWriterFunc func(rec *CycleRecord) error
+
+// WriteCycle calls f(rec).
+func (f CycleWriterFunc) WriteCycle(rec *CycleRecord) (string, error) {
+	return "", f(rec)
+}
+
+// MultiWriter fans each cycle record out to several sinks. A failing sink
+// does not stop the record reaching the remaining ones.
+type MultiWriter struct {
+	writers []CycleWriter
+}
+
+// NewMultiWriter composes the given sinks; nil sinks are ignored.
+func NewMultiWriter(writers ...CycleWriter) *MultiWriter {
+	mw := &MultiWriter{}
+	for _, w := range writers {
+		if w != nil {
+			mw.writers = append(mw.writers, w)
+		}
+	}
+	return mw
+}
+
+// WriteCycle writes rec to every sink in order. It returns the first non-empty
+// location reported by a sink and the joined errors of the sinks that failed.
+func (m *MultiWriter) WriteCycle(rec *CycleRecord) (string, error) {
+	if rec == nil {
+		return "", fmt.Errorf("journal: nil record")
+	}
+	var (
+		location string
+		errs     []error
+	)
+	for i, w := range m.writers {
+		loc, err := w.WriteCycle(rec)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("journal: sink %d: %w", i, err))
+			continue
+		}
+		if location == "" {
+			location = loc
+		}
+	}
+	return location, errors.Join(errs...)
+}
+
+// JSONWriter writes each cycle record as a single line of JSON, which suits
+// tailing and log shippers. It is safe for concurrent use.
+type JSONWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONWriter constructs a JSONWriter over w.
+func NewJSONWriter(w io.Writer) *JSONWriter {
+	return &JSONWriter{w: w}
+}
+
+// NewStdoutWriter constructs a JSONWriter over os.Stdout.
+func NewStdoutWriter() *JSONWriter {
+	return NewJSONWriter(os.Stdout)
+}
+
+// WriteCycle encodes rec as one JSON line.
+func (j *JSONWriter) WriteCycle(rec *CycleRecord) (string, error) {
+	if rec == nil {
+		return "", fmt.Errorf("journal: nil record")
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if rec.Timestamp.IsZero() {
+		rec.Timestamp = time.Now()
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return "", err
+	}
+	if _, err := j.w.Write(append(data, '\n')); err != nil {
+		return "", err
+	}
+	return "", nil
+}
@@ -0,0 +1,60 @@
+package journal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiWriterReachesAllSinks(t *testing.T) {
+	dir := t.TempDir()
+	var stdout bytes.Buffer
+	var stored []*CycleRecord
+	db := CycleWriterFunc(func(rec *CycleRecord) error {
+		stored = append(stored, rec)
+		return nil
+	})
+	mw := NewMultiWriter(NewWriter(dir), NewJSONWriter(&stdout), db)
+
+	path, err := mw.WriteCycle(&CycleRecord{TraderID: "t1", DecisionsJSON: "[]", Success: true})
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "cycle_*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, files[0], path, "the file sink's path should be reported")
+	fromFile, err := ReadCycle(files[0])
+	require.NoError(t, err)
+	assert.Equal(t, "t1", fromFile.TraderID)
+
+	var fromStdout CycleRecord
+	require.NoError(t, json.Unmarshal(bytes.TrimSpace(stdout.Bytes()), &fromStdout))
+	assert.Equal(t, "t1", fromStdout.TraderID)
+	assert.Equal(t, 1, fromStdout.CycleNumber)
+	assert.Equal(t, 1, bytes.Count(stdout.Bytes(), []byte("\n")), "one JSON line per cycle")
+
+	require.Len(t, stored, 1)
+	assert.Equal(t, "t1", stored[0].TraderID)
+}
+
+func TestMultiWriterIsolatesSinkFailure(t *testing.T) {
+	dir := t.TempDir()
+	var stdout bytes.Buffer
+	failing := CycleWriterFunc(func(*CycleRecord) error { return errors.New("db unavailable") })
+	mw := NewMultiWriter(failing, nil, NewWriter(dir), NewJSONWriter(&stdout))
+
+	_, err := mw.WriteCycle(&CycleRecord{TraderID: "t1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sink 0")
+	assert.Contains(t, err.Error(), "db unavailable")
+
+	files, err := filepath.Glob(filepath.Join(dir, "cycle_*.json"))
+	require.NoError(t, err)
+	assert.Len(t, files, 1, "later sinks should still receive the record")
+	assert.NotEmpty(t, stdout.String())
+}
@@ -0,0 +1,102 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Filter selects cycle records when reading a journal back. Zero fields match
+// everything.
+type Filter struct {
+	TraderID string
+	From     time.Time // inclusive
+	To       time.Time // exclusive
+	Success  *bool     // when set, only cycles with this outcome
+}
+
+// Match reports whether rec passes the filter.
+func (f Filter) Match(rec *CycleRecord) bool {
+	if rec == nil {
+		return false
+	}
+	if f.TraderID != "" && rec.TraderID != f.TraderID {
+		return false
+	}
+	if !f.From.IsZero() && rec.Timestamp.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !rec.Timestamp.Before(f.To) {
+		return false
+	}
+	if f.Success != nil && rec.Success != *f.Success {
+		return false
+	}
+	return true
+}
+
+// Reader loads cycle records from a directory laid out by Writer, whether
+// per-cycle or rotated, compressed or not.
+type Reader struct {
+	dir string
+}
+
+// NewReader constructs a reader over dir, typically journal/<trader_id>.
+func NewReader(dir string) *Reader {
+	if dir == "" {
+		dir = "journal"
+	}
+	return &Reader{dir: dir}
+}
+
+// Files lists the cycle files in the directory in write order.
+func (r *Reader) Files() ([]string, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, fmt.Errorf("journal: read dir %s: %w", r.dir, err)
+	}
+	var files []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !isJournalFile(name) {
+			continue
+		}
+		files = append(files, filepath.Join(r.dir, name))
+	}
+	// Names embed a UTC second timestamp then the writer sequence, so the
+	// lexical order is the write order.
+	sort.Strings(files)
+	return files, nil
+}
+
+// isJournalFile matches per-cycle files and rotated segments, compressed or not.
+func isJournalFile(name string) bool {
+	name = strings.TrimSuffix(name, ".gz")
+	return (strings.HasPrefix(name, "cycle_") && strings.HasSuffix(name, ".json")) ||
+		(strings.HasPrefix(name, "cycles_") && strings.HasSuffix(name, ".jsonl"))
+}
+
+// Cycles returns the records matching filter, oldest first.
+func (r *Reader) Cycles(filter Filter) ([]*CycleRecord, error) {
+	files, err := r.Files()
+	if err != nil {
+		return nil, err
+	}
+	var out []*CycleRecord
+	for _, path := range files {
+		recs, err := ReadCycles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, rec := range recs {
+			if filter.Match(rec) {
+				out = append(out, rec)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Timestamp.Before(out[j].Timestamp) })
+	return out, nil
+}
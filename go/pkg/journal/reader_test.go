@@ -0,0 +1,149 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReaderRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, ok := range []bool{true, false, true} {
+		_, err := w.WriteCycle(&CycleRecord{
+			Timestamp:     base.Add(time.Duration(i) * time.Hour),
+			TraderID:      "t1",
+			PromptDigest:  "digest",
+			CoTTrace:      "thinking",
+			DecisionsJSON: `[{"symbol":"BTC","action":"open_long"}]`,
+			Actions:       []map[string]any{{"symbol": "BTC", "result": "ok"}},
+			Success:       ok,
+		})
+		require.NoError(t, err)
+	}
+	// Unrelated files in the directory are ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0o644))
+
+	r := NewReader(dir)
+	all, err := r.Cycles(Filter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, []int{1, 2, 3}, []int{all[0].CycleNumber, all[1].CycleNumber, all[2].CycleNumber})
+	assert.Equal(t, "thinking", all[0].CoTTrace)
+	assert.Equal(t, `[{"symbol":"BTC","action":"open_long"}]`, all[0].DecisionsJSON)
+	assert.Equal(t, "ok", all[0].Actions[0]["result"])
+	assert.True(t, all[0].Timestamp.Equal(base))
+
+	failed := false
+	failures, err := r.Cycles(Filter{Success: &failed})
+	require.NoError(t, err)
+	require.Len(t, failures, 1)
+	assert.Equal(t, 2, failures[0].CycleNumber)
+
+	window, err := r.Cycles(Filter{From: base.Add(time.Hour), To: base.Add(2 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, window, 1)
+	assert.Equal(t, 2, window[0].CycleNumber)
+
+	other, err := r.Cycles(Filter{TraderID: "t2"})
+	require.NoError(t, err)
+	assert.Empty(t, other)
+}
+
+func TestReaderMissingDir(t *testing.T) {
+	_, err := NewReader(filepath.Join(t.TempDir(), "absent")).Cycles(Filter{})
+	assert.Error(t, err)
+}
+
+func TestReaderReadsCompressedCycleFiles(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir, WithCompression())
+	path, err := w.WriteCycle(&CycleRecord{TraderID: "t1", CoTTrace: "long reasoning", Success: true})
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(path, ".json.gz"))
+
+	rec, err := ReadCycle(path)
+	require.NoError(t, err)
+	assert.Equal(t, "long reasoning", rec.CoTTrace)
+
+	// A plain file written before compression was enabled reads alongside it.
+	_, err = NewWriter(dir).WriteCycle(&CycleRecord{TraderID: "t1", Timestamp: rec.Timestamp.Add(time.Minute)})
+	require.NoError(t, err)
+	all, err := NewReader(dir).Cycles(Filter{})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "long reasoning", all[0].CoTTrace)
+}
+
+func TestWriterRotatesBySize(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		t.Run(fmt.Sprintf("compress=%v", compress), func(t *testing.T) {
+			dir := t.TempDir()
+			opts := []WriterOption{WithMaxFileBytes(2048)}
+			suffix := ".jsonl"
+			if compress {
+				opts = append(opts, WithCompression())
+				suffix += ".gz"
+			}
+			w := NewWriter(dir, opts...)
+			base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+			const n = 60
+			for i := 0; i < n; i++ {
+				_, err := w.WriteCycle(&CycleRecord{
+					Timestamp: base.Add(time.Duration(i) * time.Minute),
+					TraderID:  "t1",
+					CoTTrace:  strings.Repeat(fmt.Sprintf("step %d considered; ", i), 20),
+					Success:   true,
+				})
+				require.NoError(t, err)
+			}
+
+			r := NewReader(dir)
+			files, err := r.Files()
+			require.NoError(t, err)
+			require.Greater(t, len(files), 1, "segments roll once they reach the size limit")
+			for _, f := range files {
+				assert.True(t, strings.HasSuffix(f, suffix), f)
+			}
+
+			all, err := r.Cycles(Filter{})
+			require.NoError(t, err)
+			require.Len(t, all, n)
+			for i, rec := range all {
+				assert.Equal(t, i+1, rec.CycleNumber)
+			}
+			assert.Contains(t, all[n-1].CoTTrace, fmt.Sprintf("step %d", n-1))
+		})
+	}
+}
+
+func TestWriterRotatesDaily(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir, WithRotateEvery(24*time.Hour), WithCompression())
+	day := time.Date(2025, 3, 1, 22, 0, 0, 0, time.UTC)
+	var paths []string
+	for i := 0; i < 4; i++ {
+		path, err := w.WriteCycle(&CycleRecord{Timestamp: day.Add(time.Duration(i) * time.Hour), TraderID: "t1"})
+		require.NoError(t, err)
+		paths = append(paths, path)
+	}
+	assert.Equal(t, paths[0], paths[1], "same UTC day shares a segment")
+	assert.NotEqual(t, paths[1], paths[2], "midnight UTC starts a new segment")
+	assert.Equal(t, paths[2], paths[3])
+
+	recs, err := ReadCycles(paths[2])
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+	assert.Equal(t, 3, recs[0].CycleNumber)
+
+	all, err := NewReader(dir).Cycles(Filter{From: day.Add(2 * time.Hour)})
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
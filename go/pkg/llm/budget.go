@@ -0,0 +1,71 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is returned by Chat once the configured daily spend
+// budget has been used up. The budget resets at UTC midnight.
+var ErrBudgetExceeded = errors.New("llm: daily spend budget exceeded")
+
+// spendBudget accumulates estimated request cost per UTC day. A zero limit
+// disables the budget.
+type spendBudget struct {
+	mu    sync.Mutex
+	limit float64
+	day   string
+	spent float64
+	now   func() time.Time
+}
+
+func newSpendBudget(limitUSD float64) *spendBudget {
+	return &spendBudget{limit: limitUSD, now: time.Now}
+}
+
+// rollLocked resets the accumulated spend when the UTC day changes.
+func (b *spendBudget) rollLocked() {
+	day := b.now().UTC().Format(time.DateOnly)
+	if day != b.day {
+		b.day = day
+		b.spent = 0
+	}
+}
+
+// check returns ErrBudgetExceeded when today's spend has reached the limit.
+func (b *spendBudget) check() error {
+	if b == nil || b.limit <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rollLocked()
+	if b.spent >= b.limit {
+		return fmt.Errorf("%w: spent %.4f usd of %.4f usd on %s", ErrBudgetExceeded, b.spent, b.limit, b.day)
+	}
+	return nil
+}
+
+// add records the cost of a completed request against today's budget.
+func (b *spendBudget) add(costUSD float64) {
+	if b == nil || b.limit <= 0 || costUSD <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rollLocked()
+	b.spent += costUSD
+}
+
+// SpentTodayUSD reports the estimated spend accumulated since UTC midnight.
+func (c *Client) SpentTodayUSD() float64 {
+	if c.budget == nil {
+		return 0
+	}
+	c.budget.mu.Lock()
+	defer c.budget.mu.Unlock()
+	c.budget.rollLocked()
+	return c.budget.spent
+}
@@ -0,0 +1,129 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ResponseCache stores completed chat responses keyed by prompt digest.
+// Implementations must be safe for concurrent use; a Redis-backed cache can
+// satisfy it by serialising ChatResponse as JSON.
+type ResponseCache interface {
+	Get(ctx context.Context, key string) (*ChatResponse, bool)
+	Set(ctx context.Context, key string, resp *ChatResponse, ttl time.Duration)
+}
+
+// WithResponseCache makes Chat serve identical prompts from cache for ttl.
+// Streaming requests are never cached.
+func WithResponseCache(cache ResponseCache, ttl time.Duration) ClientOption {
+	return func(opts *clientOptions) {
+		opts.cache = cache
+		opts.cacheTTL = ttl
+	}
+}
+
+// MemoryCache is an in-process ResponseCache.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+	now     func() time.Time
+}
+
+type memoryCacheEntry struct {
+	resp    ChatResponse
+	expires time.Time
+}
+
+// NewMemoryCache returns an empty in-memory response cache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]memoryCacheEntry), now: time.Now}
+}
+
+// Get returns a copy of the unexpired response stored under key.
+func (m *MemoryCache) Get(_ context.Context, key string) (*ChatResponse, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !m.now().Before(entry.expires) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	resp := entry.resp
+	resp.Choices = append([]Choice(nil), entry.resp.Choices...)
+	return &resp, true
+}
+
+// Set stores a copy of resp under key until ttl elapses, dropping any
+// entries that have already expired.
+func (m *MemoryCache) Set(_ context.Context, key string, resp *ChatResponse, ttl time.Duration) {
+	if resp == nil || ttl <= 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	for k, entry := range m.entries {
+		if !now.Before(entry.expires) {
+			delete(m.entries, k)
+		}
+	}
+	stored := *resp
+	stored.Choices = append([]Choice(nil), resp.Choices...)
+	m.entries[key] = memoryCacheEntry{resp: stored, expires: now.Add(ttl)}
+}
+
+// cacheKey returns the cache key for req, or "" when req must not be cached.
+// The key is DigestString of the prompt (messages plus the settings that
+// shape the answer) joined with the resolved model and temperature.
+func (c *Client) cacheKey(req *ChatRequest, modelID string) string {
+	if c.cache == nil || c.cacheTTL <= 0 || req.NoCache || req.Stream {
+		return ""
+	}
+	prompt, err := json.Marshal(struct {
+		Messages            []Message       `json:"messages"`
+		ResponseFormat      *ResponseFormat `json:"response_format,omitempty"`
+		Tools               []Tool          `json:"tools,omitempty"`
+		ToolChoice          string          `json:"tool_choice,omitempty"`
+		MaxCompletionTokens *int            `json:"max_completion_tokens,omitempty"`
+		TopP                *float64        `json:"top_p,omitempty"`
+		Routing             *RoutingConfig  `json:"routing,omitempty"`
+	}{req.Messages, req.ResponseFormat, req.Tools, req.ToolChoice, req.MaxCompletionTokens, req.TopP, req.Routing})
+	if err != nil {
+		return ""
+	}
+	temperature := "default"
+	if req.Temperature != nil {
+		temperature = strconv.FormatFloat(*req.Temperature, 'f', -1, 64)
+	} else if modelCfg, ok := c.config.Model(c.requestAlias(req)); ok && modelCfg.Temperature != nil {
+		temperature = strconv.FormatFloat(*modelCfg.Temperature, 'f', -1, 64)
+	}
+	return DigestString(string(prompt)) + ":" + modelID + ":" + temperature
+}
+
+// cachedResponse returns the cached response for key marked as a cache hit.
+// Hits consumed no tokens, so their usage is reported as zero.
+func (c *Client) cachedResponse(ctx context.Context, key string) (*ChatResponse, bool) {
+	if key == "" {
+		return nil, false
+	}
+	resp, ok := c.cache.Get(ctx, key)
+	if !ok || resp == nil {
+		return nil, false
+	}
+	resp.Usage = Usage{}
+	resp.Cached = true
+	return resp, true
+}
+
+func (c *Client) storeResponse(ctx context.Context, key string, resp *ChatResponse) {
+	if key == "" || resp == nil {
+		return
+	}
+	c.cache.Set(ctx, key, resp, c.cacheTTL)
+}
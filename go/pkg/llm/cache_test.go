@@ -0,0 +1,93 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newCountingChatServer(t *testing.T, calls *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id":"chatcmpl-cache",
+			"object":"chat.completion",
+			"created":1730366400,
+			"model":"openai/gpt-5",
+			"choices":[{"index":0,"finish_reason":"stop","logprobs":null,"message":{"role":"assistant","content":"cached answer"}}],
+			"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}
+		}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newCachingClient(t *testing.T, serverURL string, ttl time.Duration) *Client {
+	t.Helper()
+	cfg := &Config{
+		BaseURL:      serverURL,
+		APIKey:       "test-key",
+		DefaultModel: "gpt-5",
+		Timeout:      5 * time.Second,
+		LogLevel:     "error",
+		Models: map[string]ModelConfig{
+			"gpt-5": {Provider: "openai", ModelName: "openai/gpt-5"},
+		},
+	}
+	client, err := NewClient(cfg, WithResponseCache(NewMemoryCache(), ttl))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestClientChat_ServesIdenticalPromptFromCache(t *testing.T) {
+	var calls int32
+	server := newCountingChatServer(t, &calls)
+	client := newCachingClient(t, server.URL, time.Minute)
+	ctx := context.Background()
+	req := &ChatRequest{Messages: []Message{{Role: "user", Content: "ping"}}}
+
+	first, err := client.Chat(ctx, req)
+	require.NoError(t, err)
+	require.False(t, first.Cached)
+	require.Equal(t, 15, first.Usage.TotalTokens)
+
+	second, err := client.Chat(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls), "second identical call must not reach the server")
+	require.True(t, second.Cached)
+	require.Equal(t, "cached answer", second.Choices[0].Message.Content)
+	require.Zero(t, second.Usage.TotalTokens)
+
+	temp := 0.2
+	_, err = client.Chat(ctx, &ChatRequest{Messages: req.Messages, Temperature: &temp})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls), "a different temperature is a different key")
+
+	_, err = client.Chat(ctx, &ChatRequest{Messages: req.Messages, NoCache: true})
+	require.NoError(t, err)
+	require.Equal(t, int32(3), atomic.LoadInt32(&calls), "NoCache must bypass the cache")
+}
+
+func TestMemoryCacheExpires(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewMemoryCache()
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	cache.Set(ctx, "k", &ChatResponse{ID: "a"}, time.Minute)
+	got, ok := cache.Get(ctx, "k")
+	require.True(t, ok)
+	require.Equal(t, "a", got.ID)
+
+	now = now.Add(time.Minute)
+	_, ok = cache.Get(ctx, "k")
+	require.False(t, ok)
+}
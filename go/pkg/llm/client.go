@@ -36,6 +36,24 @@ type Client struct {
 	httpClient   *http.Client
 	// defaultRouting is applied when using zenmux/auto with no explicit Routing provided
 	defaultRouting *RoutingConfig
+	// slots enforces per-model MaxConcurrentRequests
+	slots modelSlots
+	// limiters enforces per-model RequestsPerMinute
+	limiters modelLimiters
+	// budget enforces Config.DailyBudgetUSD across all requests
+	budget *spendBudget
+	// endpoints is the failover chain for Chat; endpoints[0] is the primary
+	// and shares openaiClient.
+	endpoints []chatEndpoint
+	// cache serves identical non-streaming prompts for cacheTTL when set
+	cache    ResponseCache
+	cacheTTL time.Duration
+}
+
+// chatEndpoint pairs an endpoint's configuration with its SDK client.
+type chatEndpoint struct {
+	cfg    EndpointConfig
+	openai *openai.Client
 }
 
 // ClientOption configures optional client behaviour.
@@ -46,6 +64,8 @@ type clientOptions struct {
 	retry        *RetryHandler
 	httpClient   *http.Client
 	openaiClient *openai.Client
+	cache        ResponseCache
+	cacheTTL     time.Duration
 }
 
 // WithLogger injects a custom logger implementation.
@@ -106,16 +126,21 @@ func NewClient(cfg *Config, opts ...ClientOption) (*Client, error) {
 	} else {
 		retryHandler = NewRetryHandler(RetryConfig{
 			MaxRetries: clientCfg.MaxRetries,
+			Adaptive:   clientCfg.AdaptiveRetry,
 		})
 	}
 
-	var oaClient *openai.Client
-	if optState.openaiClient != nil {
-		oaClient = optState.openaiClient
-	} else {
+	chain := clientCfg.endpointChain()
+	endpoints := make([]chatEndpoint, len(chain))
+	for i, ep := range chain {
+		endpoints[i].cfg = ep
+		if i == 0 && optState.openaiClient != nil {
+			endpoints[i].openai = optState.openaiClient
+			continue
+		}
 		oaOpts := []option.RequestOption{
-			option.WithAPIKey(clientCfg.APIKey),
-			option.WithBaseURL(clientCfg.BaseURL),
+			option.WithAPIKey(ep.APIKey),
+			option.WithBaseURL(ep.BaseURL),
 		}
 		if clientCfg.Timeout > 0 {
 			oaOpts = append(oaOpts, option.WithRequestTimeout(clientCfg.Timeout))
@@ -124,8 +149,9 @@ func NewClient(cfg *Config, opts ...ClientOption) (*Client, error) {
 			oaOpts = append(oaOpts, option.WithHTTPClient(optState.httpClient))
 		}
 		clientVal := openai.NewClient(oaOpts...)
-		oaClient = &clientVal
+		endpoints[i].openai = &clientVal
 	}
+	oaClient := endpoints[0].openai
 
 	c := &Client{
 		config:       clientCfg,
@@ -133,6 +159,12 @@ func NewClient(cfg *Config, opts ...ClientOption) (*Client, error) {
 		logger:       logger,
 		retryHandler: retryHandler,
 		httpClient:   optState.httpClient,
+		slots:        newModelSlots(clientCfg.Models),
+		limiters:     newModelLimiters(clientCfg.Models),
+		budget:       newSpendBudget(clientCfg.DailyBudgetUSD),
+		endpoints:    endpoints,
+		cache:        optState.cache,
+		cacheTTL:     optState.cacheTTL,
 	}
 
 	// NOTE: zenmux/auto routing is currently unstable (returns HTTP 500).
@@ -178,6 +210,24 @@ func (c *Client) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, err
 	if err != nil {
 		return nil, err
 	}
+	cacheKey := c.cacheKey(req, modelID)
+	if resp, ok := c.cachedResponse(ctx, cacheKey); ok {
+		c.logger.Debug(ctx, "llm chat cache hit", Fields{"model": modelID})
+		return resp, nil
+	}
+	if err := c.budget.check(); err != nil {
+		c.logger.Error(ctx, err, Fields{"model": modelID})
+		return nil, err
+	}
+
+	if err := c.limiters.wait(ctx, c.requestAlias(req)); err != nil {
+		return nil, err
+	}
+	release, err := c.slots.acquire(ctx, c.requestAlias(req))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
 	c.logger.Info(ctx, "llm chat request", Fields{
 		"model":    modelID,
@@ -193,7 +243,19 @@ func (c *Client) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, err
 		if reqCopy.Routing == nil && c.defaultRouting != nil {
 			reqCopy.Routing = c.defaultRouting
 		}
-		return c.chatRaw(ctx, &reqCopy, modelID)
+		var resp *ChatResponse
+		err := c.withFailover(ctx, modelID, func(ep *chatEndpoint) error {
+			var rawErr error
+			resp, rawErr = c.chatRaw(ctx, ep, &reqCopy, modelID)
+			return rawErr
+		})
+		if err != nil {
+			return nil, err
+		}
+		resp.Usage.CostUSD = c.config.EstimateCost(c.requestAlias(req), resp.Usage)
+		c.budget.add(resp.Usage.CostUSD)
+		c.storeResponse(ctx, cacheKey, resp)
+		return resp, nil
 	}
 
 	start := time.Now()
@@ -203,22 +265,27 @@ func (c *Client) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, err
 	})
 
 	var completion *openai.ChatCompletion
-	err = c.retryHandler.Do(ctx, func() error {
-		resp, callErr := c.openaiClient.Chat.Completions.New(ctx, params)
-		if callErr != nil {
-			c.logger.Error(ctx, fmt.Errorf("chat completion failed: %w", callErr), Fields{
-				"model": modelID,
-			})
-			return callErr
-		}
-		completion = resp
-		return nil
+	err = c.withFailover(ctx, modelID, func(ep *chatEndpoint) error {
+		return c.retryHandler.Do(ctx, func() error {
+			resp, callErr := ep.openai.Chat.Completions.New(ctx, params)
+			if callErr != nil {
+				c.logger.Error(ctx, fmt.Errorf("chat completion failed: %w", callErr), Fields{
+					"model":    modelID,
+					"endpoint": ep.cfg.Name,
+				})
+				return callErr
+			}
+			completion = resp
+			return nil
+		})
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	result := convertCompletion(completion)
+	result.Usage.CostUSD = c.config.EstimateCost(c.requestAlias(req), result.Usage)
+	c.budget.add(result.Usage.CostUSD)
 	respText := ""
 	if len(result.Choices) > 0 {
 		respText = strings.TrimSpace(result.Choices[0].Message.Content)
@@ -228,14 +295,52 @@ func (c *Client) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, err
 		"duration_ms":       time.Since(start).Milliseconds(),
 		"prompt_tokens":     result.Usage.PromptTokens,
 		"completion_tokens": result.Usage.CompletionTokens,
+		"cost_usd":          result.Usage.CostUSD,
 		"response":          respText,
 	})
 
+	c.storeResponse(ctx, cacheKey, result)
 	return result, nil
 }
 
+// withFailover runs call against each endpoint in order until one succeeds.
+// call is expected to retry internally, so moving on means the current
+// endpoint has exhausted its retries. Cancellation of ctx stops the chain.
+func (c *Client) withFailover(ctx context.Context, modelID string, call func(ep *chatEndpoint) error) error {
+	if len(c.endpoints) == 1 {
+		return call(&c.endpoints[0])
+	}
+	var errs []error
+	for i := range c.endpoints {
+		ep := &c.endpoints[i]
+		err := call(ep)
+		if err == nil {
+			if i > 0 {
+				c.logger.Info(ctx, "llm chat served by fallback endpoint", Fields{
+					"model":    modelID,
+					"endpoint": ep.cfg.Name,
+				})
+			}
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("endpoint %s: %w", ep.cfg.Name, err))
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return errors.Join(errs...)
+		}
+		if i+1 < len(c.endpoints) {
+			c.logger.Warn(ctx, "llm endpoint failed, failing over", Fields{
+				"model":    modelID,
+				"endpoint": ep.cfg.Name,
+				"next":     c.endpoints[i+1].cfg.Name,
+				"error":    err.Error(),
+			})
+		}
+	}
+	return fmt.Errorf("llm: all %d endpoints failed: %w", len(c.endpoints), errors.Join(errs...))
+}
+
 // chatRaw posts a raw JSON body to support Zenmux auto-routing extensions.
-func (c *Client) chatRaw(ctx context.Context, req *ChatRequest, modelID string) (*ChatResponse, error) {
+func (c *Client) chatRaw(ctx context.Context, ep *chatEndpoint, req *ChatRequest, modelID string) (*ChatResponse, error) {
 	if c.httpClient == nil {
 		c.httpClient = &http.Client{Timeout: c.config.Timeout}
 	}
@@ -259,6 +364,13 @@ func (c *Client) chatRaw(ctx context.Context, req *ChatRequest, modelID string)
 			if m.ToolCallID != "" {
 				item["tool_call_id"] = m.ToolCallID
 			}
+		case "assistant":
+			if m.Name != "" {
+				item["name"] = m.Name
+			}
+			if len(m.ToolCalls) > 0 {
+				item["tool_calls"] = m.ToolCalls
+			}
 		default:
 			if m.Name != "" {
 				item["name"] = m.Name
@@ -307,13 +419,13 @@ func (c *Client) chatRaw(ctx context.Context, req *ChatRequest, modelID string)
 	}
 
 	// POST to <base>/chat/completions with retry/backoff
-	url := strings.TrimRight(c.config.BaseURL, "/") + "/chat/completions"
+	url := strings.TrimRight(ep.cfg.BaseURL, "/") + "/chat/completions"
 	data, _ := json.Marshal(body)
 
 	var completion *openai.ChatCompletion
 	if err := c.retryHandler.Do(ctx, func() error {
 		httpReq, _ := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
-		httpReq.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+		httpReq.Header.Set("Authorization", "Bearer "+ep.cfg.APIKey)
 		httpReq.Header.Set("Content-Type", "application/json")
 
 		resp, callErr := c.httpClient.Do(httpReq)
@@ -366,6 +478,7 @@ func summarizeMessages(msgs []Message) string {
 }
 
 // ChatStream initiates a streaming completion call. The returned channel closes once the stream is exhausted.
+// Streaming calls never read from or populate the response cache.
 func (c *Client) ChatStream(ctx context.Context, req *ChatRequest) (<-chan StreamResponse, error) {
 	if req == nil {
 		return nil, errors.New("llm: request cannot be nil")
@@ -376,19 +489,35 @@ func (c *Client) ChatStream(ctx context.Context, req *ChatRequest) (<-chan Strea
 	if err != nil {
 		return nil, err
 	}
+	if err := c.budget.check(); err != nil {
+		return nil, err
+	}
 
+	if err := c.limiters.wait(ctx, c.requestAlias(req)); err != nil {
+		return nil, err
+	}
+	release, err := c.slots.acquire(ctx, c.requestAlias(req))
+	if err != nil {
+		return nil, err
+	}
 	stream := c.openaiClient.Chat.Completions.NewStreaming(ctx, params)
 	if stream == nil {
+		release()
 		return nil, errors.New("llm: streaming not supported")
 	}
 
 	out := make(chan StreamResponse)
 	go func(s *ssestream.Stream[openai.ChatCompletionChunk]) {
+		defer release()
 		defer close(out)
 		defer s.Close()
 		for s.Next() {
-			chunk := s.Current()
-			out <- convertChunk(chunk)
+			resp := convertChunk(s.Current())
+			if resp.Usage != nil {
+				resp.Usage.CostUSD = c.config.EstimateCost(c.requestAlias(req), *resp.Usage)
+				c.budget.add(resp.Usage.CostUSD)
+			}
+			out <- resp
 		}
 		if err := s.Err(); err != nil {
 			c.logger.Error(ctx, fmt.Errorf("stream failed: %w", err), Fields{"model": modelID})
@@ -398,7 +527,10 @@ func (c *Client) ChatStream(ctx context.Context, req *ChatRequest) (<-chan Strea
 	return out, nil
 }
 
-// ChatStructured enforces structured output using JSON schema and decodes the result into target.
+// ChatStructured enforces structured output and decodes the result into
+// target. Models configured with structured_output "function_call" receive a
+// forced StructuredToolName tool call whose arguments carry the response;
+// all others use a json_schema response format.
 func (c *Client) ChatStructured(ctx context.Context, req *ChatRequest, target interface{}) (*ChatResponse, error) {
 	if target == nil {
 		return nil, errors.New("llm: structured target cannot be nil")
@@ -415,32 +547,84 @@ func (c *Client) ChatStructured(ctx context.Context, req *ChatRequest, target in
 	}
 
 	var strict bool = true
-	format := &ResponseFormat{
-		Type:        "json_schema",
-		Name:        deriveSchemaName(value),
-		Schema:      schema,
-		Description: "Structured response",
-		Strict:      &strict,
-	}
-
 	structuredReq := *req
-	structuredReq.ResponseFormat = format
+	functionCall := c.structuredOutputMode(req) == StructuredOutputFunctionCall
+	if functionCall {
+		structuredReq.Tools = []Tool{{
+			Type: "function",
+			Function: FunctionDefinition{
+				Name:        StructuredToolName,
+				Description: "Submit the structured response",
+				Parameters:  schema,
+				Strict:      &strict,
+			},
+		}}
+		structuredReq.ToolChoice = StructuredToolName
+	} else {
+		structuredReq.ResponseFormat = &ResponseFormat{
+			Type:        "json_schema",
+			Name:        deriveSchemaName(value),
+			Schema:      schema,
+			Description: "Structured response",
+			Strict:      &strict,
+		}
+	}
 	resp, err := c.Chat(ctx, &structuredReq)
 	if err != nil {
 		return nil, err
 	}
+	content, err := c.decodeStructured(ctx, resp, functionCall, target)
+	var parseErr *StructuredParseError
+	if err == nil || !c.config.RepairStructured || !errors.As(err, &parseErr) {
+		return resp, err
+	}
 
+	// Give the model one chance to fix its own output.
+	c.logger.Info(ctx, "llm structured repair attempt", Fields{"model": resp.Model})
+	repairReq := structuredReq
+	repairReq.Messages = append(append([]Message(nil), structuredReq.Messages...),
+		Message{Role: "assistant", Content: content},
+		Message{Role: "user", Content: fmt.Sprintf("Your previous response was not valid JSON: %v. Reply with only the corrected JSON object matching the schema.", parseErr.Err)},
+	)
+	repaired, err := c.Chat(ctx, &repairReq)
+	if err != nil {
+		return resp, fmt.Errorf("%w; repair attempt failed: %v", parseErr, err)
+	}
+	repaired.Usage = resp.Usage.Add(repaired.Usage)
+	if _, err := c.decodeStructured(ctx, repaired, functionCall, target); err != nil {
+		return repaired, err
+	}
+	return repaired, nil
+}
+
+// decodeStructured parses resp into target and returns the content it read.
+// Failures are a *RefusalError for refusal-shaped output and a
+// *StructuredParseError otherwise.
+func (c *Client) decodeStructured(ctx context.Context, resp *ChatResponse, functionCall bool, target interface{}) (string, error) {
 	if len(resp.Choices) == 0 {
-		return nil, errors.New("llm: empty structured response")
+		return "", errors.New("llm: empty structured response")
 	}
 	content := strings.TrimSpace(resp.Choices[0].Message.Content)
+	if functionCall {
+		if args, ok := structuredToolArguments(resp.Choices[0]); ok {
+			content = strings.TrimSpace(args)
+		}
+	}
 	if err := ParseStructured(content, target); err != nil {
-		c.logger.Error(ctx, fmt.Errorf("parse structured response: %w", err), Fields{
+		if detectRefusal(content) {
+			refusal := &RefusalError{Text: content}
+			c.logger.Error(ctx, refusal, Fields{
+				"model": resp.Model,
+			})
+			return content, refusal
+		}
+		parseErr := &StructuredParseError{Model: resp.Model, Content: content, Err: err}
+		c.logger.Error(ctx, parseErr, Fields{
 			"model": resp.Model,
 		})
-		return nil, err
+		return content, parseErr
 	}
-	return resp, nil
+	return content, nil
 }
 
 // GetConfig returns an immutable copy of the client configuration.
@@ -492,6 +676,13 @@ func (c *Client) buildChatParams(req *ChatRequest) (openai.ChatCompletionNewPara
 		params.ResponseFormat = rf
 	}
 
+	if len(req.Tools) > 0 {
+		params.Tools = toToolParams(req.Tools)
+	}
+	if choice, ok := toToolChoiceParam(req.ToolChoice); ok {
+		params.ToolChoice = choice
+	}
+
 	if req.Temperature != nil {
 		params.Temperature = openai.Float(*req.Temperature)
 	} else if modelCfg.Temperature != nil {
@@ -528,6 +719,9 @@ func buildMessageParams(msgs []Message) ([]openai.ChatCompletionMessageParamUnio
 			result = append(result, param)
 		case "assistant":
 			param := openai.ChatCompletionMessageParamOfAssistant(m.Content)
+			if len(m.ToolCalls) > 0 && param.OfAssistant != nil {
+				param.OfAssistant.ToolCalls = toToolCallParams(m.ToolCalls)
+			}
 			result = append(result, param)
 		case "tool":
 			param := openai.ToolMessage(m.Content, m.ToolCallID)
@@ -589,6 +783,51 @@ func toResponseFormatParam(format *ResponseFormat) (openai.ChatCompletionNewPara
 	}
 }
 
+func toToolParams(tools []Tool) []openai.ChatCompletionToolParam {
+	params := make([]openai.ChatCompletionToolParam, 0, len(tools))
+	for _, tool := range tools {
+		fn := shared.FunctionDefinitionParam{
+			Name:       tool.Function.Name,
+			Parameters: shared.FunctionParameters(tool.Function.Parameters),
+		}
+		if desc := strings.TrimSpace(tool.Function.Description); desc != "" {
+			fn.Description = openai.String(desc)
+		}
+		if tool.Function.Strict != nil {
+			fn.Strict = openai.Bool(*tool.Function.Strict)
+		}
+		params = append(params, openai.ChatCompletionToolParam{Function: fn})
+	}
+	return params
+}
+
+func toToolChoiceParam(choice string) (openai.ChatCompletionToolChoiceOptionUnionParam, bool) {
+	choice = strings.TrimSpace(choice)
+	switch strings.ToLower(choice) {
+	case "":
+		return openai.ChatCompletionToolChoiceOptionUnionParam{}, false
+	case "auto", "none", "required":
+		return openai.ChatCompletionToolChoiceOptionUnionParam{OfAuto: openai.String(strings.ToLower(choice))}, true
+	default:
+		return openai.ChatCompletionToolChoiceOptionParamOfChatCompletionNamedToolChoice(openai.ChatCompletionNamedToolChoiceFunctionParam{Name: choice}), true
+	}
+}
+
+// toToolCallParams converts assistant tool calls back into SDK params.
+func toToolCallParams(calls []ToolCall) []openai.ChatCompletionMessageToolCallParam {
+	params := make([]openai.ChatCompletionMessageToolCallParam, 0, len(calls))
+	for _, call := range calls {
+		params = append(params, openai.ChatCompletionMessageToolCallParam{
+			ID: call.ID,
+			Function: openai.ChatCompletionMessageToolCallFunctionParam{
+				Name:      call.Function.Name,
+				Arguments: call.Function.Arguments,
+			},
+		})
+	}
+	return params
+}
+
 func convertCompletion(resp *openai.ChatCompletion) *ChatResponse {
 	if resp == nil {
 		return nil
@@ -3,11 +3,14 @@ package llm
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -389,3 +392,562 @@ func TestConvertToolCalls(t *testing.T) {
 		require.Equal(t, "call_2", result[1].ID)
 	})
 }
+
+func TestClientChatMaxConcurrentRequests(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		inFlight    int
+		maxInFlight int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id":"chatcmpl-1",
+			"object":"chat.completion",
+			"created":1730366400,
+			"model":"openai/gpt-5",
+			"choices":[{"index":0,"finish_reason":"stop","logprobs":null,"message":{"role":"assistant","content":"ok","tool_calls":[]}}],
+			"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}
+		}`))
+	}))
+	defer server.Close()
+
+	cfg := &Config{
+		BaseURL:      server.URL,
+		APIKey:       "test-key",
+		DefaultModel: "gpt-5",
+		Timeout:      5 * time.Second,
+		MaxRetries:   1,
+		LogLevel:     "error",
+		Models: map[string]ModelConfig{
+			"gpt-5": {Provider: "openai", ModelName: "openai/gpt-5", MaxConcurrentRequests: 1},
+		},
+	}
+	client, err := NewClient(cfg, WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = client.Chat(ctx, &ChatRequest{Model: "gpt-5", Messages: []Message{{Role: "user", Content: "hi"}}})
+		}(i)
+	}
+	wg.Wait()
+	for _, e := range errs {
+		require.NoError(t, e)
+	}
+	require.Equal(t, 1, maxInFlight, "overlapping calls should be serialized")
+}
+
+func TestClientChatMaxConcurrentRequestsHonoursContext(t *testing.T) {
+	cfg := &Config{
+		BaseURL:      "http://127.0.0.1:1",
+		APIKey:       "test-key",
+		DefaultModel: "gpt-5",
+		Timeout:      5 * time.Second,
+		MaxRetries:   1,
+		LogLevel:     "error",
+		Models: map[string]ModelConfig{
+			"gpt-5": {ModelName: "openai/gpt-5", MaxConcurrentRequests: 1},
+		},
+	}
+	client, err := NewClient(cfg)
+	require.NoError(t, err)
+
+	release, err := client.slots.acquire(context.Background(), "gpt-5")
+	require.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.Chat(ctx, &ChatRequest{Messages: []Message{{Role: "user", Content: "hi"}}})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClientChatRequestsPerMinute(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		arrivals = map[string][]time.Time{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Model string `json:"model"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		arrivals[body.Model] = append(arrivals[body.Model], time.Now())
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id":"chatcmpl-1",
+			"object":"chat.completion",
+			"created":1730366400,
+			"model":"openai/gpt-5",
+			"choices":[{"index":0,"finish_reason":"stop","logprobs":null,"message":{"role":"assistant","content":"ok","tool_calls":[]}}],
+			"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}
+		}`))
+	}))
+	defer server.Close()
+
+	cfg := &Config{
+		BaseURL:      server.URL,
+		APIKey:       "test-key",
+		DefaultModel: "gpt-5",
+		Timeout:      5 * time.Second,
+		MaxRetries:   1,
+		LogLevel:     "error",
+		Models: map[string]ModelConfig{
+			"gpt-5":  {Provider: "openai", ModelName: "openai/gpt-5", RequestsPerMinute: 1},
+			"claude": {Provider: "anthropic", ModelName: "anthropic/claude", RequestsPerMinute: 1},
+		},
+	}
+	client, err := NewClient(cfg, WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+	defer client.Close()
+	require.Equal(t, time.Minute, client.limiters["gpt-5"].interval)
+
+	// Keep 1 request per window but shrink the window so the test is fast.
+	const window = 100 * time.Millisecond
+	client.limiters["gpt-5"] = newRateLimiter(1, window)
+	client.limiters["claude"] = newRateLimiter(1, window)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		model := "gpt-5"
+		if i == len(errs)-1 {
+			model = "claude"
+		}
+		wg.Add(1)
+		go func(i int, model string) {
+			defer wg.Done()
+			_, errs[i] = client.Chat(ctx, &ChatRequest{Model: model, Messages: []Message{{Role: "user", Content: "hi"}}})
+		}(i, model)
+	}
+	wg.Wait()
+	for _, e := range errs {
+		require.NoError(t, e)
+	}
+
+	gpt := arrivals["openai/gpt-5"]
+	require.Len(t, gpt, 3)
+	sort.Slice(gpt, func(i, j int) bool { return gpt[i].Before(gpt[j]) })
+	for i := 1; i < len(gpt); i++ {
+		require.GreaterOrEqual(t, gpt[i].Sub(gpt[i-1]), window-10*time.Millisecond, "requests to one model should be spaced by the limit")
+	}
+	// The other model has its own bucket and is not queued behind gpt-5.
+	require.Len(t, arrivals["anthropic/claude"], 1)
+	require.Less(t, arrivals["anthropic/claude"][0].Sub(start), window)
+}
+
+func TestClientChatRequestsPerMinuteHonoursContext(t *testing.T) {
+	cfg := &Config{
+		BaseURL:      "http://127.0.0.1:1",
+		APIKey:       "test-key",
+		DefaultModel: "gpt-5",
+		Timeout:      5 * time.Second,
+		MaxRetries:   1,
+		LogLevel:     "error",
+		Models: map[string]ModelConfig{
+			"gpt-5": {ModelName: "openai/gpt-5", RequestsPerMinute: 1},
+		},
+	}
+	client, err := NewClient(cfg)
+	require.NoError(t, err)
+
+	// Spend the only token of the minute.
+	require.NoError(t, client.limiters.wait(context.Background(), "gpt-5"))
+	reserved := client.limiters["gpt-5"].next
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.Chat(ctx, &ChatRequest{Messages: []Message{{Role: "user", Content: "hi"}}})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	// The abandoned slot is handed back.
+	require.Equal(t, reserved, client.limiters["gpt-5"].next)
+}
+
+func TestClientChatStructuredRefusal(t *testing.T) {
+	var content string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		msg, _ := json.Marshal(content)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id":"chatcmpl-refusal",
+			"object":"chat.completion",
+			"created":1730366400,
+			"model":"openai/gpt-5",
+			"choices":[{"index":0,"finish_reason":"stop","logprobs":null,"message":{"role":"assistant","content":` + string(msg) + `,"tool_calls":[]}}],
+			"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}
+		}`))
+	}))
+	defer server.Close()
+
+	cfg := &Config{
+		BaseURL:      server.URL,
+		APIKey:       "test-key",
+		DefaultModel: "gpt-5",
+		Timeout:      5 * time.Second,
+		MaxRetries:   1,
+		LogLevel:     "error",
+	}
+	client, err := NewClient(cfg, WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+	defer client.Close()
+
+	type Decision struct {
+		Action string `json:"action"`
+	}
+	req := &ChatRequest{Messages: []Message{{Role: "user", Content: "decide"}}}
+
+	content = "I'm sorry, but I can't provide trading decisions."
+	var decision Decision
+	_, err = client.ChatStructured(context.Background(), req, &decision)
+	require.ErrorIs(t, err, ErrModelRefusal)
+	var refusal *RefusalError
+	require.ErrorAs(t, err, &refusal)
+	require.Equal(t, content, refusal.Text)
+
+	content = `{"action": "BUY"`
+	_, err = client.ChatStructured(context.Background(), req, &decision)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrModelRefusal, "malformed JSON is a parse failure, not a refusal")
+}
+
+func TestClientChatStructuredRepair(t *testing.T) {
+	replies := []string{`{"action": "BUY"`, `{"action": "BUY"}`}
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, body)
+		content := replies[0]
+		if len(requests) > 1 {
+			content = replies[1]
+		}
+		msg, _ := json.Marshal(content)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id":"chatcmpl-repair",
+			"object":"chat.completion",
+			"created":1730366400,
+			"model":"openai/gpt-5",
+			"choices":[{"index":0,"finish_reason":"stop","logprobs":null,"message":{"role":"assistant","content":` + string(msg) + `,"tool_calls":[]}}],
+			"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}
+		}`))
+	}))
+	defer server.Close()
+
+	type Decision struct {
+		Action string `json:"action"`
+	}
+	req := &ChatRequest{Messages: []Message{{Role: "user", Content: "decide"}}}
+	newClient := func(repair bool) *Client {
+		cfg := &Config{
+			BaseURL:          server.URL,
+			APIKey:           "test-key",
+			DefaultModel:     "gpt-5",
+			Timeout:          5 * time.Second,
+			MaxRetries:       1,
+			LogLevel:         "error",
+			RepairStructured: repair,
+		}
+		client, err := NewClient(cfg, WithHTTPClient(server.Client()))
+		require.NoError(t, err)
+		return client
+	}
+
+	// Without repair the error names the model and quotes the raw content.
+	var decision Decision
+	_, err := newClient(false).ChatStructured(context.Background(), req, &decision)
+	var parseErr *StructuredParseError
+	require.ErrorAs(t, err, &parseErr)
+	require.Equal(t, "openai/gpt-5", parseErr.Model)
+	require.Equal(t, replies[0], parseErr.Content)
+	require.Contains(t, err.Error(), "openai/gpt-5")
+	require.Contains(t, err.Error(), `{\"action\": \"BUY\"`)
+	require.Len(t, requests, 1)
+
+	requests = nil
+	resp, err := newClient(true).ChatStructured(context.Background(), req, &decision)
+	require.NoError(t, err)
+	require.Equal(t, "BUY", decision.Action)
+	require.Equal(t, 30, resp.Usage.TotalTokens, "usage covers both attempts")
+	require.Len(t, requests, 2)
+
+	msgs, ok := requests[1]["messages"].([]any)
+	require.True(t, ok)
+	require.Len(t, msgs, 3)
+	echo := msgs[1].(map[string]any)
+	require.Equal(t, "assistant", echo["role"])
+	require.Equal(t, replies[0], echo["content"])
+	repair := msgs[2].(map[string]any)
+	require.Equal(t, "user", repair["role"])
+	require.Contains(t, repair["content"], "Your previous response was not valid JSON")
+}
+
+func TestStructuredParseErrorTruncatesContent(t *testing.T) {
+	err := &StructuredParseError{Model: "m", Content: strings.Repeat("x", maxErrorContent+100), Err: errors.New("bad")}
+	require.Contains(t, err.Error(), strings.Repeat("x", maxErrorContent)+"...")
+	require.NotContains(t, err.Error(), strings.Repeat("x", maxErrorContent+1))
+}
+
+func TestClientChatStructuredFunctionCall(t *testing.T) {
+	var captured map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &captured)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id":"chatcmpl-tool",
+			"object":"chat.completion",
+			"created":1730366400,
+			"model":"anthropic/claude",
+			"choices":[{"index":0,"finish_reason":"tool_calls","logprobs":null,"message":{
+				"role":"assistant",
+				"content":"",
+				"tool_calls":[{"id":"call_1","type":"function","function":{"name":"submit_decision","arguments":"{\"action\":\"SELL\",\"symbol\":\"ETH\",\"confidence\":0.7}"}}]
+			}}],
+			"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}
+		}`))
+	}))
+	defer server.Close()
+
+	cfg := &Config{
+		BaseURL:      server.URL,
+		APIKey:       "test-key",
+		DefaultModel: "claude",
+		Timeout:      5 * time.Second,
+		MaxRetries:   1,
+		LogLevel:     "error",
+		Models: map[string]ModelConfig{
+			"claude": {ModelName: "anthropic/claude", StructuredOutput: StructuredOutputFunctionCall},
+		},
+	}
+	client, err := NewClient(cfg, WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+	defer client.Close()
+
+	type Decision struct {
+		Action     string  `json:"action"`
+		Symbol     string  `json:"symbol"`
+		Confidence float64 `json:"confidence"`
+	}
+	var decision Decision
+	_, err = client.ChatStructured(context.Background(), &ChatRequest{Messages: []Message{{Role: "user", Content: "decide"}}}, &decision)
+	require.NoError(t, err)
+	require.Equal(t, "SELL", decision.Action)
+	require.Equal(t, "ETH", decision.Symbol)
+	require.InDelta(t, 0.7, decision.Confidence, 1e-9)
+
+	require.NotContains(t, captured, "response_format", "function-call mode should not send a response format")
+	tools, ok := captured["tools"].([]any)
+	require.True(t, ok)
+	require.Len(t, tools, 1)
+	fn := tools[0].(map[string]any)["function"].(map[string]any)
+	require.Equal(t, StructuredToolName, fn["name"])
+	require.Contains(t, fn, "parameters")
+	choice, ok := captured["tool_choice"].(map[string]any)
+	require.True(t, ok, "tool call should be forced")
+	require.Equal(t, StructuredToolName, choice["function"].(map[string]any)["name"])
+}
+
+func TestClientChatDailyBudget(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id":"chatcmpl-1",
+			"object":"chat.completion",
+			"created":1730366400,
+			"model":"openai/gpt-5",
+			"choices":[{"index":0,"finish_reason":"stop","logprobs":null,"message":{"role":"assistant","content":"ok","tool_calls":[]}}],
+			"usage":{"prompt_tokens":1000,"completion_tokens":500,"total_tokens":1500}
+		}`))
+	}))
+	defer server.Close()
+
+	cfg := &Config{
+		BaseURL:        server.URL,
+		APIKey:         "test-key",
+		DefaultModel:   "gpt-5",
+		Timeout:        5 * time.Second,
+		MaxRetries:     1,
+		LogLevel:       "error",
+		DailyBudgetUSD: 0.05,
+		Models: map[string]ModelConfig{
+			// 1000 prompt + 500 completion tokens cost 0.02 USD per call.
+			"gpt-5": {Provider: "openai", ModelName: "openai/gpt-5", InputCostPerMTok: 10, OutputCostPerMTok: 20},
+		},
+	}
+	client, err := NewClient(cfg, WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+	defer client.Close()
+	now := time.Date(2025, 1, 6, 23, 0, 0, 0, time.UTC)
+	client.budget.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	req := &ChatRequest{Model: "gpt-5", Messages: []Message{{Role: "user", Content: "hi"}}}
+	for i := 0; i < 3; i++ {
+		resp, err := client.Chat(ctx, req)
+		require.NoError(t, err, "call %d should fit within the budget", i+1)
+		require.InDelta(t, 0.02, resp.Usage.CostUSD, 1e-9)
+	}
+	require.InDelta(t, 0.06, client.SpentTodayUSD(), 1e-9)
+
+	_, err = client.Chat(ctx, req)
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+	_, err = client.ChatStructured(ctx, req, &struct{ Action string }{})
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+	require.EqualValues(t, 3, atomic.LoadInt32(&calls), "blocked calls must not reach the provider")
+
+	now = now.Add(2 * time.Hour) // past UTC midnight
+	_, err = client.Chat(ctx, req)
+	require.NoError(t, err, "the budget resets at UTC midnight")
+	require.InDelta(t, 0.02, client.SpentTodayUSD(), 1e-9)
+}
+
+func TestClientChat_FailsOverToNextEndpoint(t *testing.T) {
+	var primaryCalls, fallbackCalls int32
+	var primaryAuth, fallbackAuth atomic.Value
+
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&primaryCalls, 1)
+		primaryAuth.Store(r.Header.Get("Authorization"))
+		http.Error(w, `{"error":{"message":"upstream down"}}`, http.StatusInternalServerError)
+	}))
+	defer primary.Close()
+
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fallbackCalls, 1)
+		fallbackAuth.Store(r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id":"chatcmpl-fallback",
+			"object":"chat.completion",
+			"created":1730366400,
+			"model":"openai/gpt-5",
+			"choices":[{"index":0,"finish_reason":"stop","logprobs":null,"message":{"role":"assistant","content":"served by fallback"}}],
+			"usage":{"prompt_tokens":4,"completion_tokens":3,"total_tokens":7}
+		}`))
+	}))
+	defer fallback.Close()
+
+	cfg := &Config{
+		BaseURL:      primary.URL,
+		APIKey:       "primary-key",
+		DefaultModel: "gpt-5",
+		Timeout:      5 * time.Second,
+		MaxRetries:   1,
+		LogLevel:     "error",
+		Models: map[string]ModelConfig{
+			"gpt-5": {Provider: "openai", ModelName: "openai/gpt-5"},
+		},
+		Endpoints: []EndpointConfig{
+			{Name: "backup", BaseURL: fallback.URL, APIKey: "fallback-key"},
+		},
+	}
+
+	client, err := NewClient(cfg, WithRetryHandler(NewRetryHandler(RetryConfig{
+		MaxRetries:     1,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	})))
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := client.Chat(ctx, &ChatRequest{
+		Messages: []Message{{Role: "user", Content: "ping"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Choices, 1)
+	require.Equal(t, "served by fallback", resp.Choices[0].Message.Content)
+
+	require.GreaterOrEqual(t, atomic.LoadInt32(&primaryCalls), int32(2), "primary should be retried before failing over")
+	require.Equal(t, int32(1), atomic.LoadInt32(&fallbackCalls))
+	require.Equal(t, "Bearer primary-key", primaryAuth.Load())
+	require.Equal(t, "Bearer fallback-key", fallbackAuth.Load())
+}
+
+func TestClientChat_AllEndpointsFail(t *testing.T) {
+	failing := func(calls *int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(calls, 1)
+			http.Error(w, `{"error":{"message":"bad request"}}`, http.StatusBadRequest)
+		}))
+	}
+	var firstCalls, secondCalls int32
+	first := failing(&firstCalls)
+	defer first.Close()
+	second := failing(&secondCalls)
+	defer second.Close()
+
+	cfg := &Config{
+		BaseURL:      first.URL,
+		APIKey:       "k1",
+		DefaultModel: "gpt-5",
+		Timeout:      5 * time.Second,
+		LogLevel:     "error",
+		Endpoints:    []EndpointConfig{{BaseURL: second.URL, APIKey: "k2"}},
+	}
+	client, err := NewClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Chat(context.Background(), &ChatRequest{
+		Messages: []Message{{Role: "user", Content: "ping"}},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "all 2 endpoints failed")
+	require.Contains(t, err.Error(), "endpoints[0]")
+	require.Equal(t, int32(1), atomic.LoadInt32(&firstCalls))
+	require.Equal(t, int32(1), atomic.LoadInt32(&secondCalls))
+}
+
+func TestBuildMessageParams_EchoesAssistantToolCalls(t *testing.T) {
+	params, err := buildMessageParams([]Message{
+		{Role: "assistant", ToolCalls: []ToolCall{{ID: "call_1", Type: "function", Function: FunctionCall{Name: "get_positions", Arguments: "{}"}}}},
+		{Role: "tool", ToolCallID: "call_1", Content: "[]"},
+	})
+	require.NoError(t, err)
+	require.Len(t, params, 2)
+
+	data, err := json.Marshal(params)
+	require.NoError(t, err)
+	var decoded []map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	calls, ok := decoded[0]["tool_calls"].([]any)
+	require.True(t, ok, "assistant message should carry tool_calls: %s", data)
+	require.Len(t, calls, 1)
+	call := calls[0].(map[string]any)
+	require.Equal(t, "call_1", call["id"])
+	require.Equal(t, "get_positions", call["function"].(map[string]any)["name"])
+	require.Equal(t, "call_1", decoded[1]["tool_call_id"])
+}
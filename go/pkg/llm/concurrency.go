@@ -0,0 +1,115 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// modelSlots bounds in-flight requests per model alias. Aliases without a
+// configured MaxConcurrentRequests are unbounded.
+type modelSlots map[string]chan struct{}
+
+func newModelSlots(models map[string]ModelConfig) modelSlots {
+	slots := make(modelSlots)
+	for alias, m := range models {
+		if m.MaxConcurrentRequests > 0 {
+			slots[alias] = make(chan struct{}, m.MaxConcurrentRequests)
+		}
+	}
+	return slots
+}
+
+// acquire blocks until a slot for alias is free or ctx is done. The returned
+// release func must be called exactly once when the request completes.
+func (s modelSlots) acquire(ctx context.Context, alias string) (func(), error) {
+	sem, ok := s[alias]
+	if !ok {
+		return func() {}, nil
+	}
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// modelLimiters paces requests per model alias. Aliases without a configured
+// RequestsPerMinute are not rate limited.
+type modelLimiters map[string]*rateLimiter
+
+func newModelLimiters(models map[string]ModelConfig) modelLimiters {
+	limiters := make(modelLimiters)
+	for alias, m := range models {
+		if m.RequestsPerMinute > 0 {
+			limiters[alias] = newRateLimiter(m.RequestsPerMinute, time.Minute)
+		}
+	}
+	return limiters
+}
+
+// wait blocks until alias may send another request or ctx is done.
+func (l modelLimiters) wait(ctx context.Context, alias string) error {
+	limiter, ok := l[alias]
+	if !ok {
+		return nil
+	}
+	return limiter.wait(ctx)
+}
+
+// rateLimiter is a token bucket holding a single token that refills every
+// window/limit, so requests are spaced evenly instead of bursting at the
+// start of each window.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time // earliest time the next request may start
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{interval: window / time.Duration(limit)}
+}
+
+// wait reserves the next free slot and sleeps until it arrives. A caller
+// whose ctx ends first returns the slot so later callers are not delayed.
+func (r *rateLimiter) wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	now := time.Now()
+	slot := r.next
+	if slot.Before(now) {
+		slot = now
+	}
+	r.next = slot.Add(r.interval)
+	r.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		r.mu.Lock()
+		if r.next.Equal(slot.Add(r.interval)) {
+			r.next = slot
+		}
+		r.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// requestAlias resolves the model alias a request targets.
+func (c *Client) requestAlias(req *ChatRequest) string {
+	if alias := strings.TrimSpace(req.Model); alias != "" {
+		return alias
+	}
+	return c.config.DefaultModel
+}
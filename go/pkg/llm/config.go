@@ -38,10 +38,29 @@ type Config struct {
 	Models       map[string]ModelConfig `yaml:"models"`
 	// Optional defaults for Zenmux auto-routing
 	RoutingDefaults *RoutingConfig `yaml:"routing_defaults,omitempty"`
+	// AdaptiveRetry lengthens retry backoff as the provider's recent error
+	// rate and latency rise.
+	AdaptiveRetry bool `yaml:"adaptive_retry"`
+	// DailyBudgetUSD caps the estimated spend per UTC day; once reached, Chat
+	// returns ErrBudgetExceeded until midnight. Zero disables the cap.
+	DailyBudgetUSD float64 `yaml:"daily_budget_usd"`
+	// Endpoints lists fallback providers tried in order once retries against
+	// BaseURL are exhausted. Each endpoint keeps its own base URL and key.
+	Endpoints []EndpointConfig `yaml:"endpoints,omitempty"`
+	// RepairStructured retries ChatStructured once, quoting the parse error
+	// back to the model, when its response is not valid for the schema.
+	RepairStructured bool `yaml:"repair_structured"`
 
 	timeoutRaw string `yaml:"timeout"`
 }
 
+// EndpointConfig identifies an OpenAI-compatible provider endpoint.
+type EndpointConfig struct {
+	Name    string `yaml:"name,omitempty"`
+	BaseURL string `yaml:"base_url"`
+	APIKey  string `yaml:"api_key"`
+}
+
 // ModelConfig defines defaults for a particular model alias.
 type ModelConfig struct {
 	Provider            string   `yaml:"provider"`
@@ -49,8 +68,31 @@ type ModelConfig struct {
 	Temperature         *float64 `yaml:"temperature,omitempty"`
 	MaxCompletionTokens *int     `yaml:"max_completion_tokens,omitempty"`
 	TopP                *float64 `yaml:"top_p,omitempty"`
+	// MaxConcurrentRequests caps in-flight requests for this model; 0 means unlimited.
+	MaxConcurrentRequests int `yaml:"max_concurrent_requests,omitempty"`
+	// RequestsPerMinute paces Chat/ChatStream calls for this model with a
+	// token bucket shared by every caller of the client; 0 means unlimited.
+	RequestsPerMinute int `yaml:"requests_per_minute,omitempty"`
+	// StructuredOutput selects how ChatStructured requests structured output:
+	// "json_schema" (default) or "function_call".
+	StructuredOutput string `yaml:"structured_output,omitempty"`
+	// InputCostPerMTok and OutputCostPerMTok price prompt and completion
+	// tokens in USD per million; zero leaves cost unestimated.
+	InputCostPerMTok  float64 `yaml:"input_cost_per_mtok,omitempty"`
+	OutputCostPerMTok float64 `yaml:"output_cost_per_mtok,omitempty"`
+}
+
+// EstimateCost prices usage with the model's configured token costs.
+func (m ModelConfig) EstimateCost(u Usage) float64 {
+	return (float64(u.PromptTokens)*m.InputCostPerMTok + float64(u.CompletionTokens)*m.OutputCostPerMTok) / 1e6
 }
 
+// Structured output modes for ModelConfig.StructuredOutput.
+const (
+	StructuredOutputJSONSchema   = "json_schema"
+	StructuredOutputFunctionCall = "function_call"
+)
+
 // LoadConfig reads configuration from disk.
 func LoadConfig(path string) (*Config, error) {
 	confkit.LoadDotenvOnce()
@@ -76,14 +118,18 @@ func MustLoad() *Config {
 func LoadConfigFromReader(r io.Reader) (*Config, error) {
 	confkit.LoadDotenvOnce()
 	var raw struct {
-		BaseURL         string                 `yaml:"base_url"`
-		APIKey          string                 `yaml:"api_key"`
-		DefaultModel    string                 `yaml:"default_model"`
-		Timeout         string                 `yaml:"timeout"`
-		MaxRetries      int                    `yaml:"max_retries"`
-		LogLevel        string                 `yaml:"log_level"`
-		Models          map[string]ModelConfig `yaml:"models"`
-		RoutingDefaults *RoutingConfig         `yaml:"routing_defaults"`
+		BaseURL          string                 `yaml:"base_url"`
+		APIKey           string                 `yaml:"api_key"`
+		DefaultModel     string                 `yaml:"default_model"`
+		Timeout          string                 `yaml:"timeout"`
+		MaxRetries       int                    `yaml:"max_retries"`
+		LogLevel         string                 `yaml:"log_level"`
+		Models           map[string]ModelConfig `yaml:"models"`
+		RoutingDefaults  *RoutingConfig         `yaml:"routing_defaults"`
+		AdaptiveRetry    bool                   `yaml:"adaptive_retry"`
+		DailyBudgetUSD   float64                `yaml:"daily_budget_usd"`
+		Endpoints        []EndpointConfig       `yaml:"endpoints"`
+		RepairStructured bool                   `yaml:"repair_structured"`
 	}
 
 	data, err := io.ReadAll(r)
@@ -95,14 +141,18 @@ func LoadConfigFromReader(r io.Reader) (*Config, error) {
 	}
 
 	cfg := &Config{
-		BaseURL:         raw.BaseURL,
-		APIKey:          raw.APIKey,
-		DefaultModel:    raw.DefaultModel,
-		MaxRetries:      raw.MaxRetries,
-		LogLevel:        raw.LogLevel,
-		Models:          raw.Models,
-		RoutingDefaults: raw.RoutingDefaults,
-		timeoutRaw:      raw.Timeout,
+		BaseURL:          raw.BaseURL,
+		APIKey:           raw.APIKey,
+		DefaultModel:     raw.DefaultModel,
+		MaxRetries:       raw.MaxRetries,
+		LogLevel:         raw.LogLevel,
+		Models:           raw.Models,
+		RoutingDefaults:  raw.RoutingDefaults,
+		AdaptiveRetry:    raw.AdaptiveRetry,
+		DailyBudgetUSD:   raw.DailyBudgetUSD,
+		Endpoints:        raw.Endpoints,
+		RepairStructured: raw.RepairStructured,
+		timeoutRaw:       raw.Timeout,
 	}
 
 	cfg.applyDefaults()
@@ -133,6 +183,30 @@ func (c *Config) Validate() error {
 	if c.MaxRetries < 0 {
 		return errors.New("llm config: max_retries cannot be negative")
 	}
+	if c.DailyBudgetUSD < 0 {
+		return errors.New("llm config: daily_budget_usd cannot be negative")
+	}
+	for i, ep := range c.Endpoints {
+		if strings.TrimSpace(ep.BaseURL) == "" {
+			return fmt.Errorf("llm config: endpoints[%d].base_url is required", i)
+		}
+		if strings.TrimSpace(ep.APIKey) == "" {
+			return fmt.Errorf("llm config: endpoints[%d].api_key is required", i)
+		}
+	}
+	for alias, m := range c.Models {
+		if m.MaxConcurrentRequests < 0 {
+			return fmt.Errorf("llm config: models.%s.max_concurrent_requests cannot be negative", alias)
+		}
+		if m.RequestsPerMinute < 0 {
+			return fmt.Errorf("llm config: models.%s.requests_per_minute cannot be negative", alias)
+		}
+		switch m.StructuredOutput {
+		case "", StructuredOutputJSONSchema, StructuredOutputFunctionCall:
+		default:
+			return fmt.Errorf("llm config: models.%s.structured_output %q must be %s or %s", alias, m.StructuredOutput, StructuredOutputJSONSchema, StructuredOutputFunctionCall)
+		}
+	}
 	return nil
 }
 
@@ -145,6 +219,16 @@ func (c *Config) Model(name string) (ModelConfig, bool) {
 	return modelCfg, ok
 }
 
+// EstimateCost prices usage for the named model alias, returning zero when the
+// alias is unknown or unpriced.
+func (c *Config) EstimateCost(alias string, u Usage) float64 {
+	modelCfg, ok := c.Model(alias)
+	if !ok {
+		return 0
+	}
+	return modelCfg.EstimateCost(u)
+}
+
 // Clone returns a shallow copy of the configuration.
 func (c *Config) Clone() *Config {
 	if c == nil {
@@ -158,9 +242,26 @@ func (c *Config) Clone() *Config {
 			cp.Models[k] = v
 		}
 	}
+	if c.Endpoints != nil {
+		cp.Endpoints = append([]EndpointConfig(nil), c.Endpoints...)
+	}
 	return &cp
 }
 
+// endpointChain returns the primary endpoint followed by the configured
+// fallbacks, naming any endpoint left unnamed.
+func (c *Config) endpointChain() []EndpointConfig {
+	chain := make([]EndpointConfig, 0, 1+len(c.Endpoints))
+	chain = append(chain, EndpointConfig{Name: "primary", BaseURL: c.BaseURL, APIKey: c.APIKey})
+	for i, ep := range c.Endpoints {
+		if strings.TrimSpace(ep.Name) == "" {
+			ep.Name = fmt.Sprintf("endpoints[%d]", i)
+		}
+		chain = append(chain, ep)
+	}
+	return chain
+}
+
 func (c *Config) applyDefaults() {
 	if strings.TrimSpace(c.BaseURL) == "" {
 		c.BaseURL = defaultBaseURL
@@ -177,6 +278,10 @@ func (c *Config) applyEnvOverrides() {
 	c.BaseURL = expandAndOverride(c.BaseURL, envBaseURL)
 	c.APIKey = expandAndOverride(c.APIKey, envAPIKey)
 	c.DefaultModel = expandAndOverride(c.DefaultModel, envDefaultModel)
+	for i := range c.Endpoints {
+		c.Endpoints[i].BaseURL = os.ExpandEnv(c.Endpoints[i].BaseURL)
+		c.Endpoints[i].APIKey = os.ExpandEnv(c.Endpoints[i].APIKey)
+	}
 
 	if raw := os.Getenv(envTimeout); raw != "" {
 		c.timeoutRaw = raw
@@ -18,6 +18,7 @@ api_key: "test-api-key"
 default_model: "gpt-4"
 timeout: "30s"
 max_retries: 3
+adaptive_retry: true
 log_level: "info"
 
 models:
@@ -38,6 +39,7 @@ models:
 		require.Equal(t, "gpt-4", cfg.DefaultModel)
 		require.Equal(t, 30*time.Second, cfg.Timeout)
 		require.Equal(t, 3, cfg.MaxRetries)
+		require.True(t, cfg.AdaptiveRetry)
 		require.Equal(t, "info", cfg.LogLevel)
 	})
 
@@ -166,6 +168,30 @@ func TestConfigValidate(t *testing.T) {
 			expectErr: true,
 			errMsg:    "max_retries cannot be negative",
 		},
+		{
+			name: "unknown structured output mode",
+			cfg: &Config{
+				BaseURL:      "https://api.example.com",
+				APIKey:       "test-key",
+				DefaultModel: "gpt-4",
+				Timeout:      30 * time.Second,
+				Models:       map[string]ModelConfig{"gpt-4": {StructuredOutput: "xml"}},
+			},
+			expectErr: true,
+			errMsg:    "structured_output",
+		},
+		{
+			name: "negative daily budget",
+			cfg: &Config{
+				BaseURL:        "https://api.example.com",
+				APIKey:         "test-key",
+				DefaultModel:   "gpt-4",
+				Timeout:        30 * time.Second,
+				DailyBudgetUSD: -1,
+			},
+			expectErr: true,
+			errMsg:    "daily_budget_usd",
+		},
 	}
 
 	for _, tt := range tests {
@@ -278,6 +304,20 @@ func TestConfigModel(t *testing.T) {
 	})
 }
 
+func TestConfigEstimateCost(t *testing.T) {
+	cfg := &Config{
+		Models: map[string]ModelConfig{
+			"priced":   {ModelName: "m1", InputCostPerMTok: 3, OutputCostPerMTok: 15},
+			"unpriced": {ModelName: "m2"},
+		},
+	}
+	usage := Usage{PromptTokens: 200_000, CompletionTokens: 10_000, TotalTokens: 210_000}
+
+	require.InDelta(t, 0.75, cfg.EstimateCost("priced", usage), 1e-9) // 0.2M*3 + 0.01M*15
+	require.Zero(t, cfg.EstimateCost("unpriced", usage))
+	require.Zero(t, cfg.EstimateCost("unknown", usage))
+}
+
 func TestConfigClone(t *testing.T) {
 	temp := 0.7
 	maxCompletionTokens := 1024
@@ -347,3 +387,35 @@ timeout: "30s"
 	require.Equal(t, "https://expanded.com", cfg.BaseURL)
 	require.Equal(t, "expanded-key", cfg.APIKey)
 }
+
+func TestLoadConfigEndpoints(t *testing.T) {
+	t.Setenv("BACKUP_LLM_KEY", "backup-secret")
+	data := `
+base_url: "https://primary.example.com"
+api_key: "primary"
+default_model: "gpt-5"
+endpoints:
+  - name: backup
+    base_url: "https://backup.example.com/v1"
+    api_key: "${BACKUP_LLM_KEY}"
+`
+	cfg, err := LoadConfigFromReader(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, cfg.Endpoints, 1)
+	require.Equal(t, "backup-secret", cfg.Endpoints[0].APIKey)
+
+	chain := cfg.endpointChain()
+	require.Len(t, chain, 2)
+	require.Equal(t, "primary", chain[0].Name)
+	require.Equal(t, "https://primary.example.com", chain[0].BaseURL)
+	require.Equal(t, "backup", chain[1].Name)
+
+	_, err = LoadConfigFromReader(strings.NewReader(`
+base_url: "https://primary.example.com"
+api_key: "primary"
+default_model: "gpt-5"
+endpoints:
+  - base_url: "https://backup.example.com/v1"
+`))
+	require.ErrorContains(t, err, "endpoints[0].api_key is required")
+}
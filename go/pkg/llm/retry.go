@@ -6,6 +6,7 @@ import (
 	"math"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/openai/openai-go"
@@ -15,6 +16,11 @@ const (
 	defaultInitialBackoff = 200 * time.Millisecond
 	defaultMaxBackoff     = 3 * time.Second
 	defaultBackoffFactor  = 2.0
+
+	defaultAdaptiveWindow = 20
+	// adaptiveErrorGain is how many extra multiples of InitialBackoff are
+	// added at a 100% observed error rate.
+	adaptiveErrorGain = 4.0
 )
 
 // RetryConfig encapsulates exponential backoff settings.
@@ -23,11 +29,22 @@ type RetryConfig struct {
 	InitialBackoff time.Duration
 	MaxBackoff     time.Duration
 	Multiplier     float64
+	// Adaptive scales the starting backoff with the provider's recent error
+	// rate and latency instead of always starting from InitialBackoff.
+	Adaptive bool
+	// AdaptiveWindow is the approximate number of recent attempts the
+	// rolling estimates cover. Defaults to 20.
+	AdaptiveWindow int
 }
 
 // RetryHandler executes retryable operations with backoff.
 type RetryHandler struct {
 	cfg RetryConfig
+
+	mu        sync.Mutex
+	errorRate float64
+	latency   time.Duration
+	samples   int
 }
 
 // NewRetryHandler constructs a handler with sane defaults.
@@ -44,16 +61,21 @@ func NewRetryHandler(cfg RetryConfig) *RetryHandler {
 	if cfg.MaxRetries < 0 {
 		cfg.MaxRetries = 0
 	}
+	if cfg.AdaptiveWindow <= 0 {
+		cfg.AdaptiveWindow = defaultAdaptiveWindow
+	}
 	return &RetryHandler{cfg: cfg}
 }
 
 // Do executes fn with retries until it succeeds or exhausts attempts.
 func (r *RetryHandler) Do(ctx context.Context, fn func() error) error {
 	var attempt int
-	backoff := r.cfg.InitialBackoff
+	backoff := r.CurrentBackoff()
 
 	for {
+		start := time.Now()
 		err := fn()
+		r.observe(time.Since(start), err)
 		if err == nil {
 			return nil
 		}
@@ -79,6 +101,59 @@ func (r *RetryHandler) Do(ctx context.Context, fn func() error) error {
 	}
 }
 
+// CurrentBackoff returns the delay Do would wait before its first retry.
+// Without Adaptive it is always InitialBackoff. With Adaptive it grows with
+// the rolling error rate, is never shorter than the observed latency weighted
+// by that rate, and is capped at MaxBackoff.
+func (r *RetryHandler) CurrentBackoff() time.Duration {
+	if !r.cfg.Adaptive {
+		return r.cfg.InitialBackoff
+	}
+	r.mu.Lock()
+	errorRate, latency := r.errorRate, r.latency
+	r.mu.Unlock()
+
+	backoff := float64(r.cfg.InitialBackoff) * (1 + adaptiveErrorGain*errorRate)
+	backoff = math.Max(backoff, float64(latency)*errorRate)
+	return time.Duration(math.Min(backoff, float64(r.cfg.MaxBackoff)))
+}
+
+// ErrorRate reports the rolling share of attempts that failed with a
+// retryable error. It is always zero unless Adaptive is set.
+func (r *RetryHandler) ErrorRate() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.errorRate
+}
+
+// observe folds one attempt into the rolling estimates. Non-retryable errors
+// (bad requests, cancellations) say nothing about provider health and are
+// ignored.
+func (r *RetryHandler) observe(elapsed time.Duration, err error) {
+	if !r.cfg.Adaptive {
+		return
+	}
+	failed := 0.0
+	if err != nil {
+		if !shouldRetry(err) {
+			return
+		}
+		failed = 1
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples++
+	// Use a plain average until the window fills so early samples are not
+	// dominated by the zero starting value, then an exponential moving average.
+	alpha := 2.0 / float64(r.cfg.AdaptiveWindow+1)
+	if r.samples < r.cfg.AdaptiveWindow {
+		alpha = 1.0 / float64(r.samples)
+	}
+	r.errorRate += alpha * (failed - r.errorRate)
+	r.latency += time.Duration(alpha * float64(elapsed-r.latency))
+}
+
 func shouldRetry(err error) bool {
 	if err == nil {
 		return false
@@ -255,3 +255,93 @@ type nonTemporaryError struct {
 func (e *nonTemporaryError) Error() string   { return e.msg }
 func (e *nonTemporaryError) Temporary() bool { return false }
 func (e *nonTemporaryError) Timeout() bool   { return false }
+
+func TestRetryHandlerAdaptiveBackoff(t *testing.T) {
+	retryable := &openai.Error{StatusCode: http.StatusServiceUnavailable}
+	newHandler := func() *RetryHandler {
+		return NewRetryHandler(RetryConfig{
+			InitialBackoff: 10 * time.Millisecond,
+			MaxBackoff:     200 * time.Millisecond,
+			Adaptive:       true,
+			AdaptiveWindow: 10,
+		})
+	}
+
+	t.Run("static handler ignores observations", func(t *testing.T) {
+		handler := NewRetryHandler(RetryConfig{InitialBackoff: 10 * time.Millisecond})
+		for i := 0; i < 10; i++ {
+			handler.observe(time.Millisecond, retryable)
+		}
+		require.Equal(t, 10*time.Millisecond, handler.CurrentBackoff())
+		require.Zero(t, handler.ErrorRate())
+	})
+
+	t.Run("backoff grows with rising error rate", func(t *testing.T) {
+		handler := newHandler()
+		for i := 0; i < 10; i++ {
+			handler.observe(time.Millisecond, nil)
+		}
+		require.Equal(t, 10*time.Millisecond, handler.CurrentBackoff())
+
+		prev := handler.CurrentBackoff()
+		for i := 0; i < 10; i++ {
+			handler.observe(time.Millisecond, retryable)
+			next := handler.CurrentBackoff()
+			require.Greater(t, next, prev, "backoff should grow after failure %d", i+1)
+			prev = next
+		}
+		require.Greater(t, handler.ErrorRate(), 0.8)
+		require.LessOrEqual(t, prev, 200*time.Millisecond)
+	})
+
+	t.Run("backoff shrinks as provider recovers", func(t *testing.T) {
+		handler := newHandler()
+		for i := 0; i < 10; i++ {
+			handler.observe(time.Millisecond, retryable)
+		}
+		prev := handler.CurrentBackoff()
+		for i := 0; i < 20; i++ {
+			handler.observe(time.Millisecond, nil)
+			next := handler.CurrentBackoff()
+			require.Less(t, next, prev, "backoff should shrink after success %d", i+1)
+			prev = next
+		}
+		require.Less(t, handler.ErrorRate(), 0.2)
+	})
+
+	t.Run("slow failing provider waits at least its latency share", func(t *testing.T) {
+		handler := newHandler()
+		for i := 0; i < 10; i++ {
+			handler.observe(150*time.Millisecond, retryable)
+		}
+		require.InDelta(t, float64(150*time.Millisecond), float64(handler.CurrentBackoff()), float64(time.Millisecond))
+
+		for i := 0; i < 10; i++ {
+			handler.observe(time.Second, retryable)
+		}
+		require.Equal(t, 200*time.Millisecond, handler.CurrentBackoff(), "backoff is capped at MaxBackoff")
+	})
+
+	t.Run("non-retryable errors do not count", func(t *testing.T) {
+		handler := newHandler()
+		for i := 0; i < 10; i++ {
+			handler.observe(time.Millisecond, &openai.Error{StatusCode: http.StatusBadRequest})
+			handler.observe(time.Millisecond, context.Canceled)
+		}
+		require.Zero(t, handler.ErrorRate())
+		require.Equal(t, 10*time.Millisecond, handler.CurrentBackoff())
+	})
+
+	t.Run("Do feeds the estimate", func(t *testing.T) {
+		handler := NewRetryHandler(RetryConfig{
+			MaxRetries:     2,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     20 * time.Millisecond,
+			Adaptive:       true,
+		})
+		err := handler.Do(context.Background(), func() error { return retryable })
+		require.Error(t, err)
+		require.Equal(t, 1.0, handler.ErrorRate())
+		require.Greater(t, handler.CurrentBackoff(), time.Millisecond)
+	})
+}
@@ -56,6 +56,84 @@ func GenerateSchema(v interface{}) (map[string]interface{}, error) {
 	return schema, nil
 }
 
+// ErrModelRefusal reports that the model answered with prose declining to
+// decide instead of a structured response. Use errors.As with *RefusalError
+// to recover the model's text.
+var ErrModelRefusal = errors.New("llm: model refused to produce a structured response")
+
+// RefusalError carries the text of a refusal-shaped response.
+type RefusalError struct {
+	Text string
+}
+
+func (e *RefusalError) Error() string {
+	if e.Text == "" {
+		return ErrModelRefusal.Error() + ": empty response"
+	}
+	return fmt.Sprintf("%s: %q", ErrModelRefusal.Error(), truncateRefusal(e.Text))
+}
+
+// Unwrap lets errors.Is(err, ErrModelRefusal) match.
+func (e *RefusalError) Unwrap() error { return ErrModelRefusal }
+
+// maxErrorContent bounds the response text quoted in StructuredParseError.
+const maxErrorContent = 500
+
+// StructuredParseError reports a structured response that was not valid for
+// the target type, with the model and the offending content for debugging.
+type StructuredParseError struct {
+	Model   string
+	Content string
+	Err     error
+}
+
+func (e *StructuredParseError) Error() string {
+	content := e.Content
+	if len(content) > maxErrorContent {
+		content = content[:maxErrorContent] + "..."
+	}
+	return fmt.Sprintf("llm: parse structured response from %s: %v; content: %q", e.Model, e.Err, content)
+}
+
+func (e *StructuredParseError) Unwrap() error { return e.Err }
+
+var refusalPhrases = []string{
+	"i'm sorry", "i am sorry", "i apologize", "i apologise",
+	"i cannot", "i can't", "i can not", "i'm unable", "i am unable", "unable to provide",
+	"i won't", "i will not", "as an ai",
+}
+
+// detectRefusal classifies content that failed structured parsing. Empty
+// output, prose without any JSON object, or apology/refusal phrasing count as
+// a refusal; anything else is treated as a genuine parse failure.
+func detectRefusal(content string) bool {
+	trimmed := strings.TrimSpace(content)
+	if trimmed == "" {
+		return true
+	}
+	if !strings.Contains(trimmed, "{") {
+		return true
+	}
+	lower := strings.ToLower(trimmed)
+	if strings.HasPrefix(lower, "{") {
+		return false
+	}
+	for _, phrase := range refusalPhrases {
+		if strings.Contains(lower, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+func truncateRefusal(s string) string {
+	const max = 200
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "..."
+}
+
 // ParseStructured decodes a JSON string into the provided target value.
 func ParseStructured(jsonStr string, target interface{}) error {
 	if target == nil {
@@ -143,3 +221,28 @@ func buildSchemaForType(t reflect.Type) map[string]interface{} {
 		return map[string]interface{}{"type": "string"}
 	}
 }
+
+// StructuredToolName is the function models call to submit a structured
+// response in function-call mode.
+const StructuredToolName = "submit_decision"
+
+// structuredOutputMode resolves the structured output mode for req's model.
+func (c *Client) structuredOutputMode(req *ChatRequest) string {
+	if c.config != nil {
+		if m, ok := c.config.Model(c.requestAlias(req)); ok && m.StructuredOutput != "" {
+			return m.StructuredOutput
+		}
+	}
+	return StructuredOutputJSONSchema
+}
+
+// structuredToolArguments returns the arguments of the StructuredToolName
+// call in choice.
+func structuredToolArguments(choice Choice) (string, bool) {
+	for _, call := range choice.ToolCalls {
+		if call.Function.Name == StructuredToolName {
+			return call.Function.Arguments, true
+		}
+	}
+	return "", false
+}
@@ -358,3 +358,11 @@ func TestBuildSchemaForType(t *testing.T) {
 		require.Equal(t, "array", itemsSchema["type"])
 	})
 }
+
+func TestDetectRefusal(t *testing.T) {
+	require.True(t, detectRefusal(""), "empty output is a non-decision")
+	require.True(t, detectRefusal("Market conditions are unclear today."), "prose without JSON is a non-decision")
+	require.True(t, detectRefusal("I apologize, I cannot comply. {}"), "apology text is a refusal")
+	require.False(t, detectRefusal(`{"action": "BUY",}`), "malformed JSON is a parse failure")
+	require.False(t, detectRefusal("```json\n{\"action\": \"BUY\"\n```"), "fenced JSON is a parse failure")
+}
@@ -9,8 +9,14 @@ type ChatRequest struct {
 	TopP                *float64        `json:"top_p,omitempty"`
 	Stream              bool            `json:"stream,omitempty"`
 	ResponseFormat      *ResponseFormat `json:"response_format,omitempty"`
+	// Tools declares functions the model may call. ToolChoice forces a
+	// function by name; "auto", "none" and "required" pass through as-is.
+	Tools      []Tool `json:"tools,omitempty"`
+	ToolChoice string `json:"tool_choice,omitempty"`
 	// Optional: Zenmux multi-model routing config; used when Model == "zenmux/auto"
 	Routing *RoutingConfig `json:"model_routing_config,omitempty"`
+	// NoCache bypasses the client's response cache for this request.
+	NoCache bool `json:"-"`
 }
 
 // Message represents a chat message in the conversation.
@@ -19,6 +25,9 @@ type Message struct {
 	Content    string `json:"content"`
 	Name       string `json:"name,omitempty"`
 	ToolCallID string `json:"tool_call_id,omitempty"`
+	// ToolCalls echoes an assistant turn's tool invocations back to the
+	// model so the following "tool" messages can answer them.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
 }
 
 // ResponseFormat controls the structure of the assistant response.
@@ -30,6 +39,20 @@ type ResponseFormat struct {
 	Strict      *bool       `json:"strict,omitempty"`
 }
 
+// Tool declares a function the model may call.
+type Tool struct {
+	Type     string             `json:"type"`
+	Function FunctionDefinition `json:"function"`
+}
+
+// FunctionDefinition describes a callable function and its JSON schema parameters.
+type FunctionDefinition struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	Strict      *bool                  `json:"strict,omitempty"`
+}
+
 // RoutingConfig describes Zenmux auto-routing preferences.
 type RoutingConfig struct {
 	AvailableModels []string `json:"available_models"`
@@ -46,6 +69,9 @@ type ChatResponse struct {
 	RawJSON     string   `json:"raw_json,omitempty"`
 	Tier        string   `json:"tier,omitempty"`
 	Fingerprint string   `json:"fingerprint,omitempty"`
+	// Cached reports that the response was served from the response cache;
+	// its Usage is zero because no tokens were spent.
+	Cached bool `json:"cached,omitempty"`
 }
 
 // Choice represents a single completion choice.
@@ -74,6 +100,19 @@ type Usage struct {
 	PromptTokens     int `json:"prompt_tokens"`
 	CompletionTokens int `json:"completion_tokens"`
 	TotalTokens      int `json:"total_tokens"`
+	// CostUSD is estimated from the model's configured token prices; zero
+	// when the model has no pricing.
+	CostUSD float64 `json:"cost_usd,omitempty"`
+}
+
+// Add returns the sum of u and o, for requests spanning several calls.
+func (u Usage) Add(o Usage) Usage {
+	return Usage{
+		PromptTokens:     u.PromptTokens + o.PromptTokens,
+		CompletionTokens: u.CompletionTokens + o.CompletionTokens,
+		TotalTokens:      u.TotalTokens + o.TotalTokens,
+		CostUSD:          u.CostUSD + o.CostUSD,
+	}
 }
 
 // StreamResponse represents a streaming completion chunk.
@@ -0,0 +1,137 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"nof0-api/pkg/exchange"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// markPositionOpened records when symbol's position was opened; adding to a
+// held position keeps the original entry time.
+func (t *VirtualTrader) markPositionOpened(symbol string, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.positionOpenedAt == nil {
+		t.positionOpenedAt = make(map[string]time.Time)
+	}
+	key := fundingKey(symbol)
+	if _, ok := t.positionOpenedAt[key]; !ok {
+		t.positionOpenedAt[key] = at
+	}
+}
+
+// observePositionAges reconciles entry times with the held positions: entries
+// for closed positions are dropped and positions opened outside this process
+// are dated from now. It returns the entry time of each held position.
+func (t *VirtualTrader) observePositionAges(positions []exchange.Position, now time.Time) map[string]time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	held := make(map[string]time.Time, len(positions))
+	for _, p := range positions {
+		if parseFloat(p.Szi) == 0 {
+			continue
+		}
+		key := fundingKey(p.Coin)
+		at, ok := t.positionOpenedAt[key]
+		if !ok {
+			at = now
+		}
+		held[key] = at
+	}
+	t.positionOpenedAt = make(map[string]time.Time, len(held))
+	for key, at := range held {
+		t.positionOpenedAt[key] = at
+	}
+	return held
+}
+
+// agedPositionCloses returns a close decision for every position held longer
+// than ExecGuards.MaxPositionAge.
+func agedPositionCloses(t *VirtualTrader, positions []executorpkg.PositionInfo, now time.Time) []executorpkg.Decision {
+	maxAge := t.ExecGuards.MaxPositionAge
+	if maxAge <= 0 {
+		return nil
+	}
+	var closes []executorpkg.Decision
+	for _, p := range positions {
+		if p.EntryTimeMs <= 0 || p.Quantity == 0 {
+			continue
+		}
+		age := now.Sub(time.UnixMilli(p.EntryTimeMs))
+		if age <= maxAge {
+			continue
+		}
+		action := "close_long"
+		if p.Side == "short" {
+			action = "close_short"
+		}
+		closes = append(closes, executorpkg.Decision{
+			Symbol:    p.Symbol,
+			Action:    action,
+			Reasoning: fmt.Sprintf("position age %s exceeds max_position_age %s", age.Round(time.Second), maxAge),
+		})
+	}
+	return closes
+}
+
+// closeAgedPositions closes positions past ExecGuards.MaxPositionAge ahead
+// of the model's decisions. It returns the journal actions, marked as
+// system-initiated, and the symbols it closed.
+func (m *Manager) closeAgedPositions(ctx context.Context, t *VirtualTrader, positions []executorpkg.PositionInfo, cycle int) ([]map[string]any, map[string]bool, bool) {
+	closes := agedPositionCloses(t, positions, m.now())
+	if len(closes) == 0 {
+		return nil, nil, true
+	}
+	actions := make([]map[string]any, 0, len(closes))
+	closed := make(map[string]bool, len(closes))
+	allOK := true
+	for i := range closes {
+		d := closes[i]
+		d.IdempotencyKey = decisionIntentKey(t.ID, cycle, d)
+		m.recordGuardRejection(t.ID, GuardMaxPositionAge, 1)
+		logx.WithContext(ctx).Infof("manager: trader %s system %s symbol=%s reason=max_position_age detail=%q", t.ID, d.Action, d.Symbol, d.Reasoning)
+		order, execErr := m.executeDecision(t, &d)
+		observeExecution(t.ID, d.Action, order, execErr)
+		act := map[string]any{
+			"symbol":    d.Symbol,
+			"action":    d.Action,
+			"initiator": "system",
+			"reason":    GuardMaxPositionAge,
+			"detail":    d.Reasoning,
+			"result":    "ok",
+		}
+		if order != nil && order.DryRun {
+			act["dry_run"] = true
+		}
+		if execErr != nil {
+			act["result"] = "error"
+			act["error"] = execErr.Error()
+			allOK = false
+			logx.WithContext(ctx).Errorf("manager: trader %s system close symbol=%s error=%v", t.ID, d.Symbol, execErr)
+			m.sendAlert(AlertExecutionError, t.ID, "max_position_age %s %s failed: %v", d.Action, d.Symbol, execErr)
+		} else {
+			closed[fundingKey(d.Symbol)] = true
+		}
+		actions = append(actions, act)
+	}
+	return actions, closed, allOK
+}
+
+// withoutSymbols drops decisions for symbols the manager already acted on.
+func withoutSymbols(decisions []executorpkg.Decision, symbols map[string]bool) []executorpkg.Decision {
+	if len(symbols) == 0 {
+		return decisions
+	}
+	kept := make([]executorpkg.Decision, 0, len(decisions))
+	for _, d := range decisions {
+		if !symbols[fundingKey(d.Symbol)] {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
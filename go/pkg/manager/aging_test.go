@@ -0,0 +1,84 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+	"nof0-api/pkg/journal"
+)
+
+func TestMaxPositionAgeClosesStalePositionBeforeModelDecisions(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+	persist := &fakePersistence{}
+	m := NewManager(nil, nil, nil, nil, persist)
+	m.SetClock(fixedClock{now})
+
+	ex := sim.NewWithEquity(10000)
+	require.NoError(t, ex.SetMarkPrice(ctx, "BTC", 60000))
+	_, err := ex.IOCMarket(ctx, "BTC", true, 0.01, 0, false)
+	require.NoError(t, err)
+	require.NoError(t, ex.SetMarkPrice(ctx, "ETH", 3000))
+	_, err = ex.IOCMarket(ctx, "ETH", false, 0.1, 0, false)
+	require.NoError(t, err)
+
+	// The model wants to keep BTC; the manager closes it regardless.
+	exec := &fakeExecutor{decisions: []executorpkg.Decision{{Symbol: "BTC", Action: "hold"}}}
+	mk := newFakeMarket(testSnapshot("BTC", 60000, 0), testSnapshot("ETH", 3000, 0))
+	vt := newCycleTestTrader(m, "aging", mk, exec)
+	vt.ExchangeProvider = ex
+	off := false
+	vt.ExecGuards = ExecGuards{MaxPositionAge: 24 * time.Hour, EnableSparseDataGuard: &off}
+	vt.Journal = journal.NewWriter(t.TempDir())
+	vt.JournalEnabled = true
+	vt.markPositionOpened("BTC", now.Add(-30*time.Hour))
+	vt.markPositionOpened("ETH", now.Add(-2*time.Hour))
+
+	m.runTraderCycle(ctx, vt)
+
+	require.Equal(t, 1, exec.callCount())
+	entries := map[string]int64{}
+	for _, p := range exec.contexts[0].Positions {
+		entries[p.Symbol] = p.EntryTimeMs
+	}
+	assert.Equal(t, now.Add(-30*time.Hour).UnixMilli(), entries["BTC"], "the executor context carries entry times")
+
+	positions, err := ex.GetPositions(ctx)
+	require.NoError(t, err)
+	require.Len(t, positions, 1, "only the stale position is closed")
+	assert.Equal(t, "ETH", positions[0].Coin)
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardMaxPositionAge])
+
+	require.Len(t, persist.cycles, 1)
+	actions := persist.cycles[0].Cycle.Actions
+	require.Len(t, actions, 1)
+	assert.Equal(t, "BTC", actions[0]["symbol"])
+	assert.Equal(t, "close_long", actions[0]["action"])
+	assert.Equal(t, "system", actions[0]["initiator"])
+	assert.Equal(t, GuardMaxPositionAge, actions[0]["reason"])
+	assert.Equal(t, "ok", actions[0]["result"])
+}
+
+func TestObservePositionAgesDatesUnknownPositionsFromFirstSight(t *testing.T) {
+	ctx := context.Background()
+	ex := sim.NewWithEquity(10000)
+	require.NoError(t, ex.SetMarkPrice(ctx, "SOL", 150))
+	_, err := ex.IOCMarket(ctx, "SOL", true, 1, 0, false)
+	require.NoError(t, err)
+	positions, err := ex.GetPositions(ctx)
+	require.NoError(t, err)
+
+	vt := &VirtualTrader{ID: "ages"}
+	vt.markPositionOpened("DOGE", time.Unix(100, 0))
+	first := time.Unix(1000, 0)
+	ages := vt.observePositionAges(positions, first)
+	assert.Equal(t, map[string]time.Time{"SOL": first}, ages, "closed positions are dropped")
+	ages = vt.observePositionAges(positions, first.Add(time.Hour))
+	assert.Equal(t, first, ages["SOL"], "the first sighting is kept")
+}
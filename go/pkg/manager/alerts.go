@@ -0,0 +1,100 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// Alert event types posted to monitoring.alert_webhook.
+const (
+	AlertTraderPaused   = "trader_paused"
+	AlertDecisionFailed = "decision_failed"
+	AlertExecutionError = "execution_error"
+)
+
+// alertTimeout bounds each webhook delivery.
+const alertTimeout = 5 * time.Second
+
+// Alert is the JSON payload posted to the alert webhook.
+type Alert struct {
+	Event     string    `json:"event"`
+	TraderID  string    `json:"trader_id"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// alertNotifier posts alerts to a webhook in the background so a slow or
+// unreachable endpoint never stalls the trading loop. Failures are logged.
+type alertNotifier struct {
+	url    string
+	client *http.Client
+	wg     sync.WaitGroup
+}
+
+// newAlertNotifier returns nil when no webhook is configured.
+func newAlertNotifier(url string) *alertNotifier {
+	if url == "" {
+		return nil
+	}
+	return &alertNotifier{url: url, client: &http.Client{Timeout: alertTimeout}}
+}
+
+func (n *alertNotifier) send(a Alert) {
+	if n == nil {
+		return
+	}
+	body, err := json.Marshal(a)
+	if err != nil {
+		logx.Errorf("manager: encode alert event=%s trader=%s err=%v", a.Event, a.TraderID, err)
+		return
+	}
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), alertTimeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+		if err != nil {
+			logx.Errorf("manager: build alert request event=%s trader=%s err=%v", a.Event, a.TraderID, err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := n.client.Do(req)
+		if err != nil {
+			logx.Errorf("manager: post alert event=%s trader=%s err=%v", a.Event, a.TraderID, err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			logx.Errorf("manager: post alert event=%s trader=%s status=%d", a.Event, a.TraderID, resp.StatusCode)
+		}
+	}()
+}
+
+// wait blocks until in-flight deliveries finish.
+func (n *alertNotifier) wait() {
+	if n != nil {
+		n.wg.Wait()
+	}
+}
+
+// sendAlert fires a webhook alert without blocking; it is a no-op when no
+// webhook is configured.
+func (m *Manager) sendAlert(event, traderID, format string, args ...any) {
+	if m == nil || m.alerts == nil {
+		return
+	}
+	m.alerts.send(Alert{
+		Event:     event,
+		TraderID:  traderID,
+		Message:   fmt.Sprintf(format, args...),
+		Timestamp: m.now(),
+	})
+}
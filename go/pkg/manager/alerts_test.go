@@ -0,0 +1,95 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	executorpkg "nof0-api/pkg/executor"
+)
+
+func newAlertServer(t *testing.T) (*httptest.Server, chan Alert) {
+	t.Helper()
+	alerts := make(chan Alert, 8)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var a Alert
+		if assert.NoError(t, json.NewDecoder(r.Body).Decode(&a)) {
+			alerts <- a
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, alerts
+}
+
+func nextAlert(t *testing.T, alerts chan Alert) Alert {
+	t.Helper()
+	select {
+	case a := <-alerts:
+		return a
+	case <-time.After(2 * time.Second):
+		t.Fatal("no alert delivered")
+		return Alert{}
+	}
+}
+
+func TestAlertWebhookOnDecisionAndExecutionErrors(t *testing.T) {
+	server, alerts := newAlertServer(t)
+	m := NewManager(&Config{Monitoring: MonitoringConfig{AlertWebhook: server.URL}}, nil, nil, nil, nil)
+	exec := &fakeExecutor{err: errors.New("model timed out")}
+	vt := newCycleTestTrader(m, "alerting", newFakeMarket(testSnapshot("BTC", 60000, 0.01)), exec)
+	off := false
+	vt.ExecGuards = ExecGuards{EnableSparseDataGuard: &off}
+
+	m.runTraderCycle(context.Background(), vt)
+	a := nextAlert(t, alerts)
+	assert.Equal(t, AlertDecisionFailed, a.Event)
+	assert.Equal(t, "alerting", a.TraderID)
+	assert.Contains(t, a.Message, "model timed out")
+	assert.False(t, a.Timestamp.IsZero())
+
+	exec.err = nil
+	exec.decisions = []executorpkg.Decision{{Symbol: "BTC", Action: "open_long", PositionSizeUSD: -1}}
+	vt.LastDecisionAt = time.Time{}
+	m.runTraderCycle(context.Background(), vt)
+	a = nextAlert(t, alerts)
+	assert.Equal(t, AlertExecutionError, a.Event)
+	assert.Contains(t, a.Message, "open_long BTC failed")
+	m.alerts.wait()
+}
+
+func TestAlertWebhookOnSharpePause(t *testing.T) {
+	server, alerts := newAlertServer(t)
+	m := NewManager(&Config{Monitoring: MonitoringConfig{AlertWebhook: server.URL}}, nil, nil, nil, nil)
+	exec := &fakeExecutor{}
+	vt := newCycleTestTrader(m, "paused", newFakeMarket(testSnapshot("BTC", 60000, 0.01)), exec)
+	vt.ExecGuards = ExecGuards{SharpePauseThreshold: 0.5, PauseDurationOnBreach: time.Hour}
+	vt.Performance = &PerformanceMetrics{SharpeRatio: -1}
+
+	m.runTraderCycle(context.Background(), vt)
+	a := nextAlert(t, alerts)
+	assert.Equal(t, AlertTraderPaused, a.Event)
+	assert.Equal(t, "paused", a.TraderID)
+	assert.Contains(t, a.Message, "paused until")
+	assert.Zero(t, exec.callCount(), "a paused trader should not be consulted")
+}
+
+func TestAlertWebhookDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { <-release }))
+	defer server.Close()
+	defer close(release)
+	n := newAlertNotifier(server.URL)
+
+	start := time.Now()
+	n.send(Alert{Event: AlertExecutionError, TraderID: "t1"})
+	require.Less(t, time.Since(start), 100*time.Millisecond, "send should return before the webhook answers")
+}
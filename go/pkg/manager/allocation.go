@@ -0,0 +1,111 @@
+package manager
+
+import (
+	"math"
+	"sort"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// allocationInput is the per-trader data an allocation strategy needs.
+type allocationInput struct {
+	ID            string
+	AllocationPct float64
+	Sharpe        float64
+}
+
+// deployableEquity is total equity minus the configured reserve.
+func deployableEquity(cfg ManagerConfig) float64 {
+	if cfg.TotalEquityUSD <= 0 {
+		return 0
+	}
+	return cfg.TotalEquityUSD * (1 - cfg.ReserveEquityPct/100)
+}
+
+// resolveAllocations returns each trader's equity in USD under cfg's
+// strategy. Shares are rounded to cents; under the weighted strategies the
+// last trader by ID absorbs the rounding so the shares sum to the deployable
+// equity. by_allocation_pct takes allocation_pct of total equity as-is, which
+// validation already bounds by the reserve.
+func resolveAllocations(cfg ManagerConfig, inputs []allocationInput) map[string]float64 {
+	out := make(map[string]float64, len(inputs))
+	if len(inputs) == 0 {
+		return out
+	}
+	sorted := append([]allocationInput(nil), inputs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	if cfg.AllocationStrategy == "" || cfg.AllocationStrategy == AllocationStrategyByAllocationPct {
+		for _, in := range sorted {
+			out[in.ID] = roundCents(cfg.TotalEquityUSD * in.AllocationPct / 100)
+		}
+		return out
+	}
+
+	weights := make([]float64, len(sorted))
+	total := 0.0
+	if cfg.AllocationStrategy == AllocationStrategyBySharpe || cfg.AllocationStrategy == AllocationStrategyPerformanceBased {
+		for i, in := range sorted {
+			if in.Sharpe > 0 && !math.IsInf(in.Sharpe, 0) {
+				weights[i] = in.Sharpe
+				total += in.Sharpe
+			}
+		}
+	}
+	if total <= 0 {
+		// Equal split, also the fallback when no trader has a positive Sharpe.
+		for i := range weights {
+			weights[i] = 1
+		}
+		total = float64(len(weights))
+	}
+
+	deployable := deployableEquity(cfg)
+	remaining := roundCents(deployable)
+	for i, in := range sorted {
+		if i == len(sorted)-1 {
+			out[in.ID] = math.Max(0, roundCents(remaining))
+			break
+		}
+		share := roundCents(deployable * weights[i] / total)
+		out[in.ID] = share
+		remaining -= share
+	}
+	return out
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// allocateEquityLocked recomputes ResourceAlloc.AllocatedEquityUSD for every
+// registered trader. Callers must hold m.mu.
+func (m *Manager) allocateEquityLocked() {
+	if m.config == nil || len(m.traders) == 0 {
+		return
+	}
+	shares := resolveAllocations(m.config.Manager, m.allocationInputsLocked())
+	for id, equity := range shares {
+		t := m.traders[id]
+		t.mu.Lock()
+		t.ResourceAlloc.AllocatedEquityUSD = equity
+		t.mu.Unlock()
+		logx.Infof("manager: trader %s allocated equity=%.2f usd strategy=%s", id, equity, m.config.Manager.AllocationStrategy)
+	}
+}
+
+// allocationInputsLocked snapshots the allocation inputs of every registered
+// trader. Callers must hold m.mu.
+func (m *Manager) allocationInputsLocked() []allocationInput {
+	inputs := make([]allocationInput, 0, len(m.traders))
+	for _, t := range m.traders {
+		t.mu.RLock()
+		in := allocationInput{ID: t.ID, AllocationPct: t.ResourceAlloc.AllocationPct}
+		if t.Performance != nil {
+			in.Sharpe = t.Performance.SharpeRatio
+		}
+		t.mu.RUnlock()
+		inputs = append(inputs, in)
+	}
+	return inputs
+}
@@ -0,0 +1,77 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveAllocations(t *testing.T) {
+	inputs := []allocationInput{
+		{ID: "c", AllocationPct: 20, Sharpe: 1},
+		{ID: "a", AllocationPct: 40, Sharpe: 2},
+		{ID: "b", AllocationPct: 30, Sharpe: -1},
+	}
+	cases := []struct {
+		name string
+		cfg  ManagerConfig
+		want map[string]float64
+	}{
+		{
+			name: "equal carves out the reserve",
+			cfg:  ManagerConfig{TotalEquityUSD: 10000, ReserveEquityPct: 10, AllocationStrategy: AllocationStrategyEqual},
+			want: map[string]float64{"a": 3000, "b": 3000, "c": 3000},
+		},
+		{
+			name: "equal rounding lands on the last trader",
+			cfg:  ManagerConfig{TotalEquityUSD: 100, AllocationStrategy: AllocationStrategyEqual},
+			want: map[string]float64{"a": 33.33, "b": 33.33, "c": 33.34},
+		},
+		{
+			name: "by_allocation_pct uses each trader's share of total equity",
+			cfg:  ManagerConfig{TotalEquityUSD: 10000, ReserveEquityPct: 10, AllocationStrategy: AllocationStrategyByAllocationPct},
+			want: map[string]float64{"a": 4000, "b": 3000, "c": 2000},
+		},
+		{
+			name: "unset strategy behaves like by_allocation_pct",
+			cfg:  ManagerConfig{TotalEquityUSD: 10000},
+			want: map[string]float64{"a": 4000, "b": 3000, "c": 2000},
+		},
+		{
+			name: "by_sharpe clamps negative Sharpe to zero",
+			cfg:  ManagerConfig{TotalEquityUSD: 10000, ReserveEquityPct: 10, AllocationStrategy: AllocationStrategyBySharpe},
+			want: map[string]float64{"a": 6000, "b": 0, "c": 3000},
+		},
+		{
+			name: "performance_based is an alias of by_sharpe",
+			cfg:  ManagerConfig{TotalEquityUSD: 10000, ReserveEquityPct: 10, AllocationStrategy: AllocationStrategyPerformanceBased},
+			want: map[string]float64{"a": 6000, "b": 0, "c": 3000},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := resolveAllocations(tc.cfg, inputs)
+			require.Len(t, got, len(tc.want))
+			for id, want := range tc.want {
+				assert.InDelta(t, want, got[id], 1e-9, id)
+			}
+		})
+	}
+}
+
+func TestResolveAllocationsBySharpeFallsBackToEqual(t *testing.T) {
+	cfg := ManagerConfig{TotalEquityUSD: 1000, ReserveEquityPct: 25, AllocationStrategy: AllocationStrategyBySharpe}
+	got := resolveAllocations(cfg, []allocationInput{{ID: "a", Sharpe: -0.5}, {ID: "b"}})
+	assert.InDelta(t, 375, got["a"], 1e-9, "no positive Sharpe should split evenly")
+	assert.InDelta(t, 375, got["b"], 1e-9)
+}
+
+func TestRegisterTraderResolvesAllocatedEquity(t *testing.T) {
+	m, _, cfg := newUpdateTestManager(t)
+	assert.InDelta(t, 5000, m.traders["live"].ResourceAlloc.AllocatedEquityUSD, 1e-9, "50% of 10000 under the default strategy")
+
+	cfg.AllocationPct = 25
+	require.NoError(t, m.UpdateTrader(cfg))
+	assert.InDelta(t, 2500, m.traders["live"].ResourceAlloc.AllocatedEquityUSD, 1e-9, "updates should re-resolve allocations")
+}
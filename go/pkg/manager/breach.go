@@ -0,0 +1,131 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"nof0-api/pkg/exchange"
+)
+
+// observeDrawdown raises t's peak equity to equity when higher and returns
+// the current drawdown from that peak in percent. The worst drawdown seen is
+// published on Performance.MaxDrawdownPct.
+func (t *VirtualTrader) observeDrawdown(equity float64) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if equity > t.peakEquity {
+		t.peakEquity = equity
+	}
+	if t.peakEquity <= 0 {
+		return 0
+	}
+	drawdown := (t.peakEquity - equity) / t.peakEquity * 100
+	if t.Performance != nil && drawdown > t.Performance.MaxDrawdownPct {
+		t.Performance.MaxDrawdownPct = drawdown
+	}
+	return drawdown
+}
+
+// gateDrawdown reports whether t's equity has fallen MaxDrawdownPct below its
+// peak. On a breach the trader is paused for PauseDurationOnBreach (when set)
+// and, with ExitOnBreach, its positions are closed.
+func (m *Manager) gateDrawdown(ctx context.Context, t *VirtualTrader, equity float64) bool {
+	limit := t.ExecGuards.MaxDrawdownPct
+	if limit <= 0 {
+		return false
+	}
+	drawdown := t.observeDrawdown(equity)
+	if drawdown < limit {
+		return false
+	}
+	m.recordGuardRejection(t.ID, GuardMaxDrawdown, 1)
+	if pause := t.ExecGuards.PauseDurationOnBreach; pause > 0 {
+		t.mu.Lock()
+		paused := false
+		if t.PauseUntil.Before(time.Now()) {
+			t.PauseUntil = time.Now().Add(pause)
+			paused = true
+		}
+		t.mu.Unlock()
+		if paused {
+			m.sendAlert(AlertTraderPaused, t.ID, "drawdown %.2f%% reached limit %.2f%%; paused until %s", drawdown, limit, t.PauseUntil.Format(time.RFC3339))
+		}
+	}
+	logx.WithContext(ctx).Errorf("manager: trader %s skip cycle reason=max_drawdown drawdown=%.2f%% limit=%.2f%% exit_on_breach=%t", t.ID, drawdown, limit, t.ExecGuards.ExitOnBreach)
+	if t.ExecGuards.ExitOnBreach {
+		for _, err := range m.exitOnBreach(ctx, t) {
+			logx.WithContext(ctx).Errorf("%v", err)
+			m.sendAlert(AlertExecutionError, t.ID, "exit on breach: %v", err)
+		}
+	}
+	return true
+}
+
+// exitOnBreach cancels resting orders and closes every open position of t with
+// a reduce-only market IOC, largest unrealized loss first, returning one error
+// per failed step.
+func (m *Manager) exitOnBreach(ctx context.Context, t *VirtualTrader) []error {
+	positions, err := t.ExchangeProvider.GetPositions(ctx)
+	if err != nil {
+		return []error{fmt.Errorf("manager: exit on breach trader %s: get positions: %w", t.ID, err)}
+	}
+	open := make([]exchange.Position, 0, len(positions))
+	for _, p := range positions {
+		if parseFloat(p.Szi) != 0 {
+			open = append(open, p)
+		}
+	}
+	sort.SliceStable(open, func(i, j int) bool {
+		pi, pj := parseFloat(open[i].UnrealizedPnl), parseFloat(open[j].UnrealizedPnl)
+		if pi != pj {
+			return pi < pj
+		}
+		return open[i].Coin < open[j].Coin
+	})
+	if m.DryRun() {
+		for _, p := range open {
+			logx.Infof("manager: trader %s dry-run exit on breach symbol=%s size=%s unrealized_pnl=%s reduce_only=true", t.ID, p.Coin, p.Szi, p.UnrealizedPnl)
+		}
+		return nil
+	}
+
+	execProvider, ok := t.ExchangeProvider.(interface {
+		IOCMarket(context.Context, string, bool, float64, float64, bool) (*exchange.OrderResponse, error)
+	})
+	if !ok {
+		return []error{fmt.Errorf("manager: exit on breach trader %s requires market IOC support", t.ID)}
+	}
+	canceller, canCancel := t.ExchangeProvider.(interface {
+		CancelAllBySymbol(context.Context, string) error
+	})
+	slippage := t.MarketIOCSlippageBps / 10000.0
+	if slippage <= 0 {
+		slippage = defaultMarketIOCSlippageBps / 10000.0
+	}
+	var errs []error
+	for _, pos := range open {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("manager: exit on breach trader %s symbol=%s: %w", t.ID, pos.Coin, err))
+			continue
+		}
+		if canCancel {
+			if err := canceller.CancelAllBySymbol(ctx, pos.Coin); err != nil {
+				errs = append(errs, fmt.Errorf("manager: exit on breach trader %s: cancel orders %s: %w", t.ID, pos.Coin, err))
+			}
+		}
+		szi := parseFloat(pos.Szi)
+		m.accrueFunding(ctx, t, []exchange.Position{pos})
+		resp, err := execProvider.IOCMarket(ctx, pos.Coin, szi < 0, math.Abs(szi), slippage, true)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("manager: exit on breach trader %s: close %s: %w", t.ID, pos.Coin, err))
+			continue
+		}
+		m.recordFlattenClose(t, pos, resp, "exit_on_breach")
+	}
+	return errs
+}
@@ -0,0 +1,111 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/exchange/sim"
+)
+
+// breachExchange records the market IOCs sent through the sim provider.
+type breachExchange struct {
+	*sim.Provider
+	closes     []string
+	reduceOnly []bool
+}
+
+func (b *breachExchange) IOCMarket(ctx context.Context, coin string, isBuy bool, qty, slippage float64, reduceOnly bool) (*exchange.OrderResponse, error) {
+	b.closes = append(b.closes, coin)
+	b.reduceOnly = append(b.reduceOnly, reduceOnly)
+	return b.Provider.IOCMarket(ctx, coin, isBuy, qty, slippage, reduceOnly)
+}
+
+func newBreachTrader(t *testing.T, m *Manager, exitOnBreach bool) (*VirtualTrader, *breachExchange, *fakeExecutor) {
+	t.Helper()
+	ctx := context.Background()
+	ex := &breachExchange{Provider: sim.NewWithEquity(1000)}
+	opens := []struct {
+		coin  string
+		price float64
+		qty   float64
+		long  bool
+	}{
+		{"BTC", 60000, 0.01, true},
+		{"ETH", 3000, 0.2, false},
+		{"SOL", 150, 1, true},
+	}
+	for _, o := range opens {
+		require.NoError(t, ex.SetMarkPrice(ctx, o.coin, o.price))
+		_, err := ex.Provider.IOCMarket(ctx, o.coin, o.long, o.qty, 0, false)
+		require.NoError(t, err)
+	}
+	exec := &fakeExecutor{}
+	vt := newCycleTestTrader(m, "breach", newFakeMarket(
+		testSnapshot("BTC", 60000, 0),
+		testSnapshot("ETH", 3000, 0),
+		testSnapshot("SOL", 150, 0),
+	), exec)
+	vt.ExchangeProvider = ex
+	vt.ExecGuards.MaxDrawdownPct = 5
+	vt.ExecGuards.ExitOnBreach = exitOnBreach
+	return vt, ex, exec
+}
+
+func TestDrawdownBreachExitsPositionsReduceOnly(t *testing.T) {
+	ctx := context.Background()
+	persist := &fakePersistence{}
+	m := NewManager(nil, nil, nil, nil, persist)
+	vt, ex, exec := newBreachTrader(t, m, true)
+
+	m.runTraderCycle(ctx, vt)
+	require.Equal(t, 1, exec.callCount(), "no breach at the equity peak")
+	assert.Empty(t, ex.closes)
+
+	// BTC loses 60, the ETH short loses 30, SOL gains 10: roughly 8% off peak.
+	require.NoError(t, ex.SetMarkPrice(ctx, "BTC", 54000))
+	require.NoError(t, ex.SetMarkPrice(ctx, "ETH", 3150))
+	require.NoError(t, ex.SetMarkPrice(ctx, "SOL", 160))
+
+	m.runTraderCycle(ctx, vt)
+	assert.Equal(t, 1, exec.callCount(), "a breached trader must not consult the executor")
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardMaxDrawdown])
+	assert.Equal(t, []string{"BTC", "ETH", "SOL"}, ex.closes, "largest loss should be closed first")
+	assert.Equal(t, []bool{true, true, true}, ex.reduceOnly, "breach exits must be reduce-only")
+
+	positions, err := ex.GetPositions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, positions, "every position should be closed")
+	assert.InDelta(t, 8, vt.Performance.MaxDrawdownPct, 0.5, "open fees widen the drawdown slightly")
+
+	var closes int
+	for _, ev := range persist.events {
+		if ev.Event == PositionEventClose {
+			closes++
+			assert.Equal(t, "exit_on_breach", ev.Decision.Reasoning)
+		}
+	}
+	assert.Equal(t, 3, closes)
+}
+
+func TestDrawdownBreachWithoutExitKeepsPositions(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt, ex, exec := newBreachTrader(t, m, false)
+	vt.ExecGuards.PauseDurationOnBreach = time.Hour
+
+	m.runTraderCycle(ctx, vt)
+	require.NoError(t, ex.SetMarkPrice(ctx, "BTC", 54000))
+	m.runTraderCycle(ctx, vt)
+
+	assert.Equal(t, 1, exec.callCount())
+	assert.Empty(t, ex.closes)
+	assert.True(t, vt.PauseUntil.After(time.Now()), "the breach should pause the trader")
+	positions, err := ex.GetPositions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, positions, 3)
+}
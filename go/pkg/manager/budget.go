@@ -0,0 +1,79 @@
+package manager
+
+import (
+	"fmt"
+	"time"
+
+	"nof0-api/pkg/llm"
+)
+
+// defaultTokenBudgetWindow applies when a budget sets limits but no window.
+const defaultTokenBudgetWindow = 24 * time.Hour
+
+type tokenUsageEntry struct {
+	at      time.Time
+	tokens  int64
+	costUSD float64
+}
+
+// recordTokenUsage adds one LLM call to the trader's cumulative token count
+// and rolling usage window. Window usage is only kept while a budget is
+// configured.
+func (t *VirtualTrader) recordTokenUsage(at time.Time, u llm.Usage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.TokensUsed += int64(u.TotalTokens)
+	if !t.TokenBudget.Enabled() || (u.TotalTokens == 0 && u.CostUSD == 0) {
+		return
+	}
+	t.pruneTokenUsageLocked(at)
+	t.tokenUsage = append(t.tokenUsage, tokenUsageEntry{at: at, tokens: int64(u.TotalTokens), costUSD: u.CostUSD})
+}
+
+// TokenUsage returns the tokens and estimated cost spent within the budget
+// window ending at now.
+func (t *VirtualTrader) TokenUsage(now time.Time) (tokens int64, costUSD float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pruneTokenUsageLocked(now)
+	for _, e := range t.tokenUsage {
+		tokens += e.tokens
+		costUSD += e.costUSD
+	}
+	return tokens, costUSD
+}
+
+// tokenBudgetExceeded reports whether the trader has used up its token or
+// cost allowance for the current window, with a log-friendly detail.
+func (t *VirtualTrader) tokenBudgetExceeded(now time.Time) (bool, string) {
+	t.mu.RLock()
+	budget := t.TokenBudget
+	t.mu.RUnlock()
+	if !budget.Enabled() {
+		return false, ""
+	}
+	tokens, cost := t.TokenUsage(now)
+	if budget.MaxTokens > 0 && tokens >= budget.MaxTokens {
+		return true, fmt.Sprintf("tokens=%d max_tokens=%d window=%s", tokens, budget.MaxTokens, budget.window())
+	}
+	if budget.MaxCostUSD > 0 && cost >= budget.MaxCostUSD {
+		return true, fmt.Sprintf("cost_usd=%.4f max_cost_usd=%.4f window=%s", cost, budget.MaxCostUSD, budget.window())
+	}
+	return false, ""
+}
+
+func (t *VirtualTrader) pruneTokenUsageLocked(now time.Time) {
+	cutoff := now.Add(-t.TokenBudget.window())
+	keep := 0
+	for keep < len(t.tokenUsage) && !t.tokenUsage[keep].at.After(cutoff) {
+		keep++
+	}
+	t.tokenUsage = t.tokenUsage[keep:]
+}
+
+func (b TokenBudget) window() time.Duration {
+	if b.Window > 0 {
+		return b.Window
+	}
+	return defaultTokenBudgetWindow
+}
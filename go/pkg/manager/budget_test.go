@@ -0,0 +1,63 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"nof0-api/pkg/llm"
+)
+
+func TestTokenBudgetSkipsCyclesUntilWindowRolls(t *testing.T) {
+	start := time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC)
+	m := NewManager(nil, nil, nil, nil, nil)
+	m.SetClock(fixedClock{start})
+	exec := &fakeExecutor{usage: llm.Usage{TotalTokens: 100, CostUSD: 0.02}}
+	vt := newCycleTestTrader(m, "budget", newFakeMarket(testSnapshot("BTC", 60000, 0.01)), exec)
+	vt.TokenBudget = TokenBudget{MaxTokens: 250, Window: time.Hour}
+	off := false
+	vt.ExecGuards.EnableSparseDataGuard = &off
+
+	for i := 0; i < 4; i++ {
+		m.runTraderCycle(context.Background(), vt)
+	}
+	assert.Equal(t, 3, exec.callCount(), "the call that crosses the cap runs; later cycles are skipped")
+	tokens, cost := vt.TokenUsage(start)
+	assert.Equal(t, int64(300), tokens)
+	assert.InDelta(t, 0.06, cost, 1e-9)
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardTokenBudget])
+
+	// Once the window rolls past the recorded calls the trader resumes.
+	m.SetClock(fixedClock{start.Add(time.Hour + time.Second)})
+	m.runTraderCycle(context.Background(), vt)
+	assert.Equal(t, 4, exec.callCount())
+}
+
+func TestTokenBudgetCostCap(t *testing.T) {
+	now := time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC)
+	vt := &VirtualTrader{ID: "cost", TokenBudget: TokenBudget{MaxCostUSD: 0.05}}
+
+	vt.recordTokenUsage(now, llm.Usage{TotalTokens: 10, CostUSD: 0.03})
+	over, _ := vt.tokenBudgetExceeded(now)
+	assert.False(t, over)
+
+	vt.recordTokenUsage(now.Add(time.Minute), llm.Usage{TotalTokens: 10, CostUSD: 0.03})
+	over, detail := vt.tokenBudgetExceeded(now.Add(time.Minute))
+	assert.True(t, over)
+	assert.Contains(t, detail, "max_cost_usd")
+
+	// The default window is a day; the first call expires first.
+	over, _ = vt.tokenBudgetExceeded(now.Add(24*time.Hour + 30*time.Second))
+	assert.False(t, over, "usage older than the window no longer counts")
+}
+
+func TestTokenBudgetDisabledKeepsNoUsage(t *testing.T) {
+	vt := &VirtualTrader{ID: "free"}
+	vt.recordTokenUsage(time.Now(), llm.Usage{TotalTokens: 1_000_000})
+	tokens, _ := vt.TokenUsage(time.Now())
+	assert.Zero(t, tokens)
+	over, _ := vt.tokenBudgetExceeded(time.Now())
+	assert.False(t, over)
+}
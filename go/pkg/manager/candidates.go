@@ -0,0 +1,64 @@
+package manager
+
+import (
+	"math"
+
+	"nof0-api/pkg/market"
+)
+
+// candidateScore ranks a snapshot under the given candidate_ranking mode.
+// ok is false when the snapshot lacks the indicators the mode relies on.
+func candidateScore(mode string, s *market.Snapshot) (score float64, ok bool) {
+	switch mode {
+	case CandidateRankingMomentum:
+		// Both directions are tradable, so rank by MACD magnitude scaled to
+		// price (in percent) to keep assets comparable.
+		if s.Indicators.MACD == 0 || s.Price.Last <= 0 {
+			return 0, false
+		}
+		return math.Abs(s.Indicators.MACD) / s.Price.Last * 100, true
+	case CandidateRankingRSIExtreme:
+		rsi, ok := s.Indicators.RSI["RSI14"]
+		if !ok {
+			rsi, ok = s.Indicators.RSI["RSI7"]
+		}
+		if !ok {
+			return 0, false
+		}
+		return math.Abs(rsi - 50), true
+	default:
+		return math.Abs(s.Change.OneHour), true
+	}
+}
+
+// candidateSource labels candidates with the ranking that selected them.
+func candidateSource(mode string) string {
+	switch mode {
+	case CandidateRankingMomentum:
+		return "rank_macd_abs"
+	case CandidateRankingRSIExtreme:
+		return "rank_rsi_extreme"
+	default:
+		return "rank_1h_abs"
+	}
+}
+
+// fundingDownRankFactor scales the score of candidates whose funding rate
+// exceeds the funding guard. Only one side pays, so they stay eligible.
+const fundingDownRankFactor = 0.5
+
+// fundingGuardRate returns the max absolute funding rate for new opens, or 0
+// when the funding guard is disabled.
+func fundingGuardRate(g ExecGuards) float64 {
+	if !toggleOn(g.EnableFundingGuard) {
+		return 0
+	}
+	return g.MaxAbsFundingRate
+}
+
+// fundingCrowded reports whether the snapshot's funding rate is beyond the
+// funding guard in either direction.
+func fundingCrowded(g ExecGuards, s *market.Snapshot) bool {
+	limit := fundingGuardRate(g)
+	return limit > 0 && s.Funding != nil && math.Abs(s.Funding.Rate) > limit
+}
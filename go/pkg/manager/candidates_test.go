@@ -0,0 +1,129 @@
+package manager
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	executorpkg "nof0-api/pkg/executor"
+	"nof0-api/pkg/journal"
+	"nof0-api/pkg/market"
+)
+
+func TestSelectCandidates_ExcludesImplausibleSnapshots(t *testing.T) {
+	mk := newFakeMarket(
+		testSnapshot("BTC", 60000, 0.02),
+		testSnapshot("ETH", 3000, -0.01),
+		testSnapshot("SCAM", 0.01, 50), // 5000% hourly change
+	)
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := &VirtualTrader{ID: "t1", MarketProvider: mk}
+
+	got := m.selectCandidates(context.Background(), vt, 5, nil)
+	symbols := make([]string, 0, len(got))
+	for _, c := range got {
+		symbols = append(symbols, c.Symbol)
+	}
+	assert.Equal(t, []string{"BTC", "ETH"}, symbols, "implausible snapshot should be excluded from ranking")
+}
+
+func TestWriteJournalRecord_PersistsCandidateScores(t *testing.T) {
+	dir := t.TempDir()
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := &VirtualTrader{
+		ID:             "t1",
+		MarketProvider: newFakeMarket(testSnapshot("BTC", 60000, 0.02), testSnapshot("SOL", 150, -0.05)),
+		Journal:        journal.NewWriter(dir),
+		JournalEnabled: true,
+	}
+	ectx := executorpkg.Context{CandidateCoins: m.selectCandidates(context.Background(), vt, 5, nil)}
+
+	err := m.writeJournalRecord(vt, &ectx, nil, "", nil, nil, nil, true)
+	assert.NoError(t, err, "journal write should succeed")
+
+	files, err := filepath.Glob(filepath.Join(dir, "cycle_*.json"))
+	assert.NoError(t, err, "glob should succeed")
+	if !assert.Len(t, files, 1, "one cycle file should be written") {
+		return
+	}
+	rec, err := journal.ReadCycle(files[0])
+	assert.NoError(t, err, "ReadCycle should succeed")
+	assert.Equal(t, []journal.CandidateScore{
+		{Symbol: "SOL", Score: 0.05, Sources: []string{"rank_1h_abs"}},
+		{Symbol: "BTC", Score: 0.02, Sources: []string{"rank_1h_abs"}},
+	}, rec.CandidateSet, "candidates should read back in rank order with scores")
+}
+
+func indicatorSnapshot(symbol string, price, change1h, macd float64, rsi map[string]float64) *market.Snapshot {
+	s := testSnapshot(symbol, price, change1h)
+	s.Indicators = market.IndicatorInfo{MACD: macd, RSI: rsi}
+	return s
+}
+
+func candidateSymbols(cs []executorpkg.CandidateCoin) []string {
+	out := make([]string, 0, len(cs))
+	for _, c := range cs {
+		out = append(out, c.Symbol)
+	}
+	return out
+}
+
+func TestSelectCandidates_RankingModes(t *testing.T) {
+	mk := newFakeMarket(
+		indicatorSnapshot("BTC", 60000, 0.03, 600, map[string]float64{"RSI14": 55}), // MACD 1%
+		indicatorSnapshot("ETH", 3000, 0.01, -90, map[string]float64{"RSI14": 22}),  // MACD -3%
+		indicatorSnapshot("SOL", 150, -0.02, 0, map[string]float64{"RSI7": 71}),     // no MACD
+		indicatorSnapshot("DOGE", 0.1, 0.005, 0.0002, nil),                          // no RSI
+	)
+	m := NewManager(nil, nil, nil, nil, nil)
+
+	cases := []struct {
+		mode    string
+		symbols []string
+		source  string
+	}{
+		{mode: "", symbols: []string{"BTC", "SOL", "ETH", "DOGE"}, source: "rank_1h_abs"},
+		{mode: CandidateRankingChange1h, symbols: []string{"BTC", "SOL", "ETH", "DOGE"}, source: "rank_1h_abs"},
+		{mode: CandidateRankingMomentum, symbols: []string{"ETH", "BTC", "DOGE"}, source: "rank_macd_abs"},
+		{mode: CandidateRankingRSIExtreme, symbols: []string{"ETH", "SOL", "BTC"}, source: "rank_rsi_extreme"},
+	}
+	for _, tc := range cases {
+		vt := &VirtualTrader{ID: "t1", MarketProvider: mk, ExecGuards: ExecGuards{CandidateRanking: tc.mode}}
+		got := m.selectCandidates(context.Background(), vt, 5, nil)
+		assert.Equal(t, tc.symbols, candidateSymbols(got), "mode %q ranking", tc.mode)
+		if assert.NotEmpty(t, got, "mode %q should yield candidates", tc.mode) {
+			assert.Equal(t, []string{tc.source}, got[0].Sources, "mode %q source label", tc.mode)
+		}
+	}
+}
+
+func fundingSnapshot(symbol string, price, change1h, funding float64) *market.Snapshot {
+	s := testSnapshot(symbol, price, change1h)
+	s.Funding = &market.FundingInfo{Rate: funding}
+	return s
+}
+
+func TestFundingGuard_DownRanksCandidatesAndReachesExecutor(t *testing.T) {
+	mk := newFakeMarket(
+		fundingSnapshot("BTC", 60000, 0.03, 0.001),   // crowded long: 0.03 -> 0.015
+		fundingSnapshot("ETH", 3000, 0.02, -0.0001),  // within the threshold
+		fundingSnapshot("SOL", 150, 0.025, -0.0008),  // crowded short: 0.025 -> 0.0125
+		fundingSnapshot("DOGE", 0.1, 0.001, 0.00001), // calm
+	)
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := newCycleTestTrader(m, "funding", mk, &fakeExecutor{})
+	vt.ExecGuards.MaxAbsFundingRate = 0.0005
+
+	ectx, _ := m.buildExecutorContext(vt, snapshotCache{})
+	assert.Equal(t, []string{"ETH", "BTC", "SOL", "DOGE"}, candidateSymbols(ectx.CandidateCoins), "crowded funding should be down-ranked, not dropped")
+	assert.InDelta(t, 0.015, ectx.CandidateCoins[1].Score, 1e-12)
+	assert.InDelta(t, 0.0005, ectx.MaxAbsFundingRate, 1e-12, "threshold should reach executor validation")
+
+	disabled := false
+	vt.ExecGuards.EnableFundingGuard = &disabled
+	ectx, _ = m.buildExecutorContext(vt, snapshotCache{})
+	assert.Equal(t, []string{"BTC", "SOL", "ETH", "DOGE"}, candidateSymbols(ectx.CandidateCoins))
+	assert.Zero(t, ectx.MaxAbsFundingRate, "disabled guard should not reach the executor")
+}
@@ -0,0 +1,35 @@
+package manager
+
+import "time"
+
+// Clock abstracts the wall clock so scheduling decisions can be tested.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// SetClock overrides the clock used for scheduling; nil restores the system clock.
+func (m *Manager) SetClock(c Clock) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c == nil {
+		c = systemClock{}
+	}
+	m.clock = c
+}
+
+func (m *Manager) now() time.Time {
+	m.mu.RLock()
+	c := m.clock
+	m.mu.RUnlock()
+	if c == nil {
+		return time.Now()
+	}
+	return c.Now()
+}
@@ -0,0 +1,46 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+func TestExecuteDecisionRejectsCloseOnOppositeSide(t *testing.T) {
+	ctx := context.Background()
+	ex := &methodRecordingExchange{Provider: sim.New()}
+	require.NoError(t, ex.SetMarkPrice(ctx, "SOL", 150))
+	_, err := ex.Provider.IOCMarket(ctx, "SOL", false, 2, 0.001, false)
+	require.NoError(t, err, "seed short should open")
+
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := &VirtualTrader{
+		ID:               "sides",
+		ExchangeProvider: ex,
+		MarketProvider:   newFakeMarket(testSnapshot("SOL", 150, 0)),
+		Cooldown:         make(map[string]time.Time),
+	}
+
+	err = m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "close_long"})
+	require.Error(t, err, "close_long on a short should be rejected")
+	assert.Contains(t, err.Error(), "position is short")
+	assert.Empty(t, ex.methods, "rejected close should not reach the exchange")
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardCloseSide])
+	_, cooling := vt.Cooldown["SOL"]
+	assert.False(t, cooling, "rejected close should not start a cooldown")
+	positions, err := ex.GetPositions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, positions, 1, "short should still be open")
+
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "close_short"}))
+	assert.Equal(t, []string{"ClosePosition"}, ex.methods, "matching close should be submitted")
+	positions, err = ex.GetPositions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, positions)
+}
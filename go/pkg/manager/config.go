@@ -13,6 +13,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"nof0-api/pkg/confkit"
+	executorpkg "nof0-api/pkg/executor"
 )
 
 // OrderStyle defines how the manager submits opening orders.
@@ -21,8 +22,81 @@ type OrderStyle string
 const (
 	OrderStyleLimitIOC  OrderStyle = "limit_ioc"
 	OrderStyleMarketIOC OrderStyle = "market_ioc"
+	// OrderStylePostOnly rests a GTC add-liquidity-only limit that the
+	// exchange rejects instead of crossing the spread.
+	OrderStylePostOnly OrderStyle = "post_only"
 
 	defaultMarketIOCSlippageBps = 50.0 // 0.50% slippage
+	defaultMinOrderNotionalUSD  = 10.0 // Hyperliquid's minimum order value
+)
+
+// MarginMode selects the margin type leverage is set with before an open.
+type MarginMode string
+
+const (
+	MarginModeCross    MarginMode = "cross"
+	MarginModeIsolated MarginMode = "isolated"
+	// MarginModeAuto uses cross margin unless the asset only supports isolated.
+	MarginModeAuto MarginMode = "auto"
+)
+
+// SizingMode selects how an open's notional is chosen.
+type SizingMode string
+
+const (
+	// SizingModeModel uses the model's position_size_usd as given (default).
+	SizingModeModel SizingMode = "model"
+	// SizingModeConfidence scales the notional linearly with the decision's
+	// confidence, from sizing_floor_usd at 0 to max_position_size_usd at 100.
+	SizingModeConfidence SizingMode = "confidence_scaled"
+	// SizingModeKelly sizes by the Kelly fraction of the trader's win rate and
+	// average win/loss payoff, scaled by kelly_multiplier.
+	SizingModeKelly SizingMode = "kelly"
+
+	defaultKellyMultiplier = 0.5 // half-Kelly
+)
+
+// Allocation strategies split deployable equity (total minus reserve) across
+// traders.
+const (
+	// AllocationStrategyEqual gives every trader the same share.
+	AllocationStrategyEqual = "equal"
+	// AllocationStrategyByAllocationPct uses each trader's allocation_pct of
+	// total equity.
+	AllocationStrategyByAllocationPct = "by_allocation_pct"
+	// AllocationStrategyBySharpe weights shares by non-negative Sharpe ratio.
+	AllocationStrategyBySharpe = "by_sharpe"
+	// AllocationStrategyPerformanceBased is an alias of by_sharpe.
+	AllocationStrategyPerformanceBased = "performance_based"
+)
+
+// Candidate ranking modes for exec_guards.candidate_ranking.
+const (
+	// CandidateRankingChange1h ranks by absolute 1h price change (default).
+	CandidateRankingChange1h = "change_1h"
+	// CandidateRankingMomentum ranks by MACD magnitude relative to price.
+	CandidateRankingMomentum = "momentum"
+	// CandidateRankingRSIExtreme ranks by RSI distance from 50.
+	CandidateRankingRSIExtreme = "rsi_extreme"
+)
+
+// Journal sinks for traders[].journal_sinks.
+const (
+	// JournalSinkFile writes one JSON file per cycle under journal_dir (default).
+	JournalSinkFile = "file"
+	// JournalSinkStdout writes one JSON line per cycle to stdout.
+	JournalSinkStdout = "stdout"
+)
+
+// Startup reconciliation modes for manager.startup_reconcile, applied to
+// exchange positions that have no persisted position row.
+const (
+	// StartupReconcileOff leaves untracked positions alone (default).
+	StartupReconcileOff = "off"
+	// StartupReconcileAdopt records an open position row for each one.
+	StartupReconcileAdopt = "adopt"
+	// StartupReconcileFlatten cancels resting orders and closes each one.
+	StartupReconcileFlatten = "flatten"
 )
 
 // Config defines the overall manager configuration schema.
@@ -30,6 +104,8 @@ type Config struct {
 	Manager    ManagerConfig    `yaml:"manager"`
 	Traders    []TraderConfig   `yaml:"traders"`
 	Monitoring MonitoringConfig `yaml:"monitoring"`
+	// GuardProfiles holds named exec guard presets referenced by traders via exec_guards_profile.
+	GuardProfiles map[string]ExecGuards `yaml:"guard_profiles"`
 
 	baseDir string
 }
@@ -41,7 +117,35 @@ type ManagerConfig struct {
 	RebalanceInterval   time.Duration `yaml:"-"`
 	StateStorageBackend string        `yaml:"state_storage_backend"`
 	StateStoragePath    string        `yaml:"state_storage_path"`
-
+	// MaxConcurrentDecisions caps how many traders run a decision cycle per
+	// tick; slots are shared fairly. Zero runs every eligible trader in turn.
+	MaxConcurrentDecisions int `yaml:"max_concurrent_decisions"`
+	// DecisionWorkers sizes a pool that runs trader cycles in the background
+	// so a slow LLM call no longer holds up the tick for everyone else. Each
+	// trader still runs at most one cycle at a time. Zero keeps cycles inside
+	// the tick.
+	DecisionWorkers int `yaml:"decision_workers"`
+	// MaxCycles stops the trading loop after this many decision cycles across
+	// all traders; MaxCyclesPerTrader caps each trader and stops the loop once
+	// every trader is done. Zero means unlimited.
+	MaxCycles          int `yaml:"max_cycles"`
+	MaxCyclesPerTrader int `yaml:"max_cycles_per_trader"`
+	// StartupReconcile decides what happens at startup to exchange positions
+	// the manager has no record of, e.g. after a crash: off, adopt or flatten.
+	StartupReconcile string `yaml:"startup_reconcile"`
+	// SyncConcurrency caps how many traders sync their exchange account at
+	// once (default 4); SyncTimeout bounds each trader's sync (default 10s).
+	SyncConcurrency int           `yaml:"sync_concurrency"`
+	SyncTimeout     time.Duration `yaml:"-"`
+	SyncTimeoutRaw  string        `yaml:"sync_timeout"`
+	// TakerFeeBps and MakerFeeBps estimate commissions on fills the venue
+	// reports no fee for: taker for IOC orders, maker for post_only ones.
+	// Unset keeps the persistence defaults; 0 means fee-free.
+	TakerFeeBps *float64 `yaml:"taker_fee_bps"`
+	MakerFeeBps *float64 `yaml:"maker_fee_bps"`
+
+	// RebalanceIntervalRaw sets how often allocations are recomputed from
+	// live account equity; "0" disables rebalancing.
 	RebalanceIntervalRaw string `yaml:"rebalance_interval"`
 }
 
@@ -50,20 +154,79 @@ type TraderConfig struct {
 	Name                 string         `yaml:"name"`
 	ExchangeProvider     string         `yaml:"exchange_provider"`
 	MarketProvider       string         `yaml:"market_provider"`
+	VaultAddress         string         `yaml:"vault_address"` // optional vault/subaccount the trader's orders are signed for
 	OrderStyle           OrderStyle     `yaml:"order_style"`
 	MarketIOCSlippageBps float64        `yaml:"market_ioc_slippage_bps"`
+	PostOnlyOffsetBps    float64        `yaml:"post_only_offset_bps"`
+	MinOrderNotionalUSD  float64        `yaml:"min_order_notional_usd"`
+	MarginMode           MarginMode     `yaml:"margin_mode"`
+	SizingMode           SizingMode     `yaml:"sizing_mode"`
+	SizingFloorUSD       float64        `yaml:"sizing_floor_usd"`
+	KellyMultiplier      float64        `yaml:"kelly_multiplier"`
 	PromptTemplate       string         `yaml:"prompt_template"`
 	ExecutorTemplate     string         `yaml:"executor_prompt_template"`
 	Model                string         `yaml:"model"`
 	DecisionInterval     time.Duration  `yaml:"-"`
 	RiskParams           RiskParameters `yaml:"risk_params"`
 	ExecGuards           ExecGuards     `yaml:"exec_guards"`
+	ExecGuardsProfile    string         `yaml:"exec_guards_profile"`
 	AllocationPct        float64        `yaml:"allocation_pct"`
 	AutoStart            bool           `yaml:"auto_start"`
 	JournalEnabled       bool           `yaml:"journal_enabled"`
 	JournalDir           string         `yaml:"journal_dir"`
+	// JournalSinks lists where journaled cycles are written (file, stdout);
+	// defaults to file. Cycles also reach the database through persistence.
+	JournalSinks []string `yaml:"journal_sinks"`
+	// JournalCompress gzips journal files; JournalMaxFileMB and
+	// JournalRotateEvery append cycles to segment files rolled by size or age.
+	JournalCompress       bool          `yaml:"journal_compress"`
+	JournalMaxFileMB      float64       `yaml:"journal_max_file_mb"`
+	JournalRotateEvery    time.Duration `yaml:"-"`
+	JournalRotateEveryRaw string        `yaml:"journal_rotate_every"`
+	// PersistPrompts stores each journaled cycle's full rendered prompt and
+	// response in the conversations tables, linked by cycle id, in place of
+	// the executor's unlinked conversation record. Off by default: prompts
+	// are large and the cycle otherwise keeps only a digest.
+	PersistPrompts bool `yaml:"persist_prompts"`
+	// PromptSections disables optional executor prompt blocks for token economy.
+	PromptSections executorpkg.PromptSections `yaml:"prompt_sections"`
+	// SymbolUniverse restricts decisions to candidates and open positions ("lenient" drops, "strict" errors).
+	SymbolUniverse executorpkg.SymbolUniverseMode `yaml:"symbol_universe"`
+	// DecisionSchema requests a "minimal" structured schema for models that
+	// fail the full one; empty keeps the full schema.
+	DecisionSchema executorpkg.DecisionSchemaMode `yaml:"decision_schema"`
+	// TradingWindows restricts decisions to UTC sessions; empty means always active.
+	TradingWindows []TradingWindow `yaml:"trading_windows"`
+	// TokenBudget caps LLM usage over a rolling window; over budget, cycles are skipped.
+	TokenBudget TokenBudget `yaml:"token_budget"`
+	// ToolCalling lets the executor's model call get_snapshot/get_positions
+	// against this trader's providers before deciding, for at most
+	// MaxToolIterations turns (default 4).
+	ToolCalling       bool `yaml:"tool_calling"`
+	MaxToolIterations int  `yaml:"max_tool_iterations"`
+	// StreamDecisions streams the executor's completion to capture the
+	// model's chain of thought incrementally, bounded by DecisionTimeout.
+	StreamDecisions bool `yaml:"stream_decisions"`
 
 	DecisionIntervalRaw string `yaml:"decision_interval"`
+	// DecisionTimeout bounds one LLM decision call and must be shorter than
+	// DecisionInterval. Unset, it defaults to 60s capped below the interval.
+	DecisionTimeout    time.Duration `yaml:"-"`
+	DecisionTimeoutRaw string        `yaml:"decision_timeout"`
+}
+
+// TokenBudget limits a trader's LLM tokens and estimated cost (from the llm
+// config's per-model prices) within a rolling window. Zero limits are off.
+type TokenBudget struct {
+	MaxTokens  int64         `yaml:"max_tokens"`
+	MaxCostUSD float64       `yaml:"max_cost_usd"`
+	Window     time.Duration `yaml:"-"`
+	WindowRaw  string        `yaml:"window"`
+}
+
+// Enabled reports whether any limit is set.
+func (b TokenBudget) Enabled() bool {
+	return b.MaxTokens > 0 || b.MaxCostUSD > 0
 }
 
 // ExecGuards defines optional hard guards applied at execution/validation time.
@@ -71,6 +234,15 @@ type ExecGuards struct {
 	MaxNewPositionsPerCycle int     `yaml:"max_new_positions_per_cycle"`
 	LiquidityThresholdUSD   float64 `yaml:"liquidity_threshold_usd"`
 	MaxMarginUsagePct       float64 `yaml:"max_margin_usage_pct"`
+	// MaxAbsFundingRate rejects opens that would pay funding above this
+	// fractional rate and down-ranks candidates beyond it (0 disables).
+	MaxAbsFundingRate float64 `yaml:"max_abs_funding_rate"`
+
+	// ReservedPositionSlots holds back the last N max_positions slots from
+	// routine opens; only decisions with confidence >= ReservedSlotMinConfidence
+	// may fill them.
+	ReservedPositionSlots     int `yaml:"reserved_position_slots"`
+	ReservedSlotMinConfidence int `yaml:"reserved_slot_min_confidence"`
 
 	BTCETHMinEquityMultiple float64 `yaml:"btceth_position_value_min_equity_multiple"`
 	BTCETHMaxEquityMultiple float64 `yaml:"btceth_position_value_max_equity_multiple"`
@@ -79,19 +251,72 @@ type ExecGuards struct {
 
 	CooldownAfterClose    time.Duration `yaml:"-"`
 	CooldownAfterCloseRaw string        `yaml:"cooldown_after_close"`
+	// CooldownAfterLoss replaces CooldownAfterClose after a losing close (PnL < 0).
+	CooldownAfterLoss    time.Duration `yaml:"-"`
+	CooldownAfterLossRaw string        `yaml:"cooldown_after_loss"`
 	// Feature toggles (default true if omitted)
 	EnableLiquidityGuard   *bool `yaml:"enable_liquidity_guard"`
 	EnableMarginUsageGuard *bool `yaml:"enable_margin_usage_guard"`
 	EnableValueBandGuard   *bool `yaml:"enable_value_band_guard"`
 	EnableCooldownGuard    *bool `yaml:"enable_cooldown_guard"`
+	EnableFundingGuard     *bool `yaml:"enable_funding_guard"`
+	// DuplicatePositionGuard stops an open from adding to a position already
+	// held in the same direction: "reject" fails the open, "skip" treats it
+	// as a no-op. Empty or "off" allows scaling in.
+	DuplicatePositionGuard string `yaml:"duplicate_position_guard"`
 
 	// Candidate selection
 	CandidateLimit int `yaml:"candidate_limit"`
+	// CandidateRanking selects the ranking heuristic: change_1h (default),
+	// momentum or rsi_extreme.
+	CandidateRanking string `yaml:"candidate_ranking"`
+	// MinSnapshotCoveragePct skips a cycle when fewer than this share of the
+	// symbols fetched for the cycle have market snapshots (default 50 when the
+	// guard is enabled).
+	MinSnapshotCoveragePct float64 `yaml:"min_snapshot_coverage_pct"`
+	EnableSparseDataGuard  *bool   `yaml:"enable_sparse_data_guard"`
+	// DecisionLatencyBudgetPct skips execution when building the context and
+	// obtaining a decision took longer than this share of the decision
+	// interval (0 disables).
+	DecisionLatencyBudgetPct float64 `yaml:"decision_latency_budget_pct"`
+
+	// OrderRetryAttempts bounds PlaceOrder attempts on transient failures
+	// (default 3; 1 disables retries). Retries reuse the order's cloid.
+	OrderRetryAttempts int `yaml:"order_retry_attempts"`
+	// OrderRetryBackoff is the delay before the first retry, doubled after
+	// each further attempt (default 250ms).
+	OrderRetryBackoff    time.Duration `yaml:"-"`
+	OrderRetryBackoffRaw string        `yaml:"order_retry_backoff"`
 
 	// Performance gating
 	SharpePauseThreshold     float64       `yaml:"sharpe_pause_threshold"`
 	PauseDurationOnBreach    time.Duration `yaml:"-"`
 	PauseDurationOnBreachRaw string        `yaml:"pause_duration_on_breach"`
+	// SharpeLookback computes the gated Sharpe ratio from the equity curve
+	// sampled over this trailing window instead of the trade statistics.
+	SharpeLookback    time.Duration `yaml:"-"`
+	SharpeLookbackRaw string        `yaml:"sharpe_lookback"`
+	// SharpeWindow computes the gated Sharpe ratio from the last N per-cycle
+	// equity returns; combined with SharpeLookback both limits apply.
+	SharpeWindow int `yaml:"sharpe_window"`
+	// SharpeMinTrades disables the Sharpe gate until the trader has closed at
+	// least this many trades.
+	SharpeMinTrades int `yaml:"sharpe_min_trades"`
+	// MaxDrawdownPct skips cycles, pausing for PauseDurationOnBreach when
+	// set, while equity is this many percent below its peak (0 disables).
+	MaxDrawdownPct float64 `yaml:"max_drawdown_pct"`
+	// MaxDailyLossUSD and MaxDailyLossPct pause the trader until the next
+	// UTC midnight once equity falls this far below the day's first equity
+	// reading (0 disables each).
+	MaxDailyLossUSD float64 `yaml:"max_daily_loss_usd"`
+	MaxDailyLossPct float64 `yaml:"max_daily_loss_pct"`
+	// MaxPositionAge closes positions held longer than this before the
+	// model's decisions run, whatever the model decides (0 disables).
+	MaxPositionAge    time.Duration `yaml:"-"`
+	MaxPositionAgeRaw string        `yaml:"max_position_age"`
+	// ExitOnBreach closes every open position reduce-only, largest loss
+	// first, when a hard guard such as MaxDrawdownPct trips.
+	ExitOnBreach bool `yaml:"exit_on_breach"`
 }
 
 type RiskParameters struct {
@@ -149,6 +374,9 @@ func LoadConfigFromReader(r io.Reader, baseDir string) (*Config, error) {
 	}
 	cfg.baseDir = baseDir
 
+	if err := cfg.resolveGuardProfiles(data); err != nil {
+		return nil, err
+	}
 	cfg.applyDefaults()
 	if err := cfg.parseDurations(); err != nil {
 		return nil, err
@@ -161,10 +389,51 @@ func LoadConfigFromReader(r io.Reader, baseDir string) (*Config, error) {
 	return &cfg, nil
 }
 
+// resolveGuardProfiles merges referenced guard profiles into each trader's
+// exec_guards. The trader's inline exec_guards are decoded from data on top of
+// the profile, so every key set inline wins, zero values included.
+func (c *Config) resolveGuardProfiles(data []byte) error {
+	var raw struct {
+		GuardProfiles map[string]yaml.Node `yaml:"guard_profiles"`
+		Traders       []struct {
+			ExecGuards yaml.Node `yaml:"exec_guards"`
+		} `yaml:"traders"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("unmarshal manager config: %w", err)
+	}
+	for i := range c.Traders {
+		name := strings.TrimSpace(c.Traders[i].ExecGuardsProfile)
+		c.Traders[i].ExecGuardsProfile = name
+		if name == "" {
+			continue
+		}
+		profile, ok := raw.GuardProfiles[name]
+		if !ok {
+			return fmt.Errorf("manager config: traders[%d].exec_guards_profile %q is not defined in guard_profiles", i, name)
+		}
+		// Decode the profile afresh per trader so no pointer field is shared.
+		var guards ExecGuards
+		if err := profile.Decode(&guards); err != nil {
+			return fmt.Errorf("manager config: guard_profiles.%s: %w", name, err)
+		}
+		if inline := raw.Traders[i].ExecGuards; !inline.IsZero() {
+			if err := inline.Decode(&guards); err != nil {
+				return fmt.Errorf("manager config: traders[%d].exec_guards: %w", i, err)
+			}
+		}
+		c.Traders[i].ExecGuards = guards
+	}
+	return nil
+}
+
 func (c *Config) applyDefaults() {
 	if strings.TrimSpace(c.Manager.RebalanceIntervalRaw) == "" {
 		c.Manager.RebalanceIntervalRaw = "1h"
 	}
+	if strings.TrimSpace(c.Manager.AllocationStrategy) == "" {
+		c.Manager.AllocationStrategy = AllocationStrategyByAllocationPct
+	}
 	for i := range c.Traders {
 		if strings.TrimSpace(c.Traders[i].DecisionIntervalRaw) == "" {
 			c.Traders[i].DecisionIntervalRaw = "3m"
@@ -179,6 +448,22 @@ func (c *Config) applyDefaults() {
 		if c.Traders[i].MarketIOCSlippageBps <= 0 {
 			c.Traders[i].MarketIOCSlippageBps = defaultMarketIOCSlippageBps
 		}
+		if c.Traders[i].MinOrderNotionalUSD == 0 {
+			c.Traders[i].MinOrderNotionalUSD = defaultMinOrderNotionalUSD
+		}
+		if strings.TrimSpace(string(c.Traders[i].MarginMode)) == "" {
+			c.Traders[i].MarginMode = MarginModeCross
+		}
+		if strings.TrimSpace(string(c.Traders[i].SizingMode)) == "" {
+			c.Traders[i].SizingMode = SizingModeModel
+		}
+		if c.Traders[i].KellyMultiplier == 0 {
+			c.Traders[i].KellyMultiplier = defaultKellyMultiplier
+		}
+		c.Traders[i].ExecGuards.CandidateRanking = strings.ToLower(strings.TrimSpace(c.Traders[i].ExecGuards.CandidateRanking))
+		for j, sink := range c.Traders[i].JournalSinks {
+			c.Traders[i].JournalSinks[j] = strings.ToLower(strings.TrimSpace(sink))
+		}
 	}
 	if strings.TrimSpace(c.Monitoring.UpdateIntervalRaw) == "" {
 		c.Monitoring.UpdateIntervalRaw = "30s"
@@ -187,16 +472,28 @@ func (c *Config) applyDefaults() {
 
 func (c *Config) parseDurations() error {
 	var err error
-	c.Manager.RebalanceInterval, err = parsePositiveDuration("manager.rebalance_interval", c.Manager.RebalanceIntervalRaw)
+	c.Manager.RebalanceInterval, err = parseNonNegativeDuration("manager.rebalance_interval", c.Manager.RebalanceIntervalRaw)
 	if err != nil {
 		return err
 	}
+	if raw := strings.TrimSpace(c.Manager.SyncTimeoutRaw); raw != "" {
+		c.Manager.SyncTimeout, err = parsePositiveDuration("manager.sync_timeout", raw)
+		if err != nil {
+			return err
+		}
+	}
 	for i := range c.Traders {
 		d, err := parsePositiveDuration(fmt.Sprintf("traders[%d].decision_interval", i), c.Traders[i].DecisionIntervalRaw)
 		if err != nil {
 			return err
 		}
 		c.Traders[i].DecisionInterval = d
+		if raw := strings.TrimSpace(c.Traders[i].DecisionTimeoutRaw); raw != "" {
+			c.Traders[i].DecisionTimeout, err = parsePositiveDuration(fmt.Sprintf("traders[%d].decision_timeout", i), raw)
+			if err != nil {
+				return err
+			}
+		}
 		// ExecGuards cooldown is optional; parse if provided and non-empty.
 		raw := strings.TrimSpace(c.Traders[i].ExecGuards.CooldownAfterCloseRaw)
 		if raw != "" {
@@ -206,6 +503,22 @@ func (c *Config) parseDurations() error {
 			}
 			c.Traders[i].ExecGuards.CooldownAfterClose = cd
 		}
+		if raw := strings.TrimSpace(c.Traders[i].ExecGuards.CooldownAfterLossRaw); raw != "" {
+			cd, err := time.ParseDuration(raw)
+			if err != nil || cd < 0 {
+				return fmt.Errorf("manager config: traders[%d].exec_guards.cooldown_after_loss invalid: %v", i, err)
+			}
+			c.Traders[i].ExecGuards.CooldownAfterLoss = cd
+		}
+		if budget := &c.Traders[i].TokenBudget; budget.Enabled() {
+			budget.Window = defaultTokenBudgetWindow
+			if strings.TrimSpace(budget.WindowRaw) != "" {
+				budget.Window, err = parsePositiveDuration(fmt.Sprintf("traders[%d].token_budget.window", i), budget.WindowRaw)
+				if err != nil {
+					return err
+				}
+			}
+		}
 		rawPause := strings.TrimSpace(c.Traders[i].ExecGuards.PauseDurationOnBreachRaw)
 		if rawPause != "" {
 			pd, err := time.ParseDuration(rawPause)
@@ -214,6 +527,30 @@ func (c *Config) parseDurations() error {
 			}
 			c.Traders[i].ExecGuards.PauseDurationOnBreach = pd
 		}
+		if raw := strings.TrimSpace(c.Traders[i].ExecGuards.OrderRetryBackoffRaw); raw != "" {
+			c.Traders[i].ExecGuards.OrderRetryBackoff, err = parsePositiveDuration(fmt.Sprintf("traders[%d].exec_guards.order_retry_backoff", i), raw)
+			if err != nil {
+				return err
+			}
+		}
+		if raw := strings.TrimSpace(c.Traders[i].ExecGuards.SharpeLookbackRaw); raw != "" {
+			c.Traders[i].ExecGuards.SharpeLookback, err = parsePositiveDuration(fmt.Sprintf("traders[%d].exec_guards.sharpe_lookback", i), raw)
+			if err != nil {
+				return err
+			}
+		}
+		if raw := strings.TrimSpace(c.Traders[i].JournalRotateEveryRaw); raw != "" {
+			c.Traders[i].JournalRotateEvery, err = parsePositiveDuration(fmt.Sprintf("traders[%d].journal_rotate_every", i), raw)
+			if err != nil {
+				return err
+			}
+		}
+		if raw := strings.TrimSpace(c.Traders[i].ExecGuards.MaxPositionAgeRaw); raw != "" {
+			c.Traders[i].ExecGuards.MaxPositionAge, err = parsePositiveDuration(fmt.Sprintf("traders[%d].exec_guards.max_position_age", i), raw)
+			if err != nil {
+				return err
+			}
+		}
 	}
 	c.Monitoring.UpdateInterval, err = parsePositiveDuration("monitoring.update_interval", c.Monitoring.UpdateIntervalRaw)
 	if err != nil {
@@ -224,14 +561,19 @@ func (c *Config) parseDurations() error {
 
 func (c *Config) expandFields() {
 	c.Manager.StateStoragePath = c.resolvePath(c.Manager.StateStoragePath)
-	c.Manager.AllocationStrategy = strings.TrimSpace(c.Manager.AllocationStrategy)
+	c.Manager.AllocationStrategy = strings.ToLower(strings.TrimSpace(c.Manager.AllocationStrategy))
 	c.Manager.StateStorageBackend = strings.TrimSpace(c.Manager.StateStorageBackend)
+	c.Manager.StartupReconcile = strings.ToLower(strings.TrimSpace(c.Manager.StartupReconcile))
 	for i := range c.Traders {
 		c.Traders[i].ID = strings.TrimSpace(c.Traders[i].ID)
 		c.Traders[i].Name = strings.TrimSpace(c.Traders[i].Name)
 		c.Traders[i].ExchangeProvider = strings.TrimSpace(c.Traders[i].ExchangeProvider)
 		c.Traders[i].MarketProvider = strings.TrimSpace(c.Traders[i].MarketProvider)
+		c.Traders[i].VaultAddress = strings.TrimSpace(os.ExpandEnv(c.Traders[i].VaultAddress))
 		c.Traders[i].OrderStyle = OrderStyle(strings.ToLower(strings.TrimSpace(string(c.Traders[i].OrderStyle))))
+		c.Traders[i].MarginMode = MarginMode(strings.ToLower(strings.TrimSpace(string(c.Traders[i].MarginMode))))
+		c.Traders[i].SizingMode = SizingMode(strings.ToLower(strings.TrimSpace(string(c.Traders[i].SizingMode))))
+		c.Traders[i].ExecGuards.DuplicatePositionGuard = strings.ToLower(strings.TrimSpace(c.Traders[i].ExecGuards.DuplicatePositionGuard))
 		c.Traders[i].PromptTemplate = c.resolvePath(c.Traders[i].PromptTemplate)
 		c.Traders[i].ExecutorTemplate = c.resolvePath(c.Traders[i].ExecutorTemplate)
 		c.Traders[i].JournalDir = c.resolvePath(c.Traders[i].JournalDir)
@@ -256,6 +598,37 @@ func (c *Config) Validate() error {
 	if c.Manager.ReserveEquityPct < 0 || c.Manager.ReserveEquityPct > 100 {
 		return errors.New("manager config: manager.reserve_equity_pct must be between 0 and 100")
 	}
+	switch c.Manager.AllocationStrategy {
+	case "", AllocationStrategyEqual, AllocationStrategyByAllocationPct, AllocationStrategyBySharpe, AllocationStrategyPerformanceBased:
+	default:
+		return fmt.Errorf("manager config: manager.allocation_strategy %q unsupported", c.Manager.AllocationStrategy)
+	}
+	switch c.Manager.StartupReconcile {
+	case "", StartupReconcileOff, StartupReconcileAdopt, StartupReconcileFlatten:
+	default:
+		return fmt.Errorf("manager config: manager.startup_reconcile %q unsupported", c.Manager.StartupReconcile)
+	}
+	if c.Manager.MaxConcurrentDecisions < 0 {
+		return errors.New("manager config: manager.max_concurrent_decisions cannot be negative")
+	}
+	if fee := c.Manager.TakerFeeBps; fee != nil && *fee < 0 {
+		return errors.New("manager config: manager.taker_fee_bps cannot be negative")
+	}
+	if fee := c.Manager.MakerFeeBps; fee != nil && *fee < 0 {
+		return errors.New("manager config: manager.maker_fee_bps cannot be negative")
+	}
+	if c.Manager.DecisionWorkers < 0 {
+		return errors.New("manager config: manager.decision_workers cannot be negative")
+	}
+	if c.Manager.SyncConcurrency < 0 {
+		return errors.New("manager config: manager.sync_concurrency cannot be negative")
+	}
+	if c.Manager.MaxCycles < 0 {
+		return errors.New("manager config: manager.max_cycles cannot be negative")
+	}
+	if c.Manager.MaxCyclesPerTrader < 0 {
+		return errors.New("manager config: manager.max_cycles_per_trader cannot be negative")
+	}
 	if strings.TrimSpace(c.Manager.StateStorageBackend) == "" {
 		return errors.New("manager config: manager.state_storage_backend is required")
 	}
@@ -285,6 +658,9 @@ func (c *Config) Validate() error {
 		if strings.TrimSpace(trader.MarketProvider) == "" {
 			return fmt.Errorf("manager config: traders[%d].market_provider is required", i)
 		}
+		if trader.VaultAddress != "" && !isHexAddress(trader.VaultAddress) {
+			return fmt.Errorf("manager config: traders[%d].vault_address %q is not a 0x-prefixed 20-byte hex address", i, trader.VaultAddress)
+		}
 		if trader.PromptTemplate == "" {
 			return fmt.Errorf("manager config: traders[%d].prompt_template is required", i)
 		}
@@ -308,16 +684,89 @@ func (c *Config) Validate() error {
 		if err := trader.validateOrderStyle(i); err != nil {
 			return err
 		}
+		if err := trader.validateMarginMode(i); err != nil {
+			return err
+		}
+		if err := trader.validateSizingMode(i); err != nil {
+			return err
+		}
 		// ExecGuards validation (optional; non-negative checks)
 		if trader.ExecGuards.MaxNewPositionsPerCycle < 0 {
 			return fmt.Errorf("manager config: traders[%d].exec_guards.max_new_positions_per_cycle cannot be negative", i)
 		}
+		if trader.ExecGuards.ReservedPositionSlots < 0 {
+			return fmt.Errorf("manager config: traders[%d].exec_guards.reserved_position_slots cannot be negative", i)
+		}
+		if trader.ExecGuards.ReservedPositionSlots > 0 && (trader.ExecGuards.ReservedSlotMinConfidence <= 0 || trader.ExecGuards.ReservedSlotMinConfidence > 100) {
+			return fmt.Errorf("manager config: traders[%d].exec_guards.reserved_slot_min_confidence must be 1..100 when reserved_position_slots is set", i)
+		}
 		if trader.ExecGuards.LiquidityThresholdUSD < 0 {
 			return fmt.Errorf("manager config: traders[%d].exec_guards.liquidity_threshold_usd cannot be negative", i)
 		}
+		if trader.ExecGuards.MaxAbsFundingRate < 0 {
+			return fmt.Errorf("manager config: traders[%d].exec_guards.max_abs_funding_rate cannot be negative", i)
+		}
 		if trader.ExecGuards.MaxMarginUsagePct < 0 || trader.ExecGuards.MaxMarginUsagePct > 100 {
 			return fmt.Errorf("manager config: traders[%d].exec_guards.max_margin_usage_pct must be 0..100", i)
 		}
+		if trader.ExecGuards.MinSnapshotCoveragePct < 0 || trader.ExecGuards.MinSnapshotCoveragePct > 100 {
+			return fmt.Errorf("manager config: traders[%d].exec_guards.min_snapshot_coverage_pct must be 0..100", i)
+		}
+		if trader.JournalMaxFileMB < 0 {
+			return fmt.Errorf("manager config: traders[%d].journal_max_file_mb cannot be negative", i)
+		}
+		for _, sink := range trader.JournalSinks {
+			switch sink {
+			case JournalSinkFile, JournalSinkStdout:
+			default:
+				return fmt.Errorf("manager config: traders[%d].journal_sinks %q unsupported", i, sink)
+			}
+		}
+		switch trader.ExecGuards.CandidateRanking {
+		case "", CandidateRankingChange1h, CandidateRankingMomentum, CandidateRankingRSIExtreme:
+		default:
+			return fmt.Errorf("manager config: traders[%d].exec_guards.candidate_ranking %q unsupported", i, trader.ExecGuards.CandidateRanking)
+		}
+		for j, w := range trader.TradingWindows {
+			if err := w.validate(); err != nil {
+				return fmt.Errorf("manager config: traders[%d].trading_windows[%d]: %v", i, j, err)
+			}
+		}
+		if trader.DecisionTimeout > 0 && trader.DecisionTimeout >= trader.DecisionInterval {
+			return fmt.Errorf("manager config: traders[%d].decision_timeout %s must be less than decision_interval %s", i, trader.DecisionTimeout, trader.DecisionInterval)
+		}
+		if trader.MaxToolIterations < 0 {
+			return fmt.Errorf("manager config: traders[%d].max_tool_iterations cannot be negative", i)
+		}
+		if trader.ExecGuards.OrderRetryAttempts < 0 {
+			return fmt.Errorf("manager config: traders[%d].exec_guards.order_retry_attempts cannot be negative", i)
+		}
+		if trader.ExecGuards.SharpeWindow < 0 {
+			return fmt.Errorf("manager config: traders[%d].exec_guards.sharpe_window cannot be negative", i)
+		}
+		if trader.ExecGuards.SharpeMinTrades < 0 {
+			return fmt.Errorf("manager config: traders[%d].exec_guards.sharpe_min_trades cannot be negative", i)
+		}
+		if trader.ExecGuards.MaxDrawdownPct < 0 || trader.ExecGuards.MaxDrawdownPct > 100 {
+			return fmt.Errorf("manager config: traders[%d].exec_guards.max_drawdown_pct must be 0..100", i)
+		}
+		switch trader.ExecGuards.DuplicatePositionGuard {
+		case "", DuplicatePositionOff, DuplicatePositionReject, DuplicatePositionSkip:
+		default:
+			return fmt.Errorf("manager config: traders[%d].exec_guards.duplicate_position_guard %q unsupported", i, trader.ExecGuards.DuplicatePositionGuard)
+		}
+		if trader.ExecGuards.MaxDailyLossUSD < 0 {
+			return fmt.Errorf("manager config: traders[%d].exec_guards.max_daily_loss_usd cannot be negative", i)
+		}
+		if trader.ExecGuards.MaxDailyLossPct < 0 || trader.ExecGuards.MaxDailyLossPct > 100 {
+			return fmt.Errorf("manager config: traders[%d].exec_guards.max_daily_loss_pct must be 0..100", i)
+		}
+		if trader.ExecGuards.DecisionLatencyBudgetPct < 0 || trader.ExecGuards.DecisionLatencyBudgetPct > 100 {
+			return fmt.Errorf("manager config: traders[%d].exec_guards.decision_latency_budget_pct must be 0..100", i)
+		}
+		if trader.TokenBudget.MaxTokens < 0 || trader.TokenBudget.MaxCostUSD < 0 {
+			return fmt.Errorf("manager config: traders[%d].token_budget limits cannot be negative", i)
+		}
 	}
 	if totalAllocation > 100+1e-6 {
 		return fmt.Errorf("manager config: trader allocation sum %.2f exceeds 100", totalAllocation)
@@ -334,13 +783,46 @@ func (c *Config) Validate() error {
 
 func (t TraderConfig) validateOrderStyle(index int) error {
 	switch t.OrderStyle {
-	case OrderStyleLimitIOC, OrderStyleMarketIOC:
+	case OrderStyleLimitIOC, OrderStyleMarketIOC, OrderStylePostOnly:
 	default:
 		return fmt.Errorf("manager config: traders[%d].order_style %q unsupported", index, t.OrderStyle)
 	}
 	if t.OrderStyle == OrderStyleMarketIOC && t.MarketIOCSlippageBps <= 0 {
 		return fmt.Errorf("manager config: traders[%d].market_ioc_slippage_bps must be positive", index)
 	}
+	if t.PostOnlyOffsetBps < 0 {
+		return fmt.Errorf("manager config: traders[%d].post_only_offset_bps cannot be negative", index)
+	}
+	if t.MinOrderNotionalUSD < 0 {
+		return fmt.Errorf("manager config: traders[%d].min_order_notional_usd cannot be negative", index)
+	}
+	return nil
+}
+
+func (t TraderConfig) validateMarginMode(index int) error {
+	switch t.MarginMode {
+	case "", MarginModeCross, MarginModeIsolated, MarginModeAuto:
+		return nil
+	default:
+		return fmt.Errorf("manager config: traders[%d].margin_mode %q unsupported", index, t.MarginMode)
+	}
+}
+
+func (t TraderConfig) validateSizingMode(index int) error {
+	switch t.SizingMode {
+	case "", SizingModeModel, SizingModeConfidence, SizingModeKelly:
+	default:
+		return fmt.Errorf("manager config: traders[%d].sizing_mode %q unsupported", index, t.SizingMode)
+	}
+	if t.KellyMultiplier < 0 || t.KellyMultiplier > 1 {
+		return fmt.Errorf("manager config: traders[%d].kelly_multiplier must be within 0..1", index)
+	}
+	if t.SizingFloorUSD < 0 {
+		return fmt.Errorf("manager config: traders[%d].sizing_floor_usd cannot be negative", index)
+	}
+	if t.SizingFloorUSD > t.RiskParams.MaxPositionSizeUSD {
+		return fmt.Errorf("manager config: traders[%d].sizing_floor_usd %.2f exceeds risk_params.max_position_size_usd %.2f", index, t.SizingFloorUSD, t.RiskParams.MaxPositionSizeUSD)
+	}
 	return nil
 }
 
@@ -385,6 +867,23 @@ func parsePositiveDuration(field, value string) (time.Duration, error) {
 	return d, nil
 }
 
+// parseNonNegativeDuration is parsePositiveDuration that also accepts zero,
+// for intervals where zero disables the feature.
+func parseNonNegativeDuration(field, value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, fmt.Errorf("manager config: %s is required", field)
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("manager config: invalid %s %q: %w", field, value, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("manager config: %s cannot be negative, got %s", field, d)
+	}
+	return d, nil
+}
+
 // TraderIDs returns a stable ordered list of trader IDs.
 func (c *Config) TraderIDs() []string {
 	ids := make([]string, 0, len(c.Traders))
@@ -394,3 +893,16 @@ func (c *Config) TraderIDs() []string {
 	sort.Strings(ids)
 	return ids
 }
+
+// isHexAddress reports whether s is a 0x-prefixed 20-byte hex address.
+func isHexAddress(s string) bool {
+	if len(s) != 42 || !strings.HasPrefix(strings.ToLower(s), "0x") {
+		return false
+	}
+	for _, r := range s[2:] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
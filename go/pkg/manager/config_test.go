@@ -1,9 +1,11 @@
 package manager
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -97,6 +99,7 @@ monitoring:
 	assert.Equal(t, "hl_market", cfg.Traders[0].MarketProvider, "MarketProvider should be trimmed")
 	assert.Equal(t, OrderStyleLimitIOC, cfg.Traders[0].OrderStyle, "OrderStyle should default to limit_ioc")
 	assert.Equal(t, defaultMarketIOCSlippageBps, cfg.Traders[0].MarketIOCSlippageBps, "MarketIOCSlippageBps should default")
+	assert.Equal(t, defaultMinOrderNotionalUSD, cfg.Traders[0].MinOrderNotionalUSD, "MinOrderNotionalUSD should default")
 
 	wantStatePath := filepath.Join(dir, "state/manager.json")
 	assert.Equal(t, wantStatePath, cfg.Manager.StateStoragePath, "StateStoragePath should match expected path")
@@ -273,3 +276,580 @@ monitoring:
 	assert.Error(t, err, "LoadConfig should error for missing market provider")
 	assert.Contains(t, err.Error(), "market_provider", "error should mention market_provider")
 }
+
+func TestGuardProfiles(t *testing.T) {
+	configYAML := `
+manager:
+  total_equity_usd: 1000
+  reserve_equity_pct: 0
+  allocation_strategy: equal
+  rebalance_interval: 1h
+  state_storage_backend: file
+  state_storage_path: state.json
+
+guard_profiles:
+  conservative:
+    max_new_positions_per_cycle: 1
+    liquidity_threshold_usd: 15000000
+    max_margin_usage_pct: 40
+    cooldown_after_close: 30m
+    cooldown_after_loss: 2h
+    enable_value_band_guard: false
+
+traders:
+  - id: t1
+    name: Trader1
+    exchange_provider: ex
+    market_provider: market_a
+    prompt_template: prompt.tmpl
+    executor_prompt_template: prompts/executor/default_prompt.tmpl
+    allocation_pct: 40
+    exec_guards_profile: conservative
+    risk_params: &risk
+      max_positions: 1
+      max_position_size_usd: 100
+      max_margin_usage_pct: 50
+      major_coin_leverage: 10
+      altcoin_leverage: 5
+      min_risk_reward_ratio: 2
+      min_confidence: 70
+
+  - id: t2
+    name: Trader2
+    exchange_provider: ex
+    market_provider: market_a
+    prompt_template: prompt.tmpl
+    executor_prompt_template: prompts/executor/default_prompt.tmpl
+    allocation_pct: 40
+    exec_guards_profile: " conservative "
+    exec_guards:
+      max_margin_usage_pct: 25
+      cooldown_after_close: 5m
+      enable_value_band_guard: true
+    risk_params: *risk
+
+monitoring:
+  update_interval: 10s
+  metrics_exporter: prometheus
+`
+	cfg, err := LoadConfig(writeTestManagerConfig(t, configYAML))
+	assert.NoError(t, err, "LoadConfig should not error")
+	if !assert.NotNil(t, cfg, "config should not be nil") {
+		return
+	}
+
+	inherited := cfg.Traders[0].ExecGuards
+	assert.Equal(t, 1, inherited.MaxNewPositionsPerCycle, "profile max_new_positions_per_cycle should be inherited")
+	assert.Equal(t, 15000000.0, inherited.LiquidityThresholdUSD, "profile liquidity threshold should be inherited")
+	assert.Equal(t, 40.0, inherited.MaxMarginUsagePct, "profile margin usage should be inherited")
+	assert.Equal(t, 30*time.Minute, inherited.CooldownAfterClose, "profile cooldown should be inherited and parsed")
+	assert.Equal(t, 2*time.Hour, inherited.CooldownAfterLoss, "profile loss cooldown should be inherited and parsed")
+	if assert.NotNil(t, inherited.EnableValueBandGuard, "profile toggle should be inherited") {
+		assert.False(t, *inherited.EnableValueBandGuard, "profile toggle value should be preserved")
+	}
+
+	overridden := cfg.Traders[1].ExecGuards
+	assert.Equal(t, "conservative", cfg.Traders[1].ExecGuardsProfile, "profile name should be trimmed")
+	assert.Equal(t, 1, overridden.MaxNewPositionsPerCycle, "unset fields should come from the profile")
+	assert.Equal(t, 25.0, overridden.MaxMarginUsagePct, "inline margin usage should override the profile")
+	assert.Equal(t, 5*time.Minute, overridden.CooldownAfterClose, "inline cooldown should override the profile")
+	if assert.NotNil(t, overridden.EnableValueBandGuard, "inline toggle should be set") {
+		assert.True(t, *overridden.EnableValueBandGuard, "inline toggle should override the profile")
+	}
+}
+
+func TestGuardProfileInlineZeroOverrides(t *testing.T) {
+	configYAML := `
+manager:
+  total_equity_usd: 1000
+  state_storage_backend: file
+  state_storage_path: state.json
+
+guard_profiles:
+  strict:
+    max_new_positions_per_cycle: 1
+    liquidity_threshold_usd: 15000000
+    max_margin_usage_pct: 40
+
+traders:
+  - id: t1
+    name: Trader1
+    exchange_provider: ex
+    market_provider: market_a
+    prompt_template: prompt.tmpl
+    allocation_pct: 40
+    exec_guards_profile: strict
+    exec_guards:
+      max_new_positions_per_cycle: 0
+      liquidity_threshold_usd: 0
+    risk_params:
+      max_positions: 1
+      max_position_size_usd: 100
+      major_coin_leverage: 10
+      altcoin_leverage: 5
+      min_risk_reward_ratio: 2
+      min_confidence: 70
+  - id: t2
+    name: Trader2
+    exchange_provider: ex
+    market_provider: market_a
+    prompt_template: prompt.tmpl
+    allocation_pct: 40
+    exec_guards_profile: strict
+    risk_params:
+      max_positions: 1
+      max_position_size_usd: 100
+      major_coin_leverage: 10
+      altcoin_leverage: 5
+      min_risk_reward_ratio: 2
+      min_confidence: 70
+
+monitoring:
+  update_interval: 10s
+  metrics_exporter: prometheus
+`
+	cfg, err := LoadConfig(writeTestManagerConfig(t, configYAML))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	guards := cfg.Traders[0].ExecGuards
+	assert.Zero(t, guards.MaxNewPositionsPerCycle, "an inline zero should override the profile")
+	assert.Zero(t, guards.LiquidityThresholdUSD, "an inline zero should override the profile")
+	assert.Equal(t, 40.0, guards.MaxMarginUsagePct, "keys absent inline should come from the profile")
+	assert.Equal(t, 1, cfg.Traders[1].ExecGuards.MaxNewPositionsPerCycle, "other traders keep the profile value")
+}
+
+func TestGuardProfileUnknown(t *testing.T) {
+	configYAML := `
+manager:
+  total_equity_usd: 1000
+  state_storage_backend: file
+  state_storage_path: state.json
+
+traders:
+  - id: t1
+    name: Trader1
+    exchange_provider: ex
+    market_provider: market_a
+    prompt_template: prompt.tmpl
+    allocation_pct: 40
+    exec_guards_profile: missing
+    risk_params:
+      max_positions: 1
+      max_position_size_usd: 100
+      major_coin_leverage: 10
+      altcoin_leverage: 5
+      min_risk_reward_ratio: 2
+
+monitoring:
+  metrics_exporter: prometheus
+`
+	_, err := LoadConfig(writeTestManagerConfig(t, configYAML))
+	assert.Error(t, err, "LoadConfig should error for unknown guard profile")
+	assert.Contains(t, err.Error(), "exec_guards_profile", "error should mention exec_guards_profile")
+}
+
+func TestAllocationStrategyUnknown(t *testing.T) {
+	configYAML := `
+manager:
+  total_equity_usd: 1000
+  allocation_strategy: By_Volume
+  state_storage_backend: file
+  state_storage_path: state.json
+
+traders:
+  - id: t1
+    name: Trader1
+    exchange_provider: ex
+    market_provider: market_a
+    prompt_template: prompt.tmpl
+    allocation_pct: 40
+    risk_params:
+      max_positions: 1
+      max_position_size_usd: 100
+      major_coin_leverage: 10
+      altcoin_leverage: 5
+      min_risk_reward_ratio: 2
+
+monitoring:
+  metrics_exporter: prometheus
+`
+	_, err := LoadConfig(writeTestManagerConfig(t, configYAML))
+	assert.Error(t, err, "LoadConfig should error for an unknown allocation strategy")
+	assert.Contains(t, err.Error(), `allocation_strategy "by_volume"`, "error should name the normalised strategy")
+}
+
+func TestRebalanceIntervalZeroParses(t *testing.T) {
+	configYAML := `
+manager:
+  total_equity_usd: 1000
+  rebalance_interval: "0"
+  state_storage_backend: file
+  state_storage_path: state.json
+
+traders:
+  - id: t1
+    name: Trader1
+    exchange_provider: ex
+    market_provider: market_a
+    prompt_template: prompt.tmpl
+    allocation_pct: 40
+    risk_params:
+      max_positions: 1
+      max_position_size_usd: 100
+      major_coin_leverage: 10
+      altcoin_leverage: 5
+      min_risk_reward_ratio: 2
+
+monitoring:
+  metrics_exporter: prometheus
+`
+	cfg, err := LoadConfig(writeTestManagerConfig(t, configYAML))
+	assert.NoError(t, err, "LoadConfig should accept a zero rebalance interval")
+	if assert.NotNil(t, cfg) {
+		assert.Zero(t, cfg.Manager.RebalanceInterval, "zero should disable rebalancing")
+	}
+}
+
+// writeTestManagerConfig writes configYAML alongside prompt.tmpl and the default
+// executor prompt into a temp dir and returns the config path.
+func writeTestManagerConfig(t *testing.T, configYAML string) string {
+	t.Helper()
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "prompt.tmpl"), []byte("generic prompt"), 0o600)
+	assert.NoError(t, err, "write prompt should succeed")
+	execPrompt := filepath.Join(dir, "prompts/executor/default_prompt.tmpl")
+	err = os.MkdirAll(filepath.Dir(execPrompt), 0o700)
+	assert.NoError(t, err, "mkdir executor prompts should succeed")
+	err = os.WriteFile(execPrompt, []byte("executor prompt"), 0o600)
+	assert.NoError(t, err, "write executor prompt should succeed")
+	path := filepath.Join(dir, "manager.yaml")
+	err = os.WriteFile(path, []byte(configYAML), 0o600)
+	assert.NoError(t, err, "write manager config should succeed")
+	return path
+}
+
+func TestTokenBudgetConfig(t *testing.T) {
+	base := `
+manager:
+  total_equity_usd: 1000
+  state_storage_backend: file
+  state_storage_path: state.json
+
+traders:
+  - id: t1
+    name: Trader1
+    exchange_provider: ex
+    market_provider: market_a
+    prompt_template: prompt.tmpl
+    allocation_pct: 40
+    risk_params:
+      max_positions: 1
+      max_position_size_usd: 100
+      major_coin_leverage: 10
+      altcoin_leverage: 5
+      min_risk_reward_ratio: 2
+    token_budget:
+%s
+monitoring:
+  metrics_exporter: prometheus
+`
+	cfg, err := LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(base, "      max_tokens: 50000\n      max_cost_usd: 2.5\n      window: 6h\n")))
+	if assert.NoError(t, err) {
+		budget := cfg.Traders[0].TokenBudget
+		assert.Equal(t, int64(50000), budget.MaxTokens)
+		assert.Equal(t, 2.5, budget.MaxCostUSD)
+		assert.Equal(t, 6*time.Hour, budget.Window)
+	}
+
+	cfg, err = LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(base, "      max_tokens: 50000\n")))
+	if assert.NoError(t, err) {
+		assert.Equal(t, 24*time.Hour, cfg.Traders[0].TokenBudget.Window, "window defaults to a day")
+	}
+
+	_, err = LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(base, "      max_cost_usd: -1\n")))
+	assert.ErrorContains(t, err, "token_budget")
+}
+
+func TestCandidateRankingConfig(t *testing.T) {
+	configYAML := `
+manager:
+  total_equity_usd: 1000
+  state_storage_backend: file
+  state_storage_path: state.json
+
+traders:
+  - id: t1
+    name: Trader1
+    exchange_provider: ex
+    market_provider: market_a
+    prompt_template: prompt.tmpl
+    allocation_pct: 40
+    exec_guards:
+      candidate_ranking: %s
+    risk_params:
+      max_positions: 1
+      max_position_size_usd: 100
+      major_coin_leverage: 10
+      altcoin_leverage: 5
+      min_risk_reward_ratio: 2
+
+monitoring:
+  metrics_exporter: prometheus
+`
+	cfg, err := LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(configYAML, "RSI_Extreme")))
+	if assert.NoError(t, err, "LoadConfig should accept a known ranking mode") {
+		assert.Equal(t, CandidateRankingRSIExtreme, cfg.Traders[0].ExecGuards.CandidateRanking, "ranking mode should be normalised")
+	}
+
+	_, err = LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(configYAML, "volume")))
+	assert.Error(t, err, "LoadConfig should error for an unknown ranking mode")
+	assert.Contains(t, err.Error(), `candidate_ranking "volume"`, "error should name the ranking mode")
+}
+
+func TestJournalRotationConfig(t *testing.T) {
+	configYAML := `
+manager:
+  total_equity_usd: 1000
+  state_storage_backend: file
+  state_storage_path: state.json
+
+traders:
+  - id: t1
+    name: Trader1
+    exchange_provider: ex
+    market_provider: market_a
+    prompt_template: prompt.tmpl
+    allocation_pct: 40
+    journal_compress: true
+    journal_max_file_mb: %s
+    journal_rotate_every: %s
+    risk_params:
+      max_positions: 1
+      max_position_size_usd: 100
+      major_coin_leverage: 10
+      altcoin_leverage: 5
+      min_risk_reward_ratio: 2
+
+monitoring:
+  metrics_exporter: prometheus
+`
+	cfg, err := LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(configYAML, "50", "24h")))
+	if assert.NoError(t, err, "LoadConfig should accept journal rotation settings") {
+		assert.True(t, cfg.Traders[0].JournalCompress)
+		assert.Equal(t, 50.0, cfg.Traders[0].JournalMaxFileMB)
+		assert.Equal(t, 24*time.Hour, cfg.Traders[0].JournalRotateEvery)
+	}
+
+	_, err = LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(configYAML, "-1", "24h")))
+	assert.ErrorContains(t, err, "journal_max_file_mb cannot be negative")
+
+	_, err = LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(configYAML, "0", "daily")))
+	assert.ErrorContains(t, err, "journal_rotate_every")
+}
+
+func TestStartupReconcileConfig(t *testing.T) {
+	configYAML := `
+manager:
+  total_equity_usd: 1000
+  startup_reconcile: %s
+  state_storage_backend: file
+  state_storage_path: state.json
+
+traders:
+  - id: t1
+    name: Trader1
+    exchange_provider: ex
+    market_provider: market_a
+    prompt_template: prompt.tmpl
+    allocation_pct: 40
+    risk_params:
+      max_positions: 1
+      max_position_size_usd: 100
+      major_coin_leverage: 10
+      altcoin_leverage: 5
+      min_risk_reward_ratio: 2
+
+monitoring:
+  metrics_exporter: prometheus
+`
+	cfg, err := LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(configYAML, "Flatten")))
+	if assert.NoError(t, err, "LoadConfig should accept a known reconcile mode") {
+		assert.Equal(t, StartupReconcileFlatten, cfg.Manager.StartupReconcile, "reconcile mode should be normalised")
+	}
+
+	_, err = LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(configYAML, "close")))
+	assert.Error(t, err, "LoadConfig should error for an unknown reconcile mode")
+	assert.Contains(t, err.Error(), `startup_reconcile "close"`, "error should name the reconcile mode")
+}
+
+func TestSyncConfig(t *testing.T) {
+	configYAML := `
+manager:
+  total_equity_usd: 1000
+  sync_concurrency: %d
+  sync_timeout: %s
+  state_storage_backend: file
+  state_storage_path: state.json
+
+traders:
+  - id: t1
+    name: Trader1
+    exchange_provider: ex
+    market_provider: market_a
+    prompt_template: prompt.tmpl
+    allocation_pct: 40
+    risk_params:
+      max_positions: 1
+      max_position_size_usd: 100
+      major_coin_leverage: 10
+      altcoin_leverage: 5
+      min_risk_reward_ratio: 2
+
+monitoring:
+  metrics_exporter: prometheus
+`
+	cfg, err := LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(configYAML, 8, "5s")))
+	if assert.NoError(t, err, "LoadConfig should accept sync settings") {
+		assert.Equal(t, 8, cfg.Manager.SyncConcurrency)
+		assert.Equal(t, 5*time.Second, cfg.Manager.SyncTimeout)
+	}
+
+	_, err = LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(configYAML, -1, "5s")))
+	assert.Error(t, err, "LoadConfig should reject a negative sync concurrency")
+	assert.Contains(t, err.Error(), "sync_concurrency")
+
+	_, err = LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(configYAML, 2, "0s")))
+	assert.Error(t, err, "LoadConfig should reject a non-positive sync timeout")
+	assert.Contains(t, err.Error(), "sync_timeout")
+}
+
+func TestSharpeGateConfig(t *testing.T) {
+	configYAML := `
+manager:
+  total_equity_usd: 1000
+  state_storage_backend: file
+  state_storage_path: state.json
+
+traders:
+  - id: t1
+    name: Trader1
+    exchange_provider: ex
+    market_provider: market_a
+    prompt_template: prompt.tmpl
+    allocation_pct: 40
+    risk_params:
+      max_positions: 1
+      max_position_size_usd: 100
+      major_coin_leverage: 10
+      altcoin_leverage: 5
+      min_risk_reward_ratio: 2
+    exec_guards:
+      sharpe_pause_threshold: -0.5
+      pause_duration_on_breach: 30m
+      sharpe_lookback: %s
+      sharpe_min_trades: %d
+      sharpe_window: %d
+
+monitoring:
+  metrics_exporter: prometheus
+`
+	cfg, err := LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(configYAML, "24h", 10, 48)))
+	if assert.NoError(t, err, "LoadConfig should accept a Sharpe lookback") {
+		assert.Equal(t, 24*time.Hour, cfg.Traders[0].ExecGuards.SharpeLookback)
+		assert.Equal(t, 10, cfg.Traders[0].ExecGuards.SharpeMinTrades)
+		assert.Equal(t, 48, cfg.Traders[0].ExecGuards.SharpeWindow)
+	}
+
+	_, err = LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(configYAML, "0s", 10, 0)))
+	assert.Error(t, err, "LoadConfig should reject a non-positive lookback")
+	assert.Contains(t, err.Error(), "sharpe_lookback")
+
+	_, err = LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(configYAML, "24h", -1, 0)))
+	assert.Error(t, err, "LoadConfig should reject a negative minimum sample")
+	assert.Contains(t, err.Error(), "sharpe_min_trades")
+
+	_, err = LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(configYAML, "24h", 0, -1)))
+	assert.Error(t, err, "LoadConfig should reject a negative window")
+	assert.Contains(t, err.Error(), "sharpe_window")
+}
+
+func TestDecisionTimeoutConfig(t *testing.T) {
+	configYAML := `
+manager:
+  total_equity_usd: 1000
+  state_storage_backend: file
+  state_storage_path: state.json
+
+traders:
+  - id: t1
+    name: Trader1
+    exchange_provider: ex
+    market_provider: market_a
+    prompt_template: prompt.tmpl
+    allocation_pct: 40
+    decision_interval: 30s
+    decision_timeout: %s
+    risk_params:
+      max_positions: 1
+      max_position_size_usd: 100
+      major_coin_leverage: 10
+      altcoin_leverage: 5
+      min_risk_reward_ratio: 2
+
+monitoring:
+  metrics_exporter: prometheus
+`
+	_, err := LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(configYAML, "60s")))
+	assert.Error(t, err, "a 60s timeout on a 30s interval should be rejected")
+	assert.Contains(t, err.Error(), "decision_timeout")
+
+	cfg, err := LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(configYAML, "20s")))
+	if assert.NoError(t, err) {
+		assert.Equal(t, 20*time.Second, cfg.Traders[0].DecisionTimeout)
+	}
+}
+
+func TestDecisionTimeoutDerivation(t *testing.T) {
+	assert.Equal(t, 24*time.Second, decisionTimeout(0, 30*time.Second), "the default should be capped below a 30s interval")
+	assert.Equal(t, 48*time.Second, decisionTimeout(0, time.Minute), "an interval equal to the default still caps it")
+	assert.Equal(t, defaultDecisionTimeout, decisionTimeout(0, 3*time.Minute))
+	assert.Equal(t, 10*time.Second, decisionTimeout(10*time.Second, 30*time.Second), "an explicit timeout wins")
+}
+
+func TestVaultAddressConfig(t *testing.T) {
+	base := `
+manager:
+  total_equity_usd: 1000
+  state_storage_backend: file
+  state_storage_path: state.json
+
+traders:
+  - id: t1
+    name: Trader1
+    exchange_provider: ex
+    market_provider: market_a
+    vault_address: %s
+    prompt_template: prompt.tmpl
+    allocation_pct: 40
+    risk_params:
+      max_positions: 1
+      max_position_size_usd: 100
+      major_coin_leverage: 10
+      altcoin_leverage: 5
+      min_risk_reward_ratio: 2
+monitoring:
+  metrics_exporter: prometheus
+`
+	t.Setenv("TEST_VAULT", "0xAbCdEf0123456789abcdef0123456789ABCDEF01")
+	cfg, err := LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(base, "${TEST_VAULT}")))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0xAbCdEf0123456789abcdef0123456789ABCDEF01", cfg.Traders[0].VaultAddress)
+	}
+
+	for _, bad := range []string{"0x1234", "AbCdEf0123456789abcdef0123456789ABCDEF0123", "0xZZCdEf0123456789abcdef0123456789ABCDEF01"} {
+		_, err = LoadConfig(writeTestManagerConfig(t, fmt.Sprintf(base, bad)))
+		assert.ErrorContains(t, err, "vault_address", bad)
+	}
+}
@@ -0,0 +1,83 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+func TestCooldownAfterLoss(t *testing.T) {
+	closeAt := func(t *testing.T, exitPrice float64) (*Manager, *VirtualTrader) {
+		ctx := context.Background()
+		ex := sim.New()
+		require.NoError(t, ex.SetMarkPrice(ctx, "ETH", 3000))
+		_, err := ex.IOCMarket(ctx, "ETH", true, 0.5, 0, false)
+		require.NoError(t, err)
+
+		m := NewManager(nil, nil, nil, nil, nil)
+		vt := &VirtualTrader{
+			ID:               "cd",
+			ExchangeProvider: ex,
+			MarketProvider:   newFakeMarket(testSnapshot("ETH", exitPrice, 0)),
+			RiskParams:       RiskParameters{MaxPositions: 3},
+			ExecGuards:       ExecGuards{CooldownAfterClose: 5 * time.Minute, CooldownAfterLoss: 30 * time.Minute},
+			Cooldown:         make(map[string]time.Time),
+		}
+		require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "ETH", Action: "close_long"}))
+		return m, vt
+	}
+	reopen := []executorpkg.Decision{{Symbol: "ETH", Action: "open_long", PositionSizeUSD: 100}}
+	later := time.Now().Add(10 * time.Minute)
+
+	m, vt := closeAt(t, 2900)
+	assert.True(t, vt.LossCooldown["ETH"], "close below entry should be classified as a loss")
+	if assert.NotNil(t, vt.Performance, "realized close should update performance") {
+		assert.Equal(t, 1, vt.Performance.LosingTrades, "losing close should be counted as a losing trade")
+	}
+	assert.Empty(t, m.applyDecisionGuards(vt, append([]executorpkg.Decision(nil), reopen...), 0, later), "losing close should hold the longer cooldown")
+	assert.Len(t, m.applyDecisionGuards(vt, append([]executorpkg.Decision(nil), reopen...), 0, time.Now().Add(31*time.Minute)), 1, "loss cooldown should expire")
+
+	m, vt = closeAt(t, 3100)
+	assert.False(t, vt.LossCooldown["ETH"], "close above entry should be classified as a win")
+	assert.Len(t, m.applyDecisionGuards(vt, append([]executorpkg.Decision(nil), reopen...), 0, later), 1, "winning close should use the standard cooldown")
+	assert.Empty(t, m.applyDecisionGuards(vt, append([]executorpkg.Decision(nil), reopen...), 0, time.Now().Add(time.Minute)), "standard cooldown should still apply")
+}
+
+func TestExecuteDecisionEnforcesCooldown(t *testing.T) {
+	ctx := context.Background()
+	closedAt := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+	ex := sim.New()
+	require.NoError(t, ex.SetMarkPrice(ctx, "ETH", 3000))
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := &VirtualTrader{
+		ID:               "cd",
+		ExchangeProvider: ex,
+		MarketProvider:   newFakeMarket(testSnapshot("ETH", 3000, 0.02), testSnapshot("SOL", 150, 0.01)),
+		RiskParams:       RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 1000, MajorCoinLeverage: 3},
+		ExecGuards:       ExecGuards{CooldownAfterClose: 15 * time.Minute},
+		Cooldown:         map[string]time.Time{"ETH": closedAt},
+	}
+	reopen := &executorpkg.Decision{Symbol: "ETH", Action: "open_long", PositionSizeUSD: 300}
+
+	m.SetClock(fixedClock{closedAt.Add(10 * time.Minute)})
+	err := m.ExecuteDecision(vt, reopen)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cooling down")
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardCooldown])
+	candidates := m.selectCandidates(ctx, vt, 0, nil)
+	require.Len(t, candidates, 1, "a cooling symbol is not offered as a candidate")
+	assert.Equal(t, "SOL", candidates[0].Symbol)
+
+	m.SetClock(fixedClock{closedAt.Add(16 * time.Minute)})
+	require.NoError(t, m.ExecuteDecision(vt, reopen))
+	positions, err := ex.GetPositions(ctx)
+	require.NoError(t, err)
+	require.Len(t, positions, 1, "the open goes through once the cooldown elapses")
+	assert.Len(t, m.selectCandidates(ctx, vt, 0, nil), 2)
+}
@@ -0,0 +1,386 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+	"nof0-api/pkg/journal"
+	"nof0-api/pkg/llm"
+)
+
+// newCycleTestTrader wires a running trader backed by the sim exchange, the
+// given fake market and a fake executor, and registers it with m.
+func newCycleTestTrader(m *Manager, id string, mk *fakeMarket, exec *fakeExecutor) *VirtualTrader {
+	vt := &VirtualTrader{
+		ID:               id,
+		ExchangeProvider: sim.New(),
+		MarketProvider:   mk,
+		Executor:         exec,
+		RiskParams:       RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 1000, MajorCoinLeverage: 5, AltcoinLeverage: 3},
+		State:            TraderStateRunning,
+		Cooldown:         make(map[string]time.Time),
+	}
+	m.traders[id] = vt
+	return vt
+}
+
+func TestRunTraderCycle_SkipsOnSparseMarketData(t *testing.T) {
+	mk := newFakeMarket(
+		testSnapshot("BTC", 60000, 0.03),
+		testSnapshot("ETH", 3000, 0.02),
+		testSnapshot("SOL", 150, 0.01),
+		testSnapshot("DOGE", 0.1, 0.04),
+	)
+	// Three of four assets fail to load when the executor context is built.
+	mk.down["BTC"], mk.down["ETH"], mk.down["SOL"] = true, true, true
+	exec := &fakeExecutor{}
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := newCycleTestTrader(m, "sparse", mk, exec)
+
+	m.runTraderCycle(context.Background(), vt)
+	assert.Zero(t, exec.callCount(), "executor should not be consulted on sparse data")
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardSparseData], "sparse data skip should be counted")
+	assert.False(t, vt.LastDecisionAt.IsZero(), "skipped cycle should still advance the decision clock")
+}
+
+func TestRunTraderCycle_SparseGuardThresholdAndToggle(t *testing.T) {
+	newMarket := func() *fakeMarket {
+		mk := newFakeMarket(testSnapshot("BTC", 60000, 0.03), testSnapshot("ETH", 3000, 0.02))
+		mk.down["BTC"] = true
+		return mk
+	}
+
+	// 50% coverage meets the default threshold.
+	exec := &fakeExecutor{}
+	m := NewManager(nil, nil, nil, nil, nil)
+	m.runTraderCycle(context.Background(), newCycleTestTrader(m, "half", newMarket(), exec))
+	assert.Equal(t, 1, exec.callCount(), "coverage at the threshold should proceed")
+
+	// Disabled guard never skips.
+	exec = &fakeExecutor{}
+	off := false
+	vt := newCycleTestTrader(m, "off", newMarket(), exec)
+	vt.ExecGuards.MinSnapshotCoveragePct = 90
+	vt.ExecGuards.EnableSparseDataGuard = &off
+	m.runTraderCycle(context.Background(), vt)
+	assert.Equal(t, 1, exec.callCount(), "disabled sparse guard should not skip")
+}
+
+func TestRunTraderCycle_SkipsExecutionOverLatencyBudget(t *testing.T) {
+	newTrader := func(m *Manager, exec *fakeExecutor) *VirtualTrader {
+		mk := newFakeMarket(testSnapshot("ETH", 3000, 0.02))
+		vt := newCycleTestTrader(m, "slow", mk, exec)
+		vt.DecisionInterval = 100 * time.Millisecond
+		vt.ExecGuards.DecisionLatencyBudgetPct = 50
+		vt.Journal = journal.NewWriter(t.TempDir())
+		vt.JournalEnabled = true
+		return vt
+	}
+	decision := executorpkg.Decision{Symbol: "ETH", Action: "open_long", PositionSizeUSD: 300, Leverage: 2}
+
+	exec := &fakeExecutor{decisions: []executorpkg.Decision{decision}, delay: 80 * time.Millisecond}
+	m := NewManager(nil, nil, nil, nil, nil)
+	dir := t.TempDir()
+	vt := newTrader(m, exec)
+	vt.Journal = journal.NewWriter(dir)
+	m.runTraderCycle(context.Background(), vt)
+
+	positions, err := vt.ExchangeProvider.GetPositions(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, positions, "over-budget decision should not be executed")
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardLatencyBudget], "latency skip should be counted")
+	files, err := filepath.Glob(filepath.Join(dir, "cycle_*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1, "skipped cycle should be journaled")
+	rec, err := journal.ReadCycle(files[0])
+	require.NoError(t, err)
+	assert.False(t, rec.Success)
+	assert.Contains(t, rec.ErrorMessage, "decision latency")
+	assert.Empty(t, rec.Actions, "no actions should be recorded for a skipped cycle")
+
+	fast := &fakeExecutor{decisions: []executorpkg.Decision{decision}}
+	m = NewManager(nil, nil, nil, nil, nil)
+	vt = newTrader(m, fast)
+	m.runTraderCycle(context.Background(), vt)
+	positions, err = vt.ExchangeProvider.GetPositions(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, positions, 1, "decision within budget should execute")
+}
+
+func TestRunTraderCycle_JournalsModelRefusal(t *testing.T) {
+	dir := t.TempDir()
+	exec := &fakeExecutor{err: &llm.RefusalError{Text: "I'm sorry, I can't help with that."}}
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := newCycleTestTrader(m, "refuse", newFakeMarket(testSnapshot("BTC", 60000, 0.01)), exec)
+	vt.Journal = journal.NewWriter(dir)
+	vt.JournalEnabled = true
+
+	m.runTraderCycle(context.Background(), vt)
+
+	files, err := filepath.Glob(filepath.Join(dir, "cycle_*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	rec, err := journal.ReadCycle(files[0])
+	require.NoError(t, err)
+	assert.False(t, rec.Success)
+	assert.Equal(t, true, rec.Extra["model_refusal"], "refusal should be tagged distinctly")
+	assert.Equal(t, "I'm sorry, I can't help with that.", rec.Extra["refusal_text"])
+}
+
+func TestRunTraderCycle_JournalsLLMBudgetSkip(t *testing.T) {
+	dir := t.TempDir()
+	exec := &fakeExecutor{err: fmt.Errorf("%w: spent 1.0000 usd of 1.0000 usd", llm.ErrBudgetExceeded)}
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := newCycleTestTrader(m, "broke", newFakeMarket(testSnapshot("BTC", 60000, 0.01)), exec)
+	vt.Journal = journal.NewWriter(dir)
+	vt.JournalEnabled = true
+
+	m.runTraderCycle(context.Background(), vt)
+
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardLLMSpendBudget])
+	files, err := filepath.Glob(filepath.Join(dir, "cycle_*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1, "budget skips should still be journaled")
+	rec, err := journal.ReadCycle(files[0])
+	require.NoError(t, err)
+	assert.False(t, rec.Success)
+	assert.Equal(t, true, rec.Extra["cycle_skipped"])
+	assert.Equal(t, GuardLLMSpendBudget, rec.Extra["skip_reason"])
+	assert.Contains(t, rec.ErrorMessage, "daily spend budget exceeded")
+}
+
+func TestRunTraderCycle_JournalsEveryExtraTag(t *testing.T) {
+	dir := t.TempDir()
+	exec := &fakeExecutor{err: errors.Join(&llm.RefusalError{Text: "no"}, llm.ErrBudgetExceeded)}
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := newCycleTestTrader(m, "both", newFakeMarket(testSnapshot("BTC", 60000, 0.01)), exec)
+	vt.Journal = journal.NewWriter(dir)
+	vt.JournalEnabled = true
+
+	m.runTraderCycle(context.Background(), vt)
+
+	files, err := filepath.Glob(filepath.Join(dir, "cycle_*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	rec, err := journal.ReadCycle(files[0])
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"model_refusal": true,
+		"refusal_text":  "no",
+		"cycle_skipped": true,
+		"skip_reason":   GuardLLMSpendBudget,
+	}, rec.Extra, "a later tag must not overwrite an earlier one")
+}
+
+func TestRunTraderCycle_JournalsInputHash(t *testing.T) {
+	var records []*journal.CycleRecord
+	m := NewManager(nil, nil, nil, nil, nil)
+	exec := &fakeExecutor{decisions: []executorpkg.Decision{{Symbol: "BTC", Action: "hold"}}}
+	vt := newCycleTestTrader(m, "hash", newFakeMarket(testSnapshot("BTC", 60000, 0.01)), exec)
+	vt.Journal = journal.CycleWriterFunc(func(rec *journal.CycleRecord) error {
+		records = append(records, rec)
+		return nil
+	})
+	vt.JournalEnabled = true
+	vt.Performance = &PerformanceMetrics{}
+
+	m.runTraderCycle(context.Background(), vt)
+	m.runTraderCycle(context.Background(), vt)
+	require.Len(t, records, 2)
+	require.NotEmpty(t, records[0].InputHash)
+	assert.Equal(t, records[0].InputHash, records[1].InputHash, "cycles over the same state should share an input hash")
+
+	ex := vt.ExchangeProvider.(*sim.Provider)
+	require.NoError(t, ex.SetMarkPrice(context.Background(), "BTC", 60000))
+	_, err := ex.IOCMarket(context.Background(), "BTC", true, 0.01, 0.01, false)
+	require.NoError(t, err)
+	m.runTraderCycle(context.Background(), vt)
+	require.Len(t, records, 3)
+	assert.NotEqual(t, records[0].InputHash, records[2].InputHash, "a new position should change the input hash")
+}
+
+func TestRunTraderCycle_PersistsPromptLinkedToCycle(t *testing.T) {
+	run := func(persistPrompts bool) (DecisionCycleRecord, *journal.CycleRecord) {
+		dir := t.TempDir()
+		persist := &fakePersistence{}
+		m := NewManager(nil, nil, nil, nil, persist)
+		exec := &fakeExecutor{prompt: "## Account\nequity 1000", cot: "flat market", usage: llm.Usage{PromptTokens: 120, CompletionTokens: 30, TotalTokens: 150}}
+		vt := newCycleTestTrader(m, "prompts", newFakeMarket(testSnapshot("BTC", 60000, 0.01)), exec)
+		vt.Journal = journal.NewWriter(dir)
+		vt.JournalEnabled = true
+		vt.PersistPrompts = persistPrompts
+
+		m.runTraderCycle(context.Background(), vt)
+
+		require.Len(t, persist.cycles, 1)
+		files, err := filepath.Glob(filepath.Join(dir, "cycle_*.json"))
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		rec, err := journal.ReadCycle(files[0])
+		require.NoError(t, err)
+		return persist.cycles[0], rec
+	}
+
+	stored, journaled := run(true)
+	require.NotEmpty(t, stored.Cycle.CycleID)
+	assert.Equal(t, journaled.CycleID, stored.Cycle.CycleID, "journal and database share the cycle id")
+	assert.Equal(t, "## Account\nequity 1000", stored.Prompt, "the full rendered prompt is persisted")
+	assert.Equal(t, 150, stored.Usage.TotalTokens)
+	assert.Equal(t, llm.DigestString("## Account\nequity 1000"), stored.Cycle.PromptDigest)
+
+	stored, _ = run(false)
+	assert.NotEmpty(t, stored.Cycle.CycleID, "cycles are always identified")
+	assert.Empty(t, stored.Prompt, "prompts are not persisted unless enabled")
+}
+
+func TestRunTraderCycle_RejectedDecisionsAreNotExecuted(t *testing.T) {
+	dir := t.TempDir()
+	// An executor that hands back its decision along with the rejection, as a
+	// strict symbol universe did for off-universe symbols.
+	exec := &fakeExecutor{
+		decisions: []executorpkg.Decision{{Symbol: "DOGE", Action: "open_long", PositionSizeUSD: 300, Leverage: 2}},
+		err:       fmt.Errorf("executor: decision symbol %q is outside the candidate set and open positions", "DOGE"),
+	}
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := newCycleTestTrader(m, "strict", newFakeMarket(testSnapshot("DOGE", 0.1, 0.01), testSnapshot("BTC", 60000, 0.01)), exec)
+	vt.Journal = journal.NewWriter(dir)
+	vt.JournalEnabled = true
+
+	m.runTraderCycle(context.Background(), vt)
+
+	positions, err := vt.ExchangeProvider.GetPositions(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, positions, "a rejected decision must not place an order")
+	files, err := filepath.Glob(filepath.Join(dir, "cycle_*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	rec, err := journal.ReadCycle(files[0])
+	require.NoError(t, err)
+	assert.False(t, rec.Success)
+	assert.Contains(t, rec.ErrorMessage, "outside the candidate set")
+	assert.Empty(t, rec.Actions)
+	require.Len(t, rec.Executions, 1)
+	assert.Equal(t, journal.ExecutionSkipped, rec.Executions[0].Status)
+	assert.Equal(t, "decision_error", rec.Executions[0].Reason)
+}
+
+// outOfRangeLLM proposes a BTC long with a confidence above 100.
+type outOfRangeLLM struct{}
+
+func (outOfRangeLLM) Chat(context.Context, *llm.ChatRequest) (*llm.ChatResponse, error) {
+	return nil, nil
+}
+
+func (outOfRangeLLM) ChatStream(context.Context, *llm.ChatRequest) (<-chan llm.StreamResponse, error) {
+	return nil, nil
+}
+
+func (outOfRangeLLM) ChatStructured(_ context.Context, _ *llm.ChatRequest, target interface{}) (*llm.ChatResponse, error) {
+	jsonStr := `{"signal":"buy_to_enter","symbol":"BTC","leverage":5,"position_size_usd":200,"entry_price":60000,` +
+		`"stop_loss":59000,"take_profit":64000,"risk_usd":10,"confidence":150,"invalidation_condition":"x","reasoning":"y"}`
+	if err := llm.ParseStructured(jsonStr, target); err != nil {
+		return nil, err
+	}
+	return &llm.ChatResponse{Choices: []llm.Choice{{Message: llm.Message{Role: "assistant", Content: jsonStr}}}}, nil
+}
+
+func (outOfRangeLLM) GetConfig() *llm.Config { return &llm.Config{} }
+func (outOfRangeLLM) Close() error           { return nil }
+
+// conversationSink counts conversations recorded by an executor.
+type conversationSink struct {
+	mu      sync.Mutex
+	records []executorpkg.ConversationRecord
+}
+
+func (s *conversationSink) RecordConversation(_ context.Context, rec executorpkg.ConversationRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, rec)
+	return nil
+}
+
+func TestPersistPromptsRecordsOneLinkedConversationPerCycle(t *testing.T) {
+	run := func(persistPrompts bool) (*conversationSink, *fakePersistence) {
+		sink := &conversationSink{}
+		factory := NewBasicExecutorFactory(outOfRangeLLM{}, sink)
+		exec, err := factory.NewExecutor(TraderConfig{
+			ID:               "prompts",
+			ExecutorTemplate: filepath.Join("..", "..", "etc", "prompts", "executor", "default_prompt.tmpl"),
+			DecisionInterval: 3 * time.Minute,
+			RiskParams:       RiskParameters{MaxPositions: 3, MajorCoinLeverage: 5, AltcoinLeverage: 3},
+			PersistPrompts:   persistPrompts,
+		})
+		require.NoError(t, err)
+		persist := &fakePersistence{}
+		m := NewManager(nil, nil, nil, nil, persist)
+		vt := newCycleTestTrader(m, "prompts", newFakeMarket(testSnapshot("BTC", 60000, 0.01)), nil)
+		vt.Executor = exec
+		vt.Journal = journal.NewWriter(t.TempDir())
+		vt.JournalEnabled = true
+		vt.PersistPrompts = persistPrompts
+
+		m.runTraderCycle(context.Background(), vt)
+		return sink, persist
+	}
+
+	sink, persist := run(true)
+	assert.Empty(t, sink.records, "the executor must not write an unlinked duplicate")
+	require.Len(t, persist.cycles, 1)
+	assert.NotEmpty(t, persist.cycles[0].Cycle.CycleID)
+	assert.NotEmpty(t, persist.cycles[0].Prompt, "the cycle carries the one linked conversation")
+
+	sink, persist = run(false)
+	assert.Len(t, sink.records, 1, "without persist_prompts the executor still records the conversation")
+	require.Len(t, persist.cycles, 1)
+	assert.Empty(t, persist.cycles[0].Prompt)
+}
+
+func TestRunTraderCycle_InvalidDecisionNeverExecutes(t *testing.T) {
+	cfg := &executorpkg.Config{
+		MajorCoinLeverage:      20,
+		AltcoinLeverage:        10,
+		MinConfidence:          75,
+		MinRiskReward:          3.0,
+		MaxPositions:           4,
+		DecisionIntervalRaw:    "3m",
+		DecisionTimeoutRaw:     "60s",
+		MaxConcurrentDecisions: 1,
+	}
+	exec, err := executorpkg.NewExecutor(cfg, outOfRangeLLM{}, filepath.Join("..", "..", "etc", "prompts", "executor", "default_prompt.tmpl"), "")
+	require.NoError(t, err)
+	m := NewManager(nil, nil, nil, nil, nil)
+	mk := newFakeMarket(testSnapshot("BTC", 60000, 0.01))
+	vt := newCycleTestTrader(m, "invalid", mk, nil)
+	vt.Executor = exec
+	require.NoError(t, vt.ExchangeProvider.(interface {
+		SetMarkPrice(context.Context, string, float64) error
+	}).SetMarkPrice(context.Background(), "BTC", 60000))
+	dir := t.TempDir()
+	vt.Journal = journal.NewWriter(dir)
+	vt.JournalEnabled = true
+
+	m.runTraderCycle(context.Background(), vt)
+
+	positions, err := vt.ExchangeProvider.GetPositions(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, positions, "a decision failing central validation must not be executed")
+	files, err := filepath.Glob(filepath.Join(dir, "cycle_*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	rec, err := journal.ReadCycle(files[0])
+	require.NoError(t, err)
+	assert.False(t, rec.Success)
+	assert.Contains(t, rec.ErrorMessage, "confidence")
+	assert.Empty(t, rec.Actions)
+}
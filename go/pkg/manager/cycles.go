@@ -0,0 +1,71 @@
+package manager
+
+// countCycle records one finished decision cycle for t.
+func (m *Manager) countCycle(t *VirtualTrader) {
+	m.cyclesCompleted.Add(1)
+	t.mu.Lock()
+	t.CyclesCompleted++
+	t.mu.Unlock()
+}
+
+// CyclesCompleted returns the number of decision cycles run across all traders.
+func (m *Manager) CyclesCompleted() int64 {
+	if m == nil {
+		return 0
+	}
+	return m.cyclesCompleted.Load()
+}
+
+func (m *Manager) cycleLimits() (total, perTrader int) {
+	if m.config == nil {
+		return 0, 0
+	}
+	return m.config.Manager.MaxCycles, m.config.Manager.MaxCyclesPerTrader
+}
+
+// remainingCycles reports how many cycles may still run under manager.max_cycles.
+func (m *Manager) remainingCycles() (int, bool) {
+	total, _ := m.cycleLimits()
+	if total <= 0 {
+		return 0, false
+	}
+	// Cycles already running on decision workers count against the budget.
+	left := total - int(m.cyclesCompleted.Load()) - int(m.cyclesInFlight.Load())
+	if left < 0 {
+		left = 0
+	}
+	return left, true
+}
+
+func (m *Manager) traderCycleLimitReached(t *VirtualTrader) bool {
+	_, perTrader := m.cycleLimits()
+	if perTrader <= 0 {
+		return false
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.CyclesCompleted >= perTrader
+}
+
+// cycleLimitReached reports whether the loop should stop: the global budget is
+// spent, or every active trader has used its per-trader budget.
+func (m *Manager) cycleLimitReached() bool {
+	// Only finished cycles count here; remainingCycles also reserves the ones
+	// still running on decision workers so dispatch cannot overshoot.
+	if total, _ := m.cycleLimits(); total > 0 && m.cyclesCompleted.Load() >= int64(total) {
+		return true
+	}
+	if _, perTrader := m.cycleLimits(); perTrader <= 0 {
+		return false
+	}
+	active := m.GetActiveTraders()
+	if len(active) == 0 {
+		return false
+	}
+	for _, t := range active {
+		if !m.traderCycleLimitReached(t) {
+			return false
+		}
+	}
+	return true
+}
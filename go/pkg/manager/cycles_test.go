@@ -0,0 +1,67 @@
+package manager
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// steppingClock advances by step on every Now call so each scheduling round
+// sees every trader's decision interval as elapsed.
+type steppingClock struct {
+	mu   sync.Mutex
+	t    time.Time
+	step time.Duration
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(c.step)
+	return c.t
+}
+
+func newCycleLimitManager(cfg ManagerConfig) *Manager {
+	m := NewManager(&Config{Manager: cfg}, nil, nil, nil, nil)
+	m.tickInterval = time.Millisecond
+	m.SetClock(&steppingClock{t: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), step: time.Hour})
+	return m
+}
+
+func runLoopWithTimeout(t *testing.T, m *Manager) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, m.RunTradingLoop(ctx), "loop should stop on its own before the deadline")
+}
+
+func TestRunTradingLoopStopsAfterMaxCycles(t *testing.T) {
+	m := newCycleLimitManager(ManagerConfig{MaxCycles: 5})
+	execA, execB := &fakeExecutor{}, &fakeExecutor{}
+	for id, exec := range map[string]*fakeExecutor{"a": execA, "b": execB} {
+		vt := newCycleTestTrader(m, id, newFakeMarket(testSnapshot("BTC", 60000, 0.01)), exec)
+		vt.DecisionInterval = time.Minute
+	}
+
+	runLoopWithTimeout(t, m)
+	assert.Equal(t, 5, execA.callCount()+execB.callCount())
+	assert.Equal(t, int64(5), m.CyclesCompleted())
+}
+
+func TestRunTradingLoopStopsAfterMaxCyclesPerTrader(t *testing.T) {
+	m := newCycleLimitManager(ManagerConfig{MaxCyclesPerTrader: 3})
+	execA, execB := &fakeExecutor{}, &fakeExecutor{}
+	for id, exec := range map[string]*fakeExecutor{"a": execA, "b": execB} {
+		vt := newCycleTestTrader(m, id, newFakeMarket(testSnapshot("BTC", 60000, 0.01)), exec)
+		vt.DecisionInterval = time.Minute
+	}
+
+	runLoopWithTimeout(t, m)
+	assert.Equal(t, 3, execA.callCount())
+	assert.Equal(t, 3, execB.callCount())
+	assert.Equal(t, int64(6), m.CyclesCompleted())
+}
@@ -0,0 +1,60 @@
+package manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// utcDay returns the UTC midnight starting the day that contains at.
+func utcDay(at time.Time) time.Time {
+	return at.UTC().Truncate(24 * time.Hour)
+}
+
+// observeDailyLoss returns how far equity sits below the first equity seen on
+// the UTC day of now, and that day-start equity. The reference resets on the
+// first observation after each UTC midnight.
+func (t *VirtualTrader) observeDailyLoss(now time.Time, equity float64) (loss, dayStartEquity float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if day := utcDay(now); !day.Equal(t.lossDay) || t.dayStartEquity <= 0 {
+		t.lossDay = day
+		t.dayStartEquity = equity
+	}
+	return t.dayStartEquity - equity, t.dayStartEquity
+}
+
+// gateDailyLoss reports whether t has lost MaxDailyLossUSD, or
+// MaxDailyLossPct of its day-start equity, since UTC midnight. Unrealized PnL
+// counts through the account equity. On a breach the trader is paused until
+// the next UTC midnight.
+func (m *Manager) gateDailyLoss(ctx context.Context, t *VirtualTrader, equity float64) bool {
+	limitUSD, limitPct := t.ExecGuards.MaxDailyLossUSD, t.ExecGuards.MaxDailyLossPct
+	if limitUSD <= 0 && limitPct <= 0 {
+		return false
+	}
+	now := m.now()
+	loss, start := t.observeDailyLoss(now, equity)
+	lossPct := 0.0
+	if start > 0 {
+		lossPct = loss / start * 100
+	}
+	if !(limitUSD > 0 && loss >= limitUSD) && !(limitPct > 0 && lossPct >= limitPct) {
+		return false
+	}
+	m.recordGuardRejection(t.ID, GuardDailyLoss, 1)
+	resume := utcDay(now).Add(24 * time.Hour)
+	t.mu.Lock()
+	paused := false
+	if t.PauseUntil.Before(resume) {
+		t.PauseUntil = resume
+		paused = true
+	}
+	t.mu.Unlock()
+	if paused {
+		m.sendAlert(AlertTraderPaused, t.ID, "daily loss %.2f usd (%.2f%%) reached limit; paused until %s", loss, lossPct, resume.Format(time.RFC3339))
+	}
+	logx.WithContext(ctx).Errorf("manager: trader %s skip cycle reason=max_daily_loss loss=%.2f usd loss_pct=%.2f%% limit_usd=%.2f limit_pct=%.2f%% resume=%s", t.ID, loss, lossPct, limitUSD, limitPct, resume.Format(time.RFC3339))
+	return true
+}
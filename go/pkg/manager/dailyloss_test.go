@@ -0,0 +1,71 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+func TestDailyLossLimitPausesUntilMidnight(t *testing.T) {
+	ctx := context.Background()
+	morning := time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC)
+	m := NewManager(nil, nil, nil, nil, nil)
+	m.SetClock(fixedClock{morning})
+
+	ex := sim.NewWithEquity(1000)
+	require.NoError(t, ex.SetMarkPrice(ctx, "BTC", 60000))
+	_, err := ex.IOCMarket(ctx, "BTC", true, 0.01, 0, false)
+	require.NoError(t, err)
+	require.NoError(t, ex.SetMarkPrice(ctx, "SOL", 150))
+
+	exec := &fakeExecutor{}
+	mk := newFakeMarket(testSnapshot("BTC", 60000, 0), testSnapshot("SOL", 150, 0)).withMaxLeverage(20)
+	vt := newCycleTestTrader(m, "daily", mk, exec)
+	vt.ExchangeProvider = ex
+	vt.ExecGuards.MaxDailyLossUSD = 50
+
+	// The first reading of the day sets the reference equity.
+	m.runTraderCycle(ctx, vt)
+	require.Equal(t, 1, exec.callCount())
+
+	// BTC falls 10%: 60 usd of unrealized loss, over the 50 usd limit.
+	require.NoError(t, ex.SetMarkPrice(ctx, "BTC", 54000))
+	mk.snaps["BTC"] = testSnapshot("BTC", 54000, 0)
+	exec.decisions = []executorpkg.Decision{{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 100, Confidence: 90}}
+	m.SetClock(fixedClock{morning.Add(3 * time.Hour)})
+	m.runTraderCycle(ctx, vt)
+
+	assert.Equal(t, 1, exec.callCount(), "a breached trader must not consult the executor")
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardDailyLoss])
+	positions, err := ex.GetPositions(ctx)
+	require.NoError(t, err)
+	require.Len(t, positions, 1, "no new position after the breach")
+	assert.Equal(t, "BTC", positions[0].Coin)
+
+	midnight := time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, midnight, vt.PauseUntil)
+	assert.False(t, vt.ShouldMakeDecisionAt(morning.Add(14*time.Hour)), "paused for the rest of the day")
+	assert.True(t, vt.ShouldMakeDecisionAt(midnight.Add(time.Minute)), "resumes after midnight")
+
+	// The next day measures losses from its own first reading.
+	m.SetClock(fixedClock{midnight.Add(time.Minute)})
+	m.runTraderCycle(ctx, vt)
+	assert.Equal(t, 2, exec.callCount())
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardDailyLoss])
+}
+
+func TestDailyLossPctLimit(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	m.SetClock(fixedClock{time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC)})
+	vt := &VirtualTrader{ID: "pct", ExecGuards: ExecGuards{MaxDailyLossPct: 5}}
+
+	assert.False(t, m.gateDailyLoss(context.Background(), vt, 2000))
+	assert.False(t, m.gateDailyLoss(context.Background(), vt, 1920), "4% down is inside the limit")
+	assert.True(t, m.gateDailyLoss(context.Background(), vt, 1890), "5.5% down breaches it")
+}
@@ -0,0 +1,60 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"nof0-api/pkg/exchange"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// SetDryRun toggles dry-run mode. While enabled ExecuteDecision validates,
+// sizes, prices and formats orders against the real providers but logs them
+// instead of submitting them, and skips leverage, SL/TP and close calls.
+func (m *Manager) SetDryRun(enabled bool) {
+	if m == nil {
+		return
+	}
+	if m.dryRun.Swap(enabled) != enabled {
+		logx.Infof("manager: dry-run mode enabled=%t", enabled)
+	}
+}
+
+// DryRun reports whether dry-run mode is active.
+func (m *Manager) DryRun() bool {
+	return m != nil && m.dryRun.Load()
+}
+
+// dryRunClose logs the reduce-only close that would flatten pos.
+func (m *Manager) dryRunClose(t *VirtualTrader, decision *executorpkg.Decision, pos *exchange.Position, price float64) *ExecutedOrder {
+	method := "ClosePosition"
+	if m.WindDown() || t.OrderStyle == OrderStyleMarketIOC {
+		method = "IOCMarket"
+	}
+	size := 0.0
+	if pos != nil {
+		size = math.Abs(parseFloat(pos.Szi))
+	}
+	logx.Infof("manager: trader %s dry-run close symbol=%s action=%s method=%s size=%.8f price=%.8f reduce_only=true", t.ID, decision.Symbol, decision.Action, method, size, price)
+	return &ExecutedOrder{
+		Symbol:      decision.Symbol,
+		Action:      decision.Action,
+		Price:       price,
+		Size:        size,
+		NotionalUSD: price * size,
+		ReduceOnly:  true,
+		DryRun:      true,
+	}
+}
+
+// formatDryRunOrder renders the order a dry run would have submitted.
+func formatDryRunOrder(order exchange.Order) string {
+	b, err := json.Marshal(order)
+	if err != nil {
+		return fmt.Sprintf("%+v", order)
+	}
+	return string(b)
+}
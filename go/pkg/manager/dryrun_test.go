@@ -0,0 +1,117 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+	"nof0-api/pkg/journal"
+)
+
+// dryRunExchange extends methodRecordingExchange with the calls dry-run mode
+// must still make (asset lookups, formatting) and the ones it must skip.
+type dryRunExchange struct {
+	methodRecordingExchange
+	stopLosses int
+}
+
+func (d *dryRunExchange) GetAssetIndex(ctx context.Context, coin string) (int, error) {
+	d.methods = append(d.methods, "GetAssetIndex")
+	return d.Provider.GetAssetIndex(ctx, coin)
+}
+
+func (d *dryRunExchange) FormatSize(ctx context.Context, coin string, size float64) (string, error) {
+	d.methods = append(d.methods, "FormatSize")
+	return d.Provider.FormatSize(ctx, coin, size)
+}
+
+func (d *dryRunExchange) UpdateLeverage(ctx context.Context, asset int, isCross bool, leverage int) error {
+	d.methods = append(d.methods, "UpdateLeverage")
+	return d.Provider.UpdateLeverage(ctx, asset, isCross, leverage)
+}
+
+func (d *dryRunExchange) SetStopLoss(context.Context, string, string, float64, float64) error {
+	d.stopLosses++
+	return nil
+}
+
+func (d *dryRunExchange) SetTakeProfit(context.Context, string, string, float64, float64) error {
+	return nil
+}
+
+func TestDryRunFormsOrdersWithoutSubmitting(t *testing.T) {
+	ctx := context.Background()
+	ex := &dryRunExchange{methodRecordingExchange: methodRecordingExchange{Provider: sim.New()}}
+	require.NoError(t, ex.SetMarkPrice(ctx, "ETH", 3000))
+	_, err := ex.Provider.IOCMarket(ctx, "ETH", true, 1, 0.001, false)
+	require.NoError(t, err, "seed position should open")
+
+	persist := &fakePersistence{}
+	m := NewManager(nil, nil, nil, nil, persist)
+	m.SetDryRun(true)
+	require.True(t, m.DryRun())
+	vt := &VirtualTrader{
+		ID:               "dry",
+		ExchangeProvider: ex,
+		MarketProvider:   newFakeMarket(testSnapshot("SOL", 150, 0), testSnapshot("ETH", 3000, 0)),
+		RiskParams:       RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 1000, AltcoinLeverage: 3},
+		Cooldown:         make(map[string]time.Time),
+	}
+
+	order, err := m.executeDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 300, StopLoss: 140, TakeProfit: 170})
+	require.NoError(t, err)
+	require.NotNil(t, order)
+	assert.True(t, order.DryRun)
+	assert.InDelta(t, 2, order.Size, 1e-9, "dry run should still size the order")
+	assert.Equal(t, 3, order.Leverage, "dry run should still resolve leverage")
+	assert.Equal(t, []string{"GetAssetIndex", "FormatSize"}, ex.methods, "only lookups and formatting should reach the exchange")
+	assert.Zero(t, ex.stopLosses, "SL/TP should not be placed")
+
+	order, err = m.executeDecision(vt, &executorpkg.Decision{Symbol: "ETH", Action: "close_long"})
+	require.NoError(t, err)
+	require.NotNil(t, order)
+	assert.True(t, order.DryRun)
+	assert.True(t, order.ReduceOnly)
+	assert.InDelta(t, 1, order.Size, 1e-9, "dry-run close should size from the held position")
+	assert.NotContains(t, ex.methods, "ClosePosition")
+	assert.NotContains(t, ex.methods, "PlaceOrder")
+	assert.Empty(t, vt.Cooldown, "dry-run close should not start a cooldown")
+	assert.Empty(t, persist.events, "nothing executed, so no position events")
+
+	positions, err := ex.GetPositions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, positions, 1, "exchange state should be untouched")
+}
+
+func TestDryRunCycleMarksJournalActions(t *testing.T) {
+	persist := &fakePersistence{}
+	m := NewManager(nil, nil, nil, nil, persist)
+	m.SetDryRun(true)
+	exec := &fakeExecutor{decisions: []executorpkg.Decision{{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 300}}}
+	vt := newCycleTestTrader(m, "dry", newFakeMarket(testSnapshot("SOL", 150, 0.01)), exec)
+	off := false
+	vt.ExecGuards.EnableSparseDataGuard = &off
+	vt.Journal = journal.NewWriter(t.TempDir())
+	vt.JournalEnabled = true
+
+	m.runTraderCycle(context.Background(), vt)
+
+	require.Len(t, persist.cycles, 1)
+	rec := persist.cycles[0].Cycle
+	require.Len(t, rec.Actions, 1)
+	assert.Equal(t, true, rec.Actions[0]["dry_run"])
+	assert.Equal(t, "ok", rec.Actions[0]["result"])
+	require.Len(t, rec.Executions, 1)
+	assert.Equal(t, journal.ExecutionDryRun, rec.Executions[0].Status)
+	positions, err := vt.ExchangeProvider.GetPositions(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, positions)
+}
+
+var _ exchange.Provider = (*dryRunExchange)(nil)
@@ -0,0 +1,45 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// Modes for ExecGuards.DuplicatePositionGuard.
+const (
+	DuplicatePositionOff    = "off"
+	DuplicatePositionReject = "reject"
+	DuplicatePositionSkip   = "skip"
+)
+
+func duplicatePositionGuardOn(g ExecGuards) bool {
+	return g.DuplicatePositionGuard == DuplicatePositionReject || g.DuplicatePositionGuard == DuplicatePositionSkip
+}
+
+// gateDuplicatePosition checks an open against the position already held in
+// its symbol. Opening the side already held is rejected with an error, or
+// skipped (skip true, nil error) in skip mode; opening the opposite side
+// passes.
+func (m *Manager) gateDuplicatePosition(t *VirtualTrader, decision *executorpkg.Decision) (skip bool, err error) {
+	if !duplicatePositionGuardOn(t.ExecGuards) {
+		return false, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	held := positionSide(findPosition(ctx, t, decision.Symbol))
+	if held == "" || held != strings.TrimPrefix(decision.Action, "open_") {
+		return false, nil
+	}
+	m.recordGuardRejection(t.ID, GuardDuplicatePos, 1)
+	if t.ExecGuards.DuplicatePositionGuard == DuplicatePositionSkip {
+		logx.Infof("manager: trader %s skip %s symbol=%s reason=duplicate_position held=%s", t.ID, decision.Action, decision.Symbol, held)
+		return true, nil
+	}
+	return false, fmt.Errorf("manager: trader %s %s %s rejected: %s position already open", t.ID, decision.Action, decision.Symbol, held)
+}
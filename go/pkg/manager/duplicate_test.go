@@ -0,0 +1,75 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// newDuplicateTrader returns a trader holding a 0.1 ETH long.
+func newDuplicateTrader(t *testing.T, mode string) (*Manager, *VirtualTrader, *sim.Provider) {
+	t.Helper()
+	ctx := context.Background()
+	ex := sim.New()
+	require.NoError(t, ex.SetMarkPrice(ctx, "ETH", 3000))
+	_, err := ex.IOCMarket(ctx, "ETH", true, 0.1, 0, false)
+	require.NoError(t, err)
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := &VirtualTrader{
+		ID:               "dup",
+		ExchangeProvider: ex,
+		MarketProvider:   newFakeMarket(testSnapshot("ETH", 3000, 0)),
+		RiskParams:       RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 1000, MajorCoinLeverage: 3},
+		ExecGuards:       ExecGuards{DuplicatePositionGuard: mode},
+		Cooldown:         make(map[string]time.Time),
+	}
+	return m, vt, ex
+}
+
+func ethSize(t *testing.T, ex *sim.Provider) float64 {
+	t.Helper()
+	pos := findPosition(context.Background(), &VirtualTrader{ExchangeProvider: ex}, "ETH")
+	if pos == nil {
+		return 0
+	}
+	return parseFloat(pos.Szi)
+}
+
+func TestDuplicatePositionGuardRejectsSameDirection(t *testing.T) {
+	m, vt, ex := newDuplicateTrader(t, DuplicatePositionReject)
+
+	err := m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "ETH", Action: "open_long", PositionSizeUSD: 300})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "long position already open")
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardDuplicatePos])
+	assert.InDelta(t, 0.1, ethSize(t, ex), 1e-9, "the position must not be stacked")
+}
+
+func TestDuplicatePositionGuardSkipIsNoop(t *testing.T) {
+	m, vt, ex := newDuplicateTrader(t, DuplicatePositionSkip)
+
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "ETH", Action: "open_long", PositionSizeUSD: 300}))
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardDuplicatePos])
+	assert.InDelta(t, 0.1, ethSize(t, ex), 1e-9)
+}
+
+func TestDuplicatePositionGuardAllowsFlip(t *testing.T) {
+	m, vt, ex := newDuplicateTrader(t, DuplicatePositionReject)
+
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "ETH", Action: "open_short", PositionSizeUSD: 600}))
+	assert.Zero(t, m.GuardRejections(vt.ID)[GuardDuplicatePos])
+	assert.Less(t, ethSize(t, ex), 0.1, "the opposite-side open reaches the exchange")
+}
+
+func TestDuplicatePositionGuardOffAllowsScalingIn(t *testing.T) {
+	m, vt, ex := newDuplicateTrader(t, "")
+
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "ETH", Action: "open_long", PositionSizeUSD: 300}))
+	assert.Greater(t, ethSize(t, ex), 0.1)
+}
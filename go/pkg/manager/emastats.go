@@ -0,0 +1,101 @@
+package manager
+
+import "math"
+
+// defaultTradeStatsAlpha weights roughly the last 20 closed trades.
+const defaultTradeStatsAlpha = 2.0 / 21.0
+
+// minTradesForSharpe is the number of closed trades required before the EMA
+// Sharpe estimate replaces the configured/persisted value.
+const minTradesForSharpe = 5
+
+// EMAStats tracks exponentially weighted statistics of realized trade PnL.
+// It is not safe for concurrent use; callers serialize updates.
+type EMAStats struct {
+	Alpha   float64 // smoothing factor in (0, 1]
+	WinRate float64 // EMA of win indicator (0..1)
+	AvgPnL  float64 // EMA of realized PnL in USD
+	VarPnL  float64 // EMA variance of realized PnL
+	Count   int     // observations so far
+}
+
+// NewEMAStats returns a tracker with the given smoothing factor; values outside
+// (0, 1] fall back to the default.
+func NewEMAStats(alpha float64) *EMAStats {
+	if !(alpha > 0 && alpha <= 1) {
+		alpha = defaultTradeStatsAlpha
+	}
+	return &EMAStats{Alpha: alpha}
+}
+
+// Observe folds one closed trade's realized PnL into the statistics. The first
+// observation seeds the averages directly.
+func (s *EMAStats) Observe(pnl float64) {
+	if s == nil || math.IsNaN(pnl) || math.IsInf(pnl, 0) {
+		return
+	}
+	win := 0.0
+	if pnl > 0 {
+		win = 1
+	}
+	s.Count++
+	if s.Count == 1 {
+		s.WinRate, s.AvgPnL, s.VarPnL = win, pnl, 0
+		return
+	}
+	a := s.Alpha
+	diff := pnl - s.AvgPnL
+	s.AvgPnL += a * diff
+	s.VarPnL = (1 - a) * (s.VarPnL + a*diff*diff)
+	s.WinRate += a * (win - s.WinRate)
+}
+
+// Sharpe returns the per-trade mean/stddev ratio, or 0 without enough dispersion.
+func (s *EMAStats) Sharpe() float64 {
+	if s == nil || s.Count < 2 || s.VarPnL <= 0 {
+		return 0
+	}
+	return s.AvgPnL / math.Sqrt(s.VarPnL)
+}
+
+// recordClosedTrade updates the trader's performance from a realized close,
+// replacing the execution-success proxy with profitability-based metrics.
+func (t *VirtualTrader) recordClosedTrade(pnl float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.TradeStats == nil {
+		t.TradeStats = NewEMAStats(0)
+	}
+	if t.Performance == nil {
+		t.Performance = &PerformanceMetrics{}
+	}
+	t.TradeStats.Observe(pnl)
+	t.ResourceAlloc.RealizedPnLUSD += pnl
+	p := t.Performance
+	p.TotalTrades++
+	p.TotalPnLUSD += pnl
+	switch {
+	case pnl > 0:
+		p.AvgWinUSD = (p.AvgWinUSD*float64(p.WinningTrades) + pnl) / float64(p.WinningTrades+1)
+		p.WinningTrades++
+	case pnl < 0:
+		p.AvgLossUSD = (p.AvgLossUSD*float64(p.LosingTrades) + pnl) / float64(p.LosingTrades+1)
+		p.LosingTrades++
+	}
+	p.WinRate = t.TradeStats.WinRate
+	if t.TradeStats.Count >= minTradesForSharpe {
+		p.SharpeRatio = t.TradeStats.Sharpe()
+	}
+}
+
+// recordPartialClose books the realized PnL of a scale-out. The position is
+// still open, so no trade is counted until it is fully closed.
+func (t *VirtualTrader) recordPartialClose(pnl float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.Performance == nil {
+		t.Performance = &PerformanceMetrics{}
+	}
+	t.ResourceAlloc.RealizedPnLUSD += pnl
+	t.Performance.TotalPnLUSD += pnl
+}
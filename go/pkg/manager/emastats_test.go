@@ -0,0 +1,54 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEMAStatsWinRateConverges(t *testing.T) {
+	s := NewEMAStats(0.1)
+	// Three winners for every loser: long-run win rate 75%.
+	for i := 0; i < 400; i++ {
+		if i%4 == 3 {
+			s.Observe(-10)
+		} else {
+			s.Observe(20)
+		}
+	}
+	assert.InDelta(t, 0.75, s.WinRate, 0.08, "EMA win rate should converge to the realized ratio")
+	assert.InDelta(t, 12.5, s.AvgPnL, 3, "EMA pnl should converge to the mean trade")
+	assert.Greater(t, s.Sharpe(), 0.0, "profitable series should have positive Sharpe")
+
+	// A regime change to all losers should pull the win rate down quickly.
+	for i := 0; i < 50; i++ {
+		s.Observe(-5)
+	}
+	assert.Less(t, s.WinRate, 0.01, "win rate should decay after a losing streak")
+	assert.Equal(t, 450, s.Count)
+}
+
+func TestEMAStatsSeedAndInvalidInput(t *testing.T) {
+	s := NewEMAStats(5) // out of range falls back to default
+	assert.InDelta(t, defaultTradeStatsAlpha, s.Alpha, 1e-12)
+	s.Observe(10)
+	assert.Equal(t, 1.0, s.WinRate, "first observation seeds the win rate")
+	assert.Equal(t, 10.0, s.AvgPnL)
+	assert.Zero(t, s.Sharpe(), "single observation has no dispersion")
+}
+
+func TestRecordClosedTradeUpdatesPerformance(t *testing.T) {
+	vt := &VirtualTrader{ID: "p"}
+	for _, pnl := range []float64{10, -5, 20, -5, 15} {
+		vt.recordClosedTrade(pnl)
+	}
+	p := vt.Performance
+	assert.Equal(t, 5, p.TotalTrades)
+	assert.Equal(t, 3, p.WinningTrades)
+	assert.Equal(t, 2, p.LosingTrades)
+	assert.InDelta(t, 35.0, p.TotalPnLUSD, 1e-9)
+	assert.InDelta(t, 15.0, p.AvgWinUSD, 1e-9)
+	assert.InDelta(t, -5.0, p.AvgLossUSD, 1e-9)
+	assert.InDelta(t, vt.TradeStats.WinRate, p.WinRate, 1e-12, "win rate should come from closed trades")
+	assert.InDelta(t, vt.TradeStats.Sharpe(), p.SharpeRatio, 1e-12, "sharpe should be fed once enough trades close")
+}
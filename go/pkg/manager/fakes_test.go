@@ -0,0 +1,205 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	executorpkg "nof0-api/pkg/executor"
+	"nof0-api/pkg/llm"
+	"nof0-api/pkg/market"
+)
+
+// fakeMarket is an in-memory market.Provider for manager tests.
+type fakeMarket struct {
+	mu     sync.Mutex
+	snaps  map[string]*market.Snapshot
+	assets []market.Asset
+	calls  map[string]int
+	// down symbols always fail to serve a snapshot.
+	down map[string]bool
+	// delay is applied to every Snapshot call; inFlight/maxInFlight track
+	// concurrent calls.
+	delay       time.Duration
+	inFlight    int
+	maxInFlight int
+	// funding is served by GetFundingHistory.
+	funding []market.FundingRecord
+}
+
+func newFakeMarket(snaps ...*market.Snapshot) *fakeMarket {
+	f := &fakeMarket{snaps: make(map[string]*market.Snapshot), calls: make(map[string]int), down: make(map[string]bool)}
+	for _, s := range snaps {
+		f.snaps[s.Symbol] = s
+		f.assets = append(f.assets, market.Asset{Symbol: s.Symbol, IsActive: true})
+	}
+	return f
+}
+
+// withMaxLeverage publishes maxLeverage in every asset's metadata.
+func (f *fakeMarket) withMaxLeverage(maxLeverage float64) *fakeMarket {
+	for i := range f.assets {
+		if f.assets[i].RawMetadata == nil {
+			f.assets[i].RawMetadata = make(map[string]any)
+		}
+		f.assets[i].RawMetadata["maxLeverage"] = maxLeverage
+	}
+	return f
+}
+
+func (f *fakeMarket) Snapshot(_ context.Context, symbol string) (*market.Snapshot, error) {
+	f.mu.Lock()
+	f.inFlight++
+	if f.inFlight > f.maxInFlight {
+		f.maxInFlight = f.inFlight
+	}
+	delay := f.delay
+	f.mu.Unlock()
+	time.Sleep(delay)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inFlight--
+	f.calls[symbol]++
+	if f.down[symbol] {
+		return nil, fmt.Errorf("fake market: %s snapshot unavailable", symbol)
+	}
+	s, ok := f.snaps[symbol]
+	if !ok {
+		return nil, fmt.Errorf("fake market: unknown symbol %s", symbol)
+	}
+	cp := *s
+	return &cp, nil
+}
+
+func (f *fakeMarket) ListAssets(context.Context) ([]market.Asset, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]market.Asset, len(f.assets))
+	copy(out, f.assets)
+	return out, nil
+}
+
+func (f *fakeMarket) GetFundingHistory(_ context.Context, symbol string, start, end time.Time) ([]market.FundingRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []market.FundingRecord
+	for _, r := range f.funding {
+		if strings.EqualFold(r.Symbol, symbol) && !r.Time.Before(start) && !r.Time.After(end) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeMarket) snapshotCalls(symbol string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[symbol]
+}
+
+func testSnapshot(symbol string, price, change1h float64) *market.Snapshot {
+	return &market.Snapshot{
+		Symbol: symbol,
+		Price:  market.PriceInfo{Last: price},
+		Change: market.ChangeInfo{OneHour: change1h},
+	}
+}
+
+// fakePersistence records manager persistence hooks in memory.
+type fakePersistence struct {
+	noopPersistenceService
+	mu          sync.Mutex
+	events      []PositionEvent
+	cycles      []DecisionCycleRecord
+	snapshots   []AccountSyncSnapshot
+	realizedPnL float64
+	open        map[string][]CachedPosition
+	leverage    []LeverageChange
+	funding     []FundingAccrual
+}
+
+func (f *fakePersistence) RecordPositionEvent(_ context.Context, event PositionEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakePersistence) RecordFundingAccrual(_ context.Context, accrual FundingAccrual) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.funding = append(f.funding, accrual)
+	return nil
+}
+
+func (f *fakePersistence) RecordDecisionCycle(_ context.Context, record DecisionCycleRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cycles = append(f.cycles, record)
+	return nil
+}
+
+func (f *fakePersistence) RecordAccountSnapshot(_ context.Context, snapshot AccountSyncSnapshot) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.snapshots = append(f.snapshots, snapshot)
+	return nil
+}
+
+func (f *fakePersistence) RealizedGrossPnL(context.Context, string, time.Time, time.Time) (float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.realizedPnL, nil
+}
+
+func (f *fakePersistence) OpenPositions(_ context.Context, traderID string) ([]CachedPosition, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]CachedPosition(nil), f.open[traderID]...), nil
+}
+
+func (f *fakePersistence) RecordLeverageChange(_ context.Context, change LeverageChange) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.leverage = append(f.leverage, change)
+	return nil
+}
+
+// fakeExecutor returns canned decisions and records the contexts it was given.
+type fakeExecutor struct {
+	mu        sync.Mutex
+	decisions []executorpkg.Decision
+	err       error
+	calls     int
+	contexts  []*executorpkg.Context
+	cfg       executorpkg.Config
+	delay     time.Duration
+	usage     llm.Usage
+	prompt    string
+	cot       string
+}
+
+func (f *fakeExecutor) GetFullDecision(input *executorpkg.Context) (*executorpkg.FullDecision, error) {
+	if f.delay > 0 {
+		time.Sleep(f.delay)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	f.contexts = append(f.contexts, input)
+	out := make([]executorpkg.Decision, len(f.decisions))
+	copy(out, f.decisions)
+	return &executorpkg.FullDecision{UserPrompt: f.prompt, CoTTrace: f.cot, Decisions: out, Timestamp: time.Now(), Usage: f.usage}, f.err
+}
+
+func (f *fakeExecutor) UpdatePerformance(*executorpkg.PerformanceView) {}
+
+func (f *fakeExecutor) GetConfig() *executorpkg.Config { return &f.cfg }
+
+func (f *fakeExecutor) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
@@ -0,0 +1,146 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"nof0-api/pkg/exchange"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// StopAndFlatten stops the trading loop and waits for it and any in-flight
+// decision cycles to finish, then cancels resting orders and closes every
+// open position held by active traders. Failures are collected rather than
+// aborting the sweep; traders not reached before ctx expires are reported in
+// the joined error. Nothing is flattened when ctx expires before the cycles
+// drain, since a cycle still running could open a new position behind it.
+func (m *Manager) StopAndFlatten(ctx context.Context) error {
+	m.Stop()
+	if err := m.waitIdle(ctx); err != nil {
+		return fmt.Errorf("manager: stop and flatten: waiting for decision cycles: %w", err)
+	}
+	var errs []error
+	for _, t := range m.GetActiveTraders() {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("manager: flatten trader %s: %w", t.ID, err))
+			continue
+		}
+		errs = append(errs, m.flattenTrader(ctx, t)...)
+	}
+	return errors.Join(errs...)
+}
+
+// waitIdle blocks until every RunTradingLoop call has returned and no
+// decision cycle is in flight, or ctx expires.
+func (m *Manager) waitIdle(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		m.loopWG.Wait()
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flattenTrader cancels resting orders and closes open positions for t,
+// returning one error per failed step.
+func (m *Manager) flattenTrader(ctx context.Context, t *VirtualTrader) []error {
+	var errs []error
+	positions, err := t.ExchangeProvider.GetPositions(ctx)
+	if err != nil {
+		return []error{fmt.Errorf("manager: flatten trader %s: get positions: %w", t.ID, err)}
+	}
+	symbols := make(map[string]struct{})
+	open := make(map[string]exchange.Position)
+	for _, p := range positions {
+		if parseFloat(p.Szi) == 0 {
+			continue
+		}
+		symbols[p.Coin] = struct{}{}
+		open[p.Coin] = p
+	}
+	if orders, err := t.ExchangeProvider.GetOpenOrders(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("manager: flatten trader %s: get open orders: %w", t.ID, err))
+	} else {
+		for _, o := range orders {
+			if o.Order.Coin != "" {
+				symbols[o.Order.Coin] = struct{}{}
+			}
+		}
+	}
+	ordered := make([]string, 0, len(symbols))
+	for sym := range symbols {
+		ordered = append(ordered, sym)
+	}
+	sort.Strings(ordered)
+
+	canceller, canCancel := t.ExchangeProvider.(interface {
+		CancelAllBySymbol(context.Context, string) error
+	})
+	for _, sym := range ordered {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("manager: flatten trader %s symbol=%s: %w", t.ID, sym, err))
+			continue
+		}
+		if canCancel {
+			if err := canceller.CancelAllBySymbol(ctx, sym); err != nil {
+				errs = append(errs, fmt.Errorf("manager: flatten trader %s: cancel orders %s: %w", t.ID, sym, err))
+			}
+		}
+		pos, ok := open[sym]
+		if !ok {
+			continue
+		}
+		m.accrueFunding(ctx, t, []exchange.Position{pos})
+		resp, err := t.ExchangeProvider.ClosePosition(ctx, sym)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("manager: flatten trader %s: close %s: %w", t.ID, sym, err))
+			continue
+		}
+		m.recordFlattenClose(t, pos, resp, "stop_and_flatten")
+	}
+	return errs
+}
+
+// recordFlattenClose books a flatten close the same way ExecuteDecision books
+// a decision-driven close; reason is stored as the decision reasoning.
+func (m *Manager) recordFlattenClose(t *VirtualTrader, pos exchange.Position, resp *exchange.OrderResponse, reason string) {
+	action := "close_long"
+	if strings.HasPrefix(strings.TrimSpace(pos.Szi), "-") {
+		action = "close_short"
+	}
+	fillPrice, fillQty, ok := parseOrderFill(resp)
+	if !ok {
+		// Fall back to the mark implied by the position value.
+		if szi := math.Abs(parseFloat(pos.Szi)); szi > 0 {
+			fillPrice = parseFloat(pos.PositionValue) / szi
+			fillQty = szi
+		}
+	}
+	funding := t.takeFunding(pos.Coin)
+	pnl := closePnL(&pos, fillPrice) - funding
+	t.recordClosedTrade(pnl)
+	logx.Infof("manager: trader %s flattened position symbol=%s action=%s pnl=%.2f", t.ID, pos.Coin, action, pnl)
+	m.recordPositionEvent(PositionEvent{
+		TraderID:         t.ID,
+		Trader:           t,
+		Decision:         executorpkg.Decision{Symbol: pos.Coin, Action: action, Reasoning: reason},
+		Event:            PositionEventClose,
+		ExchangeResponse: resp,
+		FillPrice:        fillPrice,
+		FillSize:         fillQty,
+		FundingUSD:       funding,
+		OccurredAt:       m.now(),
+	})
+}
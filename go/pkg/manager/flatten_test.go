@@ -0,0 +1,158 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// flattenExchange wraps the sim provider, records cancelled symbols and can
+// fail closes for a given coin.
+type flattenExchange struct {
+	*sim.Provider
+	cancelled []string
+	failClose string
+}
+
+func (f *flattenExchange) CancelAllBySymbol(ctx context.Context, coin string) error {
+	f.cancelled = append(f.cancelled, coin)
+	return f.Provider.CancelAllBySymbol(ctx, coin)
+}
+
+func (f *flattenExchange) ClosePosition(ctx context.Context, coin string) (*exchange.OrderResponse, error) {
+	if coin == f.failClose {
+		return nil, errors.New("exchange unavailable")
+	}
+	return f.Provider.ClosePosition(ctx, coin)
+}
+
+func newFlattenTrader(t *testing.T, m *Manager, id string, ex *flattenExchange, coins ...string) *VirtualTrader {
+	t.Helper()
+	ctx := context.Background()
+	for _, coin := range coins {
+		require.NoError(t, ex.SetMarkPrice(ctx, coin, 100))
+		_, err := ex.Provider.IOCMarket(ctx, coin, coin != "SOL", 1, 0.001, false)
+		require.NoError(t, err)
+	}
+	vt := &VirtualTrader{
+		ID:               id,
+		ExchangeProvider: ex,
+		MarketProvider:   newFakeMarket(),
+		Cooldown:         make(map[string]time.Time),
+	}
+	require.NoError(t, vt.Start())
+	m.traders[id] = vt
+	return vt
+}
+
+func TestStopAndFlattenClosesActiveTraders(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(nil, nil, nil, nil, nil)
+	persist := &fakePersistence{}
+	m.persistence = persist
+
+	good := &flattenExchange{Provider: sim.New()}
+	newFlattenTrader(t, m, "good", good, "ETH", "SOL")
+	bad := &flattenExchange{Provider: sim.New(), failClose: "BTC"}
+	newFlattenTrader(t, m, "bad", bad, "BTC")
+	idle := &flattenExchange{Provider: sim.New()}
+	paused := newFlattenTrader(t, m, "idle", idle, "DOGE")
+	require.NoError(t, paused.Pause())
+
+	err := m.StopAndFlatten(ctx)
+	require.Error(t, err, "the failed close should be reported")
+	assert.Contains(t, err.Error(), "close BTC")
+	assert.Contains(t, err.Error(), "exchange unavailable")
+
+	select {
+	case <-m.stopChan:
+	default:
+		t.Fatal("StopAndFlatten should stop the trading loop")
+	}
+
+	assert.Equal(t, []string{"ETH", "SOL"}, good.cancelled, "resting orders should be cancelled per symbol")
+	positions, err := good.GetPositions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, positions, "a failure on another trader should not stop this one flattening")
+	positions, err = bad.GetPositions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, positions, 1, "the failed close should leave the position open")
+	positions, err = idle.GetPositions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, positions, 1, "inactive traders are left untouched")
+
+	closes := map[string]string{}
+	for _, ev := range persist.events {
+		if ev.Event == PositionEventClose {
+			closes[ev.Decision.Symbol] = ev.Decision.Action
+		}
+	}
+	assert.Equal(t, map[string]string{"ETH": "close_long", "SOL": "close_short"}, closes)
+}
+
+func TestStopAndFlattenRespectsDeadline(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	ex := &flattenExchange{Provider: sim.New()}
+	newFlattenTrader(t, m, "late", ex, "ETH")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := m.StopAndFlatten(ctx)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	positions, perr := ex.GetPositions(context.Background())
+	require.NoError(t, perr)
+	assert.Len(t, positions, 1, "nothing should be closed after the deadline")
+}
+
+func TestStopAndFlattenWaitsForInFlightCycles(t *testing.T) {
+	m := newCycleLimitManager(ManagerConfig{DecisionWorkers: 1})
+	ex := sim.New()
+	require.NoError(t, ex.SetMarkPrice(context.Background(), "ETH", 3000))
+	exec := &fakeExecutor{
+		decisions: []executorpkg.Decision{{Symbol: "ETH", Action: "open_long", PositionSizeUSD: 300, Leverage: 2}},
+		delay:     100 * time.Millisecond,
+	}
+	vt := newCycleTestTrader(m, "slow", newFakeMarket(testSnapshot("ETH", 3000, 0.01)), exec)
+	vt.ExchangeProvider = ex
+	vt.DecisionInterval = time.Hour
+
+	loopCtx, cancelLoop := context.WithCancel(context.Background())
+	defer cancelLoop()
+	loopDone := make(chan error, 1)
+	go func() { loopDone <- m.RunTradingLoop(loopCtx) }()
+	require.Eventually(t, func() bool { return m.cyclesInFlight.Load() == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, m.StopAndFlatten(ctx))
+	require.NoError(t, <-loopDone)
+
+	assert.Equal(t, 1, exec.calls, "the in-flight cycle should have finished")
+	positions, err := ex.GetPositions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, positions, "the position opened by the in-flight cycle should be flattened")
+}
+
+func TestStopAndFlattenGivesUpWhenCyclesDoNotDrain(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	ex := &flattenExchange{Provider: sim.New()}
+	newFlattenTrader(t, m, "busy", ex, "ETH")
+	m.wg.Add(1)
+	defer m.wg.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := m.StopAndFlatten(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "waiting for decision cycles")
+	assert.Empty(t, ex.cancelled, "nothing is flattened while a cycle may still open positions")
+}
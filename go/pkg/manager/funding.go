@@ -0,0 +1,154 @@
+package manager
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/market"
+)
+
+// PositionFunding is the funding accrued on one open position.
+type PositionFunding struct {
+	AccruedUSD float64   // cumulative funding paid (positive) or received (negative)
+	Through    time.Time // settlement time of the last accrued interval
+}
+
+func fundingKey(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
+
+// startFunding begins funding tracking for a newly opened position; adding to
+// an existing position keeps its accrual.
+func (t *VirtualTrader) startFunding(symbol string, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.Funding == nil {
+		t.Funding = make(map[string]*PositionFunding)
+	}
+	key := fundingKey(symbol)
+	if _, ok := t.Funding[key]; !ok {
+		t.Funding[key] = &PositionFunding{Through: at}
+	}
+}
+
+// takeFunding returns and clears the funding accrued on symbol.
+func (t *VirtualTrader) takeFunding(symbol string) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := fundingKey(symbol)
+	state := t.Funding[key]
+	delete(t.Funding, key)
+	if state == nil {
+		return 0
+	}
+	return state.AccruedUSD
+}
+
+// pruneFunding drops funding state for positions no longer held.
+func (t *VirtualTrader) pruneFunding(positions []exchange.Position) {
+	held := make(map[string]bool, len(positions))
+	for _, p := range positions {
+		if parseFloat(p.Szi) != 0 {
+			held[fundingKey(p.Coin)] = true
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key := range t.Funding {
+		if !held[key] {
+			delete(t.Funding, key)
+		}
+	}
+}
+
+// accrueFunding settles funding intervals elapsed since the last accrual onto
+// each open position. Longs pay positive rates and shorts receive them. The
+// notional is the position value at accrual time. Positions seen for the first
+// time start accruing from now. Market providers without
+// market.FundingHistoryProvider are a no-op.
+func (m *Manager) accrueFunding(ctx context.Context, t *VirtualTrader, positions []exchange.Position) {
+	history, ok := t.MarketProvider.(market.FundingHistoryProvider)
+	if !ok {
+		return
+	}
+	now := m.now()
+	for _, pos := range positions {
+		szi := parseFloat(pos.Szi)
+		if szi == 0 {
+			continue
+		}
+		key := fundingKey(pos.Coin)
+		t.mu.Lock()
+		if t.Funding == nil {
+			t.Funding = make(map[string]*PositionFunding)
+		}
+		state, tracked := t.Funding[key]
+		if !tracked {
+			t.Funding[key] = &PositionFunding{Through: now}
+		}
+		var since time.Time
+		if tracked {
+			since = state.Through
+		}
+		t.mu.Unlock()
+		if !tracked || !now.After(since) {
+			continue
+		}
+
+		records, err := history.GetFundingHistory(ctx, pos.Coin, since, now)
+		if err != nil {
+			logx.WithContext(ctx).Errorf("manager: trader %s funding history symbol=%s err=%v", t.ID, pos.Coin, err)
+			continue
+		}
+		notional := math.Abs(parseFloat(pos.PositionValue))
+		if notional == 0 {
+			notional = math.Abs(szi) * parsePtrFloat(pos.EntryPx)
+		}
+		direction := 1.0
+		if szi < 0 {
+			direction = -1.0
+		}
+		var amount float64
+		through := since
+		for _, r := range records {
+			if !r.Time.After(through) || r.Time.After(now) {
+				continue
+			}
+			amount += direction * r.Rate * notional
+			through = r.Time
+		}
+		if through.Equal(since) {
+			continue
+		}
+
+		t.mu.Lock()
+		state.AccruedUSD += amount
+		state.Through = through
+		total := state.AccruedUSD
+		t.mu.Unlock()
+		m.recordFundingAccrual(FundingAccrual{
+			TraderID:  t.ID,
+			Symbol:    key,
+			AmountUSD: amount,
+			TotalUSD:  total,
+			Through:   through,
+		})
+	}
+}
+
+func (m *Manager) recordFundingAccrual(accrual FundingAccrual) {
+	recorder, ok := m.persistence.(FundingRecorder)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := recorder.RecordFundingAccrual(ctx, accrual); err != nil {
+		logx.WithContext(ctx).Errorf("manager: trader %s record funding accrual symbol=%s err=%v", accrual.TraderID, accrual.Symbol, err)
+	}
+}
@@ -0,0 +1,94 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+	"nof0-api/pkg/market"
+)
+
+func TestFundingAccruesAcrossIntervalsIntoClosePnL(t *testing.T) {
+	ex := sim.New()
+	require.NoError(t, ex.SetMarkPrice(context.Background(), "BTC", 60000))
+	opened := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	mk := newFakeMarket(testSnapshot("BTC", 60000, 0))
+	mk.funding = []market.FundingRecord{
+		{Symbol: "BTC", Rate: 0.0001, Time: opened.Add(time.Hour)},
+		{Symbol: "BTC", Rate: 0.0002, Time: opened.Add(2 * time.Hour)},
+		{Symbol: "BTC", Rate: 0.0005, Time: opened.Add(5 * time.Hour)}, // settles after the close
+	}
+	persist := &fakePersistence{}
+	m := NewManager(nil, nil, nil, nil, persist)
+	m.SetClock(fixedClock{opened})
+	vt := &VirtualTrader{
+		ID:               "funding",
+		ExchangeProvider: ex,
+		MarketProvider:   mk,
+		OrderStyle:       OrderStyleMarketIOC,
+		RiskParams:       RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 1000, MajorCoinLeverage: 5},
+		Cooldown:         make(map[string]time.Time),
+	}
+	m.traders[vt.ID] = vt
+
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "BTC", Action: "open_long", PositionSizeUSD: 1000}))
+	pos := findPosition(context.Background(), vt, "BTC")
+	require.NotNil(t, pos)
+	notional := parseFloat(pos.PositionValue)
+	funding := (0.0001 + 0.0002) * notional
+
+	// Two funding intervals settle while the position is held.
+	m.SetClock(fixedClock{opened.Add(3 * time.Hour)})
+	require.NoError(t, m.SyncTraderPositions(vt.ID))
+	require.Len(t, persist.funding, 1, "settled intervals should be persisted onto the open position")
+	accrual := persist.funding[0]
+	assert.Equal(t, "BTC", accrual.Symbol)
+	assert.InDelta(t, funding, accrual.AmountUSD, 1e-9, "long pays rate * notional for each interval")
+	assert.InDelta(t, funding, accrual.TotalUSD, 1e-9)
+	assert.Equal(t, opened.Add(2*time.Hour), accrual.Through)
+
+	// A repeated sync must not double count the same intervals.
+	require.NoError(t, m.SyncTraderPositions(vt.ID))
+	assert.Len(t, persist.funding, 1)
+
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "BTC", Action: "close_long"}))
+	require.Len(t, persist.events, 2)
+	closeEvent := persist.events[1]
+	assert.Equal(t, PositionEventClose, closeEvent.Event)
+	assert.InDelta(t, funding, closeEvent.FundingUSD, 1e-9, "close should carry the accrued funding")
+	require.NotNil(t, vt.Performance)
+	assert.InDelta(t, closePnL(pos, closeEvent.FillPrice)-funding, vt.Performance.TotalPnLUSD, 1e-9, "realized PnL should be net of funding")
+	assert.Empty(t, vt.Funding, "funding state should be cleared on close")
+}
+
+func TestFundingShortReceivesPositiveRate(t *testing.T) {
+	ex := sim.New()
+	require.NoError(t, ex.SetMarkPrice(context.Background(), "ETH", 3000))
+	opened := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	mk := newFakeMarket(testSnapshot("ETH", 3000, 0))
+	mk.funding = []market.FundingRecord{{Symbol: "ETH", Rate: 0.001, Time: opened.Add(time.Hour)}}
+	m := NewManager(nil, nil, nil, nil, &fakePersistence{})
+	m.SetClock(fixedClock{opened})
+	vt := &VirtualTrader{
+		ID:               "short",
+		ExchangeProvider: ex,
+		MarketProvider:   mk,
+		OrderStyle:       OrderStyleMarketIOC,
+		RiskParams:       RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 1000, MajorCoinLeverage: 5},
+		Cooldown:         make(map[string]time.Time),
+	}
+	m.traders[vt.ID] = vt
+
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "ETH", Action: "open_short", PositionSizeUSD: 300}))
+	m.SetClock(fixedClock{opened.Add(2 * time.Hour)})
+	require.NoError(t, m.SyncTraderPositions(vt.ID))
+	pos := findPosition(context.Background(), vt, "ETH")
+	require.NotNil(t, pos)
+	require.Contains(t, vt.Funding, "ETH")
+	assert.InDelta(t, -0.001*parseFloat(pos.PositionValue), vt.Funding["ETH"].AccruedUSD, 1e-9, "short should receive positive funding")
+}
@@ -0,0 +1,87 @@
+package manager
+
+import (
+	"nof0-api/pkg/journal"
+)
+
+// toggleOn resolves a guard toggle, which defaults to enabled when unset.
+func toggleOn(toggle *bool) bool {
+	return toggle == nil || *toggle
+}
+
+// effectiveGuards resolves the guards shaping t's next cycle from its risk
+// parameters, exec guard thresholds and toggles, and manager-level overrides.
+// A guard is reported enabled only when its toggle is on and it has a
+// threshold to enforce.
+func (m *Manager) effectiveGuards(t *VirtualTrader) []journal.GuardSetting {
+	g := t.ExecGuards
+	r := t.RiskParams
+	sparseThreshold := g.MinSnapshotCoveragePct
+	if sparseThreshold <= 0 {
+		sparseThreshold = defaultMinSnapshotCoveragePct
+	}
+	latencyBudget := decisionLatencyBudget(t)
+	valueBand := g.BTCETHMinEquityMultiple > 0 || g.BTCETHMaxEquityMultiple > 0 || g.AltMinEquityMultiple > 0 || g.AltMaxEquityMultiple > 0
+	return []journal.GuardSetting{
+		{Name: GuardMaxPositions, Enabled: r.MaxPositions > 0, Params: map[string]any{"max_positions": r.MaxPositions}},
+		{Name: GuardReservedSlots, Enabled: g.ReservedPositionSlots > 0, Params: map[string]any{
+			"reserved_position_slots":      g.ReservedPositionSlots,
+			"reserved_slot_min_confidence": g.ReservedSlotMinConfidence,
+		}},
+		{Name: GuardMaxNewPerCycle, Enabled: g.MaxNewPositionsPerCycle > 0, Params: map[string]any{"max_new_positions_per_cycle": g.MaxNewPositionsPerCycle}},
+		{Name: GuardMaxPositionSize, Enabled: r.MaxPositionSizeUSD > 0, Params: map[string]any{"max_position_size_usd": r.MaxPositionSizeUSD}},
+		{Name: GuardMinNotional, Enabled: true, Params: map[string]any{"min_order_notional_usd": minOrderNotionalUSD(t)}},
+		{Name: GuardKellyNoEdge, Enabled: t.SizingMode == SizingModeKelly, Params: map[string]any{"kelly_multiplier": t.KellyMultiplier}},
+		{Name: GuardCooldown, Enabled: toggleOn(g.EnableCooldownGuard) && g.CooldownAfterClose > 0, Params: map[string]any{
+			"cooldown_after_close": g.CooldownAfterClose.String(),
+			"cooldown_after_loss":  g.CooldownAfterLoss.String(),
+		}},
+		{Name: GuardDuplicatePos, Enabled: duplicatePositionGuardOn(g), Params: map[string]any{"duplicate_position_guard": g.DuplicatePositionGuard}},
+		{Name: GuardMarketSlippage, Enabled: t.OrderStyle == OrderStyleMarketIOC, Params: map[string]any{"market_ioc_slippage_bps": marketIOCSlippageBps(t)}},
+		{Name: "liquidity", Enabled: toggleOn(g.EnableLiquidityGuard) && g.LiquidityThresholdUSD > 0, Params: map[string]any{"liquidity_threshold_usd": g.LiquidityThresholdUSD}},
+		{Name: "funding", Enabled: fundingGuardRate(g) > 0, Params: map[string]any{"max_abs_funding_rate": g.MaxAbsFundingRate}},
+		{Name: "margin_usage", Enabled: toggleOn(g.EnableMarginUsageGuard) && r.MaxMarginUsagePct > 0, Params: map[string]any{"max_margin_usage_pct": r.MaxMarginUsagePct}},
+		{Name: "value_band", Enabled: toggleOn(g.EnableValueBandGuard) && valueBand, Params: map[string]any{
+			"btceth_min_equity_multiple": g.BTCETHMinEquityMultiple,
+			"btceth_max_equity_multiple": g.BTCETHMaxEquityMultiple,
+			"alt_min_equity_multiple":    g.AltMinEquityMultiple,
+			"alt_max_equity_multiple":    g.AltMaxEquityMultiple,
+		}},
+		{Name: GuardSparseData, Enabled: toggleOn(g.EnableSparseDataGuard), Params: map[string]any{"min_snapshot_coverage_pct": sparseThreshold}},
+		{Name: GuardLatencyBudget, Enabled: latencyBudget > 0, Params: map[string]any{
+			"decision_latency_budget_pct": g.DecisionLatencyBudgetPct,
+			"budget":                      latencyBudget.String(),
+		}},
+		{Name: GuardSharpePause, Enabled: g.SharpePauseThreshold != 0 && g.PauseDurationOnBreach > 0, Params: map[string]any{
+			"sharpe_pause_threshold":   g.SharpePauseThreshold,
+			"pause_duration_on_breach": g.PauseDurationOnBreach.String(),
+			"sharpe_lookback":          g.SharpeLookback.String(),
+			"sharpe_window":            g.SharpeWindow,
+			"sharpe_min_trades":        g.SharpeMinTrades,
+		}},
+		{Name: GuardMaxDrawdown, Enabled: g.MaxDrawdownPct > 0, Params: map[string]any{
+			"max_drawdown_pct":         g.MaxDrawdownPct,
+			"pause_duration_on_breach": g.PauseDurationOnBreach.String(),
+			"exit_on_breach":           g.ExitOnBreach,
+		}},
+		{Name: GuardDailyLoss, Enabled: g.MaxDailyLossUSD > 0 || g.MaxDailyLossPct > 0, Params: map[string]any{
+			"max_daily_loss_usd": g.MaxDailyLossUSD,
+			"max_daily_loss_pct": g.MaxDailyLossPct,
+		}},
+		{Name: GuardMaxPositionAge, Enabled: g.MaxPositionAge > 0, Params: map[string]any{"max_position_age": g.MaxPositionAge.String()}},
+		{Name: GuardOverAllocation, Enabled: m.rebalanceInterval() > 0, Params: map[string]any{
+			"rebalance_interval": m.rebalanceInterval().String(),
+			"over_allocated":     t.OverAllocated,
+		}},
+		{Name: GuardSLTPRequired, Enabled: r.StopLossEnabled || r.TakeProfitEnabled, Params: map[string]any{
+			"stop_loss_enabled":   r.StopLossEnabled,
+			"take_profit_enabled": r.TakeProfitEnabled,
+		}},
+		{Name: GuardTokenBudget, Enabled: t.TokenBudget.Enabled(), Params: map[string]any{
+			"max_tokens":   t.TokenBudget.MaxTokens,
+			"max_cost_usd": t.TokenBudget.MaxCostUSD,
+			"window":       t.TokenBudget.window().String(),
+		}},
+		{Name: GuardWindDown, Enabled: m.WindDown()},
+	}
+}
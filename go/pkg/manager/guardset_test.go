@@ -0,0 +1,54 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/journal"
+)
+
+func TestRunTraderCycleRecordsEffectiveGuards(t *testing.T) {
+	persist := &fakePersistence{}
+	m := NewManager(nil, nil, nil, nil, persist)
+	vt := newCycleTestTrader(m, "guards", newFakeMarket(testSnapshot("BTC", 60000, 0.01)), &fakeExecutor{})
+	off := false
+	vt.DecisionInterval = 10 * time.Minute
+	vt.ExecGuards = ExecGuards{
+		MaxNewPositionsPerCycle:  1,
+		LiquidityThresholdUSD:    5e6,
+		CooldownAfterClose:       15 * time.Minute,
+		EnableCooldownGuard:      &off,
+		EnableSparseDataGuard:    &off,
+		DecisionLatencyBudgetPct: 50,
+	}
+	vt.RiskParams.MaxMarginUsagePct = 60
+	vt.Journal = journal.NewWriter(t.TempDir())
+	vt.JournalEnabled = true
+	m.SetWindDown(true)
+
+	m.runTraderCycle(context.Background(), vt)
+
+	require.Len(t, persist.cycles, 1)
+	guards := make(map[string]journal.GuardSetting)
+	for _, g := range persist.cycles[0].Cycle.Guards {
+		guards[g.Name] = g
+	}
+	assert.True(t, guards[GuardMaxPositions].Enabled)
+	assert.Equal(t, 3, guards[GuardMaxPositions].Params["max_positions"])
+	assert.True(t, guards[GuardMaxNewPerCycle].Enabled)
+	assert.False(t, guards[GuardCooldown].Enabled, "toggle should disable the cooldown guard")
+	assert.Equal(t, "15m0s", guards[GuardCooldown].Params["cooldown_after_close"], "disabled guards still report thresholds")
+	assert.True(t, guards["liquidity"].Enabled, "unset toggle should default to enabled")
+	assert.Equal(t, 5e6, guards["liquidity"].Params["liquidity_threshold_usd"])
+	assert.True(t, guards["margin_usage"].Enabled)
+	assert.False(t, guards["value_band"].Enabled, "guard without thresholds should be inactive")
+	assert.False(t, guards[GuardSparseData].Enabled)
+	assert.True(t, guards[GuardLatencyBudget].Enabled)
+	assert.Equal(t, "5m0s", guards[GuardLatencyBudget].Params["budget"])
+	assert.False(t, guards[GuardSharpePause].Enabled)
+	assert.True(t, guards[GuardWindDown].Enabled, "manager override should be reflected")
+}
@@ -0,0 +1,20 @@
+package manager
+
+import (
+	"fmt"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// haltOnDepletedEquity halts t when its account equity is zero or negative
+// (e.g. after liquidation), since margin and PnL percentages derived from it
+// would be meaningless. It reports whether the trader is halted.
+func (m *Manager) haltOnDepletedEquity(t *VirtualTrader, equity float64) bool {
+	if equity > 0 {
+		return false
+	}
+	reason := fmt.Sprintf("account equity %.2f usd is depleted", equity)
+	t.Halt(reason)
+	logx.Severef("manager: trader %s halted: %s; restart the trader once the account is funded", t.ID, reason)
+	return true
+}
@@ -0,0 +1,56 @@
+package manager
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/exchange/sim"
+)
+
+// depletedAccountExchange reports a fixed account value and margin usage.
+type depletedAccountExchange struct {
+	*sim.Provider
+	accountValue string
+	marginUsed   string
+}
+
+func (d *depletedAccountExchange) GetAccountState(context.Context) (*exchange.AccountState, error) {
+	return &exchange.AccountState{MarginSummary: exchange.MarginSummary{AccountValue: d.accountValue, TotalMarginUsed: d.marginUsed}}, nil
+}
+
+func TestRunTraderCycleHaltsOnDepletedEquity(t *testing.T) {
+	for _, value := range []string{"0", "-42.5"} {
+		exec := &fakeExecutor{}
+		m := NewManager(nil, nil, nil, nil, nil)
+		vt := newCycleTestTrader(m, "liq", newFakeMarket(testSnapshot("BTC", 60000, 0.01)), exec)
+		vt.ExchangeProvider = &depletedAccountExchange{Provider: sim.New(), accountValue: value, marginUsed: "10"}
+
+		ectx, accountOK := m.buildExecutorContext(vt, snapshotCache{})
+		require.True(t, accountOK)
+		assert.False(t, math.IsNaN(ectx.Account.MarginUsedPct) || math.IsInf(ectx.Account.MarginUsedPct, 0), "equity %s: margin pct must stay finite", value)
+		assert.Zero(t, ectx.Account.MarginUsedPct, "equity %s: margin pct is undefined without equity", value)
+
+		m.runTraderCycle(context.Background(), vt)
+		assert.Zero(t, exec.callCount(), "equity %s: executor should not be consulted", value)
+		assert.Equal(t, TraderStateHalted, vt.State, "equity %s: trader should be halted", value)
+		assert.NotEmpty(t, vt.HaltReason)
+		assert.False(t, vt.ShouldMakeDecision(), "equity %s: halted trader should leave scheduling", value)
+
+		require.NoError(t, vt.Start())
+		assert.Empty(t, vt.HaltReason, "restart should clear the halt reason")
+	}
+}
+
+func TestSyncTraderPositionsHaltsOnDepletedEquity(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, &fakePersistence{})
+	vt := newCycleTestTrader(m, "liq", newFakeMarket(), &fakeExecutor{})
+	vt.ExchangeProvider = &depletedAccountExchange{Provider: sim.New(), accountValue: "0", marginUsed: "0"}
+
+	require.NoError(t, m.SyncTraderPositions(vt.ID))
+	assert.Equal(t, TraderStateHalted, vt.State)
+}
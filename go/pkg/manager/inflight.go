@@ -0,0 +1,55 @@
+package manager
+
+import (
+	"time"
+
+	"nof0-api/pkg/exchange"
+)
+
+// pendingOpenTTL bounds how long a submitted open blocks its symbol when the
+// position never shows up, such as an IOC that expired unfilled.
+const pendingOpenTTL = 2 * time.Minute
+
+// claimOpen marks symbol as having an open in flight. It returns false when
+// an earlier open is still pending, in which case the caller should defer.
+func (t *VirtualTrader) claimOpen(symbol string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pendingOpens == nil {
+		t.pendingOpens = make(map[string]time.Time)
+	}
+	key := fundingKey(symbol)
+	if at, ok := t.pendingOpens[key]; ok && now.Sub(at) < pendingOpenTTL {
+		return false
+	}
+	t.pendingOpens[key] = now
+	return true
+}
+
+// releaseOpen clears the in-flight marker of symbol, as when its open failed
+// before reaching the exchange.
+func (t *VirtualTrader) releaseOpen(symbol string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pendingOpens, fundingKey(symbol))
+}
+
+// settleOpens clears in-flight markers whose position is now held or whose
+// TTL has lapsed.
+func (t *VirtualTrader) settleOpens(positions []exchange.Position, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.pendingOpens) == 0 {
+		return
+	}
+	for _, p := range positions {
+		if parseFloat(p.Szi) != 0 {
+			delete(t.pendingOpens, fundingKey(p.Coin))
+		}
+	}
+	for key, at := range t.pendingOpens {
+		if now.Sub(at) >= pendingOpenTTL {
+			delete(t.pendingOpens, key)
+		}
+	}
+}
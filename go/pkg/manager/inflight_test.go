@@ -0,0 +1,120 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// slowExchange delays market orders so that two executions overlap while the
+// first is still at the exchange.
+type slowExchange struct {
+	*sim.Provider
+	delay  time.Duration
+	failed atomic.Bool
+	orders atomic.Int32
+}
+
+func (s *slowExchange) IOCMarket(ctx context.Context, coin string, isBuy bool, qty, slippage float64, reduceOnly bool) (*exchange.OrderResponse, error) {
+	s.orders.Add(1)
+	time.Sleep(s.delay)
+	if s.failed.Load() {
+		return nil, errors.New("exchange unavailable")
+	}
+	return s.Provider.IOCMarket(ctx, coin, isBuy, qty, slippage, reduceOnly)
+}
+
+func newInFlightTrader(m *Manager, ex *slowExchange) *VirtualTrader {
+	vt := newCycleTestTrader(m, "overlap", newFakeMarket(testSnapshot("BTC", 60000, 0)), &fakeExecutor{})
+	vt.ExchangeProvider = ex
+	vt.OrderStyle = OrderStyleMarketIOC
+	return vt
+}
+
+func inFlightOpen() *executorpkg.Decision {
+	return &executorpkg.Decision{Symbol: "BTC", Action: "open_long", Leverage: 2, PositionSizeUSD: 600}
+}
+
+func TestExecuteDecision_DefersOverlappingOpen(t *testing.T) {
+	ex := &slowExchange{Provider: sim.New(), delay: 50 * time.Millisecond}
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := newInFlightTrader(m, ex)
+
+	var wg sync.WaitGroup
+	orders := make([]*ExecutedOrder, 2)
+	for i := range orders {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			order, err := m.executeDecision(vt, inFlightOpen())
+			assert.NoError(t, err)
+			orders[i] = order
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), ex.orders.Load(), "only one open should reach the exchange")
+	assert.Equal(t, 1, countNonNil(orders))
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardInFlightOpen])
+
+	// Until positions are synced the symbol stays pending.
+	order, err := m.executeDecision(vt, inFlightOpen())
+	require.NoError(t, err)
+	assert.Nil(t, order)
+	positions, err := ex.GetPositions(context.Background())
+	require.NoError(t, err)
+	require.Len(t, positions, 1)
+	assert.InDelta(t, 0.01, parseFloat(positions[0].Szi), 0.0005, "the position should not be doubled")
+
+	require.NoError(t, m.SyncTraderPositions(vt.ID))
+	order, err = m.executeDecision(vt, inFlightOpen())
+	require.NoError(t, err)
+	assert.NotNil(t, order, "a reflected open should no longer block the symbol")
+}
+
+func TestExecuteDecision_FailedOpenReleasesSymbol(t *testing.T) {
+	ex := &slowExchange{Provider: sim.New()}
+	ex.failed.Store(true)
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := newInFlightTrader(m, ex)
+
+	_, err := m.executeDecision(vt, inFlightOpen())
+	require.Error(t, err)
+
+	ex.failed.Store(false)
+	order, err := m.executeDecision(vt, inFlightOpen())
+	require.NoError(t, err)
+	assert.NotNil(t, order, "a failed open should not leave the symbol pending")
+	assert.Zero(t, m.GuardRejections(vt.ID)[GuardInFlightOpen])
+}
+
+func TestPendingOpenExpires(t *testing.T) {
+	vt := &VirtualTrader{}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.True(t, vt.claimOpen("btc", start))
+	assert.False(t, vt.claimOpen("BTC", start.Add(time.Second)))
+	assert.True(t, vt.claimOpen("ETH", start.Add(time.Second)), "other symbols are independent")
+
+	vt.settleOpens(nil, start.Add(pendingOpenTTL))
+	assert.True(t, vt.claimOpen("BTC", start.Add(pendingOpenTTL)), "an unfilled open should stop blocking after the TTL")
+}
+
+func countNonNil(orders []*ExecutedOrder) int {
+	n := 0
+	for _, o := range orders {
+		if o != nil {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,116 @@
+package manager
+
+import (
+	executorpkg "nof0-api/pkg/executor"
+	"nof0-api/pkg/journal"
+)
+
+// ExecutedOrder describes the order the manager submitted for a decision
+// after guards, sizing and order-style adjustments.
+type ExecutedOrder struct {
+	Symbol      string
+	Action      string
+	Price       float64
+	Size        float64
+	NotionalUSD float64
+	Leverage    int
+	OrderStyle  OrderStyle
+	ReduceOnly  bool
+	// DryRun marks an order that was logged but not submitted.
+	DryRun bool
+	// Resting marks a post_only open left on the book; nothing is held or
+	// protected until fills are observed.
+	Resting bool
+	// Protection is the SL/TP placement outcome for opens; nil when the
+	// provider cannot place them.
+	Protection *ProtectiveOrders
+}
+
+// intentLedger pairs each model decision with what the manager did with it,
+// so the gap between intent and execution can be audited per cycle.
+type intentLedger struct {
+	intents    []journal.DecisionIntent
+	executions []journal.DecisionExecution
+	settled    []bool
+}
+
+func newIntentLedger(ds []executorpkg.Decision) *intentLedger {
+	l := &intentLedger{
+		intents: make([]journal.DecisionIntent, 0, len(ds)),
+		settled: make([]bool, len(ds)),
+	}
+	for i, d := range ds {
+		l.intents = append(l.intents, journal.DecisionIntent{
+			Index:           i,
+			Symbol:          d.Symbol,
+			Action:          d.Action,
+			Leverage:        d.Leverage,
+			PositionSizeUSD: d.PositionSizeUSD,
+			PositionSizePct: d.PositionSizePct,
+			EntryPrice:      d.EntryPrice,
+			StopLoss:        d.StopLoss,
+			TakeProfit:      d.TakeProfit,
+			Confidence:      d.Confidence,
+		})
+	}
+	return l
+}
+
+// match returns the first unsettled intent with d's symbol and action, or -1.
+// Guards reorder and filter decisions but never rewrite them, so symbol and
+// action identify the originating intent.
+func (l *intentLedger) match(d executorpkg.Decision) int {
+	for i, in := range l.intents {
+		if !l.settled[i] && in.Symbol == d.Symbol && in.Action == d.Action {
+			return i
+		}
+	}
+	return -1
+}
+
+// executed records the outcome of executing d.
+func (l *intentLedger) executed(d executorpkg.Decision, order *ExecutedOrder, err error) {
+	idx := l.match(d)
+	if idx < 0 {
+		return
+	}
+	l.settled[idx] = true
+	exec := journal.DecisionExecution{IntentIndex: idx, Symbol: d.Symbol, Action: d.Action}
+	switch {
+	case err != nil:
+		exec.Status = journal.ExecutionFailed
+		exec.Reason = err.Error()
+	case order == nil:
+		exec.Status = journal.ExecutionSkipped
+		exec.Reason = "no_order"
+	default:
+		exec.Status = journal.ExecutionSubmitted
+		exec.Price = order.Price
+		exec.Size = order.Size
+		exec.NotionalUSD = order.NotionalUSD
+		exec.Leverage = order.Leverage
+		exec.OrderStyle = string(order.OrderStyle)
+		exec.ReduceOnly = order.ReduceOnly
+		if order.DryRun {
+			exec.Status = journal.ExecutionDryRun
+		}
+	}
+	l.executions = append(l.executions, exec)
+}
+
+// settleRemaining marks every intent without an outcome with status and reason.
+func (l *intentLedger) settleRemaining(status, reason string) {
+	for i, in := range l.intents {
+		if l.settled[i] {
+			continue
+		}
+		l.settled[i] = true
+		l.executions = append(l.executions, journal.DecisionExecution{
+			IntentIndex: i,
+			Symbol:      in.Symbol,
+			Action:      in.Action,
+			Status:      status,
+			Reason:      reason,
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	executorpkg "nof0-api/pkg/executor"
+	"nof0-api/pkg/journal"
+)
+
+func TestRunTraderCycleRecordsIntentAndExecutionSeparately(t *testing.T) {
+	persist := &fakePersistence{}
+	m := NewManager(nil, nil, nil, nil, persist)
+	exec := &fakeExecutor{decisions: []executorpkg.Decision{
+		{Symbol: "AVAX", Action: "open_long", PositionSizeUSD: 300, Confidence: 80},
+		{Symbol: "ETH", Action: "open_long", PositionSizeUSD: 200, Leverage: 2, Confidence: 75},
+	}}
+	mk := newFakeMarket(testSnapshot("AVAX", 30, 0.01), testSnapshot("ETH", 3000, 0.02)).withMaxLeverage(20)
+	vt := newCycleTestTrader(m, "intent", mk, exec)
+	off := false
+	vt.ExecGuards = ExecGuards{MaxNewPositionsPerCycle: 1, EnableSparseDataGuard: &off}
+	vt.Journal = journal.NewWriter(t.TempDir())
+	vt.JournalEnabled = true
+
+	m.runTraderCycle(context.Background(), vt)
+
+	require.Len(t, persist.cycles, 1)
+	rec := persist.cycles[0].Cycle
+	require.Len(t, rec.Intents, 2, "every model decision should be recorded as intent")
+	assert.Equal(t, journal.DecisionIntent{Index: 0, Symbol: "AVAX", Action: "open_long", PositionSizeUSD: 300, Confidence: 80}, rec.Intents[0],
+		"intent should keep the model's unresolved leverage and price")
+	assert.Equal(t, 2, rec.Intents[1].Leverage)
+
+	require.Len(t, rec.Executions, 2, "every intent should have an execution outcome")
+	byIntent := make(map[int]journal.DecisionExecution)
+	for _, e := range rec.Executions {
+		byIntent[e.IntentIndex] = e
+	}
+	avax := byIntent[0]
+	assert.Equal(t, journal.ExecutionSubmitted, avax.Status)
+	assert.Equal(t, 3, avax.Leverage, "execution should show the resolved altcoin leverage")
+	assert.InDelta(t, 30, avax.Price, 1e-9, "execution should show the resolved price")
+	assert.InDelta(t, 10, avax.Size, 1e-9)
+	assert.InDelta(t, 300, avax.NotionalUSD, 1e-9)
+	assert.Equal(t, string(OrderStyleLimitIOC), avax.OrderStyle)
+
+	eth := byIntent[1]
+	assert.Equal(t, "ETH", eth.Symbol)
+	assert.Equal(t, journal.ExecutionDropped, eth.Status, "capped open should be recorded as dropped, not omitted")
+	assert.Equal(t, "manager_guards", eth.Reason)
+	assert.Zero(t, eth.NotionalUSD)
+}
+
+func TestIntentLedgerOutcomes(t *testing.T) {
+	ds := []executorpkg.Decision{
+		{Symbol: "BTC", Action: "open_long", PositionSizeUSD: 100},
+		{Symbol: "BTC", Action: "open_long", PositionSizeUSD: 50},
+		{Symbol: "ETH", Action: "hold"},
+	}
+	l := newIntentLedger(ds)
+	l.executed(ds[1], nil, errors.New("exchange down"))
+	l.executed(ds[2], nil, nil)
+	l.settleRemaining(journal.ExecutionSkipped, GuardLatencyBudget)
+
+	require.Len(t, l.executions, 3)
+	assert.Equal(t, 0, l.executions[0].IntentIndex, "duplicate symbol/action should settle the first open intent")
+	assert.Equal(t, journal.ExecutionFailed, l.executions[0].Status)
+	assert.Equal(t, "exchange down", l.executions[0].Reason)
+	assert.Equal(t, journal.ExecutionSkipped, l.executions[1].Status)
+	assert.Equal(t, "no_order", l.executions[1].Reason)
+	assert.Equal(t, 1, l.executions[2].IntentIndex)
+	assert.Equal(t, GuardLatencyBudget, l.executions[2].Reason)
+}
@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// intentKeyTTL bounds how long an executed decision's idempotency key is
+// remembered; it only needs to outlive a retry of the same cycle.
+const intentKeyTTL = 15 * time.Minute
+
+// decisionIntentKey derives the idempotency key of d within a trader's decision
+// cycle, so a retried execution of the same logical intent maps to the same key.
+func decisionIntentKey(traderID string, cycle int, d executorpkg.Decision) string {
+	return fmt.Sprintf("%s:%d:%s:%s", traderID, cycle, fundingKey(d.Symbol), strings.ToLower(strings.TrimSpace(d.Action)))
+}
+
+// claimIntent records key as executing. It returns false when the key was
+// already claimed within intentKeyTTL, in which case the decision is a repeat
+// and must be skipped. An empty key always claims.
+func (t *VirtualTrader) claimIntent(key string, now time.Time) bool {
+	if key == "" {
+		return true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.executedIntents == nil {
+		t.executedIntents = make(map[string]time.Time)
+	}
+	for k, at := range t.executedIntents {
+		if now.Sub(at) >= intentKeyTTL {
+			delete(t.executedIntents, k)
+		}
+	}
+	if _, ok := t.executedIntents[key]; ok {
+		return false
+	}
+	t.executedIntents[key] = now
+	return true
+}
+
+// releaseIntent forgets key, as when its execution failed and may be retried.
+func (t *VirtualTrader) releaseIntent(key string) {
+	if key == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.executedIntents, key)
+}
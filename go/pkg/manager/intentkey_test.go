@@ -0,0 +1,67 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange/sim"
+)
+
+func TestExecuteDecision_SkipsRepeatedIntentKey(t *testing.T) {
+	ex := &slowExchange{Provider: sim.New()}
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := newInFlightTrader(m, ex)
+
+	d := inFlightOpen()
+	d.IdempotencyKey = decisionIntentKey(vt.ID, 1, *d)
+	order, err := m.executeDecision(vt, d)
+	require.NoError(t, err)
+	require.NotNil(t, order)
+	// Clear the in-flight marker so only the intent key can block the retry.
+	require.NoError(t, m.SyncTraderPositions(vt.ID))
+
+	retry := inFlightOpen()
+	retry.IdempotencyKey = d.IdempotencyKey
+	require.NoError(t, m.ExecuteDecision(vt, retry))
+	assert.Equal(t, int32(1), ex.orders.Load(), "a retried intent must not place a second order")
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardDuplicateIntent])
+
+	next := inFlightOpen()
+	next.IdempotencyKey = decisionIntentKey(vt.ID, 2, *next)
+	order, err = m.executeDecision(vt, next)
+	require.NoError(t, err)
+	assert.NotNil(t, order, "the same intent in a later cycle is a new order")
+	assert.Equal(t, int32(2), ex.orders.Load())
+}
+
+func TestExecuteDecision_FailedIntentMayRetry(t *testing.T) {
+	ex := &slowExchange{Provider: sim.New()}
+	ex.failed.Store(true)
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := newInFlightTrader(m, ex)
+
+	d := inFlightOpen()
+	d.IdempotencyKey = decisionIntentKey(vt.ID, 1, *d)
+	_, err := m.executeDecision(vt, d)
+	require.Error(t, err)
+
+	ex.failed.Store(false)
+	order, err := m.executeDecision(vt, d)
+	require.NoError(t, err)
+	assert.NotNil(t, order, "a failed execution should not consume its intent key")
+	assert.Zero(t, m.GuardRejections(vt.ID)[GuardDuplicateIntent])
+}
+
+func TestIntentKeyExpires(t *testing.T) {
+	vt := &VirtualTrader{}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	key := decisionIntentKey("t1", 3, *inFlightOpen())
+	assert.Equal(t, "t1:3:BTC:open_long", key)
+	require.True(t, vt.claimIntent(key, start))
+	assert.False(t, vt.claimIntent(key, start.Add(time.Minute)))
+	assert.True(t, vt.claimIntent("", start), "decisions without a key are never deduplicated")
+	assert.True(t, vt.claimIntent(key, start.Add(intentKeyTTL)), "keys should be forgotten after the TTL")
+}
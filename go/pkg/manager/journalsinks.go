@@ -0,0 +1,47 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"nof0-api/pkg/journal"
+)
+
+// newTraderJournal builds the cycle writer for a trader's configured journal
+// sinks, fanning out through a MultiWriter when more than one is set.
+func newTraderJournal(cfg TraderConfig) journal.CycleWriter {
+	sinks := cfg.JournalSinks
+	if len(sinks) == 0 {
+		sinks = []string{JournalSinkFile}
+	}
+	dir := cfg.JournalDir
+	if strings.TrimSpace(dir) == "" {
+		dir = fmt.Sprintf("journal/%s", cfg.ID)
+	}
+	var opts []journal.WriterOption
+	if cfg.JournalCompress {
+		opts = append(opts, journal.WithCompression())
+	}
+	if cfg.JournalMaxFileMB > 0 {
+		opts = append(opts, journal.WithMaxFileBytes(int64(cfg.JournalMaxFileMB*1024*1024)))
+	}
+	if cfg.JournalRotateEvery > 0 {
+		opts = append(opts, journal.WithRotateEvery(cfg.JournalRotateEvery))
+	}
+	writers := make([]journal.CycleWriter, 0, len(sinks))
+	for _, sink := range sinks {
+		switch sink {
+		case JournalSinkStdout:
+			writers = append(writers, journal.NewStdoutWriter())
+		default:
+			writers = append(writers, journal.NewWriter(dir, opts...))
+		}
+	}
+	logx.Infof("manager: trader %s journaling enabled sinks=%s dir=%s", cfg.ID, strings.Join(sinks, ","), dir)
+	if len(writers) == 1 {
+		return writers[0]
+	}
+	return journal.NewMultiWriter(writers...)
+}
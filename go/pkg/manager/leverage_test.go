@@ -0,0 +1,84 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+func TestExecuteDecisionRecordsLeverageChange(t *testing.T) {
+	ex := sim.New()
+	require.NoError(t, ex.SetMarkPrice(context.Background(), "SOL", 150))
+	persist := &fakePersistence{}
+	m := NewManager(nil, nil, nil, nil, persist)
+	vt := &VirtualTrader{
+		ID:               "lev",
+		ExchangeProvider: ex,
+		MarketProvider:   newFakeMarket(testSnapshot("SOL", 150, 0)).withMaxLeverage(20),
+		OrderStyle:       OrderStyleMarketIOC,
+		RiskParams:       RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 1000, AltcoinLeverage: 7},
+		Cooldown:         make(map[string]time.Time),
+	}
+
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 300}))
+
+	require.Len(t, persist.leverage, 1, "leverage update should be audited")
+	change := persist.leverage[0]
+	assert.Equal(t, "lev", change.TraderID)
+	assert.Equal(t, "SOL", change.Symbol)
+	assert.Equal(t, "cross", change.Mode)
+	assert.Equal(t, 7, change.Leverage, "risk-param leverage should apply when the decision omits it")
+	assert.False(t, change.ChangedAt.IsZero())
+
+	require.Len(t, persist.events, 1, "open should be recorded")
+	assert.Equal(t, PositionEventOpen, persist.events[0].Event)
+	assert.Equal(t, 7, persist.events[0].Leverage, "opened position should carry the active leverage")
+}
+
+func TestExecuteDecisionClampsLeverageToAssetMax(t *testing.T) {
+	ex := sim.New()
+	require.NoError(t, ex.SetMarkPrice(context.Background(), "SOL", 150))
+	persist := &fakePersistence{}
+	m := NewManager(nil, nil, nil, nil, persist)
+	vt := &VirtualTrader{
+		ID:               "lev",
+		ExchangeProvider: ex,
+		MarketProvider:   newFakeMarket(testSnapshot("SOL", 150, 0)).withMaxLeverage(5),
+		OrderStyle:       OrderStyleMarketIOC,
+		RiskParams:       RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 1000, AltcoinLeverage: 7},
+		Cooldown:         make(map[string]time.Time),
+	}
+
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", Leverage: 10, PositionSizeUSD: 300}))
+
+	require.Len(t, persist.leverage, 1)
+	assert.Equal(t, 5, persist.leverage[0].Leverage, "leverage should be clamped to the asset cap")
+	require.Len(t, persist.events, 1)
+	assert.Equal(t, 5, persist.events[0].Leverage)
+}
+
+func TestExecuteDecisionSkipsLeverageUpdateWithoutAssetMax(t *testing.T) {
+	ex := sim.New()
+	require.NoError(t, ex.SetMarkPrice(context.Background(), "SOL", 150))
+	persist := &fakePersistence{}
+	m := NewManager(nil, nil, nil, nil, persist)
+	vt := &VirtualTrader{
+		ID:               "lev",
+		ExchangeProvider: ex,
+		MarketProvider:   newFakeMarket(testSnapshot("SOL", 150, 0)),
+		OrderStyle:       OrderStyleMarketIOC,
+		RiskParams:       RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 1000, AltcoinLeverage: 7},
+		Cooldown:         make(map[string]time.Time),
+	}
+
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 300}))
+
+	assert.Empty(t, persist.leverage, "no leverage update without a known cap")
+	require.Len(t, persist.events, 1, "the open still goes through")
+}
@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/zeromicro/go-zero/core/logx"
@@ -62,6 +63,11 @@ func (f *BasicExecutorFactory) NewExecutor(traderCfg TraderConfig) (executorpkg.
 	if intervalRaw == "" {
 		intervalRaw = "3m"
 	}
+	timeout := decisionTimeout(traderCfg.DecisionTimeout, interval)
+	if traderCfg.DecisionTimeout <= 0 && timeout < defaultDecisionTimeout {
+		logx.Infof("manager: trader %s decision_timeout capped to %s below decision_interval %s", traderCfg.ID, timeout, interval)
+	}
+	requireSL, requireTP := traderCfg.RiskParams.StopLossEnabled, traderCfg.RiskParams.TakeProfitEnabled
 	ec := &executorpkg.Config{
 		MajorCoinLeverage:      traderCfg.RiskParams.MajorCoinLeverage,
 		AltcoinLeverage:        traderCfg.RiskParams.AltcoinLeverage,
@@ -70,15 +76,25 @@ func (f *BasicExecutorFactory) NewExecutor(traderCfg TraderConfig) (executorpkg.
 		MaxPositions:           traderCfg.RiskParams.MaxPositions,
 		DecisionIntervalRaw:    intervalRaw,
 		DecisionInterval:       interval,
-		DecisionTimeoutRaw:     "60s",
-		DecisionTimeout:        60 * time.Second,
+		DecisionTimeoutRaw:     timeout.String(),
+		DecisionTimeout:        timeout,
 		MaxConcurrentDecisions: 1,
 		AllowedTraderIDs:       []string{traderCfg.ID},
+		PromptSections:         traderCfg.PromptSections,
+		SymbolUniverse:         traderCfg.SymbolUniverse,
+		DecisionSchema:         traderCfg.DecisionSchema,
+		ToolCalling:            traderCfg.ToolCalling,
+		MaxToolIterations:      traderCfg.MaxToolIterations,
+		StreamDecisions:        traderCfg.StreamDecisions,
+		RequireStopLoss:        &requireSL,
+		RequireTakeProfit:      &requireTP,
 	}
 	// executor.NewExecutor validates config.
 	ec.TraderID = traderCfg.ID
 	var opts []executorpkg.ExecutorOption
-	if f.conversationLogger != nil {
+	// With persist_prompts the cycle path stores the conversation linked to
+	// its cycle id; recording here too would add an unlinked duplicate.
+	if f.conversationLogger != nil && !traderCfg.PersistPrompts {
 		opts = append(opts, executorpkg.WithConversationRecorder(f.conversationLogger))
 	}
 	exec, err := executorpkg.NewExecutor(ec, f.llmClient, traderCfg.ExecutorTemplate, traderCfg.Model, opts...)
@@ -88,6 +104,27 @@ func (f *BasicExecutorFactory) NewExecutor(traderCfg TraderConfig) (executorpkg.
 	return exec, nil
 }
 
+const (
+	// defaultDecisionTimeout bounds an LLM decision call when the trader
+	// does not configure decision_timeout.
+	defaultDecisionTimeout = 60 * time.Second
+	// decisionTimeoutIntervalShare caps the default timeout to this share of
+	// the decision interval so a slow call cannot overlap the next cycle.
+	decisionTimeoutIntervalShare = 0.8
+)
+
+// decisionTimeout returns the configured timeout, or the default capped to
+// decisionTimeoutIntervalShare of interval, keeping it below the interval.
+func decisionTimeout(configured, interval time.Duration) time.Duration {
+	if configured > 0 {
+		return configured
+	}
+	if interval > 0 && defaultDecisionTimeout >= interval {
+		return time.Duration(float64(interval) * decisionTimeoutIntervalShare)
+	}
+	return defaultDecisionTimeout
+}
+
 // Manager is the orchestration layer that coordinates virtual traders,
 // executors and providers.
 type Manager struct {
@@ -100,13 +137,29 @@ type Manager struct {
 	// Provider registries resolved at startup (see internal/svc for wiring).
 	exchangeProviders map[string]exchange.Provider
 	marketProviders   map[string]market.Provider
+	// vaultProviders builds per-trader signers for traders with vault_address.
+	vaultProviders VaultProviderBuilder
+	// ingestStatus reports market ingestion health for /healthz.
+	ingestStatus func() any
 
 	executorFactory ExecutorFactory
 	persistence     PersistenceService
+	guardStats      *guardStats
+	alerts          *alertNotifier
+	scheduler       *fairScheduler
+	windDown        atomic.Bool
+	dryRun          atomic.Bool
+	loopRunning     atomic.Bool
+	clock           Clock
+	tickInterval    time.Duration
+	cyclesCompleted atomic.Int64
+	workers         chan struct{} // decision worker slots; nil runs cycles inside the tick
+	cyclesInFlight  atomic.Int64
 
 	stopChan chan struct{}
 	stopOnce sync.Once
-	wg       sync.WaitGroup
+	wg       sync.WaitGroup // background decision cycles
+	loopWG   sync.WaitGroup // running RunTradingLoop calls
 }
 
 // NewManager constructs a Manager with injected dependencies.
@@ -130,8 +183,16 @@ func NewManager(
 		marketProviders:   make(map[string]market.Provider),
 		executorFactory:   execFactory,
 		persistence:       persist,
+		guardStats:        newGuardStats(),
+		alerts:            newAlertNotifier(cfg.Monitoring.AlertWebhook),
+		scheduler:         newFairScheduler(),
+		clock:             systemClock{},
+		tickInterval:      time.Second,
 		stopChan:          make(chan struct{}),
 	}
+	if n := cfg.Manager.DecisionWorkers; n > 0 {
+		m.workers = make(chan struct{}, n)
+	}
 	for k, v := range exch {
 		m.exchangeProviders[k] = v
 	}
@@ -158,17 +219,7 @@ func (m *Manager) RegisterTrader(cfg TraderConfig) (*VirtualTrader, error) {
 	if m == nil {
 		return nil, errors.New("manager: nil manager")
 	}
-	tempCfg := &Config{
-		Manager:    ManagerConfig{},
-		Traders:    []TraderConfig{cfg},
-		Monitoring: MonitoringConfig{},
-	}
-	if m.config != nil {
-		tempCfg.Manager = m.config.Manager
-		tempCfg.Monitoring = m.config.Monitoring
-	}
-	if err := tempCfg.Validate(); err != nil {
-		// Reuse config validation on a temporary wrapper to validate the trader.
+	if err := m.validateTraderConfig(cfg); err != nil {
 		return nil, err
 	}
 
@@ -180,9 +231,9 @@ func (m *Manager) RegisterTrader(cfg TraderConfig) (*VirtualTrader, error) {
 	}
 
 	// Resolve providers by ID as declared in manager config.
-	ex, ok := m.exchangeProviders[cfg.ExchangeProvider]
-	if !ok {
-		return nil, fmt.Errorf("manager: unknown exchange provider %q for trader %s", cfg.ExchangeProvider, cfg.ID)
+	ex, err := m.resolveTraderExchange(cfg)
+	if err != nil {
+		return nil, err
 	}
 	mk, ok := m.marketProviders[cfg.MarketProvider]
 	if !ok {
@@ -195,19 +246,30 @@ func (m *Manager) RegisterTrader(cfg TraderConfig) (*VirtualTrader, error) {
 	if err != nil {
 		return nil, fmt.Errorf("manager: create executor for trader %s: %w", cfg.ID, err)
 	}
+	attachToolProvider(exec, ex, mk)
 
 	vt := &VirtualTrader{
 		ID:                   cfg.ID,
 		Name:                 cfg.Name,
 		Exchange:             cfg.ExchangeProvider,
+		Market:               cfg.MarketProvider,
+		VaultAddress:         cfg.VaultAddress,
 		ExchangeProvider:     ex,
 		MarketProvider:       mk,
 		Executor:             exec,
 		PromptTemplate:       cfg.PromptTemplate,
 		OrderStyle:           cfg.OrderStyle,
 		MarketIOCSlippageBps: cfg.MarketIOCSlippageBps,
+		PostOnlyOffsetBps:    cfg.PostOnlyOffsetBps,
+		MinOrderNotionalUSD:  cfg.MinOrderNotionalUSD,
+		MarginMode:           cfg.MarginMode,
+		SizingMode:           cfg.SizingMode,
+		SizingFloorUSD:       cfg.SizingFloorUSD,
+		KellyMultiplier:      cfg.KellyMultiplier,
 		RiskParams:           cfg.RiskParams,
 		ExecGuards:           cfg.ExecGuards,
+		TradingWindows:       cfg.TradingWindows,
+		TokenBudget:          cfg.TokenBudget,
 		ResourceAlloc: ResourceAllocation{
 			AllocationPct: cfg.AllocationPct,
 		},
@@ -217,17 +279,14 @@ func (m *Manager) RegisterTrader(cfg TraderConfig) (*VirtualTrader, error) {
 		UpdatedAt:        time.Now(),
 		Cooldown:         make(map[string]time.Time),
 		JournalEnabled:   cfg.JournalEnabled,
+		PersistPrompts:   cfg.PersistPrompts,
 	}
 	if cfg.JournalEnabled {
-		dir := cfg.JournalDir
-		if strings.TrimSpace(dir) == "" {
-			dir = fmt.Sprintf("journal/%s", cfg.ID)
-		}
-		vt.Journal = journal.NewWriter(dir)
-		logx.Infof("manager: trader %s journaling enabled dir=%s", cfg.ID, dir)
+		vt.Journal = newTraderJournal(cfg)
 	}
 
 	m.traders[cfg.ID] = vt
+	m.allocateEquityLocked()
 	if cfg.AutoStart {
 		_ = vt.Start()
 	}
@@ -235,6 +294,21 @@ func (m *Manager) RegisterTrader(cfg TraderConfig) (*VirtualTrader, error) {
 	return vt, nil
 }
 
+// validateTraderConfig reuses config validation on a temporary wrapper to
+// validate a single trader against the manager settings.
+func (m *Manager) validateTraderConfig(cfg TraderConfig) error {
+	tempCfg := &Config{
+		Manager:    ManagerConfig{},
+		Traders:    []TraderConfig{cfg},
+		Monitoring: MonitoringConfig{},
+	}
+	if m.config != nil {
+		tempCfg.Manager = m.config.Manager
+		tempCfg.Monitoring = m.config.Monitoring
+	}
+	return tempCfg.Validate()
+}
+
 // UnregisterTrader stops and removes a trader from registry.
 func (m *Manager) UnregisterTrader(traderID string) error {
 	m.mu.Lock()
@@ -245,6 +319,7 @@ func (m *Manager) UnregisterTrader(traderID string) error {
 	}
 	_ = t.Stop() // Best-effort stop; ignore error for MVP.
 	delete(m.traders, traderID)
+	m.allocateEquityLocked()
 	logx.Infof("manager: unregistered trader id=%s", traderID)
 	return nil
 }
@@ -268,9 +343,29 @@ func (m *Manager) RunTradingLoop(ctx context.Context) error {
 	if m == nil {
 		return errors.New("manager: nil manager")
 	}
-	logx.WithContext(ctx).Infof("manager: trading loop starting tick=1s active_traders=%d", len(m.GetActiveTraders()))
-	ticker := time.NewTicker(1 * time.Second)
+	tick := m.tickInterval
+	if tick <= 0 {
+		tick = time.Second
+	}
+	rebalance := m.rebalanceInterval()
+	m.loopWG.Add(1)
+	defer m.loopWG.Done()
+	m.loopRunning.Store(true)
+	defer m.loopRunning.Store(false)
+	logx.WithContext(ctx).Infof("manager: trading loop starting tick=%s rebalance_interval=%s decision_workers=%d active_traders=%d", tick, rebalance, cap(m.workers), len(m.GetActiveTraders()))
+	// Let background cycles and alert deliveries finish before the caller
+	// flattens or exits.
+	defer m.alerts.wait()
+	defer m.wg.Wait()
+	ticker := time.NewTicker(tick)
 	defer ticker.Stop()
+	// A nil channel never fires, leaving rebalancing disabled.
+	var rebalanceC <-chan time.Time
+	if rebalance > 0 {
+		rebalanceTicker := time.NewTicker(rebalance)
+		defer rebalanceTicker.Stop()
+		rebalanceC = rebalanceTicker.C
+	}
 
 	for {
 		select {
@@ -280,130 +375,282 @@ func (m *Manager) RunTradingLoop(ctx context.Context) error {
 		case <-m.stopChan:
 			logx.WithContext(ctx).Infof("manager: trading loop stopping (stop signal)")
 			return nil
+		case <-rebalanceC:
+			m.Rebalance(ctx)
 		case <-ticker.C:
-			traders := m.GetActiveTraders()
-			for _, t := range traders {
-				if !t.ShouldMakeDecision() {
-					continue
-				}
-				cycleStart := time.Now()
-				// Sharpe gating
-				if t.ExecGuards.SharpePauseThreshold != 0 && t.ExecGuards.PauseDurationOnBreach > 0 && t.Performance != nil {
-					if t.Performance.SharpeRatio < t.ExecGuards.SharpePauseThreshold {
-						t.mu.Lock()
-						if t.PauseUntil.Before(time.Now()) {
-							t.PauseUntil = time.Now().Add(t.ExecGuards.PauseDurationOnBreach)
-						}
-						t.mu.Unlock()
-						logx.WithContext(ctx).Infof("manager: trader %s paused for Sharpe gating until %s", t.ID, t.PauseUntil.Format(time.RFC3339))
-						continue
-					}
-				}
-				// Build richer executor context and refresh performance view.
-				perfView := t.Performance.ToExecutorView()
-				t.Executor.UpdatePerformance(perfView)
-
-				ectx := m.buildExecutorContext(t)
-				out, decisionErr := t.Executor.GetFullDecision(&ectx)
-				// NOTE: BasicExecutor will still return a FullDecision even when validation fails (decisionErr != nil),
-				// so call sites must treat decisionErr as authoritative and avoid executing the payload until it passes.
-
-				// Prepare journaling containers
-				var decisionsJSON string
-				var actions []map[string]any
-				allOK := true
-				decisionCount := 0
-				if out != nil {
-					// decisionErr can be non-nil here; when wiring retries/guards make sure we don't execute decisions that failed validation.
-					decisionCount = len(out.Decisions)
-					if b, e := json.Marshal(out.Decisions); e == nil {
-						decisionsJSON = string(b)
-					}
-					// Close actions first, then open actions; cap new opens by remaining slots.
-					decisions := sortDecisionsCloseFirst(out.Decisions)
-					// remaining slots by max positions
-					remaining := t.RiskParams.MaxPositions - len(ectx.Positions)
-					if remaining < 0 {
-						remaining = 0
-					}
-					// also enforce per-cycle cap if configured (>0)
-					cycleCap := t.ExecGuards.MaxNewPositionsPerCycle
-					if cycleCap > 0 && cycleCap < remaining {
-						remaining = cycleCap
-					}
-					decisions = capNewOpenDecisions(decisions, remaining)
-					for i := range decisions {
-						d := decisions[i]
-						execErr := m.ExecuteDecision(t, &d)
-						act := map[string]any{
-							"symbol":            d.Symbol,
-							"action":            d.Action,
-							"leverage":          d.Leverage,
-							"position_size_usd": d.PositionSizeUSD,
-							"entry_price":       d.EntryPrice,
-							"stop_loss":         d.StopLoss,
-							"take_profit":       d.TakeProfit,
-							"confidence":        d.Confidence,
-							"result":            "ok",
-						}
-						if execErr != nil {
-							act["result"] = "error"
-							act["error"] = execErr.Error()
-							allOK = false
-							logx.WithContext(ctx).Errorf("manager: trader %s decision action=%s symbol=%s error=%v", t.ID, d.Action, d.Symbol, execErr)
-						}
-						actions = append(actions, act)
-					}
-				} else {
-					allOK = false
-					if decisionErr != nil {
-						logx.WithContext(ctx).Errorf("manager: trader %s decision generation failed: %v", t.ID, decisionErr)
-					}
-				}
+			m.runSchedulingRound(ctx)
+			if m.cycleLimitReached() {
+				logx.WithContext(ctx).Infof("manager: trading loop stopping (max cycles reached) cycles=%d", m.cyclesCompleted.Load())
+				m.Stop()
+				return nil
+			}
+		}
+	}
+}
 
-				// Update lightweight performance snapshot (success ratio proxy)
-				if t.Performance == nil {
-					t.Performance = &PerformanceMetrics{}
-				}
-				succ := 0
-				for _, a := range actions {
-					if a["result"] == "ok" {
-						succ++
-					}
-				}
-				total := len(actions)
-				t.Performance.TotalTrades += total
-				if total > 0 {
-					t.Performance.WinRate = float64(succ) / float64(total)
-				}
-				t.Performance.UpdatedAt = time.Now()
-				m.recordAnalytics(AnalyticsSnapshot{
-					TraderID:       t.ID,
-					TotalPnLUSD:    t.Performance.TotalPnLUSD,
-					TotalPnLPct:    t.Performance.TotalPnLPct,
-					SharpeRatio:    t.Performance.SharpeRatio,
-					WinRate:        t.Performance.WinRate,
-					TotalTrades:    t.Performance.TotalTrades,
-					MaxDrawdownPct: t.Performance.MaxDrawdownPct,
-					UpdatedAt:      t.Performance.UpdatedAt,
-				})
-
-				// Journal the cycle if configured
-				if t.Journal != nil && t.JournalEnabled {
-					if jErr := m.writeJournalRecord(t, &ectx, out, decisionsJSON, actions, decisionErr, allOK); jErr != nil {
-						logx.WithContext(ctx).Errorf("manager: trader %s journal write failed: %v", t.ID, jErr)
-					} else {
-						logx.WithContext(ctx).Infof("manager: trader %s journal written prompt_digest=%s", t.ID, outPromptDigest(out))
-					}
-				}
-				t.RecordDecision(time.Now())
-				if syncErr := m.SyncTraderPositions(t.ID); syncErr != nil {
-					logx.WithContext(ctx).Errorf("manager: trader %s sync positions error: %v", t.ID, syncErr)
-				}
-				logx.WithContext(ctx).Infof("manager: cycle trader=%s decisions=%d actions=%d ok=%t duration=%s", t.ID, decisionCount, len(actions), allOK && decisionErr == nil, time.Since(cycleStart).String())
+// runSchedulingRound runs one decision cycle for each eligible trader. When
+// manager.max_concurrent_decisions is set, only that many traders run per tick
+// and the fair scheduler decides who goes first so short-interval traders
+// cannot starve the rest.
+func (m *Manager) runSchedulingRound(ctx context.Context) {
+	eligible := make([]*VirtualTrader, 0)
+	now := m.now()
+	for _, t := range m.GetActiveTraders() {
+		if t.ShouldMakeDecisionAt(now) && !m.traderCycleLimitReached(t) {
+			eligible = append(eligible, t)
+		}
+	}
+	if budget, ok := m.remainingCycles(); ok && len(eligible) > budget {
+		eligible = eligible[:budget]
+	}
+	if len(eligible) == 0 {
+		return
+	}
+	if m.workers != nil {
+		m.dispatchCycles(ctx, eligible)
+		return
+	}
+	limit := 0
+	if m.config != nil {
+		limit = m.config.Manager.MaxConcurrentDecisions
+	}
+	if limit <= 0 {
+		for _, t := range eligible {
+			m.runTraderCycle(ctx, t)
+		}
+		return
+	}
+	var wg sync.WaitGroup
+	for _, t := range m.scheduler.next(eligible, limit) {
+		wg.Add(1)
+		go func(t *VirtualTrader) {
+			defer wg.Done()
+			m.runTraderCycle(ctx, t)
+		}(t)
+	}
+	wg.Wait()
+}
+
+// runTraderCycle performs a full decide/execute/journal cycle for one trader.
+func (m *Manager) runTraderCycle(ctx context.Context, t *VirtualTrader) {
+	if !m.beginCycle(t) {
+		logx.WithContext(ctx).Infof("manager: trader %s skip cycle reason=cycle_in_progress", t.ID)
+		return
+	}
+	m.runClaimedCycle(ctx, t)
+}
+
+// runClaimedCycle runs the cycle body for a trader whose deciding flag the
+// caller already set via beginCycle, and clears the flag when done.
+func (m *Manager) runClaimedCycle(ctx context.Context, t *VirtualTrader) {
+	defer t.deciding.Store(false)
+	cycleStart := time.Now()
+	defer m.countCycle(t)
+	// Book resting post_only fills before any gate can end the cycle: a
+	// paused trader runs no cycles, so its opens are cancelled on the way out.
+	resting := m.syncRestingOrders(ctx, t)
+	if m.WindDown() {
+		m.cancelRestingOrders(ctx, t, resting, GuardWindDown)
+		resting = nil
+	}
+	// Sharpe gating
+	if t.ExecGuards.SharpePauseThreshold != 0 && t.ExecGuards.PauseDurationOnBreach > 0 {
+		if sharpe, ok := t.gateSharpe(m.now()); ok && sharpe < t.ExecGuards.SharpePauseThreshold {
+			t.mu.Lock()
+			paused := false
+			if t.PauseUntil.Before(time.Now()) {
+				t.PauseUntil = time.Now().Add(t.ExecGuards.PauseDurationOnBreach)
+				paused = true
 			}
+			t.mu.Unlock()
+			m.recordGuardRejection(t.ID, GuardSharpePause, 1)
+			if paused {
+				m.sendAlert(AlertTraderPaused, t.ID, "sharpe %.2f below threshold %.2f; paused until %s", sharpe, t.ExecGuards.SharpePauseThreshold, t.PauseUntil.Format(time.RFC3339))
+			}
+			logx.WithContext(ctx).Infof("manager: trader %s paused for Sharpe gating until %s", t.ID, t.PauseUntil.Format(time.RFC3339))
+			m.cancelRestingOrders(ctx, t, resting, GuardSharpePause)
+			return
 		}
 	}
+	if over, detail := t.tokenBudgetExceeded(m.now()); over {
+		m.recordGuardRejection(t.ID, GuardTokenBudget, 1)
+		logx.WithContext(ctx).Errorf("manager: trader %s skip cycle reason=token_budget_exceeded %s", t.ID, detail)
+		t.RecordDecision(m.now())
+		return
+	}
+	// Build richer executor context and refresh performance view.
+	perfView := t.Performance.ToExecutorView()
+	t.Executor.UpdatePerformance(perfView)
+
+	buildStart := time.Now()
+	snaps := snapshotCache{}
+	ectx, accountOK := m.buildExecutorContext(t, snaps)
+	if accountOK && m.haltOnDepletedEquity(t, ectx.Account.TotalEquity) {
+		m.cancelRestingOrders(ctx, t, resting, "depleted_equity")
+		t.RecordDecision(m.now())
+		return
+	}
+	if accountOK && m.gateDrawdown(ctx, t, ectx.Account.TotalEquity) {
+		m.cancelRestingOrders(ctx, t, resting, GuardMaxDrawdown)
+		t.RecordDecision(m.now())
+		return
+	}
+	if accountOK && m.gateDailyLoss(ctx, t, ectx.Account.TotalEquity) {
+		m.cancelRestingOrders(ctx, t, resting, GuardDailyLoss)
+		t.RecordDecision(m.now())
+		return
+	}
+	m.repriceLiveOrders(ctx, t, resting, snaps)
+	if sparse, coverage := sparseMarketData(t, snaps); sparse {
+		m.recordGuardRejection(t.ID, GuardSparseData, 1)
+		logx.WithContext(ctx).Errorf("manager: trader %s skip cycle reason=sparse_market_data coverage=%.1f%% candidates=%d", t.ID, coverage, len(ectx.CandidateCoins))
+		t.RecordDecision(m.now())
+		return
+	}
+	llmStart := time.Now()
+	out, decisionErr := t.Executor.GetFullDecision(&ectx)
+	llmLatencySeconds.WithLabelValues(t.ID).Observe(time.Since(llmStart).Seconds())
+	if out != nil {
+		t.recordTokenUsage(m.now(), out.Usage)
+	}
+	if errors.Is(decisionErr, llm.ErrBudgetExceeded) {
+		m.recordGuardRejection(t.ID, GuardLLMSpendBudget, 1)
+		logx.WithContext(ctx).Errorf("manager: trader %s skip cycle reason=llm_budget_exceeded: %v", t.ID, decisionErr)
+	}
+	var guardErr *executorpkg.GuardError
+	if errors.As(decisionErr, &guardErr) {
+		m.recordGuardRejection(t.ID, guardErr.Guard, 1)
+	}
+	if decisionErr != nil {
+		errorsTotal.WithLabelValues(t.ID, "generate").Inc()
+		m.sendAlert(AlertDecisionFailed, t.ID, "decision generation failed: %v", decisionErr)
+	}
+	// An executor may return a FullDecision alongside decisionErr (for usage
+	// and CoT); decisionErr is authoritative and its decisions are never executed.
+	journalErr := decisionErr
+	latencySkip := false
+	if budget := decisionLatencyBudget(t); budget > 0 {
+		if latency := time.Since(buildStart); latency > budget {
+			latencySkip = true
+			m.recordGuardRejection(t.ID, GuardLatencyBudget, 1)
+			skipErr := fmt.Errorf("manager: execution skipped: decision latency %s exceeded budget %s", latency.Round(time.Millisecond), budget)
+			journalErr = errors.Join(decisionErr, skipErr)
+			logx.WithContext(ctx).Errorf("manager: trader %s skip execution reason=decision_latency_budget latency=%s budget=%s", t.ID, latency, budget)
+		}
+	}
+
+	// Prepare journaling containers
+	var decisionsJSON string
+	var actions []map[string]any
+	var ledger *intentLedger
+	allOK := true
+	decisionCount := 0
+	if out != nil {
+		ledger = newIntentLedger(out.Decisions)
+	}
+	t.mu.RLock()
+	cycle := t.CyclesCompleted + 1
+	t.mu.RUnlock()
+	// Aged positions close whatever the model decided, ahead of its decisions.
+	agedActions, agedClosed, agedOK := m.closeAgedPositions(ctx, t, ectx.Positions, cycle)
+	actions = append(actions, agedActions...)
+	allOK = allOK && agedOK
+	if out != nil && latencySkip {
+		decisionCount = len(out.Decisions)
+		if b, e := json.Marshal(out.Decisions); e == nil {
+			decisionsJSON = string(b)
+		}
+		allOK = false
+		ledger.settleRemaining(journal.ExecutionSkipped, GuardLatencyBudget)
+	} else if out != nil && decisionErr != nil {
+		decisionCount = len(out.Decisions)
+		if b, e := json.Marshal(out.Decisions); e == nil {
+			decisionsJSON = string(b)
+		}
+		allOK = false
+		ledger.settleRemaining(journal.ExecutionSkipped, "decision_error")
+	} else if out != nil {
+		decisionCount = len(out.Decisions)
+		if b, e := json.Marshal(out.Decisions); e == nil {
+			decisionsJSON = string(b)
+		}
+		decisions := m.applyDecisionGuards(t, withoutSymbols(out.Decisions, agedClosed), len(ectx.Positions)-len(agedClosed), m.now())
+		for i := range decisions {
+			d := decisions[i]
+			d.IdempotencyKey = decisionIntentKey(t.ID, cycle, d)
+			order, execErr := m.executeDecision(t, &d)
+			ledger.executed(d, order, execErr)
+			observeExecution(t.ID, d.Action, order, execErr)
+			act := map[string]any{
+				"symbol":            d.Symbol,
+				"action":            d.Action,
+				"leverage":          d.Leverage,
+				"position_size_usd": d.PositionSizeUSD,
+				"position_size_pct": d.PositionSizePct,
+				"close_fraction":    d.CloseFraction,
+				"entry_price":       d.EntryPrice,
+				"stop_loss":         d.StopLoss,
+				"take_profit":       d.TakeProfit,
+				"confidence":        d.Confidence,
+				"result":            "ok",
+			}
+			if order != nil && order.DryRun {
+				act["dry_run"] = true
+			}
+			if order != nil && order.Resting {
+				act["resting"] = true
+			}
+			if order != nil {
+				order.Protection.journalFields(act, d)
+			}
+			if execErr != nil {
+				act["result"] = "error"
+				act["error"] = execErr.Error()
+				allOK = false
+				logx.WithContext(ctx).Errorf("manager: trader %s decision action=%s symbol=%s error=%v", t.ID, d.Action, d.Symbol, execErr)
+				m.sendAlert(AlertExecutionError, t.ID, "%s %s failed: %v", d.Action, d.Symbol, execErr)
+			}
+			actions = append(actions, act)
+		}
+		ledger.settleRemaining(journal.ExecutionDropped, "manager_guards")
+	} else {
+		allOK = false
+		if decisionErr != nil {
+			logx.WithContext(ctx).Errorf("manager: trader %s decision generation failed: %v", t.ID, decisionErr)
+		}
+	}
+
+	// Trade statistics are updated from realized closes in ExecuteDecision;
+	// here we only publish the latest snapshot.
+	if t.Performance == nil {
+		t.Performance = &PerformanceMetrics{}
+	}
+	t.Performance.UpdatedAt = time.Now()
+	m.recordAnalytics(AnalyticsSnapshot{
+		TraderID:       t.ID,
+		TotalPnLUSD:    t.Performance.TotalPnLUSD,
+		TotalPnLPct:    t.Performance.TotalPnLPct,
+		SharpeRatio:    t.Performance.SharpeRatio,
+		WinRate:        t.Performance.WinRate,
+		TotalTrades:    t.Performance.TotalTrades,
+		MaxDrawdownPct: t.Performance.MaxDrawdownPct,
+		UpdatedAt:      t.Performance.UpdatedAt,
+	})
+
+	// Journal the cycle if configured
+	if t.Journal != nil && t.JournalEnabled {
+		if jErr := m.writeJournalRecord(t, &ectx, out, decisionsJSON, actions, ledger, journalErr, allOK); jErr != nil {
+			logx.WithContext(ctx).Errorf("manager: trader %s journal write failed: %v", t.ID, jErr)
+		} else {
+			logx.WithContext(ctx).Infof("manager: trader %s journal written prompt_digest=%s input_hash=%s", t.ID, outPromptDigest(out), executorpkg.InputHash(&ectx))
+		}
+	}
+	t.RecordDecision(m.now())
+	if syncErr := m.SyncTraderPositions(t.ID); syncErr != nil {
+		logx.WithContext(ctx).Errorf("manager: trader %s sync positions error: %v", t.ID, syncErr)
+	}
+	cycleDurationSeconds.WithLabelValues(t.ID).Observe(time.Since(cycleStart).Seconds())
+	logx.WithContext(ctx).Infof("manager: cycle trader=%s decisions=%d actions=%d ok=%t duration=%s", t.ID, decisionCount, len(actions), allOK && decisionErr == nil, time.Since(cycleStart).String())
 }
 
 // Stop signals the main loop to exit.
@@ -417,14 +664,33 @@ func (m *Manager) Stop() {
 // ExecuteDecision executes a single decision using trader's exchange provider.
 // Placeholder MVP: perform basic validation and return nil.
 func (m *Manager) ExecuteDecision(trader *VirtualTrader, decision *executorpkg.Decision) error {
+	_, err := m.executeDecision(trader, decision)
+	return err
+}
+
+// executeDecision does the work of ExecuteDecision and reports the order it
+// submitted; a nil order with a nil error means nothing was sent. A decision
+// whose IdempotencyKey already executed is skipped, so retrying the same
+// intent cannot place a second order; failed executions free the key.
+func (m *Manager) executeDecision(trader *VirtualTrader, decision *executorpkg.Decision) (_ *ExecutedOrder, err error) {
 	if trader == nil || decision == nil {
-		return errors.New("manager: execute decision requires trader and decision")
+		return nil, errors.New("manager: execute decision requires trader and decision")
+	}
+	if !trader.claimIntent(decision.IdempotencyKey, m.now()) {
+		m.recordGuardRejection(trader.ID, GuardDuplicateIntent, 1)
+		logx.Infof("manager: trader %s skip %s symbol=%s reason=duplicate_intent key=%s", trader.ID, decision.Action, decision.Symbol, decision.IdempotencyKey)
+		return nil, nil
 	}
+	defer func() {
+		if err != nil {
+			trader.releaseIntent(decision.IdempotencyKey)
+		}
+	}()
 	if decision.Symbol == "" && decision.Action != "hold" && decision.Action != "wait" {
-		return errors.New("manager: decision missing symbol")
+		return nil, errors.New("manager: decision missing symbol")
 	}
-	if decision.PositionSizeUSD < 0 {
-		return errors.New("manager: decision position size must be non-negative")
+	if decision.PositionSizeUSD < 0 || decision.PositionSizePct < 0 {
+		return nil, errors.New("manager: decision position size must be non-negative")
 	}
 
 	// Close actions shortcut via provider.
@@ -440,21 +706,46 @@ func (m *Manager) ExecuteDecision(trader *VirtualTrader, decision *executorpkg.D
 				_ = setter.SetMarkPrice(ctx, decision.Symbol, snap.Price.Last)
 			}
 		}
+		// Capture the position before closing so the close can be classified as a win or loss.
+		openPos := findPosition(ctx, trader, decision.Symbol)
+		if held := positionSide(openPos); held != "" && held != closeSide(decision.Action) {
+			logx.Infof("manager: trader %s reject close symbol=%s action=%s reason=side_mismatch held=%s", trader.ID, decision.Symbol, decision.Action, held)
+			m.recordGuardRejection(trader.ID, GuardCloseSide, 1)
+			return nil, fmt.Errorf("manager: trader %s %s %s rejected: position is %s", trader.ID, decision.Action, decision.Symbol, held)
+		}
+		if qty, partial := partialCloseQty(decision, openPos, closeSnapPrice); partial {
+			if residual, dust := belowMinResidual(trader, openPos, qty, closeSnapPrice); dust {
+				// The exchange would refuse to close what's left; take it all now.
+				logx.Infof("manager: trader %s close in full symbol=%s action=%s reason=residual_below_min_notional residual=%.2f min=%.2f", trader.ID, decision.Symbol, decision.Action, residual, minOrderNotionalUSD(trader))
+			} else {
+				return m.scaleOut(ctx, trader, decision, openPos, qty, closeSnapPrice)
+			}
+		}
+		if m.DryRun() {
+			return m.dryRunClose(trader, decision, openPos, closeSnapPrice), nil
+		}
 		// Attempt to cancel resting orders via optional extension
 		if p, ok := trader.ExchangeProvider.(interface {
 			CancelAllBySymbol(context.Context, string) error
 		}); ok {
 			_ = p.CancelAllBySymbol(ctx, decision.Symbol)
 		}
-		orderResp, err := trader.ExchangeProvider.ClosePosition(ctx, decision.Symbol)
+		if openPos != nil {
+			// Settle funding up to the close so net PnL includes it.
+			m.accrueFunding(ctx, trader, []exchange.Position{*openPos})
+		}
+		var orderResp *exchange.OrderResponse
+		var err error
+		if m.WindDown() || trader.OrderStyle == OrderStyleMarketIOC {
+			// market_ioc traders exit the way they enter: a reduce-only market IOC.
+			orderResp, err = reduceOnlyClose(ctx, trader, decision.Symbol)
+		} else {
+			orderResp, err = trader.ExchangeProvider.ClosePosition(ctx, decision.Symbol)
+		}
 		if err != nil {
-			return err
+			return nil, err
 		}
 		logx.Infof("manager: trader %s closed position symbol=%s action=%s", trader.ID, decision.Symbol, decision.Action)
-		// Mark cooldown timestamp on successful close
-		trader.mu.Lock()
-		trader.Cooldown[decision.Symbol] = time.Now()
-		trader.mu.Unlock()
 		fillPrice, fillQty, ok := parseOrderFill(orderResp)
 		if !ok {
 			fillPrice = closeSnapPrice
@@ -462,6 +753,19 @@ func (m *Manager) ExecuteDecision(trader *VirtualTrader, decision *executorpkg.D
 				fillPrice = decision.EntryPrice
 			}
 		}
+		// Mark cooldown timestamp on successful close; losing closes select the longer cooldown.
+		funding := trader.takeFunding(decision.Symbol)
+		pnl := closePnL(openPos, fillPrice) - funding
+		trader.mu.Lock()
+		trader.Cooldown[decision.Symbol] = m.now()
+		if trader.LossCooldown == nil {
+			trader.LossCooldown = make(map[string]bool)
+		}
+		trader.LossCooldown[decision.Symbol] = pnl < 0
+		trader.mu.Unlock()
+		if openPos != nil {
+			trader.recordClosedTrade(pnl)
+		}
 		if fillQty <= 0 && fillPrice > 0 && decision.PositionSizeUSD > 0 {
 			fillQty = decision.PositionSizeUSD / fillPrice
 		}
@@ -473,20 +777,80 @@ func (m *Manager) ExecuteDecision(trader *VirtualTrader, decision *executorpkg.D
 			ExchangeResponse: orderResp,
 			FillPrice:        fillPrice,
 			FillSize:         fillQty,
+			FundingUSD:       funding,
 			OccurredAt:       time.Now(),
 		})
-		return nil
+		return &ExecutedOrder{
+			Symbol:      decision.Symbol,
+			Action:      decision.Action,
+			Price:       fillPrice,
+			Size:        fillQty,
+			NotionalUSD: fillPrice * fillQty,
+			ReduceOnly:  true,
+		}, nil
 	}
 
 	if decision.Action != "open_long" && decision.Action != "open_short" {
 		// Ignore non-trade actions (e.g., hold/wait).
-		return nil
+		return nil, nil
+	}
+
+	if m.WindDown() {
+		m.recordGuardRejection(trader.ID, GuardWindDown, 1)
+		return nil, fmt.Errorf("manager: trader %s %s %s suppressed: wind-down mode active", trader.ID, decision.Action, decision.Symbol)
+	}
+
+	trader.mu.RLock()
+	until, cooling := trader.cooldownUntil(decision.Symbol, m.now())
+	trader.mu.RUnlock()
+	if cooling {
+		m.recordGuardRejection(trader.ID, GuardCooldown, 1)
+		return nil, fmt.Errorf("manager: trader %s %s %s rejected: cooling down until %s", trader.ID, decision.Action, decision.Symbol, until.Format(time.RFC3339))
+	}
+
+	if skip, err := m.gateDuplicatePosition(trader, decision); skip || err != nil {
+		return nil, err
+	}
+
+	if decision.PositionSizePct > 0 {
+		if err := resolvePctPositionSize(trader, decision); err != nil {
+			return nil, err
+		}
+	}
+	if err := m.applySizingMode(trader, decision); err != nil {
+		return nil, err
+	}
+
+	if err := requireProtectiveOrders(trader.RiskParams, decision); err != nil {
+		m.recordGuardRejection(trader.ID, GuardSLTPRequired, 1)
+		return nil, fmt.Errorf("manager: trader %s %s %s rejected: %w", trader.ID, decision.Action, decision.Symbol, err)
+	}
+
+	if minNotional := minOrderNotionalUSD(trader); decision.PositionSizeUSD < minNotional {
+		m.recordGuardRejection(trader.ID, GuardMinNotional, 1)
+		logx.Infof("manager: trader %s reject %s symbol=%s reason=below_min_notional size=%.2f min=%.2f", trader.ID, decision.Action, decision.Symbol, decision.PositionSizeUSD, minNotional)
+		return nil, fmt.Errorf("manager: trader %s %s %s rejected: size %.2f usd below min_order_notional_usd %.2f", trader.ID, decision.Action, decision.Symbol, decision.PositionSizeUSD, minNotional)
 	}
 
 	// Enforce per-trader caps.
 	if trader.RiskParams.MaxPositionSizeUSD > 0 && decision.PositionSizeUSD > trader.RiskParams.MaxPositionSizeUSD+1e-6 {
-		return fmt.Errorf("manager: decision size %.2f exceeds max_position_size_usd %.2f", decision.PositionSizeUSD, trader.RiskParams.MaxPositionSizeUSD)
+		m.recordGuardRejection(trader.ID, GuardMaxPositionSize, 1)
+		return nil, fmt.Errorf("manager: decision size %.2f exceeds max_position_size_usd %.2f", decision.PositionSizeUSD, trader.RiskParams.MaxPositionSizeUSD)
+	}
+
+	// Defer a second open while an earlier one for the symbol is not yet
+	// reflected in positions; the marker stays only once an order is live.
+	if !trader.claimOpen(decision.Symbol, m.now()) {
+		m.recordGuardRejection(trader.ID, GuardInFlightOpen, 1)
+		logx.Infof("manager: trader %s defer %s symbol=%s reason=open_in_flight", trader.ID, decision.Action, decision.Symbol)
+		return nil, nil
 	}
+	pending := false
+	defer func() {
+		if !pending {
+			trader.releaseOpen(decision.Symbol)
+		}
+	}()
 
 	// Resolve leverage preference.
 	lev := decision.Leverage
@@ -499,22 +863,51 @@ func (m *Manager) ExecuteDecision(trader *VirtualTrader, decision *executorpkg.D
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
+	activeLev := 0
 	assetIdx, err := trader.ExchangeProvider.GetAssetIndex(ctx, decision.Symbol)
-	if err == nil && lev > 0 {
-		_ = trader.ExchangeProvider.UpdateLeverage(ctx, assetIdx, true, lev)
+	meta, _ := lookupAssetMeta(ctx, trader, decision.Symbol)
+	if maxLev := int(meta.MaxLeverage); maxLev > 0 && lev > maxLev {
+		logx.WithContext(ctx).Infof("manager: trader %s clamp leverage symbol=%s requested=%d max=%d", trader.ID, decision.Symbol, lev, maxLev)
+		lev = maxLev
+	}
+	switch {
+	case err != nil || lev <= 0:
+	case m.DryRun():
+		activeLev = lev
+		logx.WithContext(ctx).Infof("manager: trader %s dry-run skip update leverage symbol=%s asset_idx=%d leverage=%d", trader.ID, decision.Symbol, assetIdx, lev)
+	case !(meta.MaxLeverage > 0):
+		// Without the asset cap the exchange may reject the update; keep the
+		// account's current leverage rather than spend the call.
+		logx.WithContext(ctx).Infof("manager: trader %s skip update leverage symbol=%s leverage=%d reason=max_leverage_unknown", trader.ID, decision.Symbol, lev)
+	default:
+		isCross := useCrossMargin(trader.MarginMode, meta)
+		if err := trader.ExchangeProvider.UpdateLeverage(ctx, assetIdx, isCross, lev); err != nil {
+			logx.WithContext(ctx).Errorf("manager: update leverage trader=%s symbol=%s lev=%d err=%v", trader.ID, decision.Symbol, lev, err)
+		} else {
+			activeLev = lev
+			m.recordLeverageChange(LeverageChange{
+				TraderID:  trader.ID,
+				Symbol:    decision.Symbol,
+				Asset:     assetIdx,
+				Mode:      marginModeLabel(isCross),
+				Leverage:  lev,
+				ChangedAt: time.Now(),
+			})
+		}
 	}
 
 	// Determine price: use decision price or query market snapshot.
 	price := decision.EntryPrice
+	var snap *market.Snapshot
 	if !(price > 0) {
-		snap, err := trader.MarketProvider.Snapshot(ctx, decision.Symbol)
+		snap, err = trader.MarketProvider.Snapshot(ctx, decision.Symbol)
 		if err != nil {
-			return fmt.Errorf("manager: fetch market snapshot for %s: %w", decision.Symbol, err)
+			return nil, fmt.Errorf("manager: fetch market snapshot for %s: %w", decision.Symbol, err)
 		}
 		price = snap.Price.Last
 	}
 	if !(price > 0) {
-		return fmt.Errorf("manager: invalid price resolved for %s", decision.Symbol)
+		return nil, fmt.Errorf("manager: invalid price resolved for %s", decision.Symbol)
 	}
 
 	// Compute size and direction.
@@ -528,55 +921,48 @@ func (m *Manager) ExecuteDecision(trader *VirtualTrader, decision *executorpkg.D
 
 	qty := decision.PositionSizeUSD / price
 	if qty <= 0 || math.IsNaN(qty) || math.IsInf(qty, 0) {
-		return fmt.Errorf("manager: invalid position size for %s: qty=%.6f", decision.Symbol, qty)
+		return nil, fmt.Errorf("manager: invalid position size for %s: qty=%.6f", decision.Symbol, qty)
 	}
 	isBuy := decision.Action == "open_long"
-	priceStr := fmt.Sprintf("%.8f", price)
-	sizeStr := fmt.Sprintf("%.8f", qty)
 	var orderResp *exchange.OrderResponse
+	resting := false
 
 	switch trader.OrderStyle {
 	case OrderStyleMarketIOC:
-		slippage := trader.MarketIOCSlippageBps / 10000.0
-		if slippage <= 0 {
-			slippage = defaultMarketIOCSlippageBps / 10000.0
-		}
+		slippage := marketIOCSlippageBps(trader) / 10000.0
 		execProvider, ok := trader.ExchangeProvider.(interface {
 			IOCMarket(context.Context, string, bool, float64, float64, bool) (*exchange.OrderResponse, error)
 		})
 		if !ok {
-			return fmt.Errorf("manager: trader %s order_style=market_ioc unsupported by exchange provider", trader.ID)
+			return nil, fmt.Errorf("manager: trader %s order_style=market_ioc unsupported by exchange provider", trader.ID)
+		}
+		if err := m.gateMarketSlippage(ctx, trader, decision.Symbol, isBuy, decision.PositionSizeUSD, snap); err != nil {
+			return nil, err
 		}
 		logx.WithContext(ctx).Infof(
 			"manager: trader %s prepared market_ioc order symbol=%s is_buy=%t raw_price=%.8f raw_qty=%.8f asset_idx=%d leverage=%d",
 			trader.ID, decision.Symbol, isBuy, price, qty, assetIdx, lev,
 		)
+		if m.DryRun() {
+			logx.WithContext(ctx).Infof("manager: trader %s dry-run market_ioc order symbol=%s is_buy=%t qty=%.8f slippage=%.6f reduce_only=false", trader.ID, decision.Symbol, isBuy, qty, slippage)
+			break
+		}
 		resp, err := execProvider.IOCMarket(ctx, decision.Symbol, isBuy, qty, slippage, false)
 		if err != nil {
-			return fmt.Errorf("manager: market_ioc order %s %s: %w", decision.Symbol, decision.Action, err)
+			return nil, fmt.Errorf("manager: market_ioc order %s %s: %w", decision.Symbol, decision.Action, err)
 		}
 		orderResp = resp
 		summary := summarizeOrderResponse(resp)
 		logx.Infof("manager: trader %s submitted market_ioc order symbol=%s notional=%.2f usd qty=%.6f slippage_bps=%.2f response=%s", trader.ID, decision.Symbol, decision.PositionSizeUSD, qty, trader.MarketIOCSlippageBps, summary)
-	case OrderStyleLimitIOC, "":
-		if p, ok := trader.ExchangeProvider.(interface {
-			FormatPrice(context.Context, string, float64) (string, error)
-		}); ok {
-			if s, err := p.FormatPrice(ctx, decision.Symbol, price); err == nil && s != "" {
-				priceStr = s
-			} else if err != nil {
-				logx.WithContext(ctx).Infof("manager: format price fallback trader=%s symbol=%s price=%.8f err=%v", trader.ID, decision.Symbol, price, err)
-			}
-		}
-		if p, ok := trader.ExchangeProvider.(interface {
-			FormatSize(context.Context, string, float64) (string, error)
-		}); ok {
-			if s, err := p.FormatSize(ctx, decision.Symbol, qty); err == nil && s != "" {
-				sizeStr = s
-			} else if err != nil {
-				logx.WithContext(ctx).Infof("manager: format size fallback trader=%s symbol=%s qty=%.8f err=%v", trader.ID, decision.Symbol, qty, err)
-			}
+	case OrderStyleLimitIOC, OrderStylePostOnly, "":
+		style, tif := OrderStyleLimitIOC, "Ioc"
+		if trader.OrderStyle == OrderStylePostOnly {
+			// Rest behind the reference price; Alo rejects rather than crosses.
+			style, tif = OrderStylePostOnly, "Alo"
+			price = postOnlyPrice(price, isBuy, trader.PostOnlyOffsetBps)
 		}
+		priceStr := formatOrderPrice(ctx, trader, decision.Symbol, price)
+		sizeStr := formatOrderSize(ctx, trader, decision.Symbol, qty)
 
 		cloid := buildCloid(trader.ID, decision.Symbol, decision.Action, qty, time.Now())
 		order := exchange.Order{
@@ -585,50 +971,143 @@ func (m *Manager) ExecuteDecision(trader *VirtualTrader, decision *executorpkg.D
 			LimitPx:    priceStr,
 			Sz:         sizeStr,
 			ReduceOnly: false,
-			OrderType:  exchange.OrderType{Limit: &exchange.LimitOrderType{TIF: "Ioc"}},
+			OrderType:  exchange.OrderType{Limit: &exchange.LimitOrderType{TIF: tif}},
 			Cloid:      cloid,
 		}
 		logx.WithContext(ctx).Infof(
-			"manager: trader %s prepared limit_ioc order symbol=%s is_buy=%t raw_price=%.8f price_str=%s raw_qty=%.8f size_str=%s asset_idx=%d leverage=%d",
-			trader.ID, decision.Symbol, isBuy, price, priceStr, qty, sizeStr, assetIdx, lev,
+			"manager: trader %s prepared %s order symbol=%s is_buy=%t raw_price=%.8f price_str=%s raw_qty=%.8f size_str=%s asset_idx=%d leverage=%d",
+			trader.ID, style, decision.Symbol, isBuy, price, priceStr, qty, sizeStr, assetIdx, lev,
 		)
-		resp, err := trader.ExchangeProvider.PlaceOrder(ctx, order)
+		if m.DryRun() {
+			logx.WithContext(ctx).Infof("manager: trader %s dry-run %s order symbol=%s order=%s", trader.ID, style, decision.Symbol, formatDryRunOrder(order))
+			break
+		}
+		resp, err := m.placeOrderWithRetry(ctx, trader, order)
 		if err != nil {
-			return fmt.Errorf("manager: place order %s %s: %w", decision.Symbol, decision.Action, err)
+			return nil, fmt.Errorf("manager: place order %s %s: %w", decision.Symbol, decision.Action, err)
 		}
 		orderResp = resp
 		summary := summarizeOrderResponse(resp)
-		logx.Infof("manager: trader %s submitted limit_ioc order symbol=%s notional=%.2f usd qty=%.6f cloid=%s response=%s", trader.ID, decision.Symbol, decision.PositionSizeUSD, qty, cloid, summary)
+		logx.Infof("manager: trader %s submitted %s order symbol=%s notional=%.2f usd qty=%.6f cloid=%s response=%s", trader.ID, style, decision.Symbol, decision.PositionSizeUSD, qty, cloid, summary)
+		if style == OrderStylePostOnly && isResting(resp) {
+			resting = true
+			trader.trackRestingOrder(cloid, restingOrder{
+				Symbol:   decision.Symbol,
+				Asset:    assetIdx,
+				IsBuy:    isBuy,
+				Price:    price,
+				PlacedAt: m.now(),
+				Oid:      restingOid(resp),
+				Size:     qty,
+				Leverage: activeLev,
+				Decision: *decision,
+			})
+		}
 	default:
-		return fmt.Errorf("manager: trader %s unsupported order_style=%s", trader.ID, trader.OrderStyle)
-	}
-	// Configure reduce-only SL/TP best-effort
-	side := "LONG"
-	if !isBuy { // open_short
-		side = "SHORT"
-	}
-	// Best-effort SL/TP via optional provider extension.
-	if p, ok := trader.ExchangeProvider.(interface {
-		SetStopLoss(context.Context, string, string, float64, float64) error
-		SetTakeProfit(context.Context, string, string, float64, float64) error
-	}); ok {
-		_ = p.SetStopLoss(ctx, decision.Symbol, side, qty, decision.StopLoss)
-		_ = p.SetTakeProfit(ctx, decision.Symbol, side, qty, decision.TakeProfit)
+		return nil, fmt.Errorf("manager: trader %s unsupported order_style=%s", trader.ID, trader.OrderStyle)
+	}
+	executed := &ExecutedOrder{
+		Symbol:      decision.Symbol,
+		Action:      decision.Action,
+		Price:       price,
+		Size:        qty,
+		NotionalUSD: price * qty,
+		Leverage:    activeLev,
+		OrderStyle:  trader.OrderStyle,
+		DryRun:      m.DryRun(),
+	}
+	if executed.OrderStyle == "" {
+		executed.OrderStyle = OrderStyleLimitIOC
+	}
+	if executed.DryRun {
+		logx.WithContext(ctx).Infof("manager: trader %s dry-run skip sl/tp symbol=%s stop_loss=%.8f take_profit=%.8f", trader.ID, decision.Symbol, decision.StopLoss, decision.TakeProfit)
+		return executed, nil
+	}
+	pending = true
+	if resting {
+		// Nothing is held yet: the open is booked as fills are observed.
+		executed.Resting = true
+		logx.Infof("manager: trader %s post_only order resting symbol=%s qty=%.6f; booking deferred until filled", trader.ID, decision.Symbol, qty)
+		return executed, nil
+	}
+	fee := fillFee{Maker: trader.OrderStyle == OrderStylePostOnly}
+	executed.Protection, err = m.bookOpenFill(ctx, trader, decision, orderResp, price, qty, qty, activeLev, true, fee)
+	return executed, err
+}
+
+// fillFee is what is known about the commission on an open's fills.
+type fillFee struct {
+	USD   float64 // venue-reported commission; 0 when unknown
+	Maker bool    // the fills added liquidity (post_only)
+}
+
+// bookOpenFill records a filled open: funding and age tracking start on the
+// first fill, an open event carries the position's total filled size, and
+// reduce-only SL/TP cover the newly filled qty. fee describes the commission
+// on the total filled size. When a required stop loss
+// fails to register the position is closed straight away.
+func (m *Manager) bookOpenFill(ctx context.Context, trader *VirtualTrader, decision *executorpkg.Decision, resp *exchange.OrderResponse, price, qty, totalQty float64, lev int, firstFill bool, fee fillFee) (*ProtectiveOrders, error) {
+	if firstFill {
+		trader.startFunding(decision.Symbol, m.now())
+		trader.markPositionOpened(decision.Symbol, m.now())
 	}
 	m.recordPositionEvent(PositionEvent{
 		TraderID:         trader.ID,
 		Trader:           trader,
 		Decision:         *decision,
 		Event:            PositionEventOpen,
-		ExchangeResponse: orderResp,
+		ExchangeResponse: resp,
 		FillPrice:        price,
-		FillSize:         qty,
+		FillSize:         totalQty,
+		Leverage:         lev,
+		FeeUSD:           fee.USD,
+		Maker:            fee.Maker,
 		OccurredAt:       time.Now(),
 	})
-	return nil
+	// Configure reduce-only SL/TP via optional provider extension.
+	side := "LONG"
+	if decision.Action == "open_short" {
+		side = "SHORT"
+	}
+	prot := m.placeProtectiveOrders(ctx, trader, decision, side, qty)
+	if prot != nil && prot.StopLossError != "" && trader.RiskParams.StopLossEnabled {
+		// A required stop loss is missing: do not leave the position unprotected.
+		if err := m.closeUnprotected(ctx, trader, decision.Symbol); err != nil {
+			return prot, fmt.Errorf("manager: trader %s %s %s: stop loss placement failed (%s) and %w", trader.ID, decision.Action, decision.Symbol, prot.StopLossError, err)
+		}
+		prot.ClosedUnprotected = true
+		trader.releaseOpen(decision.Symbol)
+		logx.Infof("manager: trader %s closed unprotected position symbol=%s reason=stop_loss_placement_failed", trader.ID, decision.Symbol)
+		return prot, fmt.Errorf("manager: trader %s %s %s: stop loss placement failed, position closed: %s", trader.ID, decision.Action, decision.Symbol, prot.StopLossError)
+	}
+	return prot, nil
+}
+
+const (
+	defaultSyncConcurrency = 4
+	defaultSyncTimeout     = 10 * time.Second
+)
+
+// syncConcurrency is how many traders SyncAllPositions syncs at once.
+func (m *Manager) syncConcurrency() int {
+	if m.config == nil || m.config.Manager.SyncConcurrency <= 0 {
+		return defaultSyncConcurrency
+	}
+	return m.config.Manager.SyncConcurrency
 }
 
-// SyncAllPositions updates cached account/position state for all traders (stub).
+// syncTimeout bounds a single trader's account sync.
+func (m *Manager) syncTimeout() time.Duration {
+	if m.config == nil || m.config.Manager.SyncTimeout <= 0 {
+		return defaultSyncTimeout
+	}
+	return m.config.Manager.SyncTimeout
+}
+
+// SyncAllPositions updates cached account/position state for all traders.
+// Traders sync in parallel on up to manager.sync_concurrency workers, each
+// bounded by manager.sync_timeout, so one slow exchange account does not hold
+// up the rest. It returns the joined errors of the traders that failed.
 func (m *Manager) SyncAllPositions() error {
 	m.mu.RLock()
 	ids := make([]string, 0, len(m.traders))
@@ -636,13 +1115,30 @@ func (m *Manager) SyncAllPositions() error {
 		ids = append(ids, id)
 	}
 	m.mu.RUnlock()
-	for _, id := range ids {
-		_ = m.SyncTraderPositions(id)
+	sort.Strings(ids)
+
+	errs := make([]error, len(ids))
+	sem := make(chan struct{}, m.syncConcurrency())
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, id string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := m.SyncTraderPositions(id); err != nil {
+				errs[i] = fmt.Errorf("trader %s: %w", id, err)
+			}
+		}(i, id)
 	}
-	return nil
+	wg.Wait()
+	return errors.Join(errs...)
 }
 
-// SyncTraderPositions updates a single trader's cached state (stub).
+// SyncTraderPositions updates a single trader's cached state from its
+// exchange account, giving up after manager.sync_timeout.
 func (m *Manager) SyncTraderPositions(traderID string) error {
 	m.mu.RLock()
 	t := m.traders[traderID]
@@ -650,18 +1146,26 @@ func (m *Manager) SyncTraderPositions(traderID string) error {
 	if t == nil {
 		return fmt.Errorf("manager: sync positions: trader %s not found", traderID)
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), m.syncTimeout())
 	defer cancel()
-	acct, err := t.ExchangeProvider.GetAccountState(ctx)
+	acct, err := fetchAccountStateWithRetry(ctx, t.ExchangeProvider)
 	if err != nil {
 		return err
 	}
 	// Parse commonly used fields from strings.
 	acctVal := parseFloat(acct.MarginSummary.AccountValue)
 	marginUsed := parseFloat(acct.MarginSummary.TotalMarginUsed)
+	m.haltOnDepletedEquity(t, acctVal)
+	t.settleOpens(acct.AssetPositions, m.now())
+	t.recordEquity(m.now(), acctVal)
+	t.observeDailyLoss(m.now(), acctVal)
 	var unreal float64
+	open := 0
 	for i := range acct.AssetPositions {
 		unreal += parseFloat(acct.AssetPositions[i].UnrealizedPnl)
+		if parseFloat(acct.AssetPositions[i].Szi) != 0 {
+			open++
+		}
 	}
 
 	t.mu.Lock()
@@ -669,8 +1173,11 @@ func (m *Manager) SyncTraderPositions(traderID string) error {
 	t.ResourceAlloc.MarginUsedUSD = marginUsed
 	t.ResourceAlloc.UnrealizedPnLUSD = unreal
 	t.ResourceAlloc.AvailableBalanceUSD = math.Max(0, acctVal-marginUsed)
+	t.ResourceAlloc.OpenPositions = open
+	realized := t.ResourceAlloc.RealizedPnLUSD
 	t.UpdatedAt = time.Now()
 	t.mu.Unlock()
+	observeAccount(traderID, acctVal, marginUsed)
 	logx.Infof("manager: trader %s equity=%.2f usd margin_used=%.2f usd avail=%.2f usd unreal_pnl=%.2f usd", traderID, acctVal, marginUsed, t.ResourceAlloc.AvailableBalanceUSD, unreal)
 	m.recordAccountSnapshot(AccountSyncSnapshot{
 		TraderID:            traderID,
@@ -678,8 +1185,15 @@ func (m *Manager) SyncTraderPositions(traderID string) error {
 		MarginUsedUSD:       marginUsed,
 		AvailableBalanceUSD: t.ResourceAlloc.AvailableBalanceUSD,
 		UnrealizedPnLUSD:    unreal,
+		RealizedPnLUSD:      realized,
 		SyncedAt:            time.Now(),
 	})
+	if _, err := m.ReconcileStalePositions(ctx, traderID, acct.AssetPositions); err != nil {
+		logx.Errorf("manager: trader %s stale position reconciliation failed: %v", traderID, err)
+	}
+	m.accrueFunding(ctx, t, acct.AssetPositions)
+	t.pruneFunding(acct.AssetPositions)
+	t.observePositionAges(acct.AssetPositions, m.now())
 	if t.Performance != nil {
 		m.recordAnalytics(AnalyticsSnapshot{
 			TraderID:       traderID,
@@ -695,6 +1209,35 @@ func (m *Manager) SyncTraderPositions(traderID string) error {
 	return nil
 }
 
+const (
+	accountStateAttempts    = 3
+	accountStateBaseBackoff = 250 * time.Millisecond
+)
+
+// fetchAccountStateWithRetry retries transient GetAccountState failures with
+// exponential backoff, giving up early when ctx expires.
+func fetchAccountStateWithRetry(ctx context.Context, provider exchange.Provider) (*exchange.AccountState, error) {
+	var lastErr error
+	backoff := accountStateBaseBackoff
+	for attempt := 1; attempt <= accountStateAttempts; attempt++ {
+		acct, err := provider.GetAccountState(ctx)
+		if err == nil {
+			return acct, nil
+		}
+		lastErr = err
+		if attempt == accountStateAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("manager: get account state: %w (last error: %v)", ctx.Err(), lastErr)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return nil, fmt.Errorf("manager: get account state after %d attempts: %w", accountStateAttempts, lastErr)
+}
+
 func parseFloat(s string) float64 {
 	if s == "" {
 		return 0
@@ -781,6 +1324,65 @@ func parseOrderFill(resp *exchange.OrderResponse) (price float64, qty float64, o
 	return 0, 0, false
 }
 
+// findPosition returns the trader's live position in symbol, or nil when flat
+// or the lookup fails.
+func findPosition(ctx context.Context, t *VirtualTrader, symbol string) *exchange.Position {
+	positions, err := t.ExchangeProvider.GetPositions(ctx)
+	if err != nil {
+		return nil
+	}
+	for i := range positions {
+		if strings.EqualFold(positions[i].Coin, symbol) && parseFloat(positions[i].Szi) != 0 {
+			return &positions[i]
+		}
+	}
+	return nil
+}
+
+// positionSide reports "long" or "short" for an open position, or "" when
+// pos is nil or flat.
+func positionSide(pos *exchange.Position) string {
+	if pos == nil {
+		return ""
+	}
+	switch szi := parseFloat(pos.Szi); {
+	case szi > 0:
+		return "long"
+	case szi < 0:
+		return "short"
+	}
+	return ""
+}
+
+// closeSide maps close_long/close_short to the side they expect to hold.
+func closeSide(action string) string {
+	return strings.TrimPrefix(action, "close_")
+}
+
+// postOnlyPrice places a maker limit offsetBps behind the reference price:
+// below it for buys, above it for sells.
+func postOnlyPrice(price float64, isBuy bool, offsetBps float64) float64 {
+	offset := offsetBps / 10000.0
+	if isBuy {
+		return price * (1 - offset)
+	}
+	return price * (1 + offset)
+}
+
+// closePnL estimates the PnL realized by closing pos at exitPrice, falling back
+// to the position's last unrealized PnL when entry or exit price is unknown.
+func closePnL(pos *exchange.Position, exitPrice float64) float64 {
+	if pos == nil {
+		return 0
+	}
+	entry := parsePtrFloat(pos.EntryPx)
+	szi := parseFloat(pos.Szi)
+	if entry > 0 && exitPrice > 0 {
+		return (exitPrice - entry) * szi
+	}
+	return parseFloat(pos.UnrealizedPnl)
+}
+
 func (m *Manager) recordPositionEvent(event PositionEvent) {
 	if m == nil || m.persistence == nil {
 		return
@@ -801,6 +1403,24 @@ func (m *Manager) recordPositionEvent(event PositionEvent) {
 	})
 }
 
+func (m *Manager) recordLeverageChange(change LeverageChange) {
+	if m == nil || m.persistence == nil {
+		return
+	}
+	recorder, ok := m.persistence.(LeverageRecorder)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	err := recorder.RecordLeverageChange(ctx, change)
+	logPersistenceError(err, "leverage change persistence failed", map[string]any{
+		"trader_id": change.TraderID,
+		"symbol":    change.Symbol,
+		"leverage":  change.Leverage,
+	})
+}
+
 func (m *Manager) recordDecisionCycle(record DecisionCycleRecord) {
 	if m == nil || m.persistence == nil || record.Cycle == nil {
 		return
@@ -851,7 +1471,7 @@ func (m *Manager) recordAnalytics(snapshot AnalyticsSnapshot) {
 	})
 }
 
-func (m *Manager) writeJournalRecord(t *VirtualTrader, ectx *executorpkg.Context, out *executorpkg.FullDecision, decisionsJSON string, actions []map[string]any, callErr error, allOK bool) error {
+func (m *Manager) writeJournalRecord(t *VirtualTrader, ectx *executorpkg.Context, out *executorpkg.FullDecision, decisionsJSON string, actions []map[string]any, ledger *intentLedger, callErr error, allOK bool) error {
 	if t == nil || ectx == nil {
 		return nil
 	}
@@ -905,37 +1525,75 @@ func (m *Manager) writeJournalRecord(t *VirtualTrader, ectx *executorpkg.Context
 			promptDigest = llm.DigestString(s)
 		}
 	}
-	// candidates list as strings for compactness
+	// candidates list as strings for compactness, plus scores for selection analysis
 	var cand []string
+	var candScores []journal.CandidateScore
 	for _, c := range ectx.CandidateCoins {
 		cand = append(cand, c.Symbol)
+		candScores = append(candScores, journal.CandidateScore{Symbol: c.Symbol, Score: c.Score, Sources: c.Sources})
 	}
+	now := m.now()
 	rec := &journal.CycleRecord{
+		Timestamp:     now,
 		TraderID:      t.ID,
+		CycleID:       cycleID(t.ID, now),
 		PromptDigest:  promptDigest,
+		InputHash:     executorpkg.InputHash(ectx),
 		CoTTrace:      cot,
 		DecisionsJSON: decisionsJSON,
 		Account:       acc,
 		Positions:     pos,
 		Candidates:    cand,
+		CandidateSet:  candScores,
 		MarketDigest:  marketDigest,
 		Actions:       actions,
+		Guards:        m.effectiveGuards(t),
 		Success:       allOK && callErr == nil,
 	}
+	if ledger != nil {
+		rec.Intents = ledger.intents
+		rec.Executions = ledger.executions
+	}
 	if callErr != nil {
 		rec.ErrorMessage = callErr.Error()
+		// Tags from every matching cause are merged into one map.
+		extra := map[string]interface{}{}
+		// Refusals are model behaviour rather than parse failures; tag them for analysis.
+		var refusal *llm.RefusalError
+		if errors.As(callErr, &refusal) {
+			extra["model_refusal"] = true
+			extra["refusal_text"] = refusal.Text
+		}
+		if errors.Is(callErr, llm.ErrBudgetExceeded) {
+			extra["cycle_skipped"] = true
+			extra["skip_reason"] = GuardLLMSpendBudget
+		}
+		if len(extra) > 0 {
+			rec.Extra = extra
+		}
 	}
 	var err error
 	if t.Journal != nil {
 		_, err = t.Journal.WriteCycle(rec)
 	}
-	m.recordDecisionCycle(DecisionCycleRecord{
+	record := DecisionCycleRecord{
 		TraderID: t.ID,
 		Cycle:    rec,
-	})
+	}
+	if t.PersistPrompts && out != nil {
+		record.Prompt = out.UserPrompt
+		record.Usage = out.Usage
+	}
+	m.recordDecisionCycle(record)
 	return err
 }
 
+// cycleID identifies a decision cycle across the journal, the
+// decision_cycles table and its linked conversation.
+func cycleID(traderID string, at time.Time) string {
+	return fmt.Sprintf("%s-%d", traderID, at.UnixNano())
+}
+
 // buildCloid creates a stable client order id for idempotent intent submission.
 func buildCloid(traderID, symbol, action string, qty float64, now time.Time) string {
 	// Bucket time to minute to avoid collision across cycles; include rounded qty to 6 dp.
@@ -947,8 +1605,10 @@ func buildCloid(traderID, symbol, action string, qty float64, now time.Time) str
 	return "0x" + hex.EncodeToString(sum[:16])
 }
 
-// buildExecutorContext collects a richer snapshot for the executor prompt and validation.
-func (m *Manager) buildExecutorContext(t *VirtualTrader) executorpkg.Context {
+// buildExecutorContext collects a richer snapshot for the executor prompt and
+// validation. accountOK reports whether the account state was fetched, so
+// callers can tell a depleted account from a failed fetch.
+func (m *Manager) buildExecutorContext(t *VirtualTrader, snaps snapshotCache) (executorpkg.Context, bool) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -966,7 +1626,7 @@ func (m *Manager) buildExecutorContext(t *VirtualTrader) executorpkg.Context {
 		for i := range acctState.AssetPositions {
 			account.TotalPnL += parseFloat(acctState.AssetPositions[i].UnrealizedPnl)
 		}
-		if account.TotalEquity != 0 {
+		if account.TotalEquity > 0 {
 			account.MarginUsedPct = 100 * (account.MarginUsed / account.TotalEquity)
 			account.TotalPnLPct = 100 * (account.TotalPnL / account.TotalEquity)
 		}
@@ -975,43 +1635,33 @@ func (m *Manager) buildExecutorContext(t *VirtualTrader) executorpkg.Context {
 	// Normalize positions (first pass: collect symbols and static fields)
 	positions := make([]executorpkg.PositionInfo, 0, len(positionsRaw))
 	symbols := make(map[string]struct{})
+	entryTimes := t.observePositionAges(positionsRaw, m.now())
 	for i := range positionsRaw {
 		p := positionsRaw[i]
-		side := "long"
-		qty := parseFloat(p.Szi)
-		if qty < 0 {
-			side = "short"
-			qty = -qty
-		}
-		positions = append(positions, executorpkg.PositionInfo{
-			Symbol:           p.Coin,
-			Side:             side,
-			EntryPrice:       parsePtrFloat(p.EntryPx),
-			MarkPrice:        0,
-			Quantity:         qty,
-			Leverage:         p.Leverage.Value,
-			UnrealizedPnL:    parseFloat(p.UnrealizedPnl),
-			LiquidationPrice: parsePtrFloat(p.LiquidationPx),
-		})
+		info := positionInfo(p)
+		if at, ok := entryTimes[fundingKey(p.Coin)]; ok {
+			info.EntryTimeMs = at.UnixMilli()
+		}
+		positions = append(positions, info)
 		symbols[p.Coin] = struct{}{}
 	}
 	account.PositionCount = len(positions)
 
 	// 2) Candidate set (basic Top-N by |1h change|) and market snapshots
-	candidates := m.selectCandidates(ctx, t, 0)
-	snaps := map[string]*market.Snapshot{}
+	candidates := m.selectCandidates(ctx, t, 0, snaps)
+	wanted := make([]string, 0, len(symbols)+len(candidates))
 	for sym := range symbols {
-		if s, err := t.MarketProvider.Snapshot(ctx, sym); err == nil && s != nil {
-			snaps[sym] = s
-		}
+		wanted = append(wanted, sym)
 	}
-	// Snapshots for candidates
 	for _, c := range candidates {
-		if _, ok := snaps[c.Symbol]; ok { // already fetched
-			continue
-		}
-		if s, err := t.MarketProvider.Snapshot(ctx, c.Symbol); err == nil && s != nil {
-			snaps[c.Symbol] = s
+		wanted = append(wanted, c.Symbol)
+	}
+	// Candidates are already cached by ranking; only position symbols may be fetched here.
+	snaps.fetch(ctx, t.MarketProvider, wanted)
+	marketData := make(map[string]*market.Snapshot, len(wanted))
+	for _, sym := range wanted {
+		if s := snaps[sym]; s != nil {
+			marketData[sym] = s
 		}
 	}
 
@@ -1019,7 +1669,7 @@ func (m *Manager) buildExecutorContext(t *VirtualTrader) executorpkg.Context {
 	for i := range positions {
 		pi := &positions[i]
 		if pi.EntryPrice > 0 {
-			if s, ok := snaps[pi.Symbol]; ok && s != nil && s.Price.Last > 0 {
+			if s, ok := marketData[pi.Symbol]; ok && s != nil && s.Price.Last > 0 {
 				pi.MarkPrice = s.Price.Last
 				pi.UnrealizedPnLPct = 100 * (pi.MarkPrice - pi.EntryPrice) / pi.EntryPrice
 			}
@@ -1033,24 +1683,7 @@ func (m *Manager) buildExecutorContext(t *VirtualTrader) executorpkg.Context {
 			if _, want := symbols[a.Symbol]; !want {
 				continue
 			}
-			ml := 0.0
-			onlyIso := false
-			if a.RawMetadata != nil {
-				if v, ok := a.RawMetadata["maxLeverage"]; ok {
-					switch x := v.(type) {
-					case float64:
-						ml = x
-					case int:
-						ml = float64(x)
-					}
-				}
-				if v, ok := a.RawMetadata["onlyIsolated"]; ok {
-					if b, ok := v.(bool); ok {
-						onlyIso = b
-					}
-				}
-			}
-			assetMeta[a.Symbol] = executorpkg.AssetMeta{MaxLeverage: ml, Precision: a.Precision, OnlyIsolated: onlyIso}
+			assetMeta[a.Symbol] = assetMetaOf(a)
 		}
 	}
 
@@ -1062,7 +1695,7 @@ func (m *Manager) buildExecutorContext(t *VirtualTrader) executorpkg.Context {
 		Account:           account,
 		Positions:         positions,
 		CandidateCoins:    candidates,
-		MarketDataMap:     snaps,
+		MarketDataMap:     marketData,
 		OpenInterestMap:   nil,
 		Performance:       t.Performance.ToExecutorView(),
 		MajorCoinLeverage: t.RiskParams.MajorCoinLeverage,
@@ -1081,6 +1714,7 @@ func (m *Manager) buildExecutorContext(t *VirtualTrader) executorpkg.Context {
 			}
 			return 0
 		}(),
+		MaxAbsFundingRate: fundingGuardRate(t.ExecGuards),
 		BTCETHPositionValueMinMultiple: func() float64 {
 			if t.ExecGuards.EnableValueBandGuard == nil || *t.ExecGuards.EnableValueBandGuard {
 				return t.ExecGuards.BTCETHMinEquityMultiple
@@ -1111,12 +1745,49 @@ func (m *Manager) buildExecutorContext(t *VirtualTrader) executorpkg.Context {
 			}
 			return 0
 		}(),
+	}, acctState != nil
+}
+
+// defaultMinSnapshotCoveragePct is used when the sparse-data guard is enabled
+// without an explicit threshold.
+const defaultMinSnapshotCoveragePct = 50.0
+
+// sparseMarketData reports whether too few candidates carry market snapshots
+// for the model to decide on, together with the observed coverage percentage.
+func sparseMarketData(t *VirtualTrader, snaps snapshotCache) (bool, float64) {
+	if len(snaps) == 0 {
+		return false, 100
+	}
+	if t.ExecGuards.EnableSparseDataGuard != nil && !*t.ExecGuards.EnableSparseDataGuard {
+		return false, 100
+	}
+	threshold := t.ExecGuards.MinSnapshotCoveragePct
+	if threshold <= 0 {
+		threshold = defaultMinSnapshotCoveragePct
 	}
+	coverage := snaps.coverage()
+	return coverage+1e-9 < threshold, coverage
 }
 
-// selectCandidates picks up to limit candidates using a simple heuristic (|1h change| ranking).
-// If limit == 0, uses ExecGuards.CandidateLimit (defaults to 10 when <=0). Applies liquidity threshold when enabled.
-func (m *Manager) selectCandidates(ctx context.Context, t *VirtualTrader, limit int) []executorpkg.CandidateCoin {
+// decisionLatencyBudget returns the maximum time allowed between building the
+// executor context and receiving a decision, or 0 when the budget is disabled.
+func decisionLatencyBudget(t *VirtualTrader) time.Duration {
+	pct := t.ExecGuards.DecisionLatencyBudgetPct
+	if pct <= 0 || t.DecisionInterval <= 0 {
+		return 0
+	}
+	return time.Duration(float64(t.DecisionInterval) * pct / 100)
+}
+
+// maxCandidateAssets bounds how many assets are snapshotted for ranking per cycle.
+const maxCandidateAssets = 200
+
+// selectCandidates picks up to limit candidates ranked by ExecGuards.CandidateRanking
+// (|1h change| by default); assets missing the indicators a mode needs are skipped.
+// If limit == 0, uses ExecGuards.CandidateLimit (defaults to 10 when <=0). Applies liquidity threshold when enabled
+// and down-ranks candidates whose funding exceeds the funding guard.
+// Snapshots are fetched in parallel into snaps (allocated when nil) for reuse within the cycle.
+func (m *Manager) selectCandidates(ctx context.Context, t *VirtualTrader, limit int, snaps snapshotCache) []executorpkg.CandidateCoin {
 	if limit <= 0 {
 		limit = t.ExecGuards.CandidateLimit
 		if limit <= 0 {
@@ -1128,18 +1799,39 @@ func (m *Manager) selectCandidates(ctx context.Context, t *VirtualTrader, limit
 		return nil
 	}
 	// Fetch snapshots (keep to first 200 assets to bound cost)
+	symbols := make([]string, 0, maxCandidateAssets)
+	for _, a := range assets {
+		if !a.IsActive {
+			continue
+		}
+		symbols = append(symbols, a.Symbol)
+		if len(symbols) >= maxCandidateAssets {
+			break
+		}
+	}
+	if snaps == nil {
+		snaps = snapshotCache{}
+	}
+	snaps.fetch(ctx, t.MarketProvider, symbols)
 	type item struct {
 		sym   string
 		score float64
 	}
 	ranked := make([]item, 0, limit*3)
-	count := 0
-	for _, a := range assets {
-		if !a.IsActive {
+	now := m.now()
+	for _, sym := range symbols {
+		s := snaps[sym]
+		if s == nil {
 			continue
 		}
-		s, err := t.MarketProvider.Snapshot(ctx, a.Symbol)
-		if err != nil || s == nil {
+		t.mu.RLock()
+		_, cooling := t.cooldownUntil(sym, now)
+		t.mu.RUnlock()
+		if cooling {
+			continue
+		}
+		if err := market.CheckSnapshot(s, market.DefaultSanityLimits()); err != nil {
+			logx.Infof("manager: trader %s skip candidate symbol=%s reason=snapshot_sanity err=%v", t.ID, sym, err)
 			continue
 		}
 		// Liquidity threshold if enabled
@@ -1150,23 +1842,23 @@ func (m *Manager) selectCandidates(ctx context.Context, t *VirtualTrader, limit
 				}
 			}
 		}
-		score := s.Change.OneHour
-		if score < 0 {
-			score = -score
+		score, ok := candidateScore(t.ExecGuards.CandidateRanking, s)
+		if !ok {
+			continue
 		}
-		ranked = append(ranked, item{sym: a.Symbol, score: score})
-		count++
-		if count >= 200 {
-			break
+		if fundingCrowded(t.ExecGuards, s) {
+			score *= fundingDownRankFactor
 		}
+		ranked = append(ranked, item{sym: sym, score: score})
 	}
 	sort.Slice(ranked, func(i, j int) bool { return ranked[i].score > ranked[j].score })
 	if len(ranked) > limit {
 		ranked = ranked[:limit]
 	}
+	source := candidateSource(t.ExecGuards.CandidateRanking)
 	out := make([]executorpkg.CandidateCoin, 0, len(ranked))
 	for _, it := range ranked {
-		out = append(out, executorpkg.CandidateCoin{Symbol: it.sym, Sources: []string{"rank_1h_abs"}})
+		out = append(out, executorpkg.CandidateCoin{Symbol: it.sym, Sources: []string{source}, Score: it.score})
 	}
 	return out
 }
@@ -1197,6 +1889,83 @@ func priority(action string) int {
 	}
 }
 
+// applyDecisionGuards orders decisions close-first, drops opens while winding
+// down or for symbols still cooling down, and caps new opens by remaining
+// position slots and the per-cycle limit. Every dropped decision is counted
+// against its guard.
+func (m *Manager) applyDecisionGuards(t *VirtualTrader, ds []executorpkg.Decision, openPositions int, now time.Time) []executorpkg.Decision {
+	decisions := m.dropOpensForWindDown(t, sortDecisionsCloseFirst(ds))
+	decisions = m.dropOpensWhenOverAllocated(t, decisions)
+
+	kept := decisions[:0]
+	dropped := 0
+	t.mu.RLock()
+	for _, d := range decisions {
+		if isOpenAction(d.Action) {
+			if until, cooling := t.cooldownUntil(d.Symbol, now); cooling {
+				dropped++
+				logx.Infof("manager: trader %s skip open symbol=%s reason=cooldown loss=%t until=%s", t.ID, d.Symbol, t.LossCooldown[d.Symbol], until.Format(time.RFC3339))
+				continue
+			}
+		}
+		kept = append(kept, d)
+	}
+	t.mu.RUnlock()
+	decisions = kept
+	m.recordGuardRejection(t.ID, GuardCooldown, dropped)
+
+	decisions = m.dropRoutineOpensIntoReserve(t, decisions, openPositions)
+
+	// remaining slots by max positions
+	remaining := t.RiskParams.MaxPositions - openPositions
+	if remaining < 0 {
+		remaining = 0
+	}
+	guard := GuardMaxPositions
+	// also enforce per-cycle cap if configured (>0)
+	if cycleCap := t.ExecGuards.MaxNewPositionsPerCycle; cycleCap > 0 && cycleCap < remaining {
+		remaining = cycleCap
+		guard = GuardMaxNewPerCycle
+	}
+	capped := capNewOpenDecisions(decisions, remaining)
+	if dropped := len(decisions) - len(capped); dropped > 0 {
+		logx.Infof("manager: trader %s capped %d open decision(s) reason=%s remaining_slots=%d", t.ID, dropped, guard, remaining)
+		m.recordGuardRejection(t.ID, guard, dropped)
+	}
+	return capped
+}
+
+func isOpenAction(action string) bool {
+	return action == "open_long" || action == "open_short"
+}
+
+// dropRoutineOpensIntoReserve drops open decisions below
+// ReservedSlotMinConfidence that would occupy one of the reserved slots at the
+// top of MaxPositions. High-confidence opens pass through to the regular cap.
+func (m *Manager) dropRoutineOpensIntoReserve(t *VirtualTrader, ds []executorpkg.Decision, openPositions int) []executorpkg.Decision {
+	reserved := t.ExecGuards.ReservedPositionSlots
+	if reserved <= 0 || t.RiskParams.MaxPositions <= 0 {
+		return ds
+	}
+	routineLimit := t.RiskParams.MaxPositions - reserved
+	kept := ds[:0]
+	dropped := 0
+	opens := 0
+	for _, d := range ds {
+		if isOpenAction(d.Action) {
+			if d.Confidence < t.ExecGuards.ReservedSlotMinConfidence && openPositions+opens >= routineLimit {
+				dropped++
+				logx.Infof("manager: trader %s skip open symbol=%s reason=%s confidence=%d min_confidence=%d", t.ID, d.Symbol, GuardReservedSlots, d.Confidence, t.ExecGuards.ReservedSlotMinConfidence)
+				continue
+			}
+			opens++
+		}
+		kept = append(kept, d)
+	}
+	m.recordGuardRejection(t.ID, GuardReservedSlots, dropped)
+	return kept
+}
+
 // capNewOpenDecisions limits the number of new open actions to remainingSlots; non-open actions are kept.
 func capNewOpenDecisions(ds []executorpkg.Decision, remainingSlots int) []executorpkg.Decision {
 	if remainingSlots <= 0 {
@@ -0,0 +1,73 @@
+package manager
+
+import (
+	"context"
+	"strings"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	executorpkg "nof0-api/pkg/executor"
+	"nof0-api/pkg/market"
+)
+
+// lookupAssetMeta finds symbol in the trader's market asset list. The bool
+// is false when the asset list is unavailable or does not include symbol.
+func lookupAssetMeta(ctx context.Context, t *VirtualTrader, symbol string) (executorpkg.AssetMeta, bool) {
+	if t.MarketProvider == nil {
+		return executorpkg.AssetMeta{}, false
+	}
+	assets, err := t.MarketProvider.ListAssets(ctx)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("manager: trader %s list assets for %s: %v", t.ID, symbol, err)
+		return executorpkg.AssetMeta{}, false
+	}
+	for _, a := range assets {
+		if strings.EqualFold(a.Symbol, symbol) {
+			return assetMetaOf(a), true
+		}
+	}
+	return executorpkg.AssetMeta{}, false
+}
+
+// useCrossMargin reports whether leverage is set with cross margin. An unset
+// mode keeps cross; auto switches to isolated for assets whose metadata marks
+// them onlyIsolated.
+func useCrossMargin(mode MarginMode, meta executorpkg.AssetMeta) bool {
+	switch mode {
+	case MarginModeIsolated:
+		return false
+	case MarginModeAuto:
+		return !meta.OnlyIsolated
+	default:
+		return true
+	}
+}
+
+func marginModeLabel(isCross bool) string {
+	if isCross {
+		return string(MarginModeCross)
+	}
+	return string(MarginModeIsolated)
+}
+
+// assetMetaOf extracts the executor view of a market asset's metadata.
+func assetMetaOf(a market.Asset) executorpkg.AssetMeta {
+	meta := executorpkg.AssetMeta{Precision: a.Precision}
+	if a.RawMetadata == nil {
+		return meta
+	}
+	if v, ok := a.RawMetadata["maxLeverage"]; ok {
+		switch x := v.(type) {
+		case float64:
+			meta.MaxLeverage = x
+		case int:
+			meta.MaxLeverage = float64(x)
+		}
+	}
+	if v, ok := a.RawMetadata["onlyIsolated"]; ok {
+		if b, ok := v.(bool); ok {
+			meta.OnlyIsolated = b
+		}
+	}
+	return meta
+}
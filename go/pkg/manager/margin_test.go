@@ -0,0 +1,66 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// leverageExchange records the margin type of every leverage update.
+type leverageExchange struct {
+	*sim.Provider
+	crosses []bool
+}
+
+func (l *leverageExchange) UpdateLeverage(ctx context.Context, asset int, isCross bool, leverage int) error {
+	l.crosses = append(l.crosses, isCross)
+	return l.Provider.UpdateLeverage(ctx, asset, isCross, leverage)
+}
+
+func TestExecuteDecisionMarginMode(t *testing.T) {
+	cases := []struct {
+		name         string
+		mode         MarginMode
+		onlyIsolated bool
+		wantCross    bool
+	}{
+		{name: "unset keeps cross", wantCross: true},
+		{name: "cross", mode: MarginModeCross, onlyIsolated: true, wantCross: true},
+		{name: "isolated", mode: MarginModeIsolated, wantCross: false},
+		{name: "auto on a normal asset", mode: MarginModeAuto, wantCross: true},
+		{name: "auto on an isolated-only asset", mode: MarginModeAuto, onlyIsolated: true, wantCross: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ex := &leverageExchange{Provider: sim.New()}
+			require.NoError(t, ex.SetMarkPrice(context.Background(), "DOGE", 0.1))
+			mk := newFakeMarket(testSnapshot("DOGE", 0.1, 0))
+			mk.withMaxLeverage(5).assets[0].RawMetadata["onlyIsolated"] = tc.onlyIsolated
+			vt := &VirtualTrader{
+				ID:               "margin",
+				ExchangeProvider: ex,
+				MarketProvider:   mk,
+				MarginMode:       tc.mode,
+				RiskParams:       RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 1000, AltcoinLeverage: 3},
+				Cooldown:         make(map[string]time.Time),
+			}
+			m := NewManager(nil, nil, nil, nil, nil)
+			require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "DOGE", Action: "open_long", Leverage: 2, PositionSizeUSD: 100}))
+			require.Len(t, ex.crosses, 1)
+			assert.Equal(t, tc.wantCross, ex.crosses[0])
+		})
+	}
+}
+
+func TestValidateMarginMode(t *testing.T) {
+	assert.NoError(t, TraderConfig{MarginMode: MarginModeAuto}.validateMarginMode(0))
+	err := TraderConfig{MarginMode: "portfolio"}.validateMarginMode(1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "traders[1].margin_mode")
+}
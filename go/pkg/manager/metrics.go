@@ -0,0 +1,201 @@
+package manager
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Guard names used to label rejection counters.
+const (
+	GuardCooldown        = "cooldown"
+	GuardMaxPositions    = "max_positions"
+	GuardMaxNewPerCycle  = "max_new_positions_per_cycle"
+	GuardMaxPositionSize = "max_position_size_usd"
+	GuardSharpePause     = "sharpe_pause"
+	GuardSparseData      = "sparse_market_data"
+	GuardWindDown        = "wind_down"
+	GuardLatencyBudget   = "decision_latency_budget"
+	GuardCloseSide       = "close_side_mismatch"
+	GuardOverAllocation  = "over_allocation"
+	GuardTokenBudget     = "token_budget"
+	GuardSLTPRequired    = "sl_tp_required"
+	GuardReservedSlots   = "reserved_position_slots"
+	GuardLLMSpendBudget  = "llm_daily_budget"
+	GuardInFlightOpen    = "in_flight_open"
+	GuardDuplicateIntent = "duplicate_intent"
+	GuardMaxDrawdown     = "max_drawdown"
+	GuardDailyLoss       = "max_daily_loss"
+	GuardMaxPositionAge  = "max_position_age"
+	GuardDuplicatePos    = "duplicate_position"
+	GuardMarketSlippage  = "market_slippage"
+	GuardMinNotional     = "min_order_notional"
+	GuardKellyNoEdge     = "kelly_no_edge"
+)
+
+var guardRejectionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "nof0_manager_guard_rejections_total",
+	Help: "Number of decisions or cycles blocked by a manager or executor validator guard.",
+}, []string{"trader", "guard"})
+
+// MetricsExporterPrometheus serves the collectors below when set as
+// monitoring.metrics_exporter.
+const MetricsExporterPrometheus = "prometheus"
+
+var (
+	decisionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nof0_manager_decisions_total",
+		Help: "Number of decisions handed to execution.",
+	}, []string{"trader", "action"})
+	opensTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nof0_manager_opens_total",
+		Help: "Number of opening orders submitted.",
+	}, []string{"trader", "action"})
+	closesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nof0_manager_closes_total",
+		Help: "Number of positions closed by a decision.",
+	}, []string{"trader", "action"})
+	errorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nof0_manager_errors_total",
+		Help: "Number of failed decision generations (action=generate) and executions.",
+	}, []string{"trader", "action"})
+	cycleDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "nof0_manager_cycle_duration_seconds",
+		Help:    "Duration of completed decision cycles.",
+		Buckets: prometheus.ExponentialBuckets(0.5, 2, 10),
+	}, []string{"trader"})
+	llmLatencySeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "nof0_manager_llm_latency_seconds",
+		Help:    "Time spent obtaining a decision from the executor's LLM.",
+		Buckets: prometheus.ExponentialBuckets(0.5, 2, 10),
+	}, []string{"trader"})
+	equityUSD = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nof0_manager_equity_usd",
+		Help: "Latest synced account equity per trader.",
+	}, []string{"trader"})
+	marginUsagePct = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nof0_manager_margin_usage_pct",
+		Help: "Latest synced margin used as a percentage of equity per trader.",
+	}, []string{"trader"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		guardRejectionsTotal,
+		decisionsTotal,
+		opensTotal,
+		closesTotal,
+		errorsTotal,
+		cycleDurationSeconds,
+		llmLatencySeconds,
+		equityUSD,
+		marginUsagePct,
+	)
+}
+
+// observeExecution counts one executed decision by outcome. Dry-run orders
+// count as decisions but not as opens or closes.
+func observeExecution(traderID, action string, order *ExecutedOrder, err error) {
+	decisionsTotal.WithLabelValues(traderID, action).Inc()
+	switch {
+	case err != nil:
+		errorsTotal.WithLabelValues(traderID, action).Inc()
+	case order == nil || order.DryRun:
+	case action == "open_long" || action == "open_short":
+		opensTotal.WithLabelValues(traderID, action).Inc()
+	case action == "close_long" || action == "close_short":
+		closesTotal.WithLabelValues(traderID, action).Inc()
+	}
+}
+
+// observeAccount publishes a trader's synced equity and margin usage.
+func observeAccount(traderID string, equity, marginUsed float64) {
+	equityUSD.WithLabelValues(traderID).Set(equity)
+	usage := 0.0
+	if equity > 0 {
+		usage = 100 * marginUsed / equity
+	}
+	marginUsagePct.WithLabelValues(traderID).Set(usage)
+}
+
+// guardStats keeps in-memory rejection counts per trader and guard so they can
+// be inspected without a metrics backend.
+type guardStats struct {
+	mu     sync.Mutex
+	counts map[string]map[string]int64
+}
+
+func newGuardStats() *guardStats {
+	return &guardStats{counts: make(map[string]map[string]int64)}
+}
+
+func (g *guardStats) add(traderID, guard string, n int) {
+	if g == nil || n <= 0 {
+		return
+	}
+	g.mu.Lock()
+	byGuard, ok := g.counts[traderID]
+	if !ok {
+		byGuard = make(map[string]int64)
+		g.counts[traderID] = byGuard
+	}
+	byGuard[guard] += int64(n)
+	g.mu.Unlock()
+}
+
+func (g *guardStats) snapshot(traderID string) map[string]int64 {
+	out := make(map[string]int64)
+	if g == nil {
+		return out
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for guard, n := range g.counts[traderID] {
+		out[guard] = n
+	}
+	return out
+}
+
+func (g *guardStats) traders() []string {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ids := make([]string, 0, len(g.counts))
+	for id := range g.counts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// recordGuardRejection increments both the in-memory and Prometheus counters.
+func (m *Manager) recordGuardRejection(traderID, guard string, n int) {
+	if m == nil || n <= 0 {
+		return
+	}
+	m.guardStats.add(traderID, guard, n)
+	guardRejectionsTotal.WithLabelValues(traderID, guard).Add(float64(n))
+}
+
+// GuardRejections returns a copy of the rejection counts for a trader keyed by guard name.
+func (m *Manager) GuardRejections(traderID string) map[string]int64 {
+	if m == nil {
+		return map[string]int64{}
+	}
+	return m.guardStats.snapshot(traderID)
+}
+
+// GuardRejectionsAll returns rejection counts for every trader that has tripped a guard.
+func (m *Manager) GuardRejectionsAll() map[string]map[string]int64 {
+	out := make(map[string]map[string]int64)
+	if m == nil {
+		return out
+	}
+	for _, id := range m.guardStats.traders() {
+		out[id] = m.guardStats.snapshot(id)
+	}
+	return out
+}
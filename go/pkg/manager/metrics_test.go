@@ -0,0 +1,156 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	executorpkg "nof0-api/pkg/executor"
+)
+
+func TestApplyDecisionGuards_CooldownRejection(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	now := time.Now()
+	vt := &VirtualTrader{
+		ID:         "guard_cooldown",
+		RiskParams: RiskParameters{MaxPositions: 5},
+		ExecGuards: ExecGuards{CooldownAfterClose: 10 * time.Minute},
+		Cooldown:   map[string]time.Time{"BTC": now.Add(-time.Minute), "SOL": now.Add(-time.Hour)},
+	}
+	decisions := []executorpkg.Decision{
+		{Symbol: "BTC", Action: "open_long"},
+		{Symbol: "SOL", Action: "open_short"},
+		{Symbol: "ETH", Action: "close_long"},
+	}
+
+	out := m.applyDecisionGuards(vt, decisions, 0, now)
+	assert.Len(t, out, 2, "open inside cooldown window should be dropped")
+	for _, d := range out {
+		assert.NotEqual(t, "BTC", d.Symbol, "BTC is still cooling down")
+	}
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardCooldown], "cooldown counter should increment once")
+	assert.Equal(t, 1.0, testutil.ToFloat64(guardRejectionsTotal.WithLabelValues(vt.ID, GuardCooldown)), "prometheus cooldown counter should increment")
+
+	m.applyDecisionGuards(vt, decisions, 0, now)
+	assert.Equal(t, int64(2), m.GuardRejections(vt.ID)[GuardCooldown], "cooldown counter should accumulate across cycles")
+}
+
+func TestApplyDecisionGuards_PositionCapRejection(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := &VirtualTrader{
+		ID:         "guard_cap",
+		RiskParams: RiskParameters{MaxPositions: 3},
+		Cooldown:   map[string]time.Time{},
+	}
+	decisions := []executorpkg.Decision{
+		{Symbol: "BTC", Action: "open_long"},
+		{Symbol: "ETH", Action: "open_long"},
+		{Symbol: "SOL", Action: "open_short"},
+		{Symbol: "DOGE", Action: "close_short"},
+	}
+
+	out := m.applyDecisionGuards(vt, decisions, 2, time.Now())
+	assert.Len(t, out, 2, "one close and one open should remain with a single free slot")
+	assert.Equal(t, int64(2), m.GuardRejections(vt.ID)[GuardMaxPositions], "max_positions counter should count dropped opens")
+	assert.Equal(t, 2.0, testutil.ToFloat64(guardRejectionsTotal.WithLabelValues(vt.ID, GuardMaxPositions)), "prometheus max_positions counter should increment")
+	assert.Zero(t, m.GuardRejections(vt.ID)[GuardCooldown], "cooldown counter should be untouched")
+
+	vt.ExecGuards.MaxNewPositionsPerCycle = 1
+	m.applyDecisionGuards(vt, decisions, 0, time.Now())
+	assert.Equal(t, int64(2), m.GuardRejections(vt.ID)[GuardMaxNewPerCycle], "per-cycle cap should be attributed to its own guard")
+	assert.Contains(t, m.GuardRejectionsAll(), vt.ID, "trader should appear in aggregate counts")
+}
+
+func TestApplyDecisionGuards_ReservedPositionSlots(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := &VirtualTrader{
+		ID:         "guard_reserved",
+		RiskParams: RiskParameters{MaxPositions: 3},
+		ExecGuards: ExecGuards{ReservedPositionSlots: 1, ReservedSlotMinConfidence: 80},
+		Cooldown:   map[string]time.Time{},
+	}
+	routine := []executorpkg.Decision{
+		{Symbol: "BTC", Action: "open_long", Confidence: 60},
+		{Symbol: "ETH", Action: "open_long", Confidence: 65},
+	}
+
+	out := m.applyDecisionGuards(vt, routine, 1, time.Now())
+	if assert.Len(t, out, 1, "routine opens should stop one slot before max_positions") {
+		assert.Equal(t, "BTC", out[0].Symbol)
+	}
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardReservedSlots], "reserved-slot rejection should be counted")
+	assert.Zero(t, m.GuardRejections(vt.ID)[GuardMaxPositions], "max_positions should not claim the rejection")
+
+	out = m.applyDecisionGuards(vt, routine[:1], 2, time.Now())
+	assert.Empty(t, out, "routine open may not fill the reserved slot")
+
+	highConviction := []executorpkg.Decision{
+		{Symbol: "SOL", Action: "open_short", Confidence: 85},
+		{Symbol: "DOGE", Action: "open_long", Confidence: 90},
+	}
+	out = m.applyDecisionGuards(vt, highConviction, 2, time.Now())
+	if assert.Len(t, out, 1, "high-confidence open should use the reserved slot") {
+		assert.Equal(t, "DOGE", out[0].Symbol, "decisions are ordered by symbol before capping")
+	}
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardMaxPositions], "max_positions still caps the reserved slot")
+	assert.Equal(t, int64(2), m.GuardRejections(vt.ID)[GuardReservedSlots], "high-confidence opens should not be counted as reserved-slot rejections")
+}
+
+func TestExecutorGuardRejectionIsCounted(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, &fakePersistence{})
+	exec := &fakeExecutor{err: &executorpkg.GuardError{Guard: executorpkg.GuardLiquidity, Err: errors.New("decision[0]: ABC illiquid")}}
+	vt := newCycleTestTrader(m, "exec_guard", newFakeMarket(testSnapshot("BTC", 60000, 0.01)), exec)
+	off := false
+	vt.ExecGuards = ExecGuards{EnableSparseDataGuard: &off}
+
+	m.runTraderCycle(context.Background(), vt)
+
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[executorpkg.GuardLiquidity], "validator rejection should be attributed to its guard")
+	assert.Equal(t, 1.0, testutil.ToFloat64(guardRejectionsTotal.WithLabelValues(vt.ID, executorpkg.GuardLiquidity)), "prometheus counter should include validator guards")
+
+	exec.err = errors.New("executor: parse decision")
+	m.runTraderCycle(context.Background(), vt)
+	assert.Len(t, m.GuardRejections(vt.ID), 1, "plain decision errors are not guard rejections")
+}
+
+func TestPrometheusMetricsAfterCycle(t *testing.T) {
+	cfg := &Config{Monitoring: MonitoringConfig{MetricsExporter: MetricsExporterPrometheus}}
+	m := NewManager(cfg, nil, nil, nil, &fakePersistence{})
+	exec := &fakeExecutor{decisions: []executorpkg.Decision{
+		{Symbol: "AVAX", Action: "open_long", PositionSizeUSD: 300, Confidence: 80},
+		{Symbol: "ETH", Action: "open_short", PositionSizeUSD: -1},
+	}}
+	vt := newCycleTestTrader(m, "prom", newFakeMarket(testSnapshot("AVAX", 30, 0.01), testSnapshot("ETH", 3000, 0.02)), exec)
+	off := false
+	vt.ExecGuards = ExecGuards{EnableSparseDataGuard: &off}
+
+	m.runTraderCycle(context.Background(), vt)
+	require.NoError(t, m.SyncTraderPositions(vt.ID))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(decisionsTotal.WithLabelValues("prom", "open_long")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(opensTotal.WithLabelValues("prom", "open_long")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(errorsTotal.WithLabelValues("prom", "open_short")), "the invalid open should count as an error")
+	assert.Zero(t, testutil.ToFloat64(opensTotal.WithLabelValues("prom", "open_short")))
+
+	server := httptest.NewServer(m.StatusHandler())
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/metrics/prometheus")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	scrape := string(body)
+	assert.Contains(t, scrape, `nof0_manager_opens_total{action="open_long",trader="prom"} 1`)
+	assert.Contains(t, scrape, `nof0_manager_cycle_duration_seconds_count{trader="prom"} 1`)
+	assert.Contains(t, scrape, `nof0_manager_llm_latency_seconds_count{trader="prom"} 1`)
+	assert.Contains(t, scrape, `nof0_manager_equity_usd{trader="prom"}`)
+	assert.Contains(t, scrape, `nof0_manager_margin_usage_pct{trader="prom"}`)
+}
@@ -0,0 +1,42 @@
+package manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"nof0-api/pkg/exchange"
+)
+
+const (
+	defaultOrderRetryAttempts = 3
+	defaultOrderRetryBackoff  = 250 * time.Millisecond
+)
+
+// placeOrderWithRetry submits order, retrying transient failures with
+// exponential backoff. Every attempt carries the same cloid, so the exchange
+// books at most one order however many requests reach it.
+func (m *Manager) placeOrderWithRetry(ctx context.Context, t *VirtualTrader, order exchange.Order) (*exchange.OrderResponse, error) {
+	attempts := t.ExecGuards.OrderRetryAttempts
+	if attempts <= 0 {
+		attempts = defaultOrderRetryAttempts
+	}
+	backoff := t.ExecGuards.OrderRetryBackoff
+	if backoff <= 0 {
+		backoff = defaultOrderRetryBackoff
+	}
+	for attempt := 1; ; attempt++ {
+		resp, err := t.ExchangeProvider.PlaceOrder(ctx, order)
+		if err == nil || attempt >= attempts || !exchange.IsRetriable(err) {
+			return resp, err
+		}
+		logx.WithContext(ctx).Infof("manager: trader %s retry place order cloid=%s attempt=%d/%d backoff=%s err=%v", t.ID, order.Cloid, attempt, attempts, backoff, err)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/exchange/sim"
+)
+
+// flakyExchange books an order on its first PlaceOrder but loses the
+// response, then fails again before succeeding. Like Hyperliquid it answers
+// a repeated cloid with the order already booked.
+type flakyExchange struct {
+	*sim.Provider
+	failures []error
+	cloids   []string
+	booked   map[string]*exchange.OrderResponse
+}
+
+func (f *flakyExchange) PlaceOrder(ctx context.Context, order exchange.Order) (*exchange.OrderResponse, error) {
+	f.cloids = append(f.cloids, order.Cloid)
+	if resp, ok := f.booked[order.Cloid]; ok && len(f.failures) == 0 {
+		return resp, nil
+	}
+	if _, ok := f.booked[order.Cloid]; !ok {
+		resp, err := f.Provider.PlaceOrder(ctx, order)
+		if err != nil {
+			return nil, err
+		}
+		f.booked[order.Cloid] = resp
+	}
+	if len(f.failures) > 0 {
+		err := f.failures[0]
+		f.failures = f.failures[1:]
+		return nil, err
+	}
+	return f.booked[order.Cloid], nil
+}
+
+func newRetryTrader(m *Manager, ex *flakyExchange) *VirtualTrader {
+	vt := newCycleTestTrader(m, "retry", newFakeMarket(testSnapshot("BTC", 60000, 0)), &fakeExecutor{})
+	vt.ExchangeProvider = ex
+	vt.OrderStyle = OrderStyleLimitIOC
+	vt.ExecGuards.OrderRetryBackoff = time.Millisecond
+	return vt
+}
+
+func TestPlaceOrderRetriesTransientFailures(t *testing.T) {
+	ex := &flakyExchange{
+		Provider: sim.New(),
+		failures: []error{
+			&exchange.HTTPError{Op: "hyperliquid: exchange", StatusCode: 502, Body: "bad gateway"},
+			context.DeadlineExceeded,
+		},
+		booked: make(map[string]*exchange.OrderResponse),
+	}
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := newRetryTrader(m, ex)
+
+	order, err := m.executeDecision(vt, inFlightOpen())
+	require.NoError(t, err)
+	require.NotNil(t, order)
+
+	require.Len(t, ex.cloids, 3, "two transient failures then a success")
+	assert.Equal(t, ex.cloids[0], ex.cloids[1], "retries must reuse the cloid")
+	assert.Equal(t, ex.cloids[0], ex.cloids[2], "retries must reuse the cloid")
+	assert.Len(t, ex.booked, 1)
+	positions, err := ex.GetPositions(context.Background())
+	require.NoError(t, err)
+	require.Len(t, positions, 1)
+	assert.InDelta(t, 0.01, parseFloat(positions[0].Szi), 1e-9, "exactly one logical order")
+}
+
+func TestPlaceOrderDoesNotRetryTerminalFailures(t *testing.T) {
+	ex := &flakyExchange{
+		Provider: sim.New(),
+		failures: []error{errors.New("Insufficient margin to place order")},
+		booked:   make(map[string]*exchange.OrderResponse),
+	}
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := newRetryTrader(m, ex)
+
+	_, err := m.executeDecision(vt, inFlightOpen())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Insufficient margin")
+	assert.Len(t, ex.cloids, 1, "a rejection should not be retried")
+}
+
+func TestPlaceOrderStopsAtMaxAttempts(t *testing.T) {
+	transient := &exchange.HTTPError{StatusCode: 503}
+	ex := &flakyExchange{
+		Provider: sim.New(),
+		failures: []error{transient, transient, transient},
+		booked:   make(map[string]*exchange.OrderResponse),
+	}
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := newRetryTrader(m, ex)
+	vt.ExecGuards.OrderRetryAttempts = 2
+
+	_, err := m.executeDecision(vt, inFlightOpen())
+	require.Error(t, err)
+	assert.ErrorAs(t, err, new(*exchange.HTTPError))
+	assert.Len(t, ex.cloids, 2)
+}
@@ -0,0 +1,64 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// methodRecordingExchange wraps the sim provider and records which order
+// submission method the manager used.
+type methodRecordingExchange struct {
+	*sim.Provider
+	methods []string
+}
+
+func (r *methodRecordingExchange) PlaceOrder(ctx context.Context, order exchange.Order) (*exchange.OrderResponse, error) {
+	r.methods = append(r.methods, "PlaceOrder")
+	return r.Provider.PlaceOrder(ctx, order)
+}
+
+func (r *methodRecordingExchange) IOCMarket(ctx context.Context, coin string, isBuy bool, qty, slippage float64, reduceOnly bool) (*exchange.OrderResponse, error) {
+	r.methods = append(r.methods, "IOCMarket")
+	return r.Provider.IOCMarket(ctx, coin, isBuy, qty, slippage, reduceOnly)
+}
+
+func (r *methodRecordingExchange) ClosePosition(ctx context.Context, coin string) (*exchange.OrderResponse, error) {
+	r.methods = append(r.methods, "ClosePosition")
+	return r.Provider.ClosePosition(ctx, coin)
+}
+
+func TestExecuteDecisionHonoursOrderStyle(t *testing.T) {
+	cases := map[OrderStyle]struct {
+		open, close string
+	}{
+		OrderStyleLimitIOC:  {open: "PlaceOrder", close: "ClosePosition"},
+		OrderStyleMarketIOC: {open: "IOCMarket", close: "IOCMarket"},
+		OrderStylePostOnly:  {open: "PlaceOrder", close: "ClosePosition"},
+	}
+	for style, want := range cases {
+		ex := &methodRecordingExchange{Provider: sim.New()}
+		require.NoError(t, ex.SetMarkPrice(context.Background(), "SOL", 150))
+		m := NewManager(nil, nil, nil, nil, nil)
+		vt := &VirtualTrader{
+			ID:                   "style",
+			ExchangeProvider:     ex,
+			MarketProvider:       newFakeMarket(testSnapshot("SOL", 150, 0)),
+			OrderStyle:           style,
+			MarketIOCSlippageBps: 50,
+			RiskParams:           RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 1000, AltcoinLeverage: 3},
+			Cooldown:             make(map[string]time.Time),
+		}
+
+		require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 300}), style)
+		require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "close_long"}), style)
+		assert.Equal(t, []string{want.open, want.close}, ex.methods, "%s: unexpected provider methods", style)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"nof0-api/pkg/exchange"
 	executorpkg "nof0-api/pkg/executor"
 	"nof0-api/pkg/journal"
+	"nof0-api/pkg/llm"
 )
 
 // PositionEventType distinguishes between open/close lifecycle hooks.
@@ -17,8 +18,11 @@ type PositionEventType string
 const (
 	// PositionEventOpen marks an entry event (new or increased exposure).
 	PositionEventOpen PositionEventType = "open"
-	// PositionEventClose marks an exit event (full close or reduce-only).
+	// PositionEventClose marks an exit event that flattens the position.
 	PositionEventClose PositionEventType = "close"
+	// PositionEventReduce marks a partial close: FillSize is the quantity
+	// scaled out and the rest of the position stays open.
+	PositionEventReduce PositionEventType = "reduce"
 )
 
 // PositionEvent captures the minimal data needed for persistence/caching layers.
@@ -32,12 +36,33 @@ type PositionEvent struct {
 	OccurredAt       time.Time
 	FillPrice        float64
 	FillSize         float64
+	// Leverage is the exchange leverage active when the event occurred
+	// (0 when unknown; persistence falls back to Decision.Leverage).
+	Leverage int
+	// FundingUSD is the funding accrued over the position's life on close
+	// (positive = paid); it is deducted from the realized net PnL.
+	FundingUSD float64
+	// FeeUSD is the commission the venue reported for the fills behind the
+	// event (the total filled size for opens); 0 when unknown, in which case
+	// persistence estimates it from the configured fee rates.
+	FeeUSD float64
+	// Maker marks fills that added liquidity (post_only orders), estimated
+	// at the maker rather than the taker fee rate.
+	Maker bool
+	// Reconciled marks a synthetic close emitted because the exchange no
+	// longer reports a position the persistence layer still holds open, or an
+	// open adopted at startup for an exchange position with no record.
+	Reconciled bool
 }
 
 // DecisionCycleRecord is emitted after each decision loop for DB/cache mirroring.
 type DecisionCycleRecord struct {
 	TraderID string
 	Cycle    *journal.CycleRecord
+	// Prompt is the full rendered prompt, set only for traders with
+	// persist_prompts; it is stored as a conversation linked by Cycle.CycleID.
+	Prompt string
+	Usage  llm.Usage
 }
 
 // AccountSyncSnapshot represents a normalized account/equity update.
@@ -47,7 +72,10 @@ type AccountSyncSnapshot struct {
 	MarginUsedUSD       float64
 	AvailableBalanceUSD float64
 	UnrealizedPnLUSD    float64
-	SyncedAt            time.Time
+	// RealizedPnLUSD is the cumulative net PnL of trades closed so far, so the
+	// equity curve can be split into realized and unrealized components.
+	RealizedPnLUSD float64
+	SyncedAt       time.Time
 }
 
 // AnalyticsSnapshot captures performance metrics for persistence/leaderboard.
@@ -71,6 +99,58 @@ type PersistenceService interface {
 	HydrateCaches(ctx context.Context, traderIDs []string) error
 }
 
+// RealizedPnLReader is an optional PersistenceService capability exposing the
+// realized gross PnL (price PnL before commissions and funding) the manager
+// recorded for a trader within a time window.
+type RealizedPnLReader interface {
+	RealizedGrossPnL(ctx context.Context, traderID string, start, end time.Time) (float64, error)
+}
+
+// LeverageChange records a leverage/margin-mode update sent to the exchange.
+type LeverageChange struct {
+	TraderID  string
+	Symbol    string
+	Asset     int
+	Mode      string // "cross" or "isolated"
+	Leverage  int
+	ChangedAt time.Time
+}
+
+// LeverageRecorder is an optional PersistenceService capability auditing
+// leverage changes.
+type LeverageRecorder interface {
+	RecordLeverageChange(ctx context.Context, change LeverageChange) error
+}
+
+// FundingAccrual records funding settled against an open position.
+type FundingAccrual struct {
+	TraderID  string
+	Symbol    string
+	AmountUSD float64 // funding settled by this accrual (positive = paid)
+	TotalUSD  float64 // cumulative funding on the position
+	Through   time.Time
+}
+
+// FundingRecorder is an optional PersistenceService capability storing
+// funding accrued on open positions.
+type FundingRecorder interface {
+	RecordFundingAccrual(ctx context.Context, accrual FundingAccrual) error
+}
+
+// CachedPosition is an open position as last recorded by the persistence layer.
+type CachedPosition struct {
+	Symbol     string
+	Side       string // "long" or "short"
+	Quantity   float64
+	EntryPrice float64
+}
+
+// OpenPositionReader is an optional PersistenceService capability listing the
+// positions persistence still considers open for a trader.
+type OpenPositionReader interface {
+	OpenPositions(ctx context.Context, traderID string) ([]CachedPosition, error)
+}
+
 type noopPersistenceService struct{}
 
 func (noopPersistenceService) RecordPositionEvent(ctx context.Context, event PositionEvent) error {
@@ -0,0 +1,67 @@
+package manager
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// orderCaptureExchange wraps the sim provider and records placed orders.
+type orderCaptureExchange struct {
+	*sim.Provider
+	orders []exchange.Order
+}
+
+func (o *orderCaptureExchange) PlaceOrder(ctx context.Context, order exchange.Order) (*exchange.OrderResponse, error) {
+	o.orders = append(o.orders, order)
+	return o.Provider.PlaceOrder(ctx, order)
+}
+
+func TestPostOnlyOrderRestsBehindTouch(t *testing.T) {
+	cases := map[string]struct {
+		action    string
+		wantPrice float64
+	}{
+		"buy below":  {action: "open_long", wantPrice: 2997},
+		"sell above": {action: "open_short", wantPrice: 3003},
+	}
+	for name, tc := range cases {
+		ex := &orderCaptureExchange{Provider: sim.New()}
+		require.NoError(t, ex.SetMarkPrice(context.Background(), "ETH", 3000))
+		m := NewManager(nil, nil, nil, nil, nil)
+		vt := &VirtualTrader{
+			ID:                "maker",
+			ExchangeProvider:  ex,
+			MarketProvider:    newFakeMarket(testSnapshot("ETH", 3000, 0)),
+			OrderStyle:        OrderStylePostOnly,
+			PostOnlyOffsetBps: 10,
+			RiskParams:        RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 1000, MajorCoinLeverage: 3},
+			Cooldown:          make(map[string]time.Time),
+		}
+
+		require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "ETH", Action: tc.action, PositionSizeUSD: 300}), name)
+		require.Len(t, ex.orders, 1, name)
+		order := ex.orders[0]
+		require.NotNil(t, order.OrderType.Limit, name)
+		assert.Equal(t, "Alo", order.OrderType.Limit.TIF, "%s: post_only should submit add-liquidity-only", name)
+		px, err := strconv.ParseFloat(order.LimitPx, 64)
+		require.NoError(t, err, name)
+		assert.InDelta(t, tc.wantPrice, px, 1e-6, "%s: limit should sit offset behind the touch", name)
+	}
+}
+
+func TestValidateOrderStylePostOnly(t *testing.T) {
+	assert.NoError(t, TraderConfig{OrderStyle: OrderStylePostOnly}.validateOrderStyle(0))
+	assert.NoError(t, TraderConfig{OrderStyle: OrderStylePostOnly, PostOnlyOffsetBps: 5}.validateOrderStyle(0))
+	err := TraderConfig{OrderStyle: OrderStylePostOnly, PostOnlyOffsetBps: -1}.validateOrderStyle(2)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "traders[2].post_only_offset_bps")
+}
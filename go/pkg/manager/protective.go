@@ -0,0 +1,99 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// ProtectiveOrders reports how the stop loss and take profit of a new
+// position were placed. Errors are empty when the order registered or none
+// was requested.
+type ProtectiveOrders struct {
+	StopLossPlaced   bool
+	StopLossError    string
+	TakeProfitPlaced bool
+	TakeProfitError  string
+	// ClosedUnprotected is set when the position was closed straight away
+	// because its required stop loss failed to register.
+	ClosedUnprotected bool
+}
+
+// journalFields adds the placement outcome to a journal action record.
+func (p *ProtectiveOrders) journalFields(act map[string]any, d executorpkg.Decision) {
+	if p == nil {
+		return
+	}
+	if d.StopLoss > 0 {
+		act["sl_placed"] = p.StopLossPlaced
+		if p.StopLossError != "" {
+			act["sl_error"] = p.StopLossError
+		}
+	}
+	if d.TakeProfit > 0 {
+		act["tp_placed"] = p.TakeProfitPlaced
+		if p.TakeProfitError != "" {
+			act["tp_error"] = p.TakeProfitError
+		}
+	}
+	if p.ClosedUnprotected {
+		act["closed_unprotected"] = true
+	}
+}
+
+// placeProtectiveOrders registers reduce-only SL/TP triggers for a freshly
+// opened position via the optional provider extension. It returns nil when
+// the provider cannot place them.
+func (m *Manager) placeProtectiveOrders(ctx context.Context, t *VirtualTrader, d *executorpkg.Decision, side string, qty float64) *ProtectiveOrders {
+	p, ok := t.ExchangeProvider.(interface {
+		SetStopLoss(context.Context, string, string, float64, float64) error
+		SetTakeProfit(context.Context, string, string, float64, float64) error
+	})
+	if !ok {
+		return nil
+	}
+	res := &ProtectiveOrders{}
+	if d.StopLoss > 0 {
+		if err := p.SetStopLoss(ctx, d.Symbol, side, qty, d.StopLoss); err != nil {
+			res.StopLossError = err.Error()
+			logx.WithContext(ctx).Errorf("manager: trader %s set stop loss symbol=%s price=%.8f err=%v", t.ID, d.Symbol, d.StopLoss, err)
+		} else {
+			res.StopLossPlaced = true
+		}
+	}
+	if d.TakeProfit > 0 {
+		if err := p.SetTakeProfit(ctx, d.Symbol, side, qty, d.TakeProfit); err != nil {
+			res.TakeProfitError = err.Error()
+			logx.WithContext(ctx).Errorf("manager: trader %s set take profit symbol=%s price=%.8f err=%v", t.ID, d.Symbol, d.TakeProfit, err)
+		} else {
+			res.TakeProfitPlaced = true
+		}
+	}
+	return res
+}
+
+// closeUnprotected flattens a position whose required stop loss failed to
+// register, cancelling any trigger that did get placed. The close is booked
+// like a flatten close.
+func (m *Manager) closeUnprotected(ctx context.Context, t *VirtualTrader, symbol string) error {
+	if p, ok := t.ExchangeProvider.(interface {
+		CancelAllBySymbol(context.Context, string) error
+	}); ok {
+		if err := p.CancelAllBySymbol(ctx, symbol); err != nil {
+			logx.WithContext(ctx).Errorf("manager: trader %s cancel orders symbol=%s err=%v", t.ID, symbol, err)
+		}
+	}
+	pos := findPosition(ctx, t, symbol)
+	if pos == nil {
+		return nil
+	}
+	resp, err := reduceOnlyClose(ctx, t, symbol)
+	if err != nil {
+		return fmt.Errorf("close %s: %w", symbol, err)
+	}
+	m.recordFlattenClose(t, *pos, resp, "stop_loss_placement_failed")
+	return nil
+}
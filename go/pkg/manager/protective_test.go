@@ -0,0 +1,96 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// protectiveExchange wraps the sim provider with SL/TP placement that can fail.
+type protectiveExchange struct {
+	*sim.Provider
+	slErr error
+	tpErr error
+}
+
+func (p *protectiveExchange) SetStopLoss(context.Context, string, string, float64, float64) error {
+	return p.slErr
+}
+
+func (p *protectiveExchange) SetTakeProfit(context.Context, string, string, float64, float64) error {
+	return p.tpErr
+}
+
+func newProtectiveTrader(m *Manager, ex *protectiveExchange, stopLossRequired bool) *VirtualTrader {
+	vt := newCycleTestTrader(m, "p1", newFakeMarket(testSnapshot("BTC", 60000, 0)), &fakeExecutor{})
+	vt.ExchangeProvider = ex
+	vt.OrderStyle = OrderStyleMarketIOC
+	vt.RiskParams.StopLossEnabled = stopLossRequired
+	return vt
+}
+
+func protectiveOpen() *executorpkg.Decision {
+	return &executorpkg.Decision{
+		Symbol:          "BTC",
+		Action:          "open_long",
+		Leverage:        2,
+		PositionSizeUSD: 600,
+		StopLoss:        58000,
+		TakeProfit:      66000,
+	}
+}
+
+func TestExecuteDecision_SurfacesStopLossFailure(t *testing.T) {
+	ex := &protectiveExchange{Provider: sim.New(), slErr: errors.New("trigger rejected")}
+	persist := &fakePersistence{}
+	m := NewManager(nil, nil, nil, nil, persist)
+	vt := newProtectiveTrader(m, ex, false)
+
+	order, err := m.executeDecision(vt, protectiveOpen())
+	require.NoError(t, err, "an optional stop loss failure should not fail the open")
+	require.NotNil(t, order.Protection)
+	assert.False(t, order.Protection.StopLossPlaced)
+	assert.Equal(t, "trigger rejected", order.Protection.StopLossError)
+	assert.True(t, order.Protection.TakeProfitPlaced)
+	assert.False(t, order.Protection.ClosedUnprotected)
+
+	positions, err := ex.GetPositions(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, positions, 1, "the position should stay open")
+
+	act := map[string]any{}
+	order.Protection.journalFields(act, *protectiveOpen())
+	assert.Equal(t, false, act["sl_placed"])
+	assert.Equal(t, "trigger rejected", act["sl_error"])
+	assert.Equal(t, true, act["tp_placed"])
+	assert.NotContains(t, act, "tp_error")
+}
+
+func TestExecuteDecision_ClosesWhenRequiredStopLossFails(t *testing.T) {
+	ex := &protectiveExchange{Provider: sim.New(), slErr: errors.New("trigger rejected")}
+	persist := &fakePersistence{}
+	m := NewManager(nil, nil, nil, nil, persist)
+	vt := newProtectiveTrader(m, ex, true)
+
+	order, err := m.executeDecision(vt, protectiveOpen())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stop loss placement failed")
+	assert.Contains(t, err.Error(), "trigger rejected")
+	require.NotNil(t, order)
+	assert.True(t, order.Protection.ClosedUnprotected)
+
+	positions, err := ex.GetPositions(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, positions, "an unprotected position should be closed")
+
+	require.Len(t, persist.events, 2, "the open and the protective close should both be booked")
+	assert.Equal(t, PositionEventOpen, persist.events[0].Event)
+	assert.Equal(t, PositionEventClose, persist.events[1].Event)
+	assert.Equal(t, "stop_loss_placement_failed", persist.events[1].Decision.Reasoning)
+}
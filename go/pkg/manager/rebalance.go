@@ -0,0 +1,130 @@
+package manager
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// rebalanceInterval returns manager.rebalance_interval; zero disables
+// rebalancing.
+func (m *Manager) rebalanceInterval() time.Duration {
+	if m.config == nil {
+		return 0
+	}
+	return m.config.Manager.RebalanceInterval
+}
+
+// Rebalance syncs every trader's account, recomputes allocations from the
+// live equity of the distinct accounts they trade on and flags traders whose
+// margin now exceeds their target so their new opens are blocked until the
+// next rebalance finds them back within it. Configured total equity is used
+// when no trader reports live equity.
+func (m *Manager) Rebalance(ctx context.Context) {
+	if m == nil || m.config == nil {
+		return
+	}
+	if err := m.SyncAllPositions(); err != nil {
+		logx.WithContext(ctx).Errorf("manager: rebalance sync positions: %v", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.traders) == 0 {
+		return
+	}
+	cfg := m.config.Manager
+	// Traders on one exchange account each report that account's full
+	// equity, so count every account once.
+	accounts := make(map[any]float64, len(m.traders))
+	for _, t := range m.traders {
+		t.mu.RLock()
+		if eq := t.ResourceAlloc.CurrentEquityUSD; eq > 0 {
+			accounts[equityAccountKey(t)] = eq
+		}
+		t.mu.RUnlock()
+	}
+	live := 0.0
+	for _, eq := range accounts {
+		live += eq
+	}
+	if live > 0 {
+		cfg.TotalEquityUSD = live
+	}
+	shares := resolveAllocations(cfg, m.allocationInputsLocked())
+	ids := make([]string, 0, len(shares))
+	for id := range shares {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		t := m.traders[id]
+		t.mu.Lock()
+		before := t.ResourceAlloc.AllocatedEquityUSD
+		t.ResourceAlloc.AllocatedEquityUSD = shares[id]
+		over := t.ResourceAlloc.IsOverAllocated()
+		t.OverAllocated = over
+		alloc := t.ResourceAlloc
+		t.mu.Unlock()
+		logx.WithContext(ctx).Infof("manager: rebalance trader %s allocation=%.2f->%.2f usd equity=%.2f margin_used=%.2f over_allocated=%t strategy=%s",
+			id, before, alloc.AllocatedEquityUSD, alloc.CurrentEquityUSD, alloc.MarginUsedUSD, over, cfg.AllocationStrategy)
+	}
+	logx.WithContext(ctx).Infof("manager: rebalance complete traders=%d total_equity=%.2f usd reserve_pct=%.2f", len(shares), cfg.TotalEquityUSD, cfg.ReserveEquityPct)
+}
+
+// equityAccountKey identifies the exchange account behind t's equity and
+// positions. Traders configured on the same exchange share one provider, and
+// so one account; vault traders get a provider of their own even when they
+// share the exchange name.
+func equityAccountKey(t *VirtualTrader) any {
+	if t.ExchangeProvider == nil || !reflect.TypeOf(t.ExchangeProvider).Comparable() {
+		return t.ID
+	}
+	return t.ExchangeProvider
+}
+
+// accountGroupsLocked returns the traders grouped by exchange account, each
+// group sorted by trader ID and the groups ordered by their first trader.
+// Callers hold m.mu.
+func (m *Manager) accountGroupsLocked() [][]*VirtualTrader {
+	byAccount := make(map[any][]*VirtualTrader)
+	for _, t := range m.traders {
+		key := equityAccountKey(t)
+		byAccount[key] = append(byAccount[key], t)
+	}
+	groups := make([][]*VirtualTrader, 0, len(byAccount))
+	for _, traders := range byAccount {
+		sort.Slice(traders, func(i, j int) bool { return traders[i].ID < traders[j].ID })
+		groups = append(groups, traders)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i][0].ID < groups[j][0].ID })
+	return groups
+}
+
+// dropOpensWhenOverAllocated removes open_* decisions for a trader the last
+// rebalance found over its target allocation.
+func (m *Manager) dropOpensWhenOverAllocated(t *VirtualTrader, ds []executorpkg.Decision) []executorpkg.Decision {
+	t.mu.RLock()
+	over := t.OverAllocated
+	t.mu.RUnlock()
+	if !over {
+		return ds
+	}
+	kept := ds[:0]
+	dropped := 0
+	for _, d := range ds {
+		if isOpenAction(d.Action) {
+			dropped++
+			logx.Infof("manager: trader %s suppress open symbol=%s action=%s reason=over_allocation", t.ID, d.Symbol, d.Action)
+			continue
+		}
+		kept = append(kept, d)
+	}
+	m.recordGuardRejection(t.ID, GuardOverAllocation, dropped)
+	return kept
+}
@@ -0,0 +1,87 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+func newRebalanceTestManager(interval time.Duration) (*Manager, *depletedAccountExchange, *depletedAccountExchange) {
+	cfg := &Config{Manager: ManagerConfig{
+		TotalEquityUSD:     1000,
+		AllocationStrategy: AllocationStrategyEqual,
+		RebalanceInterval:  interval,
+	}}
+	m := NewManager(cfg, nil, nil, nil, nil)
+	rich := &depletedAccountExchange{Provider: sim.New(), accountValue: "3000", marginUsed: "500"}
+	heavy := &depletedAccountExchange{Provider: sim.New(), accountValue: "1000", marginUsed: "2500"}
+	for id, ex := range map[string]*depletedAccountExchange{"rich": rich, "heavy": heavy} {
+		vt := newCycleTestTrader(m, id, newFakeMarket(), &fakeExecutor{})
+		vt.ExchangeProvider = ex
+	}
+	return m, rich, heavy
+}
+
+func TestRebalanceUsesLiveEquityAndBlocksOverAllocatedOpens(t *testing.T) {
+	m, _, heavy := newRebalanceTestManager(time.Hour)
+	m.Rebalance(context.Background())
+
+	rich, over := m.traders["rich"], m.traders["heavy"]
+	assert.InDelta(t, 2000, rich.ResourceAlloc.AllocatedEquityUSD, 1e-9, "live equity 4000 split evenly")
+	assert.InDelta(t, 2000, over.ResourceAlloc.AllocatedEquityUSD, 1e-9)
+	assert.False(t, rich.OverAllocated)
+	assert.True(t, over.OverAllocated, "margin 2500 exceeds the 2000 target")
+
+	ds := []executorpkg.Decision{
+		{Symbol: "BTC", Action: "open_long", PositionSizeUSD: 100},
+		{Symbol: "ETH", Action: "close_long"},
+	}
+	kept := m.applyDecisionGuards(over, append([]executorpkg.Decision(nil), ds...), 0, time.Now())
+	if assert.Len(t, kept, 1, "opens should be blocked while over-allocated") {
+		assert.Equal(t, "close_long", kept[0].Action)
+	}
+	assert.Equal(t, int64(1), m.GuardRejections("heavy")[GuardOverAllocation])
+	assert.Len(t, m.applyDecisionGuards(rich, append([]executorpkg.Decision(nil), ds...), 0, time.Now()), 2)
+
+	heavy.marginUsed = "100"
+	m.Rebalance(context.Background())
+	assert.False(t, over.OverAllocated, "trader should be unblocked once it drifts back under target")
+}
+
+func TestRebalanceCountsSharedAccountEquityOnce(t *testing.T) {
+	cfg := &Config{Manager: ManagerConfig{
+		TotalEquityUSD:     1000,
+		AllocationStrategy: AllocationStrategyEqual,
+	}}
+	m := NewManager(cfg, nil, nil, nil, nil)
+	shared := &depletedAccountExchange{Provider: sim.New(), accountValue: "3000", marginUsed: "500"}
+	for _, id := range []string{"alpha", "beta"} {
+		vt := newCycleTestTrader(m, id, newFakeMarket(), &fakeExecutor{})
+		vt.ExchangeProvider = shared
+	}
+	m.Rebalance(context.Background())
+
+	assert.InDelta(t, 1500, m.traders["alpha"].ResourceAlloc.AllocatedEquityUSD, 1e-9, "one 3000 account split evenly, not 6000")
+	assert.InDelta(t, 1500, m.traders["beta"].ResourceAlloc.AllocatedEquityUSD, 1e-9)
+}
+
+func TestRunTradingLoopRebalancesOnInterval(t *testing.T) {
+	m, _, _ := newRebalanceTestManager(10 * time.Millisecond)
+	m.tickInterval = time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_ = m.RunTradingLoop(ctx)
+	assert.InDelta(t, 2000, m.traders["rich"].ResourceAlloc.AllocatedEquityUSD, 1e-9, "rebalance ticker should recompute allocations")
+
+	disabled, _, _ := newRebalanceTestManager(0)
+	disabled.tickInterval = time.Hour
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel2()
+	_ = disabled.RunTradingLoop(ctx2)
+	assert.Zero(t, disabled.traders["rich"].ResourceAlloc.AllocatedEquityUSD, "zero interval should disable rebalancing")
+}
@@ -0,0 +1,118 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"nof0-api/pkg/exchange"
+)
+
+// defaultPnLReconcileToleranceUSD absorbs rounding differences between venue
+// and manager bookkeeping before a window is flagged.
+const defaultPnLReconcileToleranceUSD = 0.01
+
+// PnLReconciliation compares venue-reported and manager-recorded realized PnL
+// for one trader over a time window. Both PnL figures are gross of
+// commissions and funding.
+type PnLReconciliation struct {
+	TraderID     string
+	Start        time.Time
+	End          time.Time
+	VenuePnL     float64
+	ManagerPnL   float64
+	Difference   float64 // VenuePnL - ManagerPnL
+	VenueFeesUSD float64 // commissions charged on the window's fills
+	FillCount    int
+	Discrepancy  bool
+}
+
+// ReconcileRealizedPnL sums the venue closedPnl for the trader's fills in
+// [start, end] and compares it against the manager's persisted realized gross
+// PnL for the same window, logging any discrepancy beyond tolerance. The venue
+// reports closedPnl before fees and funding, so it is matched against the
+// manager's gross trade PnL rather than its net; the fills' fees are reported
+// alongside.
+func (m *Manager) ReconcileRealizedPnL(ctx context.Context, traderID string, start, end time.Time) (*PnLReconciliation, error) {
+	if m == nil {
+		return nil, errors.New("manager: nil manager")
+	}
+	m.mu.RLock()
+	t, ok := m.traders[traderID]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("manager: trader %s not found", traderID)
+	}
+	fillsSource, ok := t.ExchangeProvider.(interface {
+		GetUserFillsByTime(context.Context, time.Time, time.Time) ([]exchange.UserFill, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("manager: trader %s exchange provider does not expose historical fills", traderID)
+	}
+	pnlSource, ok := m.persistence.(RealizedPnLReader)
+	if !ok {
+		return nil, errors.New("manager: persistence does not expose realized pnl")
+	}
+
+	fills, err := fillsSource.GetUserFillsByTime(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("manager: fetch fills for trader %s: %w", traderID, err)
+	}
+	managerPnL, err := pnlSource.RealizedGrossPnL(ctx, traderID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("manager: load realized pnl for trader %s: %w", traderID, err)
+	}
+
+	venuePnL, venueFees := 0.0, 0.0
+	for _, f := range fills {
+		venuePnL += parseFloat(f.ClosedPnl)
+		venueFees += f.FeeUSD()
+	}
+	out := &PnLReconciliation{
+		TraderID:     traderID,
+		Start:        start,
+		End:          end,
+		VenuePnL:     venuePnL,
+		ManagerPnL:   managerPnL,
+		Difference:   venuePnL - managerPnL,
+		VenueFeesUSD: venueFees,
+		FillCount:    len(fills),
+	}
+	out.Discrepancy = math.Abs(out.Difference) > defaultPnLReconcileToleranceUSD
+	if out.Discrepancy {
+		logx.WithContext(ctx).Errorf("manager: trader %s pnl discrepancy window=%s..%s venue=%.4f manager=%.4f diff=%.4f fees=%.4f fills=%d",
+			traderID, start.Format(time.RFC3339), end.Format(time.RFC3339), venuePnL, managerPnL, out.Difference, venueFees, len(fills))
+	} else {
+		logx.WithContext(ctx).Infof("manager: trader %s pnl reconciled window=%s..%s gross_pnl=%.4f fees=%.4f fills=%d",
+			traderID, start.Format(time.RFC3339), end.Format(time.RFC3339), venuePnL, venueFees, len(fills))
+	}
+	return out, nil
+}
+
+// RecentFills returns the venue fills for traderID executed at or after since,
+// so persistence can book trades at real fill prices and fees instead of
+// position deltas. Providers without fill history return an error.
+func (m *Manager) RecentFills(ctx context.Context, traderID string, since time.Time) ([]exchange.UserFill, error) {
+	if m == nil {
+		return nil, errors.New("manager: nil manager")
+	}
+	m.mu.RLock()
+	t, ok := m.traders[traderID]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("manager: trader %s not found", traderID)
+	}
+	source, ok := t.ExchangeProvider.(exchange.FillHistory)
+	if !ok {
+		return nil, fmt.Errorf("manager: trader %s exchange provider does not expose fills", traderID)
+	}
+	fills, err := source.GetUserFills(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("manager: fetch fills for trader %s: %w", traderID, err)
+	}
+	return fills, nil
+}
@@ -0,0 +1,99 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/exchange/sim"
+)
+
+type fillsExchange struct {
+	*sim.Provider
+	fills []exchange.UserFill
+}
+
+func (f *fillsExchange) GetUserFillsByTime(context.Context, time.Time, time.Time) ([]exchange.UserFill, error) {
+	return f.fills, nil
+}
+
+func (f *fillsExchange) GetUserFills(_ context.Context, since time.Time) ([]exchange.UserFill, error) {
+	var out []exchange.UserFill
+	for _, fill := range f.fills {
+		if fill.Time >= since.UnixMilli() {
+			out = append(out, fill)
+		}
+	}
+	return out, nil
+}
+
+func TestReconcileRealizedPnL(t *testing.T) {
+	ex := &fillsExchange{Provider: sim.New(), fills: []exchange.UserFill{
+		{Coin: "BTC", Dir: "Close Long", ClosedPnl: "12.5"},
+		{Coin: "ETH", Dir: "Open Long", ClosedPnl: "0.0"},
+		{Coin: "SOL", Dir: "Close Short", ClosedPnl: "-2.5"},
+	}}
+	persist := &fakePersistence{realizedPnL: 10}
+	m := NewManager(nil, nil, nil, nil, persist)
+	m.traders["t1"] = &VirtualTrader{ID: "t1", ExchangeProvider: ex}
+	end := time.Now()
+	start := end.Add(-24 * time.Hour)
+
+	rec, err := m.ReconcileRealizedPnL(context.Background(), "t1", start, end)
+	assert.NoError(t, err, "reconcile should succeed")
+	assert.InDelta(t, 10.0, rec.VenuePnL, 1e-9, "venue pnl should sum closedPnl")
+	assert.False(t, rec.Discrepancy, "matching records should reconcile")
+	assert.Equal(t, 3, rec.FillCount, "all fills should be counted")
+
+	persist.realizedPnL = 4
+	rec, err = m.ReconcileRealizedPnL(context.Background(), "t1", start, end)
+	assert.NoError(t, err, "reconcile should succeed")
+	assert.True(t, rec.Discrepancy, "diverging manager records should be flagged")
+	assert.InDelta(t, 6.0, rec.Difference, 1e-9, "difference should be venue minus manager")
+
+	m.traders["t2"] = &VirtualTrader{ID: "t2", ExchangeProvider: sim.New()}
+	_, err = m.ReconcileRealizedPnL(context.Background(), "t2", start, end)
+	assert.Error(t, err, "providers without fill history should be rejected")
+}
+
+func TestReconcileRealizedPnLIgnoresFees(t *testing.T) {
+	// A round trip of 0.1 BTC from 60000 to 61000: closedPnl is the 100 USD
+	// price move; the 5.42 USD of fees are not part of it.
+	ex := &fillsExchange{Provider: sim.New(), fills: []exchange.UserFill{
+		{Coin: "BTC", Dir: "Open Long", Px: "60000", Sz: "0.1", ClosedPnl: "0.0", Fee: "2.7"},
+		{Coin: "BTC", Dir: "Close Long", Px: "61000", Sz: "0.1", ClosedPnl: "100.0", Fee: "2.72"},
+	}}
+	persist := &fakePersistence{realizedPnL: 100}
+	m := NewManager(nil, nil, nil, nil, persist)
+	m.traders["t1"] = &VirtualTrader{ID: "t1", ExchangeProvider: ex}
+	end := time.Now()
+
+	rec, err := m.ReconcileRealizedPnL(context.Background(), "t1", end.Add(-time.Hour), end)
+	assert.NoError(t, err)
+	assert.False(t, rec.Discrepancy, "gross venue pnl should match the manager's gross pnl despite fees")
+	assert.InDelta(t, 0, rec.Difference, 1e-9)
+	assert.InDelta(t, 5.42, rec.VenueFeesUSD, 1e-9, "fill fees should be reported")
+}
+
+func TestRecentFills(t *testing.T) {
+	ex := &fillsExchange{Provider: sim.New(), fills: []exchange.UserFill{
+		{Coin: "BTC", Px: "60000", Sz: "0.01", Fee: "0.3", Time: 1700000000000},
+		{Coin: "BTC", Px: "61000", Sz: "0.01", Fee: "0.31", Time: 1700000600000},
+	}}
+	m := NewManager(nil, nil, nil, nil, nil)
+	m.traders["t1"] = &VirtualTrader{ID: "t1", ExchangeProvider: ex}
+
+	fills, err := m.RecentFills(context.Background(), "t1", time.UnixMilli(1700000300000))
+	assert.NoError(t, err, "fills should be fetched")
+	if assert.Len(t, fills, 1, "older fills should be dropped") {
+		assert.InDelta(t, 61000, fills[0].Price(), 1e-9, "fill price should parse")
+		assert.InDelta(t, 0.31, fills[0].FeeUSD(), 1e-9, "fill fee should parse")
+	}
+
+	m.traders["t2"] = &VirtualTrader{ID: "t2", ExchangeProvider: sim.New()}
+	_, err = m.RecentFills(context.Background(), "t2", time.Time{})
+	assert.Error(t, err, "providers without fills should be rejected")
+}
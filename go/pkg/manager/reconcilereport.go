@@ -0,0 +1,188 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// positionQtyTolerance absorbs size rounding between the venue and the
+// persisted cache before a position is flagged as mismatched.
+const positionQtyTolerance = 1e-6
+
+// PositionDiscrepancy describes one position on which the exchange and the
+// persisted position cache disagree.
+type PositionDiscrepancy struct {
+	TraderID     string
+	Symbol       string
+	ExchangeSide string // "long", "short" or "" when the exchange has none
+	ExchangeQty  float64
+	CachedSide   string // "long", "short" or "" when persistence has none
+	CachedQty    float64
+}
+
+// ReconciliationReport is the outcome of Manager.Reconcile and of
+// Manager.ReconcileOnStartup.
+type ReconciliationReport struct {
+	CheckedAt time.Time
+	// Accounts is the number of exchange accounts compared.
+	Accounts int
+	// Adopted lists exchange positions missing from persistence that were
+	// recorded as reconciled opens.
+	Adopted []PositionDiscrepancy
+	// Flattened lists exchange positions missing from persistence that were
+	// closed because manager.startup_reconcile is flatten.
+	Flattened []PositionDiscrepancy
+	// Missing lists persisted positions the exchange no longer reports. They
+	// are flagged only; SyncTraderPositions books them as stale closes.
+	Missing []PositionDiscrepancy
+	// Mismatched lists positions both sides hold with a different side or size.
+	Mismatched []PositionDiscrepancy
+}
+
+// Clean reports whether no discrepancy was found.
+func (r *ReconciliationReport) Clean() bool {
+	return len(r.Adopted) == 0 && len(r.Flattened) == 0 && len(r.Missing) == 0 && len(r.Mismatched) == 0
+}
+
+// Reconcile compares each exchange account's live positions with the
+// positions persistence holds open for the traders sharing that account.
+// Positions only the exchange holds are adopted into the cache, assigned to
+// the first trader by ID; positions only persistence holds, or held with a
+// different side or size, are logged and reported. Persistence backends
+// without OpenPositionReader yield an empty report.
+func (m *Manager) Reconcile(ctx context.Context) (*ReconciliationReport, error) {
+	if m == nil {
+		return nil, errors.New("manager: nil manager")
+	}
+	return m.reconcile(ctx, StartupReconcileAdopt)
+}
+
+// reconcile runs reconcileAccount over every exchange account, handling
+// untracked positions according to mode.
+func (m *Manager) reconcile(ctx context.Context, mode string) (*ReconciliationReport, error) {
+	if err := validateReconcileMode(mode); err != nil {
+		return nil, err
+	}
+	report := &ReconciliationReport{CheckedAt: m.now()}
+	reader, ok := m.persistence.(OpenPositionReader)
+	if !ok {
+		return report, nil
+	}
+	m.mu.RLock()
+	groups := m.accountGroupsLocked()
+	m.mu.RUnlock()
+
+	var errs []error
+	for _, traders := range groups {
+		if err := m.reconcileAccount(ctx, reader, traders, traders[0], mode, report); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		report.Accounts++
+	}
+	if !report.Clean() {
+		logx.WithContext(ctx).Infof("manager: reconcile mode=%s accounts=%d adopted=%d flattened=%d missing=%d mismatched=%d",
+			mode, report.Accounts, len(report.Adopted), len(report.Flattened), len(report.Missing), len(report.Mismatched))
+	}
+	return report, errors.Join(errs...)
+}
+
+// reconcileAccount adds the discrepancies of one exchange account, shared by
+// traders (sorted by ID), to report. Positions only the exchange holds are
+// adopted into, or flattened by, owner according to mode; the others are
+// reported only. It is the single reconciliation path behind startup and the
+// on-demand report.
+func (m *Manager) reconcileAccount(ctx context.Context, reader OpenPositionReader, traders []*VirtualTrader, owner *VirtualTrader, mode string, report *ReconciliationReport) error {
+	type cachedOwner struct {
+		traderID string
+		pos      CachedPosition
+	}
+	cached := make(map[string]cachedOwner)
+	for _, t := range traders {
+		positions, err := reader.OpenPositions(ctx, t.ID)
+		if err != nil {
+			return fmt.Errorf("manager: reconcile: load cached positions for trader %s: %w", t.ID, err)
+		}
+		for _, c := range positions {
+			cached[strings.ToUpper(strings.TrimSpace(c.Symbol))] = cachedOwner{traderID: t.ID, pos: c}
+		}
+	}
+	live, err := owner.ExchangeProvider.GetPositions(ctx)
+	if err != nil {
+		return fmt.Errorf("manager: reconcile: fetch positions for trader %s: %w", owner.ID, err)
+	}
+
+	var errs []error
+	seen := make(map[string]bool, len(live))
+	for _, pos := range live {
+		symbol := strings.ToUpper(strings.TrimSpace(pos.Coin))
+		szi := parseFloat(pos.Szi)
+		if symbol == "" || szi == 0 {
+			continue
+		}
+		seen[symbol] = true
+		d := PositionDiscrepancy{Symbol: symbol, ExchangeSide: positionSide(&pos), ExchangeQty: math.Abs(szi)}
+		c, ok := cached[symbol]
+		if !ok {
+			d.TraderID = owner.ID
+			if err := ctx.Err(); err != nil {
+				errs = append(errs, fmt.Errorf("manager: reconcile trader %s symbol=%s: %w", owner.ID, symbol, err))
+				continue
+			}
+			logx.WithContext(ctx).Infof("manager: trader %s reconcile %s symbol=%s side=%s qty=%.6f: not in persistence", owner.ID, mode, symbol, d.ExchangeSide, d.ExchangeQty)
+			if mode == StartupReconcileAdopt {
+				m.adoptPosition(owner, pos)
+				report.Adopted = append(report.Adopted, d)
+				continue
+			}
+			if m.DryRun() {
+				logx.WithContext(ctx).Infof("manager: dry-run trader %s would flatten untracked position symbol=%s", owner.ID, symbol)
+				continue
+			}
+			closed, err := m.flattenUntracked(ctx, owner, pos)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			if closed {
+				report.Flattened = append(report.Flattened, d)
+			}
+			continue
+		}
+		d.TraderID = c.traderID
+		d.CachedSide = strings.ToLower(strings.TrimSpace(c.pos.Side))
+		d.CachedQty = c.pos.Quantity
+		if d.CachedSide != d.ExchangeSide || math.Abs(d.CachedQty-d.ExchangeQty) > positionQtyTolerance {
+			logx.WithContext(ctx).Errorf("manager: trader %s reconcile mismatch symbol=%s exchange=%s/%.6f cached=%s/%.6f",
+				d.TraderID, symbol, d.ExchangeSide, d.ExchangeQty, d.CachedSide, d.CachedQty)
+			report.Mismatched = append(report.Mismatched, d)
+		}
+	}
+
+	symbols := make([]string, 0, len(cached))
+	for symbol := range cached {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		if seen[symbol] {
+			continue
+		}
+		c := cached[symbol]
+		d := PositionDiscrepancy{
+			TraderID:   c.traderID,
+			Symbol:     symbol,
+			CachedSide: strings.ToLower(strings.TrimSpace(c.pos.Side)),
+			CachedQty:  c.pos.Quantity,
+		}
+		logx.WithContext(ctx).Errorf("manager: trader %s reconcile missing symbol=%s side=%s qty=%.6f: not reported by exchange", d.TraderID, symbol, d.CachedSide, d.CachedQty)
+		report.Missing = append(report.Missing, d)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,56 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange/sim"
+)
+
+func TestReconcileReportsDiscrepancies(t *testing.T) {
+	ctx := context.Background()
+	m, persist, ex := newUntrackedFixture(t, StartupReconcileOff)
+	persist.open["t2"][0].Quantity = 0.4
+	persist.open["t1"] = []CachedPosition{{Symbol: "SOL", Side: "long", Quantity: 3, EntryPrice: 150}}
+
+	clean := sim.New()
+	require.NoError(t, clean.SetMarkPrice(ctx, "ETH", 3000))
+	_, err := clean.IOCMarket(ctx, "ETH", false, 1, 0, false)
+	require.NoError(t, err)
+	m.traders["t3"] = &VirtualTrader{ID: "t3", Exchange: "other", ExchangeProvider: clean}
+	persist.open["t3"] = []CachedPosition{{Symbol: "ETH", Side: "short", Quantity: 1, EntryPrice: 3000}}
+
+	report, err := m.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Accounts)
+	assert.False(t, report.Clean())
+
+	require.Len(t, report.Adopted, 1)
+	assert.Equal(t, PositionDiscrepancy{TraderID: "t1", Symbol: "BTC", ExchangeSide: "short", ExchangeQty: 0.1}, report.Adopted[0])
+	require.Len(t, persist.events, 1, "the adopted position should be recorded")
+	assert.True(t, persist.events[0].Reconciled)
+	assert.Equal(t, "open_short", persist.events[0].Decision.Action)
+
+	require.Len(t, report.Missing, 1)
+	assert.Equal(t, PositionDiscrepancy{TraderID: "t1", Symbol: "SOL", CachedSide: "long", CachedQty: 3}, report.Missing[0])
+
+	require.Len(t, report.Mismatched, 1)
+	assert.Equal(t, PositionDiscrepancy{TraderID: "t2", Symbol: "ETH", ExchangeSide: "long", ExchangeQty: 0.5, CachedSide: "long", CachedQty: 0.4}, report.Mismatched[0])
+
+	positions, err := ex.GetPositions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, positions, 2, "reconciling must not trade")
+}
+
+func TestReconcileWithoutPositionReader(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	m.traders["t1"] = &VirtualTrader{ID: "t1", Exchange: "hl", ExchangeProvider: sim.New()}
+
+	report, err := m.Reconcile(context.Background())
+	require.NoError(t, err)
+	assert.True(t, report.Clean())
+	assert.Zero(t, report.Accounts)
+}
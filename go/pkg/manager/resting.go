@@ -0,0 +1,364 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"nof0-api/pkg/exchange"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// repriceToleranceBps is the drift between a resting order's limit and its
+// target price below which the order is left alone.
+const repriceToleranceBps = 1.0
+
+// restingOrder is a post_only open that rested on the book instead of filling.
+// Size is the order's full qty; Filled and FilledNotional accumulate the fills
+// booked so far.
+type restingOrder struct {
+	Symbol         string
+	Asset          int
+	IsBuy          bool
+	Price          float64
+	PlacedAt       time.Time
+	Oid            int64
+	Size           float64
+	Filled         float64
+	FilledNotional float64
+	Leverage       int
+	Decision       executorpkg.Decision
+}
+
+// trackRestingOrder records a post_only open left resting under cloid.
+func (t *VirtualTrader) trackRestingOrder(cloid string, order restingOrder) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.restingOrders == nil {
+		t.restingOrders = make(map[string]restingOrder)
+	}
+	t.restingOrders[cloid] = order
+}
+
+// restingOrderCloids returns the tracked cloids in a stable order.
+func (t *VirtualTrader) restingOrderCloids() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	cloids := make([]string, 0, len(t.restingOrders))
+	for cloid := range t.restingOrders {
+		cloids = append(cloids, cloid)
+	}
+	sort.Strings(cloids)
+	return cloids
+}
+
+// isResting reports whether resp left at least one order on the book.
+func isResting(resp *exchange.OrderResponse) bool {
+	return restingOid(resp) != 0
+}
+
+// restingOid returns the exchange id of the first order resp left resting.
+func restingOid(resp *exchange.OrderResponse) int64 {
+	if resp == nil {
+		return 0
+	}
+	for _, st := range resp.Response.Data.Statuses {
+		if st.Resting != nil {
+			return st.Resting.Oid
+		}
+	}
+	return 0
+}
+
+// restingFill is the cumulative fill of one resting order.
+type restingFill struct {
+	size     float64
+	notional float64
+	fee      float64
+}
+
+// restingOrderFills sums the account's fills since the oldest tracked order by
+// cloid and by oid. ok is false when the provider cannot report fills.
+func restingOrderFills(ctx context.Context, t *VirtualTrader, cloids []string) (byCloid map[string]restingFill, byOid map[int64]restingFill, ok bool) {
+	history, ok := t.ExchangeProvider.(exchange.FillHistory)
+	if !ok {
+		return nil, nil, false
+	}
+	var since time.Time
+	t.mu.RLock()
+	for _, cloid := range cloids {
+		if at := t.restingOrders[cloid].PlacedAt; since.IsZero() || at.Before(since) {
+			since = at
+		}
+	}
+	t.mu.RUnlock()
+	fills, err := history.GetUserFills(ctx, since.Add(-time.Minute))
+	if err != nil {
+		logx.WithContext(ctx).Errorf("manager: trader %s resting order fills: %v", t.ID, err)
+		return nil, nil, false
+	}
+	byCloid = make(map[string]restingFill)
+	byOid = make(map[int64]restingFill)
+	for _, f := range fills {
+		add := func(acc restingFill) restingFill {
+			acc.size += f.Size()
+			acc.notional += f.Size() * f.Price()
+			acc.fee += parseFloat(f.Fee)
+			return acc
+		}
+		if cloid := strings.ToLower(strings.TrimSpace(f.Cloid)); cloid != "" {
+			byCloid[cloid] = add(byCloid[cloid])
+		}
+		if f.Oid != 0 {
+			byOid[f.Oid] = add(byOid[f.Oid])
+		}
+	}
+	return byCloid, byOid, true
+}
+
+// repriceRestingOrders reconciles tracked post_only opens against the
+// exchange with syncRestingOrders, then reprices the ones still live.
+func (m *Manager) repriceRestingOrders(ctx context.Context, t *VirtualTrader, snaps snapshotCache) {
+	m.repriceLiveOrders(ctx, t, m.syncRestingOrders(ctx, t), snaps)
+}
+
+// syncRestingOrders reconciles tracked post_only opens against the exchange.
+// Fills observed since the last pass are booked as opens (funding, position
+// event, SL/TP for the filled qty) and orders no longer open are forgotten.
+// It returns the orders still resting, by cloid.
+func (m *Manager) syncRestingOrders(ctx context.Context, t *VirtualTrader) map[string]exchange.OrderInfo {
+	cloids := t.restingOrderCloids()
+	if len(cloids) == 0 {
+		return nil
+	}
+	open, err := t.ExchangeProvider.GetOpenOrders(ctx)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("manager: trader %s sync resting orders: get open orders: %v", t.ID, err)
+		return nil
+	}
+	remaining := make(map[string]exchange.OrderInfo, len(open))
+	for _, o := range open {
+		if cloid := strings.TrimSpace(o.Order.Cloid); cloid != "" {
+			remaining[strings.ToLower(cloid)] = o.Order
+		}
+	}
+	fillsByCloid, fillsByOid, haveFills := restingOrderFills(ctx, t, cloids)
+
+	live := make(map[string]exchange.OrderInfo, len(cloids))
+	for _, cloid := range cloids {
+		t.mu.RLock()
+		tracked := t.restingOrders[cloid]
+		t.mu.RUnlock()
+		info, isLive := remaining[strings.ToLower(cloid)]
+		size := parseFloat(info.Sz)
+		isLive = isLive && size > 0
+
+		// Total filled so far: the venue's fills when it reports them, else
+		// what has left the book while the order is still open.
+		filled, notional := tracked.Filled, tracked.FilledNotional
+		fee := 0.0 // unknown without fill history
+		switch {
+		case haveFills:
+			fill, ok := fillsByCloid[strings.ToLower(cloid)]
+			if !ok && tracked.Oid != 0 {
+				fill = fillsByOid[tracked.Oid]
+			}
+			filled, notional, fee = fill.size, fill.notional, fill.fee
+		case isLive:
+			if done := tracked.Size - size; done > filled {
+				notional += (done - filled) * tracked.Price
+				filled = done
+			}
+		default:
+			logx.WithContext(ctx).Infof("manager: trader %s resting order left the book symbol=%s cloid=%s; no fill history, unbooked remainder assumed cancelled", t.ID, tracked.Symbol, cloid)
+		}
+		if filled-tracked.Filled > 1e-12 {
+			if closed := m.bookRestingFill(ctx, t, cloid, tracked, filled, notional, fee); closed {
+				t.mu.Lock()
+				delete(t.restingOrders, cloid)
+				t.mu.Unlock()
+				continue
+			}
+		}
+
+		if !isLive {
+			t.mu.Lock()
+			delete(t.restingOrders, cloid)
+			t.mu.Unlock()
+			logx.WithContext(ctx).Infof("manager: trader %s resting order settled symbol=%s cloid=%s filled=%.8f of %.8f", t.ID, tracked.Symbol, cloid, filled, tracked.Size)
+			continue
+		}
+		live[cloid] = info
+	}
+	return live
+}
+
+// repriceLiveOrders modifies the live resting orders in place, keeping their
+// cloid and unfilled size, to rest PostOnlyOffsetBps behind the current mark
+// instead of being abandoned as the price drifts away.
+func (m *Manager) repriceLiveOrders(ctx context.Context, t *VirtualTrader, live map[string]exchange.OrderInfo, snaps snapshotCache) {
+	if len(live) == 0 {
+		return
+	}
+	modifier, ok := t.ExchangeProvider.(interface {
+		ModifyOrderByCloid(context.Context, string, exchange.Order) (*exchange.OrderResponse, error)
+	})
+	if !ok {
+		return
+	}
+	for _, cloid := range t.restingOrderCloids() {
+		info, ok := live[cloid]
+		if !ok {
+			continue
+		}
+		t.mu.RLock()
+		tracked := t.restingOrders[cloid]
+		t.mu.RUnlock()
+		if err := m.repriceRestingOrder(ctx, t, modifier.ModifyOrderByCloid, cloid, tracked, info, snaps); err != nil {
+			logx.WithContext(ctx).Errorf("%v", err)
+		}
+	}
+}
+
+// cancelRestingOrders cancels the live resting opens of a trader that may not
+// add exposure (paused, halted or winding down), then syncs again so a fill
+// racing the cancel is still booked and the cancelled orders are forgotten.
+// Orders whose cancel fails stay tracked for the next pass.
+func (m *Manager) cancelRestingOrders(ctx context.Context, t *VirtualTrader, live map[string]exchange.OrderInfo, reason string) {
+	if len(live) == 0 {
+		return
+	}
+	for _, cloid := range t.restingOrderCloids() {
+		if _, ok := live[cloid]; !ok {
+			continue
+		}
+		t.mu.RLock()
+		tracked := t.restingOrders[cloid]
+		t.mu.RUnlock()
+		if tracked.Oid == 0 {
+			continue
+		}
+		if err := t.ExchangeProvider.CancelOrder(ctx, tracked.Asset, tracked.Oid); err != nil {
+			logx.WithContext(ctx).Errorf("manager: trader %s cancel resting order symbol=%s cloid=%s reason=%s: %v", t.ID, tracked.Symbol, cloid, reason, err)
+			continue
+		}
+		logx.WithContext(ctx).Infof("manager: trader %s cancelled resting order symbol=%s cloid=%s reason=%s", t.ID, tracked.Symbol, cloid, reason)
+	}
+	m.syncRestingOrders(ctx, t)
+}
+
+// bookRestingFill books the qty of a resting order filled since the last pass
+// and records the new totals; fee is the venue's commission on the total
+// filled, or 0 when unknown. It reports true when the position was closed
+// because its stop loss failed, which also cancels the order's remainder.
+func (m *Manager) bookRestingFill(ctx context.Context, t *VirtualTrader, cloid string, tracked restingOrder, filled, notional, fee float64) bool {
+	delta := filled - tracked.Filled
+	price := tracked.Price
+	if filled > 0 && notional > 0 {
+		price = notional / filled
+	}
+	decision := tracked.Decision
+	logx.WithContext(ctx).Infof("manager: trader %s resting order filled symbol=%s cloid=%s new_qty=%.8f total_qty=%.8f avg_px=%.8f", t.ID, tracked.Symbol, cloid, delta, filled, price)
+	prot, err := m.bookOpenFill(ctx, t, &decision, nil, price, delta, filled, tracked.Leverage, tracked.Filled == 0, fillFee{USD: fee, Maker: true})
+	if err != nil {
+		logx.WithContext(ctx).Errorf("%v", err)
+	}
+	if prot != nil && prot.ClosedUnprotected {
+		if tracked.Oid != 0 && filled < tracked.Size {
+			if err := t.ExchangeProvider.CancelOrder(ctx, tracked.Asset, tracked.Oid); err != nil {
+				logx.WithContext(ctx).Errorf("manager: trader %s cancel resting remainder symbol=%s cloid=%s: %v", t.ID, tracked.Symbol, cloid, err)
+			}
+		}
+		return true
+	}
+	t.mu.Lock()
+	if entry, ok := t.restingOrders[cloid]; ok {
+		entry.Filled, entry.FilledNotional = filled, notional
+		t.restingOrders[cloid] = entry
+	}
+	t.mu.Unlock()
+	return false
+}
+
+// repriceRestingOrder moves one live resting order to the post_only price for
+// the current mark when it has drifted more than repriceToleranceBps.
+func (m *Manager) repriceRestingOrder(ctx context.Context, t *VirtualTrader, modify func(context.Context, string, exchange.Order) (*exchange.OrderResponse, error), cloid string, tracked restingOrder, info exchange.OrderInfo, snaps snapshotCache) error {
+	snap := snaps[tracked.Symbol]
+	if snap == nil {
+		var err error
+		if snap, err = t.MarketProvider.Snapshot(ctx, tracked.Symbol); err != nil {
+			return fmt.Errorf("manager: trader %s reprice %s: fetch market snapshot: %w", t.ID, tracked.Symbol, err)
+		}
+	}
+	if snap == nil || !(snap.Price.Last > 0) {
+		return fmt.Errorf("manager: trader %s reprice %s: no mark price", t.ID, tracked.Symbol)
+	}
+	current := parseFloat(info.LimitPx)
+	if current <= 0 {
+		current = tracked.Price
+	}
+	target := postOnlyPrice(snap.Price.Last, tracked.IsBuy, t.PostOnlyOffsetBps)
+	if current > 0 && math.Abs(target-current)/current*10000 < repriceToleranceBps {
+		return nil
+	}
+	size := parseFloat(info.Sz)
+	order := exchange.Order{
+		Asset:     tracked.Asset,
+		IsBuy:     tracked.IsBuy,
+		LimitPx:   formatOrderPrice(ctx, t, tracked.Symbol, target),
+		Sz:        formatOrderSize(ctx, t, tracked.Symbol, size),
+		OrderType: exchange.OrderType{Limit: &exchange.LimitOrderType{TIF: "Alo"}},
+		Cloid:     cloid,
+	}
+	if m.DryRun() {
+		logx.WithContext(ctx).Infof("manager: trader %s dry-run reprice resting order symbol=%s cloid=%s from=%.8f to=%s size=%s", t.ID, tracked.Symbol, cloid, current, order.LimitPx, order.Sz)
+		return nil
+	}
+	resp, err := modify(ctx, cloid, order)
+	if err != nil {
+		return fmt.Errorf("manager: trader %s reprice %s cloid=%s: %w", t.ID, tracked.Symbol, cloid, err)
+	}
+	t.mu.Lock()
+	if entry, ok := t.restingOrders[cloid]; ok {
+		entry.Price = target
+		t.restingOrders[cloid] = entry
+	}
+	t.mu.Unlock()
+	logx.WithContext(ctx).Infof("manager: trader %s repriced resting order symbol=%s cloid=%s from=%.8f to=%s size=%s response=%s", t.ID, tracked.Symbol, cloid, current, order.LimitPx, order.Sz, summarizeOrderResponse(resp))
+	return nil
+}
+
+// formatOrderPrice renders price with the provider's tick rules when it
+// offers them, falling back to 8 decimals.
+func formatOrderPrice(ctx context.Context, t *VirtualTrader, symbol string, price float64) string {
+	if p, ok := t.ExchangeProvider.(interface {
+		FormatPrice(context.Context, string, float64) (string, error)
+	}); ok {
+		if s, err := p.FormatPrice(ctx, symbol, price); err == nil && s != "" {
+			return s
+		} else if err != nil {
+			logx.WithContext(ctx).Infof("manager: format price fallback trader=%s symbol=%s price=%.8f err=%v", t.ID, symbol, price, err)
+		}
+	}
+	return fmt.Sprintf("%.8f", price)
+}
+
+// formatOrderSize renders qty with the provider's lot rules when it offers
+// them, falling back to 8 decimals.
+func formatOrderSize(ctx context.Context, t *VirtualTrader, symbol string, qty float64) string {
+	if p, ok := t.ExchangeProvider.(interface {
+		FormatSize(context.Context, string, float64) (string, error)
+	}); ok {
+		if s, err := p.FormatSize(ctx, symbol, qty); err == nil && s != "" {
+			return s
+		} else if err != nil {
+			logx.WithContext(ctx).Infof("manager: format size fallback trader=%s symbol=%s qty=%.8f err=%v", t.ID, symbol, qty, err)
+		}
+	}
+	return fmt.Sprintf("%.8f", qty)
+}
@@ -0,0 +1,284 @@
+package manager
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// restingExchange leaves every post_only order resting, serves a scripted
+// open-order book and records modifications.
+type restingExchange struct {
+	*sim.Provider
+	placed    []exchange.Order
+	open      []exchange.OrderStatus
+	modified  []exchange.Order
+	stops     []float64
+	cancelled []int64
+}
+
+func (r *restingExchange) PlaceOrder(_ context.Context, order exchange.Order) (*exchange.OrderResponse, error) {
+	r.placed = append(r.placed, order)
+	resp := &exchange.OrderResponse{Status: "ok"}
+	resp.Response.Data.Statuses = []exchange.OrderStatusResponse{{Resting: &exchange.RestingOrder{Oid: int64(len(r.placed))}}}
+	return resp, nil
+}
+
+func (r *restingExchange) GetOpenOrders(context.Context) ([]exchange.OrderStatus, error) {
+	return r.open, nil
+}
+
+func (r *restingExchange) ModifyOrderByCloid(_ context.Context, cloid string, order exchange.Order) (*exchange.OrderResponse, error) {
+	order.Cloid = cloid
+	r.modified = append(r.modified, order)
+	return &exchange.OrderResponse{Status: "ok"}, nil
+}
+
+func (r *restingExchange) CancelOrder(_ context.Context, _ int, oid int64) error {
+	r.cancelled = append(r.cancelled, oid)
+	kept := r.open[:0]
+	for _, o := range r.open {
+		if o.Order.Oid != oid {
+			kept = append(kept, o)
+		}
+	}
+	r.open = kept
+	return nil
+}
+
+func (r *restingExchange) SetStopLoss(_ context.Context, _ string, _ string, qty float64, _ float64) error {
+	r.stops = append(r.stops, qty)
+	return nil
+}
+
+func (r *restingExchange) SetTakeProfit(context.Context, string, string, float64, float64) error {
+	return nil
+}
+
+func TestRestingPostOnlyOpenIsBookedOnlyAsItFills(t *testing.T) {
+	ctx := context.Background()
+	ex := &restingExchange{Provider: sim.New()}
+	require.NoError(t, ex.SetMarkPrice(ctx, "ETH", 3000))
+	persist := &fakePersistence{}
+	m := NewManager(nil, nil, nil, nil, persist)
+	vt := &VirtualTrader{
+		ID:                "maker",
+		ExchangeProvider:  ex,
+		MarketProvider:    newFakeMarket(testSnapshot("ETH", 3000, 0)),
+		OrderStyle:        OrderStylePostOnly,
+		PostOnlyOffsetBps: 10,
+		RiskParams:        RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 1000, MajorCoinLeverage: 3},
+		Cooldown:          make(map[string]time.Time),
+	}
+
+	decision := &executorpkg.Decision{Symbol: "ETH", Action: "open_long", PositionSizeUSD: 300, StopLoss: 2900}
+	require.NoError(t, m.ExecuteDecision(vt, decision))
+	require.Len(t, ex.placed, 1)
+	cloid := ex.placed[0].Cloid
+	qty, err := strconv.ParseFloat(ex.placed[0].Sz, 64)
+	require.NoError(t, err)
+
+	// Resting: nothing is held, so nothing is booked.
+	assert.Empty(t, persist.events, "no open event while the order rests")
+	assert.Empty(t, ex.stops, "no stop loss while the order rests")
+	assert.Empty(t, vt.Funding, "no funding accrual while the order rests")
+	assert.Empty(t, vt.positionOpenedAt)
+
+	// Still untouched on the book: still nothing booked.
+	ex.open = []exchange.OrderStatus{{Order: exchange.OrderInfo{Coin: "ETH", Side: "B", LimitPx: ex.placed[0].LimitPx, Sz: ex.placed[0].Sz, Cloid: cloid}, Status: "open"}}
+	m.repriceRestingOrders(ctx, vt, snapshotCache{})
+	assert.Empty(t, persist.events)
+
+	// A quarter fills: only that quarter is booked and protected.
+	ex.open[0].Order.Sz = strconv.FormatFloat(qty*0.75, 'f', -1, 64)
+	m.repriceRestingOrders(ctx, vt, snapshotCache{})
+	require.Len(t, persist.events, 1)
+	assert.Equal(t, PositionEventOpen, persist.events[0].Event)
+	assert.InDelta(t, qty*0.25, persist.events[0].FillSize, 1e-9)
+	require.Len(t, ex.stops, 1)
+	assert.InDelta(t, qty*0.25, ex.stops[0], 1e-9)
+	assert.Contains(t, vt.Funding, "ETH")
+
+	// It leaves the book and the venue reports no fills: the remainder is
+	// taken as cancelled and nothing more is booked.
+	ex.open = nil
+	m.repriceRestingOrders(ctx, vt, snapshotCache{})
+	assert.Empty(t, vt.restingOrderCloids())
+	assert.Len(t, persist.events, 1)
+	assert.Len(t, ex.stops, 1)
+}
+
+// fillingExchange adds a scripted fill history to restingExchange.
+type fillingExchange struct {
+	*restingExchange
+	fills []exchange.UserFill
+}
+
+func (f *fillingExchange) GetUserFills(context.Context, time.Time) ([]exchange.UserFill, error) {
+	return f.fills, nil
+}
+
+func TestRestingPostOnlyFillsAreBookedFromFillHistory(t *testing.T) {
+	ctx := context.Background()
+	ex := &fillingExchange{restingExchange: &restingExchange{Provider: sim.New()}}
+	require.NoError(t, ex.SetMarkPrice(ctx, "ETH", 3000))
+	persist := &fakePersistence{}
+	m := NewManager(nil, nil, nil, nil, persist)
+	vt := &VirtualTrader{
+		ID:                "maker",
+		ExchangeProvider:  ex,
+		MarketProvider:    newFakeMarket(testSnapshot("ETH", 3000, 0)),
+		OrderStyle:        OrderStylePostOnly,
+		PostOnlyOffsetBps: 10,
+		RiskParams:        RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 1000, MajorCoinLeverage: 3},
+		Cooldown:          make(map[string]time.Time),
+	}
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "ETH", Action: "open_long", PositionSizeUSD: 300, StopLoss: 2900}))
+	require.Len(t, ex.placed, 1)
+
+	// The order filled in two parts, matched by oid, then left the book; a
+	// fill of another order is ignored.
+	ex.fills = []exchange.UserFill{
+		{Coin: "ETH", Px: "2996", Sz: "0.04", Side: "B", Oid: 1, Fee: "0.018"},
+		{Coin: "ETH", Px: "2990", Sz: "0.5", Side: "B", Oid: 99, Fee: "0.2"},
+		{Coin: "ETH", Px: "2998", Sz: "0.06", Side: "B", Oid: 1, Fee: "0.027"},
+	}
+	m.repriceRestingOrders(ctx, vt, snapshotCache{})
+
+	assert.Empty(t, vt.restingOrderCloids())
+	require.Len(t, persist.events, 1)
+	assert.InDelta(t, 0.1, persist.events[0].FillSize, 1e-9)
+	assert.InDelta(t, 2997.2, persist.events[0].FillPrice, 1e-9, "booked at the fills' VWAP")
+	assert.InDelta(t, 0.045, persist.events[0].FeeUSD, 1e-12, "the venue's fees on the order are carried")
+	assert.True(t, persist.events[0].Maker, "post_only fills are maker fills")
+	require.Len(t, ex.stops, 1)
+	assert.InDelta(t, 0.1, ex.stops[0], 1e-9)
+}
+
+func TestRestingFillIsBookedAndRemainderCancelledOnDrawdownPause(t *testing.T) {
+	ctx := context.Background()
+	ex := &fillingExchange{restingExchange: &restingExchange{Provider: sim.NewWithEquity(1000)}}
+	require.NoError(t, ex.SetMarkPrice(ctx, "ETH", 3000))
+	persist := &fakePersistence{}
+	m := NewManager(nil, nil, nil, nil, persist)
+	exec := &fakeExecutor{}
+	vt := newCycleTestTrader(m, "maker", newFakeMarket(testSnapshot("ETH", 3000, 0)), exec)
+	vt.ExchangeProvider = ex
+	vt.OrderStyle = OrderStylePostOnly
+	vt.PostOnlyOffsetBps = 10
+	vt.ExecGuards.MaxDrawdownPct = 5
+	vt.ExecGuards.PauseDurationOnBreach = time.Hour
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "ETH", Action: "open_long", PositionSizeUSD: 300, StopLoss: 2900}))
+	require.Len(t, ex.placed, 1)
+	placed := ex.placed[0]
+
+	// Part of the order filled while the account fell well below its peak.
+	vt.peakEquity = 2000
+	ex.open = []exchange.OrderStatus{{Order: exchange.OrderInfo{Coin: "ETH", Side: "B", LimitPx: placed.LimitPx, Sz: "0.06", Oid: 1, Cloid: placed.Cloid}, Status: "open"}}
+	ex.fills = []exchange.UserFill{{Coin: "ETH", Px: "2997", Sz: "0.04", Side: "B", Oid: 1}}
+	m.runTraderCycle(ctx, vt)
+
+	assert.Zero(t, exec.callCount(), "a paused trader must not consult the executor")
+	assert.True(t, vt.PauseUntil.After(time.Now()))
+	require.Len(t, persist.events, 1, "the fill is booked before the gate ends the cycle")
+	assert.InDelta(t, 0.04, persist.events[0].FillSize, 1e-9)
+	require.Len(t, ex.stops, 1)
+	assert.InDelta(t, 0.04, ex.stops[0], 1e-9)
+	assert.Equal(t, []int64{1}, ex.cancelled, "the unfilled remainder must not rest through the pause")
+	assert.Empty(t, vt.restingOrderCloids())
+	assert.Empty(t, ex.modified)
+}
+
+func TestWindDownCancelsRestingOpens(t *testing.T) {
+	ctx := context.Background()
+	ex := &restingExchange{Provider: sim.New()}
+	require.NoError(t, ex.SetMarkPrice(ctx, "ETH", 3000))
+	m := NewManager(nil, nil, nil, nil, nil)
+	exec := &fakeExecutor{}
+	vt := newCycleTestTrader(m, "maker", newFakeMarket(testSnapshot("ETH", 3000, 0)), exec)
+	vt.ExchangeProvider = ex
+	vt.OrderStyle = OrderStylePostOnly
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "ETH", Action: "open_long", PositionSizeUSD: 300}))
+	require.Len(t, ex.placed, 1)
+	ex.open = []exchange.OrderStatus{{Order: exchange.OrderInfo{Coin: "ETH", Side: "B", LimitPx: ex.placed[0].LimitPx, Sz: ex.placed[0].Sz, Oid: 1, Cloid: ex.placed[0].Cloid}, Status: "open"}}
+
+	m.SetWindDown(true)
+	m.runTraderCycle(ctx, vt)
+	assert.Equal(t, []int64{1}, ex.cancelled)
+	assert.Empty(t, vt.restingOrderCloids())
+}
+
+func TestRepriceRestingOrdersFollowsMark(t *testing.T) {
+	ctx := context.Background()
+	ex := &restingExchange{Provider: sim.New()}
+	require.NoError(t, ex.SetMarkPrice(ctx, "ETH", 3000))
+	mk := newFakeMarket(testSnapshot("ETH", 3000, 0))
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := &VirtualTrader{
+		ID:                "maker",
+		ExchangeProvider:  ex,
+		MarketProvider:    mk,
+		OrderStyle:        OrderStylePostOnly,
+		PostOnlyOffsetBps: 10,
+		RiskParams:        RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 1000, MajorCoinLeverage: 3},
+		Cooldown:          make(map[string]time.Time),
+	}
+
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "ETH", Action: "open_long", PositionSizeUSD: 300}))
+	require.Len(t, ex.placed, 1)
+	cloid := ex.placed[0].Cloid
+	require.Equal(t, []string{cloid}, vt.restingOrderCloids(), "a resting post_only open is tracked by cloid")
+
+	// Part of the order filled and the market ran away from the bid.
+	mk.snaps["ETH"] = testSnapshot("ETH", 3100, 0)
+	ex.open = []exchange.OrderStatus{{Order: exchange.OrderInfo{Coin: "ETH", Side: "B", LimitPx: ex.placed[0].LimitPx, Sz: "0.06", Cloid: cloid}, Status: "open"}}
+	m.repriceRestingOrders(ctx, vt, snapshotCache{})
+
+	require.Len(t, ex.modified, 1)
+	mod := ex.modified[0]
+	assert.Equal(t, cloid, mod.Cloid)
+	assert.True(t, mod.IsBuy)
+	require.NotNil(t, mod.OrderType.Limit)
+	assert.Equal(t, "Alo", mod.OrderType.Limit.TIF)
+	px, err := strconv.ParseFloat(mod.LimitPx, 64)
+	require.NoError(t, err)
+	assert.InDelta(t, 3096.9, px, 1e-6, "reprices to the offset behind the new mark")
+	sz, err := strconv.ParseFloat(mod.Sz, 64)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.06, sz, 1e-9, "keeps only the unfilled remainder")
+
+	// Already at the target: nothing to modify.
+	ex.open[0].Order.LimitPx = mod.LimitPx
+	m.repriceRestingOrders(ctx, vt, snapshotCache{})
+	assert.Len(t, ex.modified, 1)
+
+	// Gone from the book (filled or cancelled): tracking stops.
+	ex.open = nil
+	m.repriceRestingOrders(ctx, vt, snapshotCache{})
+	assert.Len(t, ex.modified, 1)
+	assert.Empty(t, vt.restingOrderCloids())
+}
+
+func TestLimitIOCOrdersAreNotTracked(t *testing.T) {
+	ex := &restingExchange{Provider: sim.New()}
+	require.NoError(t, ex.SetMarkPrice(context.Background(), "ETH", 3000))
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := &VirtualTrader{
+		ID:               "taker",
+		ExchangeProvider: ex,
+		MarketProvider:   newFakeMarket(testSnapshot("ETH", 3000, 0)),
+		OrderStyle:       OrderStyleLimitIOC,
+		RiskParams:       RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 1000, MajorCoinLeverage: 3},
+		Cooldown:         make(map[string]time.Time),
+	}
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "ETH", Action: "open_long", PositionSizeUSD: 300}))
+	assert.Empty(t, vt.restingOrderCloids())
+}
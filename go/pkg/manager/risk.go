@@ -0,0 +1,116 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// PositionRisk describes one open position's share of a trader's exposure.
+type PositionRisk struct {
+	Symbol          string
+	Side            string
+	NotionalUSD     float64
+	MarginUSD       float64
+	Leverage        int
+	UnrealizedPnL   float64
+	ContributionPct float64 // share of total notional, 0..100
+}
+
+// PortfolioRisk aggregates per-position risk for a trader.
+type PortfolioRisk struct {
+	TraderID         string
+	Positions        []PositionRisk // sorted by descending notional
+	TotalNotionalUSD float64
+	TotalMarginUSD   float64
+	ComputedAt       time.Time
+}
+
+// Contribution returns the contribution percentage for symbol, or 0 when not held.
+func (p *PortfolioRisk) Contribution(symbol string) float64 {
+	if p == nil {
+		return 0
+	}
+	for _, pos := range p.Positions {
+		if pos.Symbol == symbol {
+			return pos.ContributionPct
+		}
+	}
+	return 0
+}
+
+// RiskBreakdown reports each open position's notional, margin and share of the
+// trader's total notional exposure, using live exchange positions.
+func (m *Manager) RiskBreakdown(traderID string) (*PortfolioRisk, error) {
+	if m == nil {
+		return nil, errors.New("manager: nil manager")
+	}
+	m.mu.RLock()
+	t, ok := m.traders[traderID]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("manager: trader %s not found", traderID)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	raw, err := t.ExchangeProvider.GetPositions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("manager: fetch positions for trader %s: %w", traderID, err)
+	}
+
+	out := &PortfolioRisk{TraderID: traderID, ComputedAt: time.Now()}
+	for _, p := range raw {
+		qty := parseFloat(p.Szi)
+		if qty == 0 {
+			continue
+		}
+		side := "long"
+		if qty < 0 {
+			side = "short"
+		}
+		notional := math.Abs(parseFloat(p.PositionValue))
+		if notional == 0 {
+			notional = math.Abs(qty) * parsePtrFloat(p.EntryPx)
+		}
+		margin := notional
+		if p.Leverage.Value > 0 {
+			margin = notional / float64(p.Leverage.Value)
+		}
+		out.Positions = append(out.Positions, PositionRisk{
+			Symbol:        p.Coin,
+			Side:          side,
+			NotionalUSD:   notional,
+			MarginUSD:     margin,
+			Leverage:      p.Leverage.Value,
+			UnrealizedPnL: parseFloat(p.UnrealizedPnl),
+		})
+		out.TotalNotionalUSD += notional
+		out.TotalMarginUSD += margin
+	}
+	if out.TotalNotionalUSD > 0 {
+		for i := range out.Positions {
+			out.Positions[i].ContributionPct = 100 * out.Positions[i].NotionalUSD / out.TotalNotionalUSD
+		}
+	}
+	sort.SliceStable(out.Positions, func(i, j int) bool {
+		return out.Positions[i].NotionalUSD > out.Positions[j].NotionalUSD
+	})
+	return out, nil
+}
+
+// requireProtectiveOrders rejects an open that lacks the stop-loss or
+// take-profit the trader's risk parameters require.
+func requireProtectiveOrders(r RiskParameters, d *executorpkg.Decision) error {
+	if r.StopLossEnabled && !(d.StopLoss > 0) {
+		return errors.New("stop_loss required by risk_params.stop_loss_enabled but missing")
+	}
+	if r.TakeProfitEnabled && !(d.TakeProfit > 0) {
+		return errors.New("take_profit required by risk_params.take_profit_enabled but missing")
+	}
+	return nil
+}
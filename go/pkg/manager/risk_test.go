@@ -0,0 +1,81 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// staticPositionsExchange returns a fixed set of positions.
+type staticPositionsExchange struct {
+	*sim.Provider
+	positions []exchange.Position
+}
+
+func (s *staticPositionsExchange) GetPositions(context.Context) ([]exchange.Position, error) {
+	return s.positions, nil
+}
+
+func TestRiskBreakdown(t *testing.T) {
+	ex := &staticPositionsExchange{Provider: sim.New(), positions: []exchange.Position{
+		{Coin: "BTC", Szi: "0.01", PositionValue: "600", Leverage: exchange.Leverage{Type: "cross", Value: 10}, UnrealizedPnl: "5"},
+		{Coin: "ETH", Szi: "-0.5", PositionValue: "1800", Leverage: exchange.Leverage{Type: "cross", Value: 5}, UnrealizedPnl: "-3"},
+	}}
+	m := NewManager(nil, nil, nil, nil, nil)
+	m.traders["t1"] = &VirtualTrader{ID: "t1", ExchangeProvider: ex}
+
+	risk, err := m.RiskBreakdown("t1")
+	require.NoError(t, err)
+	require.Len(t, risk.Positions, 2)
+
+	assert.InDelta(t, 2400.0, risk.TotalNotionalUSD, 1e-9)
+	assert.InDelta(t, 60.0+360.0, risk.TotalMarginUSD, 1e-9)
+
+	var notional, margin, contribution float64
+	for _, p := range risk.Positions {
+		notional += p.NotionalUSD
+		margin += p.MarginUSD
+		contribution += p.ContributionPct
+	}
+	assert.InDelta(t, risk.TotalNotionalUSD, notional, 1e-9, "per-symbol notionals should sum to the total")
+	assert.InDelta(t, risk.TotalMarginUSD, margin, 1e-9, "per-symbol margins should sum to the total")
+	assert.InDelta(t, 100.0, contribution, 1e-9, "contributions should sum to 100%")
+
+	assert.Equal(t, "ETH", risk.Positions[0].Symbol, "largest exposure should sort first")
+	assert.Equal(t, "short", risk.Positions[0].Side)
+	assert.InDelta(t, 75.0, risk.Contribution("ETH"), 1e-9)
+	assert.InDelta(t, 25.0, risk.Contribution("BTC"), 1e-9)
+
+	_, err = m.RiskBreakdown("missing")
+	assert.Error(t, err)
+}
+
+func TestExecuteDecisionRequiresStopLossAndTakeProfit(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := newCycleTestTrader(m, "protect", newFakeMarket(testSnapshot("SOL", 150, 0)), &fakeExecutor{})
+	vt.RiskParams.StopLossEnabled = true
+
+	_, err := m.executeDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 150, TakeProfit: 170})
+	require.ErrorContains(t, err, "stop_loss required")
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardSLTPRequired])
+	positions, err := vt.ExchangeProvider.GetPositions(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, positions, "rejected open must not reach the exchange")
+
+	vt.RiskParams.TakeProfitEnabled = true
+	_, err = m.executeDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_short", PositionSizeUSD: 150, StopLoss: 160})
+	require.ErrorContains(t, err, "take_profit required")
+
+	_, err = m.executeDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 150, StopLoss: 140, TakeProfit: 170})
+	require.NoError(t, err, "opens carrying both legs pass")
+
+	vt.RiskParams.StopLossEnabled, vt.RiskParams.TakeProfitEnabled = false, false
+	_, err = m.executeDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "close_long"})
+	require.NoError(t, err, "closes never need SL/TP")
+}
@@ -0,0 +1,147 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"nof0-api/pkg/exchange"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// partialCloseQty returns the quantity a close decision scales out of pos and
+// true, or false when the decision closes the whole position. CloseFraction
+// takes precedence; otherwise a PositionSizeUSD below the position notional
+// at price selects a partial close. Without a price the position's mark is used.
+func partialCloseQty(d *executorpkg.Decision, pos *exchange.Position, price float64) (float64, bool) {
+	if pos == nil {
+		return 0, false
+	}
+	held := math.Abs(parseFloat(pos.Szi))
+	if held == 0 {
+		return 0, false
+	}
+	if price <= 0 {
+		price = positionMark(pos)
+	}
+	var qty float64
+	switch {
+	case d.CloseFraction > 0:
+		qty = held * d.CloseFraction
+	case d.PositionSizeUSD > 0 && price > 0:
+		qty = d.PositionSizeUSD / price
+	default:
+		return 0, false
+	}
+	if qty >= held*(1-1e-9) {
+		return 0, false
+	}
+	return qty, true
+}
+
+// scaleOut reduces pos by qty with a reduce-only market IOC, leaving the rest
+// of the position and its protective orders in place. The realized PnL of the
+// reduced slice is booked without counting a closed trade; funding stays on
+// the position until it is fully closed.
+func (m *Manager) scaleOut(ctx context.Context, t *VirtualTrader, d *executorpkg.Decision, pos *exchange.Position, qty, refPrice float64) (*ExecutedOrder, error) {
+	szi := parseFloat(pos.Szi)
+	if refPrice <= 0 {
+		refPrice = positionMark(pos)
+	}
+	if m.DryRun() {
+		logx.Infof("manager: trader %s dry-run scale out symbol=%s action=%s method=IOCMarket size=%.8f price=%.8f reduce_only=true", t.ID, d.Symbol, d.Action, qty, refPrice)
+		return &ExecutedOrder{
+			Symbol:      d.Symbol,
+			Action:      d.Action,
+			Price:       refPrice,
+			Size:        qty,
+			NotionalUSD: refPrice * qty,
+			ReduceOnly:  true,
+			DryRun:      true,
+		}, nil
+	}
+	execProvider, ok := t.ExchangeProvider.(interface {
+		IOCMarket(context.Context, string, bool, float64, float64, bool) (*exchange.OrderResponse, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("manager: trader %s partial close %s requires market IOC support", t.ID, d.Symbol)
+	}
+	slippage := t.MarketIOCSlippageBps / 10000.0
+	if slippage <= 0 {
+		slippage = defaultMarketIOCSlippageBps / 10000.0
+	}
+	resp, err := execProvider.IOCMarket(ctx, d.Symbol, szi < 0, qty, slippage, true)
+	if err != nil {
+		return nil, err
+	}
+	fillPrice, fillQty, ok := parseOrderFill(resp)
+	if !ok || fillPrice <= 0 {
+		fillPrice = refPrice
+		if fillPrice <= 0 {
+			fillPrice = d.EntryPrice
+		}
+	}
+	if fillQty <= 0 {
+		fillQty = qty
+	}
+	var pnl float64
+	if entry := parsePtrFloat(pos.EntryPx); entry > 0 && fillPrice > 0 {
+		pnl = (fillPrice - entry) * math.Copysign(fillQty, szi)
+	}
+	t.recordPartialClose(pnl)
+	logx.Infof("manager: trader %s scaled out symbol=%s action=%s size=%.8f of %.8f price=%.8f pnl=%.2f", t.ID, d.Symbol, d.Action, fillQty, math.Abs(szi), fillPrice, pnl)
+	m.recordPositionEvent(PositionEvent{
+		TraderID:         t.ID,
+		Trader:           t,
+		Decision:         *d,
+		Event:            PositionEventReduce,
+		ExchangeResponse: resp,
+		FillPrice:        fillPrice,
+		FillSize:         fillQty,
+		OccurredAt:       time.Now(),
+	})
+	return &ExecutedOrder{
+		Symbol:      d.Symbol,
+		Action:      d.Action,
+		Price:       fillPrice,
+		Size:        fillQty,
+		NotionalUSD: fillPrice * fillQty,
+		ReduceOnly:  true,
+	}, nil
+}
+
+// positionMark derives the mark price of pos from its notional value, or 0
+// when the exchange did not report one.
+func positionMark(pos *exchange.Position) float64 {
+	held := math.Abs(parseFloat(pos.Szi))
+	if held == 0 {
+		return 0
+	}
+	return math.Abs(parseFloat(pos.PositionValue)) / held
+}
+
+// minOrderNotionalUSD is the smallest order value t submits, falling back to
+// the exchange minimum when unset.
+func minOrderNotionalUSD(t *VirtualTrader) float64 {
+	if t.MinOrderNotionalUSD > 0 {
+		return t.MinOrderNotionalUSD
+	}
+	return defaultMinOrderNotionalUSD
+}
+
+// belowMinResidual reports the notional pos would keep after scaling out qty
+// and whether that residual falls below the trader's minimum order notional,
+// i.e. could no longer be closed on its own. Without a price it reports false.
+func belowMinResidual(t *VirtualTrader, pos *exchange.Position, qty, price float64) (float64, bool) {
+	if price <= 0 {
+		price = positionMark(pos)
+	}
+	if price <= 0 {
+		return 0, false
+	}
+	residual := (math.Abs(parseFloat(pos.Szi)) - qty) * price
+	return residual, residual < minOrderNotionalUSD(t)
+}
@@ -0,0 +1,129 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+func newScaleOutTrader(t *testing.T, symbol string, price float64) (*Manager, *VirtualTrader, *fakePersistence) {
+	ex := sim.New()
+	require.NoError(t, ex.SetMarkPrice(context.Background(), symbol, price))
+	persist := &fakePersistence{}
+	m := NewManager(nil, nil, nil, nil, persist)
+	vt := &VirtualTrader{
+		ID:               "scale",
+		ExchangeProvider: ex,
+		MarketProvider:   newFakeMarket(testSnapshot(symbol, price, 0)),
+		OrderStyle:       OrderStyleMarketIOC,
+		RiskParams:       RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 2000, MajorCoinLeverage: 5, AltcoinLeverage: 3},
+		Cooldown:         make(map[string]time.Time),
+	}
+	m.traders[vt.ID] = vt
+	return m, vt, persist
+}
+
+func TestExecuteDecision_ScalesOutLongByFraction(t *testing.T) {
+	m, vt, persist := newScaleOutTrader(t, "BTC", 60000)
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "BTC", Action: "open_long", PositionSizeUSD: 1200}))
+	before := findPosition(context.Background(), vt, "BTC")
+	require.NotNil(t, before)
+	held := parseFloat(before.Szi)
+
+	order, err := m.executeDecision(vt, &executorpkg.Decision{Symbol: "BTC", Action: "close_long", CloseFraction: 0.25})
+	require.NoError(t, err)
+	require.NotNil(t, order)
+	assert.True(t, order.ReduceOnly)
+	assert.InDelta(t, held*0.25, order.Size, 1e-9)
+
+	after := findPosition(context.Background(), vt, "BTC")
+	require.NotNil(t, after, "a scale-out must leave the rest of the position open")
+	assert.InDelta(t, held*0.75, parseFloat(after.Szi), 1e-9)
+
+	require.Len(t, persist.events, 2)
+	reduce := persist.events[1]
+	assert.Equal(t, PositionEventReduce, reduce.Event)
+	assert.InDelta(t, held*0.25, reduce.FillSize, 1e-9)
+	assert.Zero(t, vt.Performance.TotalTrades, "a scale-out is not a closed trade")
+	assert.True(t, vt.Cooldown["BTC"].IsZero(), "the close cooldown starts only on a full close")
+
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "BTC", Action: "close_long"}))
+	assert.Nil(t, findPosition(context.Background(), vt, "BTC"))
+	require.Len(t, persist.events, 3)
+	assert.Equal(t, PositionEventClose, persist.events[2].Event)
+	assert.InDelta(t, held*0.75, persist.events[2].FillSize, 1e-9)
+	assert.Equal(t, 1, vt.Performance.TotalTrades)
+}
+
+func TestExecuteDecision_ScalesOutShortByNotional(t *testing.T) {
+	m, vt, persist := newScaleOutTrader(t, "SOL", 150)
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_short", PositionSizeUSD: 900}))
+	before := findPosition(context.Background(), vt, "SOL")
+	require.NotNil(t, before)
+	held := parseFloat(before.Szi)
+	require.Less(t, held, 0.0)
+
+	order, err := m.executeDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "close_short", PositionSizeUSD: 300})
+	require.NoError(t, err)
+	require.NotNil(t, order)
+	assert.InDelta(t, 2.0, order.Size, 1e-9, "300 usd at 150 scales out 2 SOL")
+
+	after := findPosition(context.Background(), vt, "SOL")
+	require.NotNil(t, after)
+	assert.InDelta(t, held+2, parseFloat(after.Szi), 1e-9, "the short shrinks toward zero")
+	require.Len(t, persist.events, 2)
+	assert.Equal(t, PositionEventReduce, persist.events[1].Event)
+
+	// A size at or above the position notional is a full close.
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "close_short", PositionSizeUSD: 5000}))
+	assert.Nil(t, findPosition(context.Background(), vt, "SOL"))
+	assert.Equal(t, PositionEventClose, persist.events[2].Event)
+}
+
+func TestPartialCloseQty(t *testing.T) {
+	long := &exchange.Position{Coin: "ETH", Szi: "2", PositionValue: "100"}
+	qty, ok := partialCloseQty(&executorpkg.Decision{CloseFraction: 0.5}, long, 50)
+	require.True(t, ok)
+	assert.InDelta(t, 1.0, qty, 1e-12)
+
+	_, ok = partialCloseQty(&executorpkg.Decision{CloseFraction: 1}, long, 50)
+	assert.False(t, ok, "a fraction of one is a full close")
+	_, ok = partialCloseQty(&executorpkg.Decision{}, long, 50)
+	assert.False(t, ok, "no fraction or size is a full close")
+
+	qty, ok = partialCloseQty(&executorpkg.Decision{PositionSizeUSD: 25}, long, 0)
+	require.True(t, ok, "the position mark stands in for a missing price")
+	assert.InDelta(t, 0.5, qty, 1e-12)
+	assert.InDelta(t, 50, positionMark(long), 1e-12)
+	assert.Zero(t, positionMark(&exchange.Position{Szi: "0"}))
+}
+
+func TestExecuteDecision_RejectsOpenBelowMinNotional(t *testing.T) {
+	m, vt, persist := newScaleOutTrader(t, "SOL", 150)
+	vt.MinOrderNotionalUSD = 25
+
+	err := m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 12})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "below min_order_notional_usd")
+	assert.Empty(t, persist.events, "a sub-minimum open must not reach the exchange")
+	assert.Nil(t, findPosition(context.Background(), vt, "SOL"))
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardMinNotional])
+}
+
+func TestExecuteDecision_ClosesInFullWhenResidualBelowMinNotional(t *testing.T) {
+	m, vt, persist := newScaleOutTrader(t, "SOL", 150)
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 300}))
+
+	// Scaling out 295 of 300 usd would leave a 5 usd residual.
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "close_long", PositionSizeUSD: 295}))
+	assert.Nil(t, findPosition(context.Background(), vt, "SOL"), "the dust residual is closed with the rest")
+	require.Len(t, persist.events, 2)
+	assert.Equal(t, PositionEventClose, persist.events[1].Event)
+}
@@ -0,0 +1,47 @@
+package manager
+
+import (
+	"sort"
+	"sync"
+)
+
+// fairScheduler hands out decision slots round-robin: among eligible traders,
+// the one served least recently goes first, so a trader with a short decision
+// interval cannot monopolise a limited number of concurrent slots.
+type fairScheduler struct {
+	mu         sync.Mutex
+	seq        uint64
+	lastServed map[string]uint64
+}
+
+func newFairScheduler() *fairScheduler {
+	return &fairScheduler{lastServed: make(map[string]uint64)}
+}
+
+// next returns up to slots traders from eligible ordered by fairness and marks
+// them as served. Traders never served before come first, ties break by ID.
+func (s *fairScheduler) next(eligible []*VirtualTrader, slots int) []*VirtualTrader {
+	if slots <= 0 || len(eligible) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ordered := make([]*VirtualTrader, len(eligible))
+	copy(ordered, eligible)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		li, lj := s.lastServed[ordered[i].ID], s.lastServed[ordered[j].ID]
+		if li != lj {
+			return li < lj
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+	if len(ordered) > slots {
+		ordered = ordered[:slots]
+	}
+	for _, t := range ordered {
+		s.seq++
+		s.lastServed[t.ID] = s.seq
+	}
+	return ordered
+}
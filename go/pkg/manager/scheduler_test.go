@@ -0,0 +1,59 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFairScheduler_SlowTraderNotStarved(t *testing.T) {
+	s := newFairScheduler()
+	fast := &VirtualTrader{ID: "a_fast"}
+	slow := &VirtualTrader{ID: "b_slow"}
+
+	served := map[string]int{}
+	slowDue := false
+	maxWait, wait := 0, 0
+	for round := 0; round < 12; round++ {
+		// The fast trader is eligible every round; the slow one becomes due every
+		// third round and stays due until it is served.
+		if round%3 == 0 {
+			slowDue = true
+		}
+		eligible := []*VirtualTrader{fast}
+		if slowDue {
+			eligible = append(eligible, slow)
+		}
+		picked := s.next(eligible, 1)
+		if !assert.Len(t, picked, 1, "one slot should be filled each round") {
+			return
+		}
+		served[picked[0].ID]++
+		if picked[0] == slow {
+			slowDue = false
+			wait = 0
+		} else if slowDue {
+			wait++
+			if wait > maxWait {
+				maxWait = wait
+			}
+		}
+	}
+	assert.Equal(t, 4, served["b_slow"], "slow trader should get a cycle for every interval it becomes due")
+	assert.Equal(t, 8, served["a_fast"], "fast trader should fill the remaining rounds")
+	assert.LessOrEqual(t, maxWait, 1, "slow trader should wait at most one round under contention")
+}
+
+func TestFairScheduler_RoundRobin(t *testing.T) {
+	s := newFairScheduler()
+	traders := []*VirtualTrader{{ID: "t1"}, {ID: "t2"}, {ID: "t3"}}
+
+	var order []string
+	for i := 0; i < 6; i++ {
+		for _, p := range s.next(traders, 1) {
+			order = append(order, p.ID)
+		}
+	}
+	assert.Equal(t, []string{"t1", "t2", "t3", "t1", "t2", "t3"}, order, "slots should rotate across contending traders")
+	assert.Len(t, s.next(traders, 5), 3, "slots beyond eligible traders should not duplicate picks")
+}
@@ -0,0 +1,127 @@
+package manager
+
+import (
+	"math"
+	"time"
+)
+
+// minEquitySamplesForSharpe is the number of equity samples inside the
+// lookback and window needed for a curve Sharpe; fewer leave the gate without data.
+const minEquitySamplesForSharpe = 3
+
+// sharpeYear is the span equity-curve Sharpe ratios are annualised to.
+const sharpeYear = 365 * 24 * time.Hour
+
+// equitySample is one point of a trader's equity curve.
+type equitySample struct {
+	At        time.Time
+	EquityUSD float64
+}
+
+// recordEquity appends an equity sample when a Sharpe lookback or window is
+// configured and drops samples that have aged out of either.
+func (t *VirtualTrader) recordEquity(at time.Time, equityUSD float64) {
+	lookback, window := t.ExecGuards.SharpeLookback, t.ExecGuards.SharpeWindow
+	if (lookback <= 0 && window <= 0) || !(equityUSD > 0) {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.equityCurve = append(t.equityCurve, equitySample{At: at, EquityUSD: equityUSD})
+	drop := 0
+	if lookback > 0 {
+		cutoff := at.Add(-lookback)
+		for drop < len(t.equityCurve) && t.equityCurve[drop].At.Before(cutoff) {
+			drop++
+		}
+	}
+	// A window of N returns needs N+1 samples.
+	if window > 0 && len(t.equityCurve)-drop > window+1 {
+		drop = len(t.equityCurve) - window - 1
+	}
+	t.equityCurve = append(t.equityCurve[:0], t.equityCurve[drop:]...)
+}
+
+// equitySharpe returns the annualised Sharpe ratio of the returns between
+// consecutive equity samples inside the lookback ending at now, limited to
+// the last sharpe_window returns, and false when there are too few samples.
+func (t *VirtualTrader) equitySharpe(now time.Time) (float64, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	lookback, window := t.ExecGuards.SharpeLookback, t.ExecGuards.SharpeWindow
+	samples := t.equityCurve
+	if lookback > 0 {
+		cutoff := now.Add(-lookback)
+		first := 0
+		for first < len(samples) && samples[first].At.Before(cutoff) {
+			first++
+		}
+		samples = samples[first:]
+	}
+	if window > 0 && len(samples) > window+1 {
+		samples = samples[len(samples)-window-1:]
+	}
+	if len(samples) < minEquitySamplesForSharpe {
+		return 0, false
+	}
+	returns := make([]float64, 0, len(samples)-1)
+	for i := 1; i < len(samples); i++ {
+		returns = append(returns, samples[i].EquityUSD/samples[i-1].EquityUSD-1)
+	}
+	period := samples[len(samples)-1].At.Sub(samples[0].At) / time.Duration(len(returns))
+	return annualizeSharpe(sharpeOfReturns(returns), period), true
+}
+
+// annualizeSharpe scales a per-period Sharpe ratio to a year of periods of
+// the given length. Crypto trades around the clock, so a year is 365 days.
+func annualizeSharpe(sharpe float64, period time.Duration) float64 {
+	if period <= 0 {
+		return sharpe
+	}
+	return sharpe * math.Sqrt(float64(sharpeYear)/float64(period))
+}
+
+// sharpeOfReturns is the mean over the sample standard deviation of returns,
+// or 0 without dispersion.
+func sharpeOfReturns(returns []float64) float64 {
+	if len(returns) < 2 {
+		return 0
+	}
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(len(returns) - 1)
+	if variance <= 0 {
+		return 0
+	}
+	return mean / math.Sqrt(variance)
+}
+
+// gateSharpe returns the Sharpe ratio the pause gate compares against its
+// threshold. It reports false, disabling the gate, while the trader has
+// closed fewer than sharpe_min_trades trades or its equity curve is too
+// short to judge.
+func (t *VirtualTrader) gateSharpe(now time.Time) (float64, bool) {
+	g := t.ExecGuards
+	t.mu.RLock()
+	perf := t.Performance
+	var sharpe float64
+	trades := 0
+	if perf != nil {
+		sharpe, trades = perf.SharpeRatio, perf.TotalTrades
+	}
+	t.mu.RUnlock()
+	if perf == nil || trades < g.SharpeMinTrades {
+		return 0, false
+	}
+	if g.SharpeLookback > 0 || g.SharpeWindow > 0 {
+		return t.equitySharpe(now)
+	}
+	return sharpe, true
+}
@@ -0,0 +1,122 @@
+package manager
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSharpeGatedTrader(m *Manager, exec *fakeExecutor) *VirtualTrader {
+	vt := newCycleTestTrader(m, "gated", newFakeMarket(testSnapshot("BTC", 60000, 0.01)), exec)
+	off := false
+	vt.ExecGuards = ExecGuards{
+		SharpePauseThreshold:  -0.5,
+		PauseDurationOnBreach: time.Hour,
+		SharpeMinTrades:       10,
+		EnableSparseDataGuard: &off,
+	}
+	return vt
+}
+
+func TestSharpeGateSuppressedBelowMinimumTrades(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	exec := &fakeExecutor{}
+	vt := newSharpeGatedTrader(m, exec)
+	vt.Performance = &PerformanceMetrics{SharpeRatio: -3, TotalTrades: 4}
+
+	m.runTraderCycle(context.Background(), vt)
+	assert.Equal(t, 1, exec.callCount(), "a small sample should not pause the trader")
+	assert.Zero(t, m.GuardRejections(vt.ID)[GuardSharpePause])
+	assert.True(t, vt.PauseUntil.IsZero())
+
+	vt.Performance.TotalTrades = 10
+	m.runTraderCycle(context.Background(), vt)
+	assert.Equal(t, 1, exec.callCount(), "the gate should apply once the sample is large enough")
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardSharpePause])
+	assert.False(t, vt.PauseUntil.IsZero())
+}
+
+func TestSharpeGateUsesEquityCurveLookback(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	exec := &fakeExecutor{}
+	vt := newSharpeGatedTrader(m, exec)
+	vt.ExecGuards.SharpeLookback = time.Hour
+	// The trade statistics look healthy; only the equity curve is losing.
+	vt.Performance = &PerformanceMetrics{SharpeRatio: 2, TotalTrades: 12}
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.SetClock(fixedClock{t: start.Add(50 * time.Minute)})
+	// Stale gains outside the lookback are ignored.
+	vt.recordEquity(start.Add(-3*time.Hour), 800)
+	vt.recordEquity(start.Add(-2*time.Hour), 1200)
+	_, ok := vt.gateSharpe(m.now())
+	assert.False(t, ok, "too few samples inside the lookback")
+
+	for i, equity := range []float64{1000, 990, 985, 970, 962, 950} {
+		vt.recordEquity(start.Add(time.Duration(i)*10*time.Minute), equity)
+	}
+	require.Len(t, vt.equityCurve, 6, "samples older than the lookback should be pruned")
+	sharpe, ok := vt.gateSharpe(m.now())
+	require.True(t, ok)
+	assert.Less(t, sharpe, -0.5)
+
+	m.runTraderCycle(context.Background(), vt)
+	assert.Zero(t, exec.callCount(), "a losing equity curve should pause the trader")
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardSharpePause])
+}
+
+func TestSharpeOfReturns(t *testing.T) {
+	assert.Zero(t, sharpeOfReturns([]float64{0.01}), "one return has no dispersion")
+	assert.Zero(t, sharpeOfReturns([]float64{0.01, 0.01}), "constant returns have no dispersion")
+	assert.InDelta(t, 1.0, sharpeOfReturns([]float64{0.02, 0, 0.01}), 1e-9)
+}
+
+func TestSharpeGateUsesRollingWindow(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	exec := &fakeExecutor{}
+	vt := newSharpeGatedTrader(m, exec)
+	vt.ExecGuards.SharpeWindow = 4
+	vt.Performance = &PerformanceMetrics{SharpeRatio: 2, TotalTrades: 12}
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.SetClock(fixedClock{t: start.Add(2 * time.Hour)})
+	// Early gains fall out of the window; the last four cycles lose steadily.
+	for i, equity := range []float64{800, 900, 1000, 1000, 995, 985, 980, 970} {
+		vt.recordEquity(start.Add(time.Duration(i)*15*time.Minute), equity)
+	}
+	require.Len(t, vt.equityCurve, 5, "the window keeps its returns' samples only")
+	assert.Equal(t, 1000.0, vt.equityCurve[0].EquityUSD)
+	sharpe, ok := vt.gateSharpe(m.now())
+	require.True(t, ok)
+	assert.Less(t, sharpe, -0.5)
+
+	m.runTraderCycle(context.Background(), vt)
+	assert.Zero(t, exec.callCount(), "a losing window should pause the trader")
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardSharpePause])
+}
+
+func TestSharpeGateWindowAllowsPositiveReturns(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	exec := &fakeExecutor{}
+	vt := newSharpeGatedTrader(m, exec)
+	vt.ExecGuards.SharpeWindow = 3
+	vt.Performance = &PerformanceMetrics{SharpeRatio: -3, TotalTrades: 12}
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, equity := range []float64{1000, 1010, 1015, 1030} {
+		vt.recordEquity(start.Add(time.Duration(i)*time.Hour), equity)
+	}
+	m.runTraderCycle(context.Background(), vt)
+	assert.Equal(t, 1, exec.callCount(), "the equity curve, not the trade statistics, drives the gate")
+	assert.Zero(t, m.GuardRejections(vt.ID)[GuardSharpePause])
+}
+
+func TestAnnualizeSharpe(t *testing.T) {
+	assert.InDelta(t, math.Sqrt(365), annualizeSharpe(1, 24*time.Hour), 1e-9)
+	assert.InDelta(t, 2*math.Sqrt(365*24), annualizeSharpe(2, time.Hour), 1e-9)
+	assert.Equal(t, 0.5, annualizeSharpe(0.5, 0), "without spacing the ratio is left as is")
+}
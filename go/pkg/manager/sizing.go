@@ -0,0 +1,107 @@
+package manager
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// resolvePctPositionSize converts decision.PositionSizePct into
+// PositionSizeUSD against the trader's last synced available balance. The
+// result is capped at MaxPositionSizeUSD rather than rejected, since the model
+// cannot know the cap in percentage terms. Percentage sizing wins when the
+// decision also carries a USD size.
+func resolvePctPositionSize(trader *VirtualTrader, decision *executorpkg.Decision) error {
+	if decision.PositionSizePct > 100 {
+		return fmt.Errorf("manager: position_size_pct %.2f exceeds 100", decision.PositionSizePct)
+	}
+	trader.mu.RLock()
+	avail := trader.ResourceAlloc.AvailableBalanceUSD
+	trader.mu.RUnlock()
+	if !(avail > 0) {
+		return fmt.Errorf("manager: trader %s position_size_pct requires a positive available balance", trader.ID)
+	}
+	usd := avail * decision.PositionSizePct / 100
+	if limit := trader.RiskParams.MaxPositionSizeUSD; limit > 0 && usd > limit {
+		logx.Infof("manager: trader %s %s position_size_pct=%.2f sized %.2f usd, capped at max_position_size_usd %.2f", trader.ID, decision.Symbol, decision.PositionSizePct, usd, limit)
+		usd = limit
+	}
+	decision.PositionSizeUSD = usd
+	return nil
+}
+
+// kellyMinTrades is the closed-trade history Kelly sizing needs before its
+// win rate and payoff are trusted; with less the model size is used.
+const kellyMinTrades = 10
+
+// applySizingMode replaces the model's PositionSizeUSD with the size the
+// trader's sizing mode derives; the model size is kept when there is no cap
+// to size against.
+//
+// confidence_scaled runs linearly from SizingFloorUSD at confidence 0 to
+// MaxPositionSizeUSD at 100. kelly stakes KellyMultiplier times the Kelly
+// fraction of the trader's equity, capped at MaxPositionSizeUSD; until the
+// trade history can support an estimate the model size stands, and an open
+// with no edge (a non-positive fraction) is rejected.
+func (m *Manager) applySizingMode(trader *VirtualTrader, decision *executorpkg.Decision) error {
+	limit := trader.RiskParams.MaxPositionSizeUSD
+	if !(limit > 0) {
+		return nil
+	}
+	var usd float64
+	switch trader.SizingMode {
+	case SizingModeConfidence:
+		confidence := math.Min(math.Max(float64(decision.Confidence), 0), 100)
+		floor := math.Min(math.Max(trader.SizingFloorUSD, 0), limit)
+		usd = floor + (limit-floor)*confidence/100
+	case SizingModeKelly:
+		trader.mu.RLock()
+		var perf PerformanceMetrics
+		if trader.Performance != nil {
+			perf = *trader.Performance
+		}
+		equity := trader.ResourceAlloc.CurrentEquityUSD
+		if !(equity > 0) {
+			equity = trader.ResourceAlloc.AllocatedEquityUSD
+		}
+		trader.mu.RUnlock()
+		if perf.TotalTrades < kellyMinTrades || perf.WinningTrades == 0 || perf.LosingTrades == 0 || !(equity > 0) {
+			logx.Infof("manager: trader %s %s sizing_mode=kelly keeps model size %.2f usd: trades=%d wins=%d losses=%d equity=%.2f", trader.ID, decision.Symbol, decision.PositionSizeUSD, perf.TotalTrades, perf.WinningTrades, perf.LosingTrades, equity)
+			return nil
+		}
+		f, ok := kellyFraction(perf.WinRate, perf.AvgWinUSD, perf.AvgLossUSD)
+		if !ok {
+			logx.Infof("manager: trader %s %s sizing_mode=kelly keeps model size %.2f usd: win_rate=%.4f avg_win=%.2f avg_loss=%.2f", trader.ID, decision.Symbol, decision.PositionSizeUSD, perf.WinRate, perf.AvgWinUSD, perf.AvgLossUSD)
+			return nil
+		}
+		if f <= 0 {
+			m.recordGuardRejection(trader.ID, GuardKellyNoEdge, 1)
+			return fmt.Errorf("manager: trader %s %s %s rejected: kelly fraction %.4f shows no edge (win_rate=%.4f payoff=%.2f)", trader.ID, decision.Action, decision.Symbol, f, perf.WinRate, perf.AvgWinUSD/math.Abs(perf.AvgLossUSD))
+		}
+		multiplier := trader.KellyMultiplier
+		if !(multiplier > 0) {
+			multiplier = defaultKellyMultiplier
+		}
+		usd = math.Min(f*multiplier*equity, limit)
+	default:
+		return nil
+	}
+	logx.Infof("manager: trader %s %s sizing_mode=%s confidence=%d sized %.2f usd (model %.2f)", trader.ID, decision.Symbol, trader.SizingMode, decision.Confidence, usd, decision.PositionSizeUSD)
+	decision.PositionSizeUSD = usd
+	return nil
+}
+
+// kellyFraction returns the Kelly stake p - (1-p)/b for win rate p and payoff
+// b = avgWin/|avgLoss|. ok is false when the inputs cannot define a stake: a
+// win rate outside (0, 1) or a missing average win or loss.
+func kellyFraction(winRate, avgWin, avgLoss float64) (float64, bool) {
+	avgLoss = math.Abs(avgLoss)
+	if !(winRate > 0 && winRate < 1) || !(avgWin > 0) || !(avgLoss > 0) {
+		return 0, false
+	}
+	payoff := avgWin / avgLoss
+	return winRate - (1-winRate)/payoff, true
+}
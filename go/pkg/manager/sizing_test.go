@@ -0,0 +1,178 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	executorpkg "nof0-api/pkg/executor"
+)
+
+func TestExecuteDecisionPositionSizePct(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	newTrader := func(avail float64) *VirtualTrader {
+		vt := newCycleTestTrader(m, "pct", newFakeMarket(testSnapshot("SOL", 100, 0)), &fakeExecutor{})
+		vt.ResourceAlloc.AvailableBalanceUSD = avail
+		return vt
+	}
+
+	t.Run("ten percent of 1000 is 100 usd", func(t *testing.T) {
+		vt := newTrader(1000)
+		order, err := m.executeDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizePct: 10})
+		require.NoError(t, err)
+		require.NotNil(t, order)
+		assert.InDelta(t, 100, order.NotionalUSD, 1e-6)
+		assert.InDelta(t, 1, order.Size, 1e-9)
+	})
+
+	t.Run("pct takes precedence over usd", func(t *testing.T) {
+		vt := newTrader(1000)
+		d := &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 500, PositionSizePct: 10}
+		order, err := m.executeDecision(vt, d)
+		require.NoError(t, err)
+		assert.InDelta(t, 100, order.NotionalUSD, 1e-6)
+		assert.InDelta(t, 100, d.PositionSizeUSD, 1e-6, "resolved size should be written back to the decision")
+	})
+
+	t.Run("capped by max position size", func(t *testing.T) {
+		vt := newTrader(50000)
+		order, err := m.executeDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizePct: 10})
+		require.NoError(t, err)
+		assert.InDelta(t, vt.RiskParams.MaxPositionSizeUSD, order.NotionalUSD, 1e-6)
+	})
+
+	t.Run("requires a synced balance", func(t *testing.T) {
+		vt := newTrader(0)
+		_, err := m.executeDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizePct: 10})
+		require.ErrorContains(t, err, "available balance")
+		positions, err := vt.ExchangeProvider.GetPositions(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, positions)
+	})
+
+	t.Run("rejects more than 100 percent", func(t *testing.T) {
+		vt := newTrader(1000)
+		_, err := m.executeDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizePct: 120})
+		require.ErrorContains(t, err, "exceeds 100")
+	})
+}
+
+func TestExecuteDecisionConfidenceScaledSizing(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	newTrader := func(floor float64) *VirtualTrader {
+		vt := newCycleTestTrader(m, "conf", newFakeMarket(testSnapshot("SOL", 100, 0)), &fakeExecutor{})
+		vt.SizingMode = SizingModeConfidence
+		vt.SizingFloorUSD = floor
+		return vt
+	}
+
+	t.Run("confidence 50 takes half the max", func(t *testing.T) {
+		vt := newTrader(0)
+		d := &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 900, Confidence: 50}
+		order, err := m.executeDecision(vt, d)
+		require.NoError(t, err)
+		assert.InDelta(t, 500, order.NotionalUSD, 1e-6, "the model size is replaced")
+		assert.InDelta(t, 500, d.PositionSizeUSD, 1e-6)
+	})
+
+	t.Run("scales up from the floor", func(t *testing.T) {
+		vt := newTrader(200)
+		order, err := m.executeDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 100, Confidence: 75})
+		require.NoError(t, err)
+		assert.InDelta(t, 800, order.NotionalUSD, 1e-6)
+	})
+
+	t.Run("model mode keeps the model size", func(t *testing.T) {
+		vt := newTrader(0)
+		vt.SizingMode = SizingModeModel
+		order, err := m.executeDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 300, Confidence: 50})
+		require.NoError(t, err)
+		assert.InDelta(t, 300, order.NotionalUSD, 1e-6)
+	})
+}
+
+func TestValidateSizingMode(t *testing.T) {
+	risk := RiskParameters{MaxPositionSizeUSD: 1000}
+	assert.NoError(t, TraderConfig{SizingMode: SizingModeConfidence, SizingFloorUSD: 100, RiskParams: risk}.validateSizingMode(0))
+	assert.ErrorContains(t, TraderConfig{SizingMode: "martingale", RiskParams: risk}.validateSizingMode(1), "traders[1].sizing_mode")
+	assert.ErrorContains(t, TraderConfig{SizingMode: SizingModeConfidence, SizingFloorUSD: 2000, RiskParams: risk}.validateSizingMode(2), "exceeds risk_params.max_position_size_usd")
+	assert.NoError(t, TraderConfig{SizingMode: SizingModeKelly, KellyMultiplier: 0.25, RiskParams: risk}.validateSizingMode(3))
+	assert.ErrorContains(t, TraderConfig{SizingMode: SizingModeKelly, KellyMultiplier: 1.5, RiskParams: risk}.validateSizingMode(4), "kelly_multiplier")
+}
+
+func TestKellyFraction(t *testing.T) {
+	cases := []struct {
+		name                     string
+		winRate, avgWin, avgLoss float64
+		want                     float64
+		ok                       bool
+	}{
+		{name: "even payoff 60 percent", winRate: 0.6, avgWin: 100, avgLoss: -100, want: 0.2, ok: true},
+		{name: "two to one payoff 40 percent", winRate: 0.4, avgWin: 200, avgLoss: -100, want: 0.1, ok: true},
+		{name: "negative edge", winRate: 0.3, avgWin: 100, avgLoss: -100, want: -0.4, ok: true},
+		{name: "loss sign ignored", winRate: 0.5, avgWin: 300, avgLoss: 100, want: 0.5 - 0.5/3, ok: true},
+		{name: "never lost", winRate: 1, avgWin: 100, avgLoss: -100},
+		{name: "never won", winRate: 0, avgWin: 100, avgLoss: -100},
+		{name: "no losses recorded", winRate: 0.5, avgWin: 100},
+		{name: "no wins recorded", winRate: 0.5, avgLoss: -100},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := kellyFraction(tc.winRate, tc.avgWin, tc.avgLoss)
+			assert.Equal(t, tc.ok, ok)
+			assert.InDelta(t, tc.want, got, 1e-9)
+		})
+	}
+}
+
+func TestExecuteDecisionKellySizing(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	newTrader := func(perf *PerformanceMetrics) *VirtualTrader {
+		vt := newCycleTestTrader(m, "kelly", newFakeMarket(testSnapshot("SOL", 100, 0)), &fakeExecutor{})
+		vt.SizingMode = SizingModeKelly
+		vt.KellyMultiplier = 0.5
+		vt.ResourceAlloc.CurrentEquityUSD = 5000
+		vt.Performance = perf
+		return vt
+	}
+	seasoned := func(winRate float64) *PerformanceMetrics {
+		return &PerformanceMetrics{TotalTrades: 20, WinningTrades: 12, LosingTrades: 8, WinRate: winRate, AvgWinUSD: 100, AvgLossUSD: -100}
+	}
+
+	t.Run("half kelly of equity", func(t *testing.T) {
+		vt := newTrader(seasoned(0.6))
+		order, err := m.executeDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 900})
+		require.NoError(t, err)
+		assert.InDelta(t, 500, order.NotionalUSD, 1e-6, "0.2 kelly * 0.5 * 5000 equity")
+	})
+
+	t.Run("capped by max position size", func(t *testing.T) {
+		vt := newTrader(seasoned(0.6))
+		vt.KellyMultiplier = 1
+		vt.ResourceAlloc.CurrentEquityUSD = 50000
+		order, err := m.executeDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 100})
+		require.NoError(t, err)
+		assert.InDelta(t, vt.RiskParams.MaxPositionSizeUSD, order.NotionalUSD, 1e-6)
+	})
+
+	t.Run("no edge rejects", func(t *testing.T) {
+		vt := newTrader(seasoned(0.3))
+		_, err := m.executeDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 300})
+		require.ErrorContains(t, err, "no edge")
+		assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardKellyNoEdge])
+	})
+
+	t.Run("thin history keeps the model size", func(t *testing.T) {
+		vt := newTrader(&PerformanceMetrics{TotalTrades: 3, WinningTrades: 3, WinRate: 1, AvgWinUSD: 50})
+		order, err := m.executeDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 300})
+		require.NoError(t, err)
+		assert.InDelta(t, 300, order.NotionalUSD, 1e-6)
+
+		vt = newTrader(nil)
+		order, err = m.executeDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 300})
+		require.NoError(t, err)
+		assert.InDelta(t, 300, order.NotionalUSD, 1e-6)
+	})
+}
@@ -0,0 +1,65 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"nof0-api/pkg/market"
+)
+
+// marketIOCSlippageBps is the slippage tolerance a market_ioc order is sent
+// with, falling back to the default when the trader leaves it unset.
+func marketIOCSlippageBps(t *VirtualTrader) float64 {
+	if t.MarketIOCSlippageBps > 0 {
+		return t.MarketIOCSlippageBps
+	}
+	return defaultMarketIOCSlippageBps
+}
+
+// estimateSlippageBps estimates how far from mid a market order of
+// notionalUSD fills, in bps. The venue's impact price gives the slippage at
+// its quoted notional; other sizes scale linearly from there. ok is false
+// when the snapshot carries no usable impact prices.
+func estimateSlippageBps(snap *market.Snapshot, isBuy bool, notionalUSD float64) (bps float64, ok bool) {
+	if snap == nil || snap.Impact == nil || !(snap.Price.Last > 0) {
+		return 0, false
+	}
+	ref := snap.Impact
+	if !(ref.BidPx > 0) || !(ref.AskPx > 0) || !(ref.NotionalUSD > 0) {
+		return 0, false
+	}
+	mid := snap.Price.Last
+	impact := mid - ref.BidPx
+	if isBuy {
+		impact = ref.AskPx - mid
+	}
+	if impact < 0 {
+		impact = 0
+	}
+	return impact / mid * 10000 * notionalUSD / ref.NotionalUSD, true
+}
+
+// gateMarketSlippage rejects a market_ioc open whose estimated slippage for
+// its notional exceeds the trader's market_ioc_slippage_bps, rather than
+// letting it fill deep into a thin book. Snapshots without impact prices pass.
+func (m *Manager) gateMarketSlippage(ctx context.Context, t *VirtualTrader, symbol string, isBuy bool, notionalUSD float64, snap *market.Snapshot) error {
+	if snap == nil {
+		var err error
+		if snap, err = t.MarketProvider.Snapshot(ctx, symbol); err != nil {
+			logx.WithContext(ctx).Infof("manager: trader %s skip slippage estimate symbol=%s err=%v", t.ID, symbol, err)
+			return nil
+		}
+	}
+	est, ok := estimateSlippageBps(snap, isBuy, notionalUSD)
+	if !ok {
+		return nil
+	}
+	limit := marketIOCSlippageBps(t)
+	if est <= limit {
+		return nil
+	}
+	m.recordGuardRejection(t.ID, GuardMarketSlippage, 1)
+	return fmt.Errorf("manager: trader %s market_ioc %s rejected: estimated slippage %.1f bps for %.2f usd exceeds %.1f bps", t.ID, symbol, est, notionalUSD, limit)
+}
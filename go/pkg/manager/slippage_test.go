@@ -0,0 +1,55 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+	"nof0-api/pkg/market"
+)
+
+func TestEstimateSlippageBps(t *testing.T) {
+	snap := testSnapshot("SOL", 150, 0)
+	_, ok := estimateSlippageBps(snap, true, 1000)
+	assert.False(t, ok, "no impact prices, no estimate")
+
+	snap.Impact = &market.ImpactInfo{BidPx: 149.7, AskPx: 150.6, NotionalUSD: 6000}
+	bps, ok := estimateSlippageBps(snap, true, 6000)
+	require.True(t, ok)
+	assert.InDelta(t, 40, bps, 1e-9, "buys pay the impact ask at the quoted notional")
+	bps, _ = estimateSlippageBps(snap, false, 3000)
+	assert.InDelta(t, 10, bps, 1e-9, "sells scale the impact bid linearly with notional")
+}
+
+func TestMarketIOCRejectsOverSlippageEstimate(t *testing.T) {
+	ex := sim.New()
+	require.NoError(t, ex.SetMarkPrice(context.Background(), "SOL", 150))
+	snap := testSnapshot("SOL", 150, 0)
+	// A thin book: 40 bps to buy 6k notional.
+	snap.Impact = &market.ImpactInfo{BidPx: 149.4, AskPx: 150.6, NotionalUSD: 6000}
+	persist := &fakePersistence{}
+	m := NewManager(nil, nil, nil, nil, persist)
+	vt := &VirtualTrader{
+		ID:                   "thin",
+		ExchangeProvider:     ex,
+		MarketProvider:       newFakeMarket(snap),
+		OrderStyle:           OrderStyleMarketIOC,
+		MarketIOCSlippageBps: 5,
+		RiskParams:           RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 2000, AltcoinLeverage: 3},
+		Cooldown:             make(map[string]time.Time),
+	}
+
+	err := m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 1000})
+	require.Error(t, err, "~6.7 bps estimated against a 5 bps limit")
+	assert.Contains(t, err.Error(), "estimated slippage")
+	assert.Empty(t, persist.events, "no order should be placed")
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardMarketSlippage])
+
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "SOL", Action: "open_long", PositionSizeUSD: 300}), "~2 bps fits the limit")
+	require.Len(t, persist.events, 1)
+}
@@ -0,0 +1,61 @@
+package manager
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"nof0-api/pkg/market"
+)
+
+// snapshotFetchConcurrency bounds parallel Snapshot calls within a cycle.
+const snapshotFetchConcurrency = 8
+
+// snapshotCache memoises market snapshots for one decision cycle so each
+// symbol is fetched at most once. A nil entry records a failed fetch.
+type snapshotCache map[string]*market.Snapshot
+
+// fetch loads snapshots for symbols not yet cached, in parallel.
+func (c snapshotCache) fetch(ctx context.Context, provider market.Provider, symbols []string) {
+	missing := make([]string, 0, len(symbols))
+	for _, sym := range symbols {
+		if _, ok := c[sym]; ok {
+			continue
+		}
+		c[sym] = nil // reserve so duplicates in symbols are fetched once
+		missing = append(missing, sym)
+	}
+	if len(missing) == 0 {
+		return
+	}
+	results := make([]*market.Snapshot, len(missing))
+	var g errgroup.Group
+	g.SetLimit(snapshotFetchConcurrency)
+	for i, sym := range missing {
+		g.Go(func() error {
+			// Per-symbol failures leave a nil entry; they never abort the batch.
+			if s, err := provider.Snapshot(ctx, sym); err == nil {
+				results[i] = s
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	for i, sym := range missing {
+		c[sym] = results[i]
+	}
+}
+
+// coverage returns the share (0-100) of fetched symbols that have a snapshot.
+func (c snapshotCache) coverage() float64 {
+	if len(c) == 0 {
+		return 100
+	}
+	covered := 0
+	for _, s := range c {
+		if s != nil {
+			covered++
+		}
+	}
+	return 100 * float64(covered) / float64(len(c))
+}
@@ -0,0 +1,66 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange/sim"
+	"nof0-api/pkg/market"
+)
+
+func TestBuildExecutorContext_FetchesEachSnapshotOnce(t *testing.T) {
+	snaps := []*market.Snapshot{testSnapshot("BTC", 60000, 0.05)}
+	for i := 0; i < 23; i++ {
+		snaps = append(snaps, testSnapshot(fmt.Sprintf("ALT%02d", i), 10, 0.001*float64(i)))
+	}
+	mk := newFakeMarket(snaps...)
+	mk.delay = 20 * time.Millisecond
+
+	ex := sim.New()
+	require.NoError(t, ex.SetMarkPrice(context.Background(), "BTC", 60000))
+	_, err := ex.IOCMarket(context.Background(), "BTC", true, 0.01, 0.01, false)
+	require.NoError(t, err)
+
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := newCycleTestTrader(m, "cache", mk, &fakeExecutor{})
+	vt.ExchangeProvider = ex
+
+	start := time.Now()
+	ectx, _ := m.buildExecutorContext(vt, snapshotCache{})
+	elapsed := time.Since(start)
+
+	for _, s := range snaps {
+		assert.Equal(t, 1, mk.snapshotCalls(s.Symbol), "%s should be fetched once per cycle", s.Symbol)
+	}
+	assert.Contains(t, ectx.MarketDataMap, "BTC", "position symbol should have market data")
+	if assert.Len(t, ectx.Positions, 1) {
+		assert.Equal(t, 60000.0, ectx.Positions[0].MarkPrice, "position should be marked from the cached snapshot")
+	}
+	assert.Len(t, ectx.MarketDataMap, len(ectx.CandidateCoins), "market data should cover candidates and positions only")
+
+	mk.mu.Lock()
+	maxInFlight := mk.maxInFlight
+	mk.mu.Unlock()
+	assert.Greater(t, maxInFlight, 1, "candidate snapshots should be fetched in parallel")
+	assert.LessOrEqual(t, maxInFlight, snapshotFetchConcurrency, "fetches should respect the concurrency bound")
+	t.Logf("%d snapshots at %s each took %s", len(snaps), mk.delay, elapsed)
+	assert.Less(t, elapsed, time.Duration(len(snaps))*mk.delay/2, "parallel fetch should beat serial latency")
+}
+
+func TestSnapshotCache_RecordsFailuresOnce(t *testing.T) {
+	mk := newFakeMarket(testSnapshot("BTC", 60000, 0.01))
+	mk.down["BTC"] = true
+	cache := snapshotCache{}
+
+	cache.fetch(context.Background(), mk, []string{"BTC", "BTC", "ETH"})
+	cache.fetch(context.Background(), mk, []string{"BTC", "ETH"})
+
+	assert.Equal(t, 1, mk.snapshotCalls("BTC"), "failed symbol should not be retried within a cycle")
+	assert.Equal(t, 1, mk.snapshotCalls("ETH"), "unknown symbol should not be retried within a cycle")
+	assert.Zero(t, cache.coverage(), "no symbol has a snapshot")
+}
@@ -0,0 +1,76 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"nof0-api/pkg/exchange"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// ReconcileStalePositions closes out positions that persistence still holds
+// open but the exchange no longer reports (e.g. closed externally or
+// liquidated). Each one is recorded as a synthetic close event flagged
+// Reconciled so analytics stop counting it as live exposure. It returns the
+// reconciled symbols. live is the position list from the caller's most recent
+// account fetch, so reconciliation costs no extra exchange round trip.
+// Persistence backends without OpenPositionReader are a no-op.
+func (m *Manager) ReconcileStalePositions(ctx context.Context, traderID string, live []exchange.Position) ([]string, error) {
+	if m == nil {
+		return nil, errors.New("manager: nil manager")
+	}
+	reader, ok := m.persistence.(OpenPositionReader)
+	if !ok {
+		return nil, nil
+	}
+	m.mu.RLock()
+	t, ok := m.traders[traderID]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("manager: trader %s not found", traderID)
+	}
+
+	cached, err := reader.OpenPositions(ctx, traderID)
+	if err != nil {
+		return nil, fmt.Errorf("manager: load cached positions for trader %s: %w", traderID, err)
+	}
+	if len(cached) == 0 {
+		return nil, nil
+	}
+	held := make(map[string]bool, len(live))
+	for _, p := range live {
+		if parseFloat(p.Szi) != 0 {
+			held[strings.ToUpper(strings.TrimSpace(p.Coin))] = true
+		}
+	}
+
+	var reconciled []string
+	now := time.Now()
+	for _, c := range cached {
+		symbol := strings.ToUpper(strings.TrimSpace(c.Symbol))
+		if symbol == "" || held[symbol] {
+			continue
+		}
+		action := "close_long"
+		if strings.EqualFold(c.Side, "short") {
+			action = "close_short"
+		}
+		logx.Infof("manager: trader %s reconciling stale position symbol=%s side=%s qty=%.6f: not reported by exchange", traderID, symbol, c.Side, c.Quantity)
+		m.recordPositionEvent(PositionEvent{
+			TraderID:   traderID,
+			Trader:     t,
+			Decision:   executorpkg.Decision{Symbol: symbol, Action: action},
+			Event:      PositionEventClose,
+			OccurredAt: now,
+			FillSize:   c.Quantity,
+			Reconciled: true,
+		})
+		reconciled = append(reconciled, symbol)
+	}
+	return reconciled, nil
+}
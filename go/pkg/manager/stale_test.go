@@ -0,0 +1,49 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange/sim"
+)
+
+func TestReconcileStalePositions(t *testing.T) {
+	ctx := context.Background()
+	ex := sim.New()
+	require.NoError(t, ex.SetMarkPrice(ctx, "ETH", 3000))
+	_, err := ex.IOCMarket(ctx, "ETH", true, 0.5, 0, false)
+	require.NoError(t, err)
+
+	persist := &fakePersistence{open: map[string][]CachedPosition{
+		"t1": {
+			{Symbol: "BTC", Side: "long", Quantity: 0.1, EntryPrice: 60000},
+			{Symbol: "ETH", Side: "long", Quantity: 0.5, EntryPrice: 3000},
+		},
+	}}
+	m := NewManager(nil, nil, nil, nil, persist)
+	m.traders["t1"] = &VirtualTrader{ID: "t1", ExchangeProvider: ex}
+
+	live, err := ex.GetPositions(ctx)
+	require.NoError(t, err)
+	symbols, err := m.ReconcileStalePositions(ctx, "t1", live)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTC"}, symbols, "only the position missing on the exchange should be reconciled")
+	require.Len(t, persist.events, 1, "one synthetic close should be emitted")
+	ev := persist.events[0]
+	assert.Equal(t, PositionEventClose, ev.Event)
+	assert.True(t, ev.Reconciled, "synthetic close should carry the reconciliation flag")
+	assert.Equal(t, "BTC", ev.Decision.Symbol)
+	assert.Equal(t, "close_long", ev.Decision.Action)
+	assert.InDelta(t, 0.1, ev.FillSize, 1e-9)
+}
+
+func TestReconcileStalePositionsWithoutReader(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	m.traders["t1"] = &VirtualTrader{ID: "t1", ExchangeProvider: sim.New()}
+	symbols, err := m.ReconcileStalePositions(context.Background(), "t1", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, symbols, "persistence without cached positions should be a no-op")
+}
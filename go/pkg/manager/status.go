@@ -0,0 +1,109 @@
+package manager
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// TraderStatus is the externally visible state of one trader.
+type TraderStatus struct {
+	ID             string      `json:"id"`
+	Name           string      `json:"name"`
+	State          TraderState `json:"state"`
+	EquityUSD      float64     `json:"equity_usd"`
+	OpenPositions  int         `json:"open_positions"`
+	LastDecisionAt *time.Time  `json:"last_decision_at,omitempty"`
+	WinRate        float64     `json:"win_rate"`
+	TokensUsed     int64       `json:"tokens_used"`
+}
+
+// Status returns a point-in-time view of t read under its mutex.
+func (t *VirtualTrader) Status() TraderStatus {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	s := TraderStatus{
+		ID:            t.ID,
+		Name:          t.Name,
+		State:         t.State,
+		EquityUSD:     t.ResourceAlloc.CurrentEquityUSD,
+		OpenPositions: t.ResourceAlloc.OpenPositions,
+		TokensUsed:    t.TokensUsed,
+	}
+	if !t.LastDecisionAt.IsZero() {
+		last := t.LastDecisionAt
+		s.LastDecisionAt = &last
+	}
+	if t.Performance != nil {
+		s.WinRate = t.Performance.WinRate
+	}
+	return s
+}
+
+// LoopRunning reports whether RunTradingLoop is currently executing.
+func (m *Manager) LoopRunning() bool {
+	return m != nil && m.loopRunning.Load()
+}
+
+// SetIngestStatusSource installs fn, whose result is reported as "ingest" in
+// the /healthz payload; cmd/llm passes the market ingestor's Status.
+func (m *Manager) SetIngestStatusSource(fn func() any) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ingestStatus = fn
+}
+
+// healthPayload is the /healthz response body.
+type healthPayload struct {
+	Status      string `json:"status"`
+	LoopRunning bool   `json:"loop_running"`
+	Ingest      any    `json:"ingest,omitempty"`
+}
+
+// StatusHandler serves /healthz, which answers 200 while the trading loop
+// runs and 503 otherwise with a JSON body carrying the market ingestion
+// status when a source is set, and /metrics, which lists the active traders'
+// status as JSON. With monitoring.metrics_exporter set to prometheus the
+// Prometheus collectors are exposed at /metrics/prometheus.
+func (m *Manager) StatusHandler() http.Handler {
+	mux := http.NewServeMux()
+	if m.config != nil && strings.EqualFold(m.config.Monitoring.MetricsExporter, MetricsExporterPrometheus) {
+		mux.Handle("/metrics/prometheus", promhttp.Handler())
+	}
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		body := healthPayload{Status: "ok", LoopRunning: m.LoopRunning()}
+		m.mu.RLock()
+		ingest := m.ingestStatus
+		m.mu.RUnlock()
+		if ingest != nil {
+			body.Ingest = ingest()
+		}
+		code := http.StatusOK
+		if !body.LoopRunning {
+			body.Status = "trading loop not running"
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(body)
+	})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		traders := m.GetActiveTraders()
+		statuses := make([]TraderStatus, 0, len(traders))
+		for _, t := range traders {
+			statuses = append(statuses, t.Status())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"loop_running": m.LoopRunning(),
+			"traders":      statuses,
+		})
+	})
+	return mux
+}
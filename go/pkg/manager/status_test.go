@@ -0,0 +1,104 @@
+package manager
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/llm"
+)
+
+func TestStatusHandler(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := newCycleTestTrader(m, "s1", newFakeMarket(testSnapshot("BTC", 60000, 0)), &fakeExecutor{})
+	vt.Name = "Status"
+	_, err := m.executeDecision(vt, protectiveOpen())
+	require.NoError(t, err)
+	require.NoError(t, m.SyncTraderPositions("s1"))
+	vt.recordTokenUsage(time.Now(), llm.Usage{TotalTokens: 120})
+	vt.recordTokenUsage(time.Now(), llm.Usage{TotalTokens: 30})
+	vt.recordClosedTrade(5)
+	vt.LastDecisionAt = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(m.StatusHandler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/healthz")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "unhealthy before the loop starts")
+
+	m.loopRunning.Store(true)
+	resp, err = http.Get(server.URL + "/healthz")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var body struct {
+		LoopRunning bool           `json:"loop_running"`
+		Traders     []TraderStatus `json:"traders"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.True(t, body.LoopRunning)
+	require.Len(t, body.Traders, 1)
+	s := body.Traders[0]
+	assert.Equal(t, "s1", s.ID)
+	assert.Equal(t, "Status", s.Name)
+	assert.Equal(t, TraderStateRunning, s.State)
+	assert.Greater(t, s.EquityUSD, 0.0)
+	assert.Equal(t, 1, s.OpenPositions)
+	assert.Equal(t, int64(150), s.TokensUsed, "tokens accumulate without a budget")
+	assert.Greater(t, s.WinRate, 0.0)
+	require.NotNil(t, s.LastDecisionAt)
+	assert.True(t, s.LastDecisionAt.Equal(vt.LastDecisionAt))
+}
+
+func TestHealthzReportsIngestStatus(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	type symbolStatus struct {
+		Provider  string `json:"provider"`
+		Symbol    string `json:"symbol"`
+		LastError string `json:"last_error,omitempty"`
+		Failures  int64  `json:"failures"`
+	}
+	m.SetIngestStatusSource(func() any {
+		return map[string]any{"symbols": []symbolStatus{{Provider: "hl", Symbol: "BTC", LastError: "timeout", Failures: 2}}}
+	})
+	server := httptest.NewServer(m.StatusHandler())
+	defer server.Close()
+
+	get := func() (int, healthPayload, []symbolStatus) {
+		resp, err := http.Get(server.URL + "/healthz")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		var body struct {
+			healthPayload
+			Ingest struct {
+				Symbols []symbolStatus `json:"symbols"`
+			} `json:"ingest"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return resp.StatusCode, body.healthPayload, body.Ingest.Symbols
+	}
+
+	code, health, symbols := get()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.False(t, health.LoopRunning)
+	assert.Len(t, symbols, 1, "ingest status is reported while unhealthy too")
+
+	m.loopRunning.Store(true)
+	code, health, symbols = get()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", health.Status)
+	require.Len(t, symbols, 1)
+	assert.Equal(t, symbolStatus{Provider: "hl", Symbol: "BTC", LastError: "timeout", Failures: 2}, symbols[0])
+}
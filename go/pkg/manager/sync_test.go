@@ -0,0 +1,151 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// flakyAccountExchange fails GetAccountState a fixed number of times before
+// delegating to the embedded sim provider.
+type flakyAccountExchange struct {
+	*sim.Provider
+	failures int
+	calls    int
+}
+
+func (f *flakyAccountExchange) GetAccountState(ctx context.Context) (*exchange.AccountState, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, errors.New("transient account state failure")
+	}
+	return f.Provider.GetAccountState(ctx)
+}
+
+func TestSyncTraderPositionsRetriesAccountState(t *testing.T) {
+	ex := &flakyAccountExchange{Provider: sim.New(), failures: 1}
+	m := NewManager(nil, nil, nil, nil, &fakePersistence{})
+	m.traders["t1"] = &VirtualTrader{ID: "t1", ExchangeProvider: ex}
+
+	require.NoError(t, m.SyncTraderPositions("t1"))
+	assert.Equal(t, 2, ex.calls, "account state should be fetched again after a transient failure")
+	assert.Greater(t, m.traders["t1"].ResourceAlloc.CurrentEquityUSD, 0.0, "equity should update after retry")
+}
+
+func TestSyncTraderPositionsGivesUpAfterRetries(t *testing.T) {
+	ex := &flakyAccountExchange{Provider: sim.New(), failures: accountStateAttempts}
+	m := NewManager(nil, nil, nil, nil, &fakePersistence{})
+	m.traders["t1"] = &VirtualTrader{ID: "t1", ExchangeProvider: ex}
+
+	assert.Error(t, m.SyncTraderPositions("t1"))
+	assert.Equal(t, accountStateAttempts, ex.calls, "retries should be bounded")
+}
+
+// gatedAccountExchange records how many account fetches overlap. A hung
+// exchange blocks until the sync gives up.
+type gatedAccountExchange struct {
+	*sim.Provider
+	delay   time.Duration
+	hang    bool
+	active  *atomic.Int32
+	maxSeen *atomic.Int32
+}
+
+func (g *gatedAccountExchange) GetAccountState(ctx context.Context) (*exchange.AccountState, error) {
+	n := g.active.Add(1)
+	defer g.active.Add(-1)
+	for {
+		seen := g.maxSeen.Load()
+		if n <= seen || g.maxSeen.CompareAndSwap(seen, n) {
+			break
+		}
+	}
+	if g.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	time.Sleep(g.delay)
+	return g.Provider.GetAccountState(ctx)
+}
+
+func TestSyncAllPositionsRunsBoundedInParallel(t *testing.T) {
+	var active, maxSeen atomic.Int32
+	m := NewManager(&Config{Manager: ManagerConfig{SyncConcurrency: 3}}, nil, nil, nil, &fakePersistence{})
+	for i := 0; i < 6; i++ {
+		id := fmt.Sprintf("t%d", i)
+		m.traders[id] = &VirtualTrader{ID: id, ExchangeProvider: &gatedAccountExchange{
+			Provider: sim.New(), delay: 50 * time.Millisecond, active: &active, maxSeen: &maxSeen,
+		}}
+	}
+
+	start := time.Now()
+	require.NoError(t, m.SyncAllPositions())
+	assert.Less(t, time.Since(start), 250*time.Millisecond, "six 50ms syncs on three workers should take about two rounds")
+	assert.Equal(t, int32(3), maxSeen.Load(), "no more than sync_concurrency traders should sync at once")
+	for _, vt := range m.traders {
+		assert.Greater(t, vt.ResourceAlloc.CurrentEquityUSD, 0.0, "trader %s should be synced", vt.ID)
+	}
+}
+
+func TestSyncAllPositionsAggregatesFailures(t *testing.T) {
+	var active, maxSeen atomic.Int32
+	m := NewManager(&Config{Manager: ManagerConfig{SyncTimeout: 50 * time.Millisecond}}, nil, nil, nil, &fakePersistence{})
+	m.traders["ok"] = &VirtualTrader{ID: "ok", ExchangeProvider: &gatedAccountExchange{Provider: sim.New(), active: &active, maxSeen: &maxSeen}}
+	m.traders["hung"] = &VirtualTrader{ID: "hung", ExchangeProvider: &gatedAccountExchange{Provider: sim.New(), hang: true, active: &active, maxSeen: &maxSeen}}
+	m.traders["down"] = &VirtualTrader{ID: "down", ExchangeProvider: &flakyAccountExchange{Provider: sim.New(), failures: accountStateAttempts}}
+
+	start := time.Now()
+	err := m.SyncAllPositions()
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second, "the hung trader should be cut off by sync_timeout")
+	assert.Contains(t, err.Error(), "trader hung")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "trader down")
+	assert.Contains(t, err.Error(), "transient account state failure")
+	assert.NotContains(t, err.Error(), "trader ok")
+	assert.Greater(t, m.traders["ok"].ResourceAlloc.CurrentEquityUSD, 0.0, "healthy traders still sync")
+}
+
+func TestAccountSnapshotTracksRealizedPnL(t *testing.T) {
+	ctx := context.Background()
+	ex := sim.New()
+	require.NoError(t, ex.SetMarkPrice(ctx, "BTC", 60000))
+	persist := &fakePersistence{}
+	m := NewManager(nil, nil, nil, nil, persist)
+	vt := &VirtualTrader{
+		ID:               "realized",
+		ExchangeProvider: ex,
+		MarketProvider:   newFakeMarket(testSnapshot("BTC", 60000, 0)),
+		OrderStyle:       OrderStyleMarketIOC,
+		RiskParams:       RiskParameters{MaxPositions: 3, MaxPositionSizeUSD: 1000, MajorCoinLeverage: 5},
+		Cooldown:         make(map[string]time.Time),
+	}
+	m.traders[vt.ID] = vt
+
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "BTC", Action: "open_long", PositionSizeUSD: 1000}))
+	require.NoError(t, ex.SetMarkPrice(ctx, "BTC", 63000))
+	vt.MarketProvider = newFakeMarket(testSnapshot("BTC", 63000, 0))
+	require.NoError(t, m.SyncTraderPositions(vt.ID))
+	require.Len(t, persist.snapshots, 1)
+	before := persist.snapshots[0]
+	assert.Zero(t, before.RealizedPnLUSD, "nothing is realized while the position is open")
+	assert.Greater(t, before.UnrealizedPnLUSD, 0.0)
+
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "BTC", Action: "close_long"}))
+	require.NoError(t, m.SyncTraderPositions(vt.ID))
+	require.Len(t, persist.snapshots, 2)
+	after := persist.snapshots[1]
+	assert.Greater(t, after.RealizedPnLUSD, before.RealizedPnLUSD, "a profitable close should raise realized pnl")
+	assert.InDelta(t, vt.Performance.TotalPnLUSD, after.RealizedPnLUSD, 1e-9)
+	assert.Zero(t, after.UnrealizedPnLUSD, "no open positions remain")
+}
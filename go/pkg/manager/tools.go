@@ -0,0 +1,70 @@
+package manager
+
+import (
+	"context"
+
+	"nof0-api/pkg/exchange"
+	executorpkg "nof0-api/pkg/executor"
+	"nof0-api/pkg/market"
+)
+
+// traderTools backs executor tool calls with a trader's providers.
+type traderTools struct {
+	exchange exchange.Provider
+	market   market.Provider
+}
+
+// Snapshot implements executorpkg.ToolProvider.
+func (tt traderTools) Snapshot(ctx context.Context, symbol string) (*market.Snapshot, error) {
+	return tt.market.Snapshot(ctx, symbol)
+}
+
+// Positions implements executorpkg.ToolProvider.
+func (tt traderTools) Positions(ctx context.Context) ([]executorpkg.PositionInfo, error) {
+	raw, err := tt.exchange.GetPositions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	positions := make([]executorpkg.PositionInfo, 0, len(raw))
+	for _, p := range raw {
+		if parseFloat(p.Szi) == 0 {
+			continue
+		}
+		info := positionInfo(p)
+		info.MarkPrice = positionMark(&p)
+		positions = append(positions, info)
+	}
+	return positions, nil
+}
+
+// attachToolProvider hands the trader's providers to executors that support
+// tool calling; others are left untouched.
+func attachToolProvider(exec executorpkg.Executor, ex exchange.Provider, mk market.Provider) {
+	aware, ok := exec.(interface {
+		SetToolProvider(executorpkg.ToolProvider)
+	})
+	if !ok || ex == nil || mk == nil {
+		return
+	}
+	aware.SetToolProvider(traderTools{exchange: ex, market: mk})
+}
+
+// positionInfo normalises an exchange position into the executor view; the
+// mark price and PnL percentage are filled in by callers that have them.
+func positionInfo(p exchange.Position) executorpkg.PositionInfo {
+	side := "long"
+	qty := parseFloat(p.Szi)
+	if qty < 0 {
+		side = "short"
+		qty = -qty
+	}
+	return executorpkg.PositionInfo{
+		Symbol:           p.Coin,
+		Side:             side,
+		EntryPrice:       parsePtrFloat(p.EntryPx),
+		Quantity:         qty,
+		Leverage:         p.Leverage.Value,
+		UnrealizedPnL:    parseFloat(p.UnrealizedPnl),
+		LiquidationPrice: parsePtrFloat(p.LiquidationPx),
+	}
+}
@@ -0,0 +1,43 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+type toolAwareExecutor struct {
+	fakeExecutor
+	tools executorpkg.ToolProvider
+}
+
+func (e *toolAwareExecutor) SetToolProvider(p executorpkg.ToolProvider) { e.tools = p }
+
+func TestTraderToolsResolveProviders(t *testing.T) {
+	ctx := context.Background()
+	ex := sim.New()
+	require.NoError(t, ex.SetMarkPrice(ctx, "ETH", 3000))
+	_, err := ex.IOCMarket(ctx, "ETH", false, 0.5, 0, false)
+	require.NoError(t, err)
+
+	exec := &toolAwareExecutor{}
+	attachToolProvider(exec, ex, newFakeMarket(testSnapshot("BTC", 60000, 0.01)))
+	require.NotNil(t, exec.tools, "tool-aware executors should receive the trader's providers")
+
+	snap, err := exec.tools.Snapshot(ctx, "BTC")
+	require.NoError(t, err)
+	assert.Equal(t, 60000.0, snap.Price.Last)
+
+	positions, err := exec.tools.Positions(ctx)
+	require.NoError(t, err)
+	require.Len(t, positions, 1)
+	assert.Equal(t, "ETH", positions[0].Symbol)
+	assert.Equal(t, "short", positions[0].Side)
+	assert.InDelta(t, 0.5, positions[0].Quantity, 1e-9)
+	assert.InDelta(t, 3000, positions[0].MarkPrice, 10, "mark is derived from the position value")
+}
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/zeromicro/go-zero/core/logx"
@@ -20,6 +21,9 @@ const (
 	TraderStatePaused  TraderState = "paused"
 	TraderStateStopped TraderState = "stopped"
 	TraderStateError   TraderState = "error"
+	// TraderStateHalted marks a trader whose account equity is depleted;
+	// it stays out of scheduling until restarted explicitly.
+	TraderStateHalted TraderState = "halted"
 )
 
 // ResourceAllocation tracks funds and margin usage assigned to a trader.
@@ -30,6 +34,8 @@ type ResourceAllocation struct {
 	AvailableBalanceUSD float64 // Available balance (updated via sync)
 	MarginUsedUSD       float64 // Margin currently used
 	UnrealizedPnLUSD    float64 // Unrealized PnL
+	RealizedPnLUSD      float64 // Cumulative realized PnL from closed trades
+	OpenPositions       int     // Open positions on the exchange (updated via sync)
 }
 
 // IsOverAllocated reports whether live usage exceeds the assigned slice.
@@ -73,20 +79,31 @@ func (p *PerformanceMetrics) ToExecutorView() *executorpkg.PerformanceView {
 // VirtualTrader models a strategy instance bound to providers and an executor.
 type VirtualTrader struct {
 	mu sync.RWMutex
+	// deciding is set while a decision cycle runs; claimed under Manager.mu.
+	deciding atomic.Bool
 
 	ID                   string
 	Name                 string
 	Exchange             string
+	Market               string
+	VaultAddress         string
 	ExchangeProvider     exchange.Provider
 	MarketProvider       market.Provider
 	Executor             executorpkg.Executor
 	PromptTemplate       string
 	OrderStyle           OrderStyle
 	MarketIOCSlippageBps float64
+	PostOnlyOffsetBps    float64
+	MinOrderNotionalUSD  float64
+	MarginMode           MarginMode
+	SizingMode           SizingMode
+	SizingFloorUSD       float64
+	KellyMultiplier      float64
 	RiskParams           RiskParameters
 	ExecGuards           ExecGuards
 	ResourceAlloc        ResourceAllocation
 	State                TraderState
+	HaltReason           string
 	Performance          *PerformanceMetrics
 	LastDecisionAt       time.Time
 	DecisionInterval     time.Duration
@@ -94,12 +111,55 @@ type VirtualTrader struct {
 	UpdatedAt            time.Time
 	// Cooldown map tracks last successful close time per symbol
 	Cooldown map[string]time.Time
+	// CyclesCompleted counts decision cycles run for this trader.
+	CyclesCompleted int
+	// TradeStats tracks EMA win rate and PnL of realized closes.
+	TradeStats *EMAStats
+	// TradingWindows limits decisions to UTC sessions; empty means always active.
+	TradingWindows []TradingWindow
+	// LossCooldown marks symbols whose last close realized a loss, selecting
+	// ExecGuards.CooldownAfterLoss instead of CooldownAfterClose.
+	LossCooldown map[string]bool
+	// Funding tracks funding accrued on open positions, keyed by upper-case symbol.
+	Funding map[string]*PositionFunding
+	// pendingOpens holds symbols with a submitted open that positions do not
+	// reflect yet, keyed by upper-case symbol with the submission time.
+	pendingOpens map[string]time.Time
+	// restingOrders tracks post_only opens left resting on the book, keyed
+	// by cloid, so later cycles reprice them instead of abandoning them.
+	restingOrders map[string]restingOrder
+	// positionOpenedAt holds the entry time of each held position, keyed by
+	// upper-case symbol, for ExecGuards.MaxPositionAge.
+	positionOpenedAt map[string]time.Time
+	// executedIntents holds the idempotency keys of decisions already
+	// executed, with their execution time; entries expire after intentKeyTTL.
+	executedIntents map[string]time.Time
 	// Decision journal writer (per trader)
-	Journal *journal.Writer
+	Journal journal.CycleWriter
 	// Journal flags
 	JournalEnabled bool
+	// PersistPrompts stores each cycle's full rendered prompt as a
+	// conversation linked to the decision cycle.
+	PersistPrompts bool
 	// Pause window for Sharpe gating
 	PauseUntil time.Time
+	// equityCurve holds equity samples inside ExecGuards.SharpeLookback.
+	equityCurve []equitySample
+	// peakEquity is the highest account equity seen, the reference for
+	// ExecGuards.MaxDrawdownPct.
+	peakEquity float64
+	// lossDay is the UTC day whose first equity reading, dayStartEquity,
+	// anchors ExecGuards.MaxDailyLossUSD/Pct.
+	lossDay        time.Time
+	dayStartEquity float64
+	// OverAllocated is set by Rebalance when margin used exceeds the
+	// allocated equity; new opens are blocked while it holds.
+	OverAllocated bool
+	// TokenBudget caps LLM usage; tokenUsage holds calls inside its window.
+	TokenBudget TokenBudget
+	tokenUsage  []tokenUsageEntry
+	// TokensUsed counts LLM tokens spent since the trader was registered.
+	TokensUsed int64
 }
 
 // Start transitions the trader into running state.
@@ -110,6 +170,7 @@ func (t *VirtualTrader) Start() error {
 		return nil
 	}
 	t.State = TraderStateRunning
+	t.HaltReason = ""
 	t.UpdatedAt = time.Now()
 	logx.Infof("trader %s started", t.ID)
 	return nil
@@ -141,6 +202,15 @@ func (t *VirtualTrader) Stop() error {
 	return nil
 }
 
+// Halt moves the trader into halted state with the given reason.
+func (t *VirtualTrader) Halt(reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.State = TraderStateHalted
+	t.HaltReason = reason
+	t.UpdatedAt = time.Now()
+}
+
 // IsActive returns true when trader should participate in scheduling.
 func (t *VirtualTrader) IsActive() bool {
 	t.mu.RLock()
@@ -148,14 +218,47 @@ func (t *VirtualTrader) IsActive() bool {
 	return t.State == TraderStateRunning
 }
 
+// cooldownFor returns the cooldown applying to symbol after its last close.
+// Callers must hold t.mu.
+func (t *VirtualTrader) cooldownFor(symbol string) time.Duration {
+	if t.LossCooldown[symbol] && t.ExecGuards.CooldownAfterLoss > 0 {
+		return t.ExecGuards.CooldownAfterLoss
+	}
+	return t.ExecGuards.CooldownAfterClose
+}
+
+// cooldownUntil returns when symbol may be reopened after its last close and
+// whether that is still ahead of now. It always reports false while the
+// cooldown guard is disabled or unconfigured. Callers must hold t.mu.
+func (t *VirtualTrader) cooldownUntil(symbol string, now time.Time) (time.Time, bool) {
+	if !toggleOn(t.ExecGuards.EnableCooldownGuard) || (t.ExecGuards.CooldownAfterClose <= 0 && t.ExecGuards.CooldownAfterLoss <= 0) {
+		return time.Time{}, false
+	}
+	closedAt, ok := t.Cooldown[symbol]
+	if !ok {
+		return time.Time{}, false
+	}
+	until := closedAt.Add(t.cooldownFor(symbol))
+	return until, now.Before(until)
+}
+
 // ShouldMakeDecision determines whether a decision should be requested now.
 func (t *VirtualTrader) ShouldMakeDecision() bool {
+	return t.ShouldMakeDecisionAt(time.Now())
+}
+
+// ShouldMakeDecisionAt is ShouldMakeDecision evaluated at the given time,
+// additionally requiring now to fall inside the trader's trading windows.
+func (t *VirtualTrader) ShouldMakeDecisionAt(now time.Time) bool {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 	if t.State != TraderStateRunning {
 		return false
 	}
-	if !t.PauseUntil.IsZero() && time.Now().Before(t.PauseUntil) {
+	if !t.PauseUntil.IsZero() && now.Before(t.PauseUntil) {
+		return false
+	}
+	if !inTradingWindows(t.TradingWindows, now) {
 		return false
 	}
 	if t.DecisionInterval <= 0 {
@@ -164,7 +267,7 @@ func (t *VirtualTrader) ShouldMakeDecision() bool {
 	if t.LastDecisionAt.IsZero() {
 		return true
 	}
-	return time.Since(t.LastDecisionAt) >= t.DecisionInterval
+	return now.Sub(t.LastDecisionAt) >= t.DecisionInterval
 }
 
 // RecordDecision updates timestamps after a decision round completes.
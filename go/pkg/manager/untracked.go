@@ -0,0 +1,129 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"nof0-api/pkg/exchange"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// ReconcileOnStartup applies manager.startup_reconcile to every exchange
+// account before trading starts and returns the resulting report. Traders
+// sharing an exchange account are reconciled once, with untracked positions
+// assigned to the first trader by ID. Mode off returns a nil report.
+func (m *Manager) ReconcileOnStartup(ctx context.Context) (*ReconciliationReport, error) {
+	if m == nil || m.config == nil {
+		return nil, nil
+	}
+	mode := m.config.Manager.StartupReconcile
+	if mode == "" || mode == StartupReconcileOff {
+		return nil, nil
+	}
+	return m.reconcile(ctx, mode)
+}
+
+// ReconcileUntrackedPositions handles exchange positions that no trader on the
+// same exchange account has a persisted open position for, as happens when
+// the process dies between an order filling and its row being written. With
+// mode adopt each one is recorded as a reconciled open event for traderID;
+// with flatten its resting orders are cancelled and it is closed. It returns
+// the affected symbols. Persistence backends without OpenPositionReader are
+// a no-op, since nothing can be told apart as untracked.
+func (m *Manager) ReconcileUntrackedPositions(ctx context.Context, traderID, mode string) ([]string, error) {
+	if m == nil {
+		return nil, errors.New("manager: nil manager")
+	}
+	if err := validateReconcileMode(mode); err != nil {
+		return nil, err
+	}
+	reader, ok := m.persistence.(OpenPositionReader)
+	if !ok {
+		return nil, nil
+	}
+	m.mu.RLock()
+	t, ok := m.traders[traderID]
+	var peers []*VirtualTrader
+	if ok {
+		key := equityAccountKey(t)
+		for _, group := range m.accountGroupsLocked() {
+			if equityAccountKey(group[0]) == key {
+				peers = group
+				break
+			}
+		}
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("manager: trader %s not found", traderID)
+	}
+
+	report := &ReconciliationReport{CheckedAt: m.now()}
+	err := m.reconcileAccount(ctx, reader, peers, t, mode, report)
+	var reconciled []string
+	for _, d := range append(report.Adopted, report.Flattened...) {
+		reconciled = append(reconciled, d.Symbol)
+	}
+	return reconciled, err
+}
+
+// validateReconcileMode rejects modes that take no action on untracked
+// positions.
+func validateReconcileMode(mode string) error {
+	switch mode {
+	case StartupReconcileAdopt, StartupReconcileFlatten:
+		return nil
+	default:
+		return fmt.Errorf("manager: startup reconcile mode %q unsupported", mode)
+	}
+}
+
+// flattenUntracked cancels the resting orders of an untracked position and
+// closes it, recording the close against t. It reports whether the position
+// was closed, which a failed cancel does not prevent.
+func (m *Manager) flattenUntracked(ctx context.Context, t *VirtualTrader, pos exchange.Position) (bool, error) {
+	symbol := strings.ToUpper(strings.TrimSpace(pos.Coin))
+	var errs []error
+	if canceller, ok := t.ExchangeProvider.(interface {
+		CancelAllBySymbol(context.Context, string) error
+	}); ok {
+		if err := canceller.CancelAllBySymbol(ctx, pos.Coin); err != nil {
+			errs = append(errs, fmt.Errorf("manager: reconcile trader %s: cancel orders %s: %w", t.ID, symbol, err))
+		}
+	}
+	resp, err := t.ExchangeProvider.ClosePosition(ctx, pos.Coin)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("manager: reconcile trader %s: close %s: %w", t.ID, symbol, err))
+		return false, errors.Join(errs...)
+	}
+	m.recordFlattenClose(t, pos, resp, "startup_reconcile_flatten")
+	return true, errors.Join(errs...)
+}
+
+// adoptPosition records pos as a reconciled open event at its entry price so
+// it is tracked, and later closed, like any other position of t.
+func (m *Manager) adoptPosition(t *VirtualTrader, pos exchange.Position) {
+	action := "open_long"
+	if strings.HasPrefix(strings.TrimSpace(pos.Szi), "-") {
+		action = "open_short"
+	}
+	m.recordPositionEvent(PositionEvent{
+		TraderID: t.ID,
+		Trader:   t,
+		Decision: executorpkg.Decision{
+			Symbol:    pos.Coin,
+			Action:    action,
+			Leverage:  pos.Leverage.Value,
+			Reasoning: "startup_reconcile_adopt",
+		},
+		Event:      PositionEventOpen,
+		FillPrice:  parsePtrFloat(pos.EntryPx),
+		FillSize:   math.Abs(parseFloat(pos.Szi)),
+		Leverage:   pos.Leverage.Value,
+		OccurredAt: m.now(),
+		Reconciled: true,
+	})
+}
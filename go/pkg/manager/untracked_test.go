@@ -0,0 +1,128 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange/sim"
+)
+
+// newUntrackedFixture returns a manager whose sim exchange holds a tracked
+// ETH long and an untracked BTC short.
+func newUntrackedFixture(t *testing.T, mode string) (*Manager, *fakePersistence, *flattenExchange) {
+	t.Helper()
+	ctx := context.Background()
+	ex := &flattenExchange{Provider: sim.New()}
+	require.NoError(t, ex.SetMarkPrice(ctx, "ETH", 3000))
+	require.NoError(t, ex.SetMarkPrice(ctx, "BTC", 60000))
+	_, err := ex.IOCMarket(ctx, "ETH", true, 0.5, 0, false)
+	require.NoError(t, err)
+	_, err = ex.IOCMarket(ctx, "BTC", false, 0.1, 0, false)
+	require.NoError(t, err)
+
+	persist := &fakePersistence{open: map[string][]CachedPosition{
+		"t2": {{Symbol: "ETH", Side: "long", Quantity: 0.5, EntryPrice: 3000}},
+	}}
+	m := NewManager(&Config{Manager: ManagerConfig{StartupReconcile: mode}}, nil, nil, nil, persist)
+	m.traders["t1"] = &VirtualTrader{ID: "t1", Exchange: "hl", ExchangeProvider: ex}
+	m.traders["t2"] = &VirtualTrader{ID: "t2", Exchange: "hl", ExchangeProvider: ex}
+	return m, persist, ex
+}
+
+func TestReconcileOnStartupAdoptsUntrackedPosition(t *testing.T) {
+	ctx := context.Background()
+	m, persist, ex := newUntrackedFixture(t, StartupReconcileAdopt)
+
+	report, err := m.ReconcileOnStartup(ctx)
+	require.NoError(t, err)
+	require.Len(t, report.Adopted, 1)
+	assert.Equal(t, "BTC", report.Adopted[0].Symbol)
+	require.Len(t, persist.events, 1, "only the untracked position should be adopted")
+	ev := persist.events[0]
+	assert.Equal(t, "t1", ev.TraderID, "adoption goes to the first trader on the account")
+	assert.Equal(t, PositionEventOpen, ev.Event)
+	assert.True(t, ev.Reconciled)
+	assert.Equal(t, "BTC", ev.Decision.Symbol)
+	assert.Equal(t, "open_short", ev.Decision.Action)
+	assert.InDelta(t, 0.1, ev.FillSize, 1e-9)
+
+	positions, err := ex.GetPositions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, positions, 2, "adopting must not touch the exchange")
+	for _, p := range positions {
+		if p.Coin == "BTC" {
+			assert.InDelta(t, parsePtrFloat(p.EntryPx), ev.FillPrice, 1e-9, "adopted at the exchange entry price")
+		}
+	}
+	assert.Empty(t, ex.cancelled)
+}
+
+func TestReconcileOnStartupFlattensUntrackedPosition(t *testing.T) {
+	ctx := context.Background()
+	m, persist, ex := newUntrackedFixture(t, StartupReconcileFlatten)
+
+	report, err := m.ReconcileOnStartup(ctx)
+	require.NoError(t, err)
+	require.Len(t, report.Flattened, 1)
+	assert.Equal(t, PositionDiscrepancy{TraderID: "t1", Symbol: "BTC", ExchangeSide: "short", ExchangeQty: 0.1}, report.Flattened[0])
+	assert.Empty(t, report.Adopted)
+	assert.Equal(t, []string{"BTC"}, ex.cancelled)
+	positions, err := ex.GetPositions(ctx)
+	require.NoError(t, err)
+	require.Len(t, positions, 1, "the tracked position should stay open")
+	assert.Equal(t, "ETH", positions[0].Coin)
+
+	require.Len(t, persist.events, 1)
+	ev := persist.events[0]
+	assert.Equal(t, PositionEventClose, ev.Event)
+	assert.Equal(t, "close_short", ev.Decision.Action)
+	assert.Equal(t, "startup_reconcile_flatten", ev.Decision.Reasoning)
+}
+
+func TestReconcileOnStartupOffIsNoop(t *testing.T) {
+	ctx := context.Background()
+	m, persist, ex := newUntrackedFixture(t, StartupReconcileOff)
+
+	report, err := m.ReconcileOnStartup(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, report)
+	assert.Empty(t, persist.events)
+	positions, err := ex.GetPositions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, positions, 2)
+}
+
+func TestReconcileTreatsVaultTradersAsSeparateAccounts(t *testing.T) {
+	ctx := context.Background()
+	persist := &fakePersistence{open: map[string][]CachedPosition{}}
+	m := NewManager(&Config{Manager: ManagerConfig{StartupReconcile: StartupReconcileAdopt}}, nil, nil, nil, persist)
+	for id, coin := range map[string]string{"v1": "BTC", "v2": "ETH"} {
+		vault := sim.New()
+		require.NoError(t, vault.SetMarkPrice(ctx, coin, 1000))
+		_, err := vault.IOCMarket(ctx, coin, true, 1, 0, false)
+		require.NoError(t, err)
+		// Same provider name, different vault accounts.
+		m.traders[id] = &VirtualTrader{ID: id, Exchange: "hl", VaultAddress: "0x" + id, ExchangeProvider: vault}
+	}
+
+	_, err := m.ReconcileOnStartup(ctx)
+	require.NoError(t, err)
+	adopted := map[string]string{}
+	for _, ev := range persist.events {
+		adopted[ev.TraderID] = ev.Decision.Symbol
+	}
+	assert.Equal(t, map[string]string{"v1": "BTC", "v2": "ETH"}, adopted, "each vault is inspected and owns its positions")
+
+	persist.events = nil
+	report, err := m.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Accounts)
+	require.Len(t, report.Adopted, 2)
+	assert.Equal(t, "v1", report.Adopted[0].TraderID)
+	assert.Equal(t, "BTC", report.Adopted[0].Symbol)
+	assert.Equal(t, "v2", report.Adopted[1].TraderID)
+	assert.Equal(t, "ETH", report.Adopted[1].Symbol)
+}
@@ -0,0 +1,100 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// beginCycle claims t for a decision cycle. It holds m.mu so cycles cannot
+// start while UpdateTrader is swapping the trader's configuration, and it
+// reports false when a cycle for t is already running.
+func (m *Manager) beginCycle(t *VirtualTrader) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return t.deciding.CompareAndSwap(false, true)
+}
+
+// UpdateTrader hot-reloads a registered trader: it re-validates cfg, builds a
+// fresh executor and replaces the trader's risk parameters, exec guards and
+// scheduling settings in place. Lifecycle state, cooldowns, performance and
+// cached positions are preserved. It fails while the trader is mid-decision
+// and blocks new cycles until the swap completes. Exchange and market
+// providers and the vault address cannot change; unregister and register the
+// trader instead.
+func (m *Manager) UpdateTrader(cfg TraderConfig) error {
+	if m == nil {
+		return errors.New("manager: nil manager")
+	}
+	if err := m.validateTraderConfig(cfg); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ok := m.traders[cfg.ID]
+	if !ok {
+		return fmt.Errorf("manager: trader %s not found", cfg.ID)
+	}
+	if t.deciding.Load() {
+		return fmt.Errorf("manager: trader %s is mid-decision; retry after the cycle completes", cfg.ID)
+	}
+	if cfg.ExchangeProvider != t.Exchange {
+		return fmt.Errorf("manager: trader %s exchange provider cannot change from %q to %q", cfg.ID, t.Exchange, cfg.ExchangeProvider)
+	}
+	if cfg.VaultAddress != t.VaultAddress {
+		return fmt.Errorf("manager: trader %s vault address cannot change from %q to %q", cfg.ID, t.VaultAddress, cfg.VaultAddress)
+	}
+	if cfg.MarketProvider != t.Market {
+		return fmt.Errorf("manager: trader %s market provider cannot change from %q to %q", cfg.ID, t.Market, cfg.MarketProvider)
+	}
+	if m.executorFactory == nil {
+		return errors.New("manager: executorFactory is not set")
+	}
+	exec, err := m.executorFactory.NewExecutor(cfg)
+	if err != nil {
+		return fmt.Errorf("manager: create executor for trader %s: %w", cfg.ID, err)
+	}
+	attachToolProvider(exec, t.ExchangeProvider, t.MarketProvider)
+
+	t.mu.Lock()
+	t.Name = cfg.Name
+	t.Executor = exec
+	t.PromptTemplate = cfg.PromptTemplate
+	t.OrderStyle = cfg.OrderStyle
+	t.MarketIOCSlippageBps = cfg.MarketIOCSlippageBps
+	t.PostOnlyOffsetBps = cfg.PostOnlyOffsetBps
+	t.MinOrderNotionalUSD = cfg.MinOrderNotionalUSD
+	t.MarginMode = cfg.MarginMode
+	t.SizingMode = cfg.SizingMode
+	t.SizingFloorUSD = cfg.SizingFloorUSD
+	t.KellyMultiplier = cfg.KellyMultiplier
+	t.RiskParams = cfg.RiskParams
+	t.ExecGuards = cfg.ExecGuards
+	t.TradingWindows = cfg.TradingWindows
+	t.TokenBudget = cfg.TokenBudget
+	t.ResourceAlloc.AllocationPct = cfg.AllocationPct
+	t.DecisionInterval = cfg.DecisionInterval
+	t.JournalEnabled = cfg.JournalEnabled
+	t.PersistPrompts = cfg.PersistPrompts
+	if cfg.JournalEnabled && t.Journal == nil {
+		t.Journal = newTraderJournal(cfg)
+	}
+	t.UpdatedAt = time.Now()
+	state := t.State
+	t.mu.Unlock()
+
+	if m.config != nil {
+		for i := range m.config.Traders {
+			if m.config.Traders[i].ID == cfg.ID {
+				m.config.Traders[i] = cfg
+			}
+		}
+	}
+	m.allocateEquityLocked()
+	logx.Infof("manager: updated trader id=%s name=%s model=%s order_style=%s state=%s", cfg.ID, cfg.Name, cfg.Model, cfg.OrderStyle, state)
+	return nil
+}
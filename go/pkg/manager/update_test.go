@@ -0,0 +1,117 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+	"nof0-api/pkg/market"
+)
+
+// countingExecutorFactory hands out fresh fake executors and counts builds.
+type countingExecutorFactory struct {
+	built int
+}
+
+func (f *countingExecutorFactory) NewExecutor(TraderConfig) (executorpkg.Executor, error) {
+	f.built++
+	return &fakeExecutor{}, nil
+}
+
+const updateTestConfig = `
+manager:
+  total_equity_usd: 10000
+  state_storage_backend: file
+  state_storage_path: ./state/manager.json
+
+traders:
+  - id: live
+    name: Live Trader
+    exchange_provider: sim
+    market_provider: mk
+    prompt_template: prompt.tmpl
+    executor_prompt_template: prompts/executor/default_prompt.tmpl
+    decision_interval: 5m
+    allocation_pct: 50
+    risk_params:
+      max_positions: 3
+      max_position_size_usd: 500
+      max_margin_usage_pct: 60
+      major_coin_leverage: 5
+      altcoin_leverage: 3
+      min_risk_reward_ratio: 2.0
+      min_confidence: 70
+    exec_guards:
+      cooldown_after_close: 10m
+
+monitoring:
+  update_interval: 10s
+  metrics_exporter: prometheus
+`
+
+func newUpdateTestManager(t *testing.T) (*Manager, *countingExecutorFactory, TraderConfig) {
+	t.Helper()
+	cfg, err := LoadConfig(writeTestManagerConfig(t, updateTestConfig))
+	require.NoError(t, err)
+	factory := &countingExecutorFactory{}
+	m := NewManager(cfg, factory,
+		map[string]exchange.Provider{"sim": sim.New()},
+		map[string]market.Provider{"mk": newFakeMarket(testSnapshot("BTC", 60000, 0.01))},
+		nil)
+	_, err = m.RegisterTrader(cfg.Traders[0])
+	require.NoError(t, err)
+	return m, factory, cfg.Traders[0]
+}
+
+func TestUpdateTraderSwapsConfigAndPreservesState(t *testing.T) {
+	m, factory, cfg := newUpdateTestManager(t)
+	vt := m.traders["live"]
+	require.NoError(t, vt.Start())
+	closedAt := time.Now().Add(-time.Minute)
+	vt.Cooldown["BTC"] = closedAt
+	oldExec := vt.Executor
+
+	cfg.RiskParams.MaxPositions = 1
+	cfg.ExecGuards.MaxNewPositionsPerCycle = 1
+	cfg.DecisionInterval = time.Minute
+	require.NoError(t, m.UpdateTrader(cfg))
+
+	assert.Equal(t, 2, factory.built, "update should build a fresh executor")
+	assert.NotSame(t, oldExec, vt.Executor)
+	assert.Equal(t, 1, vt.RiskParams.MaxPositions)
+	assert.Equal(t, 1, vt.ExecGuards.MaxNewPositionsPerCycle)
+	assert.Equal(t, time.Minute, vt.DecisionInterval)
+	assert.Same(t, vt, m.traders["live"], "trader should be updated in place")
+	assert.Equal(t, TraderStateRunning, vt.State, "running state should survive the update")
+	assert.Equal(t, closedAt, vt.Cooldown["BTC"], "cooldowns should survive the update")
+}
+
+func TestUpdateTraderRejections(t *testing.T) {
+	m, _, cfg := newUpdateTestManager(t)
+
+	bad := cfg
+	bad.RiskParams.MaxPositions = 0
+	assert.Error(t, m.UpdateTrader(bad), "invalid config should be rejected")
+
+	unknown := cfg
+	unknown.ID = "ghost"
+	assert.Error(t, m.UpdateTrader(unknown))
+
+	moved := cfg
+	moved.ExchangeProvider = "other"
+	assert.Error(t, m.UpdateTrader(moved), "exchange provider changes need a re-register")
+
+	vt := m.traders["live"]
+	require.True(t, m.beginCycle(vt))
+	assert.False(t, m.beginCycle(vt), "overlapping cycles should not start")
+	err := m.UpdateTrader(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mid-decision")
+	vt.deciding.Store(false)
+	assert.NoError(t, m.UpdateTrader(cfg), "update should succeed once the cycle completes")
+}
@@ -0,0 +1,45 @@
+package manager
+
+import (
+	"fmt"
+
+	"nof0-api/pkg/exchange"
+)
+
+// VaultProviderBuilder builds a provider that signs orders for vaultAddress
+// with the credentials of the named exchange provider, such as
+// exchange.Config.BuildVaultProvider.
+type VaultProviderBuilder func(providerName, vaultAddress string) (exchange.Provider, error)
+
+// SetVaultProviderBuilder installs the builder used for traders configured
+// with vault_address. Without one, registering such a trader fails rather
+// than trading the provider's own account.
+func (m *Manager) SetVaultProviderBuilder(builder VaultProviderBuilder) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.vaultProviders = builder
+}
+
+// resolveTraderExchange returns the provider cfg's orders go through: a
+// dedicated vault signer when vault_address is set, else the shared provider.
+// Callers hold m.mu.
+func (m *Manager) resolveTraderExchange(cfg TraderConfig) (exchange.Provider, error) {
+	ex, ok := m.exchangeProviders[cfg.ExchangeProvider]
+	if !ok {
+		return nil, fmt.Errorf("manager: unknown exchange provider %q for trader %s", cfg.ExchangeProvider, cfg.ID)
+	}
+	if cfg.VaultAddress == "" {
+		return ex, nil
+	}
+	if m.vaultProviders == nil {
+		return nil, fmt.Errorf("manager: trader %s sets vault_address but no vault provider builder is configured", cfg.ID)
+	}
+	vault, err := m.vaultProviders(cfg.ExchangeProvider, cfg.VaultAddress)
+	if err != nil {
+		return nil, fmt.Errorf("manager: trader %s vault provider: %w", cfg.ID, err)
+	}
+	return vault, nil
+}
@@ -0,0 +1,70 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+	"nof0-api/pkg/market"
+)
+
+func TestVaultTraderRoutesOrdersThroughVaultProvider(t *testing.T) {
+	ctx := context.Background()
+	cfg, err := LoadConfig(writeTestManagerConfig(t, updateTestConfig))
+	require.NoError(t, err)
+	shared, vault := sim.New(), sim.New()
+	for _, p := range []*sim.Provider{shared, vault} {
+		require.NoError(t, p.SetMarkPrice(ctx, "BTC", 60000))
+	}
+	m := NewManager(cfg, &countingExecutorFactory{},
+		map[string]exchange.Provider{"sim": shared},
+		map[string]market.Provider{"mk": newFakeMarket(testSnapshot("BTC", 60000, 0.01))},
+		nil)
+
+	traderCfg := cfg.Traders[0]
+	traderCfg.VaultAddress = "0x1111111111111111111111111111111111111111"
+	_, err = m.RegisterTrader(traderCfg)
+	assert.ErrorContains(t, err, "no vault provider builder", "a vault trader must not fall back to the shared account")
+
+	var builtFor []string
+	m.SetVaultProviderBuilder(func(name, address string) (exchange.Provider, error) {
+		builtFor = append(builtFor, name+"/"+address)
+		return vault, nil
+	})
+	vt, err := m.RegisterTrader(traderCfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sim/0x1111111111111111111111111111111111111111"}, builtFor)
+
+	require.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "BTC", Action: "open_long", PositionSizeUSD: 300, Leverage: 2}))
+	onVault, err := vault.GetPositions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, onVault, 1, "the order is signed for the vault")
+	onShared, err := shared.GetPositions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, onShared, "the provider's own account is untouched")
+
+	traderCfg.VaultAddress = ""
+	assert.ErrorContains(t, m.UpdateTrader(traderCfg), "vault address cannot change")
+}
+
+func TestVaultProviderBuildError(t *testing.T) {
+	cfg, err := LoadConfig(writeTestManagerConfig(t, updateTestConfig))
+	require.NoError(t, err)
+	m := NewManager(cfg, &countingExecutorFactory{},
+		map[string]exchange.Provider{"sim": sim.New()},
+		map[string]market.Provider{"mk": newFakeMarket(testSnapshot("BTC", 60000, 0.01))},
+		nil)
+	m.SetVaultProviderBuilder(func(string, string) (exchange.Provider, error) {
+		return nil, errors.New("bad key")
+	})
+	traderCfg := cfg.Traders[0]
+	traderCfg.VaultAddress = "0x1111111111111111111111111111111111111111"
+	_, err = m.RegisterTrader(traderCfg)
+	assert.ErrorContains(t, err, "vault provider: bad key")
+}
@@ -0,0 +1,78 @@
+package manager
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"nof0-api/pkg/exchange"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// SetWindDown toggles wind-down mode. While enabled the manager refuses new
+// exposure: open_* decisions are dropped and closes are submitted reduce-only.
+func (m *Manager) SetWindDown(enabled bool) {
+	if m == nil {
+		return
+	}
+	if m.windDown.Swap(enabled) != enabled {
+		logx.Infof("manager: wind-down mode enabled=%t", enabled)
+	}
+}
+
+// WindDown reports whether wind-down mode is active.
+func (m *Manager) WindDown() bool {
+	return m != nil && m.windDown.Load()
+}
+
+// dropOpensForWindDown removes open_* decisions while wind-down is active.
+func (m *Manager) dropOpensForWindDown(t *VirtualTrader, ds []executorpkg.Decision) []executorpkg.Decision {
+	if !m.WindDown() {
+		return ds
+	}
+	kept := ds[:0]
+	dropped := 0
+	for _, d := range ds {
+		if isOpenAction(d.Action) {
+			dropped++
+			logx.Infof("manager: trader %s suppress open symbol=%s action=%s reason=wind_down", t.ID, d.Symbol, d.Action)
+			continue
+		}
+		kept = append(kept, d)
+	}
+	m.recordGuardRejection(t.ID, GuardWindDown, dropped)
+	return kept
+}
+
+// reduceOnlyClose flattens the trader's position in symbol with an explicit
+// reduce-only IOC order, falling back to the provider's ClosePosition when it
+// cannot submit market IOC orders.
+func reduceOnlyClose(ctx context.Context, t *VirtualTrader, symbol string) (*exchange.OrderResponse, error) {
+	execProvider, ok := t.ExchangeProvider.(interface {
+		IOCMarket(context.Context, string, bool, float64, float64, bool) (*exchange.OrderResponse, error)
+	})
+	if !ok {
+		return t.ExchangeProvider.ClosePosition(ctx, symbol)
+	}
+	positions, err := t.ExchangeProvider.GetPositions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range positions {
+		if !strings.EqualFold(p.Coin, symbol) {
+			continue
+		}
+		szi := parseFloat(p.Szi)
+		if szi == 0 {
+			return nil, nil
+		}
+		slippage := t.MarketIOCSlippageBps / 10000.0
+		if slippage <= 0 {
+			slippage = defaultMarketIOCSlippageBps / 10000.0
+		}
+		return execProvider.IOCMarket(ctx, symbol, szi < 0, math.Abs(szi), slippage, true)
+	}
+	return nil, nil
+}
@@ -0,0 +1,68 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"nof0-api/pkg/exchange"
+	"nof0-api/pkg/exchange/sim"
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// recordingExchange wraps the sim provider and records the reduce-only flag of
+// every IOC order it submits.
+type recordingExchange struct {
+	*sim.Provider
+	iocReduceOnly []bool
+}
+
+func (r *recordingExchange) IOCMarket(ctx context.Context, coin string, isBuy bool, qty, slippage float64, reduceOnly bool) (*exchange.OrderResponse, error) {
+	r.iocReduceOnly = append(r.iocReduceOnly, reduceOnly)
+	return r.Provider.IOCMarket(ctx, coin, isBuy, qty, slippage, reduceOnly)
+}
+
+func TestWindDown_DropsOpensAndClosesReduceOnly(t *testing.T) {
+	ctx := context.Background()
+	ex := &recordingExchange{Provider: sim.New()}
+	assert.NoError(t, ex.SetMarkPrice(ctx, "ETH", 3000), "SetMarkPrice should succeed")
+	_, err := ex.Provider.IOCMarket(ctx, "ETH", true, 0.5, 0.001, false)
+	assert.NoError(t, err, "seed position should open")
+
+	mk := newFakeMarket(testSnapshot("ETH", 3000, 0.01), testSnapshot("BTC", 60000, 0.02))
+	m := NewManager(nil, nil, nil, nil, nil)
+	vt := &VirtualTrader{
+		ID:               "wind",
+		ExchangeProvider: ex,
+		MarketProvider:   mk,
+		OrderStyle:       OrderStyleMarketIOC,
+		RiskParams:       RiskParameters{MaxPositions: 5, MaxPositionSizeUSD: 1000},
+		Cooldown:         make(map[string]time.Time),
+	}
+	m.SetWindDown(true)
+	assert.True(t, m.WindDown(), "wind-down should be reported as active")
+
+	decisions := m.applyDecisionGuards(vt, []executorpkg.Decision{
+		{Symbol: "BTC", Action: "open_long", PositionSizeUSD: 100},
+		{Symbol: "ETH", Action: "close_long"},
+	}, 1, time.Now())
+	if assert.Len(t, decisions, 1, "open decisions should be dropped") {
+		assert.Equal(t, "close_long", decisions[0].Action, "close should survive wind-down")
+	}
+	assert.Equal(t, int64(1), m.GuardRejections(vt.ID)[GuardWindDown], "suppressed open should be counted")
+
+	assert.NoError(t, m.ExecuteDecision(vt, &decisions[0]), "close should execute during wind-down")
+	assert.Equal(t, []bool{true}, ex.iocReduceOnly, "close should be submitted reduce-only")
+	positions, err := ex.GetPositions(ctx)
+	assert.NoError(t, err, "GetPositions should succeed")
+	assert.Empty(t, positions, "position should be flat after the reduce-only close")
+
+	err = m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "BTC", Action: "open_long", PositionSizeUSD: 100})
+	assert.Error(t, err, "direct opens should be refused during wind-down")
+	assert.Len(t, ex.iocReduceOnly, 1, "refused open should not reach the exchange")
+
+	m.SetWindDown(false)
+	assert.NoError(t, m.ExecuteDecision(vt, &executorpkg.Decision{Symbol: "BTC", Action: "open_long", PositionSizeUSD: 100}), "opens should resume after wind-down")
+}
@@ -0,0 +1,100 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TradingWindow is a UTC session during which a trader may act.
+type TradingWindow struct {
+	Start string `yaml:"start"` // "HH:MM" UTC, inclusive
+	End   string `yaml:"end"`   // "HH:MM" UTC, exclusive; End before Start wraps past midnight
+	// Days restricts the window to weekdays ("mon".."sun") on which it starts;
+	// empty means every day.
+	Days []string `yaml:"days"`
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+func parseClockMinutes(s string) (int, error) {
+	ts, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return ts.Hour()*60 + ts.Minute(), nil
+}
+
+func (w TradingWindow) validate() error {
+	start, err := parseClockMinutes(w.Start)
+	if err != nil {
+		return fmt.Errorf("invalid start %q", w.Start)
+	}
+	end, err := parseClockMinutes(w.End)
+	if err != nil {
+		return fmt.Errorf("invalid end %q", w.End)
+	}
+	if start == end {
+		return fmt.Errorf("start and end must differ")
+	}
+	for _, d := range w.Days {
+		if _, ok := weekdayNames[strings.ToLower(strings.TrimSpace(d))]; !ok {
+			return fmt.Errorf("invalid day %q", d)
+		}
+	}
+	return nil
+}
+
+func (w TradingWindow) onDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if weekdayNames[strings.ToLower(strings.TrimSpace(d))] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether now (converted to UTC) falls inside the window.
+// Invalid windows never match; config validation rejects them up front.
+func (w TradingWindow) contains(now time.Time) bool {
+	start, err := parseClockMinutes(w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClockMinutes(w.End)
+	if err != nil {
+		return false
+	}
+	now = now.UTC()
+	minute := now.Hour()*60 + now.Minute()
+	if start < end {
+		return minute >= start && minute < end && w.onDay(now.Weekday())
+	}
+	// Overnight window: the part after midnight belongs to the previous day's session.
+	if minute >= start {
+		return w.onDay(now.Weekday())
+	}
+	if minute < end {
+		return w.onDay(now.AddDate(0, 0, -1).Weekday())
+	}
+	return false
+}
+
+// inTradingWindows reports whether now falls inside any window; no windows means always active.
+func inTradingWindows(windows []TradingWindow, now time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+	for _, w := range windows {
+		if w.contains(now) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fixedClock struct{ t time.Time }
+
+func (c fixedClock) Now() time.Time { return c.t }
+
+func TestTradingWindowSkipsOutsideHours(t *testing.T) {
+	m := NewManager(nil, nil, nil, nil, nil)
+	exec := &fakeExecutor{}
+	vt := newCycleTestTrader(m, "eu", newFakeMarket(), exec)
+	vt.TradingWindows = []TradingWindow{{Start: "09:00", End: "17:00"}}
+
+	// 2025-01-06 is a Monday.
+	cases := map[string]struct {
+		at   time.Time
+		want bool
+	}{
+		"before open":  {time.Date(2025, 1, 6, 8, 59, 0, 0, time.UTC), false},
+		"at open":      {time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC), true},
+		"midday":       {time.Date(2025, 1, 6, 13, 30, 0, 0, time.UTC), true},
+		"at close":     {time.Date(2025, 1, 6, 17, 0, 0, 0, time.UTC), false},
+		"non-utc zone": {time.Date(2025, 1, 6, 12, 0, 0, 0, time.FixedZone("UTC+8", 8*3600)), false},
+	}
+	for name, tc := range cases {
+		m.SetClock(fixedClock{tc.at})
+		vt.LastDecisionAt = time.Time{}
+		before := exec.callCount()
+		m.runSchedulingRound(context.Background())
+		assert.Equal(t, tc.want, exec.callCount() > before, "%s: cycle should run=%t", name, tc.want)
+	}
+}
+
+func TestTradingWindowDaysAndOvernight(t *testing.T) {
+	weekdays := TradingWindow{Start: "09:00", End: "17:00", Days: []string{"mon", "tue", "wed", "thu", "fri"}}
+	assert.True(t, weekdays.contains(time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC)), "friday inside window")
+	assert.False(t, weekdays.contains(time.Date(2025, 1, 11, 10, 0, 0, 0, time.UTC)), "saturday excluded")
+
+	asia := TradingWindow{Start: "22:00", End: "06:00", Days: []string{"sun"}}
+	assert.True(t, asia.contains(time.Date(2025, 1, 5, 23, 0, 0, 0, time.UTC)), "sunday evening inside")
+	assert.True(t, asia.contains(time.Date(2025, 1, 6, 5, 59, 0, 0, time.UTC)), "monday early belongs to sunday session")
+	assert.False(t, asia.contains(time.Date(2025, 1, 6, 23, 0, 0, 0, time.UTC)), "monday evening excluded")
+
+	assert.True(t, inTradingWindows(nil, time.Now()), "no windows means always active")
+	assert.Error(t, TradingWindow{Start: "25:00", End: "17:00"}.validate())
+	assert.Error(t, TradingWindow{Start: "09:00", End: "17:00", Days: []string{"funday"}}.validate())
+}
@@ -0,0 +1,49 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// dispatchCycles hands eligible traders to free decision workers and returns
+// without waiting, so the trading loop keeps ticking while slow cycles run.
+// Traders with a cycle still in flight are passed over until it finishes,
+// which keeps each trader's own cycles serialized. Free slots are shared
+// through the fair scheduler, further capped by max_concurrent_decisions
+// counting the cycles still in flight from earlier ticks.
+func (m *Manager) dispatchCycles(ctx context.Context, eligible []*VirtualTrader) {
+	idle := make([]*VirtualTrader, 0, len(eligible))
+	for _, t := range eligible {
+		if !t.deciding.Load() {
+			idle = append(idle, t)
+		}
+	}
+	slots := cap(m.workers) - len(m.workers)
+	if limit := m.config.Manager.MaxConcurrentDecisions; limit > 0 {
+		if free := max(limit-int(m.cyclesInFlight.Load()), 0); free < slots {
+			slots = free
+		}
+	}
+	for _, t := range m.scheduler.next(idle, slots) {
+		// Claim the trader before handing it off so the next tick cannot
+		// dispatch it again while the worker is starting up.
+		if !m.beginCycle(t) {
+			continue
+		}
+		m.workers <- struct{}{}
+		m.cyclesInFlight.Add(1)
+		m.wg.Add(1)
+		go func(t *VirtualTrader) {
+			defer func() {
+				m.cyclesInFlight.Add(-1)
+				<-m.workers
+				m.wg.Done()
+			}()
+			m.runClaimedCycle(ctx, t)
+		}(t)
+	}
+	if n := len(idle) - slots; n > 0 {
+		logx.WithContext(ctx).Debugf("manager: %d eligible traders waiting for a decision worker", n)
+	}
+}
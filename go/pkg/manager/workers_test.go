@@ -0,0 +1,106 @@
+package manager
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	executorpkg "nof0-api/pkg/executor"
+)
+
+// overlapCounter tracks the highest number of concurrent callers.
+type overlapCounter struct {
+	active, peak atomic.Int32
+}
+
+func (c *overlapCounter) enter() (exit func()) {
+	n := c.active.Add(1)
+	for {
+		p := c.peak.Load()
+		if n <= p || c.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+	return func() { c.active.Add(-1) }
+}
+
+// overlapExecutor records concurrent decision calls per trader and across
+// all traders sharing the same pool counter.
+type overlapExecutor struct {
+	*fakeExecutor
+	own, pool *overlapCounter
+}
+
+func (o *overlapExecutor) GetFullDecision(input *executorpkg.Context) (*executorpkg.FullDecision, error) {
+	defer o.own.enter()()
+	defer o.pool.enter()()
+	return o.fakeExecutor.GetFullDecision(input)
+}
+
+func runSlowTraders(t *testing.T, cfg ManagerConfig, traders int, latency time.Duration) (time.Duration, *Manager) {
+	t.Helper()
+	m := newCycleLimitManager(cfg)
+	for i := 0; i < traders; i++ {
+		vt := newCycleTestTrader(m, string(rune('a'+i)), newFakeMarket(testSnapshot("BTC", 60000, 0.01)), &fakeExecutor{delay: latency})
+		vt.DecisionInterval = time.Minute
+	}
+	start := time.Now()
+	runLoopWithTimeout(t, m)
+	return time.Since(start), m
+}
+
+func TestDecisionWorkersRunSlowTradersConcurrently(t *testing.T) {
+	const traders, latency = 4, 100 * time.Millisecond
+
+	serial, _ := runSlowTraders(t, ManagerConfig{MaxCyclesPerTrader: 1}, traders, latency)
+	pooled, m := runSlowTraders(t, ManagerConfig{MaxCyclesPerTrader: 1, DecisionWorkers: traders}, traders, latency)
+
+	assert.GreaterOrEqual(t, serial, traders*latency, "serial loop waits on every trader in turn")
+	assert.Less(t, pooled, 2*latency, "worker pool should overlap slow decision calls")
+	assert.Equal(t, int64(traders), m.CyclesCompleted())
+	t.Logf("%d traders at %s latency: serial=%s pooled=%s", traders, latency, serial, pooled)
+}
+
+func TestDecisionWorkersBoundConcurrency(t *testing.T) {
+	m := newCycleLimitManager(ManagerConfig{MaxCycles: 6, DecisionWorkers: 2})
+	pool := &overlapCounter{}
+	var execs []*overlapExecutor
+	for _, id := range []string{"a", "b", "c"} {
+		exec := &overlapExecutor{fakeExecutor: &fakeExecutor{delay: 20 * time.Millisecond}, own: &overlapCounter{}, pool: pool}
+		vt := newCycleTestTrader(m, id, newFakeMarket(testSnapshot("BTC", 60000, 0.01)), exec.fakeExecutor)
+		vt.Executor = exec
+		vt.DecisionInterval = time.Minute
+		execs = append(execs, exec)
+	}
+
+	runLoopWithTimeout(t, m)
+
+	calls := 0
+	for _, exec := range execs {
+		assert.Equal(t, int32(1), exec.own.peak.Load(), "a trader must never run two cycles at once")
+		calls += exec.callCount()
+	}
+	assert.Equal(t, int32(2), pool.peak.Load(), "concurrency should fill but not exceed decision_workers")
+	assert.Equal(t, 6, calls, "max_cycles must not overshoot with cycles in flight")
+	assert.Equal(t, int64(6), m.CyclesCompleted())
+}
+
+func TestMaxConcurrentDecisionsCountsCyclesInFlight(t *testing.T) {
+	// Cycles outlast many 1ms ticks; each tick must only fill the slots the
+	// in-flight cycles leave free.
+	m := newCycleLimitManager(ManagerConfig{MaxCycles: 8, DecisionWorkers: 4, MaxConcurrentDecisions: 2})
+	pool := &overlapCounter{}
+	for _, id := range []string{"a", "b", "c", "d"} {
+		exec := &overlapExecutor{fakeExecutor: &fakeExecutor{delay: 20 * time.Millisecond}, own: &overlapCounter{}, pool: pool}
+		vt := newCycleTestTrader(m, id, newFakeMarket(testSnapshot("BTC", 60000, 0.01)), exec.fakeExecutor)
+		vt.Executor = exec
+		vt.DecisionInterval = time.Minute
+	}
+
+	runLoopWithTimeout(t, m)
+
+	assert.Equal(t, int32(2), pool.peak.Load(), "max_concurrent_decisions caps cycles in flight across ticks")
+	assert.Equal(t, int64(8), m.CyclesCompleted())
+}
@@ -0,0 +1,225 @@
+package market
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// BlendPolicy selects how a BlendProvider combines the prices that survive
+// outlier rejection.
+type BlendPolicy string
+
+const (
+	BlendMedian   BlendPolicy = "median"
+	BlendMean     BlendPolicy = "mean"
+	BlendWeighted BlendPolicy = "weighted"
+)
+
+// defaultBlendMaxDeviation is the largest fractional distance from the median
+// price a provider may report before its tick is discarded.
+const defaultBlendMaxDeviation = 0.02
+
+// BlendProvider queries several providers for the same symbol and blends
+// their last prices into one snapshot, so a single provider's bad tick cannot
+// drive a decision. Prices further than the max deviation from the median are
+// dropped before blending. The non-price fields come from the first
+// surviving provider in configuration order.
+type BlendProvider struct {
+	providers    []Provider
+	policy       BlendPolicy
+	weights      []float64
+	maxDeviation float64
+}
+
+// BlendOption customises a BlendProvider.
+type BlendOption func(*BlendProvider)
+
+// WithBlendPolicy overrides the blend policy (median by default).
+func WithBlendPolicy(policy BlendPolicy) BlendOption {
+	return func(b *BlendProvider) {
+		if policy != "" {
+			b.policy = policy
+		}
+	}
+}
+
+// WithBlendWeights sets per-provider weights for the weighted policy, in the
+// same order as the providers.
+func WithBlendWeights(weights ...float64) BlendOption {
+	return func(b *BlendProvider) {
+		b.weights = append([]float64(nil), weights...)
+	}
+}
+
+// WithBlendMaxDeviation overrides the fractional distance from the median
+// (0.02 == 2%) beyond which a provider's price is rejected as an outlier.
+func WithBlendMaxDeviation(frac float64) BlendOption {
+	return func(b *BlendProvider) {
+		if frac > 0 {
+			b.maxDeviation = frac
+		}
+	}
+}
+
+// NewBlendProvider builds a provider that blends prices across providers.
+func NewBlendProvider(providers []Provider, opts ...BlendOption) (*BlendProvider, error) {
+	filtered := make([]Provider, 0, len(providers))
+	for _, p := range providers {
+		if p != nil {
+			filtered = append(filtered, p)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil, errors.New("market: blend provider requires at least one provider")
+	}
+	b := &BlendProvider{
+		providers:    filtered,
+		policy:       BlendMedian,
+		maxDeviation: defaultBlendMaxDeviation,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(b)
+		}
+	}
+	switch b.policy {
+	case BlendMedian, BlendMean:
+	case BlendWeighted:
+		if len(b.weights) != len(b.providers) {
+			return nil, fmt.Errorf("market: blend provider has %d weights for %d providers", len(b.weights), len(b.providers))
+		}
+		for i, w := range b.weights {
+			if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+				return nil, fmt.Errorf("market: blend provider weight[%d] must be a non-negative number", i)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("market: blend policy %q unsupported", b.policy)
+	}
+	return b, nil
+}
+
+// Snapshot queries every provider concurrently and returns a snapshot whose
+// last price is the blend of the non-outlier prices. It fails only when no
+// provider returns a usable price.
+func (b *BlendProvider) Snapshot(ctx context.Context, symbol string) (*Snapshot, error) {
+	snaps := make([]*Snapshot, len(b.providers))
+	errs := make([]error, len(b.providers))
+	var wg sync.WaitGroup
+	for i, p := range b.providers {
+		wg.Add(1)
+		go func(i int, p Provider) {
+			defer wg.Done()
+			snaps[i], errs[i] = p.Snapshot(ctx, symbol)
+		}(i, p)
+	}
+	wg.Wait()
+
+	var (
+		idx    []int
+		prices []float64
+		failed []error
+	)
+	for i, snap := range snaps {
+		switch {
+		case errs[i] != nil:
+			failed = append(failed, fmt.Errorf("provider[%d]: %w", i, errs[i]))
+		case snap == nil || snap.Price.Last <= 0 || math.IsNaN(snap.Price.Last) || math.IsInf(snap.Price.Last, 0):
+			failed = append(failed, fmt.Errorf("provider[%d]: no usable price", i))
+		default:
+			idx = append(idx, i)
+			prices = append(prices, snap.Price.Last)
+		}
+	}
+	if len(idx) == 0 {
+		return nil, fmt.Errorf("market: snapshot %s: %w", symbol, errors.Join(failed...))
+	}
+
+	mid := median(prices)
+	var kept []int
+	for _, i := range idx {
+		if math.Abs(snaps[i].Price.Last-mid)/mid <= b.maxDeviation {
+			kept = append(kept, i)
+		}
+	}
+	if len(kept) == 0 {
+		return nil, fmt.Errorf("market: snapshot %s: no provider within %.4f of median price %.8f", symbol, b.maxDeviation, mid)
+	}
+
+	price, err := b.blend(snaps, kept)
+	if err != nil {
+		return nil, fmt.Errorf("market: snapshot %s: %w", symbol, err)
+	}
+	out := *snaps[kept[0]]
+	out.Price.Last = price
+	return &out, nil
+}
+
+// blend combines the last prices of the kept snapshots under the policy.
+func (b *BlendProvider) blend(snaps []*Snapshot, kept []int) (float64, error) {
+	switch b.policy {
+	case BlendMean:
+		var sum float64
+		for _, i := range kept {
+			sum += snaps[i].Price.Last
+		}
+		return sum / float64(len(kept)), nil
+	case BlendWeighted:
+		var sum, total float64
+		for _, i := range kept {
+			sum += snaps[i].Price.Last * b.weights[i]
+			total += b.weights[i]
+		}
+		if total == 0 {
+			return 0, errors.New("surviving providers have zero total weight")
+		}
+		return sum / total, nil
+	default:
+		prices := make([]float64, len(kept))
+		for j, i := range kept {
+			prices[j] = snaps[i].Price.Last
+		}
+		return median(prices), nil
+	}
+}
+
+// ListAssets returns the asset list from the first provider that answers.
+func (b *BlendProvider) ListAssets(ctx context.Context) ([]Asset, error) {
+	var errs []error
+	for i, p := range b.providers {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		assets, err := p.ListAssets(ctx)
+		if err == nil {
+			return assets, nil
+		}
+		errs = append(errs, fmt.Errorf("provider[%d]: %w", i, err))
+	}
+	return nil, fmt.Errorf("market: list assets: %w", errors.Join(errs...))
+}
+
+// SetPersistence forwards persistence hooks to wrapped providers that accept them.
+func (b *BlendProvider) SetPersistence(p Persistence) {
+	for _, provider := range b.providers {
+		if aware, ok := provider.(PersistenceAware); ok {
+			aware.SetPersistence(p)
+		}
+	}
+}
+
+// median returns the median of values without reordering the caller's slice.
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
@@ -0,0 +1,64 @@
+package market
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlendProviderRejectsOutlier(t *testing.T) {
+	providers := []Provider{
+		&stubProvider{price: 100},
+		&stubProvider{price: 150},
+		&stubProvider{price: 101},
+	}
+	cases := []struct {
+		name string
+		opts []BlendOption
+		want float64
+	}{
+		{name: "median", want: 100.5},
+		{name: "mean", opts: []BlendOption{WithBlendPolicy(BlendMean)}, want: 100.5},
+		{name: "weighted", opts: []BlendOption{WithBlendPolicy(BlendWeighted), WithBlendWeights(3, 10, 1)}, want: 100.25},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := NewBlendProvider(providers, tc.opts...)
+			require.NoError(t, err)
+			snap, err := b.Snapshot(context.Background(), "BTC")
+			require.NoError(t, err)
+			assert.InDelta(t, tc.want, snap.Price.Last, 1e-9, "the 150 outlier should be excluded")
+			assert.Equal(t, "BTC", snap.Symbol)
+		})
+	}
+}
+
+func TestBlendProviderToleratesFailures(t *testing.T) {
+	b, err := NewBlendProvider([]Provider{
+		&stubProvider{err: errors.New("down")},
+		&stubProvider{price: 100},
+		&stubProvider{price: 102},
+	}, WithBlendPolicy(BlendMean))
+	require.NoError(t, err)
+	snap, err := b.Snapshot(context.Background(), "ETH")
+	require.NoError(t, err)
+	assert.InDelta(t, 101.0, snap.Price.Last, 1e-9)
+
+	b, err = NewBlendProvider([]Provider{&stubProvider{err: errors.New("down")}})
+	require.NoError(t, err)
+	_, err = b.Snapshot(context.Background(), "ETH")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "down")
+}
+
+func TestNewBlendProviderValidatesWeights(t *testing.T) {
+	_, err := NewBlendProvider([]Provider{&stubProvider{}, &stubProvider{}}, WithBlendPolicy(BlendWeighted), WithBlendWeights(1))
+	require.Error(t, err)
+	_, err = NewBlendProvider([]Provider{&stubProvider{}}, WithBlendPolicy("mode"))
+	require.Error(t, err)
+	_, err = NewBlendProvider(nil)
+	require.Error(t, err)
+}
@@ -30,6 +30,14 @@ type ProviderConfig struct {
 	HTTPTimeoutRaw string        `yaml:"http_timeout"`
 	HTTPTimeout    time.Duration `yaml:"-"`
 	MaxRetries     int           `yaml:"max_retries"`
+	// WebSocket keeps market data fresh over a streaming feed where supported.
+	WebSocket bool `yaml:"websocket"`
+	// PriceSource picks the feed behind Snapshot.Price.Last (oracle, mark or
+	// mid) where supported.
+	PriceSource string `yaml:"price_source"`
+	// SnapshotDepth fills Snapshot.Depth from the order book on every
+	// snapshot where supported, costing one extra request each.
+	SnapshotDepth bool `yaml:"snapshot_depth"`
 }
 
 // ProviderBuilder constructs a Provider from configuration.
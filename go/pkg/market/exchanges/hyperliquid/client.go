@@ -20,6 +20,9 @@ const (
 	defaultHTTPTimeout      = 10 * time.Second
 	defaultMaxRetries       = 3
 	defaultRetryBackoffBase = 150 * time.Millisecond
+	// defaultAssetCacheTTL bounds how long universe metadata and asset
+	// contexts (funding, open interest, mark) are reused before refetching.
+	defaultAssetCacheTTL = 30 * time.Second
 )
 
 // ErrSymbolNotFound indicates that the requested symbol is not listed.
@@ -31,11 +34,24 @@ type Client struct {
 	httpClient *http.Client
 	maxRetries int
 	logger     *log.Logger
+	wsURL      string
+	live       *liveFeed
+	assetTTL   time.Duration
+	clock      func() time.Time
+	// priceSource selects the feed behind Snapshot.Price.Last.
+	priceSource PriceSource
+	// snapshotDepth adds an l2Book request to every snapshot to fill
+	// Snapshot.Depth; otherwise depth is only fetched through GetDepth.
+	snapshotDepth bool
 
+	// refreshMu serialises directory refreshes so concurrent callers that see
+	// a stale cache share a single metaAndAssetCtxs request.
+	refreshMu        sync.Mutex
 	symbolsMu        sync.RWMutex
 	symbolIndex      map[string]string
 	assetCtxBySymbol map[string]AssetCtx
 	universeMeta     map[string]UniverseEntry
+	directoryAt      time.Time
 }
 
 // Option configures a new Client.
@@ -68,6 +84,35 @@ func WithMaxRetries(max int) Option {
 	}
 }
 
+// WithWebSocketURL overrides the WebSocket endpoint used by the live feed
+// (derived from the info endpoint by default).
+func WithWebSocketURL(url string) Option {
+	return func(c *Client) {
+		if url != "" {
+			c.wsURL = url
+		}
+	}
+}
+
+// WithAssetCacheTTL sets how long the universe/asset context directory is
+// reused before it is refetched. Non-positive values keep the default.
+func WithAssetCacheTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		if ttl > 0 {
+			c.assetTTL = ttl
+		}
+	}
+}
+
+// WithClock overrides the time source used for cache expiry (primarily for testing).
+func WithClock(now func() time.Time) Option {
+	return func(c *Client) {
+		if now != nil {
+			c.clock = now
+		}
+	}
+}
+
 // WithLogger injects a custom logger (defaults to log.Default()).
 func WithLogger(l *log.Logger) Option {
 	return func(c *Client) {
@@ -85,6 +130,8 @@ func NewClient(opts ...Option) *Client {
 		httpClient: httpClient,
 		maxRetries: defaultMaxRetries,
 		logger:     log.Default(),
+		assetTTL:   defaultAssetCacheTTL,
+		clock:      time.Now,
 	}
 	for _, opt := range opts {
 		opt(client)
@@ -215,15 +262,48 @@ func (c *Client) refreshSymbolDirectory(ctx context.Context) error {
 	c.symbolIndex = index
 	c.assetCtxBySymbol = assetCtx
 	c.universeMeta = universe
+	c.directoryAt = c.now()
 	c.symbolsMu.Unlock()
 	return nil
 }
 
+// ensureSymbolDirectory refreshes the symbol directory when it is missing or
+// older than the asset cache TTL, reporting whether a refresh happened.
+func (c *Client) ensureSymbolDirectory(ctx context.Context) (bool, error) {
+	if c.directoryFresh() {
+		return false, nil
+	}
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	// Another caller may have refreshed while we waited.
+	if c.directoryFresh() {
+		return false, nil
+	}
+	if err := c.refreshSymbolDirectory(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *Client) directoryFresh() bool {
+	c.symbolsMu.RLock()
+	at := c.directoryAt
+	c.symbolsMu.RUnlock()
+	return !at.IsZero() && c.now().Sub(at) < c.assetTTL
+}
+
+func (c *Client) now() time.Time {
+	if c.clock != nil {
+		return c.clock()
+	}
+	return time.Now()
+}
+
 func (c *Client) canonicalSymbolFor(ctx context.Context, symbol string) (string, error) {
 	if canonical, ok := c.canonicalFromCache(symbol); ok {
 		return canonical, nil
 	}
-	if err := c.refreshSymbolDirectory(ctx); err != nil {
+	if _, err := c.ensureSymbolDirectory(ctx); err != nil {
 		return "", err
 	}
 	if canonical, ok := c.canonicalFromCache(symbol); ok {
@@ -1,13 +1,16 @@
 package hyperliquid
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -20,6 +23,7 @@ import (
 func TestProviderSnapshot(t *testing.T) {
 	server, provider := newMockProvider(t)
 	defer server.Close()
+	provider.client.snapshotDepth = true
 
 	ctx := context.Background()
 	snapshot, err := provider.Snapshot(ctx, "BTCUSDT")
@@ -31,6 +35,15 @@ func TestProviderSnapshot(t *testing.T) {
 	require.InDelta(t, 0.00671141, snapshot.Change.FourHour, 1e-8)
 	require.NotNil(t, snapshot.OpenInterest)
 	require.InDelta(t, 150.0, snapshot.OpenInterest.Latest, 1e-9)
+	require.NotNil(t, snapshot.Depth, "l2Book depth should be surfaced on the snapshot")
+	require.Equal(t, snapshotDepthLevels, snapshot.Depth.Levels)
+	require.InDelta(t, 149.0, snapshot.Depth.BestBid, 1e-9)
+	require.InDelta(t, 151.0, snapshot.Depth.BestAsk, 1e-9)
+	require.InDelta(t, 2*(149+148+147+146+145), snapshot.Depth.BidSizeUSD, 1e-9)
+	require.NotNil(t, snapshot.Impact, "impactPxs should be surfaced on the snapshot")
+	require.InDelta(t, 149.9, snapshot.Impact.BidPx, 1e-9)
+	require.InDelta(t, 150.1, snapshot.Impact.AskPx, 1e-9)
+	require.InDelta(t, 20000, snapshot.Impact.NotionalUSD, 1e-9)
 	require.NotNil(t, snapshot.Intraday)
 	require.NotNil(t, snapshot.LongTerm)
 	require.NotEmpty(t, snapshot.Indicators.EMA)
@@ -39,16 +52,29 @@ func TestProviderSnapshot(t *testing.T) {
 func TestProviderSnapshotMixedCase(t *testing.T) {
 	server, provider := newMockProvider(t)
 	defer server.Close()
+	provider.client.snapshotDepth = true
 
 	ctx := context.Background()
 	snapshot, err := provider.Snapshot(ctx, "kpepeusdt")
 	require.NoError(t, err)
 	require.Equal(t, "kPEPE", snapshot.Symbol)
+	require.Nil(t, snapshot.Depth, "a failed l2Book request should not block the snapshot")
 	require.InDelta(t, 0.00095, snapshot.Price.Last, 1e-9)
 	require.NotNil(t, snapshot.Intraday)
 	require.NotNil(t, snapshot.LongTerm)
 }
 
+func TestProviderSnapshotSkipsDepthByDefault(t *testing.T) {
+	server, provider := newMockProvider(t)
+	defer server.Close()
+
+	snapshot, err := provider.Snapshot(context.Background(), "BTC")
+	require.NoError(t, err)
+	require.Nil(t, snapshot.Depth, "depth needs snapshot_depth; GetDepth serves it on demand")
+	require.False(t, NewProvider().client.snapshotDepth)
+	require.True(t, NewProvider(WithSnapshotDepth(true)).client.snapshotDepth)
+}
+
 func TestProviderListAssets(t *testing.T) {
 	server, provider := newMockProvider(t)
 	defer server.Close()
@@ -74,6 +100,51 @@ func TestClientGetKlines(t *testing.T) {
 	require.InDelta(t, 150.0, klines[len(klines)-1].Close, 1e-9)
 }
 
+func TestClientGetFundingHistory(t *testing.T) {
+	server, client := newMockHyperliquidServer(t)
+	defer server.Close()
+
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	records, err := client.GetFundingHistory(context.Background(), "btc", start, start.Add(3*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, start.Add(time.Hour), records[0].Time, "records should be sorted oldest first")
+	require.InDelta(t, 0.0001, records[0].Rate, 1e-12)
+	require.InDelta(t, 0.0002, records[1].Rate, 1e-12)
+
+	_, err = client.GetFundingHistory(context.Background(), "BTC", start, start.Add(-time.Hour))
+	require.Error(t, err)
+}
+
+func TestClientGetDepth(t *testing.T) {
+	server, client := newMockHyperliquidServer(t)
+	defer server.Close()
+
+	book, err := client.GetDepth(context.Background(), "btc", 3)
+	require.NoError(t, err)
+	require.Equal(t, "BTC", book.Symbol)
+	require.Len(t, book.Bids, 3)
+	require.Len(t, book.Asks, 3)
+	require.InDelta(t, 149.0, book.Bids[0].Price, 1e-9)
+	require.InDelta(t, 147.0, book.Bids[2].Price, 1e-9)
+	require.InDelta(t, 151.0, book.Asks[0].Price, 1e-9)
+	require.InDelta(t, 2.0, book.Bids[0].Size, 1e-9)
+	require.Equal(t, 3, book.Bids[2].Orders)
+	require.Equal(t, time.UnixMilli(1_700_000_000_000).UTC(), book.Time)
+
+	full, err := client.GetDepth(context.Background(), "BTC", 0)
+	require.NoError(t, err)
+	require.Len(t, full.Bids, 6, "levels <= 0 should keep every level")
+
+	depth := book.Depth(2)
+	require.NotNil(t, depth)
+	require.Equal(t, 2, depth.Levels)
+	require.InDelta(t, 149.0, depth.BestBid, 1e-9)
+	require.InDelta(t, 151.0, depth.BestAsk, 1e-9)
+	require.InDelta(t, 149*2+148*2, depth.BidSizeUSD, 1e-9)
+	require.InDelta(t, 151+152, depth.AskSizeUSD, 1e-9)
+}
+
 func TestClientGetMarketInfo(t *testing.T) {
 	server, client := newMockHyperliquidServer(t)
 	defer server.Close()
@@ -216,8 +287,89 @@ func TestListAssetsReflectsDelistedStatus(t *testing.T) {
 	require.False(t, pepe.IsActive)
 }
 
+// metaCountingTransport counts metaAndAssetCtxs requests passing through it.
+type metaCountingTransport struct {
+	base  http.RoundTripper
+	metas atomic.Int32
+}
+
+func (m *metaCountingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if strings.Contains(string(body), `"metaAndAssetCtxs"`) {
+		m.metas.Add(1)
+	}
+	return m.base.RoundTrip(req)
+}
+
+func TestAssetDirectoryCachedWithinTTL(t *testing.T) {
+	server, _ := newMockHyperliquidServer(t)
+	defer server.Close()
+
+	transport := &metaCountingTransport{base: server.Client().Transport}
+	now := time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC)
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithMaxRetries(0),
+		WithAssetCacheTTL(time.Minute),
+		WithClock(func() time.Time { return now }),
+	)
+	provider := &Provider{client: client, timeout: defaultProviderTimeout}
+	ctx := context.Background()
+
+	assets, err := provider.ListAssets(ctx)
+	require.NoError(t, err)
+	require.Len(t, assets, 2)
+	_, err = provider.Snapshot(ctx, "BTC")
+	require.NoError(t, err)
+	now = now.Add(45 * time.Second)
+	_, err = provider.Snapshot(ctx, "kPEPE")
+	require.NoError(t, err)
+	_, err = provider.ListAssets(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, transport.metas.Load(), "metadata should be fetched once within the TTL")
+
+	now = now.Add(30 * time.Second) // 75s after the first fetch
+	_, err = provider.ListAssets(ctx)
+	require.NoError(t, err)
+	info, err := client.GetMarketInfo(ctx, "BTC")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.000125, info.FundingRate, 1e-12)
+	assert.EqualValues(t, 2, transport.metas.Load(), "metadata should be refreshed exactly once after the TTL lapses")
+}
+
 // --- helpers ---
 
+func TestProviderSnapshotPriceSource(t *testing.T) {
+	server, _ := newMockHyperliquidServer(t)
+	defer server.Close()
+
+	cases := map[PriceSource]float64{
+		PriceSourceMid:    150.0,
+		PriceSourceMark:   150.2,
+		PriceSourceOracle: 149.7,
+	}
+	for src, want := range cases {
+		provider := NewProvider(
+			WithClientOptions(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithMaxRetries(0)),
+			WithPriceSource(src),
+		)
+		snapshot, err := provider.Snapshot(context.Background(), "BTC")
+		require.NoError(t, err, "source %s", src)
+		assert.InDelta(t, want, snapshot.Price.Last, 1e-9, "source %s should feed Price.Last", src)
+	}
+
+	_, err := ParsePriceSource("last")
+	assert.Error(t, err)
+	src, err := ParsePriceSource(" Oracle ")
+	require.NoError(t, err)
+	assert.Equal(t, PriceSourceOracle, src)
+}
+
 func newMockProvider(t *testing.T) (*httptest.Server, *Provider) {
 	t.Helper()
 	server, client := newMockHyperliquidServer(t)
@@ -322,6 +474,31 @@ func newMockHyperliquidServer(t *testing.T) (*httptest.Server, *Client) {
 			writeJSON(w, metaPayload)
 		case "allMids":
 			writeJSON(w, allMids)
+		case "fundingHistory":
+			if req.Coin != "BTC" || req.StartTime <= 0 {
+				http.Error(w, "bad funding request", http.StatusBadRequest)
+				return
+			}
+			writeJSON(w, []map[string]interface{}{
+				{"coin": "BTC", "fundingRate": "0.0002", "premium": "0.0001", "time": req.StartTime + 2*3600_000},
+				{"coin": "BTC", "fundingRate": "0.0001", "premium": "0.00005", "time": req.StartTime + 3600_000},
+			})
+		case "l2Book":
+			if req.Coin != "BTC" {
+				http.Error(w, "coin not mocked", http.StatusBadRequest)
+				return
+			}
+			bids := make([]map[string]interface{}, 0, 6)
+			asks := make([]map[string]interface{}, 0, 6)
+			for i := 0; i < 6; i++ {
+				bids = append(bids, map[string]interface{}{"px": fmt.Sprintf("%d", 149-i), "sz": "2", "n": i + 1})
+				asks = append(asks, map[string]interface{}{"px": fmt.Sprintf("%d", 151+i), "sz": "1", "n": 1})
+			}
+			writeJSON(w, map[string]interface{}{
+				"coin":   "BTC",
+				"time":   int64(1_700_000_000_000),
+				"levels": []interface{}{bids, asks},
+			})
 		default:
 			http.Error(w, "unsupported type", http.StatusBadRequest)
 		}
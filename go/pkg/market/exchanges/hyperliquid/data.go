@@ -40,7 +40,7 @@ func (c *Client) buildSnapshot(ctx context.Context, symbol string) (*market.Snap
 		return nil, nil, err
 	}
 
-	lastPrice, err := c.getCurrentPriceForCanonical(ctx, info.Symbol)
+	lastPrice, err := c.snapshotPrice(ctx, info)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -99,10 +99,30 @@ func (c *Client) buildSnapshot(ctx context.Context, symbol string) (*market.Snap
 		}
 	}
 
+	// Depth is opt-in and best-effort: a missing book should not block the
+	// snapshot.
+	var depth *market.DepthInfo
+	if c.snapshotDepth {
+		if book, err := c.GetDepth(ctx, info.Symbol, snapshotDepthLevels); err == nil {
+			depth = book.Depth(snapshotDepthLevels)
+		}
+	}
+
+	var impact *market.ImpactInfo
+	if info.ImpactBidPx > 0 && info.ImpactAskPx > 0 {
+		impact = &market.ImpactInfo{
+			BidPx:       info.ImpactBidPx,
+			AskPx:       info.ImpactAskPx,
+			NotionalUSD: impactNotionalUSD(info.Symbol),
+		}
+	}
+
 	snapshot := &market.Snapshot{
 		Symbol: info.Symbol,
 		Price: market.PriceInfo{
-			Last: lastPrice,
+			Last:   lastPrice,
+			Mark:   info.MarkPrice,
+			Oracle: info.OraclePrice,
 		},
 		Change: market.ChangeInfo{
 			OneHour:  change1h,
@@ -111,6 +131,8 @@ func (c *Client) buildSnapshot(ctx context.Context, symbol string) (*market.Snap
 		Indicators:   indicator,
 		OpenInterest: openInterest,
 		Funding:      funding,
+		Depth:        depth,
+		Impact:       impact,
 		Intraday:     intradaySeries,
 		LongTerm:     longerSeries,
 	}
@@ -120,6 +142,9 @@ func (c *Client) buildSnapshot(ctx context.Context, symbol string) (*market.Snap
 }
 
 func (c *Client) getCurrentPriceForCanonical(ctx context.Context, symbol string) (float64, error) {
+	if px, ok := c.liveMid(symbol); ok {
+		return px, nil
+	}
 	var response AllMidsResponse
 	if err := c.doRequest(ctx, InfoRequest{Type: "allMids"}, &response); err != nil {
 		return 0, err
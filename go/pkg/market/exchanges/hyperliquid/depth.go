@@ -0,0 +1,56 @@
+package hyperliquid
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"nof0-api/pkg/market"
+)
+
+// snapshotDepthLevels is the number of book levels per side summarised into
+// Snapshot.Depth.
+const snapshotDepthLevels = 5
+
+// GetDepth fetches the l2Book for symbol and returns at most levels per side;
+// levels <= 0 returns every level the exchange reports (up to 20).
+func (c *Client) GetDepth(ctx context.Context, symbol string, levels int) (*market.OrderBook, error) {
+	canonical, err := c.canonicalSymbolFor(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	var response L2BookResponse
+	if err := c.doRequest(ctx, InfoRequest{Type: "l2Book", Coin: canonical}, &response); err != nil {
+		return nil, err
+	}
+	book := &market.OrderBook{
+		Symbol: canonical,
+		Bids:   toBookLevels(response.Levels[0], levels),
+		Asks:   toBookLevels(response.Levels[1], levels),
+	}
+	if response.Time > 0 {
+		book.Time = time.UnixMilli(response.Time).UTC()
+	}
+	if len(book.Bids) > 0 && len(book.Asks) > 0 && book.Bids[0].Price >= book.Asks[0].Price {
+		return nil, fmt.Errorf("hyperliquid: crossed l2Book for %s: bid %v >= ask %v", canonical, book.Bids[0].Price, book.Asks[0].Price)
+	}
+	return book, nil
+}
+
+func toBookLevels(raw []L2BookLevel, limit int) []market.BookLevel {
+	if limit > 0 && len(raw) > limit {
+		raw = raw[:limit]
+	}
+	levels := make([]market.BookLevel, 0, len(raw))
+	for _, l := range raw {
+		levels = append(levels, market.BookLevel{Price: l.Px, Size: l.Sz, Orders: l.N})
+	}
+	return levels
+}
+
+// GetDepth implements market.DepthProvider.
+func (p *Provider) GetDepth(ctx context.Context, symbol string, levels int) (*market.OrderBook, error) {
+	ctx, cancel := p.withTimeout(ctx)
+	defer cancel()
+	return p.client.GetDepth(ctx, symbol, levels)
+}
@@ -0,0 +1,53 @@
+package hyperliquid
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"nof0-api/pkg/market"
+)
+
+// GetFundingHistory fetches settled funding rates for symbol within [start, end].
+func (c *Client) GetFundingHistory(ctx context.Context, symbol string, start, end time.Time) ([]market.FundingRecord, error) {
+	if !end.IsZero() && end.Before(start) {
+		return nil, fmt.Errorf("hyperliquid: funding history end %s before start %s", end, start)
+	}
+	canonical, err := c.canonicalSymbolFor(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	request := InfoRequest{
+		Type:      "fundingHistory",
+		Coin:      canonical,
+		StartTime: start.UTC().UnixMilli(),
+	}
+	if !end.IsZero() {
+		request.EndTime = end.UTC().UnixMilli()
+	}
+	var response FundingHistoryResponse
+	if err := c.doRequest(ctx, request, &response); err != nil {
+		return nil, err
+	}
+	records := make([]market.FundingRecord, 0, len(response))
+	for _, item := range response {
+		records = append(records, market.FundingRecord{
+			Symbol:  symbol,
+			Rate:    item.FundingRate,
+			Premium: item.Premium,
+			Time:    time.UnixMilli(item.Time).UTC(),
+		})
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+	return records, nil
+}
+
+// GetFundingHistory implements market.FundingHistoryProvider.
+func (p *Provider) GetFundingHistory(ctx context.Context, symbol string, start, end time.Time) ([]market.FundingRecord, error) {
+	ctx, cancel := p.withTimeout(ctx)
+	defer cancel()
+	return p.client.GetFundingHistory(ctx, symbol, start, end)
+}
@@ -26,6 +26,11 @@ func (c *Client) GetKlines(ctx context.Context, symbol string, interval string,
 	if err != nil {
 		return nil, err
 	}
+	if c.live != nil {
+		if klines, ok := c.live.klines(canonical, interval, limit); ok {
+			return klines, nil
+		}
+	}
 	endTime := time.Now().UTC()
 	startTime := endTime.Add(-duration * time.Duration(limit+10))
 
@@ -67,6 +72,9 @@ func (c *Client) GetKlines(ctx context.Context, symbol string, interval string,
 	if len(klines) > limit {
 		klines = klines[len(klines)-limit:]
 	}
+	if c.live != nil {
+		c.live.seed(canonical, interval, klines, limit)
+	}
 
 	return klines, nil
 }
@@ -13,9 +13,23 @@ type MarketInfo struct {
 	Symbol       string  // Canonical Hyperliquid symbol
 	MarkPrice    float64 // Mark price
 	MidPrice     float64 // Mid price
+	OraclePrice  float64 // Oracle price (0 when not reported)
 	FundingRate  float64 // Funding rate (decimal, not percentage)
 	OpenInterest float64 // Current open interest
 	DayVolume    float64 // 24h base volume
+	ImpactBidPx  float64 // Impact bid price (0 when not reported)
+	ImpactAskPx  float64 // Impact ask price (0 when not reported)
+}
+
+// impactNotionalUSD returns the order size Hyperliquid quotes impactPxs for:
+// 20k USDC on BTC and ETH, 6k USDC on every other perp.
+func impactNotionalUSD(symbol string) float64 {
+	switch strings.ToUpper(symbol) {
+	case "BTC", "ETH":
+		return 20000
+	default:
+		return 6000
+	}
 }
 
 // GetCurrentPrice returns the current mid price for the given symbol.
@@ -24,6 +38,9 @@ func (c *Client) GetCurrentPrice(ctx context.Context, symbol string) (float64, e
 	if err != nil {
 		return 0, err
 	}
+	if px, ok := c.liveMid(canonical); ok {
+		return px, nil
+	}
 
 	var response AllMidsResponse
 	if err := c.doRequest(ctx, InfoRequest{Type: "allMids"}, &response); err != nil {
@@ -46,13 +63,20 @@ func (c *Client) GetCurrentPrice(ctx context.Context, symbol string) (float64, e
 
 // GetMarketInfo retrieves funding, open interest and related metrics.
 func (c *Client) GetMarketInfo(ctx context.Context, symbol string) (*MarketInfo, error) {
-	if err := c.refreshSymbolDirectory(ctx); err != nil {
-		return nil, err
-	}
-
+	// The live feed keeps asset contexts fresh, so REST is only needed on a
+	// miss; otherwise the directory is refetched once the asset cache TTL lapses.
 	canonical, ctxData, ok := c.assetCtxFromCache(symbol)
-	if !ok {
-		return nil, ErrSymbolNotFound
+	if !ok || !c.liveReady() {
+		if _, err := c.ensureSymbolDirectory(ctx); err != nil {
+			return nil, err
+		}
+		canonical, ctxData, ok = c.assetCtxFromCache(symbol)
+		if !ok {
+			return nil, ErrSymbolNotFound
+		}
+	}
+	if c.live != nil {
+		c.live.watchAssetCtx(canonical)
 	}
 	mark, err := parseFloat(ctxData.MarkPx)
 	if err != nil {
@@ -68,6 +92,13 @@ func (c *Client) GetMarketInfo(ctx context.Context, symbol string) (*MarketInfo,
 	if math.IsNaN(mid) {
 		mid = mark
 	}
+	oracle, err := parseFloat(ctxData.OraclePx)
+	if err != nil {
+		return nil, fmt.Errorf("hyperliquid: parse oracle price: %w", err)
+	}
+	if math.IsNaN(oracle) {
+		oracle = 0
+	}
 	funding, err := parseFloat(ctxData.Funding)
 	if err != nil {
 		return nil, fmt.Errorf("hyperliquid: parse funding: %w", err)
@@ -92,14 +123,26 @@ func (c *Client) GetMarketInfo(ctx context.Context, symbol string) (*MarketInfo,
 			dayVolume = 0
 		}
 	}
+	// impactPxs is [bid, ask]; anything else is treated as unreported.
+	var impactBid, impactAsk float64
+	if len(ctxData.ImpactPxs) == 2 {
+		bid, bidErr := parseFloat(ctxData.ImpactPxs[0])
+		ask, askErr := parseFloat(ctxData.ImpactPxs[1])
+		if bidErr == nil && askErr == nil && bid > 0 && ask > 0 {
+			impactBid, impactAsk = bid, ask
+		}
+	}
 
 	return &MarketInfo{
 		Symbol:       canonical,
 		MarkPrice:    mark,
 		MidPrice:     mid,
+		OraclePrice:  oracle,
 		FundingRate:  funding,
 		OpenInterest: oi,
 		DayVolume:    dayVolume,
+		ImpactBidPx:  impactBid,
+		ImpactAskPx:  impactAsk,
 	}, nil
 }
 
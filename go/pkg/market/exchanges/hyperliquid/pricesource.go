@@ -0,0 +1,49 @@
+package hyperliquid
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// PriceSource selects which Hyperliquid price feeds Snapshot.Price.Last.
+type PriceSource string
+
+const (
+	// PriceSourceMid uses the order book mid from allMids (default).
+	PriceSourceMid PriceSource = "mid"
+	// PriceSourceMark uses the mark price from the asset context.
+	PriceSourceMark PriceSource = "mark"
+	// PriceSourceOracle uses the oracle price from the asset context, which
+	// is the hardest to move with thin-book trades.
+	PriceSourceOracle PriceSource = "oracle"
+)
+
+// ParsePriceSource validates a configured price source; empty selects mid.
+func ParsePriceSource(raw string) (PriceSource, error) {
+	switch src := PriceSource(strings.ToLower(strings.TrimSpace(raw))); src {
+	case "":
+		return PriceSourceMid, nil
+	case PriceSourceMid, PriceSourceMark, PriceSourceOracle:
+		return src, nil
+	default:
+		return "", fmt.Errorf("hyperliquid: unsupported price source %q", raw)
+	}
+}
+
+// snapshotPrice returns the price for info.Symbol from the configured source.
+// Asset-context prices fall back to the mid when the context lacks them.
+func (c *Client) snapshotPrice(ctx context.Context, info *MarketInfo) (float64, error) {
+	switch c.priceSource {
+	case PriceSourceMark:
+		if info.MarkPrice > 0 {
+			return info.MarkPrice, nil
+		}
+	case PriceSourceOracle:
+		if info.OraclePrice > 0 && !math.IsNaN(info.OraclePrice) {
+			return info.OraclePrice, nil
+		}
+	}
+	return c.getCurrentPriceForCanonical(ctx, info.Symbol)
+}
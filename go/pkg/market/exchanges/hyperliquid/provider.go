@@ -23,12 +23,14 @@ type Provider struct {
 	providerID  string
 	cacheMu     sync.RWMutex
 	snapshots   map[string]cachedSnapshot
-	assets      cachedAssets
 }
 
 type providerConfig struct {
 	timeout      time.Duration
 	clientConfig []Option
+	websocket    bool
+	priceSource  PriceSource
+	depth        bool
 }
 
 // ProviderOption customises the Hyperliquid provider.
@@ -50,6 +52,32 @@ func WithClientOptions(options ...Option) ProviderOption {
 	}
 }
 
+// WithWebSocket keeps mids, candles and asset contexts fresh over the
+// Hyperliquid WebSocket feed, falling back to REST while disconnected.
+func WithWebSocket(enabled bool) ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.websocket = enabled
+	}
+}
+
+// WithPriceSource selects whether Snapshot.Price.Last comes from the oracle,
+// mark or mid price. Oracle prices resist manipulation better for guard
+// decisions; mid is the default.
+func WithPriceSource(src PriceSource) ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.priceSource = src
+	}
+}
+
+// WithSnapshotDepth fills Snapshot.Depth from the l2Book on every snapshot,
+// at the cost of one extra info request each. Off by default; callers that
+// need depth on demand use GetDepth (market.DepthProvider) instead.
+func WithSnapshotDepth(enabled bool) ProviderOption {
+	return func(cfg *providerConfig) {
+		cfg.depth = enabled
+	}
+}
+
 // NewProvider constructs a Hyperliquid market provider.
 func NewProvider(opts ...ProviderOption) *Provider {
 	cfg := &providerConfig{
@@ -60,6 +88,11 @@ func NewProvider(opts ...ProviderOption) *Provider {
 	}
 
 	client := NewClient(cfg.clientConfig...)
+	client.priceSource = cfg.priceSource
+	client.snapshotDepth = cfg.depth
+	if cfg.websocket {
+		client.startLiveFeed()
+	}
 	return &Provider{
 		client:    client,
 		timeout:   cfg.timeout,
@@ -83,6 +116,19 @@ func init() {
 		if cfg.MaxRetries > 0 {
 			clientOptions = append(clientOptions, WithMaxRetries(cfg.MaxRetries))
 		}
+		if cfg.WebSocket {
+			opts = append(opts, WithWebSocket(true))
+		}
+		if cfg.SnapshotDepth {
+			opts = append(opts, WithSnapshotDepth(true))
+		}
+		if cfg.PriceSource != "" {
+			src, err := ParsePriceSource(cfg.PriceSource)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, WithPriceSource(src))
+		}
 		if len(clientOptions) > 0 {
 			opts = append(opts, WithClientOptions(clientOptions...))
 		}
@@ -96,8 +142,11 @@ func init() {
 func (p *Provider) Snapshot(ctx context.Context, symbol string) (*market.Snapshot, error) {
 	ctx, cancel := p.withTimeout(ctx)
 	defer cancel()
-	if snap, ok := p.loadSnapshot(symbol); ok {
-		return snap, nil
+	// A connected live feed is fresher than the TTL cache.
+	if !p.client.liveReady() {
+		if snap, ok := p.loadSnapshot(symbol); ok {
+			return snap, nil
+		}
 	}
 	snap, ticks, err := p.client.buildSnapshot(ctx, symbol)
 	if err != nil {
@@ -113,24 +162,25 @@ func (p *Provider) Snapshot(ctx context.Context, symbol string) (*market.Snapsho
 	return snap, nil
 }
 
+// Close stops the WebSocket feed, if running.
+func (p *Provider) Close() {
+	p.client.stopLiveFeed()
+}
+
 // ListAssets implements market.Provider by returning all supported symbols.
 func (p *Provider) ListAssets(ctx context.Context) ([]market.Asset, error) {
 	ctx, cancel := p.withTimeout(ctx)
 	defer cancel()
-	if assets, ok := p.loadAssets(); ok {
-		return assets, nil
-	}
-
-	if err := p.client.refreshSymbolDirectory(ctx); err != nil {
+	refreshed, err := p.client.ensureSymbolDirectory(ctx)
+	if err != nil {
 		return nil, err
 	}
 	assets := p.collectAssets()
-	if p.persistence != nil && len(assets) > 0 {
+	if refreshed && p.persistence != nil && len(assets) > 0 {
 		if err := p.persistence.UpsertAssets(ctx, p.providerName(), assets); err != nil {
 			logx.WithContext(ctx).Errorf("hyperliquid: persist assets err=%v", err)
 		}
 	}
-	p.storeAssets(assets)
 	return assets, nil
 }
 
@@ -180,21 +230,13 @@ func (p *Provider) SetPersistence(persist market.Persistence) {
 	p.persistence = persist
 }
 
-const (
-	snapshotCacheTTL = 15 * time.Second
-	assetCacheTTL    = 5 * time.Minute
-)
+const snapshotCacheTTL = 15 * time.Second
 
 type cachedSnapshot struct {
 	Snapshot *market.Snapshot
 	Fetched  time.Time
 }
 
-type cachedAssets struct {
-	Assets  []market.Asset
-	Fetched time.Time
-}
-
 func (p *Provider) loadSnapshot(symbol string) (*market.Snapshot, bool) {
 	p.cacheMu.RLock()
 	defer p.cacheMu.RUnlock()
@@ -219,25 +261,6 @@ func (p *Provider) storeSnapshot(symbol string, snapshot *market.Snapshot) {
 	p.snapshots[strings.ToUpper(symbol)] = cachedSnapshot{Snapshot: &copy, Fetched: time.Now()}
 }
 
-func (p *Provider) loadAssets() ([]market.Asset, bool) {
-	p.cacheMu.RLock()
-	defer p.cacheMu.RUnlock()
-	if len(p.assets.Assets) == 0 || time.Since(p.assets.Fetched) > assetCacheTTL {
-		return nil, false
-	}
-	assets := make([]market.Asset, len(p.assets.Assets))
-	copy(assets, p.assets.Assets)
-	return assets, true
-}
-
-func (p *Provider) storeAssets(assets []market.Asset) {
-	p.cacheMu.Lock()
-	defer p.cacheMu.Unlock()
-	clone := make([]market.Asset, len(assets))
-	copy(clone, assets)
-	p.assets = cachedAssets{Assets: clone, Fetched: time.Now()}
-}
-
 func (p *Provider) providerName() string {
 	if strings.TrimSpace(p.providerID) != "" {
 		return p.providerID
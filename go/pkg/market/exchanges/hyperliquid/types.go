@@ -20,6 +20,11 @@ type Kline struct {
 type InfoRequest struct {
 	Type string      `json:"type"`
 	Req  interface{} `json:"req,omitempty"`
+	// Coin, StartTime and EndTime are top-level parameters used by
+	// requests such as fundingHistory.
+	Coin      string `json:"coin,omitempty"`
+	StartTime int64  `json:"startTime,omitempty"`
+	EndTime   int64  `json:"endTime,omitempty"`
 }
 
 // CandleSnapshotRequest carries parameters for the candleSnapshot request.
@@ -43,6 +48,29 @@ type CandleResponse []struct {
 	V      float64 `json:"v,string"` // Volume
 }
 
+// FundingHistoryResponse mirrors the payload returned from fundingHistory requests.
+type FundingHistoryResponse []struct {
+	Coin        string  `json:"coin"`
+	FundingRate float64 `json:"fundingRate,string"`
+	Premium     float64 `json:"premium,string"`
+	Time        int64   `json:"time"` // Settlement timestamp (ms)
+}
+
+// L2BookResponse mirrors the payload returned from l2Book requests. Levels
+// holds bids then asks, each sorted best first.
+type L2BookResponse struct {
+	Coin   string           `json:"coin"`
+	Time   int64            `json:"time"` // Snapshot timestamp (ms)
+	Levels [2][]L2BookLevel `json:"levels"`
+}
+
+// L2BookLevel is one aggregated level of an l2Book response.
+type L2BookLevel struct {
+	Px float64 `json:"px,string"` // Price
+	Sz float64 `json:"sz,string"` // Aggregate size
+	N  int     `json:"n"`         // Number of resting orders
+}
+
 // MetaAndAssetCtxsResponse contains market meta data and per-asset contexts.
 type MetaAndAssetCtxsResponse struct {
 	Universe  []UniverseEntry
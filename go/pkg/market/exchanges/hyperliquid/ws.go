@@ -0,0 +1,409 @@
+package hyperliquid
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	wsReconnectMin    = time.Second
+	wsReconnectMax    = 30 * time.Second
+	wsDialTimeout     = 10 * time.Second
+	wsPingInterval    = 50 * time.Second // the server drops connections idle for 60s
+	wsUniverseRefresh = 5 * time.Minute
+	wsOrigin          = "http://localhost"
+)
+
+var errFeedNotConnected = errors.New("hyperliquid: websocket feed not connected")
+
+// liveFeed maintains a WebSocket subscription that keeps mids, candles and
+// asset contexts fresh in memory. Readers fall back to REST whenever the feed
+// is disconnected.
+type liveFeed struct {
+	client *Client
+	url    string
+
+	mu        sync.RWMutex
+	connected bool
+	mids      map[string]float64
+	candles   map[candleKey]*candleSeries
+	assetSubs map[string]struct{}
+
+	sendMu sync.Mutex
+	conn   *websocket.Conn
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type candleKey struct {
+	coin     string
+	interval string
+}
+
+// candleSeries holds the most recent limit klines for one coin and interval,
+// seeded over REST and advanced by candle updates.
+type candleSeries struct {
+	klines []Kline
+	limit  int
+}
+
+type wsEnvelope struct {
+	Channel string          `json:"channel"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type wsCandle struct {
+	T      int64   `json:"t"`
+	TClose int64   `json:"T"`
+	S      string  `json:"s"`
+	I      string  `json:"i"`
+	O      float64 `json:"o,string"`
+	C      float64 `json:"c,string"`
+	H      float64 `json:"h,string"`
+	L      float64 `json:"l,string"`
+	V      float64 `json:"v,string"`
+}
+
+// wsAssetCtx mirrors AssetCtx; the socket sends numbers where REST sends strings.
+type wsAssetCtx struct {
+	Coin string `json:"coin"`
+	Ctx  struct {
+		Funding      json.Number `json:"funding"`
+		OpenInterest json.Number `json:"openInterest"`
+		PrevDayPx    json.Number `json:"prevDayPx"`
+		DayNtlVlm    json.Number `json:"dayNtlVlm"`
+		DayBaseVlm   json.Number `json:"dayBaseVlm"`
+		Premium      json.Number `json:"premium"`
+		OraclePx     json.Number `json:"oraclePx"`
+		MarkPx       json.Number `json:"markPx"`
+		MidPx        json.Number `json:"midPx"`
+	} `json:"ctx"`
+}
+
+// wsURLFor derives the WebSocket endpoint from an info endpoint URL.
+func wsURLFor(baseURL string) string {
+	url := strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), "/info")
+	switch {
+	case strings.HasPrefix(url, "https://"):
+		url = "wss://" + strings.TrimPrefix(url, "https://")
+	case strings.HasPrefix(url, "http://"):
+		url = "ws://" + strings.TrimPrefix(url, "http://")
+	}
+	return url + "/ws"
+}
+
+// startLiveFeed connects the WebSocket feed in the background. It is a no-op
+// when the feed is already running.
+func (c *Client) startLiveFeed() {
+	if c.live != nil {
+		return
+	}
+	url := c.wsURL
+	if url == "" {
+		url = wsURLFor(c.baseURL)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.live = &liveFeed{
+		client:    c,
+		url:       url,
+		mids:      make(map[string]float64),
+		candles:   make(map[candleKey]*candleSeries),
+		assetSubs: make(map[string]struct{}),
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	go c.live.run(ctx)
+}
+
+// stopLiveFeed closes the WebSocket feed and waits for it to exit.
+func (c *Client) stopLiveFeed() {
+	if c.live == nil {
+		return
+	}
+	c.live.cancel()
+	<-c.live.done
+}
+
+func (c *Client) liveReady() bool {
+	return c.live != nil && c.live.ready()
+}
+
+func (c *Client) liveMid(coin string) (float64, bool) {
+	if c.live == nil {
+		return 0, false
+	}
+	return c.live.mid(coin)
+}
+
+func (f *liveFeed) run(ctx context.Context) {
+	defer close(f.done)
+	backoff := wsReconnectMin
+	for ctx.Err() == nil {
+		started := time.Now()
+		err := f.session(ctx)
+		f.reset()
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(started) > wsReconnectMax {
+			backoff = wsReconnectMin
+		}
+		logx.Errorf("hyperliquid: websocket disconnected url=%s err=%v retry_in=%s", f.url, err, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > wsReconnectMax {
+			backoff = wsReconnectMax
+		}
+	}
+}
+
+// session runs one connection until it fails or ctx is cancelled.
+func (f *liveFeed) session(ctx context.Context) error {
+	cfg, err := websocket.NewConfig(f.url, wsOrigin)
+	if err != nil {
+		return err
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, wsDialTimeout)
+	conn, err := cfg.DialContext(dialCtx)
+	cancel()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Universe metadata is not streamed; refresh it on connect and periodically.
+	if err := f.client.refreshSymbolDirectory(ctx); err != nil {
+		logx.Errorf("hyperliquid: websocket universe refresh err=%v", err)
+	}
+	f.sendMu.Lock()
+	f.conn = conn
+	f.sendMu.Unlock()
+	if err := f.subscribe(map[string]any{"type": "allMids"}); err != nil {
+		return err
+	}
+
+	sessionCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	go f.maintain(sessionCtx, conn)
+
+	for {
+		var raw []byte
+		if err := websocket.Message.Receive(conn, &raw); err != nil {
+			return err
+		}
+		var msg wsEnvelope
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			continue
+		}
+		f.handle(msg)
+	}
+}
+
+// maintain pings the server, refreshes universe metadata and closes conn
+// when the session ends.
+func (f *liveFeed) maintain(ctx context.Context, conn *websocket.Conn) {
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+	universe := time.NewTicker(wsUniverseRefresh)
+	defer universe.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+			return
+		case <-ping.C:
+			if err := f.send(map[string]any{"method": "ping"}); err != nil {
+				conn.Close()
+				return
+			}
+		case <-universe.C:
+			if err := f.client.refreshSymbolDirectory(ctx); err != nil {
+				logx.Errorf("hyperliquid: websocket universe refresh err=%v", err)
+			}
+		}
+	}
+}
+
+func (f *liveFeed) handle(msg wsEnvelope) {
+	switch msg.Channel {
+	case "allMids":
+		var payload struct {
+			Mids map[string]string `json:"mids"`
+		}
+		if err := json.Unmarshal(msg.Data, &payload); err != nil {
+			return
+		}
+		f.mu.Lock()
+		for coin, raw := range payload.Mids {
+			if px, err := parseFloat(raw); err == nil && px > 0 {
+				f.mids[coin] = px
+			}
+		}
+		f.connected = true
+		f.mu.Unlock()
+	case "candle":
+		var c wsCandle
+		if err := json.Unmarshal(msg.Data, &c); err != nil {
+			return
+		}
+		f.mergeCandle(c)
+	case "activeAssetCtx":
+		var payload wsAssetCtx
+		if err := json.Unmarshal(msg.Data, &payload); err != nil || payload.Coin == "" {
+			return
+		}
+		actx := payload.Ctx
+		f.client.symbolsMu.Lock()
+		if f.client.assetCtxBySymbol != nil {
+			f.client.assetCtxBySymbol[payload.Coin] = AssetCtx{
+				Funding:      actx.Funding.String(),
+				OpenInterest: actx.OpenInterest.String(),
+				PrevDayPx:    actx.PrevDayPx.String(),
+				DayNtlVlm:    actx.DayNtlVlm.String(),
+				DayBaseVlm:   actx.DayBaseVlm.String(),
+				Premium:      actx.Premium.String(),
+				OraclePx:     actx.OraclePx.String(),
+				MarkPx:       actx.MarkPx.String(),
+				MidPx:        actx.MidPx.String(),
+			}
+		}
+		f.client.symbolsMu.Unlock()
+	}
+}
+
+func (f *liveFeed) mergeCandle(c wsCandle) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	series, ok := f.candles[candleKey{coin: c.S, interval: c.I}]
+	if !ok || len(series.klines) == 0 {
+		return
+	}
+	k := Kline{OpenTime: c.T, Open: c.O, High: c.H, Low: c.L, Close: c.C, Volume: c.V, CloseTime: c.TClose}
+	last := &series.klines[len(series.klines)-1]
+	switch {
+	case k.OpenTime == last.OpenTime:
+		*last = k
+	case k.OpenTime > last.OpenTime:
+		series.klines = append(series.klines, k)
+		if len(series.klines) > series.limit {
+			series.klines = append([]Kline(nil), series.klines[len(series.klines)-series.limit:]...)
+		}
+	}
+}
+
+// reset marks the feed disconnected and drops streamed state so readers fall
+// back to REST until the next session reseeds it.
+func (f *liveFeed) reset() {
+	f.sendMu.Lock()
+	f.conn = nil
+	f.sendMu.Unlock()
+	f.mu.Lock()
+	f.connected = false
+	f.mids = make(map[string]float64)
+	f.candles = make(map[candleKey]*candleSeries)
+	f.assetSubs = make(map[string]struct{})
+	f.mu.Unlock()
+}
+
+func (f *liveFeed) ready() bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.connected
+}
+
+func (f *liveFeed) mid(coin string) (float64, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if !f.connected {
+		return 0, false
+	}
+	px, ok := f.mids[coin]
+	return px, ok
+}
+
+// klines returns the latest limit klines when the feed holds a series seeded
+// with at least that many.
+func (f *liveFeed) klines(coin, interval string, limit int) ([]Kline, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if !f.connected {
+		return nil, false
+	}
+	series, ok := f.candles[candleKey{coin: coin, interval: interval}]
+	if !ok || limit > series.limit || len(series.klines) == 0 {
+		return nil, false
+	}
+	out := series.klines
+	if len(out) > limit {
+		out = out[len(out)-limit:]
+	}
+	return append([]Kline(nil), out...), true
+}
+
+// seed stores REST klines and subscribes to candle updates for them.
+func (f *liveFeed) seed(coin, interval string, klines []Kline, limit int) {
+	if len(klines) == 0 {
+		return
+	}
+	key := candleKey{coin: coin, interval: interval}
+	f.mu.Lock()
+	if !f.connected {
+		f.mu.Unlock()
+		return
+	}
+	_, subscribed := f.candles[key]
+	if existing, ok := f.candles[key]; ok && existing.limit > limit {
+		limit = existing.limit
+	}
+	f.candles[key] = &candleSeries{klines: append([]Kline(nil), klines...), limit: limit}
+	f.mu.Unlock()
+	if subscribed {
+		return
+	}
+	if err := f.subscribe(map[string]any{"type": "candle", "coin": coin, "interval": interval}); err != nil {
+		logx.Errorf("hyperliquid: websocket subscribe candle coin=%s interval=%s err=%v", coin, interval, err)
+	}
+}
+
+// watchAssetCtx subscribes to asset context updates for coin once per session.
+func (f *liveFeed) watchAssetCtx(coin string) {
+	f.mu.Lock()
+	if _, ok := f.assetSubs[coin]; ok || !f.connected {
+		f.mu.Unlock()
+		return
+	}
+	f.assetSubs[coin] = struct{}{}
+	f.mu.Unlock()
+	if err := f.subscribe(map[string]any{"type": "activeAssetCtx", "coin": coin}); err != nil {
+		logx.Errorf("hyperliquid: websocket subscribe activeAssetCtx coin=%s err=%v", coin, err)
+	}
+}
+
+func (f *liveFeed) subscribe(subscription map[string]any) error {
+	return f.send(map[string]any{"method": "subscribe", "subscription": subscription})
+}
+
+func (f *liveFeed) send(v any) error {
+	f.sendMu.Lock()
+	defer f.sendMu.Unlock()
+	if f.conn == nil {
+		return errFeedNotConnected
+	}
+	if err := websocket.JSON.Send(f.conn, v); err != nil {
+		return fmt.Errorf("hyperliquid: websocket send: %w", err)
+	}
+	return nil
+}
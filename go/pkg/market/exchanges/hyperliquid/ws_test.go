@@ -0,0 +1,145 @@
+package hyperliquid
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+// mockFeed is a WebSocket server that hands each connection to the test and
+// records subscription requests.
+type mockFeed struct {
+	conns chan *websocket.Conn
+	subs  chan map[string]any
+}
+
+func newMockFeedServer(t *testing.T) (*httptest.Server, *mockFeed) {
+	t.Helper()
+	feed := &mockFeed{conns: make(chan *websocket.Conn, 4), subs: make(chan map[string]any, 32)}
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		feed.conns <- conn
+		for {
+			var msg map[string]any
+			if err := websocket.JSON.Receive(conn, &msg); err != nil {
+				return
+			}
+			if sub, ok := msg["subscription"].(map[string]any); ok {
+				feed.subs <- sub
+			}
+		}
+	}))
+	return server, feed
+}
+
+func (f *mockFeed) nextConn(t *testing.T) *websocket.Conn {
+	t.Helper()
+	select {
+	case conn := <-f.conns:
+		return conn
+	case <-time.After(5 * time.Second):
+		t.Fatal("websocket client did not connect")
+		return nil
+	}
+}
+
+// awaitSub waits for a subscription of the given type and coin ("" matches any).
+func (f *mockFeed) awaitSub(t *testing.T, typ, coin, interval string) {
+	t.Helper()
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case sub := <-f.subs:
+			if sub["type"] == typ && (coin == "" || sub["coin"] == coin) && (interval == "" || sub["interval"] == interval) {
+				return
+			}
+		case <-deadline:
+			t.Fatalf("no %s subscription for %s %s", typ, coin, interval)
+		}
+	}
+}
+
+func TestLiveFeedServesFromWebSocketAndFallsBack(t *testing.T) {
+	server, client := newMockHyperliquidServer(t)
+	defer server.Close()
+	wsServer, feed := newMockFeedServer(t)
+	defer wsServer.Close()
+
+	client.wsURL = "ws" + strings.TrimPrefix(wsServer.URL, "http")
+	client.startLiveFeed()
+	defer client.stopLiveFeed()
+	provider := &Provider{client: client, timeout: defaultProviderTimeout}
+	ctx := context.Background()
+
+	conn := feed.nextConn(t)
+	feed.awaitSub(t, "allMids", "", "")
+	price, err := client.GetCurrentPrice(ctx, "BTC")
+	require.NoError(t, err)
+	assert.InDelta(t, 150.0, price, 1e-9, "REST serves prices until the feed delivers mids")
+
+	require.NoError(t, websocket.JSON.Send(conn, map[string]any{
+		"channel": "allMids",
+		"data":    map[string]any{"mids": map[string]string{"BTC": "155"}},
+	}))
+	require.Eventually(t, client.liveReady, 2*time.Second, 10*time.Millisecond, "feed should become ready after mids arrive")
+
+	price, err = client.GetCurrentPrice(ctx, "BTC")
+	require.NoError(t, err)
+	assert.InDelta(t, 155.0, price, 1e-9, "live mid should take precedence over REST")
+
+	snapshot, err := provider.Snapshot(ctx, "BTC")
+	require.NoError(t, err)
+	assert.Equal(t, "BTC", snapshot.Symbol, "snapshot shape is unchanged")
+	assert.InDelta(t, 155.0, snapshot.Price.Last, 1e-9, "snapshot should use the live mid")
+	require.NotNil(t, snapshot.Intraday)
+	feed.awaitSub(t, "candle", "BTC", intradayInterval)
+
+	klines, err := client.GetKlines(ctx, "BTC", intradayInterval, intradayLookback)
+	require.NoError(t, err)
+	last := klines[len(klines)-1]
+	require.NoError(t, websocket.JSON.Send(conn, map[string]any{
+		"channel": "candle",
+		"data": map[string]any{
+			"t": last.OpenTime, "T": last.CloseTime, "s": "BTC", "i": intradayInterval,
+			"o": "150", "c": "175", "h": "176", "l": "149", "v": "300",
+		},
+	}))
+	require.Eventually(t, func() bool {
+		k, err := client.GetKlines(ctx, "BTC", intradayInterval, intradayLookback)
+		return err == nil && len(k) == intradayLookback && k[len(k)-1].Close == 175
+	}, 2*time.Second, 10*time.Millisecond, "candle updates should replace the in-progress kline")
+
+	require.NoError(t, websocket.JSON.Send(conn, map[string]any{
+		"channel": "activeAssetCtx",
+		"data": map[string]any{"coin": "BTC", "ctx": map[string]any{
+			"funding": 0.0005, "openInterest": 200, "markPx": 156, "midPx": 155.5, "dayBaseVlm": 10,
+		}},
+	}))
+	require.Eventually(t, func() bool {
+		info, err := client.GetMarketInfo(ctx, "BTC")
+		return err == nil && info.FundingRate == 0.0005 && info.OpenInterest == 200
+	}, 2*time.Second, 10*time.Millisecond, "asset context updates should be served without REST refresh")
+
+	require.NoError(t, conn.Close())
+	require.Eventually(t, func() bool { return !client.liveReady() }, 2*time.Second, 10*time.Millisecond, "feed should notice the disconnect")
+	price, err = client.GetCurrentPrice(ctx, "BTC")
+	require.NoError(t, err)
+	assert.InDelta(t, 150.0, price, 1e-9, "REST should serve prices while disconnected")
+	klines, err = client.GetKlines(ctx, "BTC", intradayInterval, intradayLookback)
+	require.NoError(t, err)
+	assert.InDelta(t, 150.0, klines[len(klines)-1].Close, 1e-9, "klines should fall back to REST while disconnected")
+
+	feed.nextConn(t)
+	feed.awaitSub(t, "allMids", "", "")
+}
+
+func TestWSURLFor(t *testing.T) {
+	assert.Equal(t, "wss://api.hyperliquid.xyz/ws", wsURLFor(defaultBaseURL))
+	assert.Equal(t, "wss://api.hyperliquid-testnet.xyz/ws", wsURLFor(testnetBaseURL))
+	assert.Equal(t, "ws://127.0.0.1:8080/ws", wsURLFor("http://127.0.0.1:8080"))
+}
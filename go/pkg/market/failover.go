@@ -0,0 +1,151 @@
+package market
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultFailoverCooldown = 30 * time.Second
+
+// FailoverProvider wraps an ordered list of providers and falls through to the
+// next one when a call fails. A provider that errors is skipped until its
+// cooldown elapses, after which it is probed again on the next call.
+type FailoverProvider struct {
+	providers []Provider
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	failedAt []time.Time
+}
+
+// FailoverOption customises a FailoverProvider.
+type FailoverOption func(*FailoverProvider)
+
+// WithFailoverCooldown overrides how long a failed provider is skipped.
+func WithFailoverCooldown(d time.Duration) FailoverOption {
+	return func(f *FailoverProvider) {
+		if d > 0 {
+			f.cooldown = d
+		}
+	}
+}
+
+// NewFailoverProvider builds a provider that tries providers in order.
+func NewFailoverProvider(providers []Provider, opts ...FailoverOption) (*FailoverProvider, error) {
+	filtered := make([]Provider, 0, len(providers))
+	for _, p := range providers {
+		if p != nil {
+			filtered = append(filtered, p)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil, errors.New("market: failover provider requires at least one provider")
+	}
+	f := &FailoverProvider{
+		providers: filtered,
+		cooldown:  defaultFailoverCooldown,
+		now:       time.Now,
+		failedAt:  make([]time.Time, len(filtered)),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(f)
+		}
+	}
+	return f, nil
+}
+
+// Snapshot returns the first successful snapshot from the provider chain.
+func (f *FailoverProvider) Snapshot(ctx context.Context, symbol string) (*Snapshot, error) {
+	var out *Snapshot
+	err := f.try(ctx, func(p Provider) error {
+		snap, err := p.Snapshot(ctx, symbol)
+		if err != nil {
+			return err
+		}
+		out = snap
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("market: snapshot %s: %w", symbol, err)
+	}
+	return out, nil
+}
+
+// ListAssets returns the asset list from the first healthy provider.
+func (f *FailoverProvider) ListAssets(ctx context.Context) ([]Asset, error) {
+	var out []Asset
+	err := f.try(ctx, func(p Provider) error {
+		assets, err := p.ListAssets(ctx)
+		if err != nil {
+			return err
+		}
+		out = assets
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("market: list assets: %w", err)
+	}
+	return out, nil
+}
+
+// SetPersistence forwards persistence hooks to wrapped providers that accept them.
+func (f *FailoverProvider) SetPersistence(p Persistence) {
+	for _, provider := range f.providers {
+		if aware, ok := provider.(PersistenceAware); ok {
+			aware.SetPersistence(p)
+		}
+	}
+}
+
+// Healthy reports, per wrapped provider, whether it is outside its failure cooldown.
+func (f *FailoverProvider) Healthy() []bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := f.now()
+	out := make([]bool, len(f.failedAt))
+	for i, t := range f.failedAt {
+		out[i] = t.IsZero() || now.Sub(t) >= f.cooldown
+	}
+	return out
+}
+
+// try runs call against healthy providers in order. When every provider is
+// cooling down, all are tried anyway rather than failing without a probe.
+func (f *FailoverProvider) try(ctx context.Context, call func(Provider) error) error {
+	healthy := f.Healthy()
+	order := make([]int, 0, len(f.providers))
+	for i, ok := range healthy {
+		if ok {
+			order = append(order, i)
+		}
+	}
+	if len(order) == 0 {
+		for i := range f.providers {
+			order = append(order, i)
+		}
+	}
+	var errs []error
+	for _, i := range order {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		err := call(f.providers[i])
+		f.mu.Lock()
+		if err != nil {
+			f.failedAt[i] = f.now()
+		} else {
+			f.failedAt[i] = time.Time{}
+		}
+		f.mu.Unlock()
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("provider[%d]: %w", i, err))
+	}
+	return errors.Join(errs...)
+}
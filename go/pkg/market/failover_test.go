@@ -0,0 +1,69 @@
+package market
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubProvider struct {
+	price float64
+	err   error
+	calls int
+}
+
+func (s *stubProvider) Snapshot(_ context.Context, symbol string) (*Snapshot, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &Snapshot{Symbol: symbol, Price: PriceInfo{Last: s.price}}, nil
+}
+
+func (s *stubProvider) ListAssets(context.Context) ([]Asset, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []Asset{{Symbol: "BTC", IsActive: true}}, nil
+}
+
+func TestFailoverProviderUsesSecondary(t *testing.T) {
+	primary := &stubProvider{err: errors.New("primary down")}
+	secondary := &stubProvider{price: 101}
+	now := time.Unix(1_700_000_000, 0)
+	f, err := NewFailoverProvider([]Provider{primary, secondary}, WithFailoverCooldown(time.Minute))
+	require.NoError(t, err)
+	f.now = func() time.Time { return now }
+
+	snap, err := f.Snapshot(context.Background(), "BTC")
+	require.NoError(t, err)
+	assert.Equal(t, 101.0, snap.Price.Last, "secondary data should be returned")
+	assert.Equal(t, []bool{false, true}, f.Healthy(), "primary should be marked unhealthy")
+
+	_, err = f.Snapshot(context.Background(), "BTC")
+	require.NoError(t, err)
+	assert.Equal(t, 1, primary.calls, "failed primary should be skipped during cooldown")
+
+	primary.err = nil
+	primary.price = 100
+	now = now.Add(time.Minute)
+	snap, err = f.Snapshot(context.Background(), "BTC")
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, snap.Price.Last, "primary should be re-probed after cooldown")
+	assert.Equal(t, []bool{true, true}, f.Healthy())
+}
+
+func TestFailoverProviderAllFail(t *testing.T) {
+	f, err := NewFailoverProvider([]Provider{&stubProvider{err: errors.New("a")}, &stubProvider{err: errors.New("b")}})
+	require.NoError(t, err)
+	_, err = f.ListAssets(context.Background())
+	assert.Error(t, err, "errors should surface when every provider fails")
+
+	_, err = NewFailoverProvider(nil)
+	assert.Error(t, err, "empty provider list should be rejected")
+}
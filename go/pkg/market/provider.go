@@ -1,6 +1,9 @@
 package market
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Provider exposes exchange-agnostic market data.
 type Provider interface {
@@ -10,6 +13,18 @@ type Provider interface {
 	ListAssets(ctx context.Context) ([]Asset, error)
 }
 
+// FundingHistoryProvider is an optional Provider capability returning settled
+// funding rates for a symbol within [start, end].
+type FundingHistoryProvider interface {
+	GetFundingHistory(ctx context.Context, symbol string, start, end time.Time) ([]FundingRecord, error)
+}
+
+// DepthProvider is an optional Provider capability returning the current
+// order book for a symbol truncated to the given number of levels per side.
+type DepthProvider interface {
+	GetDepth(ctx context.Context, symbol string, levels int) (*OrderBook, error)
+}
+
 // PersistenceAware indicates the provider can accept persistence hooks.
 type PersistenceAware interface {
 	SetPersistence(p Persistence)
@@ -23,6 +38,8 @@ type Snapshot struct {
 	Indicators   IndicatorInfo     // Calculated technical indicators
 	OpenInterest *OpenInterestInfo // Derivatives interest data, if available
 	Funding      *FundingInfo      // Perpetual funding information, if available
+	Depth        *DepthInfo        // Top-of-book depth, if available
+	Impact       *ImpactInfo       // Venue impact prices, if available
 	Intraday     *SeriesBundle     // Short-term time series context
 	LongTerm     *SeriesBundle     // Longer-term time series context
 }
@@ -39,7 +56,9 @@ type Asset struct {
 
 // PriceInfo holds last trade data.
 type PriceInfo struct {
-	Last float64
+	Last   float64
+	Mark   float64 // venue mark price (0 when not reported)
+	Oracle float64 // venue oracle price (0 when not reported)
 }
 
 // ChangeInfo describes percentage changes over standard windows.
@@ -66,6 +85,76 @@ type FundingInfo struct {
 	Rate float64 // fractional funding rate (0.01 == 1%)
 }
 
+// DepthInfo summarises the visible order book near the touch.
+type DepthInfo struct {
+	BestBid    float64
+	BestAsk    float64
+	BidSizeUSD float64 // notional resting on the bid side across Levels
+	AskSizeUSD float64 // notional resting on the ask side across Levels
+	Levels     int     // number of levels per side included in the sums
+}
+
+// ImpactInfo reports the average prices the venue expects a market order of
+// NotionalUSD to fill at on each side of the book.
+type ImpactInfo struct {
+	BidPx       float64 // average fill price for a sell of NotionalUSD
+	AskPx       float64 // average fill price for a buy of NotionalUSD
+	NotionalUSD float64 // order size the impact prices are quoted for
+}
+
+// OrderBook is an L2 order book snapshot. Bids are sorted best (highest)
+// first and asks best (lowest) first.
+type OrderBook struct {
+	Symbol string
+	Bids   []BookLevel
+	Asks   []BookLevel
+	Time   time.Time
+}
+
+// BookLevel is one aggregated price level.
+type BookLevel struct {
+	Price  float64
+	Size   float64
+	Orders int
+}
+
+// Depth summarises the book across at most levels per side; levels <= 0 uses
+// every level present. It returns nil when either side is empty.
+func (b *OrderBook) Depth(levels int) *DepthInfo {
+	if b == nil || len(b.Bids) == 0 || len(b.Asks) == 0 {
+		return nil
+	}
+	bids, asks := b.Bids, b.Asks
+	if levels > 0 {
+		if len(bids) > levels {
+			bids = bids[:levels]
+		}
+		if len(asks) > levels {
+			asks = asks[:levels]
+		}
+	}
+	info := &DepthInfo{
+		BestBid: bids[0].Price,
+		BestAsk: asks[0].Price,
+		Levels:  max(len(bids), len(asks)),
+	}
+	for _, l := range bids {
+		info.BidSizeUSD += l.Price * l.Size
+	}
+	for _, l := range asks {
+		info.AskSizeUSD += l.Price * l.Size
+	}
+	return info
+}
+
+// FundingRecord is one settled funding interval.
+type FundingRecord struct {
+	Symbol  string
+	Rate    float64 // fractional funding rate paid by longs (0.0001 == 0.01%)
+	Premium float64
+	Time    time.Time
+}
+
 // SeriesBundle provides supporting time series data for analysis layers.
 type SeriesBundle struct {
 	Prices []float64            // Ordered oldest → newest close prices
@@ -0,0 +1,69 @@
+package market
+
+import (
+	"fmt"
+	"math"
+)
+
+// SanityLimits bounds the values a Snapshot may report before it is treated
+// as corrupt. Zero fields disable the corresponding check.
+type SanityLimits struct {
+	MaxAbsOneHourChange  float64 // fractional (1.0 == 100%)
+	MaxAbsFourHourChange float64 // fractional (1.0 == 100%)
+	MaxAbsFundingRate    float64 // fractional per funding interval
+	MaxSeriesDeviation   float64 // max fractional gap between Price.Last and the latest intraday close
+	MaxOracleDeviation   float64 // max fractional gap between Price.Mark and Price.Oracle
+}
+
+// DefaultSanityLimits returns conservative bounds that only reject snapshots
+// no real perpetual market would produce.
+func DefaultSanityLimits() SanityLimits {
+	return SanityLimits{
+		MaxAbsOneHourChange:  5.0,  // 500% in an hour
+		MaxAbsFourHourChange: 10.0, // 1000% in four hours
+		MaxAbsFundingRate:    0.05, // 5% per interval
+		MaxSeriesDeviation:   0.5,  // last price 50% away from the latest close
+		MaxOracleDeviation:   0.25, // mark price 25% away from the oracle
+	}
+}
+
+// CheckSnapshot reports the first consistency violation found in s, or nil
+// when the snapshot looks plausible.
+func CheckSnapshot(s *Snapshot, limits SanityLimits) error {
+	if s == nil {
+		return fmt.Errorf("market: nil snapshot")
+	}
+	if !isFinite(s.Price.Last) || s.Price.Last <= 0 {
+		return fmt.Errorf("market: %s invalid price %v", s.Symbol, s.Price.Last)
+	}
+	if !isFinite(s.Change.OneHour) || (limits.MaxAbsOneHourChange > 0 && math.Abs(s.Change.OneHour) > limits.MaxAbsOneHourChange) {
+		return fmt.Errorf("market: %s implausible 1h change %.2f%%", s.Symbol, s.Change.OneHour*100)
+	}
+	if !isFinite(s.Change.FourHour) || (limits.MaxAbsFourHourChange > 0 && math.Abs(s.Change.FourHour) > limits.MaxAbsFourHourChange) {
+		return fmt.Errorf("market: %s implausible 4h change %.2f%%", s.Symbol, s.Change.FourHour*100)
+	}
+	if s.Funding != nil {
+		if !isFinite(s.Funding.Rate) || (limits.MaxAbsFundingRate > 0 && math.Abs(s.Funding.Rate) > limits.MaxAbsFundingRate) {
+			return fmt.Errorf("market: %s implausible funding rate %v", s.Symbol, s.Funding.Rate)
+		}
+	}
+	if s.OpenInterest != nil && (!isFinite(s.OpenInterest.Latest) || s.OpenInterest.Latest < 0) {
+		return fmt.Errorf("market: %s invalid open interest %v", s.Symbol, s.OpenInterest.Latest)
+	}
+	if limits.MaxSeriesDeviation > 0 && s.Intraday != nil && len(s.Intraday.Prices) > 0 {
+		lastClose := s.Intraday.Prices[len(s.Intraday.Prices)-1]
+		if lastClose > 0 && math.Abs(s.Price.Last-lastClose)/lastClose > limits.MaxSeriesDeviation {
+			return fmt.Errorf("market: %s price %v inconsistent with latest close %v", s.Symbol, s.Price.Last, lastClose)
+		}
+	}
+	if limits.MaxOracleDeviation > 0 && s.Price.Mark > 0 && s.Price.Oracle > 0 {
+		if math.Abs(s.Price.Mark-s.Price.Oracle)/s.Price.Oracle > limits.MaxOracleDeviation {
+			return fmt.Errorf("market: %s mark price %v inconsistent with oracle %v", s.Symbol, s.Price.Mark, s.Price.Oracle)
+		}
+	}
+	return nil
+}
+
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
@@ -0,0 +1,41 @@
+package market_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	market "nof0-api/pkg/market"
+)
+
+func TestCheckSnapshot(t *testing.T) {
+	limits := market.DefaultSanityLimits()
+	ok := &market.Snapshot{
+		Symbol:   "BTC",
+		Price:    market.PriceInfo{Last: 100, Mark: 100.1, Oracle: 100},
+		Change:   market.ChangeInfo{OneHour: 0.02, FourHour: -0.05},
+		Funding:  &market.FundingInfo{Rate: 0.0001},
+		Intraday: &market.SeriesBundle{Prices: []float64{98, 99, 101}},
+	}
+	assert.NoError(t, market.CheckSnapshot(ok, limits), "plausible snapshot should pass")
+
+	cases := map[string]func(s *market.Snapshot){
+		"zero price":        func(s *market.Snapshot) { s.Price.Last = 0 },
+		"nan change":        func(s *market.Snapshot) { s.Change.OneHour = math.NaN() },
+		"5000% hourly":      func(s *market.Snapshot) { s.Change.OneHour = 50 },
+		"extreme funding":   func(s *market.Snapshot) { s.Funding = &market.FundingInfo{Rate: 1} },
+		"series divergence": func(s *market.Snapshot) { s.Intraday = &market.SeriesBundle{Prices: []float64{10}} },
+		"mark off oracle":   func(s *market.Snapshot) { s.Price.Mark = 160 },
+	}
+	for name, mutate := range cases {
+		s := *ok
+		mutate(&s)
+		assert.Error(t, market.CheckSnapshot(&s, limits), "%s should be rejected", name)
+	}
+	assert.Error(t, market.CheckSnapshot(nil, limits), "nil snapshot should be rejected")
+
+	noOracle := *ok
+	noOracle.Price = market.PriceInfo{Last: 100, Mark: 160}
+	noOracle.Intraday = nil
+	assert.NoError(t, market.CheckSnapshot(&noOracle, limits), "mark without an oracle price should not be compared")
+}